@@ -3,8 +3,11 @@ package config
 import "time"
 
 type Config struct {
-	Port     string         `mapstructure:"PORT"`
-	Postgres PostgresConfig `mapstructure:"POSTGRES"`
+	Port       string           `mapstructure:"PORT"`
+	Postgres   PostgresConfig   `mapstructure:"POSTGRES"`
+	Tenant     TenantConfig     `mapstructure:"TENANT"`
+	DraftOrder DraftOrderConfig `mapstructure:"DRAFT_ORDER"`
+	Storage    StorageConfig    `mapstructure:"STORAGE"`
 }
 
 type PostgresConfig struct {
@@ -13,3 +16,30 @@ type PostgresConfig struct {
 	MaxIdleConnTime time.Duration `mapstructure:"MAX_IDLE_CONN_TIME"`
 	PingTimeout     time.Duration `mapstructure:"PING_TIMEOUT"`
 }
+
+type TenantConfig struct {
+	BaseDomain string `mapstructure:"BASE_DOMAIN"` // shops are served on <slug>.<base domain>
+	// AllowSubdomainOnly lets a request without an API token reach a shop by its subdomain alone. Anyone can type a
+	// subdomain, so it is off by default and, when on, only opens reads: changes still need the bearer token.
+	AllowSubdomainOnly bool `mapstructure:"ALLOW_SUBDOMAIN_ONLY"`
+}
+
+type DraftOrderConfig struct {
+	ReserveStock bool `mapstructure:"RESERVE_STOCK"` // parked orders and open tabs hold their items back from other sales
+}
+
+// StorageConfig picks where uploaded files such as product images are kept
+type StorageConfig struct {
+	Driver    string        `mapstructure:"DRIVER"`     // local or s3
+	LocalDir  string        `mapstructure:"LOCAL_DIR"`  // the local driver's directory, served under the public URL's path
+	PublicURL string        `mapstructure:"PUBLIC_URL"` // where the files are served from, the bucket's own URL for s3 when empty
+	S3        S3StoreConfig `mapstructure:"S3"`
+}
+
+type S3StoreConfig struct {
+	Endpoint        string `mapstructure:"ENDPOINT"`
+	Region          string `mapstructure:"REGION"`
+	Bucket          string `mapstructure:"BUCKET"`
+	AccessKeyID     string `mapstructure:"ACCESS_KEY_ID"`
+	SecretAccessKey string `mapstructure:"SECRET_ACCESS_KEY"`
+}
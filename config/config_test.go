@@ -16,6 +16,22 @@ func TestConfig(t *testing.T) {
 			MaxIdleConnTime: 5 * time.Minute,
 			PingTimeout:     3 * time.Second,
 		},
+		Tenant: TenantConfig{
+			BaseDomain:         "kasir.example.com",
+			AllowSubdomainOnly: true,
+		},
+		DraftOrder: DraftOrderConfig{
+			ReserveStock: true,
+		},
+		Storage: StorageConfig{
+			Driver:    "s3",
+			PublicURL: "https://cdn.example.com",
+			S3: S3StoreConfig{
+				Endpoint: "https://s3.ap-southeast-1.amazonaws.com",
+				Region:   "ap-southeast-1",
+				Bucket:   "kasir-media",
+			},
+		},
 	}
 
 	assert.Equal(t, "8080", cfg.Port)
@@ -23,6 +39,11 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, int32(10), cfg.Postgres.MaxConns)
 	assert.Equal(t, 5*time.Minute, cfg.Postgres.MaxIdleConnTime)
 	assert.Equal(t, 3*time.Second, cfg.Postgres.PingTimeout)
+	assert.Equal(t, "kasir.example.com", cfg.Tenant.BaseDomain)
+	assert.True(t, cfg.Tenant.AllowSubdomainOnly)
+	assert.True(t, cfg.DraftOrder.ReserveStock)
+	assert.Equal(t, "s3", cfg.Storage.Driver)
+	assert.Equal(t, "kasir-media", cfg.Storage.S3.Bucket)
 }
 
 func TestPostgresConfig(t *testing.T) {
@@ -0,0 +1,7 @@
+-- Apply after schema_sync.sql.
+-- The SKU is the store's own product code, imports match on it before falling back to the product name.
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS sku TEXT;
+---
+-- a SKU names one live product per shop, a deleted product frees its SKU
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_sku ON core.product (tenant_id, lower(sku))
+WHERE sku IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,31 @@
+-- Apply after schema_category.sql.
+-- Categories nest under a parent, NULL for a top level category.
+ALTER TABLE core.category ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES core.category(id);
+---
+ALTER TABLE core.category ADD CONSTRAINT category_not_own_parent CHECK (parent_id <> id);
+---
+CREATE INDEX idx_category_parent ON core.category (parent_id)
+WHERE deleted_at IS NULL;
+---
+-- the service rejects a move under one of the category's own subcategories,
+-- this keeps writes that do not go through it from closing a loop
+CREATE OR REPLACE FUNCTION core.fn_category_no_cycle()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.parent_id IS NOT NULL AND EXISTS (
+        WITH RECURSIVE ancestor AS (
+            SELECT id, parent_id FROM core.category WHERE id = NEW.parent_id
+            UNION
+            SELECT c.id, c.parent_id FROM core.category c JOIN ancestor a ON c.id = a.parent_id
+        )
+        SELECT 1 FROM ancestor WHERE id = NEW.id
+    ) THEN
+        RAISE EXCEPTION 'category % cannot be moved under its own subcategory', NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_category_no_cycle
+BEFORE INSERT OR UPDATE OF parent_id ON core.category
+FOR EACH ROW EXECUTE FUNCTION core.fn_category_no_cycle();
@@ -0,0 +1,39 @@
+-- Apply after schema_tenant.sql, the customer table is created tenant-scoped from the start.
+CREATE TABLE IF NOT EXISTS core.customer (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+
+    name TEXT NOT NULL,
+    phone TEXT, -- digits only with an optional leading +, see model.NormalizePhone
+    email TEXT,
+    notes TEXT,
+
+    CONSTRAINT customer_name_not_empty CHECK (char_length(trim(name)) > 0)
+);
+---
+-- the till attaches a customer by phone number, so it has to name one customer per shop
+CREATE UNIQUE INDEX idx_customer_active_phone ON core.customer (tenant_id, phone)
+WHERE deleted_at IS NULL AND phone IS NOT NULL;
+---
+CREATE INDEX idx_customer_tenant ON core.customer (tenant_id);
+---
+CREATE TRIGGER trg_customer_version_increment
+BEFORE UPDATE ON core.customer
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+ALTER TABLE core.customer ENABLE ROW LEVEL SECURITY;
+ALTER TABLE core.customer FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON core.customer
+USING (tenant_id = core.fn_current_tenant_id())
+WITH CHECK (tenant_id = core.fn_current_tenant_id());
+---
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS customer_id UUID REFERENCES core.customer(id) ON DELETE SET NULL;
+---
+CREATE INDEX idx_transaction_customer_created ON core.transaction (customer_id, created_at)
+WHERE customer_id IS NOT NULL;
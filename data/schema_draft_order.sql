@@ -0,0 +1,66 @@
+-- Apply after schema_shift.sql, the draft order tables are created tenant-scoped from the start.
+-- A draft order is a parked order or an open table tab, it becomes a transaction when it is paid.
+CREATE TABLE IF NOT EXISTS core.draft_order (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    status TEXT NOT NULL DEFAULT 'open',
+    label TEXT, -- what the cashier calls the order, "Budi" or "takeaway 3"
+    table_number TEXT,
+    outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT, -- NULL for a shop without outlets
+    customer_id UUID REFERENCES core.customer(id) ON DELETE RESTRICT,
+    notes TEXT,
+    reserves_stock BOOLEAN NOT NULL DEFAULT FALSE, -- the items are held back from other sales while the draft is open or held
+    transaction_id UUID REFERENCES core.transaction(id) ON DELETE RESTRICT, -- the sale the draft was converted into
+
+    CONSTRAINT draft_order_status_valid CHECK (status IN ('open', 'held', 'converted', 'cancelled')),
+    CONSTRAINT draft_order_held_named CHECK (status <> 'held' OR label IS NOT NULL OR table_number IS NOT NULL),
+    CONSTRAINT draft_order_converted_complete CHECK ((status = 'converted') = (transaction_id IS NOT NULL))
+);
+---
+CREATE TRIGGER trg_draft_order_version_increment
+BEFORE UPDATE ON core.draft_order
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE INDEX idx_draft_order_active ON core.draft_order (status, created_at DESC)
+WHERE status IN ('open', 'held');
+---
+-- one table runs one tab
+CREATE UNIQUE INDEX idx_draft_order_table ON core.draft_order (tenant_id, outlet_id, table_number)
+NULLS NOT DISTINCT
+WHERE status IN ('open', 'held') AND table_number IS NOT NULL;
+---
+-- the items are priced when the draft is converted, a tab pays today's price
+CREATE TABLE IF NOT EXISTS core.draft_order_item (
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    draft_order_id UUID NOT NULL REFERENCES core.draft_order(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- when the product was first added
+    created_by TEXT NOT NULL,
+
+    PRIMARY KEY (draft_order_id, product_id),
+    CONSTRAINT draft_order_item_quantity_positive CHECK (quantity > 0)
+);
+---
+CREATE INDEX idx_draft_order_item_product ON core.draft_order_item (product_id);
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['draft_order', 'draft_order_item'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
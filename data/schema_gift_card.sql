@@ -0,0 +1,106 @@
+-- Apply after schema_customer.sql, the gift card tables are created tenant-scoped from the start.
+-- A gift card is prepaid value a customer tenders at checkout. Store credit is a gift card issued to a customer.
+CREATE TABLE IF NOT EXISTS core.gift_card (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    code TEXT NOT NULL, -- normalized, upper case without separators
+    customer_id UUID REFERENCES core.customer(id) ON DELETE RESTRICT, -- the holder of store credit
+    initial_amount BIGINT NOT NULL, -- IDR loaded when the card was issued
+    balance BIGINT NOT NULL DEFAULT 0, -- cached balance of the ledger, like core.product.stock
+    expires_at TIMESTAMPTZ, -- NULL never expires
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT gift_card_code_unique UNIQUE (tenant_id, code),
+    CONSTRAINT gift_card_initial_amount_positive CHECK (initial_amount > 0),
+    CONSTRAINT gift_card_balance_not_negative CHECK (balance >= 0)
+);
+---
+CREATE TRIGGER trg_gift_card_version_increment
+BEFORE UPDATE ON core.gift_card
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE TABLE IF NOT EXISTS core.gift_card_ledger (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    gift_card_id UUID NOT NULL REFERENCES core.gift_card(id) ON DELETE RESTRICT,
+    entry_type TEXT NOT NULL,
+    amount BIGINT NOT NULL, -- signed IDR, negative takes value off the card
+    balance_after BIGINT NOT NULL DEFAULT 0, -- filled by trg_gift_card_ledger_apply
+    transaction_id UUID REFERENCES core.transaction(id) ON DELETE RESTRICT,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL, -- the actor
+
+    CONSTRAINT gift_card_entry_type_valid CHECK (entry_type IN ('issue', 'top_up', 'redeem')),
+    CONSTRAINT gift_card_amount_not_zero CHECK (amount <> 0)
+);
+---
+CREATE INDEX idx_gift_card_ledger_card_created ON core.gift_card_ledger (gift_card_id, created_at);
+---
+CREATE INDEX idx_gift_card_ledger_transaction ON core.gift_card_ledger (transaction_id)
+WHERE transaction_id IS NOT NULL;
+---
+-- The UPDATE locks the card row, so concurrent redemptions of one card run one after the other and
+-- gift_card_balance_not_negative rejects the one spending value already spent.
+CREATE OR REPLACE FUNCTION core.fn_apply_gift_card_entry()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_is_active BOOLEAN;
+    v_expires_at TIMESTAMPTZ;
+BEGIN
+    UPDATE core.gift_card
+    SET balance = balance + NEW.amount, updated_by = NEW.created_by, updated_at = CURRENT_TIMESTAMP
+    WHERE id = NEW.gift_card_id
+    RETURNING balance, is_active, expires_at INTO NEW.balance_after, v_is_active, v_expires_at;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'gift card % not found', NEW.gift_card_id;
+    END IF;
+    IF NEW.entry_type = 'redeem' AND (NOT v_is_active OR v_expires_at <= CURRENT_TIMESTAMP) THEN
+        RAISE EXCEPTION 'gift card % is inactive or expired', NEW.gift_card_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_gift_card_ledger_apply
+BEFORE INSERT ON core.gift_card_ledger
+FOR EACH ROW EXECUTE FUNCTION core.fn_apply_gift_card_entry();
+---
+-- the ledger is append-only
+CREATE OR REPLACE FUNCTION core.fn_prevent_gift_card_ledger_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'gift card ledger entries are append-only';
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_gift_card_ledger_append_only
+BEFORE UPDATE OR DELETE ON core.gift_card_ledger
+FOR EACH ROW EXECUTE FUNCTION core.fn_prevent_gift_card_ledger_change();
+---
+-- the card is a tender, total_price_amount stays the sale's value and gift_card_amount is the part the card paid
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS gift_card_id UUID REFERENCES core.gift_card(id) ON DELETE RESTRICT;
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS gift_card_amount BIGINT NOT NULL DEFAULT 0;
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['gift_card', 'gift_card_ledger'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
@@ -0,0 +1,142 @@
+CREATE TABLE IF NOT EXISTS core.product_lot (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    lot_number TEXT NOT NULL,
+    expiry_date DATE NOT NULL,
+    quantity_received INT NOT NULL,
+    quantity_remaining INT NOT NULL DEFAULT 0, -- kept by trg_stock_movement_lot
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+
+    CONSTRAINT lot_number_unique UNIQUE (product_id, lot_number),
+    CONSTRAINT quantity_received_positive CHECK (quantity_received > 0),
+    CONSTRAINT quantity_remaining_valid CHECK (quantity_remaining >= 0 AND quantity_remaining <= quantity_received)
+);
+---
+-- FEFO reads the open lots of a product earliest expiry first
+CREATE INDEX idx_product_lot_fefo ON core.product_lot (product_id, expiry_date)
+WHERE quantity_remaining > 0;
+---
+CREATE INDEX idx_product_lot_expiry ON core.product_lot (expiry_date)
+WHERE quantity_remaining > 0;
+---
+CREATE TABLE IF NOT EXISTS core.stock_movement (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    movement_type TEXT NOT NULL,
+    quantity INT NOT NULL, -- signed, negative takes stock out
+    balance_after INT NOT NULL DEFAULT 0, -- filled by trg_stock_movement_apply
+    reason TEXT,
+    reference_id UUID, -- e.g. the transaction that caused a sale movement
+    lot_id UUID REFERENCES core.product_lot(id) ON DELETE RESTRICT, -- the lot the units came from or went into
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL, -- the actor
+
+    CONSTRAINT movement_type_valid CHECK (movement_type IN ('sale', 'refund', 'purchase_receipt', 'adjustment', 'transfer', 'waste')),
+    CONSTRAINT quantity_not_zero CHECK (quantity <> 0)
+);
+---
+CREATE INDEX idx_stock_movement_product_created ON core.stock_movement (product_id, created_at DESC);
+---
+CREATE INDEX idx_stock_movement_reference ON core.stock_movement (reference_id)
+WHERE reference_id IS NOT NULL;
+---
+-- core.product.stock is a cached balance of the ledger, every change goes through a movement
+CREATE OR REPLACE FUNCTION core.fn_apply_stock_movement()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE core.product
+    SET stock = stock + NEW.quantity, updated_by = NEW.created_by
+    WHERE id = NEW.product_id AND deleted_at IS NULL
+    RETURNING stock INTO NEW.balance_after;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'product % not found or deleted', NEW.product_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_stock_movement_apply
+BEFORE INSERT ON core.stock_movement
+FOR EACH ROW EXECUTE FUNCTION core.fn_apply_stock_movement();
+---
+-- a movement against a lot moves its remaining quantity too, quantity_remaining_valid rejects overselling a lot
+CREATE OR REPLACE FUNCTION core.fn_apply_stock_movement_lot()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE core.product_lot
+    SET quantity_remaining = quantity_remaining + NEW.quantity
+    WHERE id = NEW.lot_id AND product_id = NEW.product_id;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'lot % not found for product %', NEW.lot_id, NEW.product_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_stock_movement_lot
+BEFORE INSERT ON core.stock_movement
+FOR EACH ROW WHEN (NEW.lot_id IS NOT NULL) EXECUTE FUNCTION core.fn_apply_stock_movement_lot();
+---
+-- the ledger is append-only, corrections are new movements
+CREATE OR REPLACE FUNCTION core.fn_prevent_stock_movement_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock movements are append-only';
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_stock_movement_append_only
+BEFORE UPDATE OR DELETE ON core.stock_movement
+FOR EACH ROW EXECUTE FUNCTION core.fn_prevent_stock_movement_change();
+---
+CREATE OR REPLACE VIEW core.v_stock_reconciliation AS
+SELECT
+    p.id AS product_id,
+    p.stock,
+    COALESCE(SUM(m.quantity), 0)::INT AS ledger_balance,
+    p.stock - COALESCE(SUM(m.quantity), 0)::INT AS difference
+FROM core.product p
+LEFT JOIN core.stock_movement m ON m.product_id = p.id
+GROUP BY p.id, p.stock;
+---
+CREATE TABLE IF NOT EXISTS core.stock_count (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    status TEXT NOT NULL DEFAULT 'open',
+    category_id UUID REFERENCES core.category(id) ON DELETE SET NULL, -- NULL counts the whole store
+    notes TEXT,
+    submitted_at TIMESTAMPTZ,
+    approved_at TIMESTAMPTZ,
+    approved_by TEXT,
+
+    CONSTRAINT stock_count_status_valid CHECK (status IN ('open', 'submitted', 'posted', 'cancelled'))
+);
+---
+CREATE INDEX idx_stock_count_status ON core.stock_count (status, created_at DESC);
+---
+CREATE TRIGGER trg_stock_count_version_increment
+BEFORE UPDATE ON core.stock_count
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE TABLE IF NOT EXISTS core.stock_count_line (
+    stock_count_id UUID NOT NULL REFERENCES core.stock_count(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    counted_quantity INT, -- NULL until counted
+    counted_at TIMESTAMPTZ,
+    counted_by TEXT,
+    system_stock INT, -- frozen from core.product.stock when the count is posted
+
+    PRIMARY KEY (stock_count_id, product_id),
+    CONSTRAINT counted_not_negative CHECK (counted_quantity IS NULL OR counted_quantity >= 0)
+);
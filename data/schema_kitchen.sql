@@ -0,0 +1,127 @@
+-- Apply after schema_draft_order.sql, the restaurant tables are created tenant-scoped from the start.
+-- Dining tables are where the draft orders of an F&B outlet are seated, grouped into areas.
+CREATE TABLE IF NOT EXISTS core.dining_table (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT, -- NULL for a shop without outlets
+    area TEXT NOT NULL DEFAULT '', -- "Indoor", "Terrace"
+    name TEXT NOT NULL, -- what the waiter calls the table, "7" or "T3"
+    seats INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT dining_table_name_not_empty CHECK (char_length(trim(name)) > 0),
+    CONSTRAINT dining_table_seats_not_negative CHECK (seats >= 0)
+);
+---
+CREATE TRIGGER trg_dining_table_version_increment
+BEFORE UPDATE ON core.dining_table
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE UNIQUE INDEX idx_dining_table_name ON core.dining_table (tenant_id, outlet_id, lower(name))
+NULLS NOT DISTINCT;
+---
+-- a draft order seated at a table takes the table's name as its table number
+ALTER TABLE core.draft_order ADD COLUMN IF NOT EXISTS table_id UUID REFERENCES core.dining_table(id) ON DELETE RESTRICT;
+---
+-- one party per table
+CREATE UNIQUE INDEX idx_draft_order_dining_table ON core.draft_order (table_id)
+WHERE status IN ('open', 'held') AND table_id IS NOT NULL;
+---
+-- a kitchen station prepares the products of its categories, a category is prepared at one station
+CREATE TABLE IF NOT EXISTS core.kitchen_station (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    name TEXT NOT NULL, -- "Kitchen", "Bar"
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT kitchen_station_name_not_empty CHECK (char_length(trim(name)) > 0)
+);
+---
+CREATE TRIGGER trg_kitchen_station_version_increment
+BEFORE UPDATE ON core.kitchen_station
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE UNIQUE INDEX idx_kitchen_station_name ON core.kitchen_station (tenant_id, lower(name));
+---
+CREATE TABLE IF NOT EXISTS core.kitchen_station_category (
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    category_id UUID NOT NULL REFERENCES core.category(id) ON DELETE CASCADE,
+    station_id UUID NOT NULL REFERENCES core.kitchen_station(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (tenant_id, category_id)
+);
+---
+CREATE INDEX idx_kitchen_station_category_station ON core.kitchen_station_category (station_id);
+---
+-- the units of a draft order line already sent to the kitchen, firing the order again sends the rest
+ALTER TABLE core.draft_order_item ADD COLUMN IF NOT EXISTS fired_quantity INT NOT NULL DEFAULT 0;
+ALTER TABLE core.draft_order_item ADD CONSTRAINT draft_order_item_fired_not_negative CHECK (fired_quantity >= 0);
+---
+-- a kitchen order ticket is what one station has to prepare for one firing of a draft order
+CREATE TABLE IF NOT EXISTS core.kitchen_ticket (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- queued
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    draft_order_id UUID NOT NULL REFERENCES core.draft_order(id) ON DELETE RESTRICT,
+    station_id UUID NOT NULL REFERENCES core.kitchen_station(id) ON DELETE RESTRICT,
+    label TEXT, -- the draft order's label and table number when the ticket was fired
+    table_number TEXT,
+    status TEXT NOT NULL DEFAULT 'queued',
+    preparing_at TIMESTAMPTZ,
+    ready_at TIMESTAMPTZ,
+    served_at TIMESTAMPTZ,
+
+    CONSTRAINT kitchen_ticket_status_valid CHECK (status IN ('queued', 'preparing', 'ready', 'served'))
+);
+---
+CREATE TRIGGER trg_kitchen_ticket_version_increment
+BEFORE UPDATE ON core.kitchen_ticket
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE INDEX idx_kitchen_ticket_station_active ON core.kitchen_ticket (station_id, created_at)
+WHERE status <> 'served';
+---
+CREATE INDEX idx_kitchen_ticket_draft_order ON core.kitchen_ticket (draft_order_id);
+---
+CREATE TABLE IF NOT EXISTS core.kitchen_ticket_item (
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    ticket_id UUID NOT NULL REFERENCES core.kitchen_ticket(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    product_name TEXT NOT NULL, -- snapshotted when fired
+    quantity INT NOT NULL,
+
+    PRIMARY KEY (ticket_id, product_id),
+    CONSTRAINT kitchen_ticket_item_quantity_positive CHECK (quantity > 0)
+);
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['dining_table', 'kitchen_station', 'kitchen_station_category', 'kitchen_ticket', 'kitchen_ticket_item'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
@@ -0,0 +1,155 @@
+-- Apply after schema_customer.sql, the loyalty tables are created tenant-scoped from the start.
+-- One earning program per shop, a shop without a row runs no program.
+CREATE TABLE IF NOT EXISTS core.loyalty_program (
+    tenant_id UUID PRIMARY KEY DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    spend_per_point BIGINT NOT NULL, -- IDR spent to earn one point
+    point_value BIGINT NOT NULL, -- IDR one redeemed point takes off a sale
+    expiry_days INT, -- NULL keeps points forever
+
+    CONSTRAINT loyalty_spend_per_point_positive CHECK (spend_per_point > 0),
+    CONSTRAINT loyalty_point_value_positive CHECK (point_value > 0),
+    CONSTRAINT loyalty_expiry_days_positive CHECK (expiry_days IS NULL OR expiry_days > 0)
+);
+---
+CREATE TRIGGER trg_loyalty_program_version_increment
+BEFORE UPDATE ON core.loyalty_program
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+-- points earned on a category's lines are multiplied, categories without a row earn at 1x
+CREATE TABLE IF NOT EXISTS core.loyalty_category_multiplier (
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    category_id UUID NOT NULL REFERENCES core.category(id) ON DELETE CASCADE,
+    multiplier NUMERIC(6, 2) NOT NULL,
+
+    PRIMARY KEY (tenant_id, category_id),
+    CONSTRAINT loyalty_multiplier_not_negative CHECK (multiplier >= 0)
+);
+---
+-- core.customer.points_balance is a cached balance of the ledger, like core.product.stock
+ALTER TABLE core.customer ADD COLUMN IF NOT EXISTS points_balance INT NOT NULL DEFAULT 0;
+ALTER TABLE core.customer ADD CONSTRAINT customer_points_not_negative CHECK (points_balance >= 0);
+---
+CREATE TABLE IF NOT EXISTS core.loyalty_ledger (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    customer_id UUID NOT NULL REFERENCES core.customer(id) ON DELETE RESTRICT,
+    entry_type TEXT NOT NULL,
+    points INT NOT NULL, -- signed, negative takes points out
+    balance_after INT NOT NULL DEFAULT 0, -- filled by trg_loyalty_ledger_apply
+    transaction_id UUID REFERENCES core.transaction(id) ON DELETE RESTRICT,
+    reference_entry_id UUID REFERENCES core.loyalty_ledger(id) ON DELETE RESTRICT, -- the entry expired or reversed
+    expires_at TIMESTAMPTZ, -- when the points of a positive entry lapse
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL, -- the actor
+
+    CONSTRAINT loyalty_entry_type_valid CHECK (entry_type IN ('earn', 'redeem', 'expire', 'reversal')),
+    CONSTRAINT loyalty_points_not_zero CHECK (points <> 0)
+);
+---
+CREATE INDEX idx_loyalty_ledger_customer_created ON core.loyalty_ledger (customer_id, created_at);
+---
+CREATE INDEX idx_loyalty_ledger_transaction ON core.loyalty_ledger (transaction_id)
+WHERE transaction_id IS NOT NULL;
+---
+-- an entry can be reversed once
+CREATE UNIQUE INDEX idx_loyalty_ledger_reversal ON core.loyalty_ledger (reference_entry_id)
+WHERE entry_type = 'reversal';
+---
+-- customer_points_not_negative rejects spending points twice
+CREATE OR REPLACE FUNCTION core.fn_apply_loyalty_entry()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE core.customer
+    SET points_balance = points_balance + NEW.points, updated_by = NEW.created_by
+    WHERE id = NEW.customer_id
+    RETURNING points_balance INTO NEW.balance_after;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'customer % not found', NEW.customer_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_loyalty_ledger_apply
+BEFORE INSERT ON core.loyalty_ledger
+FOR EACH ROW EXECUTE FUNCTION core.fn_apply_loyalty_entry();
+---
+-- the ledger is append-only, corrections are reversal entries
+CREATE OR REPLACE FUNCTION core.fn_prevent_loyalty_ledger_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'loyalty ledger entries are append-only';
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_loyalty_ledger_append_only
+BEFORE UPDATE OR DELETE ON core.loyalty_ledger
+FOR EACH ROW EXECUTE FUNCTION core.fn_prevent_loyalty_ledger_change();
+---
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS points_earned INT NOT NULL DEFAULT 0;
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS points_redeemed INT NOT NULL DEFAULT 0;
+-- total_price_amount is what the customer paid, after this deduction
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS points_discount_amount BIGINT NOT NULL DEFAULT 0;
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['loyalty_program', 'loyalty_category_multiplier', 'loyalty_ledger'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
+---
+-- points lapse once, two concurrent sweeps cannot both expire the same entry
+CREATE UNIQUE INDEX idx_loyalty_ledger_expiry ON core.loyalty_ledger (reference_entry_id)
+WHERE entry_type = 'expire';
+---
+-- the points discount is spread over the sale's lines, total_price_amount of a line is net of its share
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS points_discount_amount BIGINT NOT NULL DEFAULT 0;
+---
+-- spread the discount of the sales booked before, run as the table owner so every tenant's sales are redone.
+-- Each line takes its share rounded down and the last line the rest, fn_update_sales_summary moves the revenue
+-- of the daily summaries along with the lines.
+WITH lines AS (
+    SELECT
+        d.id, d.transaction_id, d.total_price_amount, t.points_discount_amount AS discount,
+        t.points_discount_amount * d.total_price_amount / SUM(d.total_price_amount) OVER (PARTITION BY d.transaction_id) AS share,
+        ROW_NUMBER() OVER (PARTITION BY d.transaction_id ORDER BY d.id DESC) AS from_last
+    FROM core.transaction_detail d
+    JOIN core.transaction t ON t.id = d.transaction_id
+    WHERE t.points_discount_amount > 0 AND d.total_price_amount > 0
+        AND NOT EXISTS (
+            SELECT 1 FROM core.transaction_detail x
+            WHERE x.transaction_id = t.id AND x.points_discount_amount > 0
+        )
+), shares AS (
+    SELECT
+        id,
+        LEAST(total_price_amount, CASE
+            WHEN from_last = 1 THEN discount - (SUM(share) OVER (PARTITION BY transaction_id) - share)
+            ELSE share
+        END) AS share
+    FROM lines
+)
+UPDATE core.transaction_detail d
+SET
+    points_discount_amount = s.share,
+    total_price_amount = d.total_price_amount - s.share
+FROM shares s
+WHERE d.id = s.id;
@@ -0,0 +1,106 @@
+CREATE TABLE IF NOT EXISTS core.outlet (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    address TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT outlet_code_not_empty CHECK (char_length(trim(code)) > 0),
+    CONSTRAINT outlet_name_not_empty CHECK (char_length(trim(name)) > 0)
+);
+---
+CREATE UNIQUE INDEX idx_outlet_active_code ON core.outlet (lower(code))
+WHERE deleted_at IS NULL;
+---
+CREATE TRIGGER trg_outlet_version_increment
+BEFORE UPDATE ON core.outlet
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+-- the stock an outlet holds of a product, core.product.stock stays the total over every outlet and unassigned stock
+CREATE TABLE IF NOT EXISTS core.outlet_stock (
+    outlet_id UUID NOT NULL REFERENCES core.outlet(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    stock INT NOT NULL DEFAULT 0, -- kept by trg_stock_movement_outlet
+    price_amount BIGINT, -- NULL sells at the product price
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (outlet_id, product_id),
+    CONSTRAINT outlet_stock_not_negative CHECK (stock >= 0),
+    CONSTRAINT outlet_price_not_negative CHECK (price_amount IS NULL OR price_amount >= 0)
+);
+---
+ALTER TABLE core.stock_movement ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT;
+---
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES core.outlet(id) ON DELETE SET NULL;
+---
+CREATE INDEX idx_transaction_outlet_created ON core.transaction (outlet_id, created_at)
+WHERE outlet_id IS NOT NULL;
+---
+-- a movement at an outlet moves that outlet's stock too, outlet_stock_not_negative rejects overselling it
+CREATE OR REPLACE FUNCTION core.fn_apply_stock_movement_outlet()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO core.outlet_stock (outlet_id, product_id, stock)
+    VALUES (NEW.outlet_id, NEW.product_id, NEW.quantity)
+    ON CONFLICT (outlet_id, product_id) DO UPDATE SET
+        stock = core.outlet_stock.stock + EXCLUDED.stock,
+        updated_at = CURRENT_TIMESTAMP;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_stock_movement_outlet
+BEFORE INSERT ON core.stock_movement
+FOR EACH ROW WHEN (NEW.outlet_id IS NOT NULL) EXECUTE FUNCTION core.fn_apply_stock_movement_outlet();
+---
+CREATE TABLE IF NOT EXISTS core.stock_transfer (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- dispatched, the stock left the source outlet
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    from_outlet_id UUID NOT NULL REFERENCES core.outlet(id) ON DELETE RESTRICT,
+    to_outlet_id UUID NOT NULL REFERENCES core.outlet(id) ON DELETE RESTRICT,
+    status TEXT NOT NULL DEFAULT 'in_transit',
+    notes TEXT,
+    received_at TIMESTAMPTZ,
+    received_by TEXT,
+    cancelled_at TIMESTAMPTZ,
+
+    CONSTRAINT stock_transfer_status_valid CHECK (status IN ('in_transit', 'received', 'cancelled')),
+    CONSTRAINT stock_transfer_outlets_differ CHECK (from_outlet_id <> to_outlet_id)
+);
+---
+CREATE INDEX idx_stock_transfer_status ON core.stock_transfer (status, created_at DESC);
+---
+CREATE TRIGGER trg_stock_transfer_version_increment
+BEFORE UPDATE ON core.stock_transfer
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE TABLE IF NOT EXISTS core.stock_transfer_line (
+    stock_transfer_id UUID NOT NULL REFERENCES core.stock_transfer(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL,
+
+    PRIMARY KEY (stock_transfer_id, product_id),
+    CONSTRAINT transfer_quantity_positive CHECK (quantity > 0)
+);
+---
+-- goods received and stock counted at an outlet move that outlet's stock, NULL keeps them on the stock held at no outlet
+ALTER TABLE core.goods_receipt ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT;
+---
+ALTER TABLE core.stock_count ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT;
+---
+-- a lot received at an outlet sits in that outlet's stock, sales only draw the lots held where they sell from
+ALTER TABLE core.product_lot ADD COLUMN IF NOT EXISTS outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT;
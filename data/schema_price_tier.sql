@@ -0,0 +1,27 @@
+-- Apply after schema_pricing.sql.
+-- A product's unit price from min_quantity units on a line, until the next tier's min_quantity.
+CREATE TABLE IF NOT EXISTS core.product_price_tier (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    min_quantity INT NOT NULL,
+    price_amount BIGINT NOT NULL,
+
+    CONSTRAINT product_price_tier_min_quantity_positive CHECK (min_quantity > 0),
+    CONSTRAINT product_price_tier_not_negative CHECK (price_amount >= 0),
+    CONSTRAINT product_price_tier_unique UNIQUE (product_id, min_quantity)
+);
+---
+-- the tier the line's unit price came from, NULL when no tier applied
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS price_tier_min_quantity INT;
+---
+ALTER TABLE core.product_price_tier ENABLE ROW LEVEL SECURITY;
+---
+ALTER TABLE core.product_price_tier FORCE ROW LEVEL SECURITY;
+---
+CREATE POLICY tenant_isolation ON core.product_price_tier
+USING (tenant_id = core.fn_current_tenant_id())
+WITH CHECK (tenant_id = core.fn_current_tenant_id());
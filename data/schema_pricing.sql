@@ -0,0 +1,68 @@
+-- Apply after schema_sync.sql, the pricing tables are created tenant-scoped from the start.
+-- A price list prices a channel or a customer group, such as wholesale or member.
+CREATE TABLE IF NOT EXISTS core.price_list (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT price_list_code_not_empty CHECK (char_length(trim(code)) > 0),
+    CONSTRAINT price_list_name_not_empty CHECK (char_length(trim(name)) > 0)
+);
+---
+CREATE UNIQUE INDEX idx_price_list_active_code ON core.price_list (tenant_id, lower(code))
+WHERE deleted_at IS NULL;
+---
+CREATE TRIGGER trg_price_list_version_increment
+BEFORE UPDATE ON core.price_list
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+-- A product's price from starts_at on, until a later row of the same list starts. A NULL price list schedules
+-- the base price, the one a sale without a price list pays instead of core.product.price_amount once it starts.
+CREATE TABLE IF NOT EXISTS core.product_price (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ, -- a scheduled price cancelled before it started
+
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    price_list_id UUID REFERENCES core.price_list(id) ON DELETE CASCADE,
+    price_amount BIGINT NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+
+    CONSTRAINT product_price_not_negative CHECK (price_amount >= 0)
+);
+---
+-- one price per list and start, uuid_nil stands in for the base price schedule
+CREATE UNIQUE INDEX idx_product_price_start ON core.product_price (
+    product_id, COALESCE(price_list_id, '00000000-0000-0000-0000-000000000000'::uuid), starts_at
+)
+WHERE deleted_at IS NULL;
+---
+ALTER TABLE core.customer ADD COLUMN IF NOT EXISTS price_list_id UUID REFERENCES core.price_list(id) ON DELETE SET NULL;
+---
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS price_list_id UUID REFERENCES core.price_list(id) ON DELETE SET NULL;
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['price_list', 'product_price'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
@@ -45,8 +45,49 @@ WHERE deleted_at IS NULL;
 CREATE INDEX idx_product_category_price ON core.product (category_id, price_display)
 WHERE deleted_at IS NULL;
 ---
+-- 0 disables low-stock alerts for the product
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS reorder_point INT NOT NULL DEFAULT 0;
+---
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS reorder_quantity INT NOT NULL DEFAULT 0;
+---
+ALTER TABLE core.product ADD CONSTRAINT reorder_not_negative CHECK (reorder_point >= 0 AND reorder_quantity >= 0);
+---
+CREATE INDEX idx_product_low_stock ON core.product (stock, reorder_point)
+WHERE deleted_at IS NULL AND reorder_point > 0;
+---
+-- moving average of the purchase cost, same scale as price_amount
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS cost_price_amount BIGINT NOT NULL DEFAULT 0;
+---
+ALTER TABLE core.product ADD CONSTRAINT cost_price_not_negative CHECK (cost_price_amount >= 0);
+---
 CREATE INDEX idx_product_name_tsvector ON core.product USING GIN (name_tsvector);
 ---
 CREATE TRIGGER trg_product_version_increment
 BEFORE UPDATE ON core.product
 FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+-- A bundle sells a set of component products, priced fixed or derived from its components.
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS product_type TEXT NOT NULL DEFAULT 'standard';
+---
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS bundle_pricing TEXT;
+---
+ALTER TABLE core.product ADD CONSTRAINT product_type_valid CHECK (product_type IN ('standard', 'bundle'));
+---
+ALTER TABLE core.product ADD CONSTRAINT bundle_pricing_valid CHECK (
+    (product_type = 'standard' AND bundle_pricing IS NULL) OR
+    (product_type = 'bundle' AND bundle_pricing IN ('fixed', 'derived'))
+);
+---
+CREATE TABLE IF NOT EXISTS core.product_bundle_component (
+    bundle_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    component_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+
+    PRIMARY KEY (bundle_id, component_id),
+    CONSTRAINT quantity_positive CHECK (quantity > 0),
+    CONSTRAINT component_not_self CHECK (bundle_id <> component_id)
+);
+---
+CREATE INDEX idx_product_bundle_component_component ON core.product_bundle_component (component_id);
@@ -0,0 +1,32 @@
+-- Apply after schema_unit.sql.
+-- A picture of the product. The files are in the blob store under key and thumbnail_key,
+-- url and thumbnail_url are where they were served from when uploaded.
+CREATE TABLE IF NOT EXISTS core.product_image (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    position INT NOT NULL, -- the first one is the product's main picture
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    key TEXT NOT NULL,
+    url TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+
+    CONSTRAINT product_image_size_positive CHECK (size_bytes > 0 AND width > 0 AND height > 0)
+);
+---
+CREATE INDEX idx_product_image_product ON core.product_image (product_id, position);
+---
+ALTER TABLE core.product_image ENABLE ROW LEVEL SECURITY;
+---
+ALTER TABLE core.product_image FORCE ROW LEVEL SECURITY;
+---
+CREATE POLICY tenant_isolation ON core.product_image
+USING (tenant_id = core.fn_current_tenant_id())
+WITH CHECK (tenant_id = core.fn_current_tenant_id());
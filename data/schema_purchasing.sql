@@ -0,0 +1,85 @@
+CREATE TABLE IF NOT EXISTS core.supplier (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    name TEXT NOT NULL,
+    contact_name TEXT,
+    phone TEXT,
+    email TEXT,
+    address TEXT,
+    notes TEXT,
+
+    CONSTRAINT supplier_name_not_empty CHECK (char_length(trim(name)) > 0)
+);
+---
+CREATE UNIQUE INDEX idx_supplier_active_name ON core.supplier (lower(name))
+WHERE deleted_at IS NULL;
+---
+CREATE TRIGGER trg_supplier_version_increment
+BEFORE UPDATE ON core.supplier
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE TABLE IF NOT EXISTS core.purchase_order (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    supplier_id UUID NOT NULL REFERENCES core.supplier(id) ON DELETE RESTRICT,
+    status TEXT NOT NULL DEFAULT 'draft',
+    notes TEXT,
+    sent_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+
+    CONSTRAINT purchase_order_status_valid CHECK (status IN ('draft', 'sent', 'partially_received', 'closed'))
+);
+---
+CREATE INDEX idx_purchase_order_status ON core.purchase_order (status, created_at DESC);
+---
+CREATE INDEX idx_purchase_order_supplier ON core.purchase_order (supplier_id);
+---
+CREATE TRIGGER trg_purchase_order_version_increment
+BEFORE UPDATE ON core.purchase_order
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+CREATE TABLE IF NOT EXISTS core.purchase_order_line (
+    purchase_order_id UUID NOT NULL REFERENCES core.purchase_order(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    quantity_ordered INT NOT NULL,
+    quantity_received INT NOT NULL DEFAULT 0,
+    cost_price_amount BIGINT NOT NULL,
+    cost_price_scale INT NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL DEFAULT 'IDR',
+
+    PRIMARY KEY (purchase_order_id, product_id),
+    CONSTRAINT quantity_ordered_positive CHECK (quantity_ordered > 0),
+    CONSTRAINT quantity_received_in_range CHECK (quantity_received BETWEEN 0 AND quantity_ordered),
+    CONSTRAINT cost_price_not_negative CHECK (cost_price_amount >= 0)
+);
+---
+CREATE TABLE IF NOT EXISTS core.goods_receipt (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    purchase_order_id UUID NOT NULL REFERENCES core.purchase_order(id) ON DELETE RESTRICT,
+    notes TEXT,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    received_by TEXT NOT NULL
+);
+---
+CREATE INDEX idx_goods_receipt_purchase_order ON core.goods_receipt (purchase_order_id, received_at);
+---
+CREATE TABLE IF NOT EXISTS core.goods_receipt_line (
+    goods_receipt_id UUID NOT NULL REFERENCES core.goods_receipt(id) ON DELETE RESTRICT,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    quantity INT NOT NULL,
+
+    PRIMARY KEY (goods_receipt_id, product_id),
+    CONSTRAINT receipt_quantity_positive CHECK (quantity > 0)
+);
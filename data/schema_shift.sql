@@ -0,0 +1,101 @@
+-- Apply after schema_gift_card.sql, the shift tables are created tenant-scoped from the start.
+-- A shift is one cashier's session on a cash drawer, from the opening float to the counted cash at close.
+CREATE TABLE IF NOT EXISTS core.cashier_shift (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    version    INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- opened
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+
+    cashier_name TEXT NOT NULL,
+    outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT, -- NULL for a shop without outlets
+    status TEXT NOT NULL DEFAULT 'open',
+    opening_float BIGINT NOT NULL DEFAULT 0, -- IDR in the drawer when the shift opened
+    notes TEXT,
+    closed_at TIMESTAMPTZ,
+    closed_by TEXT,
+    expected_cash BIGINT, -- IDR the drawer should hold, snapshotted at close
+    counted_cash BIGINT, -- IDR the supervisor counted at close
+
+    CONSTRAINT cashier_shift_status_valid CHECK (status IN ('open', 'closed')),
+    CONSTRAINT cashier_shift_opening_float_not_negative CHECK (opening_float >= 0),
+    CONSTRAINT cashier_shift_counted_cash_not_negative CHECK (counted_cash IS NULL OR counted_cash >= 0),
+    CONSTRAINT cashier_shift_closed_complete CHECK (
+        (status = 'open' AND closed_at IS NULL AND counted_cash IS NULL)
+        OR (status = 'closed' AND closed_at IS NOT NULL AND expected_cash IS NOT NULL AND counted_cash IS NOT NULL)
+    )
+);
+---
+CREATE TRIGGER trg_cashier_shift_version_increment
+BEFORE UPDATE ON core.cashier_shift
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+-- a cashier works one drawer at a time
+CREATE UNIQUE INDEX idx_cashier_shift_open ON core.cashier_shift (tenant_id, cashier_name)
+WHERE status = 'open';
+---
+CREATE INDEX idx_cashier_shift_created ON core.cashier_shift (created_at DESC);
+---
+-- petty cash put into or taken out of the drawer during the shift
+CREATE TABLE IF NOT EXISTS core.cash_drawer_entry (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    shift_id UUID NOT NULL REFERENCES core.cashier_shift(id) ON DELETE RESTRICT,
+    entry_type TEXT NOT NULL,
+    amount BIGINT NOT NULL, -- IDR, the type gives the direction
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL, -- the actor
+
+    CONSTRAINT cash_drawer_entry_type_valid CHECK (entry_type IN ('cash_in', 'cash_out')),
+    CONSTRAINT cash_drawer_entry_amount_positive CHECK (amount > 0)
+);
+---
+CREATE INDEX idx_cash_drawer_entry_shift ON core.cash_drawer_entry (shift_id, created_at);
+---
+ALTER TABLE core.transaction ADD COLUMN IF NOT EXISTS shift_id UUID REFERENCES core.cashier_shift(id) ON DELETE RESTRICT;
+---
+CREATE INDEX idx_transaction_shift ON core.transaction (shift_id) WHERE shift_id IS NOT NULL;
+---
+-- A sale or petty cash entry can only be booked on an open shift. The share lock makes closing the shift wait
+-- for them to commit, so the expected cash snapshotted at close counts every one of them.
+CREATE OR REPLACE FUNCTION core.fn_require_open_shift()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.shift_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM 1 FROM core.cashier_shift WHERE id = NEW.shift_id AND status = 'open' FOR SHARE;
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'shift % is not open', NEW.shift_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE TRIGGER trg_transaction_require_open_shift
+BEFORE INSERT ON core.transaction
+FOR EACH ROW EXECUTE FUNCTION core.fn_require_open_shift();
+---
+CREATE TRIGGER trg_cash_drawer_entry_require_open_shift
+BEFORE INSERT ON core.cash_drawer_entry
+FOR EACH ROW EXECUTE FUNCTION core.fn_require_open_shift();
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['cashier_shift', 'cash_drawer_entry'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
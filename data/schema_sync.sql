@@ -0,0 +1,45 @@
+-- Apply after schema_kitchen.sql, the sync tables are created tenant-scoped from the start.
+-- Terminals pull the catalog changed since their cursor, updated_at is kept by trg_*_version_increment.
+CREATE INDEX IF NOT EXISTS idx_product_updated ON core.product (updated_at);
+---
+CREATE INDEX IF NOT EXISTS idx_category_updated ON core.category (updated_at);
+---
+-- A sale rung up offline can sell units the books no longer hold. The sale is kept, the missing units are booked
+-- as an adjustment so the stock stays non-negative, and the conflict waits here until someone reviews it.
+CREATE TABLE IF NOT EXISTS core.stock_conflict (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP, -- synced
+    created_by TEXT NOT NULL,
+
+    transaction_id UUID NOT NULL REFERENCES core.transaction(id) ON DELETE RESTRICT,
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE RESTRICT,
+    product_name TEXT NOT NULL, -- snapshotted when synced
+    outlet_id UUID REFERENCES core.outlet(id) ON DELETE RESTRICT, -- NULL for a sale not made at an outlet
+    quantity INT NOT NULL, -- units sold beyond the stock on the books
+    resolved_at TIMESTAMPTZ,
+    resolved_by TEXT,
+    resolution_notes TEXT,
+
+    CONSTRAINT stock_conflict_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT stock_conflict_resolved_complete CHECK ((resolved_at IS NULL) = (resolved_by IS NULL))
+);
+---
+CREATE INDEX idx_stock_conflict_open ON core.stock_conflict (created_at DESC) WHERE resolved_at IS NULL;
+---
+CREATE INDEX idx_stock_conflict_transaction ON core.stock_conflict (transaction_id);
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY['stock_conflict'] LOOP
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
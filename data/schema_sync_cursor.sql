@@ -0,0 +1,37 @@
+-- Apply after schema_product_image.sql.
+-- updated_at is stamped when the writing transaction starts, a pull can pass a row that commits later with an
+-- older stamp. Each catalog row keeps the id of the transaction that last wrote it instead, and a pull hands back
+-- the oldest transaction still running: everything below it has finished, anything at or above it is sent again.
+CREATE OR REPLACE FUNCTION core.fn_stamp_change_xid()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.change_xid := pg_current_xact_id();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+---
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT pg_current_xact_id();
+---
+ALTER TABLE core.category ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT pg_current_xact_id();
+---
+CREATE INDEX IF NOT EXISTS idx_product_change_xid ON core.product (change_xid);
+---
+CREATE INDEX IF NOT EXISTS idx_category_change_xid ON core.category (change_xid);
+---
+-- named to run after trg_*_version_increment, a stamp alone does not make a new version
+CREATE TRIGGER trg_product_xid_stamp
+BEFORE INSERT OR UPDATE ON core.product
+FOR EACH ROW EXECUTE FUNCTION core.fn_stamp_change_xid();
+---
+CREATE TRIGGER trg_category_xid_stamp
+BEFORE INSERT OR UPDATE ON core.category
+FOR EACH ROW EXECUTE FUNCTION core.fn_stamp_change_xid();
+---
+-- a deleted or deactivated price list reaches the terminals through its change_xid like a product does
+ALTER TABLE core.price_list ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT pg_current_xact_id();
+---
+CREATE INDEX IF NOT EXISTS idx_price_list_change_xid ON core.price_list (change_xid);
+---
+CREATE TRIGGER trg_price_list_xid_stamp
+BEFORE INSERT OR UPDATE ON core.price_list
+FOR EACH ROW EXECUTE FUNCTION core.fn_stamp_change_xid();
@@ -0,0 +1,150 @@
+-- Multi-tenancy: every core table carries the tenant owning the row and row-level security
+-- only lets a connection see the rows of the tenant named by app.current_tenant_id.
+-- The API sets it with set_config on every connection it takes from the pool, the same way
+-- app.current_user_id feeds the audit columns. Apply this file after all other schema files
+-- except those that create their tables tenant-scoped themselves, such as schema_customer.sql.
+--
+-- RLS does not apply to superusers or roles with BYPASSRLS, the API must connect as a plain role.
+CREATE TABLE IF NOT EXISTS core.tenant (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_by TEXT NOT NULL,
+    deleted_at TIMESTAMPTZ,
+    slug TEXT NOT NULL, -- the subdomain the shop is served on
+    name TEXT NOT NULL,
+    api_token_hash TEXT, -- hex SHA-256 of the bearer token
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    CONSTRAINT tenant_slug_format CHECK (slug ~ '^[a-z0-9]([a-z0-9-]*[a-z0-9])?$')
+);
+---
+CREATE UNIQUE INDEX idx_tenant_slug ON core.tenant (slug);
+CREATE UNIQUE INDEX idx_tenant_api_token_hash ON core.tenant (api_token_hash)
+WHERE api_token_hash IS NOT NULL;
+---
+CREATE TRIGGER trg_tenant_version_increment
+BEFORE UPDATE ON core.tenant
+FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+---
+-- rows that existed before multi-tenancy belong to the default tenant
+INSERT INTO core.tenant (id, slug, name, created_by, updated_by)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default', 'SYSTEM', 'SYSTEM')
+ON CONFLICT DO NOTHING;
+---
+-- NULL when the connection names no tenant, which matches no row
+CREATE OR REPLACE FUNCTION core.fn_current_tenant_id()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.current_tenant_id', true), '')::uuid;
+$$ LANGUAGE sql STABLE;
+---
+DO $$
+DECLARE
+    v_table TEXT;
+BEGIN
+    FOREACH v_table IN ARRAY ARRAY[
+        'category', 'product', 'product_bundle_component', 'product_lot', 'stock_movement',
+        'stock_count', 'stock_count_line', 'supplier', 'purchase_order', 'purchase_order_line',
+        'goods_receipt', 'goods_receipt_line', 'outlet', 'outlet_stock', 'stock_transfer',
+        'stock_transfer_line', 'transaction', 'transaction_detail',
+        'sales_summary_daily', 'transaction_summary_daily'
+    ] LOOP
+        EXECUTE format('ALTER TABLE core.%I ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES core.tenant(id)', v_table);
+        EXECUTE format('UPDATE core.%I SET tenant_id = %L WHERE tenant_id IS NULL', v_table, '00000000-0000-0000-0000-000000000001');
+        -- inserts, including the ones made by triggers, pick up the connection's tenant
+        EXECUTE format('ALTER TABLE core.%I ALTER COLUMN tenant_id SET DEFAULT core.fn_current_tenant_id()', v_table);
+        EXECUTE format('ALTER TABLE core.%I ALTER COLUMN tenant_id SET NOT NULL', v_table);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON core.%I (tenant_id)', 'idx_' || v_table || '_tenant', v_table);
+
+        EXECUTE format('ALTER TABLE core.%I ENABLE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('ALTER TABLE core.%I FORCE ROW LEVEL SECURITY', v_table);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON core.%I', v_table);
+        EXECUTE format(
+            'CREATE POLICY tenant_isolation ON core.%I USING (tenant_id = core.fn_current_tenant_id()) WITH CHECK (tenant_id = core.fn_current_tenant_id())',
+            v_table
+        );
+    END LOOP;
+END;
+$$;
+---
+-- views read through the caller's policies instead of the owner's
+ALTER VIEW core.v_stock_reconciliation SET (security_invoker = true);
+---
+-- names and codes only have to be unique within a shop
+DROP INDEX IF EXISTS core.idx_category_active_name;
+CREATE UNIQUE INDEX idx_category_active_name ON core.category (tenant_id, lower(name))
+WHERE deleted_at IS NULL;
+---
+DROP INDEX IF EXISTS core.idx_supplier_active_name;
+CREATE UNIQUE INDEX idx_supplier_active_name ON core.supplier (tenant_id, lower(name))
+WHERE deleted_at IS NULL;
+---
+DROP INDEX IF EXISTS core.idx_outlet_active_code;
+CREATE UNIQUE INDEX idx_outlet_active_code ON core.outlet (tenant_id, lower(code))
+WHERE deleted_at IS NULL;
+---
+-- every shop has its own daily summaries
+ALTER TABLE core.sales_summary_daily DROP CONSTRAINT sales_summary_daily_pkey;
+ALTER TABLE core.sales_summary_daily ADD PRIMARY KEY (tenant_id, report_date, product_id, category_id);
+ALTER TABLE core.transaction_summary_daily DROP CONSTRAINT transaction_summary_daily_pkey;
+ALTER TABLE core.transaction_summary_daily ADD PRIMARY KEY (tenant_id, report_date);
+---
+-- the summary upserts now conflict on the tenant's row, the UPDATEs are already narrowed by the policy
+CREATE OR REPLACE FUNCTION core.fn_update_sales_summary()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_category_id UUID;
+BEGIN
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        v_category_id := COALESCE(NEW.category_id, '00000000-0000-0000-0000-000000000000'::uuid);
+    END IF;
+
+    IF (TG_OP = 'DELETE' OR TG_OP = 'UPDATE') THEN
+        UPDATE core.sales_summary_daily
+        SET
+            total_sold = total_sold - OLD.quantity,
+            total_revenue = total_revenue - OLD.total_price_amount
+        WHERE
+            report_date = DATE(OLD.created_at)
+            AND product_id = OLD.product_id
+            AND category_id = COALESCE(OLD.category_id, '00000000-0000-0000-0000-000000000000'::uuid);
+    END IF;
+
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        INSERT INTO core.sales_summary_daily (tenant_id, report_date, product_id, category_id, total_sold, total_revenue)
+        VALUES (NEW.tenant_id, DATE(NEW.created_at), NEW.product_id, v_category_id, NEW.quantity, NEW.total_price_amount)
+        ON CONFLICT (tenant_id, report_date, product_id, category_id) DO UPDATE SET
+            total_sold = core.sales_summary_daily.total_sold + EXCLUDED.total_sold,
+            total_revenue = core.sales_summary_daily.total_revenue + EXCLUDED.total_revenue;
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+---
+CREATE OR REPLACE FUNCTION core.fn_update_transaction_summary()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO core.transaction_summary_daily (tenant_id, report_date, total_revenue, total_transactions)
+        VALUES (NEW.tenant_id, DATE(NEW.created_at), NEW.total_price_amount, 1)
+        ON CONFLICT (tenant_id, report_date) DO UPDATE SET
+            total_revenue = core.transaction_summary_daily.total_revenue + EXCLUDED.total_revenue,
+            total_transactions = core.transaction_summary_daily.total_transactions + EXCLUDED.total_transactions;
+    ELSIF (TG_OP = 'DELETE') THEN
+        UPDATE core.transaction_summary_daily
+        SET
+            total_revenue = total_revenue - OLD.total_price_amount,
+            total_transactions = total_transactions - 1
+        WHERE report_date = DATE(OLD.created_at);
+    ELSIF (TG_OP = 'UPDATE') THEN
+        UPDATE core.transaction_summary_daily
+        SET total_revenue = total_revenue - OLD.total_price_amount + NEW.total_price_amount
+        WHERE report_date = DATE(NEW.created_at);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
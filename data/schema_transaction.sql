@@ -53,3 +53,7 @@ FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
 CREATE TRIGGER trg_transaction_detail_version_increment
 BEFORE UPDATE ON core.transaction_detail
 FOR EACH ROW EXECUTE FUNCTION core.fn_increment_version();
+
+-- product cost snapshotted at sale time, same scale as price_amount
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS cost_price_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS total_cost_amount BIGINT NOT NULL DEFAULT 0;
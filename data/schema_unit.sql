@@ -0,0 +1,98 @@
+-- Apply after schema_price_tier.sql.
+-- Stock is kept in the product's base unit, every quantity counting 10^-quantity_scale of it:
+-- rice sold by the kilo at scale 3 keeps stock in grams, its price stays per kilo.
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS unit TEXT NOT NULL DEFAULT 'pcs';
+---
+ALTER TABLE core.product ADD COLUMN IF NOT EXISTS quantity_scale INT NOT NULL DEFAULT 0;
+---
+ALTER TABLE core.product ADD CONSTRAINT product_quantity_scale_range CHECK (quantity_scale BETWEEN 0 AND 3);
+---
+-- A unit the product is also sold in, such as a carton of 24 pieces
+CREATE TABLE IF NOT EXISTS core.product_unit (
+    id UUID PRIMARY KEY DEFAULT uuidv7(),
+    tenant_id UUID NOT NULL DEFAULT core.fn_current_tenant_id() REFERENCES core.tenant(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_by TEXT NOT NULL,
+
+    product_id UUID NOT NULL REFERENCES core.product(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    factor INT NOT NULL, -- stock units one of this unit holds, at the product's quantity_scale
+    price_amount BIGINT, -- the unit's own price, NULL to charge factor's worth of the base price
+
+    CONSTRAINT product_unit_name_not_empty CHECK (char_length(trim(name)) > 0),
+    CONSTRAINT product_unit_factor_positive CHECK (factor > 0),
+    CONSTRAINT product_unit_price_not_negative CHECK (price_amount >= 0)
+);
+---
+CREATE UNIQUE INDEX idx_product_unit_name ON core.product_unit (product_id, lower(name));
+---
+-- quantity stays in stock units, unit and unit_quantity are what the receipt shows
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS quantity_scale INT NOT NULL DEFAULT 0;
+---
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS unit TEXT;
+---
+ALTER TABLE core.transaction_detail ADD COLUMN IF NOT EXISTS unit_quantity NUMERIC(18, 6);
+---
+ALTER TABLE core.product_unit ENABLE ROW LEVEL SECURITY;
+---
+ALTER TABLE core.product_unit FORCE ROW LEVEL SECURITY;
+---
+CREATE POLICY tenant_isolation ON core.product_unit
+USING (tenant_id = core.fn_current_tenant_id())
+WITH CHECK (tenant_id = core.fn_current_tenant_id());
+---
+-- the daily summaries count base units: 750 grams of rice at scale 3 adds 0.75 kilo, not 750
+ALTER TABLE core.sales_summary_daily ALTER COLUMN total_sold TYPE NUMERIC(18, 6);
+---
+CREATE OR REPLACE FUNCTION core.fn_update_sales_summary()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_category_id UUID;
+BEGIN
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        v_category_id := COALESCE(NEW.category_id, '00000000-0000-0000-0000-000000000000'::uuid);
+    END IF;
+
+    IF (TG_OP = 'DELETE' OR TG_OP = 'UPDATE') THEN
+        UPDATE core.sales_summary_daily
+        SET
+            total_sold = total_sold - OLD.quantity::NUMERIC / power(10::NUMERIC, OLD.quantity_scale),
+            total_revenue = total_revenue - OLD.total_price_amount
+        WHERE
+            report_date = DATE(OLD.created_at)
+            AND product_id = OLD.product_id
+            AND category_id = COALESCE(OLD.category_id, '00000000-0000-0000-0000-000000000000'::uuid);
+    END IF;
+
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        INSERT INTO core.sales_summary_daily (tenant_id, report_date, product_id, category_id, total_sold, total_revenue)
+        VALUES (
+            NEW.tenant_id, DATE(NEW.created_at), NEW.product_id, v_category_id,
+            NEW.quantity::NUMERIC / power(10::NUMERIC, NEW.quantity_scale), NEW.total_price_amount
+        )
+        ON CONFLICT (tenant_id, report_date, product_id, category_id) DO UPDATE SET
+            total_sold = core.sales_summary_daily.total_sold + EXCLUDED.total_sold,
+            total_revenue = core.sales_summary_daily.total_revenue + EXCLUDED.total_revenue;
+        RETURN NEW;
+    END IF;
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+---
+-- rebuild what was summed in stock units before, run as the table owner so every tenant's rows are redone
+UPDATE core.sales_summary_daily s
+SET total_sold = d.total_sold
+FROM (
+    SELECT
+        tenant_id, DATE(created_at) AS report_date, product_id,
+        COALESCE(category_id, '00000000-0000-0000-0000-000000000000'::uuid) AS category_id,
+        SUM(quantity::NUMERIC / power(10::NUMERIC, quantity_scale)) AS total_sold
+    FROM core.transaction_detail
+    WHERE quantity_scale > 0
+    GROUP BY 1, 2, 3, 4
+) d
+WHERE s.tenant_id = d.tenant_id
+  AND s.report_date = d.report_date
+  AND s.product_id = d.product_id
+  AND s.category_id = d.category_id;
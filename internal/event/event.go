@@ -0,0 +1,87 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// ProductLowStock is published when a sale pushes a product to or below its reorder point
+	ProductLowStock = "product.low_stock"
+	// KitchenTicketCreated and KitchenTicketUpdated are about the ticket's kitchen station
+	KitchenTicketCreated = "kitchen_ticket.created"
+	KitchenTicketUpdated = "kitchen_ticket.updated"
+)
+
+type Event struct {
+	Type       string    `json:"type"`
+	Subject    string    `json:"subject,omitempty"` // what the event is about, subscribers may only want one
+	OccurredAt time.Time `json:"occurred_at"`
+	Payload    any       `json:"payload"`
+}
+
+func New(eventType string, payload any) Event {
+	return Event{
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Payload:    payload,
+	}
+}
+
+// NewWithSubject is New for an event subscribers can filter by subject, a kitchen display by its station
+func NewWithSubject(eventType, subject string, payload any) Event {
+	e := New(eventType, payload)
+	e.Subject = subject
+	return e
+}
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Broker is an in-process fan-out of events to every subscriber.
+// Publishing never blocks, a subscriber that falls behind misses events instead of stalling the caller.
+type Broker struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]chan Event
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: map[int]chan Event{},
+	}
+}
+
+func (b *Broker) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving every published event and a func to stop receiving them
+func (b *Broker) Subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan Event, buffer)
+	b.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers, id)
+			close(ch)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_PublishSubscribe(t *testing.T) {
+	broker := NewBroker()
+	first, unsubscribeFirst := broker.Subscribe(1)
+	second, unsubscribeSecond := broker.Subscribe(1)
+	defer unsubscribeSecond()
+
+	broker.Publish(New(ProductLowStock, "payload"))
+
+	assert.Equal(t, ProductLowStock, (<-first).Type)
+	assert.Equal(t, "payload", (<-second).Payload)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, open := <-first
+	assert.False(t, open)
+
+	broker.Publish(New(ProductLowStock, "again"))
+	assert.Equal(t, "again", (<-second).Payload)
+}
+
+func TestBroker_PublishDoesNotBlockOnSlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+	events, unsubscribe := broker.Subscribe(1)
+	defer unsubscribe()
+
+	broker.Publish(New(ProductLowStock, 1))
+	broker.Publish(New(ProductLowStock, 2))
+
+	assert.Equal(t, 1, (<-events).Payload)
+	assert.Len(t, events, 0)
+}
+
+func TestNewWithSubject(t *testing.T) {
+	e := NewWithSubject(KitchenTicketCreated, "bar", "ticket")
+	assert.Equal(t, KitchenTicketCreated, e.Type)
+	assert.Equal(t, "bar", e.Subject)
+	assert.Empty(t, New(ProductLowStock, nil).Subject)
+}
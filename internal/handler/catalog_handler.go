@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+// maxImportSize caps an uploaded catalog file
+const maxImportSize = 20 << 20
+
+type CatalogHandler struct {
+	catalogService service.CatalogService
+}
+
+func NewCatalogHandler(catalogService service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+// POST /api/products/import?format=<csv|xlsx|json>&dry_run=<true to only validate>
+// The file is the body, or the "file" field of a multipart form. Without a format it is told by the content type or file name.
+func (h *CatalogHandler) ImportProducts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "dry_run must be true or false"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	format := r.URL.Query().Get("format")
+	var data []byte
+	var err error
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, header, formErr := r.FormFile("file")
+		if formErr != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "The file field is required"))
+			return
+		}
+		defer file.Close()
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+		data, err = io.ReadAll(file)
+	} else {
+		if format == "" {
+			format = catalogFormatOf(mediaType)
+		}
+		data, err = io.ReadAll(r.Body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	report, err := h.catalogService.ImportProducts(format, data, dryRun)
+	if err != nil {
+		writeCatalogError(w, err, "Failed to import products")
+		return
+	}
+	// rows that failed are in the report, the rest were imported
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(report))
+}
+
+// catalogFormatOf tells the file format from its content type, empty when it is not one of ours
+func catalogFormatOf(mediaType string) string {
+	for format, contentType := range model.CatalogContentTypes {
+		if mediaType == contentType {
+			return format
+		}
+	}
+	return ""
+}
+
+// GET /api/products/export?format=<csv|xlsx|json, csv when empty>
+func (h *CatalogHandler) ExportProducts(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = model.CatalogFormatCSV
+	}
+	out := &exportWriter{ResponseWriter: w, format: format}
+	if err := h.catalogService.ExportProducts(format, out); err != nil && !out.started {
+		w.Header().Set("Content-Type", "application/json")
+		writeCatalogError(w, err, "Failed to export products")
+	}
+	// an error once the file is on its way can only cut it short
+}
+
+// exportWriter sends the file headers with the first write, so an error before it is still answered as JSON
+type exportWriter struct {
+	http.ResponseWriter
+	format  string
+	started bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.Header().Set("Content-Type", model.CatalogContentTypes[e.format])
+		e.Header().Set("Content-Disposition", `attachment; filename="products.`+e.format+`"`)
+		e.WriteHeader(http.StatusOK)
+	}
+	return e.ResponseWriter.Write(p)
+}
+
+func writeCatalogError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProduct):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
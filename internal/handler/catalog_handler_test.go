@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCatalogHandlerImportProducts(t *testing.T) {
+	mockService := new(mocks.MockCatalogService)
+	handler := NewCatalogHandler(mockService)
+
+	csv := []byte("name,price\nKopi,18000\n")
+	mockService.On("ImportProducts", model.CatalogFormatCSV, csv, true).Return(model.ProductImportReport{DryRun: true, TotalRows: 1, Created: 1}, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/products/import?dry_run=true", bytes.NewReader(csv))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	handler.ImportProducts(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"created":1`)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "Products.CSV")
+	_, _ = part.Write(csv)
+	_ = form.Close()
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/api/products/import?dry_run=1", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	handler.ImportProducts(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "the format is told by the file name")
+
+	mockService.On("ImportProducts", "", []byte("x"), false).Return(model.ProductImportReport{}, fmt.Errorf("%w: format must be csv, xlsx or json", service.ErrInvalidProduct))
+	rec = httptest.NewRecorder()
+	handler.ImportProducts(rec, httptest.NewRequest("POST", "/api/products/import", bytes.NewBufferString("x")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ImportProducts(rec, httptest.NewRequest("POST", "/api/products/import?dry_run=maybe", bytes.NewReader(csv)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCatalogHandlerExportProducts(t *testing.T) {
+	mockService := new(mocks.MockCatalogService)
+	handler := NewCatalogHandler(mockService)
+
+	mockService.On("ExportProducts", model.CatalogFormatCSV, mock.Anything).Run(func(args mock.Arguments) {
+		_, _ = io.WriteString(args.Get(1).(io.Writer), "name\nKopi\n")
+	}).Return(nil)
+	mockService.On("ExportProducts", model.CatalogFormatXLSX, mock.Anything).Return(errors.New("connection reset"))
+
+	rec := httptest.NewRecorder()
+	handler.ExportProducts(rec, httptest.NewRequest("GET", "/api/products/export", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="products.csv"`, rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "name\nKopi\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ExportProducts(rec, httptest.NewRequest("GET", "/api/products/export?format=xlsx", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "nothing was written yet, the error is still answered")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+}
@@ -4,6 +4,7 @@ import (
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -47,6 +48,18 @@ func (h *CategoryHandler) FetchCategoryByID(w http.ResponseWriter, r *http.Reque
 
 }
 
+// GET /api/categories/{id}/tree
+func (h *CategoryHandler) FetchCategoryTree(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	tree, err := h.categoryService.FetchCategoryTree(r.PathValue("id"))
+	if err != nil {
+		writeCategoryError(w, err, "Failed to fetch category tree")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(tree))
+}
+
 // TODO: handle properly if invalid request with correct HTTPStatus
 // POST /api/categories
 func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
@@ -60,8 +73,7 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 
 	category, err := h.categoryService.CreateCategory(request)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to create category"))
+		writeCategoryError(w, err, "Failed to create category")
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -80,8 +92,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 	}
 	category, err := h.categoryService.UpdateCategoryByID(r.PathValue("id"), request)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to update category"))
+		writeCategoryError(w, err, "Failed to update category")
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -94,9 +105,29 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	err := h.categoryService.DeleteCategoryByID(r.PathValue("id"))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to delete category"))
+		writeCategoryError(w, err, "Failed to delete category")
 		return
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+func writeCategoryError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCategory):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrCategoryNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusNotFound, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonNotFound),
+		}))
+	case errors.Is(err, service.ErrCategoryHasSubcategories):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	mocks "codewithumam-kasir-api/internal/mock"
 	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -177,3 +179,49 @@ func TestCategoryHandlerDeleteCategoryError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestCategoryHandlerFetchCategoryTree(t *testing.T) {
+	mockService := new(mocks.MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+
+	mockService.On("FetchCategoryTree", "bev").Return(model.CategoryNode{
+		ID: "bev", Name: "Beverages", Breadcrumb: []model.CategoryCrumb{{ID: "bev", Name: "Beverages"}},
+		Children: []model.CategoryNode{{ID: "cof", Name: "Coffee", Children: []model.CategoryNode{}}},
+	}, nil)
+	mockService.On("FetchCategoryTree", "nope").Return(model.CategoryNode{}, fmt.Errorf("%w: nope", service.ErrCategoryNotFound))
+
+	req := httptest.NewRequest("GET", "/api/categories/bev/tree", nil)
+	req.SetPathValue("id", "bev")
+	rec := httptest.NewRecorder()
+	handler.FetchCategoryTree(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"children":[{"id":"cof"`)
+
+	req = httptest.NewRequest("GET", "/api/categories/nope/tree", nil)
+	req.SetPathValue("id", "nope")
+	rec = httptest.NewRecorder()
+	handler.FetchCategoryTree(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestCategoryHandlerHierarchyErrors(t *testing.T) {
+	mockService := new(mocks.MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+
+	request := model.UpdateCategoryRequest{Name: "Beverages", ParentID: "cof", Version: 1}
+	mockService.On("UpdateCategoryByID", "bev", request).Return(model.Category{}, fmt.Errorf("%w: a category cannot be moved under itself or its own subcategory", service.ErrInvalidCategory))
+	mockService.On("DeleteCategoryByID", "bev").Return(fmt.Errorf("%w: 1 categories are below it", service.ErrCategoryHasSubcategories))
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("PUT", "/api/categories/bev", bytes.NewBuffer(body))
+	req.SetPathValue("id", "bev")
+	rec := httptest.NewRecorder()
+	handler.UpdateCategory(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/categories/bev", nil)
+	req.SetPathValue("id", "bev")
+	rec = httptest.NewRecorder()
+	handler.DeleteCategory(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type CustomerHandler struct {
+	customerService service.CustomerService
+}
+
+func NewCustomerHandler(customerService service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+	}
+}
+
+// GET /api/customers?phone=<phone number, empty for all>
+func (h *CustomerHandler) FetchCustomers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	customers, err := h.customerService.FetchCustomers(r.URL.Query().Get("phone"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch customers"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(customers))
+}
+
+// GET /api/customers/{id}
+func (h *CustomerHandler) FetchCustomerByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	customer, err := h.customerService.FetchCustomerByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch customer"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(customer))
+}
+
+// POST /api/customers
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	customer, err := h.customerService.CreateCustomer(request)
+	if err != nil {
+		writeCustomerError(w, err, "Failed to create customer")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(customer))
+}
+
+// PUT /api/customers/{id}
+func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	customer, err := h.customerService.UpdateCustomerByID(r.PathValue("id"), request)
+	if err != nil {
+		writeCustomerError(w, err, "Failed to update customer")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(customer))
+}
+
+// DELETE /api/customers/{id}
+func (h *CustomerHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.customerService.DeleteCustomerByID(r.PathValue("id")); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to delete customer"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET /api/customers/{id}/transactions
+func (h *CustomerHandler) FetchCustomerTransactions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	history, err := h.customerService.FetchCustomerTransactions(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch customer transactions"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(history))
+}
+
+func writeCustomerError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrInvalidCustomer) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+}
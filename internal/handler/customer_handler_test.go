@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerHandlerCreateCustomer(t *testing.T) {
+	mockService := new(mocks.MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	valid := model.CreateCustomerRequest{Name: "Budi", Phone: "08123456"}
+	invalid := model.CreateCustomerRequest{Phone: "08123456"}
+	mockService.On("CreateCustomer", valid).Return(model.Customer{ID: "1", Name: "Budi"}, nil)
+	mockService.On("CreateCustomer", invalid).Return(model.Customer{}, fmt.Errorf("%w: name is required", service.ErrInvalidCustomer))
+
+	for _, tt := range []struct {
+		request model.CreateCustomerRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.CreateCustomer(rec, httptest.NewRequest("POST", "/api/customers", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestCustomerHandlerFetchCustomers_ByPhone(t *testing.T) {
+	mockService := new(mocks.MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("FetchCustomers", "08123456").Return([]model.Customer{{ID: "1", Name: "Budi", Phone: "08123456"}}, nil)
+
+	rec := httptest.NewRecorder()
+	handler.FetchCustomers(rec, httptest.NewRequest("GET", "/api/customers?phone=08123456", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"Budi"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestCustomerHandlerFetchCustomerTransactions(t *testing.T) {
+	mockService := new(mocks.MockCustomerService)
+	handler := NewCustomerHandler(mockService)
+
+	mockService.On("FetchCustomerTransactions", "c1").Return(model.CustomerHistory{
+		Customer:      model.Customer{ID: "c1", Name: "Budi"},
+		LifetimeSpend: model.Price{Amount: 40000, Currency: "IDR"},
+		VisitCount:    2,
+		Transactions:  []model.Transaction{{ID: "t1"}, {ID: "t2"}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/customers/c1/transactions", nil)
+	req.SetPathValue("id", "c1")
+	rec := httptest.NewRecorder()
+	handler.FetchCustomerTransactions(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"visit_count":2`)
+	assert.Contains(t, rec.Body.String(), `"amount":40000`)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type DiningTableHandler struct {
+	diningTableService service.DiningTableService
+}
+
+func NewDiningTableHandler(diningTableService service.DiningTableService) *DiningTableHandler {
+	return &DiningTableHandler{
+		diningTableService: diningTableService,
+	}
+}
+
+// GET /api/tables?outlet_id=<outlet id, empty for every outlet>
+func (h *DiningTableHandler) FetchDiningTables(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	tables, err := h.diningTableService.FetchDiningTables(r.URL.Query().Get("outlet_id"))
+	if err != nil {
+		writeDiningTableError(w, err, "Failed to fetch tables")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(tables))
+}
+
+// GET /api/tables/{id}
+func (h *DiningTableHandler) FetchDiningTableByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	table, err := h.diningTableService.FetchDiningTableByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch table"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(table))
+}
+
+// POST /api/tables
+func (h *DiningTableHandler) CreateDiningTable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateDiningTableRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	table, err := h.diningTableService.CreateDiningTable(request)
+	if err != nil {
+		writeDiningTableError(w, err, "Failed to create table")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(table))
+}
+
+// PUT /api/tables/{id}
+func (h *DiningTableHandler) UpdateDiningTable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdateDiningTableRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	table, err := h.diningTableService.UpdateDiningTableByID(r.PathValue("id"), request)
+	if err != nil {
+		writeDiningTableError(w, err, "Failed to update table")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(table))
+}
+
+func writeDiningTableError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrInvalidDiningTable) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+}
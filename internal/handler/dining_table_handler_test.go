@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiningTableHandlerCreateDiningTable(t *testing.T) {
+	mockService := new(mocks.MockDiningTableService)
+	handler := NewDiningTableHandler(mockService)
+
+	valid := model.CreateDiningTableRequest{Area: "Terrace", Name: "T3", Seats: 4}
+	invalid := model.CreateDiningTableRequest{Seats: 4}
+	mockService.On("CreateDiningTable", valid).Return(model.DiningTable{ID: "t1", Name: "T3", IsActive: true}, nil)
+	mockService.On("CreateDiningTable", invalid).Return(model.DiningTable{}, fmt.Errorf("%w: name is required", service.ErrInvalidDiningTable))
+
+	for _, tt := range []struct {
+		request model.CreateDiningTableRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.CreateDiningTable(rec, httptest.NewRequest("POST", "/api/tables", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestDiningTableHandlerFetchDiningTables(t *testing.T) {
+	mockService := new(mocks.MockDiningTableService)
+	handler := NewDiningTableHandler(mockService)
+
+	mockService.On("FetchDiningTables", "o1").Return([]model.DiningTable{{ID: "t1", Name: "7", Occupied: true, DraftOrderID: "d1"}}, nil)
+
+	rec := httptest.NewRecorder()
+	handler.FetchDiningTables(rec, httptest.NewRequest("GET", "/api/tables?outlet_id=o1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"occupied":true`)
+}
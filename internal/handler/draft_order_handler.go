@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type DraftOrderHandler struct {
+	draftOrderService service.DraftOrderService
+}
+
+func NewDraftOrderHandler(draftOrderService service.DraftOrderService) *DraftOrderHandler {
+	return &DraftOrderHandler{
+		draftOrderService: draftOrderService,
+	}
+}
+
+// GET /api/draft-orders?status=<open|held|converted|cancelled, empty for open and held>
+func (h *DraftOrderHandler) FetchDraftOrders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	drafts, err := h.draftOrderService.FetchDraftOrders(r.URL.Query().Get("status"))
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to fetch draft orders")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(drafts))
+}
+
+// GET /api/draft-orders/{id}
+func (h *DraftOrderHandler) FetchDraftOrderByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	draft, err := h.draftOrderService.FetchDraftOrderByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch draft order"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders
+func (h *DraftOrderHandler) CreateDraftOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateDraftOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	draft, err := h.draftOrderService.CreateDraftOrder(request)
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to create draft order")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders/{id}/items
+func (h *DraftOrderHandler) AddDraftOrderItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.AddDraftOrderItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	draft, err := h.draftOrderService.AddDraftOrderItem(r.PathValue("id"), request)
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to add draft order item")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// DELETE /api/draft-orders/{id}/items/{productId}
+func (h *DraftOrderHandler) RemoveDraftOrderItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	draft, err := h.draftOrderService.RemoveDraftOrderItem(r.PathValue("id"), r.PathValue("productId"))
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to remove draft order item")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders/{id}/hold
+func (h *DraftOrderHandler) HoldDraftOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.HoldDraftOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	draft, err := h.draftOrderService.HoldDraftOrder(r.PathValue("id"), request)
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to hold draft order")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders/{id}/resume
+func (h *DraftOrderHandler) ResumeDraftOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	draft, err := h.draftOrderService.ResumeDraftOrder(r.PathValue("id"))
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to resume draft order")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders/{id}/cancel
+func (h *DraftOrderHandler) CancelDraftOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	draft, err := h.draftOrderService.CancelDraftOrder(r.PathValue("id"))
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to cancel draft order")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders/{id}/table
+func (h *DraftOrderHandler) AssignDraftOrderTable(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.AssignDraftOrderTableRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	draft, err := h.draftOrderService.AssignDraftOrderTable(r.PathValue("id"), request)
+	if err != nil {
+		writeDraftOrderError(w, err, "Failed to assign draft order table")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(draft))
+}
+
+// POST /api/draft-orders/{id}/convert
+func (h *DraftOrderHandler) ConvertDraftOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.ConvertDraftOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	tx, err := h.draftOrderService.ConvertDraftOrder(r.PathValue("id"), request)
+	if err != nil {
+		if errors.Is(err, service.ErrDraftOrderStatus) {
+			writeDraftOrderError(w, err, "Failed to convert draft order")
+			return
+		}
+		// the sale was refused, like POST /api/transactions
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(tx))
+}
+
+func writeDraftOrderError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDraftOrder):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrDraftOrderStatus):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftOrderHandlerCreateDraftOrder(t *testing.T) {
+	mockService := new(mocks.MockDraftOrderService)
+	handler := NewDraftOrderHandler(mockService)
+
+	valid := model.CreateDraftOrderRequest{TableNumber: "7", Items: []model.AddDraftOrderItemRequest{{ProductID: "p1", Quantity: 2}}}
+	invalid := model.CreateDraftOrderRequest{Items: []model.AddDraftOrderItemRequest{{ProductID: "p1"}}}
+	mockService.On("CreateDraftOrder", valid).Return(model.DraftOrder{ID: "d1", Status: model.DraftOrderOpen, TableNumber: "7"}, nil)
+	mockService.On("CreateDraftOrder", invalid).Return(model.DraftOrder{}, fmt.Errorf("%w: quantity must be greater than zero", service.ErrInvalidDraftOrder))
+
+	for _, tt := range []struct {
+		request model.CreateDraftOrderRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.CreateDraftOrder(rec, httptest.NewRequest("POST", "/api/draft-orders", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestDraftOrderHandlerRemoveDraftOrderItem(t *testing.T) {
+	mockService := new(mocks.MockDraftOrderService)
+	handler := NewDraftOrderHandler(mockService)
+
+	mockService.On("RemoveDraftOrderItem", "d1", "p1").Return(model.DraftOrder{ID: "d1", Status: model.DraftOrderHeld}, nil)
+	mockService.On("RemoveDraftOrderItem", "d2", "p1").Return(model.DraftOrder{}, fmt.Errorf("%w: draft order is converted", service.ErrDraftOrderStatus))
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{{"d1", http.StatusOK}, {"d2", http.StatusConflict}} {
+		req := httptest.NewRequest("DELETE", "/api/draft-orders/"+tt.id+"/items/p1", nil)
+		req.SetPathValue("id", tt.id)
+		req.SetPathValue("productId", "p1")
+		rec := httptest.NewRecorder()
+		handler.RemoveDraftOrderItem(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestDraftOrderHandlerConvertDraftOrder(t *testing.T) {
+	mockService := new(mocks.MockDraftOrderService)
+	handler := NewDraftOrderHandler(mockService)
+
+	request := model.ConvertDraftOrderRequest{ShiftID: "s1"}
+	mockService.On("ConvertDraftOrder", "d1", request).Return(model.Transaction{ID: "t1", TotalItems: 2}, nil)
+	mockService.On("ConvertDraftOrder", "d2", request).Return(model.Transaction{}, fmt.Errorf("%w: draft order is cancelled", service.ErrDraftOrderStatus))
+	mockService.On("ConvertDraftOrder", "d3", request).Return(model.Transaction{}, errors.New("insufficient stock for product: Brownies"))
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{{"d1", http.StatusCreated}, {"d2", http.StatusConflict}, {"d3", http.StatusBadRequest}} {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/draft-orders/"+tt.id+"/convert", bytes.NewBuffer(body))
+		req.SetPathValue("id", tt.id)
+		rec := httptest.NewRecorder()
+		handler.ConvertDraftOrder(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestDraftOrderHandlerAssignDraftOrderTable(t *testing.T) {
+	mockService := new(mocks.MockDraftOrderService)
+	handler := NewDraftOrderHandler(mockService)
+
+	request := model.AssignDraftOrderTableRequest{TableID: "t1"}
+	mockService.On("AssignDraftOrderTable", "d1", request).Return(model.DraftOrder{ID: "d1", TableID: "t1", TableNumber: "7"}, nil)
+	mockService.On("AssignDraftOrderTable", "d2", request).Return(model.DraftOrder{}, fmt.Errorf("%w: table 7 already has an open order", service.ErrInvalidDraftOrder))
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{{"d1", http.StatusOK}, {"d2", http.StatusBadRequest}} {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/draft-orders/"+tt.id+"/table", bytes.NewBuffer(body))
+		req.SetPathValue("id", tt.id)
+		rec := httptest.NewRecorder()
+		handler.AssignDraftOrderTable(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
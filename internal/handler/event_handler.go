@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"codewithumam-kasir-api/internal/event"
+	"codewithumam-kasir-api/internal/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type EventHandler struct {
+	broker *event.Broker
+}
+
+func NewEventHandler(broker *event.Broker) *EventHandler {
+	return &EventHandler{
+		broker: broker,
+	}
+}
+
+// GET /api/events?type=<event type, empty for all>&subject=<event subject, empty for all>
+// Streams events as Server-Sent Events until the client disconnects
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Streaming is not supported"))
+		return
+	}
+	eventType, subject := r.URL.Query().Get("type"), r.URL.Query().Get("subject")
+
+	events, unsubscribe := h.broker.Subscribe(16)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, open := <-events:
+			if !open {
+				return
+			}
+			if (eventType != "" && e.Type != eventType) || (subject != "" && e.Subject != subject) {
+				continue
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"codewithumam-kasir-api/internal/event"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flushRecorder reports every Flush so the test knows when the stream has subscribed and written
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (r *flushRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	r.flushed <- struct{}{}
+}
+
+func TestEventHandlerStreamEvents(t *testing.T) {
+	broker := event.NewBroker()
+	handler := NewEventHandler(broker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/api/events?type="+event.ProductLowStock, nil).WithContext(ctx)
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+
+	done := make(chan struct{})
+	go func() {
+		handler.StreamEvents(rec, req)
+		close(done)
+	}()
+
+	<-rec.flushed // headers sent, handler is subscribed
+	broker.Publish(event.New("other.event", "ignored"))
+	broker.Publish(event.New(event.ProductLowStock, map[string]string{"name": "Kopi"}))
+	<-rec.flushed // low stock event written
+	cancel()
+	<-done
+
+	body := rec.Body.String()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Contains(t, body, "event: product.low_stock")
+	assert.Contains(t, body, `"name":"Kopi"`)
+	assert.NotContains(t, body, "other.event")
+}
+
+func TestEventHandlerStreamEventsBySubject(t *testing.T) {
+	broker := event.NewBroker()
+	handler := NewEventHandler(broker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/api/events?subject=bar", nil).WithContext(ctx)
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+
+	done := make(chan struct{})
+	go func() {
+		handler.StreamEvents(rec, req)
+		close(done)
+	}()
+
+	<-rec.flushed
+	broker.Publish(event.NewWithSubject(event.KitchenTicketCreated, "kitchen", map[string]string{"station": "Kitchen"}))
+	broker.Publish(event.NewWithSubject(event.KitchenTicketCreated, "bar", map[string]string{"station": "Bar"}))
+	<-rec.flushed
+	cancel()
+	<-done
+
+	body := rec.Body.String()
+	assert.Contains(t, body, `"station":"Bar"`)
+	assert.NotContains(t, body, `"station":"Kitchen"`)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type GiftCardHandler struct {
+	giftCardService service.GiftCardService
+}
+
+func NewGiftCardHandler(giftCardService service.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{
+		giftCardService: giftCardService,
+	}
+}
+
+// GET /api/gift-cards
+func (h *GiftCardHandler) FetchGiftCards(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	cards, err := h.giftCardService.FetchGiftCards()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch gift cards"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(cards))
+}
+
+// GET /api/gift-cards/{code}
+func (h *GiftCardHandler) FetchGiftCardBalance(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	balance, err := h.giftCardService.FetchGiftCardBalance(r.PathValue("code"))
+	if err != nil {
+		writeGiftCardError(w, err, "Failed to fetch gift card")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(balance))
+}
+
+// POST /api/gift-cards
+func (h *GiftCardHandler) IssueGiftCard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateGiftCardRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	card, err := h.giftCardService.IssueGiftCard(request)
+	if err != nil {
+		writeGiftCardError(w, err, "Failed to issue gift card")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(card))
+}
+
+// POST /api/gift-cards/{code}/top-ups
+func (h *GiftCardHandler) TopUpGiftCard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.TopUpGiftCardRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	entry, err := h.giftCardService.TopUpGiftCard(r.PathValue("code"), request)
+	if err != nil {
+		writeGiftCardError(w, err, "Failed to top up gift card")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(entry))
+}
+
+func writeGiftCardError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrGiftCardNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusNotFound, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonNotFound),
+		}))
+	case errors.Is(err, service.ErrInvalidGiftCard):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
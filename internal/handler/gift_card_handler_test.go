@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGiftCardHandlerIssueGiftCard(t *testing.T) {
+	mockService := new(mocks.MockGiftCardService)
+	handler := NewGiftCardHandler(mockService)
+
+	valid := model.CreateGiftCardRequest{Amount: 50000}
+	invalid := model.CreateGiftCardRequest{}
+	mockService.On("IssueGiftCard", valid).Return(model.GiftCard{ID: "g1", Code: "ABCD1234"}, nil)
+	mockService.On("IssueGiftCard", invalid).Return(model.GiftCard{}, fmt.Errorf("%w: amount must be greater than zero", service.ErrInvalidGiftCard))
+
+	for _, tt := range []struct {
+		request model.CreateGiftCardRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.IssueGiftCard(rec, httptest.NewRequest("POST", "/api/gift-cards", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestGiftCardHandlerFetchGiftCardBalance(t *testing.T) {
+	mockService := new(mocks.MockGiftCardService)
+	handler := NewGiftCardHandler(mockService)
+
+	mockService.On("FetchGiftCardBalance", "ABCD1234").Return(model.GiftCardBalance{
+		GiftCard: model.GiftCard{ID: "g1", Code: "ABCD1234", Balance: model.Price{Amount: 20000, Currency: "IDR"}},
+		Entries:  []model.GiftCardEntry{},
+	}, nil)
+	mockService.On("FetchGiftCardBalance", "NOPE").Return(model.GiftCardBalance{}, service.ErrGiftCardNotFound)
+
+	for _, tt := range []struct {
+		code   string
+		status int
+	}{{"ABCD1234", http.StatusOK}, {"NOPE", http.StatusNotFound}} {
+		req := httptest.NewRequest("GET", "/api/gift-cards/"+tt.code, nil)
+		req.SetPathValue("code", tt.code)
+		rec := httptest.NewRecorder()
+		handler.FetchGiftCardBalance(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestGiftCardHandlerTopUpGiftCard(t *testing.T) {
+	mockService := new(mocks.MockGiftCardService)
+	handler := NewGiftCardHandler(mockService)
+
+	request := model.TopUpGiftCardRequest{Amount: 10000}
+	mockService.On("TopUpGiftCard", "ABCD1234", request).Return(model.GiftCardEntry{
+		ID: "e1", Type: model.GiftCardEntryTopUp, BalanceAfter: model.Price{Amount: 30000, Currency: "IDR"},
+	}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/api/gift-cards/ABCD1234/top-ups", bytes.NewBuffer(body))
+	req.SetPathValue("code", "ABCD1234")
+	rec := httptest.NewRecorder()
+	handler.TopUpGiftCard(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"amount":30000`)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type KitchenHandler struct {
+	kitchenService service.KitchenService
+}
+
+func NewKitchenHandler(kitchenService service.KitchenService) *KitchenHandler {
+	return &KitchenHandler{
+		kitchenService: kitchenService,
+	}
+}
+
+// GET /api/kitchen-stations
+func (h *KitchenHandler) FetchKitchenStations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	stations, err := h.kitchenService.FetchKitchenStations()
+	if err != nil {
+		writeKitchenError(w, err, "Failed to fetch kitchen stations")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(stations))
+}
+
+// POST /api/kitchen-stations
+func (h *KitchenHandler) CreateKitchenStation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateKitchenStationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	station, err := h.kitchenService.CreateKitchenStation(request)
+	if err != nil {
+		writeKitchenError(w, err, "Failed to create kitchen station")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(station))
+}
+
+// PUT /api/kitchen-stations/{id}
+func (h *KitchenHandler) UpdateKitchenStation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdateKitchenStationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	station, err := h.kitchenService.UpdateKitchenStationByID(r.PathValue("id"), request)
+	if err != nil {
+		writeKitchenError(w, err, "Failed to update kitchen station")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(station))
+}
+
+// GET /api/kitchen-tickets?station_id=<station id, empty for every station>&status=<queued|preparing|ready|served, empty for not served>
+func (h *KitchenHandler) FetchKitchenTickets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	query := r.URL.Query()
+	tickets, err := h.kitchenService.FetchKitchenTickets(query.Get("station_id"), query.Get("status"))
+	if err != nil {
+		writeKitchenError(w, err, "Failed to fetch kitchen tickets")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(tickets))
+}
+
+// GET /api/kitchen-tickets/{id}
+func (h *KitchenHandler) FetchKitchenTicketByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	ticket, err := h.kitchenService.FetchKitchenTicketByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch kitchen ticket"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(ticket))
+}
+
+// POST /api/kitchen-tickets/{id}/status
+func (h *KitchenHandler) UpdateKitchenTicketStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdateKitchenTicketStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	ticket, err := h.kitchenService.UpdateKitchenTicketStatus(r.PathValue("id"), request)
+	if err != nil {
+		writeKitchenError(w, err, "Failed to update kitchen ticket")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(ticket))
+}
+
+// POST /api/draft-orders/{id}/fire
+func (h *KitchenHandler) FireDraftOrder(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	tickets, err := h.kitchenService.FireDraftOrder(r.PathValue("id"))
+	if err != nil {
+		writeKitchenError(w, err, "Failed to send draft order to the kitchen")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(tickets))
+}
+
+func writeKitchenError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidKitchen):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrKitchenTicketStatus), errors.Is(err, service.ErrDraftOrderStatus):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
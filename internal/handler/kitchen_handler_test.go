@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKitchenHandlerFireDraftOrder(t *testing.T) {
+	mockService := new(mocks.MockKitchenService)
+	handler := NewKitchenHandler(mockService)
+
+	mockService.On("FireDraftOrder", "d1").Return([]model.KitchenTicket{{ID: "k1", Station: "Bar", Status: model.KitchenTicketQueued}}, nil)
+	mockService.On("FireDraftOrder", "d2").Return([]model.KitchenTicket(nil), fmt.Errorf("%w: every item of the draft order was already sent", service.ErrInvalidKitchen))
+	mockService.On("FireDraftOrder", "d3").Return([]model.KitchenTicket(nil), fmt.Errorf("%w: draft order is converted", service.ErrDraftOrderStatus))
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{{"d1", http.StatusCreated}, {"d2", http.StatusBadRequest}, {"d3", http.StatusConflict}} {
+		req := httptest.NewRequest("POST", "/api/draft-orders/"+tt.id+"/fire", nil)
+		req.SetPathValue("id", tt.id)
+		rec := httptest.NewRecorder()
+		handler.FireDraftOrder(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestKitchenHandlerUpdateKitchenTicketStatus(t *testing.T) {
+	mockService := new(mocks.MockKitchenService)
+	handler := NewKitchenHandler(mockService)
+
+	request := model.UpdateKitchenTicketStatusRequest{Status: model.KitchenTicketReady}
+	mockService.On("UpdateKitchenTicketStatus", "k1", request).Return(model.KitchenTicket{ID: "k1", Status: model.KitchenTicketReady}, nil)
+	mockService.On("UpdateKitchenTicketStatus", "k2", request).Return(model.KitchenTicket{}, fmt.Errorf("%w: ticket is already served", service.ErrKitchenTicketStatus))
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{{"k1", http.StatusOK}, {"k2", http.StatusConflict}} {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/kitchen-tickets/"+tt.id+"/status", bytes.NewBuffer(body))
+		req.SetPathValue("id", tt.id)
+		rec := httptest.NewRecorder()
+		handler.UpdateKitchenTicketStatus(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestKitchenHandlerFetchKitchenTickets(t *testing.T) {
+	mockService := new(mocks.MockKitchenService)
+	handler := NewKitchenHandler(mockService)
+
+	mockService.On("FetchKitchenTickets", "s1", "").Return([]model.KitchenTicket{{ID: "k1", StationID: "s1"}}, nil)
+
+	rec := httptest.NewRecorder()
+	handler.FetchKitchenTickets(rec, httptest.NewRequest("GET", "/api/kitchen-tickets?station_id=s1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
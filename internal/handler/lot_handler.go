@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type LotHandler struct {
+	lotService service.LotService
+}
+
+func NewLotHandler(lotService service.LotService) *LotHandler {
+	return &LotHandler{
+		lotService: lotService,
+	}
+}
+
+// GET /api/products/{id}/lots
+func (h *LotHandler) FetchLots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	lots, err := h.lotService.FetchLots(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch lots"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(lots))
+}
+
+// POST /api/products/{id}/lots
+func (h *LotHandler) ReceiveLot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateProductLotRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	lot, err := h.lotService.ReceiveLot(r.PathValue("id"), request)
+	if err != nil {
+		writeLotError(w, err, "Failed to receive lot")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(lot))
+}
+
+// GET /api/inventory/expiring?within=7d
+func (h *LotHandler) FetchExpiringLots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	lots, err := h.lotService.FetchExpiringLots(r.URL.Query().Get("within"))
+	if err != nil {
+		writeLotError(w, err, "Failed to fetch expiring lots")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(lots))
+}
+
+func writeLotError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrInvalidLot) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+}
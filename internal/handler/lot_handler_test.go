@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLotHandlerFetchLots(t *testing.T) {
+	mockService := new(mocks.MockLotService)
+	handler := NewLotHandler(mockService)
+
+	mockService.On("FetchLots", "abc").Return([]model.ProductLot{{ID: "1", LotNumber: "A1"}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/products/abc/lots", nil)
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+	handler.FetchLots(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"lot_number":"A1"`)
+}
+
+func TestLotHandlerReceiveLot(t *testing.T) {
+	mockService := new(mocks.MockLotService)
+	handler := NewLotHandler(mockService)
+
+	mockService.On("ReceiveLot", "abc", mock.MatchedBy(func(r model.CreateProductLotRequest) bool {
+		return r.LotNumber == "A1" && r.ExpiryDate.Format("2006-01-02") == "2026-12-01" && r.Quantity == 5
+	})).Return(model.ProductLot{ID: "1", LotNumber: "A1"}, nil)
+
+	body := `{"lot_number":"A1","expiry_date":"2026-12-01","quantity":5}`
+	req := httptest.NewRequest("POST", "/api/products/abc/lots", bytes.NewBufferString(body))
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+	handler.ReceiveLot(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestLotHandlerReceiveLotErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+	}{
+		{"malformed date", `{"lot_number":"A1","expiry_date":"01-12-2026"}`, nil, http.StatusBadRequest},
+		{"invalid lot", `{"lot_number":""}`, fmt.Errorf("%w: lot_number is required", service.ErrInvalidLot), http.StatusBadRequest},
+		{"failure", `{"lot_number":"A1"}`, errors.New("database error"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockLotService)
+			handler := NewLotHandler(mockService)
+			if tt.err != nil {
+				mockService.On("ReceiveLot", "abc", mock.Anything).Return(model.ProductLot{}, tt.err)
+			}
+
+			req := httptest.NewRequest("POST", "/api/products/abc/lots", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "abc")
+			rec := httptest.NewRecorder()
+			handler.ReceiveLot(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestLotHandlerFetchExpiringLots(t *testing.T) {
+	mockService := new(mocks.MockLotService)
+	handler := NewLotHandler(mockService)
+
+	mockService.On("FetchExpiringLots", "7d").Return([]model.ProductLot{{ID: "1", Expired: true}}, nil)
+	mockService.On("FetchExpiringLots", "soon").Return(nil, fmt.Errorf("%w: within must be a number of days like 7d", service.ErrInvalidLot))
+
+	rec := httptest.NewRecorder()
+	handler.FetchExpiringLots(rec, httptest.NewRequest("GET", "/api/inventory/expiring?within=7d", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"expired":true`)
+
+	rec = httptest.NewRecorder()
+	handler.FetchExpiringLots(rec, httptest.NewRequest("GET", "/api/inventory/expiring?within=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
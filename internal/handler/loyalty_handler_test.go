@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoyaltyHandlerUpdateLoyaltyProgram(t *testing.T) {
+	mockService := new(mocks.MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	valid := model.UpdateLoyaltyProgramRequest{IsActive: true, SpendPerPoint: 1000, PointValue: 10}
+	invalid := model.UpdateLoyaltyProgramRequest{IsActive: true, PointValue: 10}
+	mockService.On("UpdateLoyaltyProgram", valid).Return(model.LoyaltyProgram{IsActive: true, SpendPerPoint: 1000, PointValue: 10}, nil)
+	mockService.On("UpdateLoyaltyProgram", invalid).Return(model.LoyaltyProgram{}, fmt.Errorf("%w: spend per point must be greater than zero", service.ErrInvalidLoyalty))
+
+	for _, tt := range []struct {
+		request model.UpdateLoyaltyProgramRequest
+		status  int
+	}{{valid, http.StatusOK}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.UpdateLoyaltyProgram(rec, httptest.NewRequest("PUT", "/api/loyalty/program", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestLoyaltyHandlerFetchCustomerPoints(t *testing.T) {
+	mockService := new(mocks.MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	mockService.On("FetchCustomerPoints", "c1").Return(model.CustomerPoints{
+		CustomerID: "c1",
+		Balance:    25,
+		Expiring:   []model.ExpiringPoints{},
+		Entries:    []model.LoyaltyEntry{{ID: "e1", Type: model.LoyaltyEntryEarn, Points: 25, BalanceAfter: 25}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/customers/c1/points", nil)
+	req.SetPathValue("id", "c1")
+	rec := httptest.NewRecorder()
+	handler.FetchCustomerPoints(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"balance":25`)
+	mockService.AssertExpectations(t)
+}
+
+func TestLoyaltyHandlerReverseLoyaltyEntry(t *testing.T) {
+	mockService := new(mocks.MockLoyaltyService)
+	handler := NewLoyaltyHandler(mockService)
+
+	valid := model.CreateLoyaltyReversalRequest{EntryID: "e1", Notes: "wrong customer"}
+	reversed := model.CreateLoyaltyReversalRequest{EntryID: "e2"}
+	mockService.On("ReverseLoyaltyEntry", "c1", valid).Return(model.LoyaltyEntry{ID: "e3", Type: model.LoyaltyEntryReversal, Points: -25, ReferenceEntryID: "e1"}, nil)
+	mockService.On("ReverseLoyaltyEntry", "c1", reversed).Return(model.LoyaltyEntry{}, fmt.Errorf("%w: entry is already reversed", service.ErrInvalidLoyalty))
+
+	for _, tt := range []struct {
+		request model.CreateLoyaltyReversalRequest
+		status  int
+	}{{valid, http.StatusCreated}, {reversed, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		req := httptest.NewRequest("POST", "/api/customers/c1/points/reversals", bytes.NewBuffer(body))
+		req.SetPathValue("id", "c1")
+		rec := httptest.NewRecorder()
+		handler.ReverseLoyaltyEntry(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type OutletHandler struct {
+	outletService service.OutletService
+}
+
+func NewOutletHandler(outletService service.OutletService) *OutletHandler {
+	return &OutletHandler{
+		outletService: outletService,
+	}
+}
+
+// GET /api/outlets
+func (h *OutletHandler) FetchOutlets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	outlets, err := h.outletService.FetchOutlets()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch outlets"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(outlets))
+}
+
+// GET /api/outlets/{id}
+func (h *OutletHandler) FetchOutletByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	outlet, err := h.outletService.FetchOutletByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch outlet"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(outlet))
+}
+
+// POST /api/outlets
+func (h *OutletHandler) CreateOutlet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateOutletRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	outlet, err := h.outletService.CreateOutlet(request)
+	if err != nil {
+		writeOutletError(w, err, "Failed to create outlet")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(outlet))
+}
+
+// PUT /api/outlets/{id}
+func (h *OutletHandler) UpdateOutlet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdateOutletRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	outlet, err := h.outletService.UpdateOutletByID(r.PathValue("id"), request)
+	if err != nil {
+		writeOutletError(w, err, "Failed to update outlet")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(outlet))
+}
+
+// DELETE /api/outlets/{id}
+func (h *OutletHandler) DeleteOutlet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.outletService.DeleteOutletByID(r.PathValue("id")); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to delete outlet"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET /api/outlets/{id}/stocks
+func (h *OutletHandler) FetchOutletStocks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	stocks, err := h.outletService.FetchOutletStocks(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch outlet stocks"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(stocks))
+}
+
+// PUT /api/outlets/{id}/stocks/{productId}/price
+func (h *OutletHandler) UpdateOutletPrice(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdateOutletPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	stock, err := h.outletService.UpdateOutletPrice(r.PathValue("id"), r.PathValue("productId"), request)
+	if err != nil {
+		writeOutletError(w, err, "Failed to update outlet price")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(stock))
+}
+
+func writeOutletError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrInvalidOutlet) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutletHandlerCreateOutlet(t *testing.T) {
+	mockService := new(mocks.MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	valid := model.CreateOutletRequest{Code: "JKT-01", Name: "Jakarta"}
+	invalid := model.CreateOutletRequest{Name: "Jakarta"}
+	mockService.On("CreateOutlet", valid).Return(model.Outlet{ID: "1", Code: "JKT-01"}, nil)
+	mockService.On("CreateOutlet", invalid).Return(model.Outlet{}, fmt.Errorf("%w: code is required", service.ErrInvalidOutlet))
+
+	for _, tt := range []struct {
+		request model.CreateOutletRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.CreateOutlet(rec, httptest.NewRequest("POST", "/api/outlets", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestOutletHandlerFetchOutletStocks(t *testing.T) {
+	mockService := new(mocks.MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	mockService.On("FetchOutletStocks", "o1").Return([]model.OutletStock{{OutletID: "o1", ProductID: "p1", Stock: 4, Price: 5000}}, nil)
+
+	req := httptest.NewRequest("GET", "/api/outlets/o1/stocks", nil)
+	req.SetPathValue("id", "o1")
+	rec := httptest.NewRecorder()
+	handler.FetchOutletStocks(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"stock":4`)
+}
+
+func TestOutletHandlerUpdateOutletPrice(t *testing.T) {
+	mockService := new(mocks.MockOutletService)
+	handler := NewOutletHandler(mockService)
+
+	price := int64(12000)
+	reqBody := model.UpdateOutletPriceRequest{PriceOverride: &price}
+	mockService.On("UpdateOutletPrice", "o1", "p1", reqBody).Return(model.OutletStock{OutletID: "o1", ProductID: "p1", Price: 12000, PriceOverride: &price}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("PUT", "/api/outlets/o1/stocks/p1/price", bytes.NewBuffer(body))
+	req.SetPathValue("id", "o1")
+	req.SetPathValue("productId", "p1")
+	rec := httptest.NewRecorder()
+	handler.UpdateOutletPrice(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type PriceListHandler struct {
+	priceListService service.PriceListService
+}
+
+func NewPriceListHandler(priceListService service.PriceListService) *PriceListHandler {
+	return &PriceListHandler{
+		priceListService: priceListService,
+	}
+}
+
+// GET /api/price-lists
+func (h *PriceListHandler) FetchPriceLists(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	priceLists, err := h.priceListService.FetchPriceLists()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch price lists"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(priceLists))
+}
+
+// GET /api/price-lists/{id}
+func (h *PriceListHandler) FetchPriceListByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	priceList, err := h.priceListService.FetchPriceListByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch price list"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(priceList))
+}
+
+// POST /api/price-lists
+func (h *PriceListHandler) CreatePriceList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	priceList, err := h.priceListService.CreatePriceList(request)
+	if err != nil {
+		writePriceListError(w, err, "Failed to create price list")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(priceList))
+}
+
+// PUT /api/price-lists/{id}
+func (h *PriceListHandler) UpdatePriceList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.UpdatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	priceList, err := h.priceListService.UpdatePriceListByID(r.PathValue("id"), request)
+	if err != nil {
+		writePriceListError(w, err, "Failed to update price list")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(priceList))
+}
+
+// DELETE /api/price-lists/{id}
+func (h *PriceListHandler) DeletePriceList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.priceListService.DeletePriceListByID(r.PathValue("id")); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to delete price list"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET /api/products/{id}/prices
+func (h *PriceListHandler) FetchProductPrices(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	prices, err := h.priceListService.FetchProductPrices(r.PathValue("id"))
+	if err != nil {
+		writePriceListError(w, err, "Failed to fetch product prices")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(prices))
+}
+
+// POST /api/products/{id}/prices
+func (h *PriceListHandler) SchedulePrice(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.SchedulePriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	price, err := h.priceListService.SchedulePrice(r.PathValue("id"), request)
+	if err != nil {
+		writePriceListError(w, err, "Failed to schedule price")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(price))
+}
+
+// DELETE /api/products/{id}/prices/{priceId}
+func (h *PriceListHandler) CancelPrice(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.priceListService.CancelPrice(r.PathValue("id"), r.PathValue("priceId")); err != nil {
+		writePriceListError(w, err, "Failed to cancel price")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET /api/products/{id}/price-tiers
+func (h *PriceListHandler) FetchPriceTiers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	tiers, err := h.priceListService.FetchPriceTiers(r.PathValue("id"))
+	if err != nil {
+		writePriceListError(w, err, "Failed to fetch price tiers")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(tiers))
+}
+
+// PUT /api/products/{id}/price-tiers
+func (h *PriceListHandler) ReplacePriceTiers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.ReplacePriceTiersRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	tiers, err := h.priceListService.ReplacePriceTiers(r.PathValue("id"), request)
+	if err != nil {
+		writePriceListError(w, err, "Failed to update price tiers")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(tiers))
+}
+
+func writePriceListError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPriceList):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrProductPriceStarted):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
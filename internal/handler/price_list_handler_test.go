@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceListHandlerCreatePriceList(t *testing.T) {
+	mockService := new(mocks.MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+
+	valid := model.CreatePriceListRequest{Code: "GROSIR", Name: "Wholesale"}
+	invalid := model.CreatePriceListRequest{Name: "Wholesale"}
+	mockService.On("CreatePriceList", valid).Return(model.PriceList{ID: "1", Code: "GROSIR"}, nil)
+	mockService.On("CreatePriceList", invalid).Return(model.PriceList{}, fmt.Errorf("%w: code is required", service.ErrInvalidPriceList))
+
+	for _, tt := range []struct {
+		request model.CreatePriceListRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.CreatePriceList(rec, httptest.NewRequest("POST", "/api/price-lists", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestPriceListHandlerSchedulePrice(t *testing.T) {
+	mockService := new(mocks.MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+
+	request := model.SchedulePriceRequest{Price: 15000}
+	mockService.On("SchedulePrice", "p1", request).Return(model.ProductPrice{ID: "pp1", ProductID: "p1", Price: 15000}, nil)
+
+	body, _ := json.Marshal(request)
+	req := httptest.NewRequest("POST", "/api/products/p1/prices", bytes.NewBuffer(body))
+	req.SetPathValue("id", "p1")
+	rec := httptest.NewRecorder()
+	handler.SchedulePrice(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"price":15000`)
+}
+
+func TestPriceListHandlerCancelPrice(t *testing.T) {
+	mockService := new(mocks.MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+
+	mockService.On("CancelPrice", "p1", "upcoming").Return(nil)
+	mockService.On("CancelPrice", "p1", "started").Return(fmt.Errorf("%w: it started already", service.ErrProductPriceStarted))
+	mockService.On("CancelPrice", "p1", "missing").Return(fmt.Errorf("%w: scheduled price not found", service.ErrInvalidPriceList))
+
+	for _, tt := range []struct {
+		priceID string
+		status  int
+	}{{"upcoming", http.StatusOK}, {"started", http.StatusConflict}, {"missing", http.StatusBadRequest}} {
+		req := httptest.NewRequest("DELETE", "/api/products/p1/prices/"+tt.priceID, nil)
+		req.SetPathValue("id", "p1")
+		req.SetPathValue("priceId", tt.priceID)
+		rec := httptest.NewRecorder()
+		handler.CancelPrice(rec, req)
+		assert.Equal(t, tt.status, rec.Code, tt.priceID)
+	}
+}
+
+func TestPriceListHandlerReplacePriceTiers(t *testing.T) {
+	mockService := new(mocks.MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+
+	valid := model.ReplacePriceTiersRequest{Tiers: []model.PriceTierRequest{{MinQuantity: 12, Price: 3000}}}
+	invalid := model.ReplacePriceTiersRequest{Tiers: []model.PriceTierRequest{{MinQuantity: 0, Price: 3000}}}
+	mockService.On("ReplacePriceTiers", "p1", valid).Return([]model.ProductPriceTier{{ID: "t1", ProductID: "p1", MinQuantity: 12, Price: 3000}}, nil)
+	mockService.On("ReplacePriceTiers", "p1", invalid).Return(nil, fmt.Errorf("%w: tier min_quantity must be at least 1", service.ErrInvalidPriceList))
+
+	for _, tt := range []struct {
+		request model.ReplacePriceTiersRequest
+		status  int
+	}{{valid, http.StatusOK}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		req := httptest.NewRequest("PUT", "/api/products/p1/price-tiers", bytes.NewBuffer(body))
+		req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		handler.ReplacePriceTiers(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -18,6 +19,7 @@ func NewProductHandler(productService service.ProductService) *ProductHandler {
 }
 
 // GET /api/products?name=<name>&active=<activeStatus can be nil 0 1)
+// GET /api/products?categoryId=<id> lists the category's products with those of its subcategories
 func (h *ProductHandler) FetchProducts(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
 	activeParam := r.URL.Query().Get("active")
@@ -41,7 +43,21 @@ func (h *ProductHandler) FetchProducts(w http.ResponseWriter, r *http.Request) {
 	var products []model.Product
 	var err error
 
-	if name != "" || activeStatus != nil {
+	if categoryID := r.URL.Query().Get("categoryId"); categoryID != "" {
+		if name != "" || activeStatus != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "categoryId cannot be combined with name or active"))
+			return
+		}
+		products, err = h.productService.FetchProductsByCategory(categoryID)
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusNotFound, []model.ErrorItem{
+				model.NewErrorItem(err.Error()).WithReason(model.ReasonNotFound),
+			}))
+			return
+		}
+	} else if name != "" || activeStatus != nil {
 		products, err = h.productService.FetchProductsByNameAndActiveStatus(name, activeStatus)
 	} else {
 		products, err = h.productService.FetchProducts()
@@ -56,6 +72,30 @@ func (h *ProductHandler) FetchProducts(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(products))
 }
 
+// GET /api/categories/{id}/products lists the category's products with those of its subcategories
+func (h *ProductHandler) FetchCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	products, err := h.productService.FetchProductsByCategory(r.PathValue("id"))
+	if err != nil {
+		writeCategoryError(w, err, "Failed to fetch category products")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(products))
+}
+
+// GET /api/products/low-stock
+func (h *ProductHandler) FetchLowStockProducts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := h.productService.FetchLowStockProducts()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch low stock products"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(alerts))
+}
+
 // TODO: handle properly if invalid request with correct HTTPStatus
 // GET /api/products/{id}
 func (h *ProductHandler) FetchProductByID(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +119,17 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	product, err := h.productService.CreateProduct(request)
+	if errors.Is(err, service.ErrInvalidProduct) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	if errors.Is(err, service.ErrCategoryNotFound) {
+		writeUnknownCategory(w, err)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to create product"))
@@ -98,6 +149,17 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	product, err := h.productService.UpdateProductByID(r.PathValue("id"), request)
+	if errors.Is(err, service.ErrInvalidProduct) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	if errors.Is(err, service.ErrCategoryNotFound) {
+		writeUnknownCategory(w, err)
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to update product"))
@@ -118,3 +180,75 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+// POST /api/products:batch
+// Every operation has its result in the body. An atomic batch that failed answers 200 too, with committed false.
+func (h *ProductHandler) BatchProducts(w http.ResponseWriter, r *http.Request) {
+	var request model.ProductBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+	response, err := h.productService.BatchProducts(request)
+	if errors.Is(err, service.ErrInvalidProduct) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to apply product batch"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(response))
+}
+
+// GET /api/products/{id}/units
+func (h *ProductHandler) FetchProductUnits(w http.ResponseWriter, r *http.Request) {
+	units, err := h.productService.FetchProductUnits(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch product units"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(units))
+}
+
+// PUT /api/products/{id}/units
+func (h *ProductHandler) ReplaceProductUnits(w http.ResponseWriter, r *http.Request) {
+	var request model.ReplaceProductUnitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+	units, err := h.productService.ReplaceProductUnits(r.PathValue("id"), request)
+	if errors.Is(err, service.ErrInvalidProduct) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to update product units"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(units))
+}
+
+// writeUnknownCategory answers a product assigned to a category that does not exist,
+// the request is well formed but names something missing
+func writeUnknownCategory(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusUnprocessableEntity, []model.ErrorItem{
+		model.NewErrorItem(err.Error()).WithReason(model.ReasonNotFound),
+	}))
+}
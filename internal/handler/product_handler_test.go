@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	mocks "codewithumam-kasir-api/internal/mock"
 	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -213,3 +215,148 @@ func TestProductHandlerDeleteProductError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestProductHandlerCreateProductInvalidBundle(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	reqBody := model.CreateProductRequest{Name: "Paket Hemat", Type: model.ProductTypeBundle}
+	mockService.On("CreateProduct", reqBody).
+		Return(model.Product{}, fmt.Errorf("%w: bundle must have at least one component", service.ErrInvalidProduct))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/products", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	handler.CreateProduct(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var response model.APIResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, model.ReasonInvalidValue, response.Error.Errors[0].Reason)
+}
+
+func TestProductHandlerCreateProductUnknownCategory(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	reqBody := model.CreateProductRequest{Name: "Kopi", Price: 18000, Category: "Drinkz"}
+	mockService.On("CreateProduct", reqBody).
+		Return(model.Product{}, fmt.Errorf("%w: no category is named Drinkz", service.ErrCategoryNotFound))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/api/products", bytes.NewBuffer(body))
+	rec := httptest.NewRecorder()
+
+	handler.CreateProduct(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var response model.APIResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	require.NotNil(t, response.Error)
+	assert.Equal(t, model.ReasonNotFound, response.Error.Errors[0].Reason)
+}
+
+func TestProductHandlerFetchLowStockProducts(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("FetchLowStockProducts").Return([]model.LowStockAlert{
+		{ProductID: "1", Name: "Kopi", Stocks: 2, ReorderPoint: 5, ReorderQuantity: 24},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/products/low-stock", nil)
+	rec := httptest.NewRecorder()
+
+	handler.FetchLowStockProducts(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reorder_quantity":24`)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductHandlerBatchProducts(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	request := model.ProductBatchRequest{Mode: model.ProductBatchAtomic, Operations: []model.ProductBatchOperation{{Op: model.ProductBatchDelete, ID: "p1"}}}
+	mockService.On("BatchProducts", request).Return(model.ProductBatchResponse{
+		Mode: model.ProductBatchAtomic, Committed: true, Succeeded: 1,
+		Results: []model.ProductBatchResult{{Index: 0, Op: model.ProductBatchDelete, ID: "p1", Status: model.ProductBatchSucceeded}},
+	}, nil)
+	mockService.On("BatchProducts", model.ProductBatchRequest{}).Return(model.ProductBatchResponse{}, fmt.Errorf("%w: operations are required", service.ErrInvalidProduct))
+
+	body, _ := json.Marshal(request)
+	rec := httptest.NewRecorder()
+	handler.BatchProducts(rec, httptest.NewRequest("POST", "/api/products:batch", bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"succeeded"`)
+
+	rec = httptest.NewRecorder()
+	handler.BatchProducts(rec, httptest.NewRequest("POST", "/api/products:batch", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.BatchProducts(rec, httptest.NewRequest("POST", "/api/products:batch", bytes.NewBufferString(`[`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProductHandlerReplaceProductUnits(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	valid := model.ReplaceProductUnitsRequest{Units: []model.ProductUnitRequest{{Name: "carton", Factor: 24}}}
+	invalid := model.ReplaceProductUnitsRequest{Units: []model.ProductUnitRequest{{Name: "half", Factor: 0.5}}}
+	mockService.On("ReplaceProductUnits", "p1", valid).Return([]model.ProductUnit{{ID: "u1", ProductID: "p1", Name: "carton", Factor: 24}}, nil)
+	mockService.On("ReplaceProductUnits", "p1", invalid).Return(nil, fmt.Errorf("%w: unit half must hold a positive quantity", service.ErrInvalidProduct))
+
+	for _, tt := range []struct {
+		request model.ReplaceProductUnitsRequest
+		status  int
+	}{{valid, http.StatusOK}, {invalid, http.StatusBadRequest}} {
+		body, _ := json.Marshal(tt.request)
+		req := httptest.NewRequest("PUT", "/api/products/p1/units", bytes.NewBuffer(body))
+		req.SetPathValue("id", "p1")
+		rec := httptest.NewRecorder()
+		handler.ReplaceProductUnits(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestProductHandlerFetchProducts_ByCategory(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("FetchProductsByCategory", "bev").Return([]model.Product{{ID: "1", Name: "Espresso", Category: "Coffee"}}, nil)
+	mockService.On("FetchProductsByCategory", "nope").Return(nil, fmt.Errorf("%w: nope", service.ErrCategoryNotFound))
+
+	rec := httptest.NewRecorder()
+	handler.FetchProducts(rec, httptest.NewRequest("GET", "/api/products?categoryId=bev", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Espresso")
+
+	rec = httptest.NewRecorder()
+	handler.FetchProducts(rec, httptest.NewRequest("GET", "/api/products?categoryId=nope", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.FetchProducts(rec, httptest.NewRequest("GET", "/api/products?categoryId=bev&name=kopi", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProductHandlerFetchCategoryProducts(t *testing.T) {
+	mockService := new(mocks.MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("FetchProductsByCategory", "bev").Return([]model.Product{{ID: "1", Name: "Espresso"}}, nil)
+	mockService.On("FetchProductsByCategory", "nope").Return(nil, fmt.Errorf("%w: nope", service.ErrCategoryNotFound))
+
+	for id, status := range map[string]int{"bev": http.StatusOK, "nope": http.StatusNotFound} {
+		req := httptest.NewRequest("GET", "/api/categories/"+id+"/products", nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		handler.FetchCategoryProducts(rec, req)
+		assert.Equal(t, status, rec.Code, id)
+	}
+}
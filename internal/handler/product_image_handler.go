@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+// maxImageUploadSize caps the multipart body, the image plus room for the form around it
+const maxImageUploadSize = model.MaxProductImageSize + 1<<20
+
+type ProductImageHandler struct {
+	productImageService service.ProductImageService
+}
+
+func NewProductImageHandler(productImageService service.ProductImageService) *ProductImageHandler {
+	return &ProductImageHandler{
+		productImageService: productImageService,
+	}
+}
+
+// POST /api/products/{id}/images
+// The image is the "file" field of a multipart form, JPEG, PNG or GIF up to 10 MB.
+func (h *ProductImageHandler) UploadProductImage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageUploadSize)
+	file, _, err := r.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusRequestEntityTooLarge, "The image is over 10 MB"))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "The file field of a multipart form is required"))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	image, err := h.productImageService.UploadProductImage(r.PathValue("id"), data)
+	if err != nil {
+		writeProductImageError(w, err, "Failed to upload product image")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(image))
+}
+
+// DELETE /api/products/{id}/images/{imageId}
+func (h *ProductImageHandler) DeleteProductImage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := h.productImageService.DeleteProductImage(r.PathValue("id"), r.PathValue("imageId")); err != nil {
+		writeProductImageError(w, err, "Failed to delete product image")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeProductImageError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProduct), errors.Is(err, service.ErrInvalidProductImage):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrProductImageNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusNotFound, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonNotFound),
+		}))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func imageUploadRequest(t *testing.T, field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "kopi.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/api/products/abc/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.SetPathValue("id", "abc")
+	return req
+}
+
+func TestProductImageHandlerUploadProductImage(t *testing.T) {
+	mockService := new(mocks.MockProductImageService)
+	handler := NewProductImageHandler(mockService)
+
+	mockService.On("UploadProductImage", "abc", []byte("png")).
+		Return(model.ProductImage{ID: "img", URL: "/media/products/a/b.png", ThumbnailURL: "/media/products/a/b_thumb.jpg"}, nil)
+	mockService.On("UploadProductImage", "abc", []byte("svg")).
+		Return(model.ProductImage{}, fmt.Errorf("%w: only JPEG, PNG and GIF images are accepted", service.ErrInvalidProductImage))
+
+	rec := httptest.NewRecorder()
+	handler.UploadProductImage(rec, imageUploadRequest(t, "file", []byte("png")))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response struct {
+		Data model.ProductImage `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "/media/products/a/b_thumb.jpg", response.Data.ThumbnailURL)
+
+	rec = httptest.NewRecorder()
+	handler.UploadProductImage(rec, imageUploadRequest(t, "file", []byte("svg")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.UploadProductImage(rec, imageUploadRequest(t, "image", []byte("png")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "the file field is missing")
+
+	rec = httptest.NewRecorder()
+	handler.UploadProductImage(rec, imageUploadRequest(t, "file", make([]byte, maxImageUploadSize)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	mockService.AssertNumberOfCalls(t, "UploadProductImage", 2)
+}
+
+func TestProductImageHandlerDeleteProductImage(t *testing.T) {
+	mockService := new(mocks.MockProductImageService)
+	handler := NewProductImageHandler(mockService)
+
+	mockService.On("DeleteProductImage", "abc", "img").Return(nil)
+	mockService.On("DeleteProductImage", "abc", "nope").Return(fmt.Errorf("%w: nope", service.ErrProductImageNotFound))
+	mockService.On("DeleteProductImage", "abc", mock.Anything).Return(fmt.Errorf("%w: product not found", service.ErrInvalidProduct))
+
+	for imageID, status := range map[string]int{"img": http.StatusOK, "nope": http.StatusNotFound, "other": http.StatusBadRequest} {
+		req := httptest.NewRequest("DELETE", "/api/products/abc/images/"+imageID, nil)
+		req.SetPathValue("id", "abc")
+		req.SetPathValue("imageId", imageID)
+		rec := httptest.NewRecorder()
+		handler.DeleteProductImage(rec, req)
+		assert.Equal(t, status, rec.Code, imageID)
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurchaseOrderHandlerCreatePurchaseOrder(t *testing.T) {
+	mockService := new(mocks.MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	reqBody := model.CreatePurchaseOrderRequest{SupplierID: "s1", Items: []model.PurchaseOrderItemRequest{{ProductID: "p1", Quantity: 2, CostPrice: 100}}}
+	mockService.On("CreatePurchaseOrder", reqBody).Return(model.PurchaseOrder{ID: "1", Status: model.PurchaseOrderDraft}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	rec := httptest.NewRecorder()
+	handler.CreatePurchaseOrder(rec, httptest.NewRequest("POST", "/api/purchase-orders", bytes.NewBuffer(body)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestPurchaseOrderHandlerReceiveGoods(t *testing.T) {
+	mockService := new(mocks.MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	reqBody := model.ReceiveGoodsRequest{Items: []model.GoodsReceiptItemRequest{{ProductID: "p1", Quantity: 2}}}
+	mockService.On("ReceiveGoods", "po1", reqBody).Return(model.PurchaseOrder{ID: "po1", Status: model.PurchaseOrderPartiallyReceived}, nil)
+	mockService.On("ReceiveGoods", "po2", reqBody).Return(model.PurchaseOrder{}, fmt.Errorf("%w: received quantity exceeds the outstanding 1", service.ErrInvalidPurchaseOrder))
+	mockService.On("ReceiveGoods", "po3", reqBody).Return(model.PurchaseOrder{}, fmt.Errorf("%w: purchase order is draft", service.ErrPurchaseOrderStatus))
+
+	for id, status := range map[string]int{"po1": http.StatusCreated, "po2": http.StatusBadRequest, "po3": http.StatusConflict} {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/api/purchase-orders/"+id+"/receipts", bytes.NewBuffer(body))
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+
+		handler.ReceiveGoods(rec, req)
+
+		assert.Equal(t, status, rec.Code, id)
+	}
+}
+
+func TestPurchaseOrderHandlerSendAndClose(t *testing.T) {
+	mockService := new(mocks.MockPurchaseOrderService)
+	handler := NewPurchaseOrderHandler(mockService)
+
+	mockService.On("SendPurchaseOrder", "po1").Return(model.PurchaseOrder{ID: "po1", Status: model.PurchaseOrderSent}, nil)
+	mockService.On("ClosePurchaseOrder", "po1").Return(model.PurchaseOrder{}, fmt.Errorf("%w: purchase order is draft", service.ErrPurchaseOrderStatus))
+
+	req := httptest.NewRequest("POST", "/api/purchase-orders/po1/send", nil)
+	req.SetPathValue("id", "po1")
+	rec := httptest.NewRecorder()
+	handler.SendPurchaseOrder(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest("POST", "/api/purchase-orders/po1/close", nil)
+	req.SetPathValue("id", "po1")
+	rec = httptest.NewRecorder()
+	handler.ClosePurchaseOrder(rec, req)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShiftHandlerOpenShift(t *testing.T) {
+	mockService := new(mocks.MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	valid := model.OpenShiftRequest{CashierName: "Dewi", OpeningFloat: 200000}
+	invalid := model.OpenShiftRequest{OpeningFloat: 200000}
+	busy := model.OpenShiftRequest{CashierName: "Sari"}
+	mockService.On("OpenShift", valid).Return(model.Shift{ID: "s1", CashierName: "Dewi", Status: model.ShiftOpen}, nil)
+	mockService.On("OpenShift", invalid).Return(model.Shift{}, fmt.Errorf("%w: cashier name is required", service.ErrInvalidShift))
+	mockService.On("OpenShift", busy).Return(model.Shift{}, fmt.Errorf("%w: Sari already has an open shift", service.ErrShiftStatus))
+
+	for _, tt := range []struct {
+		request model.OpenShiftRequest
+		status  int
+	}{{valid, http.StatusCreated}, {invalid, http.StatusBadRequest}, {busy, http.StatusConflict}} {
+		body, _ := json.Marshal(tt.request)
+		rec := httptest.NewRecorder()
+		handler.OpenShift(rec, httptest.NewRequest("POST", "/api/shifts", bytes.NewBuffer(body)))
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestShiftHandlerCloseShift(t *testing.T) {
+	mockService := new(mocks.MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	counted := int64(234000)
+	request := model.CloseShiftRequest{CountedCash: &counted}
+	mockService.On("CloseShift", "s1", request).Return(model.Shift{ID: "s1", Status: model.ShiftClosed}, nil)
+	mockService.On("CloseShift", "s2", request).Return(model.Shift{}, fmt.Errorf("%w: shift is closed", service.ErrShiftStatus))
+
+	for _, tt := range []struct {
+		id     string
+		status int
+	}{{"s1", http.StatusOK}, {"s2", http.StatusConflict}} {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/shifts/"+tt.id+"/close", bytes.NewBuffer(body))
+		req.SetPathValue("id", tt.id)
+		rec := httptest.NewRecorder()
+		handler.CloseShift(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+}
+
+func TestShiftHandlerFetchShiftReport(t *testing.T) {
+	mockService := new(mocks.MockShiftService)
+	handler := NewShiftHandler(mockService)
+
+	variance := model.Price{Amount: -1000, Currency: "IDR"}
+	mockService.On("FetchShiftReport", "s1").Return(model.ShiftReport{
+		Kind:         model.ShiftReportZ,
+		Shift:        model.Shift{ID: "s1", Status: model.ShiftClosed},
+		ExpectedCash: model.Price{Amount: 235000, Currency: "IDR"},
+		Variance:     &variance,
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/shifts/s1/report", nil)
+	req.SetPathValue("id", "s1")
+	rec := httptest.NewRecorder()
+	handler.FetchShiftReport(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"kind":"Z"`)
+	assert.Contains(t, rec.Body.String(), `"amount":-1000`)
+}
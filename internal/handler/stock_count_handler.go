@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type StockCountHandler struct {
+	stockCountService service.StockCountService
+}
+
+func NewStockCountHandler(stockCountService service.StockCountService) *StockCountHandler {
+	return &StockCountHandler{
+		stockCountService: stockCountService,
+	}
+}
+
+// GET /api/stock-counts
+func (h *StockCountHandler) FetchStockCounts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	counts, err := h.stockCountService.FetchStockCounts()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch stock counts"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(counts))
+}
+
+// GET /api/stock-counts/{id}
+func (h *StockCountHandler) FetchStockCountByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	count, err := h.stockCountService.FetchStockCountByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch stock count"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(count))
+}
+
+// POST /api/stock-counts
+func (h *StockCountHandler) OpenStockCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateStockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	count, err := h.stockCountService.OpenStockCount(request)
+	if err != nil {
+		writeStockCountError(w, err, "Failed to open stock count")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(count))
+}
+
+// PUT /api/stock-counts/{id}/counts
+func (h *StockCountHandler) RecordCounts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.SubmitStockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	count, err := h.stockCountService.RecordCounts(r.PathValue("id"), request)
+	if err != nil {
+		writeStockCountError(w, err, "Failed to record counts")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(count))
+}
+
+// GET /api/stock-counts/{id}/variances
+func (h *StockCountHandler) FetchVarianceReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	report, err := h.stockCountService.FetchVarianceReport(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch variance report"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(report))
+}
+
+// POST /api/stock-counts/{id}/submit
+func (h *StockCountHandler) SubmitStockCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	count, err := h.stockCountService.SubmitStockCount(r.PathValue("id"))
+	if err != nil {
+		writeStockCountError(w, err, "Failed to submit stock count")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(count))
+}
+
+// POST /api/stock-counts/{id}/approve
+func (h *StockCountHandler) ApproveStockCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	count, err := h.stockCountService.ApproveStockCount(r.PathValue("id"))
+	if err != nil {
+		writeStockCountError(w, err, "Failed to approve stock count")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(count))
+}
+
+// POST /api/stock-counts/{id}/cancel
+func (h *StockCountHandler) CancelStockCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	count, err := h.stockCountService.CancelStockCount(r.PathValue("id"))
+	if err != nil {
+		writeStockCountError(w, err, "Failed to cancel stock count")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(count))
+}
+
+func writeStockCountError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidStockCount):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrStockCountStatus):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockCountHandlerFetchStockCounts(t *testing.T) {
+	mockService := new(mocks.MockStockCountService)
+	handler := NewStockCountHandler(mockService)
+
+	mockService.On("FetchStockCounts").Return([]model.StockCount{{ID: "1", Status: model.StockCountOpen}}, nil)
+
+	rec := httptest.NewRecorder()
+	handler.FetchStockCounts(rec, httptest.NewRequest("GET", "/api/stock-counts", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockCountHandlerOpenStockCount(t *testing.T) {
+	mockService := new(mocks.MockStockCountService)
+	handler := NewStockCountHandler(mockService)
+
+	reqBody := model.CreateStockCountRequest{Notes: "monthly"}
+	mockService.On("OpenStockCount", reqBody).Return(model.StockCount{ID: "1", Status: model.StockCountOpen}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	rec := httptest.NewRecorder()
+	handler.OpenStockCount(rec, httptest.NewRequest("POST", "/api/stock-counts", bytes.NewBuffer(body)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.OpenStockCount(rec, httptest.NewRequest("POST", "/api/stock-counts", bytes.NewBufferString("invalid json")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStockCountHandlerRecordCounts(t *testing.T) {
+	mockService := new(mocks.MockStockCountService)
+	handler := NewStockCountHandler(mockService)
+
+	reqBody := model.SubmitStockCountRequest{Items: []model.StockCountItemRequest{{ProductID: "p1", CountedQuantity: -1}}}
+	mockService.On("RecordCounts", "abc", reqBody).Return(model.StockCount{}, fmt.Errorf("%w: counted quantity cannot be negative", service.ErrInvalidStockCount))
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("PUT", "/api/stock-counts/abc/counts", bytes.NewBuffer(body))
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+
+	handler.RecordCounts(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStockCountHandlerTransitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		call   func(h *StockCountHandler) http.HandlerFunc
+		err    error
+		status int
+	}{
+		{"submit", "SubmitStockCount", func(h *StockCountHandler) http.HandlerFunc { return h.SubmitStockCount }, nil, http.StatusOK},
+		{"approve", "ApproveStockCount", func(h *StockCountHandler) http.HandlerFunc { return h.ApproveStockCount }, nil, http.StatusOK},
+		{"approve conflict", "ApproveStockCount", func(h *StockCountHandler) http.HandlerFunc { return h.ApproveStockCount }, fmt.Errorf("%w: stock count is open", service.ErrStockCountStatus), http.StatusConflict},
+		{"cancel failure", "CancelStockCount", func(h *StockCountHandler) http.HandlerFunc { return h.CancelStockCount }, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockStockCountService)
+			handler := NewStockCountHandler(mockService)
+			mockService.On(tt.method, "abc").Return(model.StockCount{ID: "abc"}, tt.err)
+
+			req := httptest.NewRequest("POST", "/api/stock-counts/abc", nil)
+			req.SetPathValue("id", "abc")
+			rec := httptest.NewRecorder()
+
+			tt.call(handler)(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestStockCountHandlerFetchVarianceReport(t *testing.T) {
+	mockService := new(mocks.MockStockCountService)
+	handler := NewStockCountHandler(mockService)
+
+	mockService.On("FetchVarianceReport", "abc").Return(model.StockCountVarianceReport{StockCountID: "abc", TotalVarianceQty: -2}, nil)
+
+	req := httptest.NewRequest("GET", "/api/stock-counts/abc/variances", nil)
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+
+	handler.FetchVarianceReport(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
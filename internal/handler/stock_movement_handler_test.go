@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementHandlerFetchStockMovements(t *testing.T) {
+	mockService := new(mocks.MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	mockService.On("FetchStockMovements", "abc").Return([]model.StockMovement{
+		{ID: "1", ProductID: "abc", Type: model.StockMovementSale, Quantity: -1, BalanceAfter: 4},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/products/abc/stock-movements", nil)
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+
+	handler.FetchStockMovements(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response model.APIResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.NotNil(t, response.Data)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockMovementHandlerFetchStockMovementsError(t *testing.T) {
+	mockService := new(mocks.MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	mockService.On("FetchStockMovements", "abc").Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/api/products/abc/stock-movements", nil)
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+
+	handler.FetchStockMovements(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestStockMovementHandlerCreateStockMovement(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"invalid", fmt.Errorf("%w: waste must take stock out", service.ErrInvalidStockMovement), http.StatusBadRequest},
+		{"failed", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockStockMovementService)
+			handler := NewStockMovementHandler(mockService)
+
+			reqBody := model.CreateStockMovementRequest{Type: model.StockMovementWaste, Quantity: -1, Reason: "broken"}
+			mockService.On("CreateStockMovement", "abc", reqBody).Return(model.StockMovement{ID: "1"}, tt.err)
+
+			body, _ := json.Marshal(reqBody)
+			req := httptest.NewRequest("POST", "/api/products/abc/stock-movements", bytes.NewBuffer(body))
+			req.SetPathValue("id", "abc")
+			rec := httptest.NewRecorder()
+
+			handler.CreateStockMovement(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestStockMovementHandlerCreateStockMovementInvalidJSON(t *testing.T) {
+	mockService := new(mocks.MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	req := httptest.NewRequest("POST", "/api/products/abc/stock-movements", bytes.NewBufferString("invalid json"))
+	rec := httptest.NewRecorder()
+
+	handler.CreateStockMovement(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStockMovementHandlerFetchStockReconciliation(t *testing.T) {
+	mockService := new(mocks.MockStockMovementService)
+	handler := NewStockMovementHandler(mockService)
+
+	mockService.On("FetchStockReconciliation", "abc").Return(model.StockReconciliation{ProductID: "abc", Stock: 3, LedgerBalance: 3, Balanced: true}, nil)
+
+	req := httptest.NewRequest("GET", "/api/products/abc/stock-reconciliation", nil)
+	req.SetPathValue("id", "abc")
+	rec := httptest.NewRecorder()
+
+	handler.FetchStockReconciliation(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type StockTransferHandler struct {
+	stockTransferService service.StockTransferService
+}
+
+func NewStockTransferHandler(stockTransferService service.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{
+		stockTransferService: stockTransferService,
+	}
+}
+
+// GET /api/stock-transfers
+func (h *StockTransferHandler) FetchStockTransfers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	transfers, err := h.stockTransferService.FetchStockTransfers()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch stock transfers"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(transfers))
+}
+
+// GET /api/stock-transfers/{id}
+func (h *StockTransferHandler) FetchStockTransferByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	transfer, err := h.stockTransferService.FetchStockTransferByID(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to fetch stock transfer"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(transfer))
+}
+
+// POST /api/stock-transfers
+func (h *StockTransferHandler) CreateStockTransfer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.CreateStockTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	transfer, err := h.stockTransferService.CreateStockTransfer(request)
+	if err != nil {
+		writeStockTransferError(w, err, "Failed to create stock transfer")
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(transfer))
+}
+
+// POST /api/stock-transfers/{id}/receive
+func (h *StockTransferHandler) ReceiveStockTransfer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	transfer, err := h.stockTransferService.ReceiveStockTransfer(r.PathValue("id"))
+	if err != nil {
+		writeStockTransferError(w, err, "Failed to receive stock transfer")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(transfer))
+}
+
+// POST /api/stock-transfers/{id}/cancel
+func (h *StockTransferHandler) CancelStockTransfer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	transfer, err := h.stockTransferService.CancelStockTransfer(r.PathValue("id"))
+	if err != nil {
+		writeStockTransferError(w, err, "Failed to cancel stock transfer")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(transfer))
+}
+
+func writeStockTransferError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidStockTransfer):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrStockTransferStatus):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
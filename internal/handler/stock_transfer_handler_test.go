@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockTransferHandlerCreateStockTransfer(t *testing.T) {
+	mockService := new(mocks.MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	reqBody := model.CreateStockTransferRequest{FromOutletID: "o1", ToOutletID: "o2", Items: []model.StockTransferItemRequest{{ProductID: "p1", Quantity: 2}}}
+	mockService.On("CreateStockTransfer", reqBody).Return(model.StockTransfer{ID: "1", Status: model.StockTransferInTransit}, nil)
+
+	body, _ := json.Marshal(reqBody)
+	rec := httptest.NewRecorder()
+	handler.CreateStockTransfer(rec, httptest.NewRequest("POST", "/api/stock-transfers", bytes.NewBuffer(body)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestStockTransferHandlerReceiveAndCancel(t *testing.T) {
+	mockService := new(mocks.MockStockTransferService)
+	handler := NewStockTransferHandler(mockService)
+
+	mockService.On("ReceiveStockTransfer", "st1").Return(model.StockTransfer{ID: "st1", Status: model.StockTransferReceived}, nil)
+	mockService.On("ReceiveStockTransfer", "st2").Return(model.StockTransfer{}, fmt.Errorf("%w: stock transfer is received", service.ErrStockTransferStatus))
+	mockService.On("CancelStockTransfer", "st3").Return(model.StockTransfer{}, fmt.Errorf("%w: invalid outlet id", service.ErrInvalidStockTransfer))
+
+	for id, status := range map[string]int{"st1": http.StatusOK, "st2": http.StatusConflict} {
+		req := httptest.NewRequest("POST", "/api/stock-transfers/"+id+"/receive", nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		handler.ReceiveStockTransfer(rec, req)
+		assert.Equal(t, status, rec.Code, id)
+	}
+
+	req := httptest.NewRequest("POST", "/api/stock-transfers/st3/cancel", nil)
+	req.SetPathValue("id", "st3")
+	rec := httptest.NewRecorder()
+	handler.CancelStockTransfer(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplierHandlerFetchSuppliers(t *testing.T) {
+	mockService := new(mocks.MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	mockService.On("FetchSuppliers").Return([]model.Supplier{{ID: "1", Name: "CV Jaya"}}, nil)
+
+	rec := httptest.NewRecorder()
+	handler.FetchSuppliers(rec, httptest.NewRequest("GET", "/api/suppliers", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSupplierHandlerCreateSupplier(t *testing.T) {
+	mockService := new(mocks.MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	valid := model.CreateSupplierRequest{Name: "CV Jaya"}
+	invalid := model.CreateSupplierRequest{}
+	mockService.On("CreateSupplier", valid).Return(model.Supplier{ID: "1", Name: "CV Jaya"}, nil)
+	mockService.On("CreateSupplier", invalid).Return(model.Supplier{}, fmt.Errorf("%w: name is required", service.ErrInvalidSupplier))
+
+	body, _ := json.Marshal(valid)
+	rec := httptest.NewRecorder()
+	handler.CreateSupplier(rec, httptest.NewRequest("POST", "/api/suppliers", bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	body, _ = json.Marshal(invalid)
+	rec = httptest.NewRecorder()
+	handler.CreateSupplier(rec, httptest.NewRequest("POST", "/api/suppliers", bytes.NewBuffer(body)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.CreateSupplier(rec, httptest.NewRequest("POST", "/api/suppliers", bytes.NewBufferString("invalid json")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSupplierHandlerDeleteSupplier(t *testing.T) {
+	mockService := new(mocks.MockSupplierService)
+	handler := NewSupplierHandler(mockService)
+
+	mockService.On("DeleteSupplierByID", "ok").Return(nil)
+	mockService.On("DeleteSupplierByID", "fail").Return(errors.New("delete error"))
+
+	req := httptest.NewRequest("DELETE", "/api/suppliers/ok", nil)
+	req.SetPathValue("id", "ok")
+	rec := httptest.NewRecorder()
+	handler.DeleteSupplier(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest("DELETE", "/api/suppliers/fail", nil)
+	req.SetPathValue("id", "fail")
+	rec = httptest.NewRecorder()
+	handler.DeleteSupplier(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
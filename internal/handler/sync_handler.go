@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+)
+
+type SyncHandler struct {
+	syncService service.SyncService
+}
+
+func NewSyncHandler(syncService service.SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// GET /api/sync/catalog?cursor=<cursor of the previous pull, empty for the whole catalog>&outlet_id=<the terminal's outlet>
+func (h *SyncHandler) PullCatalog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	query := r.URL.Query()
+	delta, err := h.syncService.PullCatalog(query.Get("cursor"), query.Get("outlet_id"))
+	if err != nil {
+		writeSyncError(w, err, "Failed to pull catalog")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(delta))
+}
+
+// POST /api/sync/transactions
+func (h *SyncHandler) PushTransactions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.SyncTransactionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	results, err := h.syncService.PushTransactions(request)
+	if err != nil {
+		writeSyncError(w, err, "Failed to push transactions")
+		return
+	}
+	// every sale has its own outcome in the results, a rejected one does not fail the push
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(results))
+}
+
+// GET /api/sync/conflicts?status=<open|resolved, empty for every conflict>
+func (h *SyncHandler) FetchStockConflicts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	conflicts, err := h.syncService.FetchStockConflicts(r.URL.Query().Get("status"))
+	if err != nil {
+		writeSyncError(w, err, "Failed to fetch stock conflicts")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponseWithItems(conflicts))
+}
+
+// POST /api/sync/conflicts/{id}/resolve
+func (h *SyncHandler) ResolveStockConflict(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var request model.ResolveStockConflictRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusBadRequest, "Invalid request body"))
+		return
+	}
+
+	conflict, err := h.syncService.ResolveStockConflict(r.PathValue("id"), request)
+	if err != nil {
+		writeSyncError(w, err, "Failed to resolve stock conflict")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(conflict))
+}
+
+func writeSyncError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSync):
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusBadRequest, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonInvalidValue),
+		}))
+	case errors.Is(err, service.ErrStockConflictStatus):
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusConflict, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, message))
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncHandlerPullCatalog(t *testing.T) {
+	mockService := new(mocks.MockSyncService)
+	handler := NewSyncHandler(mockService)
+
+	mockService.On("PullCatalog", "", "o1").Return(model.CatalogDelta{Products: []model.CatalogProduct{{Product: model.Product{ID: "p1"}}}, Cursor: "7410"}, nil)
+	mockService.On("PullCatalog", "yesterday", "").Return(model.CatalogDelta{}, fmt.Errorf("%w: cursor must be one a previous pull returned", service.ErrInvalidSync))
+
+	rec := httptest.NewRecorder()
+	handler.PullCatalog(rec, httptest.NewRequest("GET", "/api/sync/catalog?outlet_id=o1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"cursor":"7410"`)
+
+	rec = httptest.NewRecorder()
+	handler.PullCatalog(rec, httptest.NewRequest("GET", "/api/sync/catalog?cursor=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSyncHandlerPushTransactions(t *testing.T) {
+	mockService := new(mocks.MockSyncService)
+	handler := NewSyncHandler(mockService)
+
+	mockService.On("PushTransactions", model.SyncTransactionsRequest{Transactions: []model.SyncTransactionRequest{{ID: "t1"}}}).Return([]model.SyncTransactionResult{
+		{ID: "t1", Status: model.SyncRejected, Error: "id must be a UUIDv7"},
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	handler.PushTransactions(rec, httptest.NewRequest("POST", "/api/sync/transactions", bytes.NewBufferString(`{"transactions":[{"id":"t1"}]}`)))
+	assert.Equal(t, http.StatusOK, rec.Code, "a rejected sale does not fail the push")
+	assert.Contains(t, rec.Body.String(), `"status":"rejected"`)
+
+	rec = httptest.NewRecorder()
+	handler.PushTransactions(rec, httptest.NewRequest("POST", "/api/sync/transactions", bytes.NewBufferString(`{`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSyncHandlerResolveStockConflict(t *testing.T) {
+	mockService := new(mocks.MockSyncService)
+	handler := NewSyncHandler(mockService)
+
+	mockService.On("ResolveStockConflict", "c1", model.ResolveStockConflictRequest{Notes: "recounted"}).Return(model.StockConflict{ID: "c1", Status: model.StockConflictResolved}, nil)
+	mockService.On("ResolveStockConflict", "c2", model.ResolveStockConflictRequest{}).Return(model.StockConflict{}, fmt.Errorf("%w: resolved by USER", service.ErrStockConflictStatus))
+	mockService.On("FetchStockConflicts", "open").Return([]model.StockConflict{{ID: "c3"}}, nil)
+
+	for _, tt := range []struct {
+		id     string
+		body   string
+		status int
+	}{{"c1", `{"notes":"recounted"}`, http.StatusOK}, {"c2", `{}`, http.StatusConflict}} {
+		req := httptest.NewRequest("POST", "/api/sync/conflicts/"+tt.id+"/resolve", bytes.NewBufferString(tt.body))
+		req.SetPathValue("id", tt.id)
+		rec := httptest.NewRecorder()
+		handler.ResolveStockConflict(rec, req)
+		assert.Equal(t, tt.status, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.FetchStockConflicts(rec, httptest.NewRequest("GET", "/api/sync/conflicts?status=open", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
+	"codewithumam-kasir-api/internal/tenant"
+	"github.com/google/uuid"
+)
+
+// TenantMiddleware resolves the shop from the bearer token or the subdomain and scopes the request context to it.
+// Whatever the tenant service allows, a request without a token can only read: changes always need the bearer token.
+func TenantMiddleware(tenantService service.TenantService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			token = strings.TrimSpace(token)
+			if token == "" && !isReadOnly(r.Method) {
+				writeTenantError(w, fmt.Errorf("%w: an api token is required to %s", service.ErrTenantNotFound, r.Method))
+				return
+			}
+			t, err := tenantService.ResolveTenant(token, r.Host)
+			if err != nil {
+				writeTenantError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), t.ID)))
+		})
+	}
+}
+
+// TenantMux serves each tenant with its own handler, built on the tenant's first request and kept afterwards.
+// Everything the factory wires, repositories and event broker alike, only ever sees that tenant.
+type TenantMux struct {
+	factory  func(tenantID uuid.UUID) http.Handler
+	mu       sync.Mutex
+	handlers map[uuid.UUID]http.Handler
+}
+
+func NewTenantMux(factory func(tenantID uuid.UUID) http.Handler) *TenantMux {
+	return &TenantMux{
+		factory:  factory,
+		handlers: map[uuid.UUID]http.Handler{},
+	}
+}
+
+func (m *TenantMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := tenant.IDFromContext(r.Context())
+	if !ok {
+		writeTenantError(w, service.ErrTenantNotFound)
+		return
+	}
+	m.handlerFor(tenantID).ServeHTTP(w, r)
+}
+
+func (m *TenantMux) handlerFor(tenantID uuid.UUID) http.Handler {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.handlers[tenantID]
+	if !ok {
+		h = m.factory(tenantID)
+		m.handlers[tenantID] = h
+	}
+	return h
+}
+
+func isReadOnly(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func writeTenantError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case errors.Is(err, service.ErrTenantNotFound):
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(model.NewAPIErrorWithErrors(http.StatusUnauthorized, []model.ErrorItem{
+			model.NewErrorItem(err.Error()).WithReason(model.ReasonUnauthorized),
+		}))
+	case errors.Is(err, service.ErrTenantInactive):
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusForbidden, err.Error()))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(http.StatusInternalServerError, "Failed to resolve tenant"))
+	}
+}
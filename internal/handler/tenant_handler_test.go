@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	inmemory "codewithumam-kasir-api/internal/repository/inmemory"
+	"codewithumam-kasir-api/internal/service"
+	"codewithumam-kasir-api/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	mockService := new(mocks.MockTenantService)
+	shopID := uuid.New()
+	mockService.On("ResolveTenant", "token-a", "example.com").Return(model.TenantEntity{ID: shopID, IsActive: true}, nil)
+	mockService.On("ResolveTenant", "", "example.com").Return(model.TenantEntity{}, fmt.Errorf("%w: no api token or subdomain", service.ErrTenantNotFound))
+	mockService.On("ResolveTenant", "token-b", "example.com").Return(model.TenantEntity{}, fmt.Errorf("%w: toko-b", service.ErrTenantInactive))
+	mockService.On("ResolveTenant", "token-c", "example.com").Return(model.TenantEntity{}, errors.New("database error"))
+
+	var seen uuid.UUID
+	handler := TenantMiddleware(mockService)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = tenant.IDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range []struct {
+		method        string
+		authorization string
+		status        int
+	}{
+		{"GET", "Bearer token-a", http.StatusOK},
+		{"POST", "Bearer token-a", http.StatusOK},
+		{"GET", "", http.StatusUnauthorized},
+		{"GET", "Bearer token-b", http.StatusForbidden},
+		{"GET", "Bearer token-c", http.StatusInternalServerError},
+	} {
+		req := httptest.NewRequest(tt.method, "http://example.com/api/categories", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, tt.status, rec.Code, tt.authorization)
+	}
+	assert.Equal(t, shopID, seen)
+}
+
+func TestTenantMiddleware_WritesNeedToken(t *testing.T) {
+	mockService := new(mocks.MockTenantService)
+	shopID := uuid.New()
+	mockService.On("ResolveTenant", "", "toko-a.example.com").Return(model.TenantEntity{ID: shopID, IsActive: true}, nil)
+
+	handler := TenantMiddleware(mockService)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for method, status := range map[string]int{
+		"GET":    http.StatusOK,
+		"POST":   http.StatusUnauthorized,
+		"PUT":    http.StatusUnauthorized,
+		"DELETE": http.StatusUnauthorized,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "http://toko-a.example.com/api/categories", nil))
+		assert.Equal(t, status, rec.Code, method)
+	}
+	mockService.AssertNumberOfCalls(t, "ResolveTenant", 1)
+}
+
+func TestTenantMux_IsolatesTenants(t *testing.T) {
+	tenants := inmemory.NewTenantRepository(
+		model.TenantEntity{ID: uuid.New(), Slug: "toko-a", APITokenHash: model.HashAPIToken("token-a"), IsActive: true},
+		model.TenantEntity{ID: uuid.New(), Slug: "toko-b", APITokenHash: model.HashAPIToken("token-b"), IsActive: true},
+	)
+	built := 0
+	mux := NewTenantMux(func(tenantID uuid.UUID) http.Handler {
+		built++
+		categoryHandler := NewCategoryHandler(service.NewCategoryService(inmemory.NewCategoryRepository(), inmemory.NewProductRepository()))
+		tenantMux := http.NewServeMux()
+		tenantMux.HandleFunc("GET /api/categories", categoryHandler.FetchCategories)
+		tenantMux.HandleFunc("POST /api/categories", categoryHandler.CreateCategory)
+		return tenantMux
+	})
+	handler := TenantMiddleware(service.NewTenantService(tenants, "kasir.example.com", true))(mux)
+
+	do := func(method string, host string, token string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, "http://"+host+"/api/categories", bytes.NewBuffer(payload))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	count := func(rec *httptest.ResponseRecorder) int {
+		var response struct {
+			Data struct {
+				Items []model.Category `json:"items"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		return len(response.Data.Items)
+	}
+
+	rec := do("POST", "toko-a.kasir.example.com", "", model.CreateCategoryRequest{Name: "Minuman"})
+	require.Equal(t, http.StatusUnauthorized, rec.Code, "a subdomain alone cannot make changes")
+	rec = do("POST", "toko-a.kasir.example.com", "token-a", model.CreateCategoryRequest{Name: "Minuman"})
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	assert.Equal(t, 1, count(do("GET", "kasir.example.com", "token-a", nil)))
+	assert.Equal(t, 0, count(do("GET", "toko-b.kasir.example.com", "", nil)))
+	assert.Equal(t, 0, count(do("GET", "kasir.example.com", "token-b", nil)))
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "toko-a.kasir.example.com", "token-b", nil).Code)
+	assert.Equal(t, 2, built)
+}
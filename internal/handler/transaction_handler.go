@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"codewithumam-kasir-api/internal/model"
@@ -64,10 +65,30 @@ func (h *TransactionHandler) FetchReport(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	report, err := h.txService.FetchReport(startDate, endDate, period)
+	report, err := h.txService.FetchReport(startDate, endDate, period, r.URL.Query().Get("outletId"))
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "startDate cannot be after endDate" {
+		if err.Error() == "startDate cannot be after endDate" || errors.Is(err, service.ErrInvalidOutlet) {
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(model.NewAPIError(status, err.Error()))
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(model.NewAPIResponse(report))
+}
+
+func (h *TransactionHandler) FetchMarginReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	period := r.URL.Query().Get("period")
+	startDate := r.URL.Query().Get("startDate")
+	endDate := r.URL.Query().Get("endDate")
+
+	report, err := h.txService.FetchMarginReport(startDate, endDate, period, r.URL.Query().Get("outletId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "startDate cannot be after endDate" || errors.Is(err, service.ErrInvalidOutlet) {
 			status = http.StatusBadRequest
 		}
 		w.WriteHeader(status)
@@ -83,10 +104,10 @@ func (h *TransactionHandler) FetchPopularCategory(w http.ResponseWriter, r *http
 	startDate := r.URL.Query().Get("startDate")
 	endDate := r.URL.Query().Get("endDate")
 
-	category, err := h.txService.FetchMostPopularCategory(startDate, endDate)
+	category, err := h.txService.FetchMostPopularCategory(startDate, endDate, r.URL.Query().Get("outletId"), r.URL.Query().Get("level"))
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "startDate cannot be after endDate" {
+		if err.Error() == "startDate cannot be after endDate" || errors.Is(err, service.ErrInvalidOutlet) || errors.Is(err, service.ErrInvalidCategory) {
 			status = http.StatusBadRequest
 		}
 		w.WriteHeader(status)
@@ -102,10 +123,10 @@ func (h *TransactionHandler) FetchPopularProduct(w http.ResponseWriter, r *http.
 	startDate := r.URL.Query().Get("startDate")
 	endDate := r.URL.Query().Get("endDate")
 
-	product, err := h.txService.FetchMostPopularProduct(startDate, endDate)
+	product, err := h.txService.FetchMostPopularProduct(startDate, endDate, r.URL.Query().Get("outletId"))
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "startDate cannot be after endDate" {
+		if err.Error() == "startDate cannot be after endDate" || errors.Is(err, service.ErrInvalidOutlet) {
 			status = http.StatusBadRequest
 		}
 		w.WriteHeader(status)
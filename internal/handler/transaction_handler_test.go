@@ -9,7 +9,9 @@ import (
 
 	"codewithumam-kasir-api/internal/mock"
 	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/service"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -60,7 +62,7 @@ func TestTransactionHandler_FetchReport(t *testing.T) {
 			req, _ := http.NewRequest("GET", tt.url, nil)
 			rr := httptest.NewRecorder()
 
-			mockService.On("FetchReport", "", "", tt.period).Return(model.ReportResponse{TotalTransactions: 10}, nil)
+			mockService.On("FetchReport", "", "", tt.period, "").Return(model.ReportResponse{TotalTransactions: 10}, nil)
 
 			handler.FetchReport(rr, req)
 
@@ -81,13 +83,27 @@ func TestTransactionHandler_FetchPopularCategory(t *testing.T) {
 	req, _ := http.NewRequest("GET", "/api/reports/popular-category", nil)
 	rr := httptest.NewRecorder()
 
-	mockService.On("FetchMostPopularCategory", "", "").Return(model.PopularCategory{Name: "Cat A", TotalSoldQty: 50}, nil)
+	mockService.On("FetchMostPopularCategory", "", "", "", "").Return(model.PopularCategory{Name: "Cat A", TotalSoldQty: 50}, nil)
 
 	handler.FetchPopularCategory(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestTransactionHandler_FetchPopularCategory_InvalidLevel(t *testing.T) {
+	mockService := new(mock.MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	req, _ := http.NewRequest("GET", "/api/reports/popular-categories?level=-1", nil)
+	rr := httptest.NewRecorder()
+
+	mockService.On("FetchMostPopularCategory", "", "", "", "-1").Return(model.PopularCategory{}, fmt.Errorf("%w: level must be 0 or more", service.ErrInvalidCategory))
+
+	handler.FetchPopularCategory(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestTransactionHandler_FetchReport_InvalidDateRange(t *testing.T) {
 	mockService := new(mock.MockTransactionService)
 	handler := NewTransactionHandler(mockService)
@@ -95,9 +111,55 @@ func TestTransactionHandler_FetchReport_InvalidDateRange(t *testing.T) {
 	req, _ := http.NewRequest("GET", "/api/reports?startDate=2024-01-02&endDate=2024-01-01", nil)
 	rr := httptest.NewRecorder()
 
-	mockService.On("FetchReport", "2024-01-02", "2024-01-01", "").Return(model.ReportResponse{}, errors.New("startDate cannot be after endDate"))
+	mockService.On("FetchReport", "2024-01-02", "2024-01-01", "", "").Return(model.ReportResponse{}, errors.New("startDate cannot be after endDate"))
 
 	handler.FetchReport(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
+
+func TestTransactionHandler_FetchMarginReport(t *testing.T) {
+	mockService := new(mock.MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	mockService.On("FetchMarginReport", "", "", "last-month", "").Return(model.MarginReport{Revenue: 1000, COGS: 400, GrossProfit: 600, MarginPercent: 60}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/reports/margin?period=last-month", nil)
+	rr := httptest.NewRecorder()
+	handler.FetchMarginReport(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"margin_percent":60`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTransactionHandler_FetchMarginReport_InvalidDateRange(t *testing.T) {
+	mockService := new(mock.MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	mockService.On("FetchMarginReport", "2026-02-01", "2026-01-01", "", "").Return(model.MarginReport{}, errors.New("startDate cannot be after endDate"))
+
+	req, _ := http.NewRequest("GET", "/api/reports/margin?startDate=2026-02-01&endDate=2026-01-01", nil)
+	rr := httptest.NewRecorder()
+	handler.FetchMarginReport(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestTransactionHandler_FetchReport_ByOutlet(t *testing.T) {
+	mockService := new(mock.MockTransactionService)
+	handler := NewTransactionHandler(mockService)
+
+	mockService.On("FetchReport", "", "", "today", "o1").Return(model.ReportResponse{TotalTransactions: 3}, nil)
+	mockService.On("FetchReport", "", "", "today", "???").Return(model.ReportResponse{}, fmt.Errorf("%w: invalid outlet id", service.ErrInvalidOutlet))
+
+	for outletID, status := range map[string]int{"o1": http.StatusOK, "???": http.StatusBadRequest} {
+		req := httptest.NewRequest("GET", "/api/reports/today", nil)
+		req.URL.RawQuery = "outletId=" + outletID
+		rr := httptest.NewRecorder()
+
+		handler.FetchReport(rr, req)
+
+		assert.Equal(t, status, rr.Code, outletID)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"codewithumam-kasir-api/internal/model"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -46,6 +47,14 @@ func (m *MockCategoryRepository) DeleteCategoryByID(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockCategoryRepository) FindCategoriesChangedSince(since uint64) ([]model.CategoryEntity, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CategoryEntity), args.Error(1)
+}
+
 // MockProductRepository is a mock implementation of ProductRepository
 type MockProductRepository struct {
 	mock.Mock
@@ -64,6 +73,35 @@ func (m *MockProductRepository) FindProductByID(id string) (model.ProductEntity,
 	return args.Get(0).(model.ProductEntity), args.Error(1)
 }
 
+func (m *MockProductRepository) FindProductBySKU(sku string) (model.ProductEntity, error) {
+	args := m.Called(sku)
+	return args.Get(0).(model.ProductEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) FindProductsByCategoryIDs(categoryIDs []string) ([]model.ProductEntity, error) {
+	args := m.Called(categoryIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) FindBundlesByComponentID(componentID string) ([]model.ProductEntity, error) {
+	args := m.Called(componentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) FindCategoryProductStats() ([]model.CategoryProductStatsEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CategoryProductStatsEntity), args.Error(1)
+}
+
 func (m *MockProductRepository) FindProductsByNameAndActiveStatus(name string, activeStatus *bool) ([]model.ProductEntity, error) {
 	args := m.Called(name, activeStatus)
 	if args.Get(0) == nil {
@@ -72,6 +110,14 @@ func (m *MockProductRepository) FindProductsByNameAndActiveStatus(name string, a
 	return args.Get(0).([]model.ProductEntity), args.Error(1)
 }
 
+func (m *MockProductRepository) FindLowStockProducts() ([]model.ProductEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductEntity), args.Error(1)
+}
+
 func (m *MockProductRepository) InsertProduct(product model.ProductEntity) (model.ProductEntity, error) {
 	args := m.Called(product)
 	return args.Get(0).(model.ProductEntity), args.Error(1)
@@ -87,6 +133,56 @@ func (m *MockProductRepository) DeleteProductByID(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) FindProductsChangedSince(since uint64) ([]model.ProductEntity, uint64, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(uint64), args.Error(2)
+	}
+	return args.Get(0).([]model.ProductEntity), args.Get(1).(uint64), args.Error(2)
+}
+
+func (m *MockProductRepository) ApplyProductBatch(operations []model.ProductBatchOperationEntity, atomic bool) ([]model.ProductBatchResultEntity, error) {
+	args := m.Called(operations, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductBatchResultEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) FindProductUnits(productID string) ([]model.ProductUnitEntity, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductUnitEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) FindProductUnitsByProductIDs(productIDs []string) ([]model.ProductUnitEntity, error) {
+	args := m.Called(productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductUnitEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) ReplaceProductUnits(productID string, units []model.ProductUnitEntity) ([]model.ProductUnitEntity, error) {
+	args := m.Called(productID, units)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductUnitEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) InsertProductImage(image model.ProductImageEntity) (model.ProductImageEntity, error) {
+	args := m.Called(image)
+	return args.Get(0).(model.ProductImageEntity), args.Error(1)
+}
+
+func (m *MockProductRepository) DeleteProductImage(productID, imageID string) (model.ProductImageEntity, error) {
+	args := m.Called(productID, imageID)
+	return args.Get(0).(model.ProductImageEntity), args.Error(1)
+}
+
 // MockTransactionRepository is a mock implementation of TransactionRepository
 type MockTransactionRepository struct {
 	mock.Mock
@@ -97,17 +193,709 @@ func (m *MockTransactionRepository) CreateTransaction(tx model.TransactionEntity
 	return args.Get(0).(model.TransactionEntity), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetReportStats(startDate, endDate time.Time) (model.ReportResponse, error) {
-	args := m.Called(startDate, endDate)
+func (m *MockTransactionRepository) FindTransactionByID(id string) (model.TransactionEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.TransactionEntity), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetReportStats(startDate, endDate time.Time, outletID *uuid.UUID) (model.ReportResponse, error) {
+	args := m.Called(startDate, endDate, outletID)
 	return args.Get(0).(model.ReportResponse), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetMostPopularCategory(startDate, endDate time.Time) (model.PopularCategory, error) {
-	args := m.Called(startDate, endDate)
+func (m *MockTransactionRepository) GetMostPopularCategory(startDate, endDate time.Time, outletID *uuid.UUID, level *int) (model.PopularCategory, error) {
+	args := m.Called(startDate, endDate, outletID, level)
 	return args.Get(0).(model.PopularCategory), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetMostPopularProduct(startDate, endDate time.Time) (model.PopularItem, error) {
-	args := m.Called(startDate, endDate)
+func (m *MockTransactionRepository) GetMostPopularProduct(startDate, endDate time.Time, outletID *uuid.UUID) (model.PopularItem, error) {
+	args := m.Called(startDate, endDate, outletID)
 	return args.Get(0).(model.PopularItem), args.Error(1)
 }
+
+func (m *MockTransactionRepository) GetSalesMargins(startDate, endDate time.Time, outletID *uuid.UUID) ([]model.SalesMarginEntity, error) {
+	args := m.Called(startDate, endDate, outletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SalesMarginEntity), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindTransactionsByCustomer(customerID string) ([]model.TransactionEntity, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TransactionEntity), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetShiftSales(shiftID string) (model.ShiftSalesEntity, error) {
+	args := m.Called(shiftID)
+	return args.Get(0).(model.ShiftSalesEntity), args.Error(1)
+}
+
+// MockStockMovementRepository is a mock implementation of StockMovementRepository
+type MockStockMovementRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockMovementRepository) FindStockMovementsByProductID(productID string) ([]model.StockMovementEntity, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockMovementEntity), args.Error(1)
+}
+
+func (m *MockStockMovementRepository) InsertStockMovement(movement model.StockMovementEntity) (model.StockMovementEntity, error) {
+	args := m.Called(movement)
+	return args.Get(0).(model.StockMovementEntity), args.Error(1)
+}
+
+func (m *MockStockMovementRepository) GetStockReconciliation(productID string) (model.StockReconciliationEntity, error) {
+	args := m.Called(productID)
+	return args.Get(0).(model.StockReconciliationEntity), args.Error(1)
+}
+
+// MockStockCountRepository is a mock implementation of StockCountRepository
+type MockStockCountRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockCountRepository) FindStockCounts() ([]model.StockCountEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockCountEntity), args.Error(1)
+}
+
+func (m *MockStockCountRepository) FindStockCountByID(id string) (model.StockCountEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockCountEntity), args.Error(1)
+}
+
+func (m *MockStockCountRepository) InsertStockCount(count model.StockCountEntity) (model.StockCountEntity, error) {
+	args := m.Called(count)
+	return args.Get(0).(model.StockCountEntity), args.Error(1)
+}
+
+func (m *MockStockCountRepository) UpdateStockCountLines(id string, lines []model.StockCountLineEntity) (model.StockCountEntity, error) {
+	args := m.Called(id, lines)
+	return args.Get(0).(model.StockCountEntity), args.Error(1)
+}
+
+func (m *MockStockCountRepository) UpdateStockCountStatus(id string, fromStatus, toStatus, actor string) (model.StockCountEntity, error) {
+	args := m.Called(id, fromStatus, toStatus, actor)
+	return args.Get(0).(model.StockCountEntity), args.Error(1)
+}
+
+func (m *MockStockCountRepository) PostStockCount(id string, actor string) (model.StockCountEntity, error) {
+	args := m.Called(id, actor)
+	return args.Get(0).(model.StockCountEntity), args.Error(1)
+}
+
+// MockSupplierRepository is a mock implementation of SupplierRepository
+type MockSupplierRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupplierRepository) FindSuppliers() ([]model.SupplierEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SupplierEntity), args.Error(1)
+}
+
+func (m *MockSupplierRepository) FindSupplierByID(id string) (model.SupplierEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.SupplierEntity), args.Error(1)
+}
+
+func (m *MockSupplierRepository) InsertSupplier(supplier model.SupplierEntity) (model.SupplierEntity, error) {
+	args := m.Called(supplier)
+	return args.Get(0).(model.SupplierEntity), args.Error(1)
+}
+
+func (m *MockSupplierRepository) UpdateSupplierByID(id string, supplier model.SupplierEntity) (model.SupplierEntity, error) {
+	args := m.Called(id, supplier)
+	return args.Get(0).(model.SupplierEntity), args.Error(1)
+}
+
+func (m *MockSupplierRepository) DeleteSupplierByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockPurchaseOrderRepository is a mock implementation of PurchaseOrderRepository
+type MockPurchaseOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseOrderRepository) FindPurchaseOrders() ([]model.PurchaseOrderEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PurchaseOrderEntity), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepository) FindPurchaseOrderByID(id string) (model.PurchaseOrderEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.PurchaseOrderEntity), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepository) InsertPurchaseOrder(order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error) {
+	args := m.Called(order)
+	return args.Get(0).(model.PurchaseOrderEntity), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepository) UpdatePurchaseOrderByID(id string, order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error) {
+	args := m.Called(id, order)
+	return args.Get(0).(model.PurchaseOrderEntity), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepository) UpdatePurchaseOrderStatus(id string, fromStatus, toStatus, actor string) (model.PurchaseOrderEntity, error) {
+	args := m.Called(id, fromStatus, toStatus, actor)
+	return args.Get(0).(model.PurchaseOrderEntity), args.Error(1)
+}
+
+func (m *MockPurchaseOrderRepository) ReceiveGoods(id string, receipt model.GoodsReceiptEntity) (model.PurchaseOrderEntity, error) {
+	args := m.Called(id, receipt)
+	return args.Get(0).(model.PurchaseOrderEntity), args.Error(1)
+}
+
+// MockLotRepository is a mock implementation of LotRepository
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) FindLotsByProductID(productID string) ([]model.ProductLotEntity, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductLotEntity), args.Error(1)
+}
+
+func (m *MockLotRepository) FindOpenLotsByProductID(productID string, outletID *uuid.UUID) ([]model.ProductLotEntity, error) {
+	args := m.Called(productID, outletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductLotEntity), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpiringLots(until time.Time) ([]model.ProductLotEntity, error) {
+	args := m.Called(until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductLotEntity), args.Error(1)
+}
+
+func (m *MockLotRepository) InsertLot(lot model.ProductLotEntity) (model.ProductLotEntity, error) {
+	args := m.Called(lot)
+	return args.Get(0).(model.ProductLotEntity), args.Error(1)
+}
+
+// MockOutletRepository is a mock implementation of OutletRepository
+type MockOutletRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutletRepository) FindOutlets() ([]model.OutletEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OutletEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) FindOutletByID(id string) (model.OutletEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.OutletEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) InsertOutlet(outlet model.OutletEntity) (model.OutletEntity, error) {
+	args := m.Called(outlet)
+	return args.Get(0).(model.OutletEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) UpdateOutletByID(id string, outlet model.OutletEntity) (model.OutletEntity, error) {
+	args := m.Called(id, outlet)
+	return args.Get(0).(model.OutletEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) DeleteOutletByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockOutletRepository) FindOutletStocks(outletID string) ([]model.OutletStockEntity, error) {
+	args := m.Called(outletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OutletStockEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) FindOutletStock(outletID, productID string) (model.OutletStockEntity, error) {
+	args := m.Called(outletID, productID)
+	return args.Get(0).(model.OutletStockEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) UpdateOutletPrice(outletID, productID string, priceOverride *int64) (model.OutletStockEntity, error) {
+	args := m.Called(outletID, productID, priceOverride)
+	return args.Get(0).(model.OutletStockEntity), args.Error(1)
+}
+
+func (m *MockOutletRepository) FindStockHeldAtOutlets(productID string) (int, error) {
+	args := m.Called(productID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockStockTransferRepository is a mock implementation of StockTransferRepository
+type MockStockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockTransferRepository) FindStockTransfers() ([]model.StockTransferEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockTransferEntity), args.Error(1)
+}
+
+func (m *MockStockTransferRepository) FindStockTransferByID(id string) (model.StockTransferEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockTransferEntity), args.Error(1)
+}
+
+func (m *MockStockTransferRepository) InsertStockTransfer(transfer model.StockTransferEntity) (model.StockTransferEntity, error) {
+	args := m.Called(transfer)
+	return args.Get(0).(model.StockTransferEntity), args.Error(1)
+}
+
+func (m *MockStockTransferRepository) ReceiveStockTransfer(id string, actor string) (model.StockTransferEntity, error) {
+	args := m.Called(id, actor)
+	return args.Get(0).(model.StockTransferEntity), args.Error(1)
+}
+
+func (m *MockStockTransferRepository) CancelStockTransfer(id string, actor string) (model.StockTransferEntity, error) {
+	args := m.Called(id, actor)
+	return args.Get(0).(model.StockTransferEntity), args.Error(1)
+}
+
+// MockTenantRepository is a mock implementation of TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) FindTenantBySlug(slug string) (model.TenantEntity, error) {
+	args := m.Called(slug)
+	return args.Get(0).(model.TenantEntity), args.Error(1)
+}
+
+func (m *MockTenantRepository) FindTenantByAPITokenHash(hash string) (model.TenantEntity, error) {
+	args := m.Called(hash)
+	return args.Get(0).(model.TenantEntity), args.Error(1)
+}
+
+// MockCustomerRepository is a mock implementation of CustomerRepository
+type MockCustomerRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) FindCustomers() ([]model.CustomerEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CustomerEntity), args.Error(1)
+}
+
+func (m *MockCustomerRepository) FindCustomerByID(id string) (model.CustomerEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.CustomerEntity), args.Error(1)
+}
+
+func (m *MockCustomerRepository) FindCustomersByPhone(phone string) ([]model.CustomerEntity, error) {
+	args := m.Called(phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.CustomerEntity), args.Error(1)
+}
+
+func (m *MockCustomerRepository) InsertCustomer(customer model.CustomerEntity) (model.CustomerEntity, error) {
+	args := m.Called(customer)
+	return args.Get(0).(model.CustomerEntity), args.Error(1)
+}
+
+func (m *MockCustomerRepository) UpdateCustomerByID(id string, customer model.CustomerEntity) (model.CustomerEntity, error) {
+	args := m.Called(id, customer)
+	return args.Get(0).(model.CustomerEntity), args.Error(1)
+}
+
+func (m *MockCustomerRepository) DeleteCustomerByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockLoyaltyRepository is a mock implementation of LoyaltyRepository
+type MockLoyaltyRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoyaltyRepository) FindLoyaltyProgram() (model.LoyaltyProgramEntity, error) {
+	args := m.Called()
+	return args.Get(0).(model.LoyaltyProgramEntity), args.Error(1)
+}
+
+func (m *MockLoyaltyRepository) SaveLoyaltyProgram(program model.LoyaltyProgramEntity) (model.LoyaltyProgramEntity, error) {
+	args := m.Called(program)
+	return args.Get(0).(model.LoyaltyProgramEntity), args.Error(1)
+}
+
+func (m *MockLoyaltyRepository) FindLoyaltyEntries(customerID string) ([]model.LoyaltyEntryEntity, error) {
+	args := m.Called(customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LoyaltyEntryEntity), args.Error(1)
+}
+
+func (m *MockLoyaltyRepository) InsertLoyaltyEntries(entries []model.LoyaltyEntryEntity) ([]model.LoyaltyEntryEntity, error) {
+	args := m.Called(entries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LoyaltyEntryEntity), args.Error(1)
+}
+
+// MockGiftCardRepository is a mock implementation of GiftCardRepository
+type MockGiftCardRepository struct {
+	mock.Mock
+}
+
+func (m *MockGiftCardRepository) FindGiftCards() ([]model.GiftCardEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.GiftCardEntity), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) FindGiftCardByCode(code string) (model.GiftCardEntity, error) {
+	args := m.Called(code)
+	return args.Get(0).(model.GiftCardEntity), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) InsertGiftCard(card model.GiftCardEntity) (model.GiftCardEntity, error) {
+	args := m.Called(card)
+	return args.Get(0).(model.GiftCardEntity), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) FindGiftCardEntries(giftCardID string) ([]model.GiftCardEntryEntity, error) {
+	args := m.Called(giftCardID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.GiftCardEntryEntity), args.Error(1)
+}
+
+func (m *MockGiftCardRepository) InsertGiftCardEntry(entry model.GiftCardEntryEntity) (model.GiftCardEntryEntity, error) {
+	args := m.Called(entry)
+	return args.Get(0).(model.GiftCardEntryEntity), args.Error(1)
+}
+
+// MockShiftRepository is a mock implementation of ShiftRepository
+type MockShiftRepository struct {
+	mock.Mock
+}
+
+func (m *MockShiftRepository) FindShifts(status string) ([]model.ShiftEntity, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ShiftEntity), args.Error(1)
+}
+
+func (m *MockShiftRepository) FindShiftByID(id string) (model.ShiftEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.ShiftEntity), args.Error(1)
+}
+
+func (m *MockShiftRepository) FindOpenShiftByCashier(cashierName string) (model.ShiftEntity, error) {
+	args := m.Called(cashierName)
+	return args.Get(0).(model.ShiftEntity), args.Error(1)
+}
+
+func (m *MockShiftRepository) InsertShift(shift model.ShiftEntity) (model.ShiftEntity, error) {
+	args := m.Called(shift)
+	return args.Get(0).(model.ShiftEntity), args.Error(1)
+}
+
+func (m *MockShiftRepository) InsertCashEntry(entry model.CashEntryEntity) (model.CashEntryEntity, error) {
+	args := m.Called(entry)
+	return args.Get(0).(model.CashEntryEntity), args.Error(1)
+}
+
+func (m *MockShiftRepository) CloseShift(id string, countedCash int64, notes string, actor string) (model.ShiftEntity, error) {
+	args := m.Called(id, countedCash, notes, actor)
+	return args.Get(0).(model.ShiftEntity), args.Error(1)
+}
+
+// MockDraftOrderRepository is a mock implementation of DraftOrderRepository
+type MockDraftOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockDraftOrderRepository) FindDraftOrders(status string) ([]model.DraftOrderEntity, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DraftOrderEntity), args.Error(1)
+}
+
+func (m *MockDraftOrderRepository) FindDraftOrderByID(id string) (model.DraftOrderEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DraftOrderEntity), args.Error(1)
+}
+
+func (m *MockDraftOrderRepository) InsertDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error) {
+	args := m.Called(draft)
+	return args.Get(0).(model.DraftOrderEntity), args.Error(1)
+}
+
+func (m *MockDraftOrderRepository) UpdateDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error) {
+	args := m.Called(draft)
+	return args.Get(0).(model.DraftOrderEntity), args.Error(1)
+}
+
+func (m *MockDraftOrderRepository) SetDraftOrderItem(item model.DraftOrderItemEntity) (model.DraftOrderEntity, error) {
+	args := m.Called(item)
+	return args.Get(0).(model.DraftOrderEntity), args.Error(1)
+}
+
+func (m *MockDraftOrderRepository) FindReservedStocks(outletID *uuid.UUID, exceptID string) (map[uuid.UUID]int, error) {
+	args := m.Called(outletID, exceptID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
+// MockDiningTableRepository is a mock implementation of DiningTableRepository
+type MockDiningTableRepository struct {
+	mock.Mock
+}
+
+func (m *MockDiningTableRepository) FindDiningTables(outletID *uuid.UUID) ([]model.DiningTableEntity, error) {
+	args := m.Called(outletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DiningTableEntity), args.Error(1)
+}
+
+func (m *MockDiningTableRepository) FindDiningTableByID(id string) (model.DiningTableEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DiningTableEntity), args.Error(1)
+}
+
+func (m *MockDiningTableRepository) InsertDiningTable(table model.DiningTableEntity) (model.DiningTableEntity, error) {
+	args := m.Called(table)
+	return args.Get(0).(model.DiningTableEntity), args.Error(1)
+}
+
+func (m *MockDiningTableRepository) UpdateDiningTableByID(id string, table model.DiningTableEntity) (model.DiningTableEntity, error) {
+	args := m.Called(id, table)
+	return args.Get(0).(model.DiningTableEntity), args.Error(1)
+}
+
+// MockKitchenRepository is a mock implementation of KitchenRepository
+type MockKitchenRepository struct {
+	mock.Mock
+}
+
+func (m *MockKitchenRepository) FindKitchenStations() ([]model.KitchenStationEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KitchenStationEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) FindKitchenStationByID(id string) (model.KitchenStationEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.KitchenStationEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) InsertKitchenStation(station model.KitchenStationEntity) (model.KitchenStationEntity, error) {
+	args := m.Called(station)
+	return args.Get(0).(model.KitchenStationEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) UpdateKitchenStationByID(id string, station model.KitchenStationEntity) (model.KitchenStationEntity, error) {
+	args := m.Called(id, station)
+	return args.Get(0).(model.KitchenStationEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) FindStationsByCategory() (map[uuid.UUID]uuid.UUID, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]uuid.UUID), args.Error(1)
+}
+
+func (m *MockKitchenRepository) FindKitchenTickets(stationID, status string) ([]model.KitchenTicketEntity, error) {
+	args := m.Called(stationID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KitchenTicketEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) FindKitchenTicketByID(id string) (model.KitchenTicketEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.KitchenTicketEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) FireKitchenTickets(draft model.DraftOrderEntity, tickets []model.KitchenTicketEntity) ([]model.KitchenTicketEntity, error) {
+	args := m.Called(draft, tickets)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KitchenTicketEntity), args.Error(1)
+}
+
+func (m *MockKitchenRepository) UpdateKitchenTicketStatus(ticket model.KitchenTicketEntity) (model.KitchenTicketEntity, error) {
+	args := m.Called(ticket)
+	return args.Get(0).(model.KitchenTicketEntity), args.Error(1)
+}
+
+// MockStockConflictRepository is a mock implementation of StockConflictRepository
+type MockStockConflictRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockConflictRepository) FindStockConflicts(status string) ([]model.StockConflictEntity, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockConflictEntity), args.Error(1)
+}
+
+func (m *MockStockConflictRepository) FindStockConflictByID(id string) (model.StockConflictEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockConflictEntity), args.Error(1)
+}
+
+func (m *MockStockConflictRepository) ResolveStockConflict(conflict model.StockConflictEntity) (model.StockConflictEntity, error) {
+	args := m.Called(conflict)
+	return args.Get(0).(model.StockConflictEntity), args.Error(1)
+}
+
+// MockPriceListRepository is a mock implementation of PriceListRepository
+type MockPriceListRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceListRepository) FindPriceListsChangedSince(since uint64) ([]model.PriceListEntity, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PriceListEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) FindPriceLists() ([]model.PriceListEntity, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PriceListEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) FindPriceListByID(id string) (model.PriceListEntity, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.PriceListEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) InsertPriceList(priceList model.PriceListEntity) (model.PriceListEntity, error) {
+	args := m.Called(priceList)
+	return args.Get(0).(model.PriceListEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) UpdatePriceListByID(id string, priceList model.PriceListEntity) (model.PriceListEntity, error) {
+	args := m.Called(id, priceList)
+	return args.Get(0).(model.PriceListEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) DeletePriceListByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPriceListRepository) FindProductPrices(productID string) ([]model.ProductPriceEntity, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) FindProductPricesByProductIDs(productIDs []string) ([]model.ProductPriceEntity, error) {
+	args := m.Called(productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) InsertProductPrice(price model.ProductPriceEntity) (model.ProductPriceEntity, error) {
+	args := m.Called(price)
+	return args.Get(0).(model.ProductPriceEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) FindProductPriceByID(productID, id string) (model.ProductPriceEntity, error) {
+	args := m.Called(productID, id)
+	return args.Get(0).(model.ProductPriceEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) CancelProductPrice(productID, id string) error {
+	args := m.Called(productID, id)
+	return args.Error(0)
+}
+
+func (m *MockPriceListRepository) FindProductPriceTiers(productID string) ([]model.ProductPriceTierEntity, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceTierEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) FindProductPriceTiersByProductIDs(productIDs []string) ([]model.ProductPriceTierEntity, error) {
+	args := m.Called(productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceTierEntity), args.Error(1)
+}
+
+func (m *MockPriceListRepository) ReplaceProductPriceTiers(productID string, tiers []model.ProductPriceTierEntity) ([]model.ProductPriceTierEntity, error) {
+	args := m.Called(productID, tiers)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceTierEntity), args.Error(1)
+}
@@ -1,6 +1,9 @@
 package mock
 
 import (
+	"io"
+
+	"codewithumam-kasir-api/internal/event"
 	"codewithumam-kasir-api/internal/model"
 
 	"github.com/stretchr/testify/mock"
@@ -24,6 +27,11 @@ func (m *MockCategoryService) FetchCategoryByID(id string) (model.Category, erro
 	return args.Get(0).(model.Category), args.Error(1)
 }
 
+func (m *MockCategoryService) FetchCategoryTree(id string) (model.CategoryNode, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.CategoryNode), args.Error(1)
+}
+
 func (m *MockCategoryService) CreateCategory(category model.CreateCategoryRequest) (model.Category, error) {
 	args := m.Called(category)
 	return args.Get(0).(model.Category), args.Error(1)
@@ -52,6 +60,14 @@ func (m *MockProductService) FetchProducts() ([]model.Product, error) {
 	return args.Get(0).([]model.Product), args.Error(1)
 }
 
+func (m *MockProductService) FetchProductsByCategory(categoryID string) ([]model.Product, error) {
+	args := m.Called(categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Product), args.Error(1)
+}
+
 func (m *MockProductService) FetchProductByID(id string) (model.Product, error) {
 	args := m.Called(id)
 	return args.Get(0).(model.Product), args.Error(1)
@@ -75,12 +91,64 @@ func (m *MockProductService) UpdateProductByID(id string, product model.UpdatePr
 	return args.Get(0).(model.Product), args.Error(1)
 }
 
+func (m *MockProductService) FetchLowStockProducts() ([]model.LowStockAlert, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.LowStockAlert), args.Error(1)
+}
+
 func (m *MockProductService) DeleteProductByID(id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockProductService) BatchProducts(request model.ProductBatchRequest) (model.ProductBatchResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.ProductBatchResponse), args.Error(1)
+}
+
+func (m *MockProductService) FetchProductUnits(id string) ([]model.ProductUnit, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductUnit), args.Error(1)
+}
+
+func (m *MockProductService) ReplaceProductUnits(id string, request model.ReplaceProductUnitsRequest) ([]model.ProductUnit, error) {
+	args := m.Called(id, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductUnit), args.Error(1)
+}
+
+// MockProductImageService is a mock implementation of ProductImageService
+type MockProductImageService struct {
+	mock.Mock
+}
+
+func (m *MockProductImageService) UploadProductImage(productID string, data []byte) (model.ProductImage, error) {
+	args := m.Called(productID, data)
+	return args.Get(0).(model.ProductImage), args.Error(1)
+}
+
+func (m *MockProductImageService) DeleteProductImage(productID, imageID string) error {
+	args := m.Called(productID, imageID)
+	return args.Error(0)
+}
+
 // MockTransactionService is a mock implementation of TransactionService
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(e event.Event) {
+	m.Called(e)
+}
+
 type MockTransactionService struct {
 	mock.Mock
 }
@@ -90,17 +158,661 @@ func (m *MockTransactionService) CreateTransaction(req model.CreateTransactionRe
 	return args.Get(0).(model.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) FetchReport(startDateStr, endDateStr, period string) (model.ReportResponse, error) {
-	args := m.Called(startDateStr, endDateStr, period)
+func (m *MockTransactionService) FetchReport(startDateStr, endDateStr, period, outletID string) (model.ReportResponse, error) {
+	args := m.Called(startDateStr, endDateStr, period, outletID)
 	return args.Get(0).(model.ReportResponse), args.Error(1)
 }
 
-func (m *MockTransactionService) FetchMostPopularCategory(startDateStr, endDateStr string) (model.PopularCategory, error) {
-	args := m.Called(startDateStr, endDateStr)
+func (m *MockTransactionService) FetchMostPopularCategory(startDateStr, endDateStr, outletID, level string) (model.PopularCategory, error) {
+	args := m.Called(startDateStr, endDateStr, outletID, level)
 	return args.Get(0).(model.PopularCategory), args.Error(1)
 }
 
-func (m *MockTransactionService) FetchMostPopularProduct(startDateStr, endDateStr string) (model.PopularItem, error) {
-	args := m.Called(startDateStr, endDateStr)
+func (m *MockTransactionService) FetchMostPopularProduct(startDateStr, endDateStr, outletID string) (model.PopularItem, error) {
+	args := m.Called(startDateStr, endDateStr, outletID)
 	return args.Get(0).(model.PopularItem), args.Error(1)
 }
+
+func (m *MockTransactionService) FetchMarginReport(startDateStr, endDateStr, period, outletID string) (model.MarginReport, error) {
+	args := m.Called(startDateStr, endDateStr, period, outletID)
+	return args.Get(0).(model.MarginReport), args.Error(1)
+}
+
+// MockStockMovementService is a mock implementation of StockMovementService
+type MockStockMovementService struct {
+	mock.Mock
+}
+
+func (m *MockStockMovementService) FetchStockMovements(productID string) ([]model.StockMovement, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockMovement), args.Error(1)
+}
+
+func (m *MockStockMovementService) CreateStockMovement(productID string, request model.CreateStockMovementRequest) (model.StockMovement, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(model.StockMovement), args.Error(1)
+}
+
+func (m *MockStockMovementService) FetchStockReconciliation(productID string) (model.StockReconciliation, error) {
+	args := m.Called(productID)
+	return args.Get(0).(model.StockReconciliation), args.Error(1)
+}
+
+// MockStockCountService is a mock implementation of StockCountService
+type MockStockCountService struct {
+	mock.Mock
+}
+
+func (m *MockStockCountService) FetchStockCounts() ([]model.StockCount, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockCount), args.Error(1)
+}
+
+func (m *MockStockCountService) FetchStockCountByID(id string) (model.StockCount, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockCount), args.Error(1)
+}
+
+func (m *MockStockCountService) OpenStockCount(request model.CreateStockCountRequest) (model.StockCount, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.StockCount), args.Error(1)
+}
+
+func (m *MockStockCountService) RecordCounts(id string, request model.SubmitStockCountRequest) (model.StockCount, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.StockCount), args.Error(1)
+}
+
+func (m *MockStockCountService) FetchVarianceReport(id string) (model.StockCountVarianceReport, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockCountVarianceReport), args.Error(1)
+}
+
+func (m *MockStockCountService) SubmitStockCount(id string) (model.StockCount, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockCount), args.Error(1)
+}
+
+func (m *MockStockCountService) ApproveStockCount(id string) (model.StockCount, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockCount), args.Error(1)
+}
+
+func (m *MockStockCountService) CancelStockCount(id string) (model.StockCount, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockCount), args.Error(1)
+}
+
+// MockSupplierService is a mock implementation of SupplierService
+type MockSupplierService struct {
+	mock.Mock
+}
+
+func (m *MockSupplierService) FetchSuppliers() ([]model.Supplier, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierService) FetchSupplierByID(id string) (model.Supplier, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierService) CreateSupplier(supplier model.CreateSupplierRequest) (model.Supplier, error) {
+	args := m.Called(supplier)
+	return args.Get(0).(model.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierService) UpdateSupplierByID(id string, supplier model.UpdateSupplierRequest) (model.Supplier, error) {
+	args := m.Called(id, supplier)
+	return args.Get(0).(model.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierService) DeleteSupplierByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockPurchaseOrderService is a mock implementation of PurchaseOrderService
+type MockPurchaseOrderService struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseOrderService) FetchPurchaseOrders() ([]model.PurchaseOrder, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) FetchPurchaseOrderByID(id string) (model.PurchaseOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) CreatePurchaseOrder(request model.CreatePurchaseOrderRequest) (model.PurchaseOrder, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) UpdatePurchaseOrderByID(id string, request model.UpdatePurchaseOrderRequest) (model.PurchaseOrder, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) SendPurchaseOrder(id string) (model.PurchaseOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) ReceiveGoods(id string, request model.ReceiveGoodsRequest) (model.PurchaseOrder, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) ClosePurchaseOrder(id string) (model.PurchaseOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.PurchaseOrder), args.Error(1)
+}
+
+// MockLotService is a mock implementation of LotService
+type MockLotService struct {
+	mock.Mock
+}
+
+func (m *MockLotService) FetchLots(productID string) ([]model.ProductLot, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductLot), args.Error(1)
+}
+
+func (m *MockLotService) ReceiveLot(productID string, request model.CreateProductLotRequest) (model.ProductLot, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(model.ProductLot), args.Error(1)
+}
+
+func (m *MockLotService) FetchExpiringLots(within string) ([]model.ProductLot, error) {
+	args := m.Called(within)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductLot), args.Error(1)
+}
+
+// MockOutletService is a mock implementation of OutletService
+type MockOutletService struct {
+	mock.Mock
+}
+
+func (m *MockOutletService) FetchOutlets() ([]model.Outlet, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Outlet), args.Error(1)
+}
+
+func (m *MockOutletService) FetchOutletByID(id string) (model.Outlet, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Outlet), args.Error(1)
+}
+
+func (m *MockOutletService) CreateOutlet(request model.CreateOutletRequest) (model.Outlet, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.Outlet), args.Error(1)
+}
+
+func (m *MockOutletService) UpdateOutletByID(id string, request model.UpdateOutletRequest) (model.Outlet, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.Outlet), args.Error(1)
+}
+
+func (m *MockOutletService) DeleteOutletByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockOutletService) FetchOutletStocks(id string) ([]model.OutletStock, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OutletStock), args.Error(1)
+}
+
+func (m *MockOutletService) UpdateOutletPrice(id, productID string, request model.UpdateOutletPriceRequest) (model.OutletStock, error) {
+	args := m.Called(id, productID, request)
+	return args.Get(0).(model.OutletStock), args.Error(1)
+}
+
+// MockStockTransferService is a mock implementation of StockTransferService
+type MockStockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockStockTransferService) FetchStockTransfers() ([]model.StockTransfer, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) FetchStockTransferByID(id string) (model.StockTransfer, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) CreateStockTransfer(request model.CreateStockTransferRequest) (model.StockTransfer, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) ReceiveStockTransfer(id string) (model.StockTransfer, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockTransfer), args.Error(1)
+}
+
+func (m *MockStockTransferService) CancelStockTransfer(id string) (model.StockTransfer, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.StockTransfer), args.Error(1)
+}
+
+// MockTenantService is a mock implementation of TenantService
+type MockTenantService struct {
+	mock.Mock
+}
+
+func (m *MockTenantService) ResolveTenant(token string, host string) (model.TenantEntity, error) {
+	args := m.Called(token, host)
+	return args.Get(0).(model.TenantEntity), args.Error(1)
+}
+
+// MockCustomerService is a mock implementation of CustomerService
+type MockCustomerService struct {
+	mock.Mock
+}
+
+func (m *MockCustomerService) FetchCustomers(phone string) ([]model.Customer, error) {
+	args := m.Called(phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) FetchCustomerByID(id string) (model.Customer, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) CreateCustomer(customer model.CreateCustomerRequest) (model.Customer, error) {
+	args := m.Called(customer)
+	return args.Get(0).(model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) UpdateCustomerByID(id string, customer model.UpdateCustomerRequest) (model.Customer, error) {
+	args := m.Called(id, customer)
+	return args.Get(0).(model.Customer), args.Error(1)
+}
+
+func (m *MockCustomerService) DeleteCustomerByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCustomerService) FetchCustomerTransactions(id string) (model.CustomerHistory, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.CustomerHistory), args.Error(1)
+}
+
+// MockLoyaltyService is a mock implementation of LoyaltyService
+type MockLoyaltyService struct {
+	mock.Mock
+}
+
+func (m *MockLoyaltyService) FetchLoyaltyProgram() (model.LoyaltyProgram, error) {
+	args := m.Called()
+	return args.Get(0).(model.LoyaltyProgram), args.Error(1)
+}
+
+func (m *MockLoyaltyService) UpdateLoyaltyProgram(request model.UpdateLoyaltyProgramRequest) (model.LoyaltyProgram, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.LoyaltyProgram), args.Error(1)
+}
+
+func (m *MockLoyaltyService) FetchCustomerPoints(customerID string) (model.CustomerPoints, error) {
+	args := m.Called(customerID)
+	return args.Get(0).(model.CustomerPoints), args.Error(1)
+}
+
+func (m *MockLoyaltyService) ReverseLoyaltyEntry(customerID string, request model.CreateLoyaltyReversalRequest) (model.LoyaltyEntry, error) {
+	args := m.Called(customerID, request)
+	return args.Get(0).(model.LoyaltyEntry), args.Error(1)
+}
+
+// MockGiftCardService is a mock implementation of GiftCardService
+type MockGiftCardService struct {
+	mock.Mock
+}
+
+func (m *MockGiftCardService) FetchGiftCards() ([]model.GiftCard, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardService) FetchGiftCardBalance(code string) (model.GiftCardBalance, error) {
+	args := m.Called(code)
+	return args.Get(0).(model.GiftCardBalance), args.Error(1)
+}
+
+func (m *MockGiftCardService) IssueGiftCard(request model.CreateGiftCardRequest) (model.GiftCard, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.GiftCard), args.Error(1)
+}
+
+func (m *MockGiftCardService) TopUpGiftCard(code string, request model.TopUpGiftCardRequest) (model.GiftCardEntry, error) {
+	args := m.Called(code, request)
+	return args.Get(0).(model.GiftCardEntry), args.Error(1)
+}
+
+// MockShiftService is a mock implementation of ShiftService
+type MockShiftService struct {
+	mock.Mock
+}
+
+func (m *MockShiftService) FetchShifts(status string) ([]model.Shift, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) FetchShiftByID(id string) (model.Shift, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) OpenShift(request model.OpenShiftRequest) (model.Shift, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) CreateCashEntry(id string, request model.CreateCashEntryRequest) (model.CashEntry, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.CashEntry), args.Error(1)
+}
+
+func (m *MockShiftService) CloseShift(id string, request model.CloseShiftRequest) (model.Shift, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.Shift), args.Error(1)
+}
+
+func (m *MockShiftService) FetchShiftReport(id string) (model.ShiftReport, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.ShiftReport), args.Error(1)
+}
+
+// MockDraftOrderService is a mock implementation of DraftOrderService
+type MockDraftOrderService struct {
+	mock.Mock
+}
+
+func (m *MockDraftOrderService) FetchDraftOrders(status string) ([]model.DraftOrder, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) FetchDraftOrderByID(id string) (model.DraftOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) CreateDraftOrder(request model.CreateDraftOrderRequest) (model.DraftOrder, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) AddDraftOrderItem(id string, request model.AddDraftOrderItemRequest) (model.DraftOrder, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) RemoveDraftOrderItem(id string, productID string) (model.DraftOrder, error) {
+	args := m.Called(id, productID)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) HoldDraftOrder(id string, request model.HoldDraftOrderRequest) (model.DraftOrder, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) ResumeDraftOrder(id string) (model.DraftOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) CancelDraftOrder(id string) (model.DraftOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) AssignDraftOrderTable(id string, request model.AssignDraftOrderTableRequest) (model.DraftOrder, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.DraftOrder), args.Error(1)
+}
+
+func (m *MockDraftOrderService) ConvertDraftOrder(id string, request model.ConvertDraftOrderRequest) (model.Transaction, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.Transaction), args.Error(1)
+}
+
+// MockDiningTableService is a mock implementation of DiningTableService
+type MockDiningTableService struct {
+	mock.Mock
+}
+
+func (m *MockDiningTableService) FetchDiningTables(outletID string) ([]model.DiningTable, error) {
+	args := m.Called(outletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DiningTable), args.Error(1)
+}
+
+func (m *MockDiningTableService) FetchDiningTableByID(id string) (model.DiningTable, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.DiningTable), args.Error(1)
+}
+
+func (m *MockDiningTableService) CreateDiningTable(request model.CreateDiningTableRequest) (model.DiningTable, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.DiningTable), args.Error(1)
+}
+
+func (m *MockDiningTableService) UpdateDiningTableByID(id string, request model.UpdateDiningTableRequest) (model.DiningTable, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.DiningTable), args.Error(1)
+}
+
+// MockKitchenService is a mock implementation of KitchenService
+type MockKitchenService struct {
+	mock.Mock
+}
+
+func (m *MockKitchenService) FetchKitchenStations() ([]model.KitchenStation, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KitchenStation), args.Error(1)
+}
+
+func (m *MockKitchenService) CreateKitchenStation(request model.CreateKitchenStationRequest) (model.KitchenStation, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.KitchenStation), args.Error(1)
+}
+
+func (m *MockKitchenService) UpdateKitchenStationByID(id string, request model.UpdateKitchenStationRequest) (model.KitchenStation, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.KitchenStation), args.Error(1)
+}
+
+func (m *MockKitchenService) FetchKitchenTickets(stationID, status string) ([]model.KitchenTicket, error) {
+	args := m.Called(stationID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KitchenTicket), args.Error(1)
+}
+
+func (m *MockKitchenService) FetchKitchenTicketByID(id string) (model.KitchenTicket, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.KitchenTicket), args.Error(1)
+}
+
+func (m *MockKitchenService) FireDraftOrder(draftOrderID string) ([]model.KitchenTicket, error) {
+	args := m.Called(draftOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.KitchenTicket), args.Error(1)
+}
+
+func (m *MockKitchenService) UpdateKitchenTicketStatus(id string, request model.UpdateKitchenTicketStatusRequest) (model.KitchenTicket, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.KitchenTicket), args.Error(1)
+}
+
+// MockSyncService is a mock implementation of SyncService
+type MockSyncService struct {
+	mock.Mock
+}
+
+func (m *MockSyncService) PullCatalog(cursor, outletID string) (model.CatalogDelta, error) {
+	args := m.Called(cursor, outletID)
+	return args.Get(0).(model.CatalogDelta), args.Error(1)
+}
+
+func (m *MockSyncService) PushTransactions(request model.SyncTransactionsRequest) ([]model.SyncTransactionResult, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SyncTransactionResult), args.Error(1)
+}
+
+func (m *MockSyncService) FetchStockConflicts(status string) ([]model.StockConflict, error) {
+	args := m.Called(status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.StockConflict), args.Error(1)
+}
+
+func (m *MockSyncService) ResolveStockConflict(id string, request model.ResolveStockConflictRequest) (model.StockConflict, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.StockConflict), args.Error(1)
+}
+
+// MockCatalogService is a mock implementation of CatalogService
+type MockCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogService) ImportProducts(format string, data []byte, dryRun bool) (model.ProductImportReport, error) {
+	args := m.Called(format, data, dryRun)
+	return args.Get(0).(model.ProductImportReport), args.Error(1)
+}
+
+func (m *MockCatalogService) ExportProducts(format string, w io.Writer) error {
+	args := m.Called(format, w)
+	return args.Error(0)
+}
+
+// MockPriceListService is a mock implementation of PriceListService
+type MockPriceListService struct {
+	mock.Mock
+}
+
+func (m *MockPriceListService) FetchPriceLists() ([]model.PriceList, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PriceList), args.Error(1)
+}
+
+func (m *MockPriceListService) FetchPriceListByID(id string) (model.PriceList, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.PriceList), args.Error(1)
+}
+
+func (m *MockPriceListService) CreatePriceList(request model.CreatePriceListRequest) (model.PriceList, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.PriceList), args.Error(1)
+}
+
+func (m *MockPriceListService) UpdatePriceListByID(id string, request model.UpdatePriceListRequest) (model.PriceList, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.PriceList), args.Error(1)
+}
+
+func (m *MockPriceListService) DeletePriceListByID(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPriceListService) FetchProductPrices(productID string) ([]model.ProductPrice, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPrice), args.Error(1)
+}
+
+func (m *MockPriceListService) SchedulePrice(productID string, request model.SchedulePriceRequest) (model.ProductPrice, error) {
+	args := m.Called(productID, request)
+	return args.Get(0).(model.ProductPrice), args.Error(1)
+}
+
+func (m *MockPriceListService) CancelPrice(productID, id string) error {
+	args := m.Called(productID, id)
+	return args.Error(0)
+}
+
+func (m *MockPriceListService) FetchPriceTiers(productID string) ([]model.ProductPriceTier, error) {
+	args := m.Called(productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceTier), args.Error(1)
+}
+
+func (m *MockPriceListService) ReplacePriceTiers(productID string, request model.ReplacePriceTiersRequest) ([]model.ProductPriceTier, error) {
+	args := m.Called(productID, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductPriceTier), args.Error(1)
+}
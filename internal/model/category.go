@@ -15,23 +15,52 @@ type CategoryEntity struct {
 	UpdatedBy   string
 	DeletedAt   *time.Time
 	Version     int
-	ID          uuid.UUID //UUIDv7
+	ChangeSeq   uint64     // the sync cursor of the last write, the id of the transaction that wrote it
+	ID          uuid.UUID  //UUIDv7
+	ParentID    *uuid.UUID // nil for a top level category
 	Name        string
 	Description string
 }
 
 type Category struct {
-	ID          string     `json:"id"` //Base62 of UUIDv7
-	Name        string     `json:"name"`
-	Description string     `json:"description"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
-	Version     int        `json:"version,omitempty"`
+	ID          string `json:"id"` //Base62 of UUIDv7
+	ParentID    string `json:"parent_id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Breadcrumb is the path from the top level category down to this one
+	Breadcrumb []CategoryCrumb `json:"breadcrumb,omitempty"`
+	// Products sums up the products of the category and of its subcategories
+	Products  *CategoryProductStats `json:"products,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	DeletedAt *time.Time            `json:"deleted_at,omitempty"`
+	Version   int                   `json:"version,omitempty"`
+}
+
+// CategoryProductStatsEntity is what the products of one category add up to, its subcategories not included
+type CategoryProductStatsEntity struct {
+	CategoryID       uuid.UUID
+	ActiveProducts   int
+	InactiveProducts int   // deleted products still assigned to the category
+	StockValue       int64 // the active products' stock at cost
+}
+
+type CategoryProductStats struct {
+	ProductCount         int   `json:"product_count"`
+	ActiveProductCount   int   `json:"active_product_count"`
+	InactiveProductCount int   `json:"inactive_product_count"`
+	StockValue           int64 `json:"stock_value"`
+}
+
+func (s *CategoryProductStats) Add(entity CategoryProductStatsEntity) {
+	s.ProductCount += entity.ActiveProducts + entity.InactiveProducts
+	s.ActiveProductCount += entity.ActiveProducts
+	s.InactiveProductCount += entity.InactiveProducts
+	s.StockValue += entity.StockValue
 }
 
 func (c *CategoryEntity) ToModel() *Category {
-	return &Category{
+	category := &Category{
 		ID:          utils.EncodeBase62(c.ID.String()),
 		Name:        c.Name,
 		Description: c.Description,
@@ -40,10 +69,15 @@ func (c *CategoryEntity) ToModel() *Category {
 		DeletedAt:   c.DeletedAt,
 		Version:     c.Version,
 	}
+	if c.ParentID != nil {
+		category.ParentID = utils.EncodeBase62(c.ParentID.String())
+	}
+	return category
 }
 
 // TODO: add validation
 type CreateCategoryRequest struct {
+	ParentID    string `json:"parent_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 }
@@ -56,6 +90,7 @@ func (c *CreateCategoryRequest) ToEntity() *CategoryEntity {
 
 	return &CategoryEntity{
 		ID:          id,
+		ParentID:    parseOptionalBase62(c.ParentID),
 		Name:        c.Name,
 		Description: c.Description,
 		CreatedBy:   "USER",
@@ -65,6 +100,8 @@ func (c *CreateCategoryRequest) ToEntity() *CategoryEntity {
 
 // TODO: add validation
 type UpdateCategoryRequest struct {
+	// ParentID moves the category under another one, empty makes it a top level category
+	ParentID    string `json:"parent_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Version     int    `json:"version"`
@@ -72,6 +109,7 @@ type UpdateCategoryRequest struct {
 
 func (c *UpdateCategoryRequest) ToEntity() *CategoryEntity {
 	return &CategoryEntity{
+		ParentID:    parseOptionalBase62(c.ParentID),
 		Name:        c.Name,
 		Description: c.Description,
 		Version:     c.Version,
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"codewithumam-kasir-api/internal/utils"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,3 +90,17 @@ func TestUpdateCategoryRequest_ToEntity(t *testing.T) {
 	assert.Equal(t, 3, entity.Version)
 	assert.Equal(t, "USER", entity.UpdatedBy)
 }
+
+func TestCategoryRequest_ParentID(t *testing.T) {
+	parentID := uuid.New()
+	encoded := utils.EncodeBase62(parentID.String())
+
+	entity := (&CreateCategoryRequest{Name: "Coffee", ParentID: encoded}).ToEntity()
+	require.NotNil(t, entity.ParentID)
+	assert.Equal(t, parentID, *entity.ParentID)
+	assert.Equal(t, encoded, entity.ToModel().ParentID)
+
+	entity = (&UpdateCategoryRequest{Name: "Coffee"}).ToEntity()
+	assert.Nil(t, entity.ParentID, "an empty parent moves the category to the top level")
+	assert.Empty(t, entity.ToModel().ParentID)
+}
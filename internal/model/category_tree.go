@@ -0,0 +1,145 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// CategoryCrumb is one step of a category's breadcrumb
+type CategoryCrumb struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CategoryNode is a category with its subcategories, down to the leaves
+type CategoryNode struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Breadcrumb  []CategoryCrumb `json:"breadcrumb"`
+	Children    []CategoryNode  `json:"children"`
+}
+
+// CategoryTree indexes live categories by id and by parent.
+// A category whose parent is not among them is treated as a top level one.
+type CategoryTree struct {
+	categories map[uuid.UUID]CategoryEntity
+	children   map[uuid.UUID][]uuid.UUID
+}
+
+func NewCategoryTree(categories []CategoryEntity) *CategoryTree {
+	tree := &CategoryTree{
+		categories: map[uuid.UUID]CategoryEntity{},
+		children:   map[uuid.UUID][]uuid.UUID{},
+	}
+	for _, c := range categories {
+		if c.DeletedAt == nil {
+			tree.categories[c.ID] = c
+		}
+	}
+	for _, c := range tree.categories {
+		if c.ParentID != nil {
+			if _, ok := tree.categories[*c.ParentID]; ok {
+				tree.children[*c.ParentID] = append(tree.children[*c.ParentID], c.ID)
+			}
+		}
+	}
+	for parent, ids := range tree.children {
+		sort.Slice(ids, func(i, j int) bool {
+			return strings.ToLower(tree.categories[ids[i]].Name) < strings.ToLower(tree.categories[ids[j]].Name)
+		})
+		tree.children[parent] = ids
+	}
+	return tree
+}
+
+func (t *CategoryTree) Find(id uuid.UUID) (CategoryEntity, bool) {
+	c, ok := t.categories[id]
+	return c, ok
+}
+
+// Ancestors returns the path from the top level category down to id, id included
+func (t *CategoryTree) Ancestors(id uuid.UUID) []CategoryEntity {
+	var path []CategoryEntity
+	seen := map[uuid.UUID]bool{}
+	for c, ok := t.categories[id]; ok && !seen[c.ID]; {
+		seen[c.ID] = true
+		path = append(path, c)
+		if c.ParentID == nil {
+			break
+		}
+		c, ok = t.categories[*c.ParentID]
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func (t *CategoryTree) Breadcrumb(id uuid.UUID) []CategoryCrumb {
+	crumbs := []CategoryCrumb{}
+	for _, c := range t.Ancestors(id) {
+		crumbs = append(crumbs, CategoryCrumb{ID: utils.EncodeBase62(c.ID.String()), Name: c.Name})
+	}
+	return crumbs
+}
+
+// Subtree returns id and the ids of all the categories below it
+func (t *CategoryTree) Subtree(id uuid.UUID) []uuid.UUID {
+	if _, ok := t.categories[id]; !ok {
+		return nil
+	}
+	ids := []uuid.UUID{id}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// IsWithin reports whether id is ancestor or one of the categories below it
+func (t *CategoryTree) IsWithin(id, ancestor uuid.UUID) bool {
+	for _, c := range t.Ancestors(id) {
+		if c.ID == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Node builds the category's subtree, nil when the category is not in the tree
+func (t *CategoryTree) Node(id uuid.UUID) *CategoryNode {
+	c, ok := t.categories[id]
+	if !ok {
+		return nil
+	}
+	return t.node(c, t.Breadcrumb(id), map[uuid.UUID]bool{})
+}
+
+func (t *CategoryTree) node(c CategoryEntity, breadcrumb []CategoryCrumb, seen map[uuid.UUID]bool) *CategoryNode {
+	seen[c.ID] = true
+	node := &CategoryNode{
+		ID:          utils.EncodeBase62(c.ID.String()),
+		Name:        c.Name,
+		Description: c.Description,
+		Breadcrumb:  breadcrumb,
+		Children:    []CategoryNode{},
+	}
+	for _, childID := range t.children[c.ID] {
+		if seen[childID] {
+			continue
+		}
+		child := t.categories[childID]
+		crumbs := append(append([]CategoryCrumb{}, breadcrumb...), CategoryCrumb{ID: utils.EncodeBase62(child.ID.String()), Name: child.Name})
+		node.Children = append(node.Children, *t.node(child, crumbs, seen))
+	}
+	return node
+}
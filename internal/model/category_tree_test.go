@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryTree(t *testing.T) {
+	beverages, coffee, espresso, latte, tea, deleted := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	deletedAt := time.Now()
+	tree := NewCategoryTree([]CategoryEntity{
+		{ID: espresso, ParentID: &coffee, Name: "Espresso"},
+		{ID: beverages, Name: "Beverages"},
+		{ID: latte, ParentID: &coffee, Name: "Latte"},
+		{ID: coffee, ParentID: &beverages, Name: "Coffee"},
+		{ID: tea, ParentID: &deleted, Name: "Tea"},
+		{ID: deleted, Name: "Old", DeletedAt: &deletedAt},
+	})
+
+	ancestors := tree.Ancestors(espresso)
+	require.Len(t, ancestors, 3)
+	assert.Equal(t, []string{"Beverages", "Coffee", "Espresso"}, []string{ancestors[0].Name, ancestors[1].Name, ancestors[2].Name})
+	assert.Equal(t, CategoryCrumb{ID: utils.EncodeBase62(coffee.String()), Name: "Coffee"}, tree.Breadcrumb(espresso)[1])
+	assert.Len(t, tree.Breadcrumb(tea), 1, "a category under a deleted one stands at the top level")
+
+	assert.ElementsMatch(t, []uuid.UUID{coffee, espresso, latte}, tree.Subtree(coffee))
+	assert.Nil(t, tree.Subtree(deleted))
+	assert.True(t, tree.IsWithin(latte, beverages))
+	assert.True(t, tree.IsWithin(coffee, coffee))
+	assert.False(t, tree.IsWithin(beverages, coffee))
+
+	node := tree.Node(beverages)
+	require.NotNil(t, node)
+	require.Len(t, node.Children, 1)
+	children := node.Children[0].Children
+	require.Len(t, children, 2)
+	assert.Equal(t, "Espresso", children[0].Name, "subcategories are sorted by name")
+	assert.Len(t, children[1].Breadcrumb, 3)
+	assert.Empty(t, children[1].Children)
+	assert.Nil(t, tree.Node(uuid.New()))
+}
+
+func TestCategoryTree_Cycle(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	tree := NewCategoryTree([]CategoryEntity{
+		{ID: a, ParentID: &b, Name: "A"},
+		{ID: b, ParentID: &a, Name: "B"},
+	})
+
+	assert.Len(t, tree.Ancestors(a), 2)
+	assert.ElementsMatch(t, []uuid.UUID{a, b}, tree.Subtree(a))
+	node := tree.Node(a)
+	require.Len(t, node.Children, 1)
+	assert.Empty(t, node.Children[0].Children)
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type CustomerEntity struct {
+	CreatedAt time.Time
+	CreatedBy string
+	UpdatedAt time.Time
+	UpdatedBy string
+	DeletedAt *time.Time
+	Version   int
+	ID        uuid.UUID //UUIDv7
+	Name      string
+	Phone     string // normalized, see NormalizePhone
+	Email     string
+	Notes     string
+
+	PointsBalance int // kept by the loyalty ledger
+	// PriceListID prices the customer's sales from the list, unless the sale names another one
+	PriceListID *uuid.UUID
+}
+
+type Customer struct {
+	ID            string     `json:"id"` //Base62 of UUIDv7
+	Name          string     `json:"name"`
+	Phone         string     `json:"phone,omitempty"`
+	Email         string     `json:"email,omitempty"`
+	Notes         string     `json:"notes,omitempty"`
+	PointsBalance int        `json:"points_balance"`
+	PriceListID   string     `json:"price_list_id,omitempty"` //Base62 of UUIDv7
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	Version       int        `json:"version,omitempty"`
+}
+
+func (c *CustomerEntity) ToModel() *Customer {
+	var priceListID string
+	if c.PriceListID != nil {
+		priceListID = utils.EncodeBase62(c.PriceListID.String())
+	}
+	return &Customer{
+		ID:            utils.EncodeBase62(c.ID.String()),
+		Name:          c.Name,
+		Phone:         c.Phone,
+		Email:         c.Email,
+		Notes:         c.Notes,
+		PointsBalance: c.PointsBalance,
+		PriceListID:   priceListID,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+		DeletedAt:     c.DeletedAt,
+		Version:       c.Version,
+	}
+}
+
+// NormalizePhone keeps the digits and a leading +, so "+62 812-3456" and "+628123456" find the same customer
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+	var b strings.Builder
+	for i, r := range phone {
+		if r >= '0' && r <= '9' || r == '+' && i == 0 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// TODO: add validation
+type CreateCustomerRequest struct {
+	Name        string `json:"name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	Notes       string `json:"notes"`
+	PriceListID string `json:"price_list_id"` //Base62 of UUIDv7, optional
+}
+
+func (c *CreateCustomerRequest) ToEntity() *CustomerEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	return &CustomerEntity{
+		ID:          id,
+		Name:        c.Name,
+		Phone:       NormalizePhone(c.Phone),
+		Email:       c.Email,
+		Notes:       c.Notes,
+		PriceListID: parseOptionalBase62(c.PriceListID),
+		CreatedBy:   "USER",
+		UpdatedBy:   "USER",
+	}
+}
+
+// TODO: add validation
+type UpdateCustomerRequest struct {
+	Name        string `json:"name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	Notes       string `json:"notes"`
+	PriceListID string `json:"price_list_id"` //Base62 of UUIDv7, empty takes the customer off any price list
+	Version     int    `json:"version"`
+}
+
+func (c *UpdateCustomerRequest) ToEntity() *CustomerEntity {
+	return &CustomerEntity{
+		Name:        c.Name,
+		Phone:       NormalizePhone(c.Phone),
+		Email:       c.Email,
+		Notes:       c.Notes,
+		PriceListID: parseOptionalBase62(c.PriceListID),
+		Version:     c.Version,
+		UpdatedBy:   "USER",
+	}
+}
+
+// CustomerHistory is a customer's purchases, newest first, with their lifetime totals
+type CustomerHistory struct {
+	Customer      Customer      `json:"customer"`
+	LifetimeSpend Price         `json:"lifetime_spend"`
+	VisitCount    int           `json:"visit_count"`
+	FirstVisitAt  *time.Time    `json:"first_visit_at,omitempty"`
+	LastVisitAt   *time.Time    `json:"last_visit_at,omitempty"`
+	Transactions  []Transaction `json:"transactions"`
+}
+
+// NewCustomerHistory totals the customer's transactions, every transaction counts as one visit
+func NewCustomerHistory(customer CustomerEntity, transactions []TransactionEntity) *CustomerHistory {
+	history := &CustomerHistory{
+		Customer:      *customer.ToModel(),
+		LifetimeSpend: Price{Currency: "IDR"},
+		Transactions:  []Transaction{},
+	}
+
+	for _, tx := range transactions {
+		history.Transactions = append(history.Transactions, *tx.ToModel())
+		history.LifetimeSpend.Amount += tx.TotalPriceAmount
+		history.VisitCount++
+
+		createdAt := tx.CreatedAt
+		if history.FirstVisitAt == nil || createdAt.Before(*history.FirstVisitAt) {
+			history.FirstVisitAt = &createdAt
+		}
+		if history.LastVisitAt == nil || createdAt.After(*history.LastVisitAt) {
+			history.LastVisitAt = &createdAt
+		}
+	}
+	history.LifetimeSpend.Display = float64(history.LifetimeSpend.Amount)
+	return history
+}
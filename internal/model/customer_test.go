@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerEntity_ToModel(t *testing.T) {
+	entity := &CustomerEntity{ID: uuid.New(), Name: "Budi", Phone: "+628123456", Version: 2, PointsBalance: 120}
+
+	customer := entity.ToModel()
+
+	require.NotNil(t, customer)
+	assert.NotEmpty(t, customer.ID)
+	assert.Equal(t, "Budi", customer.Name)
+	assert.Equal(t, "+628123456", customer.Phone)
+	assert.Equal(t, 2, customer.Version)
+	assert.Equal(t, 120, customer.PointsBalance)
+}
+
+func TestNormalizePhone(t *testing.T) {
+	assert.Equal(t, "+628123456", NormalizePhone(" +62 812-3456 "))
+	assert.Equal(t, "08123456", NormalizePhone("(0812) 3456"))
+	assert.Equal(t, "628123456", NormalizePhone("62+8123456"))
+	assert.Empty(t, NormalizePhone("  "))
+}
+
+func TestCreateCustomerRequest_ToEntity(t *testing.T) {
+	entity := (&CreateCustomerRequest{Name: "Budi", Phone: "0812-3456", Email: "budi@mail.id"}).ToEntity()
+
+	require.NotNil(t, entity)
+	assert.NotEqual(t, uuid.Nil, entity.ID)
+	assert.Equal(t, "08123456", entity.Phone)
+	assert.Equal(t, "USER", entity.CreatedBy)
+
+	updated := (&UpdateCustomerRequest{Name: "Budi", Phone: "0812 3456", Version: 3}).ToEntity()
+	assert.Equal(t, "08123456", updated.Phone)
+	assert.Equal(t, 3, updated.Version)
+}
+
+func TestNewCustomerHistory(t *testing.T) {
+	customerID := uuid.New()
+	first := time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, 0)
+
+	history := NewCustomerHistory(CustomerEntity{ID: customerID, Name: "Budi"}, []TransactionEntity{
+		{ID: uuid.New(), CreatedAt: last, TotalPriceAmount: 25000, CustomerID: &customerID},
+		{ID: uuid.New(), CreatedAt: first, TotalPriceAmount: 10000, CustomerID: &customerID},
+	})
+
+	assert.Equal(t, "Budi", history.Customer.Name)
+	assert.Equal(t, int64(35000), history.LifetimeSpend.Amount)
+	assert.Equal(t, 2, history.VisitCount)
+	assert.Equal(t, first, *history.FirstVisitAt)
+	assert.Equal(t, last, *history.LastVisitAt)
+	assert.Len(t, history.Transactions, 2)
+	assert.NotEmpty(t, history.Transactions[0].CustomerID)
+
+	empty := NewCustomerHistory(CustomerEntity{ID: customerID}, nil)
+	assert.Equal(t, 0, empty.VisitCount)
+	assert.Nil(t, empty.LastVisitAt)
+	assert.NotNil(t, empty.Transactions)
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// DiningTableEntity is a table of an F&B outlet, a draft order seated at it is the table's tab
+type DiningTableEntity struct {
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	Version      int
+	ID           uuid.UUID //UUIDv7
+	OutletID     *uuid.UUID
+	Area         string
+	Name         string
+	Seats        int
+	IsActive     bool
+	DraftOrderID *uuid.UUID // JOIN, the open or held draft order seated at the table
+}
+
+type DiningTable struct {
+	ID           string    `json:"id"` //Base62 of UUIDv7
+	OutletID     string    `json:"outlet_id,omitempty"`
+	Area         string    `json:"area,omitempty"`
+	Name         string    `json:"name"`
+	Seats        int       `json:"seats"`
+	IsActive     bool      `json:"is_active"`
+	Occupied     bool      `json:"occupied"`
+	DraftOrderID string    `json:"draft_order_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int       `json:"version,omitempty"`
+}
+
+func (t *DiningTableEntity) ToModel() *DiningTable {
+	var outletID, draftOrderID string
+	if t.OutletID != nil {
+		outletID = utils.EncodeBase62(t.OutletID.String())
+	}
+	if t.DraftOrderID != nil {
+		draftOrderID = utils.EncodeBase62(t.DraftOrderID.String())
+	}
+
+	return &DiningTable{
+		ID:           utils.EncodeBase62(t.ID.String()),
+		OutletID:     outletID,
+		Area:         t.Area,
+		Name:         t.Name,
+		Seats:        t.Seats,
+		IsActive:     t.IsActive,
+		Occupied:     t.DraftOrderID != nil,
+		DraftOrderID: draftOrderID,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+		Version:      t.Version,
+	}
+}
+
+// TODO: add validation
+type CreateDiningTableRequest struct {
+	OutletID string `json:"outlet_id"` //Base62 of UUIDv7, optional
+	Area     string `json:"area"`
+	Name     string `json:"name"`
+	Seats    int    `json:"seats"`
+	IsActive *bool  `json:"is_active"` // defaults to true
+}
+
+func (r *CreateDiningTableRequest) ToEntity() *DiningTableEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+	var outletID *uuid.UUID
+	if r.OutletID != "" {
+		parsed := parseBase62OrNil(r.OutletID)
+		outletID = &parsed
+	}
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+
+	return &DiningTableEntity{
+		ID:        id,
+		OutletID:  outletID,
+		Area:      strings.TrimSpace(r.Area),
+		Name:      strings.TrimSpace(r.Name),
+		Seats:     r.Seats,
+		IsActive:  isActive,
+		CreatedBy: "USER",
+		UpdatedBy: "USER",
+	}
+}
+
+// TODO: add validation
+type UpdateDiningTableRequest struct {
+	Area     string `json:"area"`
+	Name     string `json:"name"`
+	Seats    int    `json:"seats"`
+	IsActive bool   `json:"is_active"`
+	Version  int    `json:"version"`
+}
+
+func (r *UpdateDiningTableRequest) ToEntity() *DiningTableEntity {
+	return &DiningTableEntity{
+		Area:      strings.TrimSpace(r.Area),
+		Name:      strings.TrimSpace(r.Name),
+		Seats:     r.Seats,
+		IsActive:  r.IsActive,
+		Version:   r.Version,
+		UpdatedBy: "USER",
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDiningTableRequest_ToEntity(t *testing.T) {
+	outletID := uuid.New()
+	inactive := false
+
+	table := (&CreateDiningTableRequest{OutletID: utils.EncodeBase62(outletID.String()), Area: " Terrace ", Name: " 7 ", Seats: 4}).ToEntity()
+	require.NotNil(t, table)
+	assert.Equal(t, outletID, *table.OutletID)
+	assert.Equal(t, "Terrace", table.Area)
+	assert.Equal(t, "7", table.Name)
+	assert.True(t, table.IsActive)
+
+	table = (&CreateDiningTableRequest{Name: "T3", IsActive: &inactive}).ToEntity()
+	assert.Nil(t, table.OutletID)
+	assert.False(t, table.IsActive)
+}
+
+func TestDiningTableEntity_ToModel(t *testing.T) {
+	draftID := uuid.New()
+
+	free := (&DiningTableEntity{ID: uuid.New(), Name: "7", Seats: 4, IsActive: true}).ToModel()
+	assert.False(t, free.Occupied)
+	assert.Empty(t, free.DraftOrderID)
+
+	taken := (&DiningTableEntity{ID: uuid.New(), Name: "8", DraftOrderID: &draftID}).ToModel()
+	assert.True(t, taken.Occupied)
+	assert.Equal(t, utils.EncodeBase62(draftID.String()), taken.DraftOrderID)
+}
@@ -0,0 +1,257 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// DraftOrderOpen is on the register, DraftOrderHeld is parked under a label or table number
+	DraftOrderOpen      = "open"
+	DraftOrderHeld      = "held"
+	DraftOrderConverted = "converted"
+	DraftOrderCancelled = "cancelled"
+)
+
+// DraftOrderEntity is a parked order or an open table tab, it is priced and paid when it is converted to a transaction
+type DraftOrderEntity struct {
+	CreatedAt     time.Time
+	CreatedBy     string
+	UpdatedAt     time.Time
+	UpdatedBy     string
+	Version       int
+	ID            uuid.UUID //UUIDv7
+	Status        string
+	Label         string
+	TableNumber   string
+	TableID       *uuid.UUID // the dining table the draft is seated at, its name is the table number
+	OutletID      *uuid.UUID
+	CustomerID    *uuid.UUID
+	Notes         string
+	ReservesStock bool       // the items are held back from other sales while the draft is active
+	TransactionID *uuid.UUID // the sale the draft was converted into
+	Items         []DraftOrderItemEntity
+}
+
+type DraftOrderItemEntity struct {
+	DraftOrderID  uuid.UUID
+	ProductID     uuid.UUID
+	ProductName   string // JOIN from product table by product_id
+	Quantity      int
+	FiredQuantity int       // the units already sent to the kitchen
+	CreatedAt     time.Time // when the product was first added
+	CreatedBy     string
+}
+
+type DraftOrder struct {
+	ID            string           `json:"id"` //Base62 of UUIDv7
+	Status        string           `json:"status"`
+	Label         string           `json:"label,omitempty"`
+	TableNumber   string           `json:"table_number,omitempty"`
+	TableID       string           `json:"table_id,omitempty"`
+	OutletID      string           `json:"outlet_id,omitempty"`
+	CustomerID    string           `json:"customer_id,omitempty"`
+	Notes         string           `json:"notes,omitempty"`
+	ReservesStock bool             `json:"reserves_stock"`
+	TransactionID string           `json:"transaction_id,omitempty"`
+	TotalItems    int              `json:"total_items"`
+	Items         []DraftOrderItem `json:"items"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	Version       int              `json:"version,omitempty"`
+}
+
+type DraftOrderItem struct {
+	ProductID     string    `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	Quantity      int       `json:"quantity"`
+	FiredQuantity int       `json:"fired_quantity"`
+	AddedAt       time.Time `json:"added_at"`
+}
+
+func (d *DraftOrderEntity) ToModel() *DraftOrder {
+	var tableID, outletID, customerID, transactionID string
+	if d.TableID != nil {
+		tableID = utils.EncodeBase62(d.TableID.String())
+	}
+	if d.OutletID != nil {
+		outletID = utils.EncodeBase62(d.OutletID.String())
+	}
+	if d.CustomerID != nil {
+		customerID = utils.EncodeBase62(d.CustomerID.String())
+	}
+	if d.TransactionID != nil {
+		transactionID = utils.EncodeBase62(d.TransactionID.String())
+	}
+
+	draft := &DraftOrder{
+		ID:            utils.EncodeBase62(d.ID.String()),
+		Status:        d.Status,
+		Label:         d.Label,
+		TableNumber:   d.TableNumber,
+		TableID:       tableID,
+		OutletID:      outletID,
+		CustomerID:    customerID,
+		Notes:         d.Notes,
+		ReservesStock: d.ReservesStock,
+		TransactionID: transactionID,
+		Items:         []DraftOrderItem{},
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+		Version:       d.Version,
+	}
+	for _, i := range d.Items {
+		draft.TotalItems += i.Quantity
+		draft.Items = append(draft.Items, DraftOrderItem{
+			ProductID:     utils.EncodeBase62(i.ProductID.String()),
+			ProductName:   i.ProductName,
+			Quantity:      i.Quantity,
+			FiredQuantity: i.FiredQuantity,
+			AddedAt:       i.CreatedAt,
+		})
+	}
+	return draft
+}
+
+// IsActive tells whether the draft can still be changed, converted or cancelled
+func (d *DraftOrderEntity) IsActive() bool {
+	return d.Status == DraftOrderOpen || d.Status == DraftOrderHeld
+}
+
+// ItemQuantity returns how many units of the product are on the draft
+func (d *DraftOrderEntity) ItemQuantity(productID uuid.UUID) int {
+	for _, i := range d.Items {
+		if i.ProductID == productID {
+			return i.Quantity
+		}
+	}
+	return 0
+}
+
+// TODO: add validation
+type CreateDraftOrderRequest struct {
+	Label       string                     `json:"label"`
+	TableNumber string                     `json:"table_number"`
+	TableID     string                     `json:"table_id"`    //Base62 of UUIDv7, optional, seats the draft at the dining table
+	OutletID    string                     `json:"outlet_id"`   //Base62 of UUIDv7, optional
+	CustomerID  string                     `json:"customer_id"` //Base62 of UUIDv7, optional
+	Notes       string                     `json:"notes"`
+	Items       []AddDraftOrderItemRequest `json:"items"`
+}
+
+// ToEntity merges the lines of the same product, the draft holds one line per product
+func (r *CreateDraftOrderRequest) ToEntity() *DraftOrderEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+	var tableID, outletID, customerID *uuid.UUID
+	if r.TableID != "" {
+		parsed := parseBase62OrNil(r.TableID)
+		tableID = &parsed
+	}
+	if r.OutletID != "" {
+		parsed := parseBase62OrNil(r.OutletID)
+		outletID = &parsed
+	}
+	if r.CustomerID != "" {
+		parsed := parseBase62OrNil(r.CustomerID)
+		customerID = &parsed
+	}
+
+	draft := &DraftOrderEntity{
+		ID:          id,
+		Status:      DraftOrderOpen,
+		Label:       strings.TrimSpace(r.Label),
+		TableNumber: strings.TrimSpace(r.TableNumber),
+		TableID:     tableID,
+		OutletID:    outletID,
+		CustomerID:  customerID,
+		Notes:       r.Notes,
+		Items:       []DraftOrderItemEntity{},
+		CreatedBy:   "USER",
+		UpdatedBy:   "USER",
+	}
+	for _, item := range r.Items {
+		line := item.ToEntity(id)
+		if i := draft.indexOfItem(line.ProductID); i >= 0 {
+			draft.Items[i].Quantity += line.Quantity
+			continue
+		}
+		draft.Items = append(draft.Items, *line)
+	}
+	return draft
+}
+
+func (d *DraftOrderEntity) indexOfItem(productID uuid.UUID) int {
+	for i, item := range d.Items {
+		if item.ProductID == productID {
+			return i
+		}
+	}
+	return -1
+}
+
+// TODO: add validation
+type AddDraftOrderItemRequest struct {
+	ProductID string `json:"product_id"` //Base62 of UUIDv7
+	Quantity  int    `json:"quantity"`
+}
+
+func (r *AddDraftOrderItemRequest) ToEntity(draftOrderID uuid.UUID) *DraftOrderItemEntity {
+	return &DraftOrderItemEntity{
+		DraftOrderID: draftOrderID,
+		ProductID:    parseBase62OrNil(r.ProductID),
+		Quantity:     r.Quantity,
+		CreatedBy:    "USER",
+	}
+}
+
+// TODO: add validation
+type HoldDraftOrderRequest struct {
+	Label       string `json:"label"`        // keeps the draft's label when empty
+	TableNumber string `json:"table_number"` // keeps the draft's table number when empty
+	Notes       string `json:"notes"`
+}
+
+// TODO: add validation
+type AssignDraftOrderTableRequest struct {
+	TableID string `json:"table_id"` //Base62 of UUIDv7, moves the party to the dining table
+}
+
+// TODO: add validation
+type ConvertDraftOrderRequest struct {
+	// ShiftID is the drawer taking the payment, a tab opened on one shift can be paid on the next
+	ShiftID        string `json:"shift_id"` //Base62 of UUIDv7, optional
+	RedeemPoints   int    `json:"redeem_points"`
+	GiftCardCode   string `json:"gift_card_code"`
+	GiftCardAmount int64  `json:"gift_card_amount"`
+}
+
+// ToTransactionRequest rings the draft's items up as a sale at the draft's outlet for the draft's customer
+func (r *ConvertDraftOrderRequest) ToTransactionRequest(draft DraftOrderEntity) CreateTransactionRequest {
+	req := CreateTransactionRequest{
+		Items:          []CreateTransactionItemRequest{},
+		ShiftID:        r.ShiftID,
+		RedeemPoints:   r.RedeemPoints,
+		GiftCardCode:   r.GiftCardCode,
+		GiftCardAmount: r.GiftCardAmount,
+		DraftOrderID:   utils.EncodeBase62(draft.ID.String()),
+	}
+	if draft.OutletID != nil {
+		req.OutletID = utils.EncodeBase62(draft.OutletID.String())
+	}
+	if draft.CustomerID != nil {
+		req.CustomerID = utils.EncodeBase62(draft.CustomerID.String())
+	}
+	for _, i := range draft.Items {
+		req.Items = append(req.Items, CreateTransactionItemRequest{
+			ProductID:     utils.EncodeBase62(i.ProductID.String()),
+			StockQuantity: i.Quantity,
+		})
+	}
+	return req
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDraftOrderRequest_ToEntityMergesLines(t *testing.T) {
+	coffee, cake := uuid.New(), uuid.New()
+	request := CreateDraftOrderRequest{
+		Label:       " Budi ",
+		TableNumber: "7",
+		Items: []AddDraftOrderItemRequest{
+			{ProductID: utils.EncodeBase62(coffee.String()), Quantity: 1},
+			{ProductID: utils.EncodeBase62(cake.String()), Quantity: 2},
+			{ProductID: utils.EncodeBase62(coffee.String()), Quantity: 2},
+		},
+	}
+
+	draft := request.ToEntity()
+	require.NotNil(t, draft)
+	assert.Equal(t, DraftOrderOpen, draft.Status)
+	assert.Equal(t, "Budi", draft.Label)
+	assert.Nil(t, draft.OutletID)
+	require.Len(t, draft.Items, 2)
+	assert.Equal(t, 3, draft.ItemQuantity(coffee))
+	assert.Equal(t, 2, draft.ItemQuantity(cake))
+	assert.Equal(t, 0, draft.ItemQuantity(uuid.New()))
+	assert.Equal(t, draft.ID, draft.Items[0].DraftOrderID)
+}
+
+func TestDraftOrderEntity_ToModel(t *testing.T) {
+	txID := uuid.New()
+	draft := DraftOrderEntity{
+		ID:            uuid.New(),
+		Status:        DraftOrderConverted,
+		TableNumber:   "7",
+		TransactionID: &txID,
+		Items: []DraftOrderItemEntity{
+			{ProductID: uuid.New(), ProductName: "Kopi Susu", Quantity: 3},
+			{ProductID: uuid.New(), ProductName: "Brownies", Quantity: 2},
+		},
+	}
+
+	m := draft.ToModel()
+	assert.Equal(t, 5, m.TotalItems)
+	assert.Equal(t, utils.EncodeBase62(txID.String()), m.TransactionID)
+	assert.Equal(t, "Kopi Susu", m.Items[0].ProductName)
+	assert.False(t, draft.IsActive())
+	assert.True(t, (&DraftOrderEntity{Status: DraftOrderHeld}).IsActive())
+}
+
+func TestConvertDraftOrderRequest_ToTransactionRequest(t *testing.T) {
+	outletID, customerID, productID := uuid.New(), uuid.New(), uuid.New()
+	draft := DraftOrderEntity{
+		ID:         uuid.New(),
+		OutletID:   &outletID,
+		CustomerID: &customerID,
+		Items:      []DraftOrderItemEntity{{ProductID: productID, Quantity: 2}},
+	}
+	request := ConvertDraftOrderRequest{ShiftID: "s1", GiftCardCode: "ABCD"}
+
+	req := request.ToTransactionRequest(draft)
+	assert.Equal(t, utils.EncodeBase62(draft.ID.String()), req.DraftOrderID)
+	assert.Equal(t, utils.EncodeBase62(outletID.String()), req.OutletID)
+	assert.Equal(t, utils.EncodeBase62(customerID.String()), req.CustomerID)
+	assert.Equal(t, "s1", req.ShiftID)
+	assert.Equal(t, "ABCD", req.GiftCardCode)
+	assert.Equal(t, []CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), StockQuantity: 2}}, req.Items, "a draft line is already counted in stock units")
+}
+
+func TestProductEntity_UnreservedStocks(t *testing.T) {
+	beans, milk := uuid.New(), uuid.New()
+	product := ProductEntity{ID: beans, Stocks: 10}
+	assert.Equal(t, 7, product.UnreservedStocks(map[uuid.UUID]int{beans: 3}))
+	assert.Equal(t, 10, product.UnreservedStocks(nil))
+
+	bundle := ProductEntity{
+		ID:   uuid.New(),
+		Type: ProductTypeBundle,
+		Components: []BundleComponentEntity{
+			{ComponentID: beans, Quantity: 2, ComponentStocks: 10},
+			{ComponentID: milk, Quantity: 1, ComponentStocks: 4},
+		},
+	}
+	assert.Equal(t, 3, bundle.UnreservedStocks(map[uuid.UUID]int{beans: 4}), "6 beans left make 3")
+	assert.Equal(t, 0, bundle.UnreservedStocks(map[uuid.UUID]int{milk: 9}))
+	assert.Equal(t, 10, bundle.Components[0].ComponentStocks, "the product is left untouched")
+}
+
+func TestCreateDraftOrderRequest_ToEntitySeatsAtTable(t *testing.T) {
+	tableID := uuid.New()
+
+	draft := (&CreateDraftOrderRequest{TableID: utils.EncodeBase62(tableID.String())}).ToEntity()
+	require.NotNil(t, draft.TableID)
+	assert.Equal(t, tableID, *draft.TableID)
+	assert.Equal(t, utils.EncodeBase62(tableID.String()), draft.ToModel().TableID)
+}
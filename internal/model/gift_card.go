@@ -0,0 +1,180 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	GiftCardEntryIssue  = "issue"
+	GiftCardEntryTopUp  = "top_up"
+	GiftCardEntryRedeem = "redeem"
+)
+
+// GiftCardEntity is prepaid value tendered at checkout, store credit is a gift card held by a customer
+type GiftCardEntity struct {
+	CreatedAt     time.Time
+	CreatedBy     string
+	UpdatedAt     time.Time
+	UpdatedBy     string
+	Version       int
+	ID            uuid.UUID  //UUIDv7
+	Code          string     // normalized, see NormalizeGiftCardCode
+	CustomerID    *uuid.UUID // the holder of store credit, nil for a bearer card
+	InitialAmount int64      // IDR loaded when the card was issued
+	Balance       int64      // kept by the gift card ledger
+	ExpiresAt     *time.Time // nil never expires
+	IsActive      bool
+}
+
+type GiftCard struct {
+	ID            string     `json:"id"` //Base62 of UUIDv7
+	Code          string     `json:"code"`
+	CustomerID    string     `json:"customer_id,omitempty"`
+	InitialAmount Price      `json:"initial_amount"`
+	Balance       Price      `json:"balance"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Version       int        `json:"version,omitempty"`
+}
+
+func (g *GiftCardEntity) ToModel() *GiftCard {
+	var customerID string
+	if g.CustomerID != nil {
+		customerID = utils.EncodeBase62(g.CustomerID.String())
+	}
+
+	return &GiftCard{
+		ID:            utils.EncodeBase62(g.ID.String()),
+		Code:          g.Code,
+		CustomerID:    customerID,
+		InitialAmount: idrPrice(g.InitialAmount),
+		Balance:       idrPrice(g.Balance),
+		ExpiresAt:     g.ExpiresAt,
+		IsActive:      g.IsActive,
+		CreatedAt:     g.CreatedAt,
+		UpdatedAt:     g.UpdatedAt,
+		Version:       g.Version,
+	}
+}
+
+// IsRedeemable tells whether the card can pay for a sale at the given time
+func (g *GiftCardEntity) IsRedeemable(at time.Time) bool {
+	return g.IsActive && (g.ExpiresAt == nil || g.ExpiresAt.After(at))
+}
+
+// NormalizeGiftCardCode upper cases the code and drops spaces and dashes, so "abcd-1234" finds "ABCD1234"
+func NormalizeGiftCardCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r != ' ' && r != '-' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func idrPrice(amount int64) Price {
+	return Price{Amount: amount, Display: float64(amount), Currency: "IDR"}
+}
+
+// TODO: add validation
+type CreateGiftCardRequest struct {
+	Code       string     `json:"code"`        // generated when empty
+	CustomerID string     `json:"customer_id"` //Base62 of UUIDv7, optional
+	Amount     int64      `json:"amount"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+func (r *CreateGiftCardRequest) ToEntity() *GiftCardEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+	var customerID *uuid.UUID
+	if r.CustomerID != "" {
+		parsed := parseBase62OrNil(r.CustomerID)
+		customerID = &parsed
+	}
+
+	return &GiftCardEntity{
+		ID:            id,
+		Code:          NormalizeGiftCardCode(r.Code),
+		CustomerID:    customerID,
+		InitialAmount: r.Amount,
+		ExpiresAt:     r.ExpiresAt,
+		IsActive:      true,
+		CreatedBy:     "USER",
+		UpdatedBy:     "USER",
+	}
+}
+
+// TODO: add validation
+type TopUpGiftCardRequest struct {
+	Amount int64  `json:"amount"`
+	Notes  string `json:"notes"`
+}
+
+// GiftCardEntryEntity is one line of a gift card's append-only ledger
+type GiftCardEntryEntity struct {
+	ID            uuid.UUID //UUIDv7
+	GiftCardID    uuid.UUID
+	Type          string
+	Amount        int64 // signed IDR, negative takes value off the card
+	BalanceAfter  int64 // card balance right after this entry was applied
+	TransactionID *uuid.UUID
+	Notes         string
+	CreatedAt     time.Time
+	CreatedBy     string // the actor
+}
+
+type GiftCardEntry struct {
+	ID            string    `json:"id"` //Base62 of UUIDv7
+	Type          string    `json:"type"`
+	Amount        Price     `json:"amount"`
+	BalanceAfter  Price     `json:"balance_after"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Notes         string    `json:"notes,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	CreatedBy     string    `json:"created_by"`
+}
+
+func (e *GiftCardEntryEntity) ToModel() *GiftCardEntry {
+	var transactionID string
+	if e.TransactionID != nil {
+		transactionID = utils.EncodeBase62(e.TransactionID.String())
+	}
+
+	return &GiftCardEntry{
+		ID:            utils.EncodeBase62(e.ID.String()),
+		Type:          e.Type,
+		Amount:        idrPrice(e.Amount),
+		BalanceAfter:  idrPrice(e.BalanceAfter),
+		TransactionID: transactionID,
+		Notes:         e.Notes,
+		CreatedAt:     e.CreatedAt,
+		CreatedBy:     e.CreatedBy,
+	}
+}
+
+// GiftCardBalance is a card with its ledger, newest entry first
+type GiftCardBalance struct {
+	GiftCard
+	Entries []GiftCardEntry `json:"entries"`
+}
+
+func NewGiftCardBalance(card GiftCardEntity, entries []GiftCardEntryEntity) *GiftCardBalance {
+	balance := &GiftCardBalance{
+		GiftCard: *card.ToModel(),
+		Entries:  []GiftCardEntry{},
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		balance.Entries = append(balance.Entries, *entries[i].ToModel())
+	}
+	return balance
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGiftCardCode(t *testing.T) {
+	assert.Equal(t, "ABCD1234EFGH", NormalizeGiftCardCode(" abcd-1234 efgh "))
+	assert.Equal(t, "", NormalizeGiftCardCode(""))
+}
+
+func TestGiftCardEntity_IsRedeemable(t *testing.T) {
+	now := time.Now()
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	assert.True(t, (&GiftCardEntity{IsActive: true}).IsRedeemable(now))
+	assert.True(t, (&GiftCardEntity{IsActive: true, ExpiresAt: &later}).IsRedeemable(now))
+	assert.False(t, (&GiftCardEntity{IsActive: true, ExpiresAt: &earlier}).IsRedeemable(now))
+	assert.False(t, (&GiftCardEntity{IsActive: false}).IsRedeemable(now))
+}
+
+func TestCreateGiftCardRequest_ToEntity(t *testing.T) {
+	customerID := uuid.New()
+	request := &CreateGiftCardRequest{Code: "gift-01", CustomerID: utils.EncodeBase62(customerID.String()), Amount: 75000}
+
+	card := request.ToEntity()
+
+	require.NotNil(t, card)
+	assert.NotEqual(t, uuid.Nil, card.ID)
+	assert.Equal(t, "GIFT01", card.Code)
+	assert.Equal(t, &customerID, card.CustomerID)
+	assert.Equal(t, int64(75000), card.InitialAmount)
+	assert.True(t, card.IsActive)
+	assert.Nil(t, (&CreateGiftCardRequest{Amount: 1}).ToEntity().CustomerID, "a bearer card has no holder")
+}
+
+func TestNewGiftCardBalance(t *testing.T) {
+	card := GiftCardEntity{ID: uuid.New(), Code: "GIFT", InitialAmount: 50000, Balance: 20000, IsActive: true}
+	transactionID := uuid.New()
+	entries := []GiftCardEntryEntity{
+		{ID: uuid.New(), GiftCardID: card.ID, Type: GiftCardEntryIssue, Amount: 50000, BalanceAfter: 50000},
+		{ID: uuid.New(), GiftCardID: card.ID, Type: GiftCardEntryRedeem, Amount: -30000, BalanceAfter: 20000, TransactionID: &transactionID},
+	}
+
+	balance := NewGiftCardBalance(card, entries)
+
+	assert.Equal(t, "GIFT", balance.Code)
+	assert.Equal(t, Price{Amount: 20000, Display: 20000, Currency: "IDR"}, balance.Balance)
+	require.Len(t, balance.Entries, 2)
+	assert.Equal(t, GiftCardEntryRedeem, balance.Entries[0].Type, "newest first")
+	assert.Equal(t, utils.EncodeBase62(transactionID.String()), balance.Entries[0].TransactionID)
+	assert.Equal(t, int64(-30000), balance.Entries[0].Amount.Amount)
+}
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	KitchenTicketQueued    = "queued"
+	KitchenTicketPreparing = "preparing"
+	KitchenTicketReady     = "ready"
+	KitchenTicketServed    = "served"
+)
+
+// kitchenTicketFlow is the order a ticket moves through, it never moves back
+var kitchenTicketFlow = []string{KitchenTicketQueued, KitchenTicketPreparing, KitchenTicketReady, KitchenTicketServed}
+
+// KitchenStationEntity prepares the products of its categories, "Kitchen" for the food and "Bar" for the drinks
+type KitchenStationEntity struct {
+	CreatedAt   time.Time
+	CreatedBy   string
+	UpdatedAt   time.Time
+	UpdatedBy   string
+	Version     int
+	ID          uuid.UUID //UUIDv7
+	Name        string
+	IsActive    bool
+	CategoryIDs []uuid.UUID
+}
+
+type KitchenStation struct {
+	ID          string    `json:"id"` //Base62 of UUIDv7
+	Name        string    `json:"name"`
+	IsActive    bool      `json:"is_active"`
+	CategoryIDs []string  `json:"category_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int       `json:"version,omitempty"`
+}
+
+func (s *KitchenStationEntity) ToModel() *KitchenStation {
+	station := &KitchenStation{
+		ID:          utils.EncodeBase62(s.ID.String()),
+		Name:        s.Name,
+		IsActive:    s.IsActive,
+		CategoryIDs: []string{},
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+		Version:     s.Version,
+	}
+	for _, id := range s.CategoryIDs {
+		station.CategoryIDs = append(station.CategoryIDs, utils.EncodeBase62(id.String()))
+	}
+	return station
+}
+
+// TODO: add validation
+type CreateKitchenStationRequest struct {
+	Name        string   `json:"name"`
+	CategoryIDs []string `json:"category_ids"` //Base62 of UUIDv7, a category already routed moves to this station
+	IsActive    *bool    `json:"is_active"`    // defaults to true
+}
+
+func (r *CreateKitchenStationRequest) ToEntity() *KitchenStationEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return &KitchenStationEntity{
+		ID:          id,
+		Name:        strings.TrimSpace(r.Name),
+		IsActive:    isActive,
+		CategoryIDs: parseCategoryIDs(r.CategoryIDs),
+		CreatedBy:   "USER",
+		UpdatedBy:   "USER",
+	}
+}
+
+// TODO: add validation
+type UpdateKitchenStationRequest struct {
+	Name        string   `json:"name"`
+	IsActive    bool     `json:"is_active"`
+	CategoryIDs []string `json:"category_ids"` // replaces the station's categories
+	Version     int      `json:"version"`
+}
+
+func (r *UpdateKitchenStationRequest) ToEntity() *KitchenStationEntity {
+	return &KitchenStationEntity{
+		Name:        strings.TrimSpace(r.Name),
+		IsActive:    r.IsActive,
+		CategoryIDs: parseCategoryIDs(r.CategoryIDs),
+		Version:     r.Version,
+		UpdatedBy:   "USER",
+	}
+}
+
+func parseCategoryIDs(ids []string) []uuid.UUID {
+	parsed := []uuid.UUID{}
+	for _, id := range ids {
+		if categoryID := parseBase62OrNil(id); !slices.Contains(parsed, categoryID) {
+			parsed = append(parsed, categoryID)
+		}
+	}
+	return parsed
+}
+
+// KitchenTicketEntity is what one station prepares for one firing of a draft order
+type KitchenTicketEntity struct {
+	CreatedAt    time.Time // queued
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	Version      int
+	ID           uuid.UUID //UUIDv7
+	DraftOrderID uuid.UUID
+	StationID    uuid.UUID
+	StationName  string // JOIN from kitchen_station table by station_id
+	Label        string // the draft order's label and table number when the ticket was fired
+	TableNumber  string
+	Status       string
+	PreparingAt  *time.Time
+	ReadyAt      *time.Time
+	ServedAt     *time.Time
+	Items        []KitchenTicketItemEntity
+}
+
+type KitchenTicketItemEntity struct {
+	TicketID    uuid.UUID
+	ProductID   uuid.UUID
+	ProductName string // snapshotted when fired
+	Quantity    int
+}
+
+type KitchenTicket struct {
+	ID           string              `json:"id"` //Base62 of UUIDv7
+	DraftOrderID string              `json:"draft_order_id"`
+	StationID    string              `json:"station_id"`
+	Station      string              `json:"station"`
+	Label        string              `json:"label,omitempty"`
+	TableNumber  string              `json:"table_number,omitempty"`
+	Status       string              `json:"status"`
+	QueuedAt     time.Time           `json:"queued_at"`
+	PreparingAt  *time.Time          `json:"preparing_at,omitempty"`
+	ReadyAt      *time.Time          `json:"ready_at,omitempty"`
+	ServedAt     *time.Time          `json:"served_at,omitempty"`
+	Version      int                 `json:"version,omitempty"`
+	Items        []KitchenTicketItem `json:"items"`
+}
+
+type KitchenTicketItem struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+func (t *KitchenTicketEntity) ToModel() *KitchenTicket {
+	ticket := &KitchenTicket{
+		ID:           utils.EncodeBase62(t.ID.String()),
+		DraftOrderID: utils.EncodeBase62(t.DraftOrderID.String()),
+		StationID:    utils.EncodeBase62(t.StationID.String()),
+		Station:      t.StationName,
+		Label:        t.Label,
+		TableNumber:  t.TableNumber,
+		Status:       t.Status,
+		QueuedAt:     t.CreatedAt,
+		PreparingAt:  t.PreparingAt,
+		ReadyAt:      t.ReadyAt,
+		ServedAt:     t.ServedAt,
+		Version:      t.Version,
+		Items:        []KitchenTicketItem{},
+	}
+	for _, i := range t.Items {
+		ticket.Items = append(ticket.Items, KitchenTicketItem{
+			ProductID:   utils.EncodeBase62(i.ProductID.String()),
+			ProductName: i.ProductName,
+			Quantity:    i.Quantity,
+		})
+	}
+	return ticket
+}
+
+// CanMoveTo tells whether the ticket can go to the status, tickets only move forward but may skip a step
+func (t *KitchenTicketEntity) CanMoveTo(status string) bool {
+	to := slices.Index(kitchenTicketFlow, status)
+	return to >= 0 && to > slices.Index(kitchenTicketFlow, t.Status)
+}
+
+// IsKitchenTicketStatus tells whether status is one a ticket can be in
+func IsKitchenTicketStatus(status string) bool {
+	return slices.Contains(kitchenTicketFlow, status)
+}
+
+// NewKitchenTickets splits the part of the draft's lines not fired yet into one ticket per station.
+// stationOf maps a product to the station preparing it, products without one are not prepared.
+func NewKitchenTickets(draft DraftOrderEntity, stationOf map[uuid.UUID]uuid.UUID) []KitchenTicketEntity {
+	var tickets []KitchenTicketEntity
+	for _, item := range draft.Items {
+		stationID, ok := stationOf[item.ProductID]
+		quantity := item.Quantity - item.FiredQuantity
+		if !ok || quantity <= 0 {
+			continue
+		}
+
+		i := slices.IndexFunc(tickets, func(t KitchenTicketEntity) bool { return t.StationID == stationID })
+		if i < 0 {
+			id, _ := uuid.NewV7()
+			tickets = append(tickets, KitchenTicketEntity{
+				ID:           id,
+				DraftOrderID: draft.ID,
+				StationID:    stationID,
+				Label:        draft.Label,
+				TableNumber:  draft.TableNumber,
+				Status:       KitchenTicketQueued,
+				CreatedBy:    "USER",
+				UpdatedBy:    "USER",
+			})
+			i = len(tickets) - 1
+		}
+		tickets[i].Items = append(tickets[i].Items, KitchenTicketItemEntity{
+			TicketID:    tickets[i].ID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    quantity,
+		})
+	}
+	return tickets
+}
+
+// TODO: add validation
+type UpdateKitchenTicketStatusRequest struct {
+	Status string `json:"status"`
+}
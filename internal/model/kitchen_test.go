@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateKitchenStationRequest_ToEntity(t *testing.T) {
+	drinks := uuid.New()
+	request := CreateKitchenStationRequest{
+		Name:        " Bar ",
+		CategoryIDs: []string{utils.EncodeBase62(drinks.String()), utils.EncodeBase62(drinks.String())},
+	}
+
+	station := request.ToEntity()
+	require.NotNil(t, station)
+	assert.Equal(t, "Bar", station.Name)
+	assert.True(t, station.IsActive)
+	assert.Equal(t, []uuid.UUID{drinks}, station.CategoryIDs)
+	assert.Equal(t, []string{utils.EncodeBase62(drinks.String())}, station.ToModel().CategoryIDs)
+}
+
+func TestKitchenTicketEntity_CanMoveTo(t *testing.T) {
+	ticket := KitchenTicketEntity{Status: KitchenTicketPreparing}
+
+	assert.True(t, ticket.CanMoveTo(KitchenTicketReady))
+	assert.True(t, ticket.CanMoveTo(KitchenTicketServed))
+	assert.False(t, ticket.CanMoveTo(KitchenTicketPreparing))
+	assert.False(t, ticket.CanMoveTo(KitchenTicketQueued))
+	assert.False(t, ticket.CanMoveTo("burnt"))
+	assert.True(t, IsKitchenTicketStatus(KitchenTicketQueued))
+	assert.False(t, IsKitchenTicketStatus(""))
+}
+
+func TestNewKitchenTickets(t *testing.T) {
+	kitchen, bar := uuid.New(), uuid.New()
+	nasiGoreng, kopi, esTeh, kerupuk := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	draft := DraftOrderEntity{
+		ID:          uuid.New(),
+		TableNumber: "7",
+		Items: []DraftOrderItemEntity{
+			{ProductID: nasiGoreng, ProductName: "Nasi Goreng", Quantity: 2},
+			{ProductID: kopi, ProductName: "Kopi Susu", Quantity: 3, FiredQuantity: 1},
+			{ProductID: esTeh, ProductName: "Es Teh", Quantity: 1, FiredQuantity: 1},
+			{ProductID: kerupuk, ProductName: "Kerupuk", Quantity: 1},
+		},
+	}
+	stationOf := map[uuid.UUID]uuid.UUID{nasiGoreng: kitchen, kopi: bar, esTeh: bar}
+
+	tickets := NewKitchenTickets(draft, stationOf)
+	require.Len(t, tickets, 2)
+	assert.Equal(t, kitchen, tickets[0].StationID)
+	assert.Equal(t, KitchenTicketQueued, tickets[0].Status)
+	assert.Equal(t, "7", tickets[0].TableNumber)
+	assert.Equal(t, []KitchenTicketItemEntity{{TicketID: tickets[0].ID, ProductID: nasiGoreng, ProductName: "Nasi Goreng", Quantity: 2}}, tickets[0].Items)
+	assert.Equal(t, bar, tickets[1].StationID)
+	require.Len(t, tickets[1].Items, 1)
+	assert.Equal(t, 2, tickets[1].Items[0].Quantity)
+	assert.Equal(t, draft.ID, tickets[1].DraftOrderID)
+}
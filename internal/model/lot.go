@@ -0,0 +1,174 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar day, (un)marshalled as "2006-01-02"
+type Date struct {
+	time.Time
+}
+
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(dateLayout))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return err
+	}
+	d.Time = t
+	return nil
+}
+
+type ProductLotEntity struct {
+	ID                uuid.UUID //UUIDv7
+	ProductID         uuid.UUID
+	ProductName       string     // JOIN from product table by product_id
+	OutletID          *uuid.UUID // where the lot was received, nil when it is held at no outlet
+	LotNumber         string
+	ExpiryDate        time.Time // a calendar day, the lot can still be sold on it
+	QuantityReceived  int
+	QuantityRemaining int // moved by the stock movements booked against the lot
+	CreatedAt         time.Time
+	CreatedBy         string
+}
+
+// IsExpired reports whether the expiry date lies before the day of now
+func (l *ProductLotEntity) IsExpired(now time.Time) bool {
+	return NewDate(l.ExpiryDate).Before(NewDate(now).Time)
+}
+
+// DaysUntilExpiry is negative once the lot has expired
+func (l *ProductLotEntity) DaysUntilExpiry(now time.Time) int {
+	return int(NewDate(l.ExpiryDate).Sub(NewDate(now).Time).Hours() / 24)
+}
+
+type ProductLot struct {
+	ID                string    `json:"id"`         //Base62 of UUIDv7
+	ProductID         string    `json:"product_id"` //Base62 of UUIDv7
+	ProductName       string    `json:"product_name,omitempty"`
+	OutletID          string    `json:"outlet_id,omitempty"` //Base62 of UUIDv7
+	LotNumber         string    `json:"lot_number"`
+	ExpiryDate        Date      `json:"expiry_date"`
+	QuantityReceived  int       `json:"quantity_received"`
+	QuantityRemaining int       `json:"quantity_remaining"`
+	Expired           bool      `json:"expired"`
+	DaysUntilExpiry   int       `json:"days_until_expiry"`
+	CreatedAt         time.Time `json:"created_at"`
+	CreatedBy         string    `json:"created_by"`
+}
+
+func (l *ProductLotEntity) ToModel() *ProductLot {
+	var outletID string
+	if l.OutletID != nil {
+		outletID = utils.EncodeBase62(l.OutletID.String())
+	}
+
+	now := time.Now()
+	return &ProductLot{
+		ID:                utils.EncodeBase62(l.ID.String()),
+		ProductID:         utils.EncodeBase62(l.ProductID.String()),
+		ProductName:       l.ProductName,
+		OutletID:          outletID,
+		LotNumber:         l.LotNumber,
+		ExpiryDate:        NewDate(l.ExpiryDate),
+		QuantityReceived:  l.QuantityReceived,
+		QuantityRemaining: l.QuantityRemaining,
+		Expired:           l.IsExpired(now),
+		DaysUntilExpiry:   l.DaysUntilExpiry(now),
+		CreatedAt:         l.CreatedAt,
+		CreatedBy:         l.CreatedBy,
+	}
+}
+
+// LotAllocationEntity is the part of a sale taken out of one lot
+type LotAllocationEntity struct {
+	LotID     uuid.UUID
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+// ExpiredQuantity sums what is left in the expired lots, stock that can no longer be sold
+func ExpiredQuantity(lots []ProductLotEntity, now time.Time) int {
+	var expired int
+	for _, l := range lots {
+		if l.IsExpired(now) {
+			expired += l.QuantityRemaining
+		}
+	}
+	return expired
+}
+
+// AllocateFEFO takes quantity out of the unexpired lots, first expiry first out, and lowers
+// their remaining quantity in place. What the lots cannot cover is left unallocated, it comes
+// from stock that was never booked into a lot.
+func AllocateFEFO(lots []ProductLotEntity, quantity int, now time.Time) []LotAllocationEntity {
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].ExpiryDate.Before(lots[j].ExpiryDate)
+	})
+
+	var allocations []LotAllocationEntity
+	for i := range lots {
+		if quantity == 0 {
+			break
+		}
+		if lots[i].QuantityRemaining <= 0 || lots[i].IsExpired(now) {
+			continue
+		}
+		taken := min(quantity, lots[i].QuantityRemaining)
+		lots[i].QuantityRemaining -= taken
+		quantity -= taken
+		allocations = append(allocations, LotAllocationEntity{
+			LotID:     lots[i].ID,
+			ProductID: lots[i].ProductID,
+			Quantity:  taken,
+		})
+	}
+	return allocations
+}
+
+type CreateProductLotRequest struct {
+	OutletID   string `json:"outlet_id"` //Base62 of UUIDv7, optional
+	LotNumber  string `json:"lot_number"`
+	ExpiryDate Date   `json:"expiry_date"`
+	Quantity   int    `json:"quantity"`
+}
+
+func (r *CreateProductLotRequest) ToEntity(productID uuid.UUID) *ProductLotEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	return &ProductLotEntity{
+		ID:                id,
+		ProductID:         productID,
+		OutletID:          parseOptionalBase62(r.OutletID),
+		LotNumber:         r.LotNumber,
+		ExpiryDate:        r.ExpiryDate.Time,
+		QuantityReceived:  r.Quantity,
+		QuantityRemaining: r.Quantity,
+		CreatedBy:         "USER",
+	}
+}
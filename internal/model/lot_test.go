@@ -0,0 +1,86 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate_JSON(t *testing.T) {
+	var request CreateProductLotRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"lot_number":"A1","expiry_date":"2026-11-30","quantity":5}`), &request))
+	assert.Equal(t, time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC), request.ExpiryDate.Time)
+
+	data, err := json.Marshal(NewDate(time.Date(2026, 11, 30, 18, 45, 0, 0, time.Local)))
+	require.NoError(t, err)
+	assert.Equal(t, `"2026-11-30"`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"expiry_date":"30/11/2026"}`), &request))
+}
+
+func TestProductLotEntity_IsExpired(t *testing.T) {
+	now := time.Date(2026, 11, 10, 21, 0, 0, 0, time.UTC)
+
+	lot := ProductLotEntity{ExpiryDate: time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)}
+	assert.False(t, lot.IsExpired(now), "sellable through its expiry date")
+	assert.Equal(t, 0, lot.DaysUntilExpiry(now))
+
+	lot.ExpiryDate = time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC)
+	assert.True(t, lot.IsExpired(now))
+	assert.Equal(t, -1, lot.DaysUntilExpiry(now))
+
+	lot.ExpiryDate = time.Date(2026, 11, 17, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 7, lot.DaysUntilExpiry(now))
+}
+
+func TestAllocateFEFO(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+	productID := uuid.New()
+	lot := func(daysFromNow, remaining int) ProductLotEntity {
+		return ProductLotEntity{ID: uuid.New(), ProductID: productID, ExpiryDate: now.AddDate(0, 0, daysFromNow), QuantityRemaining: remaining}
+	}
+	late, expired, early := lot(5, 10), lot(-1, 4), lot(1, 3)
+	lots := []ProductLotEntity{late, expired, early}
+
+	assert.Equal(t, 4, ExpiredQuantity(lots, now))
+
+	allocations := AllocateFEFO(lots, 5, now)
+	assert.Equal(t, []LotAllocationEntity{
+		{LotID: early.ID, ProductID: productID, Quantity: 3},
+		{LotID: late.ID, ProductID: productID, Quantity: 2},
+	}, allocations)
+
+	allocations = AllocateFEFO(lots, 20, now)
+	assert.Equal(t, []LotAllocationEntity{{LotID: late.ID, ProductID: productID, Quantity: 8}}, allocations, "the rest is left unallocated")
+	assert.Equal(t, 4, ExpiredQuantity(lots, now), "expired lots are never consumed")
+}
+
+func TestCreateProductLotRequest_ToEntity(t *testing.T) {
+	productID := uuid.New()
+	request := CreateProductLotRequest{LotNumber: "A1", ExpiryDate: NewDate(time.Now()), Quantity: 6}
+
+	entity := request.ToEntity(productID)
+
+	assert.NotEqual(t, uuid.Nil, entity.ID)
+	assert.Equal(t, productID, entity.ProductID)
+	assert.Equal(t, 6, entity.QuantityReceived)
+	assert.Equal(t, 6, entity.QuantityRemaining)
+	assert.Equal(t, "USER", entity.CreatedBy)
+
+	lot := entity.ToModel()
+	assert.Equal(t, "A1", lot.LotNumber)
+	assert.False(t, lot.Expired)
+	assert.Empty(t, lot.OutletID)
+
+	outletID := uuid.New()
+	request.OutletID = utils.EncodeBase62(outletID.String())
+	entity = request.ToEntity(productID)
+	require.NotNil(t, entity.OutletID)
+	assert.Equal(t, outletID, *entity.OutletID)
+	assert.Equal(t, request.OutletID, entity.ToModel().OutletID)
+}
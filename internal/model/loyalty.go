@@ -0,0 +1,316 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	LoyaltyEntryEarn     = "earn"
+	LoyaltyEntryRedeem   = "redeem"
+	LoyaltyEntryExpire   = "expire"
+	LoyaltyEntryReversal = "reversal"
+)
+
+// LoyaltyProgramEntity is how a shop's customers earn and redeem points, the zero value runs no program
+type LoyaltyProgramEntity struct {
+	CreatedAt           time.Time
+	CreatedBy           string
+	UpdatedAt           time.Time
+	UpdatedBy           string
+	Version             int
+	IsActive            bool
+	SpendPerPoint       int64 // IDR spent to earn one point
+	PointValue          int64 // IDR one redeemed point takes off a sale
+	ExpiryDays          *int  // nil keeps points forever
+	CategoryMultipliers []LoyaltyCategoryMultiplierEntity
+}
+
+type LoyaltyCategoryMultiplierEntity struct {
+	CategoryID uuid.UUID
+	Multiplier float64 // 2 earns double on the category's lines, 0 earns nothing
+}
+
+type LoyaltyProgram struct {
+	IsActive            bool                        `json:"is_active"`
+	SpendPerPoint       int64                       `json:"spend_per_point"`
+	PointValue          int64                       `json:"point_value"`
+	ExpiryDays          *int                        `json:"expiry_days,omitempty"`
+	CategoryMultipliers []LoyaltyCategoryMultiplier `json:"category_multipliers"`
+	UpdatedAt           time.Time                   `json:"updated_at"`
+	Version             int                         `json:"version,omitempty"`
+}
+
+type LoyaltyCategoryMultiplier struct {
+	CategoryID string  `json:"category_id"` //Base62 of UUIDv7
+	Multiplier float64 `json:"multiplier"`
+}
+
+func (p *LoyaltyProgramEntity) ToModel() *LoyaltyProgram {
+	program := &LoyaltyProgram{
+		IsActive:            p.IsActive,
+		SpendPerPoint:       p.SpendPerPoint,
+		PointValue:          p.PointValue,
+		ExpiryDays:          p.ExpiryDays,
+		CategoryMultipliers: []LoyaltyCategoryMultiplier{},
+		UpdatedAt:           p.UpdatedAt,
+		Version:             p.Version,
+	}
+	for _, m := range p.CategoryMultipliers {
+		program.CategoryMultipliers = append(program.CategoryMultipliers, LoyaltyCategoryMultiplier{
+			CategoryID: utils.EncodeBase62(m.CategoryID.String()),
+			Multiplier: m.Multiplier,
+		})
+	}
+	return program
+}
+
+// EarnedPoints is the points a sale earns: each line's spend times its category multiplier, scaled down to
+// the share of the sale actually paid for so points redeemed on it earn nothing, per SpendPerPoint rounded down
+func (p *LoyaltyProgramEntity) EarnedPoints(details []TransactionDetailEntity, paidAmount int64) int {
+	if !p.IsActive || p.SpendPerPoint <= 0 {
+		return 0
+	}
+	var gross int64
+	var weighted float64
+	for _, d := range details {
+		gross += d.TotalPriceAmount
+		weighted += float64(d.TotalPriceAmount) * p.multiplier(d.CategoryID)
+	}
+	if gross <= 0 || paidAmount <= 0 {
+		return 0
+	}
+	return int(math.Floor(weighted * float64(paidAmount) / float64(gross) / float64(p.SpendPerPoint)))
+}
+
+// SpreadPointsDiscount takes the points discount off the lines in proportion to what each costs, so the
+// lines add up to what the sale charges and revenue per product or category is net of the discount.
+// Each line's share is of what is left, the last line takes the rounding and no line goes below zero.
+func SpreadPointsDiscount(details []TransactionDetailEntity, discount int64) {
+	var gross int64
+	for _, d := range details {
+		gross += max(d.TotalPriceAmount, 0)
+	}
+	if discount <= 0 || gross <= 0 {
+		return
+	}
+	discount = min(discount, gross)
+	for i := range details {
+		d := &details[i]
+		if d.TotalPriceAmount <= 0 {
+			continue
+		}
+		share := discount * d.TotalPriceAmount / gross
+		discount -= share
+		gross -= d.TotalPriceAmount
+		d.PointsDiscountAmount = share
+		d.TotalPriceAmount -= share
+		d.TotalPriceDisplay = float64(d.TotalPriceAmount)
+	}
+}
+
+func (p *LoyaltyProgramEntity) multiplier(categoryID *uuid.UUID) float64 {
+	if categoryID != nil {
+		for _, m := range p.CategoryMultipliers {
+			if m.CategoryID == *categoryID {
+				return m.Multiplier
+			}
+		}
+	}
+	return 1
+}
+
+// ExpiresAt is when points earned at the given time lapse, nil when the program keeps them forever
+func (p *LoyaltyProgramEntity) ExpiresAt(earnedAt time.Time) *time.Time {
+	if p.ExpiryDays == nil {
+		return nil
+	}
+	expiresAt := earnedAt.AddDate(0, 0, *p.ExpiryDays)
+	return &expiresAt
+}
+
+// TODO: add validation
+type UpdateLoyaltyProgramRequest struct {
+	IsActive            bool                        `json:"is_active"`
+	SpendPerPoint       int64                       `json:"spend_per_point"`
+	PointValue          int64                       `json:"point_value"`
+	ExpiryDays          *int                        `json:"expiry_days"`
+	CategoryMultipliers []LoyaltyCategoryMultiplier `json:"category_multipliers"`
+	Version             int                         `json:"version"`
+}
+
+func (r *UpdateLoyaltyProgramRequest) ToEntity() *LoyaltyProgramEntity {
+	program := &LoyaltyProgramEntity{
+		IsActive:      r.IsActive,
+		SpendPerPoint: r.SpendPerPoint,
+		PointValue:    r.PointValue,
+		ExpiryDays:    r.ExpiryDays,
+		Version:       r.Version,
+		CreatedBy:     "USER",
+		UpdatedBy:     "USER",
+	}
+	for _, m := range r.CategoryMultipliers {
+		program.CategoryMultipliers = append(program.CategoryMultipliers, LoyaltyCategoryMultiplierEntity{
+			CategoryID: parseBase62OrNil(m.CategoryID),
+			Multiplier: m.Multiplier,
+		})
+	}
+	return program
+}
+
+// LoyaltyEntryEntity is one line of a customer's append-only points ledger
+type LoyaltyEntryEntity struct {
+	ID               uuid.UUID //UUIDv7
+	CustomerID       uuid.UUID
+	Type             string
+	Points           int // signed, negative takes points out
+	BalanceAfter     int // customer balance right after this entry was applied
+	TransactionID    *uuid.UUID
+	ReferenceEntryID *uuid.UUID // the entry an expire or reversal entry applies to
+	ExpiresAt        *time.Time // when the points of a positive entry lapse
+	Notes            string
+	CreatedAt        time.Time
+	CreatedBy        string // the actor
+}
+
+type LoyaltyEntry struct {
+	ID               string     `json:"id"` //Base62 of UUIDv7
+	Type             string     `json:"type"`
+	Points           int        `json:"points"`
+	BalanceAfter     int        `json:"balance_after"`
+	TransactionID    string     `json:"transaction_id,omitempty"`
+	ReferenceEntryID string     `json:"reference_entry_id,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	Notes            string     `json:"notes,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	CreatedBy        string     `json:"created_by"`
+}
+
+func (e *LoyaltyEntryEntity) ToModel() *LoyaltyEntry {
+	var transactionID, referenceEntryID string
+	if e.TransactionID != nil {
+		transactionID = utils.EncodeBase62(e.TransactionID.String())
+	}
+	if e.ReferenceEntryID != nil {
+		referenceEntryID = utils.EncodeBase62(e.ReferenceEntryID.String())
+	}
+
+	return &LoyaltyEntry{
+		ID:               utils.EncodeBase62(e.ID.String()),
+		Type:             e.Type,
+		Points:           e.Points,
+		BalanceAfter:     e.BalanceAfter,
+		TransactionID:    transactionID,
+		ReferenceEntryID: referenceEntryID,
+		ExpiresAt:        e.ExpiresAt,
+		Notes:            e.Notes,
+		CreatedAt:        e.CreatedAt,
+		CreatedBy:        e.CreatedBy,
+	}
+}
+
+// LoyaltyPointLot is what is left of one positive ledger entry
+type LoyaltyPointLot struct {
+	EntryID   uuid.UUID
+	Remaining int
+	ExpiresAt *time.Time
+}
+
+// OpenPointLots replays the ledger, oldest entry first, and returns the points still held per positive entry.
+// An expire or reversal entry takes from the entry it references first, any other outflow takes from
+// the lots expiring soonest that had not lapsed when it was booked, so points are spent before they expire.
+func OpenPointLots(entries []LoyaltyEntryEntity) []LoyaltyPointLot {
+	var lots []LoyaltyPointLot
+	for _, e := range entries {
+		if e.Points > 0 {
+			lots = append(lots, LoyaltyPointLot{EntryID: e.ID, Remaining: e.Points, ExpiresAt: e.ExpiresAt})
+			continue
+		}
+
+		outflow := -e.Points
+		if e.ReferenceEntryID != nil {
+			for i := range lots {
+				if lots[i].EntryID == *e.ReferenceEntryID {
+					taken := min(outflow, lots[i].Remaining)
+					lots[i].Remaining -= taken
+					outflow -= taken
+				}
+			}
+		}
+		order := make([]int, 0, len(lots))
+		for i, lot := range lots {
+			if lot.Remaining > 0 && (lot.ExpiresAt == nil || lot.ExpiresAt.After(e.CreatedAt)) {
+				order = append(order, i)
+			}
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			ea, eb := lots[order[a]].ExpiresAt, lots[order[b]].ExpiresAt
+			return ea != nil && (eb == nil || ea.Before(*eb))
+		})
+		for _, i := range order {
+			if outflow == 0 {
+				break
+			}
+			taken := min(outflow, lots[i].Remaining)
+			lots[i].Remaining -= taken
+			outflow -= taken
+		}
+	}
+
+	open := []LoyaltyPointLot{}
+	for _, lot := range lots {
+		if lot.Remaining > 0 {
+			open = append(open, lot)
+		}
+	}
+	return open
+}
+
+// CustomerPoints is a customer's points balance with the points about to lapse and the ledger, newest first
+type CustomerPoints struct {
+	CustomerID string           `json:"customer_id"` //Base62 of UUIDv7
+	Balance    int              `json:"balance"`
+	Expiring   []ExpiringPoints `json:"expiring"`
+	Entries    []LoyaltyEntry   `json:"entries"`
+}
+
+type ExpiringPoints struct {
+	Points    int       `json:"points"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewCustomerPoints(customerID uuid.UUID, entries []LoyaltyEntryEntity) *CustomerPoints {
+	points := &CustomerPoints{
+		CustomerID: utils.EncodeBase62(customerID.String()),
+		Expiring:   []ExpiringPoints{},
+		Entries:    []LoyaltyEntry{},
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		points.Entries = append(points.Entries, *entries[i].ToModel())
+	}
+	if len(entries) > 0 {
+		points.Balance = entries[len(entries)-1].BalanceAfter
+	}
+
+	lots := OpenPointLots(entries)
+	sort.SliceStable(lots, func(a, b int) bool {
+		ea, eb := lots[a].ExpiresAt, lots[b].ExpiresAt
+		return ea != nil && (eb == nil || ea.Before(*eb))
+	})
+	for _, lot := range lots {
+		if lot.ExpiresAt != nil {
+			points.Expiring = append(points.Expiring, ExpiringPoints{Points: lot.Remaining, ExpiresAt: *lot.ExpiresAt})
+		}
+	}
+	return points
+}
+
+// TODO: add validation
+type CreateLoyaltyReversalRequest struct {
+	EntryID string `json:"entry_id"` //Base62 of UUIDv7
+	Notes   string `json:"notes"`
+}
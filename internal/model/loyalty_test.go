@@ -0,0 +1,137 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyProgramEntity_EarnedPoints(t *testing.T) {
+	drinks := uuid.New()
+	program := LoyaltyProgramEntity{
+		IsActive:      true,
+		SpendPerPoint: 1000,
+		CategoryMultipliers: []LoyaltyCategoryMultiplierEntity{
+			{CategoryID: drinks, Multiplier: 2},
+		},
+	}
+	details := []TransactionDetailEntity{
+		{TotalPriceAmount: 10500, CategoryID: &drinks},
+		{TotalPriceAmount: 5000},
+	}
+
+	assert.Equal(t, 26, program.EarnedPoints(details, 15500), "21 points on drinks plus 5 on the rest")
+	assert.Equal(t, 13, program.EarnedPoints(details, 7750), "half the sale paid with points earns half")
+	assert.Equal(t, 0, program.EarnedPoints(details, 0))
+
+	program.IsActive = false
+	assert.Equal(t, 0, program.EarnedPoints(details, 15500))
+}
+
+func TestSpreadPointsDiscount(t *testing.T) {
+	details := []TransactionDetailEntity{{TotalPriceAmount: 10000}, {TotalPriceAmount: 0}, {TotalPriceAmount: 20000}, {TotalPriceAmount: 3333}}
+	SpreadPointsDiscount(details, 1000)
+
+	var net, discounted int64
+	for _, d := range details {
+		net += d.TotalPriceAmount
+		discounted += d.PointsDiscountAmount
+	}
+	assert.Equal(t, int64(1000), discounted, "the shares add up to the discount")
+	assert.Equal(t, int64(32333), net)
+	assert.Equal(t, int64(300), details[0].PointsDiscountAmount)
+	assert.Equal(t, int64(9700), details[0].TotalPriceAmount)
+	assert.Zero(t, details[1].PointsDiscountAmount, "a free line takes no share")
+	assert.Equal(t, int64(600), details[2].PointsDiscountAmount)
+	assert.Equal(t, int64(100), details[3].PointsDiscountAmount, "the last line takes the rounding")
+
+	whole := []TransactionDetailEntity{{TotalPriceAmount: 1}, {TotalPriceAmount: 2}}
+	SpreadPointsDiscount(whole, 3)
+	assert.Zero(t, whole[0].TotalPriceAmount)
+	assert.Zero(t, whole[1].TotalPriceAmount)
+}
+
+func TestLoyaltyProgramEntity_ExpiresAt(t *testing.T) {
+	earnedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	assert.Nil(t, (&LoyaltyProgramEntity{}).ExpiresAt(earnedAt))
+
+	days := 30
+	expiresAt := (&LoyaltyProgramEntity{ExpiryDays: &days}).ExpiresAt(earnedAt)
+	require.NotNil(t, expiresAt)
+	assert.Equal(t, earnedAt.AddDate(0, 0, 30), *expiresAt)
+}
+
+func TestUpdateLoyaltyProgramRequest_ToEntity(t *testing.T) {
+	categoryID := uuid.New()
+	entity := (&UpdateLoyaltyProgramRequest{
+		IsActive:      true,
+		SpendPerPoint: 1000,
+		PointValue:    10,
+		CategoryMultipliers: []LoyaltyCategoryMultiplier{
+			{CategoryID: utils.EncodeBase62(categoryID.String()), Multiplier: 1.5},
+			{CategoryID: "???", Multiplier: 2},
+		},
+		Version: 2,
+	}).ToEntity()
+
+	assert.Equal(t, categoryID, entity.CategoryMultipliers[0].CategoryID)
+	assert.Equal(t, uuid.Nil, entity.CategoryMultipliers[1].CategoryID)
+	assert.Equal(t, 2, entity.Version)
+
+	program := entity.ToModel()
+	assert.Equal(t, utils.EncodeBase62(categoryID.String()), program.CategoryMultipliers[0].CategoryID)
+}
+
+func TestOpenPointLots(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	soon, later := start.AddDate(0, 1, 0), start.AddDate(0, 2, 0)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	entries := []LoyaltyEntryEntity{
+		{ID: first, Type: LoyaltyEntryEarn, Points: 30, ExpiresAt: &later, CreatedAt: start},
+		{ID: second, Type: LoyaltyEntryEarn, Points: 20, ExpiresAt: &soon, CreatedAt: start.AddDate(0, 0, 1)},
+		{ID: third, Type: LoyaltyEntryEarn, Points: 10, CreatedAt: start.AddDate(0, 0, 2)},
+		// spends the points expiring soonest first
+		{ID: uuid.New(), Type: LoyaltyEntryRedeem, Points: -25, CreatedAt: start.AddDate(0, 0, 3)},
+		// a reversal takes from the entry it reverses
+		{ID: uuid.New(), Type: LoyaltyEntryReversal, Points: -10, ReferenceEntryID: &third, CreatedAt: start.AddDate(0, 0, 4)},
+	}
+
+	lots := OpenPointLots(entries)
+	require.Len(t, lots, 1)
+	assert.Equal(t, first, lots[0].EntryID)
+	assert.Equal(t, 25, lots[0].Remaining)
+
+	// points that lapsed before a redemption are not spent by it
+	entries = []LoyaltyEntryEntity{
+		{ID: second, Type: LoyaltyEntryEarn, Points: 20, ExpiresAt: &soon, CreatedAt: start},
+		{ID: first, Type: LoyaltyEntryEarn, Points: 30, ExpiresAt: &later, CreatedAt: start},
+		{ID: uuid.New(), Type: LoyaltyEntryRedeem, Points: -5, CreatedAt: soon.AddDate(0, 0, 1)},
+	}
+	lots = OpenPointLots(entries)
+	require.Len(t, lots, 2)
+	assert.Equal(t, 20, lots[0].Remaining)
+	assert.Equal(t, 25, lots[1].Remaining)
+}
+
+func TestNewCustomerPoints(t *testing.T) {
+	customerID := uuid.New()
+	expiresAt := time.Now().AddDate(0, 1, 0)
+	entries := []LoyaltyEntryEntity{
+		{ID: uuid.New(), Type: LoyaltyEntryEarn, Points: 40, BalanceAfter: 40, ExpiresAt: &expiresAt},
+		{ID: uuid.New(), Type: LoyaltyEntryEarn, Points: 10, BalanceAfter: 50},
+		{ID: uuid.New(), Type: LoyaltyEntryRedeem, Points: -15, BalanceAfter: 35},
+	}
+
+	points := NewCustomerPoints(customerID, entries)
+
+	assert.Equal(t, 35, points.Balance)
+	require.Len(t, points.Expiring, 1)
+	assert.Equal(t, 25, points.Expiring[0].Points)
+	require.Len(t, points.Entries, 3)
+	assert.Equal(t, LoyaltyEntryRedeem, points.Entries[0].Type, "newest entry first")
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type OutletEntity struct {
+	CreatedAt time.Time
+	CreatedBy string
+	UpdatedAt time.Time
+	UpdatedBy string
+	DeletedAt *time.Time
+	Version   int
+	ID        uuid.UUID //UUIDv7
+	Code      string
+	Name      string
+	Address   string
+	IsActive  bool
+}
+
+type Outlet struct {
+	ID        string     `json:"id"` //Base62 of UUIDv7
+	Code      string     `json:"code"`
+	Name      string     `json:"name"`
+	Address   string     `json:"address,omitempty"`
+	IsActive  bool       `json:"is_active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Version   int        `json:"version,omitempty"`
+}
+
+func (o *OutletEntity) ToModel() *Outlet {
+	return &Outlet{
+		ID:        utils.EncodeBase62(o.ID.String()),
+		Code:      o.Code,
+		Name:      o.Name,
+		Address:   o.Address,
+		IsActive:  o.IsActive,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+		DeletedAt: o.DeletedAt,
+		Version:   o.Version,
+	}
+}
+
+// OutletStockEntity is what one outlet holds of a product and the price it sells it at
+type OutletStockEntity struct {
+	OutletID      uuid.UUID
+	ProductID     uuid.UUID
+	ProductName   string // JOIN from product table by product_id
+	ProductPrice  int64  // JOIN from product table by product_id
+	Stock         int
+	PriceOverride *int64 // nil sells at the product price
+	UpdatedAt     time.Time
+}
+
+// EffectivePrice returns the outlet's price override, or the product price without one
+func (s *OutletStockEntity) EffectivePrice() int64 {
+	if s.PriceOverride != nil {
+		return *s.PriceOverride
+	}
+	return s.ProductPrice
+}
+
+type OutletStock struct {
+	OutletID      string `json:"outlet_id"`  //Base62 of UUIDv7
+	ProductID     string `json:"product_id"` //Base62 of UUIDv7
+	ProductName   string `json:"product_name"`
+	Stock         int    `json:"stock"`
+	Price         int64  `json:"price"`
+	PriceOverride *int64 `json:"price_override"`
+}
+
+func (s *OutletStockEntity) ToModel() *OutletStock {
+	return &OutletStock{
+		OutletID:      utils.EncodeBase62(s.OutletID.String()),
+		ProductID:     utils.EncodeBase62(s.ProductID.String()),
+		ProductName:   s.ProductName,
+		Stock:         s.Stock,
+		Price:         s.EffectivePrice(),
+		PriceOverride: s.PriceOverride,
+	}
+}
+
+// TODO: add validation
+type CreateOutletRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	IsActive *bool  `json:"is_active"` // defaults to true
+}
+
+func (o *CreateOutletRequest) ToEntity() *OutletEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	isActive := true
+	if o.IsActive != nil {
+		isActive = *o.IsActive
+	}
+	return &OutletEntity{
+		ID:        id,
+		Code:      o.Code,
+		Name:      o.Name,
+		Address:   o.Address,
+		IsActive:  isActive,
+		CreatedBy: "USER",
+		UpdatedBy: "USER",
+	}
+}
+
+// TODO: add validation
+type UpdateOutletRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	IsActive bool   `json:"is_active"`
+	Version  int    `json:"version"`
+}
+
+func (o *UpdateOutletRequest) ToEntity() *OutletEntity {
+	return &OutletEntity{
+		Code:      o.Code,
+		Name:      o.Name,
+		Address:   o.Address,
+		IsActive:  o.IsActive,
+		Version:   o.Version,
+		UpdatedBy: "USER",
+	}
+}
+
+// UpdateOutletPriceRequest sets the outlet's price of a product, null goes back to the product price
+type UpdateOutletPriceRequest struct {
+	PriceOverride *int64 `json:"price_override"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutletStockEntity_EffectivePrice(t *testing.T) {
+	stock := &OutletStockEntity{OutletID: uuid.New(), ProductID: uuid.New(), ProductName: "Kopi", ProductPrice: 10000, Stock: 4}
+
+	assert.Equal(t, int64(10000), stock.EffectivePrice())
+	assert.Nil(t, stock.ToModel().PriceOverride)
+
+	override := int64(12000)
+	stock.PriceOverride = &override
+	assert.Equal(t, int64(12000), stock.EffectivePrice())
+
+	model := stock.ToModel()
+	assert.Equal(t, int64(12000), model.Price)
+	assert.Equal(t, 4, model.Stock)
+	assert.Equal(t, utils.EncodeBase62(stock.OutletID.String()), model.OutletID)
+}
+
+func TestCreateOutletRequest_ToEntity(t *testing.T) {
+	entity := (&CreateOutletRequest{Code: "JKT-01", Name: "Jakarta"}).ToEntity()
+
+	require.NotNil(t, entity)
+	assert.NotEqual(t, uuid.Nil, entity.ID)
+	assert.True(t, entity.IsActive, "outlets are active unless told otherwise")
+	assert.Equal(t, "USER", entity.CreatedBy)
+
+	inactive := false
+	entity = (&CreateOutletRequest{Code: "BDG-01", Name: "Bandung", IsActive: &inactive}).ToEntity()
+	assert.False(t, entity.IsActive)
+
+	model := entity.ToModel()
+	assert.Equal(t, "BDG-01", model.Code)
+	assert.Equal(t, utils.EncodeBase62(entity.ID.String()), model.ID)
+}
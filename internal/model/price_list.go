@@ -0,0 +1,198 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// PriceListEntity is a set of prices for a channel or a customer group, such as wholesale or member
+type PriceListEntity struct {
+	CreatedAt time.Time
+	CreatedBy string
+	UpdatedAt time.Time
+	UpdatedBy string
+	DeletedAt *time.Time
+	Version   int
+	ID        uuid.UUID //UUIDv7
+	Code      string
+	Name      string
+	IsActive  bool
+	ChangeSeq uint64 // the sync cursor of the last write, the id of the transaction that wrote it
+}
+
+type PriceList struct {
+	ID        string     `json:"id"` //Base62 of UUIDv7
+	Code      string     `json:"code"`
+	Name      string     `json:"name"`
+	IsActive  bool       `json:"is_active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Version   int        `json:"version,omitempty"`
+}
+
+func (l *PriceListEntity) ToModel() *PriceList {
+	return &PriceList{
+		ID:        utils.EncodeBase62(l.ID.String()),
+		Code:      l.Code,
+		Name:      l.Name,
+		IsActive:  l.IsActive,
+		CreatedAt: l.CreatedAt,
+		UpdatedAt: l.UpdatedAt,
+		DeletedAt: l.DeletedAt,
+		Version:   l.Version,
+	}
+}
+
+// CanPrice reports whether sales can still be priced from the list
+func (l *PriceListEntity) CanPrice() bool {
+	return l.IsActive && l.DeletedAt == nil
+}
+
+// TODO: add validation
+type CreatePriceListRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	IsActive *bool  `json:"is_active"` // defaults to true
+}
+
+func (l *CreatePriceListRequest) ToEntity() *PriceListEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	isActive := true
+	if l.IsActive != nil {
+		isActive = *l.IsActive
+	}
+	return &PriceListEntity{
+		ID:        id,
+		Code:      strings.TrimSpace(l.Code),
+		Name:      strings.TrimSpace(l.Name),
+		IsActive:  isActive,
+		CreatedBy: "USER",
+		UpdatedBy: "USER",
+	}
+}
+
+// TODO: add validation
+type UpdatePriceListRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+	Version  int    `json:"version"`
+}
+
+func (l *UpdatePriceListRequest) ToEntity() *PriceListEntity {
+	return &PriceListEntity{
+		Code:      strings.TrimSpace(l.Code),
+		Name:      strings.TrimSpace(l.Name),
+		IsActive:  l.IsActive,
+		Version:   l.Version,
+		UpdatedBy: "USER",
+	}
+}
+
+// ProductPriceEntity is a product's price from StartsAt on, until a later one of the same list takes over.
+// A nil PriceListID schedules the base price every sale without a price list pays.
+type ProductPriceEntity struct {
+	CreatedAt     time.Time
+	CreatedBy     string
+	DeletedAt     *time.Time
+	ID            uuid.UUID //UUIDv7
+	ProductID     uuid.UUID
+	PriceListID   *uuid.UUID
+	PriceListCode string // JOIN from price_list table by price_list_id
+	Price         int64
+	StartsAt      time.Time
+}
+
+type ProductPrice struct {
+	ID            string    `json:"id"`         //Base62 of UUIDv7
+	ProductID     string    `json:"product_id"` //Base62 of UUIDv7
+	PriceListID   string    `json:"price_list_id,omitempty"`
+	PriceListCode string    `json:"price_list_code,omitempty"`
+	Price         int64     `json:"price"`
+	StartsAt      time.Time `json:"starts_at"`
+	// Current marks the price in effect now, of the earlier ones of its list only the latest is
+	Current   bool      `json:"current"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (p *ProductPriceEntity) ToModel() *ProductPrice {
+	var priceListID string
+	if p.PriceListID != nil {
+		priceListID = utils.EncodeBase62(p.PriceListID.String())
+	}
+	return &ProductPrice{
+		ID:            utils.EncodeBase62(p.ID.String()),
+		ProductID:     utils.EncodeBase62(p.ProductID.String()),
+		PriceListID:   priceListID,
+		PriceListCode: p.PriceListCode,
+		Price:         p.Price,
+		StartsAt:      p.StartsAt,
+		CreatedAt:     p.CreatedAt,
+	}
+}
+
+// EffectiveProductPrice picks the price of the list in effect at the given time, the latest to have started.
+// A nil priceListID looks at the base price schedule. It returns nil when no price of the list has started yet.
+func EffectiveProductPrice(prices []ProductPriceEntity, priceListID *uuid.UUID, at time.Time) *ProductPriceEntity {
+	var effective *ProductPriceEntity
+	for i, p := range prices {
+		if p.DeletedAt != nil || p.StartsAt.After(at) || !samePriceList(p.PriceListID, priceListID) {
+			continue
+		}
+		if effective == nil || p.StartsAt.After(effective.StartsAt) {
+			effective = &prices[i]
+		}
+	}
+	return effective
+}
+
+func samePriceList(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// SchedulePriceRequest sets the product's price of the list, or its base price without one, from StartsAt on
+type SchedulePriceRequest struct {
+	PriceListID string     `json:"price_list_id"` //Base62 of UUIDv7, empty for the base price
+	Price       int64      `json:"price"`
+	StartsAt    *time.Time `json:"starts_at"` // defaults to now
+}
+
+func (r *SchedulePriceRequest) ToEntity(productID uuid.UUID) *ProductPriceEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	startsAt := time.Now()
+	if r.StartsAt != nil {
+		startsAt = *r.StartsAt
+	}
+	return &ProductPriceEntity{
+		ID:          id,
+		ProductID:   productID,
+		PriceListID: parseOptionalBase62(r.PriceListID),
+		Price:       r.Price,
+		StartsAt:    startsAt,
+		CreatedBy:   "USER",
+	}
+}
+
+// parseOptionalBase62 decodes an optional Base62 id, nil when it is empty and uuid.Nil when it does not decode
+func parseOptionalBase62(id string) *uuid.UUID {
+	if id == "" {
+		return nil
+	}
+	parsed := parseBase62OrNil(id)
+	return &parsed
+}
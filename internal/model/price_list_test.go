@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePriceListRequest_ToEntity(t *testing.T) {
+	entity := (&CreatePriceListRequest{Code: " GROSIR ", Name: "Wholesale"}).ToEntity()
+
+	require.NotNil(t, entity)
+	assert.NotEqual(t, uuid.Nil, entity.ID)
+	assert.Equal(t, "GROSIR", entity.Code)
+	assert.True(t, entity.IsActive, "price lists are active unless told otherwise")
+	assert.True(t, entity.CanPrice())
+
+	now := time.Now()
+	entity.DeletedAt = &now
+	assert.False(t, entity.CanPrice())
+}
+
+func TestEffectiveProductPrice(t *testing.T) {
+	listID := uuid.New()
+	now := time.Now()
+	cancelled := now.Add(-time.Minute)
+	prices := []ProductPriceEntity{
+		{ID: uuid.New(), Price: 10000, StartsAt: now.Add(-48 * time.Hour)},
+		{ID: uuid.New(), Price: 11000, StartsAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), Price: 12000, StartsAt: now.Add(24 * time.Hour)},
+		{ID: uuid.New(), Price: 9000, StartsAt: now.Add(-2 * time.Hour), PriceListID: &listID},
+		{ID: uuid.New(), Price: 8000, StartsAt: now.Add(-time.Minute), PriceListID: &listID, DeletedAt: &cancelled},
+	}
+
+	base := EffectiveProductPrice(prices, nil, now)
+	require.NotNil(t, base)
+	assert.Equal(t, int64(11000), base.Price, "the latest started base price wins")
+	assert.Equal(t, int64(12000), EffectiveProductPrice(prices, nil, now.Add(25*time.Hour)).Price)
+
+	listed := EffectiveProductPrice(prices, &listID, now)
+	require.NotNil(t, listed)
+	assert.Equal(t, int64(9000), listed.Price, "a cancelled price never takes effect")
+
+	other := uuid.New()
+	assert.Nil(t, EffectiveProductPrice(prices, &other, now))
+	assert.Nil(t, EffectiveProductPrice(prices, nil, now.Add(-72*time.Hour)))
+}
+
+func TestSchedulePriceRequest_ToEntity(t *testing.T) {
+	productID, listID := uuid.New(), uuid.New()
+	entity := (&SchedulePriceRequest{PriceListID: utils.EncodeBase62(listID.String()), Price: 15000}).ToEntity(productID)
+
+	require.NotNil(t, entity)
+	assert.Equal(t, productID, entity.ProductID)
+	require.NotNil(t, entity.PriceListID)
+	assert.Equal(t, listID, *entity.PriceListID)
+	assert.WithinDuration(t, time.Now(), entity.StartsAt, time.Second, "a price without starts_at takes effect now")
+
+	base := (&SchedulePriceRequest{Price: 15000}).ToEntity(productID)
+	assert.Nil(t, base.PriceListID)
+
+	model := entity.ToModel()
+	assert.Equal(t, utils.EncodeBase62(listID.String()), model.PriceListID)
+	assert.Equal(t, int64(15000), model.Price)
+}
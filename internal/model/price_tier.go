@@ -0,0 +1,83 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// ProductPriceTierEntity is the product's price per base unit on a line of MinQuantity stock units or more,
+// until the next tier's MinQuantity
+type ProductPriceTierEntity struct {
+	CreatedAt   time.Time
+	CreatedBy   string
+	ID          uuid.UUID //UUIDv7
+	ProductID   uuid.UUID
+	MinQuantity int
+	Price       int64
+}
+
+type ProductPriceTier struct {
+	ID          string `json:"id"`         //Base62 of UUIDv7
+	ProductID   string `json:"product_id"` //Base62 of UUIDv7
+	MinQuantity int    `json:"min_quantity"`
+	Price       int64  `json:"price"`
+}
+
+func (t *ProductPriceTierEntity) ToModel() *ProductPriceTier {
+	return &ProductPriceTier{
+		ID:          utils.EncodeBase62(t.ID.String()),
+		ProductID:   utils.EncodeBase62(t.ProductID.String()),
+		MinQuantity: t.MinQuantity,
+		Price:       t.Price,
+	}
+}
+
+// ApplicablePriceTier picks the tier a line of quantity units falls in, the one with the highest
+// MinQuantity the quantity reaches. It returns nil when the quantity is below every tier.
+func ApplicablePriceTier(tiers []ProductPriceTierEntity, quantity int) *ProductPriceTierEntity {
+	var applicable *ProductPriceTierEntity
+	for i, t := range tiers {
+		if t.MinQuantity > quantity {
+			continue
+		}
+		if applicable == nil || t.MinQuantity > applicable.MinQuantity {
+			applicable = &tiers[i]
+		}
+	}
+	return applicable
+}
+
+// TODO: add validation
+type PriceTierRequest struct {
+	MinQuantity int   `json:"min_quantity"`
+	Price       int64 `json:"price"`
+}
+
+// ReplacePriceTiersRequest is the product's whole tier table, an empty one removes the tiers
+type ReplacePriceTiersRequest struct {
+	Tiers []PriceTierRequest `json:"tiers"`
+}
+
+func (r *ReplacePriceTiersRequest) ToEntities(productID uuid.UUID) []ProductPriceTierEntity {
+	tiers := []ProductPriceTierEntity{}
+	for _, t := range r.Tiers {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil
+		}
+		tiers = append(tiers, ProductPriceTierEntity{
+			ID:          id,
+			ProductID:   productID,
+			MinQuantity: t.MinQuantity,
+			Price:       t.Price,
+			CreatedBy:   "USER",
+		})
+	}
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].MinQuantity < tiers[j].MinQuantity
+	})
+	return tiers
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicablePriceTier(t *testing.T) {
+	tiers := (&ReplacePriceTiersRequest{Tiers: []PriceTierRequest{
+		{MinQuantity: 12, Price: 3000},
+		{MinQuantity: 1, Price: 3500},
+		{MinQuantity: 48, Price: 2800},
+	}}).ToEntities(uuid.New())
+	require.Len(t, tiers, 3)
+	assert.Equal(t, []int{1, 12, 48}, []int{tiers[0].MinQuantity, tiers[1].MinQuantity, tiers[2].MinQuantity}, "tiers are kept by quantity")
+
+	assert.Equal(t, int64(3500), ApplicablePriceTier(tiers, 11).Price)
+	assert.Equal(t, int64(3000), ApplicablePriceTier(tiers, 12).Price)
+	assert.Equal(t, int64(2800), ApplicablePriceTier(tiers, 100).Price)
+	assert.Nil(t, ApplicablePriceTier(tiers[1:], 6), "a quantity below every tier pays the regular price")
+	assert.Nil(t, ApplicablePriceTier(nil, 6))
+}
+
+func TestTransactionDetailEntity_ToModel_PriceTier(t *testing.T) {
+	tier := 12
+	detail := (&TransactionDetailEntity{ProductName: "Telur", Quantity: 12, PriceAmount: 3000, PriceTierMinQuantity: &tier}).ToModel()
+	require.NotNil(t, detail.PriceTierMinQuantity)
+	assert.Equal(t, 12, *detail.PriceTierMinQuantity)
+}
@@ -1,12 +1,23 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"codewithumam-kasir-api/internal/utils"
 	"github.com/google/uuid"
 )
 
+const (
+	ProductTypeStandard = "standard"
+	ProductTypeBundle   = "bundle"
+
+	// BundlePricingFixed uses the bundle's own price as-is
+	BundlePricingFixed = "fixed"
+	// BundlePricingDerived sums the current component prices times their quantities
+	BundlePricingDerived = "derived"
+)
+
 // TODO: implement the metadata
 // TODO: implement the category relationship
 type ProductEntity struct {
@@ -16,46 +27,271 @@ type ProductEntity struct {
 	UpdatedBy    string
 	DeletedAt    *time.Time
 	Version      int
+	ChangeSeq    uint64    // the sync cursor of the last write, the id of the transaction that wrote it
 	ID           uuid.UUID //UUIDv7
 	Name         string
 	Price        int64
 	Stocks       int
 	CategoryID   *uuid.UUID
 	CategoryName string // JOIN from category table by category_id
+
+	Type          string
+	BundlePricing string
+	Components    []BundleComponentEntity // only populated for bundles
+
+	ReorderPoint    int // 0 disables low-stock alerts
+	ReorderQuantity int
+
+	CostPrice int64 // moving average purchase cost
+
+	SKU string // the store's own product code, empty when it has none
+
+	// Unit is the base unit stock is kept in. Stocks and every quantity of the product count
+	// 10^-QuantityScale of it, e.g. grams for a product sold by the kilo at scale 3,
+	// while Price and CostPrice stay per whole base unit.
+	Unit          string
+	QuantityScale int
+
+	Images []ProductImageEntity // by position, the first is the main picture
+}
+
+// BundleComponentEntity is a single product (and how many of it) contained in a bundle
+type BundleComponentEntity struct {
+	BundleID        uuid.UUID
+	ComponentID     uuid.UUID
+	ComponentName   string // JOIN from product table by component_id
+	ComponentPrice  int64  // JOIN from product table by component_id
+	ComponentStocks int    // JOIN from product table by component_id
+	ComponentCost   int64  // JOIN from product table by component_id
+	Quantity        int
+	CreatedAt       time.Time
+	CreatedBy       string
+}
+
+func (p *ProductEntity) IsBundle() bool {
+	return p.Type == ProductTypeBundle
+}
+
+// AvailableStocks returns how many units can be sold right now.
+// A bundle has no stock of its own, it is limited by its scarcest component.
+func (p *ProductEntity) AvailableStocks() int {
+	if !p.IsBundle() {
+		return p.Stocks
+	}
+	if len(p.Components) == 0 {
+		return 0
+	}
+
+	available := -1
+	for _, c := range p.Components {
+		if c.Quantity <= 0 {
+			return 0
+		}
+		n := c.ComponentStocks / c.Quantity
+		if available < 0 || n < available {
+			available = n
+		}
+	}
+	return max(available, 0)
+}
+
+// UnreservedStocks is what AvailableStocks leaves once the units held for draft orders are set aside.
+// reserved is keyed by the product holding the stock, a bundle's reservations sit on its components.
+func (p *ProductEntity) UnreservedStocks(reserved map[uuid.UUID]int) int {
+	unreserved := *p
+	unreserved.Stocks -= reserved[p.ID]
+	unreserved.Components = append([]BundleComponentEntity(nil), p.Components...)
+	for i, c := range unreserved.Components {
+		unreserved.Components[i].ComponentStocks = max(c.ComponentStocks-reserved[c.ComponentID], 0)
+	}
+	return unreserved.AvailableStocks()
+}
+
+// EffectiveCost returns the unit cost, a bundle costs the sum of its components
+func (p *ProductEntity) EffectiveCost() int64 {
+	if !p.IsBundle() {
+		return p.CostPrice
+	}
+
+	var total int64
+	for _, c := range p.Components {
+		total += c.ComponentCost * int64(c.Quantity)
+	}
+	return total
+}
+
+// StockValue is the stock on hand at cost, a bundle holds no stock of its own
+func (p *ProductEntity) StockValue() int64 {
+	if p.IsBundle() {
+		return 0
+	}
+	return RoundDiv(int64(max(p.Stocks, 0))*p.CostPrice, int64(QuantityMultiplier(p.QuantityScale)))
+}
+
+// MovingAverageCost returns the unit cost after receiving quantity units bought at unitCost.
+// Negative stock is treated as zero so a receipt after overselling is valued at its own cost.
+func (p *ProductEntity) MovingAverageCost(quantity int, unitCost int64) int64 {
+	onHand := int64(max(p.Stocks, 0))
+	if onHand+int64(quantity) <= 0 {
+		return unitCost
+	}
+	total := onHand*p.CostPrice + int64(quantity)*unitCost
+	return (total + (onHand+int64(quantity))/2) / (onHand + int64(quantity))
+}
+
+// IsLowStock reports whether the product sits at or below its reorder point
+func (p *ProductEntity) IsLowStock() bool {
+	return p.ReorderPoint > 0 && p.AvailableStocks() <= p.ReorderPoint
+}
+
+// EffectivePrice returns the selling price, deriving it from the components when the bundle asks for it
+func (p *ProductEntity) EffectivePrice() int64 {
+	if !p.IsBundle() || p.BundlePricing != BundlePricingDerived {
+		return p.Price
+	}
+
+	var total int64
+	for _, c := range p.Components {
+		total += c.ComponentPrice * int64(c.Quantity)
+	}
+	return total
 }
 
 type Product struct {
-	ID        string     `json:"id"` //Base62 of UUIDv7
-	Name      string     `json:"name"`
-	Price     int64      `json:"price"` //TODO: what is  the best way to represent price?
-	Stocks    int        `json:"stocks"`
-	Category  string     `json:"category,omitempty"` //category_name
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
-	Version   int        `json:"version,omitempty"`
+	ID         string     `json:"id"` //Base62 of UUIDv7
+	Name       string     `json:"name"`
+	Price      int64      `json:"price"` //TODO: what is  the best way to represent price?
+	Stocks     int        `json:"stocks"`
+	CategoryID string     `json:"category_id,omitempty"` //Base62 of UUIDv7
+	Category   string     `json:"category,omitempty"`    //category_name
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	Version    int        `json:"version,omitempty"`
+
+	Type          string            `json:"type,omitempty"`
+	BundlePricing string            `json:"bundle_pricing,omitempty"`
+	Components    []BundleComponent `json:"components,omitempty"`
+
+	ReorderPoint    int  `json:"reorder_point"`
+	ReorderQuantity int  `json:"reorder_quantity"`
+	LowStock        bool `json:"low_stock"`
+
+	CostPrice int64 `json:"cost_price"`
+
+	SKU string `json:"sku,omitempty"`
+
+	// Unit is the base unit, stocks count 10^-quantity_scale of it and price is per whole unit
+	Unit          string `json:"unit"`
+	QuantityScale int    `json:"quantity_scale"`
+
+	Images []ProductImage `json:"images,omitempty"`
+}
+
+type BundleComponent struct {
+	ProductID string `json:"product_id"` //Base62 of UUIDv7
+	Name      string `json:"name"`
+	Price     int64  `json:"price"`
+	Quantity  int    `json:"quantity"`
 }
 
 func (p *ProductEntity) ToModel() *Product {
-	return &Product{
-		ID:        utils.EncodeBase62(p.ID.String()),
-		Name:      p.Name,
-		Price:     p.Price,
-		Stocks:    p.Stocks,
-		Category:  p.CategoryName,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
-		DeletedAt: p.DeletedAt,
-		Version:   p.Version,
+	product := &Product{
+		ID:              utils.EncodeBase62(p.ID.String()),
+		Name:            p.Name,
+		Price:           p.EffectivePrice(),
+		Stocks:          p.AvailableStocks(),
+		Category:        p.CategoryName,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		DeletedAt:       p.DeletedAt,
+		Version:         p.Version,
+		Type:            p.Type,
+		BundlePricing:   p.BundlePricing,
+		ReorderPoint:    p.ReorderPoint,
+		ReorderQuantity: p.ReorderQuantity,
+		LowStock:        p.IsLowStock(),
+		CostPrice:       p.EffectiveCost(),
+		SKU:             p.SKU,
+		Unit:            p.Unit,
+		QuantityScale:   p.QuantityScale,
 	}
+	if p.CategoryID != nil {
+		product.CategoryID = utils.EncodeBase62(p.CategoryID.String())
+	}
+	for _, i := range p.Images {
+		product.Images = append(product.Images, *i.ToModel())
+	}
+	for _, c := range p.Components {
+		product.Components = append(product.Components, BundleComponent{
+			ProductID: utils.EncodeBase62(c.ComponentID.String()),
+			Name:      c.ComponentName,
+			Price:     c.ComponentPrice,
+			Quantity:  c.Quantity,
+		})
+	}
+	return product
+}
+
+type BundleComponentRequest struct {
+	ProductID string `json:"product_id"` //Base62 of UUIDv7
+	Quantity  int    `json:"quantity"`
+}
+
+// toBundleComponentEntities decodes the Base62 component ids, unparsable ids are kept as uuid.Nil so the service can reject them
+func toBundleComponentEntities(bundleID uuid.UUID, requests []BundleComponentRequest) []BundleComponentEntity {
+	var components []BundleComponentEntity
+	for _, c := range requests {
+		componentID, err := uuid.Parse(utils.DecodeBase62(c.ProductID))
+		if err != nil {
+			componentID = uuid.Nil
+		}
+		components = append(components, BundleComponentEntity{
+			BundleID:    bundleID,
+			ComponentID: componentID,
+			Quantity:    c.Quantity,
+			CreatedBy:   "USER",
+		})
+	}
+	return components
+}
+
+func unitOrDefault(unit string) string {
+	if strings.TrimSpace(unit) == "" {
+		return DefaultUnit
+	}
+	return strings.TrimSpace(unit)
+}
+
+func productTypeOrDefault(productType string) string {
+	if productType == "" {
+		return ProductTypeStandard
+	}
+	return productType
 }
 
 // TODO: add validation
 type CreateProductRequest struct {
-	Name     string `json:"name"`
-	Price    int64  `json:"price"`
-	Stocks   int    `json:"stocks"`
-	Category string `json:"category"`
+	Name   string `json:"name"`
+	SKU    string `json:"sku"`
+	Price  int64  `json:"price"`
+	Stocks int    `json:"stocks"`
+	// CategoryID picks the category, Category finds it by name instead when no id is given
+	CategoryID string `json:"category_id"`
+	Category   string `json:"category"`
+
+	Type          string                   `json:"type,omitempty"`
+	BundlePricing string                   `json:"bundle_pricing,omitempty"`
+	Components    []BundleComponentRequest `json:"components,omitempty"`
+
+	ReorderPoint    int `json:"reorder_point"`
+	ReorderQuantity int `json:"reorder_quantity"`
+
+	CostPrice int64 `json:"cost_price"`
+
+	Unit string `json:"unit"` // defaults to pcs
+	// QuantityScale is how many decimals a quantity of the unit can have, it cannot change later
+	QuantityScale int `json:"quantity_scale"`
 }
 
 func (p *CreateProductRequest) ToEntity() *ProductEntity {
@@ -65,32 +301,87 @@ func (p *CreateProductRequest) ToEntity() *ProductEntity {
 	}
 
 	return &ProductEntity{
-		ID:           id,
-		Name:         p.Name,
-		Price:        p.Price,
-		Stocks:       p.Stocks,
-		CategoryName: p.Category,
-		CreatedBy:    "USER",
-		UpdatedBy:    "USER",
+		ID:              id,
+		Name:            p.Name,
+		SKU:             strings.TrimSpace(p.SKU),
+		Price:           p.Price,
+		Stocks:          p.Stocks,
+		CategoryID:      parseOptionalBase62(p.CategoryID),
+		CategoryName:    strings.TrimSpace(p.Category),
+		Type:            productTypeOrDefault(p.Type),
+		BundlePricing:   p.BundlePricing,
+		Components:      toBundleComponentEntities(id, p.Components),
+		ReorderPoint:    p.ReorderPoint,
+		ReorderQuantity: p.ReorderQuantity,
+		CostPrice:       p.CostPrice,
+		Unit:            unitOrDefault(p.Unit),
+		QuantityScale:   p.QuantityScale,
+		CreatedBy:       "USER",
+		UpdatedBy:       "USER",
 	}
 }
 
 // TODO: add validation
 type UpdateProductRequest struct {
-	Name     string `json:"name"`
-	Price    int64  `json:"price"`
-	Stocks   int    `json:"stocks"`
-	Category string `json:"category"`
-	Version  int    `json:"version"`
+	Name   string `json:"name"`
+	SKU    string `json:"sku"`
+	Price  int64  `json:"price"`
+	Stocks int    `json:"stocks"`
+	// CategoryID picks the category, Category finds it by name instead when no id is given.
+	// Neither leaves the product uncategorized.
+	CategoryID string `json:"category_id"`
+	Category   string `json:"category"`
+	Version    int    `json:"version"`
+
+	Type          string                   `json:"type,omitempty"`
+	BundlePricing string                   `json:"bundle_pricing,omitempty"`
+	Components    []BundleComponentRequest `json:"components,omitempty"`
+
+	ReorderPoint    int `json:"reorder_point"`
+	ReorderQuantity int `json:"reorder_quantity"`
+
+	CostPrice int64 `json:"cost_price"`
+
+	Unit string `json:"unit"` // defaults to pcs
 }
 
 func (p *UpdateProductRequest) ToEntity() *ProductEntity {
 	return &ProductEntity{
-		Name:         p.Name,
-		Price:        p.Price,
-		Stocks:       p.Stocks,
-		CategoryName: p.Category,
-		Version:      p.Version,
-		UpdatedBy:    "USER",
+		Name:            p.Name,
+		SKU:             strings.TrimSpace(p.SKU),
+		Price:           p.Price,
+		Stocks:          p.Stocks,
+		CategoryID:      parseOptionalBase62(p.CategoryID),
+		CategoryName:    strings.TrimSpace(p.Category),
+		Type:            p.Type, // left out keeps the stored type
+		BundlePricing:   p.BundlePricing,
+		Components:      toBundleComponentEntities(uuid.Nil, p.Components),
+		ReorderPoint:    p.ReorderPoint,
+		ReorderQuantity: p.ReorderQuantity,
+		CostPrice:       p.CostPrice,
+		Unit:            unitOrDefault(p.Unit),
+		Version:         p.Version,
+		UpdatedBy:       "USER",
+	}
+}
+
+// LowStockAlert is the payload of the product.low_stock event and the items of GET /api/products/low-stock
+type LowStockAlert struct {
+	ProductID       string `json:"product_id"` //Base62 of UUIDv7
+	Name            string `json:"name"`
+	Category        string `json:"category,omitempty"`
+	Stocks          int    `json:"stocks"`
+	ReorderPoint    int    `json:"reorder_point"`
+	ReorderQuantity int    `json:"reorder_quantity"`
+}
+
+func (p *ProductEntity) ToLowStockAlert() *LowStockAlert {
+	return &LowStockAlert{
+		ProductID:       utils.EncodeBase62(p.ID.String()),
+		Name:            p.Name,
+		Category:        p.CategoryName,
+		Stocks:          p.AvailableStocks(),
+		ReorderPoint:    p.ReorderPoint,
+		ReorderQuantity: p.ReorderQuantity,
 	}
 }
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+const (
+	// ProductBatchAtomic applies every operation or none of them
+	ProductBatchAtomic = "atomic"
+	// ProductBatchBestEffort applies the operations that succeed and reports the rest
+	ProductBatchBestEffort = "best_effort"
+
+	ProductBatchCreate = "create"
+	ProductBatchUpdate = "update"
+	ProductBatchDelete = "delete"
+
+	ProductBatchSucceeded = "succeeded"
+	ProductBatchFailed    = "failed"
+	// ProductBatchSkipped is an operation left undone because an atomic batch failed elsewhere
+	ProductBatchSkipped = "skipped"
+)
+
+// ProductBatchOperationEntity is one write of a batch, Product carries the ID to update or delete
+type ProductBatchOperationEntity struct {
+	Op      string
+	Product ProductEntity
+}
+
+// ProductBatchResultEntity is what came of an operation, Error is empty when it succeeded
+type ProductBatchResultEntity struct {
+	Product ProductEntity
+	Error   string
+}
+
+// ProductBatchRequest is the body of POST /api/products:batch, Mode is atomic when empty
+type ProductBatchRequest struct {
+	Mode       string                  `json:"mode"`
+	Operations []ProductBatchOperation `json:"operations"`
+}
+
+// ProductBatchOperation is a create, update or delete. Product is the body POST or PUT /api/products would take.
+type ProductBatchOperation struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"` // the product to update or delete
+	Product json.RawMessage `json:"product,omitempty"`
+}
+
+// ToEntity decodes the product for the operation, ok is false when it does not decode
+func (o *ProductBatchOperation) ToEntity() (entity ProductBatchOperationEntity, ok bool) {
+	entity.Op = o.Op
+	switch o.Op {
+	case ProductBatchCreate:
+		var request CreateProductRequest
+		if json.Unmarshal(o.Product, &request) != nil {
+			return entity, false
+		}
+		product := request.ToEntity()
+		if product == nil {
+			return entity, false
+		}
+		entity.Product = *product
+	case ProductBatchUpdate:
+		var request UpdateProductRequest
+		if json.Unmarshal(o.Product, &request) != nil {
+			return entity, false
+		}
+		entity.Product = *request.ToEntity()
+	}
+	if o.Op != ProductBatchCreate {
+		entity.Product.ID = parseBase62OrNil(o.ID)
+		entity.Product.UpdatedBy = "USER"
+	}
+	return entity, true
+}
+
+type ProductBatchResult struct {
+	Index   int      `json:"index"`
+	Op      string   `json:"op"`
+	ID      string   `json:"id,omitempty"` //Base62 of UUIDv7
+	Status  string   `json:"status"`
+	Product *Product `json:"product,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ProductBatchResponse has a result per operation in request order, Committed is false when nothing was applied
+type ProductBatchResponse struct {
+	Mode      string               `json:"mode"`
+	Committed bool                 `json:"committed"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []ProductBatchResult `json:"results"`
+}
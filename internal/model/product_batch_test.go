@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductBatchOperation_ToEntity(t *testing.T) {
+	id := uuid.Must(uuid.NewV7())
+
+	create := ProductBatchOperation{Op: ProductBatchCreate, Product: json.RawMessage(`{"name":"Kopi","price":18000}`)}
+	entity, ok := create.ToEntity()
+	assert.True(t, ok)
+	assert.NotEqual(t, uuid.Nil, entity.Product.ID, "a created product gets a new id")
+	assert.Equal(t, ProductTypeStandard, entity.Product.Type)
+
+	update := ProductBatchOperation{Op: ProductBatchUpdate, ID: utils.EncodeBase62(id.String()), Product: json.RawMessage(`{"name":"Kopi","price":20000,"version":3}`)}
+	entity, ok = update.ToEntity()
+	assert.True(t, ok)
+	assert.Equal(t, id, entity.Product.ID)
+	assert.Equal(t, 3, entity.Product.Version)
+	assert.Empty(t, entity.Product.Type, "an update without a type keeps the stored one")
+
+	remove := ProductBatchOperation{Op: ProductBatchDelete, ID: utils.EncodeBase62(id.String())}
+	entity, ok = remove.ToEntity()
+	assert.True(t, ok)
+	assert.Equal(t, id, entity.Product.ID)
+	assert.Equal(t, "USER", entity.Product.UpdatedBy)
+
+	broken := ProductBatchOperation{Op: ProductBatchUpdate, ID: "x", Product: json.RawMessage(`{"price":"a lot"}`)}
+	_, ok = broken.ToEntity()
+	assert.False(t, ok)
+}
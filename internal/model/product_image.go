@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxProductImageSize caps an uploaded image file
+	MaxProductImageSize = 10 << 20
+	// MaxProductImagePixels refuses an image that would decode into an oversized bitmap, 40 megapixels
+	MaxProductImagePixels = 40_000_000
+	// ProductThumbnailSize is the longest side of a generated thumbnail in pixels
+	ProductThumbnailSize = 320
+)
+
+// ProductImageTypes maps the content types accepted for upload to the extension the file is kept with
+var ProductImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ProductImageEntity is a picture of the product, the file and its thumbnail are kept in the blob store
+type ProductImageEntity struct {
+	CreatedAt    time.Time
+	CreatedBy    string
+	ID           uuid.UUID //UUIDv7
+	ProductID    uuid.UUID
+	Position     int // the first one is the product's main picture
+	ContentType  string
+	Size         int64 // bytes of the uploaded file
+	Width        int
+	Height       int
+	Key          string // where the file is kept in the blob store
+	URL          string
+	ThumbnailKey string
+	ThumbnailURL string
+}
+
+// NewProductImageEntity names the keys of a new image of the product and its thumbnail
+func NewProductImageEntity(productID uuid.UUID, contentType string) (ProductImageEntity, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return ProductImageEntity{}, err
+	}
+	return ProductImageEntity{
+		ID:           id,
+		ProductID:    productID,
+		ContentType:  contentType,
+		Key:          fmt.Sprintf("products/%s/%s%s", productID, id, ProductImageTypes[contentType]),
+		ThumbnailKey: fmt.Sprintf("products/%s/%s_thumb.jpg", productID, id),
+		CreatedBy:    "USER",
+	}, nil
+}
+
+type ProductImage struct {
+	ID           string    `json:"id"` //Base62 of UUIDv7
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (i *ProductImageEntity) ToModel() *ProductImage {
+	return &ProductImage{
+		ID:           utils.EncodeBase62(i.ID.String()),
+		URL:          i.URL,
+		ThumbnailURL: i.ThumbnailURL,
+		ContentType:  i.ContentType,
+		Size:         i.Size,
+		Width:        i.Width,
+		Height:       i.Height,
+		Position:     i.Position,
+		CreatedAt:    i.CreatedAt,
+	}
+}
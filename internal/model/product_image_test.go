@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProductImageEntity(t *testing.T) {
+	productID := uuid.New()
+
+	image, err := NewProductImageEntity(productID, "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, productID, image.ProductID)
+	assert.Equal(t, "products/"+productID.String()+"/"+image.ID.String()+".png", image.Key)
+	assert.Equal(t, "products/"+productID.String()+"/"+image.ID.String()+"_thumb.jpg", image.ThumbnailKey)
+
+	other, err := NewProductImageEntity(productID, "image/png")
+	require.NoError(t, err)
+	assert.NotEqual(t, image.Key, other.Key)
+}
+
+func TestProductEntity_ToModel_Images(t *testing.T) {
+	now := time.Now()
+	image := ProductImageEntity{
+		ID: uuid.New(), ProductID: uuid.New(), Position: 1, ContentType: "image/jpeg", Size: 2048, Width: 800, Height: 600,
+		Key: "products/a/b.jpg", URL: "/media/products/a/b.jpg", ThumbnailURL: "/media/products/a/b_thumb.jpg", CreatedAt: now,
+	}
+
+	product := (&ProductEntity{ID: uuid.New(), Name: "Kopi", Images: []ProductImageEntity{image}}).ToModel()
+
+	require.Len(t, product.Images, 1)
+	assert.Equal(t, ProductImage{
+		ID: utils.EncodeBase62(image.ID.String()), URL: "/media/products/a/b.jpg", ThumbnailURL: "/media/products/a/b_thumb.jpg",
+		ContentType: "image/jpeg", Size: 2048, Width: 800, Height: 600, Position: 1, CreatedAt: now,
+	}, product.Images[0])
+	assert.Nil(t, (&ProductEntity{ID: uuid.New()}).ToModel().Images)
+}
@@ -0,0 +1,67 @@
+package model
+
+const (
+	CatalogFormatCSV  = "csv"
+	CatalogFormatXLSX = "xlsx"
+	CatalogFormatJSON = "json"
+)
+
+// ProductImportColumns are the header of an import or export sheet, in the order the export writes them.
+// An import may order them as it likes and leave out all but name.
+var ProductImportColumns = []string{"name", "sku", "category", "price", "stock", "cost_price", "reorder_point", "reorder_quantity"}
+
+// CatalogContentTypes maps each catalog file format to the content type it is sent with
+var CatalogContentTypes = map[string]string{
+	CatalogFormatCSV:  "text/csv",
+	CatalogFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	CatalogFormatJSON: "application/json",
+}
+
+// ProductImportRow is one product of an import file, also what the export writes.
+// A value left empty keeps what an existing product has, a new product takes the default.
+type ProductImportRow struct {
+	Name            string `json:"name"`
+	SKU             string `json:"sku,omitempty"`
+	Category        string `json:"category,omitempty"` // created when no category has the name
+	Price           *int64 `json:"price,omitempty"`    // required for a new product
+	Stock           *int   `json:"stock,omitempty"`    // the stock on hand, a change is booked as an adjustment
+	CostPrice       *int64 `json:"cost_price,omitempty"`
+	ReorderPoint    *int   `json:"reorder_point,omitempty"`
+	ReorderQuantity *int   `json:"reorder_quantity,omitempty"`
+}
+
+// ToImportRow is the product as the export writes it, a file that imports back unchanged
+func (p *ProductEntity) ToImportRow() ProductImportRow {
+	price, cost := p.EffectivePrice(), p.EffectiveCost()
+	row := ProductImportRow{
+		Name:            p.Name,
+		SKU:             p.SKU,
+		Category:        p.CategoryName,
+		Price:           &price,
+		CostPrice:       &cost,
+		ReorderPoint:    &p.ReorderPoint,
+		ReorderQuantity: &p.ReorderQuantity,
+	}
+	// a bundle's stock is its components', importing it back must not touch it
+	if !p.IsBundle() {
+		row.Stock = &p.Stocks
+	}
+	return row
+}
+
+type ProductImportReport struct {
+	DryRun            bool                 `json:"dry_run"`
+	TotalRows         int                  `json:"total_rows"`
+	Created           int                  `json:"created"`
+	Updated           int                  `json:"updated"`
+	Failed            int                  `json:"failed"`
+	CategoriesCreated []string             `json:"categories_created"`
+	Errors            []ProductImportError `json:"errors"`
+}
+
+// ProductImportError is why a row was not imported, Row is numbered as the file shows it
+type ProductImportError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
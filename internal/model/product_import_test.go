@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductEntity_ToImportRow(t *testing.T) {
+	kopi := ProductEntity{Name: "Kopi", SKU: "KP-01", CategoryName: "Drinks", Price: 18000, Stocks: 40, CostPrice: 9000, ReorderPoint: 10, ReorderQuantity: 24, Type: ProductTypeStandard}
+	row := kopi.ToImportRow()
+	assert.Equal(t, "KP-01", row.SKU)
+	assert.Equal(t, "Drinks", row.Category)
+	require.NotNil(t, row.Stock)
+	assert.Equal(t, 40, *row.Stock)
+	assert.Equal(t, int64(18000), *row.Price)
+	assert.Equal(t, int64(9000), *row.CostPrice)
+
+	paket := ProductEntity{Name: "Paket", Type: ProductTypeBundle, BundlePricing: BundlePricingDerived, Components: []BundleComponentEntity{
+		{Quantity: 2, ComponentPrice: 5000, ComponentCost: 3000},
+	}}
+	row = paket.ToImportRow()
+	assert.Nil(t, row.Stock, "a bundle's stock is left out")
+	assert.Equal(t, int64(10000), *row.Price)
+	assert.Equal(t, int64(6000), *row.CostPrice)
+}
+
+func TestCreateProductRequest_ToEntity_SKU(t *testing.T) {
+	req := CreateProductRequest{Name: "Kopi", SKU: "  KP-01 "}
+	assert.Equal(t, "KP-01", req.ToEntity().SKU)
+	assert.Equal(t, "KP-01", req.ToEntity().ToModel().SKU)
+}
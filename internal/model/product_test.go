@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"codewithumam-kasir-api/internal/utils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,6 +36,7 @@ func TestProductEntity_ToModel(t *testing.T) {
 	assert.Equal(t, int64(150000), model.Price)
 	assert.Equal(t, 10, model.Stocks)
 	assert.Equal(t, "Electronics", model.Category)
+	assert.Equal(t, utils.EncodeBase62(categoryID.String()), model.CategoryID)
 	assert.Equal(t, now, model.CreatedAt)
 	assert.Equal(t, now, model.UpdatedAt)
 	assert.Nil(t, model.DeletedAt)
@@ -103,3 +105,132 @@ func TestUpdateProductRequest_ToEntity(t *testing.T) {
 	assert.Equal(t, 5, entity.Version)
 	assert.Equal(t, "USER", entity.UpdatedBy)
 }
+
+func TestProductRequest_ToEntity_CategoryID(t *testing.T) {
+	categoryID := uuid.New()
+
+	create := (&CreateProductRequest{Name: "Mouse", CategoryID: utils.EncodeBase62(categoryID.String()), Category: " Accessories "}).ToEntity()
+	require.NotNil(t, create.CategoryID)
+	assert.Equal(t, categoryID, *create.CategoryID)
+	assert.Equal(t, "Accessories", create.CategoryName)
+
+	update := (&UpdateProductRequest{Name: "Mouse", CategoryID: "not-an-id"}).ToEntity()
+	require.NotNil(t, update.CategoryID, "a malformed id is kept so it fails the lookup")
+	assert.Equal(t, uuid.Nil, *update.CategoryID)
+
+	assert.Nil(t, (&UpdateProductRequest{Name: "Mouse", Category: "Accessories"}).ToEntity().CategoryID)
+}
+
+func TestProductEntity_Bundle(t *testing.T) {
+	entity := &ProductEntity{
+		ID:            uuid.New(),
+		Name:          "Paket Hemat",
+		Price:         20000,
+		Type:          ProductTypeBundle,
+		BundlePricing: BundlePricingDerived,
+		Components: []BundleComponentEntity{
+			{ComponentID: uuid.New(), ComponentName: "Nasi", ComponentPrice: 5000, ComponentStocks: 9, Quantity: 2},
+			{ComponentID: uuid.New(), ComponentName: "Es Teh", ComponentPrice: 3000, ComponentStocks: 10, Quantity: 1},
+		},
+	}
+
+	assert.True(t, entity.IsBundle())
+	assert.Equal(t, 4, entity.AvailableStocks())
+	assert.Equal(t, int64(13000), entity.EffectivePrice())
+
+	entity.BundlePricing = BundlePricingFixed
+	assert.Equal(t, int64(20000), entity.EffectivePrice())
+
+	model := entity.ToModel()
+	assert.Equal(t, 4, model.Stocks)
+	assert.Equal(t, ProductTypeBundle, model.Type)
+	require.Len(t, model.Components, 2)
+	assert.Equal(t, "Nasi", model.Components[0].Name)
+
+	entity.Components = nil
+	assert.Equal(t, 0, entity.AvailableStocks())
+}
+
+func TestCreateProductRequest_ToEntity_Bundle(t *testing.T) {
+	componentID := uuid.New()
+	req := &CreateProductRequest{
+		Name:          "Paket Hemat",
+		Price:         20000,
+		Type:          ProductTypeBundle,
+		BundlePricing: BundlePricingFixed,
+		Components: []BundleComponentRequest{
+			{ProductID: utils.EncodeBase62(componentID.String()), Quantity: 2},
+			{ProductID: "not-a-product", Quantity: 1},
+		},
+	}
+
+	entity := req.ToEntity()
+
+	require.Len(t, entity.Components, 2)
+	assert.Equal(t, entity.ID, entity.Components[0].BundleID)
+	assert.Equal(t, componentID, entity.Components[0].ComponentID)
+	assert.Equal(t, 2, entity.Components[0].Quantity)
+	assert.Equal(t, uuid.Nil, entity.Components[1].ComponentID)
+
+	assert.Equal(t, ProductTypeStandard, (&CreateProductRequest{Name: "Plain"}).ToEntity().Type)
+}
+
+func TestProductEntity_IsLowStock(t *testing.T) {
+	entity := &ProductEntity{ID: uuid.New(), Name: "Kopi", Stocks: 5, ReorderPoint: 5, ReorderQuantity: 24}
+
+	assert.True(t, entity.IsLowStock())
+	assert.True(t, entity.ToModel().LowStock)
+
+	alert := entity.ToLowStockAlert()
+	assert.Equal(t, "Kopi", alert.Name)
+	assert.Equal(t, 5, alert.Stocks)
+	assert.Equal(t, 24, alert.ReorderQuantity)
+
+	entity.Stocks = 6
+	assert.False(t, entity.IsLowStock())
+
+	entity.Stocks, entity.ReorderPoint = 0, 0
+	assert.False(t, entity.IsLowStock())
+}
+
+func TestProductEntity_EffectiveCost(t *testing.T) {
+	standard := ProductEntity{CostPrice: 4000}
+	assert.Equal(t, int64(4000), standard.EffectiveCost())
+
+	bundle := ProductEntity{
+		Type:      ProductTypeBundle,
+		CostPrice: 99999,
+		Components: []BundleComponentEntity{
+			{Quantity: 2, ComponentCost: 1500},
+			{Quantity: 1, ComponentCost: 3000},
+		},
+	}
+	assert.Equal(t, int64(6000), bundle.EffectiveCost())
+}
+
+func TestProductEntity_MovingAverageCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		product  ProductEntity
+		quantity int
+		unitCost int64
+		want     int64
+	}{
+		{"empty stock takes receipt cost", ProductEntity{Stocks: 0, CostPrice: 0}, 10, 5000, 5000},
+		{"weighted by quantity", ProductEntity{Stocks: 10, CostPrice: 4000}, 10, 6000, 5000},
+		{"rounded to nearest", ProductEntity{Stocks: 2, CostPrice: 0}, 1, 1000, 333},
+		{"negative stock ignored", ProductEntity{Stocks: -5, CostPrice: 9000}, 5, 2000, 2000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.product.MovingAverageCost(tt.quantity, tt.unitCost))
+		})
+	}
+}
+
+func TestProductEntity_StockValue(t *testing.T) {
+	assert.Equal(t, int64(50000), (&ProductEntity{Stocks: 10, CostPrice: 5000}).StockValue())
+	assert.Equal(t, int64(7500), (&ProductEntity{Stocks: 750, CostPrice: 10000, QuantityScale: 3}).StockValue(), "750 grams at 10000 a kilo")
+	assert.Equal(t, int64(0), (&ProductEntity{Stocks: -2, CostPrice: 5000}).StockValue(), "oversold stock is worth nothing")
+	assert.Equal(t, int64(0), (&ProductEntity{Type: ProductTypeBundle, Stocks: 3, CostPrice: 5000}).StockValue())
+}
@@ -0,0 +1,290 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderClosed            = "closed"
+)
+
+type PurchaseOrderEntity struct {
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	DeletedAt    *time.Time
+	Version      int
+	ID           uuid.UUID //UUIDv7
+	SupplierID   uuid.UUID
+	SupplierName string // JOIN from supplier table by supplier_id
+	Status       string
+	Notes        string
+	SentAt       *time.Time
+	ClosedAt     *time.Time
+	Lines        []PurchaseOrderLineEntity
+	Receipts     []GoodsReceiptEntity
+}
+
+type PurchaseOrderLineEntity struct {
+	PurchaseOrderID  uuid.UUID
+	ProductID        uuid.UUID
+	ProductName      string // JOIN from product table by product_id
+	QuantityOrdered  int
+	QuantityReceived int
+	CostPrice        int64
+}
+
+// Outstanding is how many units are still expected from the supplier
+func (l *PurchaseOrderLineEntity) Outstanding() int {
+	return max(l.QuantityOrdered-l.QuantityReceived, 0)
+}
+
+// TotalCost is the ordered quantity times the cost price of every line
+func (p *PurchaseOrderEntity) TotalCost() int64 {
+	var total int64
+	for _, l := range p.Lines {
+		total += l.CostPrice * int64(l.QuantityOrdered)
+	}
+	return total
+}
+
+// IsFullyReceived reports whether nothing is outstanding on any line
+func (p *PurchaseOrderEntity) IsFullyReceived() bool {
+	for _, l := range p.Lines {
+		if l.Outstanding() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GoodsReceiptEntity is one delivery booked against a purchase order
+type GoodsReceiptEntity struct {
+	ID              uuid.UUID //UUIDv7
+	PurchaseOrderID uuid.UUID
+	OutletID        *uuid.UUID // nil books the goods into stock not held at any outlet
+	OutletName      string     // JOIN from outlet table by outlet_id
+	Notes           string
+	ReceivedAt      time.Time
+	ReceivedBy      string
+	Lines           []GoodsReceiptLineEntity
+}
+
+type GoodsReceiptLineEntity struct {
+	GoodsReceiptID uuid.UUID
+	ProductID      uuid.UUID
+	ProductName    string // JOIN from product table by product_id
+	Quantity       int
+}
+
+type PurchaseOrder struct {
+	ID         string              `json:"id"` //Base62 of UUIDv7
+	SupplierID string              `json:"supplier_id"`
+	Supplier   string              `json:"supplier"`
+	Status     string              `json:"status"`
+	Notes      string              `json:"notes,omitempty"`
+	TotalCost  int64               `json:"total_cost"`
+	CreatedAt  time.Time           `json:"created_at"`
+	CreatedBy  string              `json:"created_by"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+	SentAt     *time.Time          `json:"sent_at,omitempty"`
+	ClosedAt   *time.Time          `json:"closed_at,omitempty"`
+	Version    int                 `json:"version,omitempty"`
+	Lines      []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts   []GoodsReceipt      `json:"receipts,omitempty"`
+}
+
+type PurchaseOrderLine struct {
+	ProductID        string `json:"product_id"` //Base62 of UUIDv7
+	ProductName      string `json:"product_name"`
+	QuantityOrdered  int    `json:"quantity_ordered"`
+	QuantityReceived int    `json:"quantity_received"`
+	Outstanding      int    `json:"outstanding"`
+	CostPrice        int64  `json:"cost_price"`
+}
+
+type GoodsReceipt struct {
+	ID         string             `json:"id"`                  //Base62 of UUIDv7
+	OutletID   string             `json:"outlet_id,omitempty"` //Base62 of UUIDv7
+	Outlet     string             `json:"outlet,omitempty"`
+	Notes      string             `json:"notes,omitempty"`
+	ReceivedAt time.Time          `json:"received_at"`
+	ReceivedBy string             `json:"received_by"`
+	Lines      []GoodsReceiptLine `json:"lines"`
+}
+
+type GoodsReceiptLine struct {
+	ProductID   string `json:"product_id"` //Base62 of UUIDv7
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+func (p *PurchaseOrderEntity) ToModel() *PurchaseOrder {
+	order := &PurchaseOrder{
+		ID:         utils.EncodeBase62(p.ID.String()),
+		SupplierID: utils.EncodeBase62(p.SupplierID.String()),
+		Supplier:   p.SupplierName,
+		Status:     p.Status,
+		Notes:      p.Notes,
+		TotalCost:  p.TotalCost(),
+		CreatedAt:  p.CreatedAt,
+		CreatedBy:  p.CreatedBy,
+		UpdatedAt:  p.UpdatedAt,
+		SentAt:     p.SentAt,
+		ClosedAt:   p.ClosedAt,
+		Version:    p.Version,
+	}
+	for _, l := range p.Lines {
+		order.Lines = append(order.Lines, PurchaseOrderLine{
+			ProductID:        utils.EncodeBase62(l.ProductID.String()),
+			ProductName:      l.ProductName,
+			QuantityOrdered:  l.QuantityOrdered,
+			QuantityReceived: l.QuantityReceived,
+			Outstanding:      l.Outstanding(),
+			CostPrice:        l.CostPrice,
+		})
+	}
+	for _, r := range p.Receipts {
+		receipt := GoodsReceipt{
+			ID:         utils.EncodeBase62(r.ID.String()),
+			Notes:      r.Notes,
+			ReceivedAt: r.ReceivedAt,
+			ReceivedBy: r.ReceivedBy,
+			Lines:      []GoodsReceiptLine{},
+		}
+		if r.OutletID != nil {
+			receipt.OutletID = utils.EncodeBase62(r.OutletID.String())
+			receipt.Outlet = r.OutletName
+		}
+		for _, l := range r.Lines {
+			receipt.Lines = append(receipt.Lines, GoodsReceiptLine{
+				ProductID:   utils.EncodeBase62(l.ProductID.String()),
+				ProductName: l.ProductName,
+				Quantity:    l.Quantity,
+			})
+		}
+		order.Receipts = append(order.Receipts, receipt)
+	}
+	return order
+}
+
+type PurchaseOrderItemRequest struct {
+	ProductID string `json:"product_id"` //Base62 of UUIDv7
+	Quantity  int    `json:"quantity"`
+	CostPrice int64  `json:"cost_price"`
+}
+
+// toPurchaseOrderLineEntities decodes the Base62 product ids, unparsable ids are kept as uuid.Nil so the service can reject them
+func toPurchaseOrderLineEntities(purchaseOrderID uuid.UUID, items []PurchaseOrderItemRequest) []PurchaseOrderLineEntity {
+	var lines []PurchaseOrderLineEntity
+	for _, item := range items {
+		productID, err := uuid.Parse(utils.DecodeBase62(item.ProductID))
+		if err != nil {
+			productID = uuid.Nil
+		}
+		lines = append(lines, PurchaseOrderLineEntity{
+			PurchaseOrderID: purchaseOrderID,
+			ProductID:       productID,
+			QuantityOrdered: item.Quantity,
+			CostPrice:       item.CostPrice,
+		})
+	}
+	return lines
+}
+
+// TODO: add validation
+type CreatePurchaseOrderRequest struct {
+	SupplierID string                     `json:"supplier_id"` //Base62 of UUIDv7
+	Notes      string                     `json:"notes"`
+	Items      []PurchaseOrderItemRequest `json:"items"`
+}
+
+func (r *CreatePurchaseOrderRequest) ToEntity() *PurchaseOrderEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	supplierID, err := uuid.Parse(utils.DecodeBase62(r.SupplierID))
+	if err != nil {
+		supplierID = uuid.Nil
+	}
+
+	return &PurchaseOrderEntity{
+		ID:         id,
+		SupplierID: supplierID,
+		Status:     PurchaseOrderDraft,
+		Notes:      r.Notes,
+		Lines:      toPurchaseOrderLineEntities(id, r.Items),
+		CreatedBy:  "USER",
+		UpdatedBy:  "USER",
+	}
+}
+
+// TODO: add validation
+type UpdatePurchaseOrderRequest struct {
+	SupplierID string                     `json:"supplier_id"` //Base62 of UUIDv7
+	Notes      string                     `json:"notes"`
+	Items      []PurchaseOrderItemRequest `json:"items"`
+	Version    int                        `json:"version"`
+}
+
+func (r *UpdatePurchaseOrderRequest) ToEntity() *PurchaseOrderEntity {
+	supplierID, err := uuid.Parse(utils.DecodeBase62(r.SupplierID))
+	if err != nil {
+		supplierID = uuid.Nil
+	}
+
+	return &PurchaseOrderEntity{
+		SupplierID: supplierID,
+		Notes:      r.Notes,
+		Lines:      toPurchaseOrderLineEntities(uuid.Nil, r.Items),
+		Version:    r.Version,
+		UpdatedBy:  "USER",
+	}
+}
+
+type ReceiveGoodsRequest struct {
+	OutletID string                    `json:"outlet_id"` //Base62 of UUIDv7, optional
+	Notes    string                    `json:"notes"`
+	Items    []GoodsReceiptItemRequest `json:"items"`
+}
+
+type GoodsReceiptItemRequest struct {
+	ProductID string `json:"product_id"` //Base62 of UUIDv7
+	Quantity  int    `json:"quantity"`
+}
+
+func (r *ReceiveGoodsRequest) ToEntity(purchaseOrderID uuid.UUID) *GoodsReceiptEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	receipt := &GoodsReceiptEntity{
+		ID:              id,
+		PurchaseOrderID: purchaseOrderID,
+		OutletID:        parseOptionalBase62(r.OutletID),
+		Notes:           r.Notes,
+		ReceivedBy:      "USER",
+	}
+	for _, item := range r.Items {
+		productID, err := uuid.Parse(utils.DecodeBase62(item.ProductID))
+		if err != nil {
+			productID = uuid.Nil
+		}
+		receipt.Lines = append(receipt.Lines, GoodsReceiptLineEntity{
+			GoodsReceiptID: id,
+			ProductID:      productID,
+			Quantity:       item.Quantity,
+		})
+	}
+	return receipt
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchaseOrderEntity_Totals(t *testing.T) {
+	order := &PurchaseOrderEntity{
+		ID:         uuid.New(),
+		SupplierID: uuid.New(),
+		Status:     PurchaseOrderPartiallyReceived,
+		Lines: []PurchaseOrderLineEntity{
+			{ProductID: uuid.New(), ProductName: "Kopi", QuantityOrdered: 10, QuantityReceived: 10, CostPrice: 5000},
+			{ProductID: uuid.New(), ProductName: "Gula", QuantityOrdered: 4, QuantityReceived: 1, CostPrice: 2500},
+		},
+	}
+
+	assert.Equal(t, int64(60000), order.TotalCost())
+	assert.False(t, order.IsFullyReceived())
+	assert.Equal(t, 3, order.Lines[1].Outstanding())
+
+	model := order.ToModel()
+	assert.Equal(t, int64(60000), model.TotalCost)
+	require.Len(t, model.Lines, 2)
+	assert.Equal(t, 0, model.Lines[0].Outstanding)
+	assert.Equal(t, 3, model.Lines[1].Outstanding)
+
+	order.Lines[1].QuantityReceived = 4
+	assert.True(t, order.IsFullyReceived())
+}
+
+func TestCreatePurchaseOrderRequest_ToEntity(t *testing.T) {
+	supplierID, productID := uuid.New(), uuid.New()
+	req := &CreatePurchaseOrderRequest{
+		SupplierID: utils.EncodeBase62(supplierID.String()),
+		Items: []PurchaseOrderItemRequest{
+			{ProductID: utils.EncodeBase62(productID.String()), Quantity: 12, CostPrice: 4000},
+			{ProductID: "not-a-product", Quantity: 1},
+		},
+	}
+
+	entity := req.ToEntity()
+
+	require.NotNil(t, entity)
+	assert.Equal(t, supplierID, entity.SupplierID)
+	assert.Equal(t, PurchaseOrderDraft, entity.Status)
+	require.Len(t, entity.Lines, 2)
+	assert.Equal(t, entity.ID, entity.Lines[0].PurchaseOrderID)
+	assert.Equal(t, productID, entity.Lines[0].ProductID)
+	assert.Equal(t, int64(4000), entity.Lines[0].CostPrice)
+	assert.Equal(t, uuid.Nil, entity.Lines[1].ProductID)
+
+	assert.Equal(t, uuid.Nil, (&UpdatePurchaseOrderRequest{SupplierID: "???"}).ToEntity().SupplierID)
+}
+
+func TestReceiveGoodsRequest_ToEntity(t *testing.T) {
+	orderID, productID := uuid.New(), uuid.New()
+	req := &ReceiveGoodsRequest{Notes: "first delivery", Items: []GoodsReceiptItemRequest{
+		{ProductID: utils.EncodeBase62(productID.String()), Quantity: 6},
+	}}
+
+	receipt := req.ToEntity(orderID)
+
+	require.NotNil(t, receipt)
+	assert.Equal(t, orderID, receipt.PurchaseOrderID)
+	assert.Equal(t, "USER", receipt.ReceivedBy)
+	require.Len(t, receipt.Lines, 1)
+	assert.Equal(t, receipt.ID, receipt.Lines[0].GoodsReceiptID)
+	assert.Equal(t, 6, receipt.Lines[0].Quantity)
+	assert.Nil(t, receipt.OutletID)
+
+	outletID := uuid.New()
+	req.OutletID = utils.EncodeBase62(outletID.String())
+	receipt = req.ToEntity(orderID)
+	require.NotNil(t, receipt.OutletID)
+	assert.Equal(t, outletID, *receipt.OutletID)
+
+	receipt.OutletName = "Jakarta"
+	order := (&PurchaseOrderEntity{ID: orderID, Receipts: []GoodsReceiptEntity{*receipt}}).ToModel()
+	assert.Equal(t, req.OutletID, order.Receipts[0].OutletID)
+	assert.Equal(t, "Jakarta", order.Receipts[0].Outlet)
+}
@@ -0,0 +1,259 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	ShiftOpen   = "open"
+	ShiftClosed = "closed"
+
+	CashEntryIn  = "cash_in"
+	CashEntryOut = "cash_out"
+
+	// ShiftReportX is read mid-shift and leaves the shift open, ShiftReportZ is the final report of a closed shift
+	ShiftReportX = "X"
+	ShiftReportZ = "Z"
+)
+
+// ShiftEntity is one cashier's session on a cash drawer, opened with a float and closed with a cash count
+type ShiftEntity struct {
+	CreatedAt    time.Time // opened
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	Version      int
+	ID           uuid.UUID //UUIDv7
+	CashierName  string
+	OutletID     *uuid.UUID
+	OutletName   string // JOIN from outlet table by outlet_id
+	Status       string
+	OpeningFloat int64 // IDR in the drawer when the shift opened
+	Notes        string
+	ClosedAt     *time.Time
+	ClosedBy     string
+	ExpectedCash *int64 // snapshotted at close
+	CountedCash  *int64
+	CashEntries  []CashEntryEntity
+}
+
+type CashEntryEntity struct {
+	ID        uuid.UUID //UUIDv7
+	ShiftID   uuid.UUID
+	Type      string
+	Amount    int64 // IDR, Type gives the direction
+	Reason    string
+	CreatedAt time.Time
+	CreatedBy string // the actor
+}
+
+type Shift struct {
+	ID           string      `json:"id"` //Base62 of UUIDv7
+	CashierName  string      `json:"cashier_name"`
+	OutletID     string      `json:"outlet_id,omitempty"`
+	Outlet       string      `json:"outlet,omitempty"`
+	Status       string      `json:"status"`
+	OpeningFloat Price       `json:"opening_float"`
+	Notes        string      `json:"notes,omitempty"`
+	OpenedAt     time.Time   `json:"opened_at"`
+	OpenedBy     string      `json:"opened_by"`
+	ClosedAt     *time.Time  `json:"closed_at,omitempty"`
+	ClosedBy     string      `json:"closed_by,omitempty"`
+	ExpectedCash *Price      `json:"expected_cash,omitempty"`
+	CountedCash  *Price      `json:"counted_cash,omitempty"`
+	Version      int         `json:"version,omitempty"`
+	CashEntries  []CashEntry `json:"cash_entries"`
+}
+
+type CashEntry struct {
+	ID        string    `json:"id"` //Base62 of UUIDv7
+	Type      string    `json:"type"`
+	Amount    Price     `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+}
+
+func (s *ShiftEntity) ToModel() *Shift {
+	var outletID string
+	if s.OutletID != nil {
+		outletID = utils.EncodeBase62(s.OutletID.String())
+	}
+
+	shift := &Shift{
+		ID:           utils.EncodeBase62(s.ID.String()),
+		CashierName:  s.CashierName,
+		OutletID:     outletID,
+		Outlet:       s.OutletName,
+		Status:       s.Status,
+		OpeningFloat: idrPrice(s.OpeningFloat),
+		Notes:        s.Notes,
+		OpenedAt:     s.CreatedAt,
+		OpenedBy:     s.CreatedBy,
+		ClosedAt:     s.ClosedAt,
+		ClosedBy:     s.ClosedBy,
+		Version:      s.Version,
+		CashEntries:  []CashEntry{},
+	}
+	if s.ExpectedCash != nil {
+		expected := idrPrice(*s.ExpectedCash)
+		shift.ExpectedCash = &expected
+	}
+	if s.CountedCash != nil {
+		counted := idrPrice(*s.CountedCash)
+		shift.CountedCash = &counted
+	}
+	for _, e := range s.CashEntries {
+		shift.CashEntries = append(shift.CashEntries, *e.ToModel())
+	}
+	return shift
+}
+
+func (e *CashEntryEntity) ToModel() *CashEntry {
+	return &CashEntry{
+		ID:        utils.EncodeBase62(e.ID.String()),
+		Type:      e.Type,
+		Amount:    idrPrice(e.Amount),
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+		CreatedBy: e.CreatedBy,
+	}
+}
+
+// ShiftSalesEntity sums the transactions rung up on a shift
+type ShiftSalesEntity struct {
+	TransactionCount int
+	ItemsSold        int
+	NetSales         int64 // what the customers paid, after points discounts
+	PointsDiscount   int64
+	GiftCardAmount   int64 // the part of NetSales paid by gift card
+}
+
+// CashSales is what the sales put into the drawer. Gift cards are the only non-cash tender
+// recorded on a sale, the rest of each sale is counted as cash.
+func (s ShiftSalesEntity) CashSales() int64 {
+	return s.NetSales - s.GiftCardAmount
+}
+
+// ShiftReport is the X report of an open shift or the Z report of a closed one
+type ShiftReport struct {
+	Kind             string `json:"kind"`
+	Shift            Shift  `json:"shift"`
+	TransactionCount int    `json:"transaction_count"`
+	ItemsSold        int    `json:"items_sold"`
+	GrossSales       Price  `json:"gross_sales"`
+	PointsDiscount   Price  `json:"points_discount"`
+	NetSales         Price  `json:"net_sales"`
+	GiftCardSales    Price  `json:"gift_card_sales"`
+	CashSales        Price  `json:"cash_sales"`
+	CashIn           Price  `json:"cash_in"`
+	CashOut          Price  `json:"cash_out"`
+	ExpectedCash     Price  `json:"expected_cash"`
+	CountedCash      *Price `json:"counted_cash,omitempty"`
+	Variance         *Price `json:"variance,omitempty"` // counted minus expected, negative when the drawer is short
+}
+
+// NewShiftReport reconciles the drawer: the opening float plus cash sales and cash put in, less cash taken out.
+// A closed shift reports the expected cash snapshotted when it closed.
+func NewShiftReport(shift ShiftEntity, sales ShiftSalesEntity) *ShiftReport {
+	report := &ShiftReport{
+		Kind:             ShiftReportX,
+		Shift:            *shift.ToModel(),
+		TransactionCount: sales.TransactionCount,
+		ItemsSold:        sales.ItemsSold,
+		GrossSales:       idrPrice(sales.NetSales + sales.PointsDiscount),
+		PointsDiscount:   idrPrice(sales.PointsDiscount),
+		NetSales:         idrPrice(sales.NetSales),
+		GiftCardSales:    idrPrice(sales.GiftCardAmount),
+		CashSales:        idrPrice(sales.CashSales()),
+	}
+
+	var cashIn, cashOut int64
+	for _, e := range shift.CashEntries {
+		switch e.Type {
+		case CashEntryIn:
+			cashIn += e.Amount
+		case CashEntryOut:
+			cashOut += e.Amount
+		}
+	}
+	report.CashIn = idrPrice(cashIn)
+	report.CashOut = idrPrice(cashOut)
+	expected := shift.OpeningFloat + sales.CashSales() + cashIn - cashOut
+
+	if shift.Status == ShiftClosed {
+		report.Kind = ShiftReportZ
+		if shift.ExpectedCash != nil {
+			expected = *shift.ExpectedCash
+		}
+		if shift.CountedCash != nil {
+			counted, variance := idrPrice(*shift.CountedCash), idrPrice(*shift.CountedCash-expected)
+			report.CountedCash, report.Variance = &counted, &variance
+		}
+	}
+	report.ExpectedCash = idrPrice(expected)
+	return report
+}
+
+// TODO: add validation
+type OpenShiftRequest struct {
+	CashierName  string `json:"cashier_name"`
+	OutletID     string `json:"outlet_id"` //Base62 of UUIDv7, optional
+	OpeningFloat int64  `json:"opening_float"`
+	Notes        string `json:"notes"`
+}
+
+func (r *OpenShiftRequest) ToEntity() *ShiftEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+	var outletID *uuid.UUID
+	if r.OutletID != "" {
+		parsed := parseBase62OrNil(r.OutletID)
+		outletID = &parsed
+	}
+
+	return &ShiftEntity{
+		ID:           id,
+		CashierName:  strings.TrimSpace(r.CashierName),
+		OutletID:     outletID,
+		Status:       ShiftOpen,
+		OpeningFloat: r.OpeningFloat,
+		Notes:        r.Notes,
+		CreatedBy:    "USER",
+		UpdatedBy:    "USER",
+	}
+}
+
+// TODO: add validation
+type CreateCashEntryRequest struct {
+	Type   string `json:"type"` // cash_in or cash_out
+	Amount int64  `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+func (r *CreateCashEntryRequest) ToEntity(shiftID uuid.UUID) *CashEntryEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+	return &CashEntryEntity{
+		ID:        id,
+		ShiftID:   shiftID,
+		Type:      r.Type,
+		Amount:    r.Amount,
+		Reason:    strings.TrimSpace(r.Reason),
+		CreatedBy: "USER",
+	}
+}
+
+// TODO: add validation
+type CloseShiftRequest struct {
+	CountedCash *int64 `json:"counted_cash"`
+	Notes       string `json:"notes"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftEntity_ToModel(t *testing.T) {
+	outletID := uuid.New()
+	counted := int64(150000)
+	entity := &ShiftEntity{
+		ID: uuid.New(), CashierName: "Sari", OutletID: &outletID, OutletName: "Jakarta",
+		Status: ShiftClosed, OpeningFloat: 100000, CountedCash: &counted, CreatedBy: "USER",
+		CashEntries: []CashEntryEntity{{ID: uuid.New(), Type: CashEntryIn, Amount: 20000, Reason: "change"}},
+	}
+
+	shift := entity.ToModel()
+
+	assert.Equal(t, utils.EncodeBase62(outletID.String()), shift.OutletID)
+	assert.Equal(t, int64(100000), shift.OpeningFloat.Amount)
+	assert.Nil(t, shift.ExpectedCash)
+	require.NotNil(t, shift.CountedCash)
+	assert.Equal(t, counted, shift.CountedCash.Amount)
+	require.Len(t, shift.CashEntries, 1)
+	assert.Equal(t, "change", shift.CashEntries[0].Reason)
+}
+
+func TestNewShiftReport(t *testing.T) {
+	shift := ShiftEntity{
+		ID: uuid.New(), Status: ShiftOpen, OpeningFloat: 200000,
+		CashEntries: []CashEntryEntity{{Type: CashEntryIn, Amount: 50000}, {Type: CashEntryOut, Amount: 15000}},
+	}
+	sales := ShiftSalesEntity{TransactionCount: 3, ItemsSold: 7, NetSales: 120000, PointsDiscount: 5000, GiftCardAmount: 20000}
+
+	x := NewShiftReport(shift, sales)
+
+	assert.Equal(t, ShiftReportX, x.Kind)
+	assert.Equal(t, int64(125000), x.GrossSales.Amount)
+	assert.Equal(t, int64(100000), x.CashSales.Amount)
+	assert.Equal(t, int64(50000), x.CashIn.Amount)
+	assert.Equal(t, int64(15000), x.CashOut.Amount)
+	assert.Equal(t, int64(335000), x.ExpectedCash.Amount, "float + cash sales + cash in - cash out")
+	assert.Nil(t, x.CountedCash)
+
+	now := time.Now()
+	expected, counted := int64(335000), int64(330000)
+	shift.Status, shift.ClosedAt, shift.ExpectedCash, shift.CountedCash = ShiftClosed, &now, &expected, &counted
+
+	z := NewShiftReport(shift, sales)
+
+	assert.Equal(t, ShiftReportZ, z.Kind)
+	require.NotNil(t, z.Variance)
+	assert.Equal(t, int64(-5000), z.Variance.Amount, "the drawer is short")
+}
+
+func TestOpenShiftRequest_ToEntity(t *testing.T) {
+	shift := (&OpenShiftRequest{CashierName: " Sari ", OpeningFloat: 100000}).ToEntity()
+
+	require.NotNil(t, shift)
+	assert.Equal(t, "Sari", shift.CashierName)
+	assert.Equal(t, ShiftOpen, shift.Status)
+	assert.Nil(t, shift.OutletID)
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	StockCountOpen      = "open"
+	StockCountSubmitted = "submitted"
+	StockCountPosted    = "posted"
+	StockCountCancelled = "cancelled"
+)
+
+type StockCountEntity struct {
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	DeletedAt    *time.Time
+	Version      int
+	ID           uuid.UUID //UUIDv7
+	Status       string
+	CategoryID   *uuid.UUID // nil counts the whole store
+	CategoryName string     // JOIN from category table by category_id
+	OutletID     *uuid.UUID // nil counts the stock not held at any outlet
+	OutletName   string     // JOIN from outlet table by outlet_id
+	Notes        string
+	SubmittedAt  *time.Time
+	ApprovedAt   *time.Time
+	ApprovedBy   string
+	Lines        []StockCountLineEntity
+}
+
+type StockCountLineEntity struct {
+	StockCountID    uuid.UUID
+	ProductID       uuid.UUID
+	ProductName     string // JOIN from product table by product_id
+	ProductPrice    int64  // JOIN from product table by product_id
+	SystemStock     int    // live product (or outlet) stock until posted, frozen afterwards
+	CountedQuantity *int   // nil until counted
+	CountedAt       *time.Time
+	CountedBy       string
+}
+
+// Variance is counted minus system stock, zero while the line is not counted yet
+func (l *StockCountLineEntity) Variance() int {
+	if l.CountedQuantity == nil {
+		return 0
+	}
+	return *l.CountedQuantity - l.SystemStock
+}
+
+type StockCount struct {
+	ID          string           `json:"id"` //Base62 of UUIDv7
+	Status      string           `json:"status"`
+	CategoryID  string           `json:"category_id,omitempty"`
+	Category    string           `json:"category,omitempty"`
+	OutletID    string           `json:"outlet_id,omitempty"` //Base62 of UUIDv7
+	Outlet      string           `json:"outlet,omitempty"`
+	Notes       string           `json:"notes,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CreatedBy   string           `json:"created_by"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	SubmittedAt *time.Time       `json:"submitted_at,omitempty"`
+	ApprovedAt  *time.Time       `json:"approved_at,omitempty"`
+	ApprovedBy  string           `json:"approved_by,omitempty"`
+	Version     int              `json:"version,omitempty"`
+	Lines       []StockCountLine `json:"lines,omitempty"`
+}
+
+type StockCountLine struct {
+	ProductID       string     `json:"product_id"` //Base62 of UUIDv7
+	ProductName     string     `json:"product_name"`
+	SystemStock     int        `json:"system_stock"`
+	CountedQuantity *int       `json:"counted_quantity"`
+	CountedAt       *time.Time `json:"counted_at,omitempty"`
+	CountedBy       string     `json:"counted_by,omitempty"`
+}
+
+func (c *StockCountEntity) ToModel() *StockCount {
+	var categoryID string
+	if c.CategoryID != nil {
+		categoryID = utils.EncodeBase62(c.CategoryID.String())
+	}
+	var outletID string
+	if c.OutletID != nil {
+		outletID = utils.EncodeBase62(c.OutletID.String())
+	}
+
+	count := &StockCount{
+		ID:          utils.EncodeBase62(c.ID.String()),
+		Status:      c.Status,
+		CategoryID:  categoryID,
+		Category:    c.CategoryName,
+		OutletID:    outletID,
+		Outlet:      c.OutletName,
+		Notes:       c.Notes,
+		CreatedAt:   c.CreatedAt,
+		CreatedBy:   c.CreatedBy,
+		UpdatedAt:   c.UpdatedAt,
+		SubmittedAt: c.SubmittedAt,
+		ApprovedAt:  c.ApprovedAt,
+		ApprovedBy:  c.ApprovedBy,
+		Version:     c.Version,
+	}
+	for _, l := range c.Lines {
+		count.Lines = append(count.Lines, StockCountLine{
+			ProductID:       utils.EncodeBase62(l.ProductID.String()),
+			ProductName:     l.ProductName,
+			SystemStock:     l.SystemStock,
+			CountedQuantity: l.CountedQuantity,
+			CountedAt:       l.CountedAt,
+			CountedBy:       l.CountedBy,
+		})
+	}
+	return count
+}
+
+// StockCountVarianceReport is the preview (while open or submitted) or the final result (once posted) of a count
+type StockCountVarianceReport struct {
+	StockCountID       string               `json:"stock_count_id"` //Base62 of UUIDv7
+	Status             string               `json:"status"`
+	TotalCounted       int                  `json:"total_counted"`
+	TotalUncounted     int                  `json:"total_uncounted"`
+	TotalVarianceQty   int                  `json:"total_variance_qty"`
+	TotalVarianceValue int64                `json:"total_variance_value"`
+	Items              []StockCountVariance `json:"items"`
+}
+
+type StockCountVariance struct {
+	ProductID       string `json:"product_id"` //Base62 of UUIDv7
+	ProductName     string `json:"product_name"`
+	SystemStock     int    `json:"system_stock"`
+	CountedQuantity *int   `json:"counted_quantity"`
+	Variance        int    `json:"variance"`
+	VarianceValue   int64  `json:"variance_value"` // variance times the current selling price
+}
+
+func (c *StockCountEntity) ToVarianceReport() *StockCountVarianceReport {
+	report := &StockCountVarianceReport{
+		StockCountID: utils.EncodeBase62(c.ID.String()),
+		Status:       c.Status,
+		Items:        []StockCountVariance{},
+	}
+	for _, l := range c.Lines {
+		if l.CountedQuantity == nil {
+			report.TotalUncounted++
+		} else {
+			report.TotalCounted++
+		}
+
+		variance := l.Variance()
+		value := int64(variance) * l.ProductPrice
+		report.TotalVarianceQty += variance
+		report.TotalVarianceValue += value
+		report.Items = append(report.Items, StockCountVariance{
+			ProductID:       utils.EncodeBase62(l.ProductID.String()),
+			ProductName:     l.ProductName,
+			SystemStock:     l.SystemStock,
+			CountedQuantity: l.CountedQuantity,
+			Variance:        variance,
+			VarianceValue:   value,
+		})
+	}
+	return report
+}
+
+// TODO: add validation
+type CreateStockCountRequest struct {
+	CategoryID string `json:"category_id"` // empty counts the whole store
+	OutletID   string `json:"outlet_id"`   //Base62 of UUIDv7, optional
+	Notes      string `json:"notes"`
+}
+
+func (r *CreateStockCountRequest) ToEntity() *StockCountEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	var categoryID *uuid.UUID
+	if r.CategoryID != "" {
+		parsed, err := uuid.Parse(utils.DecodeBase62(r.CategoryID))
+		if err != nil {
+			parsed = uuid.Nil
+		}
+		categoryID = &parsed
+	}
+
+	return &StockCountEntity{
+		ID:         id,
+		Status:     StockCountOpen,
+		CategoryID: categoryID,
+		OutletID:   parseOptionalBase62(r.OutletID),
+		Notes:      r.Notes,
+		CreatedBy:  "USER",
+		UpdatedBy:  "USER",
+	}
+}
+
+type SubmitStockCountRequest struct {
+	Items []StockCountItemRequest `json:"items"`
+}
+
+type StockCountItemRequest struct {
+	ProductID       string `json:"product_id"` //Base62 of UUIDv7
+	CountedQuantity int    `json:"counted_quantity"`
+}
+
+func (r *SubmitStockCountRequest) ToEntities(stockCountID uuid.UUID) []StockCountLineEntity {
+	var lines []StockCountLineEntity
+	for _, item := range r.Items {
+		productID, err := uuid.Parse(utils.DecodeBase62(item.ProductID))
+		if err != nil {
+			productID = uuid.Nil
+		}
+		counted := item.CountedQuantity
+		lines = append(lines, StockCountLineEntity{
+			StockCountID:    stockCountID,
+			ProductID:       productID,
+			CountedQuantity: &counted,
+			CountedBy:       "USER",
+		})
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockCountEntity_ToVarianceReport(t *testing.T) {
+	countID, _ := uuid.NewV7()
+	ten, three := 10, 3
+
+	entity := &StockCountEntity{
+		ID:     countID,
+		Status: StockCountOpen,
+		Lines: []StockCountLineEntity{
+			{ProductID: uuid.New(), ProductName: "Susu", ProductPrice: 5000, SystemStock: 12, CountedQuantity: &ten},
+			{ProductID: uuid.New(), ProductName: "Roti", ProductPrice: 2000, SystemStock: 1, CountedQuantity: &three},
+			{ProductID: uuid.New(), ProductName: "Teh", ProductPrice: 1000, SystemStock: 4},
+		},
+	}
+
+	report := entity.ToVarianceReport()
+
+	require.Len(t, report.Items, 3)
+	assert.Equal(t, utils.EncodeBase62(countID.String()), report.StockCountID)
+	assert.Equal(t, 2, report.TotalCounted)
+	assert.Equal(t, 1, report.TotalUncounted)
+	assert.Equal(t, -2, report.Items[0].Variance)
+	assert.Equal(t, int64(-10000), report.Items[0].VarianceValue)
+	assert.Equal(t, 2, report.Items[1].Variance)
+	assert.Equal(t, 0, report.Items[2].Variance)
+	assert.Equal(t, 0, report.TotalVarianceQty)
+	assert.Equal(t, int64(-6000), report.TotalVarianceValue)
+}
+
+func TestCreateStockCountRequest_ToEntity(t *testing.T) {
+	storeWide := (&CreateStockCountRequest{Notes: "monthly"}).ToEntity()
+	require.NotNil(t, storeWide)
+	assert.Nil(t, storeWide.CategoryID)
+	assert.Nil(t, storeWide.OutletID)
+	assert.Equal(t, StockCountOpen, storeWide.Status)
+
+	categoryID := uuid.New()
+	scoped := (&CreateStockCountRequest{CategoryID: utils.EncodeBase62(categoryID.String())}).ToEntity()
+	require.NotNil(t, scoped.CategoryID)
+	assert.Equal(t, categoryID, *scoped.CategoryID)
+
+	invalid := (&CreateStockCountRequest{CategoryID: "???"}).ToEntity()
+	require.NotNil(t, invalid.CategoryID)
+	assert.Equal(t, uuid.Nil, *invalid.CategoryID)
+
+	outletID := uuid.New()
+	atOutlet := (&CreateStockCountRequest{OutletID: utils.EncodeBase62(outletID.String())}).ToEntity()
+	require.NotNil(t, atOutlet.OutletID)
+	assert.Equal(t, outletID, *atOutlet.OutletID)
+	assert.Equal(t, utils.EncodeBase62(outletID.String()), atOutlet.ToModel().OutletID)
+}
+
+func TestSubmitStockCountRequest_ToEntities(t *testing.T) {
+	countID, productID := uuid.New(), uuid.New()
+	req := &SubmitStockCountRequest{Items: []StockCountItemRequest{
+		{ProductID: utils.EncodeBase62(productID.String()), CountedQuantity: 7},
+	}}
+
+	lines := req.ToEntities(countID)
+
+	require.Len(t, lines, 1)
+	assert.Equal(t, countID, lines[0].StockCountID)
+	assert.Equal(t, productID, lines[0].ProductID)
+	assert.Equal(t, 7, *lines[0].CountedQuantity)
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	StockMovementSale            = "sale"
+	StockMovementRefund          = "refund"
+	StockMovementPurchaseReceipt = "purchase_receipt"
+	StockMovementAdjustment      = "adjustment"
+	StockMovementTransfer        = "transfer"
+	StockMovementWaste           = "waste"
+)
+
+type StockMovementEntity struct {
+	ID           uuid.UUID //UUIDv7
+	ProductID    uuid.UUID
+	Type         string
+	Quantity     int // signed, negative takes stock out
+	BalanceAfter int // product stock right after this movement was applied
+	Reason       string
+	ReferenceID  *uuid.UUID // e.g. the transaction behind a sale
+	CreatedAt    time.Time
+	CreatedBy    string // the actor
+
+	LotID    *uuid.UUID // the lot the units came from or went into
+	OutletID *uuid.UUID // the outlet whose stock moved, nil for stock not assigned to an outlet
+}
+
+type StockMovement struct {
+	ID           string    `json:"id"`         //Base62 of UUIDv7
+	ProductID    string    `json:"product_id"` //Base62 of UUIDv7
+	Type         string    `json:"type"`
+	Quantity     int       `json:"quantity"`
+	BalanceAfter int       `json:"balance_after"`
+	Reason       string    `json:"reason,omitempty"`
+	ReferenceID  string    `json:"reference_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	CreatedBy    string    `json:"created_by"`
+	LotID        string    `json:"lot_id,omitempty"`
+	OutletID     string    `json:"outlet_id,omitempty"`
+}
+
+func (m *StockMovementEntity) ToModel() *StockMovement {
+	var referenceID, lotID, outletID string
+	if m.ReferenceID != nil {
+		referenceID = utils.EncodeBase62(m.ReferenceID.String())
+	}
+	if m.LotID != nil {
+		lotID = utils.EncodeBase62(m.LotID.String())
+	}
+	if m.OutletID != nil {
+		outletID = utils.EncodeBase62(m.OutletID.String())
+	}
+
+	return &StockMovement{
+		ID:           utils.EncodeBase62(m.ID.String()),
+		ProductID:    utils.EncodeBase62(m.ProductID.String()),
+		Type:         m.Type,
+		Quantity:     m.Quantity,
+		BalanceAfter: m.BalanceAfter,
+		Reason:       m.Reason,
+		ReferenceID:  referenceID,
+		CreatedAt:    m.CreatedAt,
+		CreatedBy:    m.CreatedBy,
+		LotID:        lotID,
+		OutletID:     outletID,
+	}
+}
+
+// TODO: add validation
+type CreateStockMovementRequest struct {
+	Type string `json:"type"`
+	// Quantity of the product's base unit, decimals down to its quantity scale, e.g. -0.25 kg of spilled rice
+	Quantity float64 `json:"quantity"`
+	Reason   string  `json:"reason"`
+
+	// OutletID books the movement into that outlet's stock as well, Base62 of UUIDv7
+	OutletID string `json:"outlet_id"`
+}
+
+// ToEntity counts the quantity in stock units of a product kept at quantityScale
+func (r *CreateStockMovementRequest) ToEntity(productID uuid.UUID, quantityScale int) *StockMovementEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	quantity, _ := ToStockQuantity(r.Quantity, QuantityMultiplier(quantityScale))
+	movement := &StockMovementEntity{
+		ID:        id,
+		ProductID: productID,
+		Type:      r.Type,
+		Quantity:  quantity,
+		Reason:    r.Reason,
+		CreatedBy: "USER",
+	}
+	if r.OutletID != "" {
+		outletID := parseBase62OrNil(r.OutletID)
+		movement.OutletID = &outletID
+	}
+	return movement
+}
+
+type StockReconciliationEntity struct {
+	ProductID     uuid.UUID
+	Stock         int
+	LedgerBalance int
+}
+
+// StockReconciliation compares the cached product stock against the sum of its ledger
+type StockReconciliation struct {
+	ProductID     string `json:"product_id"` //Base62 of UUIDv7
+	Stock         int    `json:"stock"`
+	LedgerBalance int    `json:"ledger_balance"`
+	Difference    int    `json:"difference"`
+	Balanced      bool   `json:"balanced"`
+}
+
+func (r *StockReconciliationEntity) ToModel() *StockReconciliation {
+	return &StockReconciliation{
+		ProductID:     utils.EncodeBase62(r.ProductID.String()),
+		Stock:         r.Stock,
+		LedgerBalance: r.LedgerBalance,
+		Difference:    r.Stock - r.LedgerBalance,
+		Balanced:      r.Stock == r.LedgerBalance,
+	}
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementEntity_ToModel(t *testing.T) {
+	id, _ := uuid.NewV7()
+	productID, _ := uuid.NewV7()
+	transactionID, _ := uuid.NewV7()
+	now := time.Now()
+
+	entity := &StockMovementEntity{
+		ID:           id,
+		ProductID:    productID,
+		Type:         StockMovementSale,
+		Quantity:     -2,
+		BalanceAfter: 8,
+		ReferenceID:  &transactionID,
+		CreatedAt:    now,
+		CreatedBy:    "USER",
+	}
+
+	model := entity.ToModel()
+
+	require.NotNil(t, model)
+	assert.Equal(t, utils.EncodeBase62(productID.String()), model.ProductID)
+	assert.Equal(t, utils.EncodeBase62(transactionID.String()), model.ReferenceID)
+	assert.Equal(t, -2, model.Quantity)
+	assert.Equal(t, 8, model.BalanceAfter)
+
+	entity.ReferenceID = nil
+	assert.Empty(t, entity.ToModel().ReferenceID)
+}
+
+func TestCreateStockMovementRequest_ToEntity(t *testing.T) {
+	productID, _ := uuid.NewV7()
+	req := &CreateStockMovementRequest{Type: StockMovementWaste, Quantity: -3, Reason: "expired"}
+
+	entity := req.ToEntity(productID, 0)
+
+	require.NotNil(t, entity)
+	assert.NotEqual(t, uuid.Nil, entity.ID)
+	assert.Equal(t, productID, entity.ProductID)
+	assert.Equal(t, StockMovementWaste, entity.Type)
+	assert.Equal(t, -3, entity.Quantity)
+	assert.Equal(t, "USER", entity.CreatedBy)
+
+	req.Quantity = -0.25
+	assert.Equal(t, -250, req.ToEntity(productID, 3).Quantity, "a quarter kilo of a product kept in grams")
+}
+
+func TestStockReconciliationEntity_ToModel(t *testing.T) {
+	productID, _ := uuid.NewV7()
+
+	balanced := (&StockReconciliationEntity{ProductID: productID, Stock: 10, LedgerBalance: 10}).ToModel()
+	assert.True(t, balanced.Balanced)
+	assert.Equal(t, 0, balanced.Difference)
+
+	drifted := (&StockReconciliationEntity{ProductID: productID, Stock: 12, LedgerBalance: 10}).ToModel()
+	assert.False(t, drifted.Balanced)
+	assert.Equal(t, 2, drifted.Difference)
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	StockTransferInTransit = "in_transit"
+	StockTransferReceived  = "received"
+	StockTransferCancelled = "cancelled"
+)
+
+// StockTransferEntity moves stock between outlets. It is dispatched on creation, the stock
+// leaves the source outlet right away and reaches the destination once received.
+type StockTransferEntity struct {
+	CreatedAt      time.Time // dispatched
+	CreatedBy      string
+	UpdatedAt      time.Time
+	UpdatedBy      string
+	DeletedAt      *time.Time
+	Version        int
+	ID             uuid.UUID //UUIDv7
+	FromOutletID   uuid.UUID
+	FromOutletName string // JOIN from outlet table by from_outlet_id
+	ToOutletID     uuid.UUID
+	ToOutletName   string // JOIN from outlet table by to_outlet_id
+	Status         string
+	Notes          string
+	ReceivedAt     *time.Time
+	ReceivedBy     string
+	CancelledAt    *time.Time
+	Lines          []StockTransferLineEntity
+}
+
+type StockTransferLineEntity struct {
+	StockTransferID uuid.UUID
+	ProductID       uuid.UUID
+	ProductName     string // JOIN from product table by product_id
+	Quantity        int
+}
+
+type StockTransfer struct {
+	ID           string              `json:"id"` //Base62 of UUIDv7
+	FromOutletID string              `json:"from_outlet_id"`
+	FromOutlet   string              `json:"from_outlet"`
+	ToOutletID   string              `json:"to_outlet_id"`
+	ToOutlet     string              `json:"to_outlet"`
+	Status       string              `json:"status"`
+	Notes        string              `json:"notes,omitempty"`
+	DispatchedAt time.Time           `json:"dispatched_at"`
+	DispatchedBy string              `json:"dispatched_by"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	ReceivedBy   string              `json:"received_by,omitempty"`
+	CancelledAt  *time.Time          `json:"cancelled_at,omitempty"`
+	Version      int                 `json:"version,omitempty"`
+	Lines        []StockTransferLine `json:"lines"`
+}
+
+type StockTransferLine struct {
+	ProductID   string `json:"product_id"` //Base62 of UUIDv7
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+func (t *StockTransferEntity) ToModel() *StockTransfer {
+	transfer := &StockTransfer{
+		ID:           utils.EncodeBase62(t.ID.String()),
+		FromOutletID: utils.EncodeBase62(t.FromOutletID.String()),
+		FromOutlet:   t.FromOutletName,
+		ToOutletID:   utils.EncodeBase62(t.ToOutletID.String()),
+		ToOutlet:     t.ToOutletName,
+		Status:       t.Status,
+		Notes:        t.Notes,
+		DispatchedAt: t.CreatedAt,
+		DispatchedBy: t.CreatedBy,
+		ReceivedAt:   t.ReceivedAt,
+		ReceivedBy:   t.ReceivedBy,
+		CancelledAt:  t.CancelledAt,
+		Version:      t.Version,
+		Lines:        []StockTransferLine{},
+	}
+	for _, l := range t.Lines {
+		transfer.Lines = append(transfer.Lines, StockTransferLine{
+			ProductID:   utils.EncodeBase62(l.ProductID.String()),
+			ProductName: l.ProductName,
+			Quantity:    l.Quantity,
+		})
+	}
+	return transfer
+}
+
+type StockTransferItemRequest struct {
+	ProductID string `json:"product_id"` //Base62 of UUIDv7
+	Quantity  int    `json:"quantity"`
+}
+
+// TODO: add validation
+type CreateStockTransferRequest struct {
+	FromOutletID string                     `json:"from_outlet_id"` //Base62 of UUIDv7
+	ToOutletID   string                     `json:"to_outlet_id"`   //Base62 of UUIDv7
+	Notes        string                     `json:"notes"`
+	Items        []StockTransferItemRequest `json:"items"`
+}
+
+// ToEntity decodes the Base62 ids, unparsable ids are kept as uuid.Nil so the service can reject them
+func (r *CreateStockTransferRequest) ToEntity() *StockTransferEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	transfer := &StockTransferEntity{
+		ID:           id,
+		FromOutletID: parseBase62OrNil(r.FromOutletID),
+		ToOutletID:   parseBase62OrNil(r.ToOutletID),
+		Status:       StockTransferInTransit,
+		Notes:        r.Notes,
+		CreatedBy:    "USER",
+		UpdatedBy:    "USER",
+	}
+	for _, item := range r.Items {
+		transfer.Lines = append(transfer.Lines, StockTransferLineEntity{
+			StockTransferID: id,
+			ProductID:       parseBase62OrNil(item.ProductID),
+			Quantity:        item.Quantity,
+		})
+	}
+	return transfer
+}
+
+func parseBase62OrNil(id string) uuid.UUID {
+	parsed, err := uuid.Parse(utils.DecodeBase62(id))
+	if err != nil {
+		return uuid.Nil
+	}
+	return parsed
+}
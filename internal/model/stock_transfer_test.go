@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStockTransferRequest_ToEntity(t *testing.T) {
+	fromID, toID, productID := uuid.New(), uuid.New(), uuid.New()
+	req := &CreateStockTransferRequest{
+		FromOutletID: utils.EncodeBase62(fromID.String()),
+		ToOutletID:   "???",
+		Notes:        "restock",
+		Items: []StockTransferItemRequest{
+			{ProductID: utils.EncodeBase62(productID.String()), Quantity: 5},
+			{ProductID: "not-a-product", Quantity: 1},
+		},
+	}
+
+	entity := req.ToEntity()
+
+	require.NotNil(t, entity)
+	assert.Equal(t, fromID, entity.FromOutletID)
+	assert.Equal(t, uuid.Nil, entity.ToOutletID)
+	assert.Equal(t, StockTransferInTransit, entity.Status)
+	require.Len(t, entity.Lines, 2)
+	assert.Equal(t, entity.ID, entity.Lines[0].StockTransferID)
+	assert.Equal(t, productID, entity.Lines[0].ProductID)
+	assert.Equal(t, uuid.Nil, entity.Lines[1].ProductID)
+
+	entity.ToOutletID = toID
+	model := entity.ToModel()
+	assert.Equal(t, utils.EncodeBase62(toID.String()), model.ToOutletID)
+	assert.Equal(t, "USER", model.DispatchedBy)
+	require.Len(t, model.Lines, 2)
+	assert.Equal(t, 5, model.Lines[0].Quantity)
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type SupplierEntity struct {
+	CreatedAt   time.Time
+	CreatedBy   string
+	UpdatedAt   time.Time
+	UpdatedBy   string
+	DeletedAt   *time.Time
+	Version     int
+	ID          uuid.UUID //UUIDv7
+	Name        string
+	ContactName string
+	Phone       string
+	Email       string
+	Address     string
+	Notes       string
+}
+
+type Supplier struct {
+	ID          string     `json:"id"` //Base62 of UUIDv7
+	Name        string     `json:"name"`
+	ContactName string     `json:"contact_name,omitempty"`
+	Phone       string     `json:"phone,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	Address     string     `json:"address,omitempty"`
+	Notes       string     `json:"notes,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Version     int        `json:"version,omitempty"`
+}
+
+func (s *SupplierEntity) ToModel() *Supplier {
+	return &Supplier{
+		ID:          utils.EncodeBase62(s.ID.String()),
+		Name:        s.Name,
+		ContactName: s.ContactName,
+		Phone:       s.Phone,
+		Email:       s.Email,
+		Address:     s.Address,
+		Notes:       s.Notes,
+		CreatedAt:   s.CreatedAt,
+		UpdatedAt:   s.UpdatedAt,
+		DeletedAt:   s.DeletedAt,
+		Version:     s.Version,
+	}
+}
+
+// TODO: add validation
+type CreateSupplierRequest struct {
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	Address     string `json:"address"`
+	Notes       string `json:"notes"`
+}
+
+func (s *CreateSupplierRequest) ToEntity() *SupplierEntity {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil
+	}
+
+	return &SupplierEntity{
+		ID:          id,
+		Name:        s.Name,
+		ContactName: s.ContactName,
+		Phone:       s.Phone,
+		Email:       s.Email,
+		Address:     s.Address,
+		Notes:       s.Notes,
+		CreatedBy:   "USER",
+		UpdatedBy:   "USER",
+	}
+}
+
+// TODO: add validation
+type UpdateSupplierRequest struct {
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	Address     string `json:"address"`
+	Notes       string `json:"notes"`
+	Version     int    `json:"version"`
+}
+
+func (s *UpdateSupplierRequest) ToEntity() *SupplierEntity {
+	return &SupplierEntity{
+		Name:        s.Name,
+		ContactName: s.ContactName,
+		Phone:       s.Phone,
+		Email:       s.Email,
+		Address:     s.Address,
+		Notes:       s.Notes,
+		Version:     s.Version,
+		UpdatedBy:   "USER",
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierEntity_ToModel(t *testing.T) {
+	entity := &SupplierEntity{ID: uuid.New(), Name: "PT Sumber Makmur", Phone: "0812", Version: 2}
+
+	supplier := entity.ToModel()
+
+	require.NotNil(t, supplier)
+	assert.NotEmpty(t, supplier.ID)
+	assert.Equal(t, "PT Sumber Makmur", supplier.Name)
+	assert.Equal(t, "0812", supplier.Phone)
+	assert.Equal(t, 2, supplier.Version)
+}
+
+func TestCreateSupplierRequest_ToEntity(t *testing.T) {
+	req := &CreateSupplierRequest{Name: "PT Sumber Makmur", Email: "sales@sumber.id"}
+
+	entity := req.ToEntity()
+
+	require.NotNil(t, entity)
+	assert.NotEqual(t, uuid.Nil, entity.ID)
+	assert.Equal(t, "sales@sumber.id", entity.Email)
+	assert.Equal(t, "USER", entity.CreatedBy)
+}
+
+func TestUpdateSupplierRequest_ToEntity(t *testing.T) {
+	entity := (&UpdateSupplierRequest{Name: "CV Jaya", Version: 3}).ToEntity()
+
+	assert.Equal(t, "CV Jaya", entity.Name)
+	assert.Equal(t, 3, entity.Version)
+	assert.Equal(t, "USER", entity.UpdatedBy)
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// SyncCreated means the offline sale was booked by this push
+	SyncCreated = "created"
+	// SyncDuplicate means a previous push already booked the sale, the terminal can drop it
+	SyncDuplicate = "duplicate"
+	// SyncRejected means the sale cannot be booked, the error tells why
+	SyncRejected = "rejected"
+
+	StockConflictOpen     = "open"
+	StockConflictResolved = "resolved"
+)
+
+// CatalogDelta is what changed in the catalog since the terminal's cursor, soft-deleted rows included
+type CatalogDelta struct {
+	Products   []CatalogProduct `json:"products"`
+	Categories []Category       `json:"categories"`
+	// PriceLists are every live price list, not only the changed ones, the terminal replaces the ones it has
+	PriceLists []PriceList `json:"price_lists"`
+	// Cursor is passed back on the next pull. It is opaque, a pull can send a product again but never skips one.
+	Cursor string `json:"cursor"`
+}
+
+// CatalogProduct is a product with everything a terminal needs to price it offline
+type CatalogProduct struct {
+	Product
+	// Prices are the scheduled base and price list prices, the terminal switches to each one as it starts
+	Prices     []ProductPrice     `json:"prices"`
+	PriceTiers []ProductPriceTier `json:"price_tiers"`
+	Units      []ProductUnit      `json:"units"`
+	// OutletPrice is the pulling outlet's price override, left out when the outlet sells at the product price
+	OutletPrice *int64 `json:"outlet_price,omitempty"`
+}
+
+func NewCatalogProduct(product ProductEntity) CatalogProduct {
+	return CatalogProduct{
+		Product:    *product.ToModel(),
+		Prices:     []ProductPrice{},
+		PriceTiers: []ProductPriceTier{},
+		Units:      []ProductUnit{},
+	}
+}
+
+// TODO: add validation
+type SyncTransactionsRequest struct {
+	Transactions []SyncTransactionRequest `json:"transactions"`
+}
+
+// SyncTransactionRequest is a sale rung up while the terminal was offline.
+// The terminal generates the UUIDv7 id, so pushing the same sale again does not book it twice.
+type SyncTransactionRequest struct {
+	ID         string                         `json:"id"` // UUIDv7, canonical or Base62
+	Items      []CreateTransactionItemRequest `json:"items"`
+	OutletID   string                         `json:"outlet_id"`   //Base62 of UUIDv7, optional
+	CustomerID string                         `json:"customer_id"` //Base62 of UUIDv7, optional
+	ShiftID    string                         `json:"shift_id"`    //Base62 of UUIDv7, optional
+	// PriceListID is the price list the terminal rang the sale up with, the prices are those in effect at CreatedAt
+	PriceListID string    `json:"price_list_id"` //Base62 of UUIDv7, optional
+	CreatedAt   time.Time `json:"created_at"`    // when the sale was rung up on the terminal
+}
+
+// ParseID parses the client id, canonical or Base62, uuid.Nil when it is neither or not a UUIDv7
+func (r *SyncTransactionRequest) ParseID() uuid.UUID {
+	id, err := uuid.Parse(r.ID)
+	if err != nil {
+		id = parseBase62OrNil(r.ID)
+	}
+	if id.Version() != 7 {
+		return uuid.Nil
+	}
+	return id
+}
+
+func (r *SyncTransactionRequest) ToCreateTransactionRequest() CreateTransactionRequest {
+	return CreateTransactionRequest{
+		Items:       r.Items,
+		OutletID:    r.OutletID,
+		CustomerID:  r.CustomerID,
+		ShiftID:     r.ShiftID,
+		PriceListID: r.PriceListID,
+		ID:          r.ParseID(),
+		OccurredAt:  r.CreatedAt,
+		Offline:     true,
+	}
+}
+
+// SyncTransactionResult is the outcome of one pushed sale, in the order they were pushed
+type SyncTransactionResult struct {
+	ID          string       `json:"id"` // as pushed
+	Status      string       `json:"status"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// StockConflictEntity is a product an offline sale sold more of than the books held
+type StockConflictEntity struct {
+	CreatedAt       time.Time
+	CreatedBy       string
+	ID              uuid.UUID //UUIDv7
+	TransactionID   uuid.UUID
+	ProductID       uuid.UUID
+	ProductName     string
+	OutletID        *uuid.UUID
+	Quantity        int // units sold beyond the stock, booked back as an adjustment
+	ResolvedAt      *time.Time
+	ResolvedBy      string
+	ResolutionNotes string
+}
+
+func (c *StockConflictEntity) IsResolved() bool {
+	return c.ResolvedAt != nil
+}
+
+type StockConflict struct {
+	ID              string     `json:"id"` //Base62 of UUIDv7
+	TransactionID   string     `json:"transaction_id"`
+	ProductID       string     `json:"product_id"`
+	ProductName     string     `json:"product_name"`
+	OutletID        string     `json:"outlet_id,omitempty"`
+	Quantity        int        `json:"quantity"`
+	Status          string     `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy      string     `json:"resolved_by,omitempty"`
+	ResolutionNotes string     `json:"resolution_notes,omitempty"`
+}
+
+func (c *StockConflictEntity) ToModel() *StockConflict {
+	var outletID string
+	if c.OutletID != nil {
+		outletID = utils.EncodeBase62(c.OutletID.String())
+	}
+	status := StockConflictOpen
+	if c.IsResolved() {
+		status = StockConflictResolved
+	}
+
+	return &StockConflict{
+		ID:              utils.EncodeBase62(c.ID.String()),
+		TransactionID:   utils.EncodeBase62(c.TransactionID.String()),
+		ProductID:       utils.EncodeBase62(c.ProductID.String()),
+		ProductName:     c.ProductName,
+		OutletID:        outletID,
+		Quantity:        c.Quantity,
+		Status:          status,
+		CreatedAt:       c.CreatedAt,
+		ResolvedAt:      c.ResolvedAt,
+		ResolvedBy:      c.ResolvedBy,
+		ResolutionNotes: c.ResolutionNotes,
+	}
+}
+
+// TODO: add validation
+type ResolveStockConflictRequest struct {
+	Notes string `json:"notes"` // what the review found, a recount or a late delivery
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncTransactionRequest_ParseID(t *testing.T) {
+	id := uuid.Must(uuid.NewV7())
+
+	canonical := SyncTransactionRequest{ID: id.String()}
+	assert.Equal(t, id, canonical.ParseID())
+	base62 := SyncTransactionRequest{ID: utils.EncodeBase62(id.String())}
+	assert.Equal(t, id, base62.ParseID())
+	v4 := SyncTransactionRequest{ID: uuid.NewString()}
+	assert.Equal(t, uuid.Nil, v4.ParseID(), "only terminal generated UUIDv7 ids are accepted")
+	garbage := SyncTransactionRequest{ID: "sale-1"}
+	assert.Equal(t, uuid.Nil, garbage.ParseID())
+}
+
+func TestSyncTransactionRequest_ToCreateTransactionRequest(t *testing.T) {
+	id := uuid.Must(uuid.NewV7())
+	occurredAt := time.Now().Add(-time.Hour)
+	sale := SyncTransactionRequest{
+		ID:        id.String(),
+		ShiftID:   "shift",
+		CreatedAt: occurredAt,
+		Items:     []CreateTransactionItemRequest{{ProductID: "kopi", Quantity: 2}},
+	}
+
+	req := sale.ToCreateTransactionRequest()
+	assert.True(t, req.Offline)
+	assert.Equal(t, id, req.ID)
+	assert.Equal(t, occurredAt, req.OccurredAt)
+	assert.Equal(t, "shift", req.ShiftID)
+	assert.Len(t, req.Items, 1)
+}
+
+func TestStockConflictEntity_ToModel(t *testing.T) {
+	outletID := uuid.New()
+	conflict := StockConflictEntity{ID: uuid.New(), TransactionID: uuid.New(), ProductID: uuid.New(), ProductName: "Kopi", OutletID: &outletID, Quantity: 2}
+
+	open := conflict.ToModel()
+	assert.Equal(t, StockConflictOpen, open.Status)
+	assert.Equal(t, utils.EncodeBase62(outletID.String()), open.OutletID)
+
+	resolvedAt := time.Now()
+	conflict.ResolvedAt, conflict.ResolvedBy = &resolvedAt, "USER"
+	assert.Equal(t, StockConflictResolved, conflict.ToModel().Status)
+
+	tx := TransactionEntity{ID: conflict.TransactionID, StockConflicts: []StockConflictEntity{conflict}}
+	assert.Len(t, tx.ToModel().StockConflicts, 1)
+}
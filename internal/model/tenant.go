@@ -0,0 +1,30 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantEntity is one shop hosted on the API, every core row belongs to exactly one
+type TenantEntity struct {
+	CreatedAt    time.Time
+	CreatedBy    string
+	UpdatedAt    time.Time
+	UpdatedBy    string
+	DeletedAt    *time.Time
+	Version      int
+	ID           uuid.UUID //UUIDv7
+	Slug         string    // the subdomain the shop is served on
+	Name         string
+	APITokenHash string // see HashAPIToken, the token itself is never stored
+	IsActive     bool
+}
+
+// HashAPIToken returns the hex SHA-256 of a bearer token as kept in core.tenant.api_token_hash
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
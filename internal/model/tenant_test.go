@@ -0,0 +1,14 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAPIToken(t *testing.T) {
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", HashAPIToken(""))
+	assert.Equal(t, HashAPIToken("secret"), HashAPIToken("secret"))
+	assert.NotEqual(t, HashAPIToken("secret"), HashAPIToken("Secret"))
+	assert.Len(t, HashAPIToken("secret"), 64)
+}
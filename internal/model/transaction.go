@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"time"
 
 	"codewithumam-kasir-api/internal/utils"
@@ -20,6 +21,25 @@ type TransactionEntity struct {
 	UpdatedBy         string
 	DeletedAt         *time.Time
 	Version           int
+
+	OutletID   *uuid.UUID // nil for sales not made at an outlet
+	CustomerID *uuid.UUID // nil for anonymous sales
+	ShiftID    *uuid.UUID // the cashier shift whose drawer took the sale
+	// PriceListID is the price list the sale was priced from, nil for base prices
+	PriceListID *uuid.UUID
+
+	PointsEarned         int
+	PointsRedeemed       int
+	PointsDiscountAmount int64                // spread over the details' totals, TotalPriceAmount is what was paid
+	LoyaltyEntries       []LoyaltyEntryEntity // not persisted with the transaction, the ledger entries to book with it
+
+	GiftCardID     *uuid.UUID
+	GiftCardAmount int64                // the part of TotalPriceAmount the gift card paid
+	GiftCardEntry  *GiftCardEntryEntity // not persisted with the transaction, the redemption to book with it
+
+	DraftOrderID *uuid.UUID // not persisted with the transaction, the draft order converted by the sale
+
+	StockConflicts []StockConflictEntity // the units an offline sale sold beyond the stock, booked with it
 }
 
 type TransactionDetailEntity struct {
@@ -43,6 +63,22 @@ type TransactionDetailEntity struct {
 	UpdatedBy         string
 	DeletedAt         *time.Time
 	Version           int
+	Components        []BundleComponentEntity // not persisted, the stock to consume when the product is a bundle
+
+	CostPriceAmount int64 // unit cost snapshotted at sale time
+	TotalCostAmount int64
+	Lots            []LotAllocationEntity // not persisted, the lots to consume first expiry first
+
+	PriceTierMinQuantity *int // the quantity tier the unit price came from, nil when no tier applied
+
+	// PointsDiscountAmount is the line's share of the points redeemed on the sale, already taken off TotalPriceAmount
+	PointsDiscountAmount int64
+
+	// Quantity above counts 10^-QuantityScale of the product's base unit, Unit and UnitQuantity
+	// are what was rung up, such as 2 cartons, and PriceAmount is per Unit
+	QuantityScale int
+	Unit          string
+	UnitQuantity  float64
 }
 
 type Transaction struct {
@@ -51,6 +87,20 @@ type Transaction struct {
 	TotalPrice Price               `json:"total_price"`
 	CreatedAt  time.Time           `json:"created_at"`
 	Details    []TransactionDetail `json:"details,omitempty"`
+	OutletID   string              `json:"outlet_id,omitempty"`
+	CustomerID string              `json:"customer_id,omitempty"`
+	ShiftID    string              `json:"shift_id,omitempty"`
+	// PriceListID is the list the sale was priced from, empty for base prices
+	PriceListID string `json:"price_list_id,omitempty"`
+
+	PointsEarned         int   `json:"points_earned,omitempty"`
+	PointsRedeemed       int   `json:"points_redeemed,omitempty"`
+	PointsDiscountAmount int64 `json:"points_discount_amount,omitempty"`
+
+	GiftCardID     string `json:"gift_card_id,omitempty"`
+	GiftCardAmount int64  `json:"gift_card_amount,omitempty"`
+
+	StockConflicts []StockConflict `json:"stock_conflicts,omitempty"`
 }
 
 type TransactionDetail struct {
@@ -61,6 +111,14 @@ type TransactionDetail struct {
 	Price        Price  `json:"price"`
 	Quantity     int    `json:"quantity"`
 	TotalPrice   Price  `json:"total_price"`
+	// PriceTierMinQuantity is the quantity tier the unit price came from, left out when no tier applied
+	PriceTierMinQuantity *int `json:"price_tier_min_quantity,omitempty"`
+	// PointsDiscountAmount is the line's share of the points discount, total_price is net of it
+	PointsDiscountAmount int64 `json:"points_discount_amount,omitempty"`
+	// Quantity counts 10^-quantity_scale of the base unit, unit_quantity of unit is what was rung up
+	QuantityScale int     `json:"quantity_scale"`
+	Unit          string  `json:"unit,omitempty"`
+	UnitQuantity  float64 `json:"unit_quantity,omitempty"`
 }
 
 type Price struct {
@@ -71,15 +129,65 @@ type Price struct {
 }
 
 type CreateTransactionRequest struct {
-	Items []CreateTransactionItemRequest `json:"items"`
+	Items      []CreateTransactionItemRequest `json:"items"`
+	OutletID   string                         `json:"outlet_id"`   //Base62 of UUIDv7, optional
+	CustomerID string                         `json:"customer_id"` //Base62 of UUIDv7, optional
+	// ShiftID rings the sale up on an open shift, the sale is at the shift's outlet unless OutletID says otherwise
+	ShiftID string `json:"shift_id"` //Base62 of UUIDv7, optional
+	// PriceListID prices the sale from the list, the customer's list when it is left out
+	PriceListID string `json:"price_list_id"` //Base62 of UUIDv7, optional
+	// RedeemPoints are taken off the sale at the program's point value, it needs a customer holding them
+	RedeemPoints int `json:"redeem_points"`
+	// GiftCardCode pays GiftCardAmount of the sale from the card, all the card covers when the amount is left out
+	GiftCardCode   string `json:"gift_card_code"`
+	GiftCardAmount int64  `json:"gift_card_amount"`
+	// DraftOrderID is set when a draft order is converted, its own reservation does not count against it
+	DraftOrderID string `json:"-"`
+	// Offline is set for a sale synced from a terminal, it keeps the terminal's ID and OccurredAt
+	// and books the units sold beyond the stock as conflicts instead of refusing the sale
+	Offline    bool      `json:"-"`
+	ID         uuid.UUID `json:"-"`
+	OccurredAt time.Time `json:"-"`
 }
 
 type CreateTransactionItemRequest struct {
 	ProductID string `json:"product_id"`
-	Quantity  int    `json:"quantity"`
+	// Quantity of Unit, decimals down to the product's quantity scale, e.g. 0.75 kg
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"` // one of the product's units, its base unit when left out
+	// Barcode is a price-embedded weighing scale label used instead of ProductID and Quantity,
+	// the product is the one whose SKU is the label's item code and the line costs the label's price
+	Barcode string `json:"barcode"`
+	// UnitPrice is what an offline terminal charged per Unit, it is kept on a synced sale as long as it is
+	// near the server's price and ignored on a sale rung up online
+	UnitPrice *int64 `json:"unit_price,omitempty"`
+	// StockQuantity is a quantity already counted in stock units, set when a draft order is converted
+	StockQuantity int `json:"-"`
 }
 
 func (e *TransactionEntity) ToModel() *Transaction {
+	var outletID, customerID, shiftID, giftCardID, priceListID string
+	if e.OutletID != nil {
+		outletID = utils.EncodeBase62(e.OutletID.String())
+	}
+	if e.CustomerID != nil {
+		customerID = utils.EncodeBase62(e.CustomerID.String())
+	}
+	if e.ShiftID != nil {
+		shiftID = utils.EncodeBase62(e.ShiftID.String())
+	}
+	if e.GiftCardID != nil {
+		giftCardID = utils.EncodeBase62(e.GiftCardID.String())
+	}
+	if e.PriceListID != nil {
+		priceListID = utils.EncodeBase62(e.PriceListID.String())
+	}
+
+	var conflicts []StockConflict
+	for _, c := range e.StockConflicts {
+		conflicts = append(conflicts, *c.ToModel())
+	}
+
 	return &Transaction{
 		ID:         utils.EncodeBase62(e.ID.String()),
 		TotalItems: e.TotalItems,
@@ -89,7 +197,21 @@ func (e *TransactionEntity) ToModel() *Transaction {
 			Display:  e.TotalPriceDisplay,
 			Currency: e.Currency,
 		},
-		CreatedAt: e.CreatedAt,
+		CreatedAt:  e.CreatedAt,
+		OutletID:   outletID,
+		CustomerID: customerID,
+		ShiftID:    shiftID,
+
+		PriceListID: priceListID,
+
+		PointsEarned:         e.PointsEarned,
+		PointsRedeemed:       e.PointsRedeemed,
+		PointsDiscountAmount: e.PointsDiscountAmount,
+
+		GiftCardID:     giftCardID,
+		GiftCardAmount: e.GiftCardAmount,
+
+		StockConflicts: conflicts,
 	}
 }
 
@@ -120,6 +242,11 @@ func (e *TransactionDetailEntity) ToModel() *TransactionDetail {
 			Display:  e.TotalPriceDisplay,
 			Currency: e.Currency,
 		},
+		PriceTierMinQuantity: e.PriceTierMinQuantity,
+		PointsDiscountAmount: e.PointsDiscountAmount,
+		QuantityScale:        e.QuantityScale,
+		Unit:                 e.Unit,
+		UnitQuantity:         e.UnitQuantity,
 	}
 }
 
@@ -130,12 +257,104 @@ type ReportResponse struct {
 	TopPopularCategories []PopularCategory `json:"top_popular_categories"`
 }
 
+// TotalSoldQty counts base units, e.g. 1.25 for a kilo and a quarter of rice
 type PopularItem struct {
-	Name         string `json:"name"`
-	TotalSoldQty int    `json:"total_sold_qty"`
+	Name         string  `json:"name"`
+	TotalSoldQty float64 `json:"total_sold_qty"`
 }
 
 type PopularCategory struct {
-	Name         string `json:"name"`
-	TotalSoldQty int    `json:"total_sold_qty"`
+	Name string `json:"name"`
+	// Path names the categories from the top level down to this one
+	Path         []string `json:"path,omitempty"`
+	TotalSoldQty float64  `json:"total_sold_qty"`
+}
+
+// SalesMarginEntity is the revenue and cost of one product sold over a period
+type SalesMarginEntity struct {
+	ProductID    *uuid.UUID
+	ProductName  string
+	CategoryID   *uuid.UUID
+	CategoryName string
+	Quantity     float64 // in base units, not stock units
+	Revenue      int64
+	COGS         int64
+}
+
+type MarginReport struct {
+	StartDate     time.Time    `json:"start_date"`
+	EndDate       time.Time    `json:"end_date"`
+	Revenue       int64        `json:"revenue"`
+	COGS          int64        `json:"cogs"`
+	GrossProfit   int64        `json:"gross_profit"`
+	MarginPercent float64      `json:"margin_percent"`
+	Products      []MarginLine `json:"products"`
+	Categories    []MarginLine `json:"categories"`
+}
+
+type MarginLine struct {
+	ID            string  `json:"id,omitempty"` //Base62 of UUIDv7, empty once the product or category is gone
+	Name          string  `json:"name"`
+	QuantitySold  float64 `json:"quantity_sold"`
+	Revenue       int64   `json:"revenue"`
+	COGS          int64   `json:"cogs"`
+	GrossProfit   int64   `json:"gross_profit"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+func newMarginLine(id *uuid.UUID, name string) MarginLine {
+	var encoded string
+	if id != nil {
+		encoded = utils.EncodeBase62(id.String())
+	}
+	return MarginLine{ID: encoded, Name: name}
+}
+
+func (l *MarginLine) add(quantity float64, revenue, cogs int64) {
+	l.QuantitySold += quantity
+	l.Revenue += revenue
+	l.COGS += cogs
+	l.GrossProfit = l.Revenue - l.COGS
+	l.MarginPercent = marginPercent(l.GrossProfit, l.Revenue)
+}
+
+// marginPercent is gross profit over revenue rounded to two decimals, 0 without revenue
+func marginPercent(grossProfit, revenue int64) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return math.Round(float64(grossProfit)*10000/float64(revenue)) / 100
+}
+
+// NewMarginReport totals the per product rows and rolls them up by category
+func NewMarginReport(startDate, endDate time.Time, rows []SalesMarginEntity) *MarginReport {
+	report := &MarginReport{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Products:   []MarginLine{},
+		Categories: []MarginLine{},
+	}
+
+	categoryIndex := map[string]int{}
+	for _, row := range rows {
+		product := newMarginLine(row.ProductID, row.ProductName)
+		product.add(row.Quantity, row.Revenue, row.COGS)
+		report.Products = append(report.Products, product)
+
+		category := newMarginLine(row.CategoryID, row.CategoryName)
+		key := category.ID + "|" + category.Name
+		i, ok := categoryIndex[key]
+		if !ok {
+			i = len(report.Categories)
+			categoryIndex[key] = i
+			report.Categories = append(report.Categories, category)
+		}
+		report.Categories[i].add(row.Quantity, row.Revenue, row.COGS)
+
+		report.Revenue += row.Revenue
+		report.COGS += row.COGS
+	}
+	report.GrossProfit = report.Revenue - report.COGS
+	report.MarginPercent = marginPercent(report.GrossProfit, report.Revenue)
+	return report
 }
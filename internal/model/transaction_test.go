@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"codewithumam-kasir-api/internal/utils"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -28,6 +30,17 @@ func TestTransactionEntity_ToModel(t *testing.T) {
 	assert.Equal(t, int64(5000), model.TotalPrice.Amount)
 }
 
+func TestTransactionEntity_ToModel_GiftCard(t *testing.T) {
+	giftCardID := uuid.New()
+	entity := &TransactionEntity{ID: uuid.New(), TotalPriceAmount: 20000, GiftCardID: &giftCardID, GiftCardAmount: 15000}
+
+	model := entity.ToModel()
+
+	assert.Equal(t, utils.EncodeBase62(giftCardID.String()), model.GiftCardID)
+	assert.Equal(t, int64(15000), model.GiftCardAmount)
+	assert.Equal(t, int64(20000), model.TotalPrice.Amount)
+}
+
 func TestTransactionDetailEntity_ToModel(t *testing.T) {
 	pID, _ := uuid.NewV7()
 	cID, _ := uuid.NewV7()
@@ -47,3 +60,39 @@ func TestTransactionDetailEntity_ToModel(t *testing.T) {
 	assert.Equal(t, "Product A", model.ProductName)
 	assert.Equal(t, 2, model.Quantity)
 }
+
+func TestNewMarginReport(t *testing.T) {
+	coffee, tea, drinks := uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	report := NewMarginReport(start, end, []SalesMarginEntity{
+		{ProductID: &coffee, ProductName: "Kopi", CategoryID: &drinks, CategoryName: "Minuman", Quantity: 3, Revenue: 30000, COGS: 12000},
+		{ProductID: &tea, ProductName: "Teh", CategoryID: &drinks, CategoryName: "Minuman", Quantity: 2, Revenue: 10000, COGS: 7000},
+		{ProductName: "Deleted", CategoryName: "Lainnya", Quantity: 1, Revenue: 0, COGS: 500},
+	})
+
+	assert.Equal(t, int64(40000), report.Revenue)
+	assert.Equal(t, int64(19500), report.COGS)
+	assert.Equal(t, int64(20500), report.GrossProfit)
+	assert.Equal(t, 51.25, report.MarginPercent)
+
+	assert.Len(t, report.Products, 3)
+	assert.Equal(t, 60.0, report.Products[0].MarginPercent)
+	assert.Equal(t, 30.0, report.Products[1].MarginPercent)
+	assert.Empty(t, report.Products[2].ID)
+	assert.Equal(t, 0.0, report.Products[2].MarginPercent, "no revenue means no margin")
+
+	assert.Len(t, report.Categories, 2)
+	assert.Equal(t, "Minuman", report.Categories[0].Name)
+	assert.Equal(t, 5.0, report.Categories[0].QuantitySold)
+	assert.Equal(t, int64(21000), report.Categories[0].GrossProfit)
+	assert.Equal(t, 52.5, report.Categories[0].MarginPercent)
+}
+
+func TestNewMarginReport_Empty(t *testing.T) {
+	report := NewMarginReport(time.Now(), time.Now(), nil)
+	assert.NotNil(t, report.Products)
+	assert.NotNil(t, report.Categories)
+	assert.Zero(t, report.MarginPercent)
+}
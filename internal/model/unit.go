@@ -0,0 +1,166 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultUnit is the base unit of a product that names none
+	DefaultUnit = "pcs"
+	// MaxQuantityScale is the finest a base unit can be split, 3 decimals is a gram of a kilo
+	MaxQuantityScale = 3
+)
+
+// QuantityMultiplier is how many stock units one whole base unit holds at the scale,
+// stock and sold quantities are kept in 10^-scale of the base unit like a price amount
+func QuantityMultiplier(scale int) int {
+	multiplier := 1
+	for range scale {
+		multiplier *= 10
+	}
+	return multiplier
+}
+
+// ToStockQuantity converts a decimal quantity of a unit worth factor stock units. It reports false
+// when the quantity is finer than the stock can count, e.g. 0.7505 kg of a product kept in grams.
+func ToStockQuantity(quantity float64, factor int) (int, bool) {
+	exact := quantity * float64(factor)
+	rounded := math.Round(exact)
+	if math.Abs(exact-rounded) > 1e-6 || rounded > math.MaxInt32 {
+		return 0, false
+	}
+	return int(rounded), true
+}
+
+// FromStockQuantity converts stock units back to a decimal quantity of a unit worth factor stock units
+func FromStockQuantity(quantity, factor int) float64 {
+	return float64(quantity) / float64(factor)
+}
+
+// RoundDiv divides rounding half away from zero, it prices fractions of a unit
+func RoundDiv(a, b int64) int64 {
+	if (a < 0) != (b < 0) {
+		return (a - b/2) / b
+	}
+	return (a + b/2) / b
+}
+
+// ProductUnitEntity is a unit the product is also sold in, such as a carton of 24 pieces
+type ProductUnitEntity struct {
+	CreatedAt time.Time
+	CreatedBy string
+	ID        uuid.UUID //UUIDv7
+	ProductID uuid.UUID
+	Name      string
+	Factor    int    // stock units one of this unit holds, at the product's quantity scale
+	Price     *int64 // the unit's own price, nil to charge Factor's worth of the base price
+}
+
+type ProductUnit struct {
+	ID        string  `json:"id"`         //Base62 of UUIDv7
+	ProductID string  `json:"product_id"` //Base62 of UUIDv7
+	Name      string  `json:"name"`
+	Factor    float64 `json:"factor"` // base units one of this unit holds
+	Price     *int64  `json:"price,omitempty"`
+}
+
+// ToModel shows Factor in whole base units, scale being the product's quantity scale
+func (u *ProductUnitEntity) ToModel(scale int) *ProductUnit {
+	return &ProductUnit{
+		ID:        utils.EncodeBase62(u.ID.String()),
+		ProductID: utils.EncodeBase62(u.ProductID.String()),
+		Name:      u.Name,
+		Factor:    FromStockQuantity(u.Factor, QuantityMultiplier(scale)),
+		Price:     u.Price,
+	}
+}
+
+// FindUnit looks a unit up by name regardless of case, it returns nil when the product has no such unit
+func FindUnit(units []ProductUnitEntity, name string) *ProductUnitEntity {
+	for i, u := range units {
+		if strings.EqualFold(u.Name, strings.TrimSpace(name)) {
+			return &units[i]
+		}
+	}
+	return nil
+}
+
+// TODO: add validation
+type ProductUnitRequest struct {
+	Name   string  `json:"name"`
+	Factor float64 `json:"factor"` // base units one of this unit holds, e.g. 24 for a carton of 24 pieces
+	Price  *int64  `json:"price"`  // optional, Factor's worth of the base price when left out
+}
+
+// ReplaceProductUnitsRequest is the product's whole set of units, an empty one removes them
+type ReplaceProductUnitsRequest struct {
+	Units []ProductUnitRequest `json:"units"`
+}
+
+// ToEntities converts the factors to stock units at the product's quantity scale,
+// a factor the scale cannot count is kept as 0 so the service can reject it
+func (r *ReplaceProductUnitsRequest) ToEntities(productID uuid.UUID, scale int) []ProductUnitEntity {
+	units := []ProductUnitEntity{}
+	for _, u := range r.Units {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil
+		}
+		factor, ok := ToStockQuantity(u.Factor, QuantityMultiplier(scale))
+		if !ok {
+			factor = 0
+		}
+		units = append(units, ProductUnitEntity{
+			ID:        id,
+			ProductID: productID,
+			Name:      strings.TrimSpace(u.Name),
+			Factor:    factor,
+			Price:     u.Price,
+			CreatedBy: "USER",
+		})
+	}
+	return units
+}
+
+// ScaleBarcode is what a price-embedded EAN-13 printed by a weighing scale carries:
+// a 2x prefix, a 5 digit item code, the 5 digit price of the weighed goods and a check digit
+type ScaleBarcode struct {
+	ItemCode string // the scale's PLU, matched against the product's SKU
+	Price    int64
+}
+
+// ParseScaleBarcode reads a price-embedded weighing scale barcode, it reports false for any other code
+func ParseScaleBarcode(code string) (ScaleBarcode, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != 13 || code[0] != '2' {
+		return ScaleBarcode{}, false
+	}
+	sum := 0
+	for i, c := range code {
+		if c < '0' || c > '9' {
+			return ScaleBarcode{}, false
+		}
+		if i == 12 {
+			break
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+	if (10-sum%10)%10 != int(code[12]-'0') {
+		return ScaleBarcode{}, false
+	}
+
+	var price int64
+	for _, c := range code[7:12] {
+		price = price*10 + int64(c-'0')
+	}
+	return ScaleBarcode{ItemCode: code[2:7], Price: price}, true
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToStockQuantity(t *testing.T) {
+	grams := QuantityMultiplier(3)
+	assert.Equal(t, 1000, grams)
+
+	quantity, ok := ToStockQuantity(0.75, grams)
+	assert.True(t, ok)
+	assert.Equal(t, 750, quantity)
+
+	quantity, ok = ToStockQuantity(2, 24)
+	assert.True(t, ok, "two cartons of 24")
+	assert.Equal(t, 48, quantity)
+
+	_, ok = ToStockQuantity(0.7505, grams)
+	assert.False(t, ok, "half a gram cannot be counted")
+	_, ok = ToStockQuantity(1.5, QuantityMultiplier(0))
+	assert.False(t, ok, "a product sold whole cannot sell half")
+
+	assert.Equal(t, 0.75, FromStockQuantity(750, grams))
+}
+
+func TestRoundDiv(t *testing.T) {
+	assert.Equal(t, int64(9375), RoundDiv(12500*750, 1000))
+	assert.Equal(t, int64(2), RoundDiv(5, 3))
+	assert.Equal(t, int64(1), RoundDiv(4, 3))
+	assert.Equal(t, int64(-2), RoundDiv(-5, 3))
+}
+
+func TestParseScaleBarcode(t *testing.T) {
+	label, ok := ParseScaleBarcode("2000123093758")
+	require.True(t, ok)
+	assert.Equal(t, "00123", label.ItemCode)
+	assert.Equal(t, int64(9375), label.Price)
+
+	for _, code := range []string{
+		"2000123093759", // wrong check digit
+		"8991234567893", // a manufacturer's EAN-13
+		"20001230937",
+		"20001230937a8",
+	} {
+		_, ok := ParseScaleBarcode(code)
+		assert.False(t, ok, code)
+	}
+}
+
+func TestReplaceProductUnitsRequest_ToEntities(t *testing.T) {
+	productID := uuid.New()
+	cartonPrice := int64(80000)
+	units := (&ReplaceProductUnitsRequest{Units: []ProductUnitRequest{
+		{Name: " Carton ", Factor: 24, Price: &cartonPrice},
+		{Name: "pack", Factor: 0.0005},
+	}}).ToEntities(productID, 0)
+
+	require.Len(t, units, 2)
+	assert.Equal(t, "Carton", units[0].Name)
+	assert.Equal(t, 24, units[0].Factor)
+	assert.Equal(t, 0, units[1].Factor, "a factor the scale cannot count is left for the service to reject")
+	assert.Equal(t, &units[0], FindUnit(units, "carton"))
+	assert.Nil(t, FindUnit(units, "box"))
+
+	model := (&ProductUnitEntity{ID: uuid.New(), ProductID: productID, Name: "sack", Factor: 5000}).ToModel(3)
+	assert.Equal(t, 5.0, model.Factor, "a 5 kg sack of a product kept in grams")
+}
+
+func TestCreateProductRequest_ToEntity_Unit(t *testing.T) {
+	entity := (&CreateProductRequest{Name: "Beras", Unit: "kg", QuantityScale: 3}).ToEntity()
+	assert.Equal(t, "kg", entity.Unit)
+	assert.Equal(t, 3, entity.QuantityScale)
+
+	entity = (&CreateProductRequest{Name: "Kopi"}).ToEntity()
+	assert.Equal(t, DefaultUnit, entity.Unit)
+	assert.Equal(t, 0, entity.QuantityScale)
+}
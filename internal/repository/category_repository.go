@@ -11,4 +11,7 @@ type CategoryRepository interface {
 	InsertCategory(category model.CategoryEntity) (model.CategoryEntity, error)
 	UpdateCategoryByID(id string, category model.CategoryEntity) (model.CategoryEntity, error)
 	DeleteCategoryByID(id string) error
+	// FindCategoriesChangedSince returns the categories written at or after the since cursor, soft-deleted ones included.
+	// The cursor comes from ProductRepository.FindProductsChangedSince.
+	FindCategoriesChangedSince(since uint64) ([]model.CategoryEntity, error)
 }
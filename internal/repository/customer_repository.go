@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type CustomerRepository interface {
+	FindCustomers() ([]model.CustomerEntity, error)
+	FindCustomerByID(id string) (model.CustomerEntity, error)
+	// FindCustomersByPhone takes a normalized phone number, at most one active customer holds it
+	FindCustomersByPhone(phone string) ([]model.CustomerEntity, error)
+	InsertCustomer(customer model.CustomerEntity) (model.CustomerEntity, error)
+	UpdateCustomerByID(id string, customer model.CustomerEntity) (model.CustomerEntity, error)
+	DeleteCustomerByID(id string) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
+)
+
+type DiningTableRepository interface {
+	// FindDiningTables lists the outlet's tables by area and name, every table when outletID is nil
+	FindDiningTables(outletID *uuid.UUID) ([]model.DiningTableEntity, error)
+	FindDiningTableByID(id string) (model.DiningTableEntity, error)
+	InsertDiningTable(table model.DiningTableEntity) (model.DiningTableEntity, error)
+	UpdateDiningTableByID(id string, table model.DiningTableEntity) (model.DiningTableEntity, error)
+}
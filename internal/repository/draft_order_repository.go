@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
+)
+
+type DraftOrderRepository interface {
+	// FindDraftOrders returns the drafts in the given status newest first, the open and held ones when status is empty
+	FindDraftOrders(status string) ([]model.DraftOrderEntity, error)
+	FindDraftOrderByID(id string) (model.DraftOrderEntity, error)
+	InsertDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error)
+	// UpdateDraftOrder saves the status, label, table number, dining table and notes, failing unless the draft is open or held
+	UpdateDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error)
+	// SetDraftOrderItem sets the product's quantity on the draft, zero removes the line.
+	// It fails unless the draft is open or held.
+	SetDraftOrderItem(item model.DraftOrderItemEntity) (model.DraftOrderEntity, error)
+	// FindReservedStocks sums the units the reserving drafts at the outlet hold, keyed by the product holding the stock:
+	// a bundle's units are held on its components. The draft exceptID is left out, every draft counts when it is empty.
+	FindReservedStocks(outletID *uuid.UUID, exceptID string) (map[uuid.UUID]int, error)
+}
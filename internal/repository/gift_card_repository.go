@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type GiftCardRepository interface {
+	FindGiftCards() ([]model.GiftCardEntity, error)
+	// FindGiftCardByCode returns the zero card, with a nil ID, when no card has the code
+	FindGiftCardByCode(code string) (model.GiftCardEntity, error)
+	// InsertGiftCard issues the card and books its initial amount as the issue entry
+	InsertGiftCard(card model.GiftCardEntity) (model.GiftCardEntity, error)
+	// FindGiftCardEntries returns the card's ledger oldest first
+	FindGiftCardEntries(giftCardID string) ([]model.GiftCardEntryEntity, error)
+	InsertGiftCardEntry(entry model.GiftCardEntryEntity) (model.GiftCardEntryEntity, error)
+}
@@ -6,6 +6,7 @@ import (
 
 	"fmt"
 	"github.com/google/uuid"
+	"sort"
 )
 
 type CategoryRepositoryInMemoryImpl struct {
@@ -77,3 +78,16 @@ func (r *CategoryRepositoryInMemoryImpl) DeleteCategoryByID(id string) error {
 	}
 	return fmt.Errorf("category not found")
 }
+
+func (r *CategoryRepositoryInMemoryImpl) FindCategoriesChangedSince(since uint64) ([]model.CategoryEntity, error) {
+	var categories []model.CategoryEntity
+	for _, c := range r.categories {
+		if c.ChangeSeq >= since {
+			categories = append(categories, c)
+		}
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].ChangeSeq < categories[j].ChangeSeq
+	})
+	return categories, nil
+}
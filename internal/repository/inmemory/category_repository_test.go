@@ -179,3 +179,14 @@ func TestInMemoryCategoryRepository_InvalidUUID(t *testing.T) {
 	err = repo.DeleteCategoryByID("invalid-uuid")
 	assert.Error(t, err)
 }
+
+func TestInMemoryCategoryRepository_FindCategoriesChangedSince(t *testing.T) {
+	repo := NewCategoryRepository()
+	_, _ = repo.InsertCategory(model.CategoryEntity{ID: uuid.New(), Name: "Old", ChangeSeq: 90})
+	_, _ = repo.InsertCategory(model.CategoryEntity{ID: uuid.New(), Name: "Drinks", ChangeSeq: 120})
+
+	categories, err := repo.FindCategoriesChangedSince(100)
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	assert.Equal(t, "Drinks", categories[0].Name)
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errCustomerNotFound = "customer not found"
+	errPointsNegative   = "customer points balance cannot go below zero"
+)
+
+type CustomerRepositoryInMemoryImpl struct {
+	customers []model.CustomerEntity
+}
+
+func NewCustomerRepository() repository.CustomerRepository {
+	return &CustomerRepositoryInMemoryImpl{
+		customers: []model.CustomerEntity{},
+	}
+}
+
+func (r *CustomerRepositoryInMemoryImpl) FindCustomers() ([]model.CustomerEntity, error) {
+	var customers []model.CustomerEntity
+	for _, c := range r.customers {
+		if c.DeletedAt == nil {
+			customers = append(customers, c)
+		}
+	}
+	return customers, nil
+}
+
+func (r *CustomerRepositoryInMemoryImpl) FindCustomersByPhone(phone string) ([]model.CustomerEntity, error) {
+	var customers []model.CustomerEntity
+	for _, c := range r.customers {
+		if c.DeletedAt == nil && c.Phone != "" && c.Phone == phone {
+			customers = append(customers, c)
+		}
+	}
+	return customers, nil
+}
+
+func (r *CustomerRepositoryInMemoryImpl) FindCustomerByID(id string) (model.CustomerEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.CustomerEntity{}, err
+	}
+	return r.customers[i], nil
+}
+
+func (r *CustomerRepositoryInMemoryImpl) InsertCustomer(customer model.CustomerEntity) (model.CustomerEntity, error) {
+	customer.CreatedAt = time.Now()
+	customer.UpdatedAt = customer.CreatedAt
+	customer.Version = 1
+	r.customers = append(r.customers, customer)
+	return customer, nil
+}
+
+func (r *CustomerRepositoryInMemoryImpl) UpdateCustomerByID(id string, customer model.CustomerEntity) (model.CustomerEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.CustomerEntity{}, err
+	}
+
+	existing := r.customers[i]
+	customer.ID = existing.ID
+	customer.CreatedAt = existing.CreatedAt
+	customer.CreatedBy = existing.CreatedBy
+	customer.PointsBalance = existing.PointsBalance
+	customer.UpdatedAt = time.Now()
+	customer.Version = existing.Version + 1
+	r.customers[i] = customer
+	return customer, nil
+}
+
+func (r *CustomerRepositoryInMemoryImpl) DeleteCustomerByID(id string) error {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r.customers[i].DeletedAt = &now
+	return nil
+}
+
+func (r *CustomerRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errCustomerNotFound)
+	}
+	for i, c := range r.customers {
+		if c.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errCustomerNotFound)
+}
+
+// applyPoints moves the cached points balance like trg_loyalty_ledger_apply, it never lets it go below zero
+func (r *CustomerRepositoryInMemoryImpl) applyPoints(id uuid.UUID, points int) (int, error) {
+	i, err := r.indexOf(id.String())
+	if err != nil {
+		return 0, err
+	}
+	balance := r.customers[i].PointsBalance + points
+	if balance < 0 {
+		return 0, errors.New(errPointsNegative)
+	}
+	r.customers[i].PointsBalance = balance
+	return balance, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryCustomerRepository_CRUD(t *testing.T) {
+	repo := NewCustomerRepository()
+
+	id := uuid.New()
+	inserted, err := repo.InsertCustomer(model.CustomerEntity{ID: id, Name: "Budi", Phone: "08123456"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, inserted.Version)
+
+	updated, err := repo.UpdateCustomerByID(id.String(), model.CustomerEntity{Name: "Budi Santoso", Phone: "08123456"})
+	require.NoError(t, err)
+	assert.Equal(t, id, updated.ID)
+	assert.Equal(t, 2, updated.Version)
+
+	byPhone, err := repo.FindCustomersByPhone("08123456")
+	require.NoError(t, err)
+	require.Len(t, byPhone, 1)
+	assert.Equal(t, "Budi Santoso", byPhone[0].Name)
+
+	require.NoError(t, repo.DeleteCustomerByID(id.String()))
+	customers, err := repo.FindCustomers()
+	require.NoError(t, err)
+	assert.Empty(t, customers)
+	byPhone, err = repo.FindCustomersByPhone("08123456")
+	require.NoError(t, err)
+	assert.Empty(t, byPhone)
+
+	_, err = repo.FindCustomerByID("invalid")
+	assert.Error(t, err)
+}
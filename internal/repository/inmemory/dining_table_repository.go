@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errDiningTableNotFound = "dining table not found"
+	errDiningTableExists   = "dining table name already exists at the outlet"
+	errDiningTableChanged  = "dining table was changed"
+)
+
+type DiningTableRepositoryInMemoryImpl struct {
+	tables    []model.DiningTableEntity
+	draftRepo repository.DraftOrderRepository
+}
+
+// NewDiningTableRepository looks the seated draft orders up on draftRepo, like diningTableSelect joins them
+func NewDiningTableRepository(draftRepo repository.DraftOrderRepository) repository.DiningTableRepository {
+	return &DiningTableRepositoryInMemoryImpl{
+		tables:    []model.DiningTableEntity{},
+		draftRepo: draftRepo,
+	}
+}
+
+func (r *DiningTableRepositoryInMemoryImpl) FindDiningTables(outletID *uuid.UUID) ([]model.DiningTableEntity, error) {
+	var tables []model.DiningTableEntity
+	for _, t := range r.tables {
+		if outletID == nil || sameOutlet(t.OutletID, outletID) {
+			tables = append(tables, t)
+		}
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		if tables[i].Area != tables[j].Area {
+			return tables[i].Area < tables[j].Area
+		}
+		return tables[i].Name < tables[j].Name
+	})
+	return r.withDraftOrders(tables...)
+}
+
+func (r *DiningTableRepositoryInMemoryImpl) FindDiningTableByID(id string) (model.DiningTableEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.DiningTableEntity{}, err
+	}
+	tables, err := r.withDraftOrders(r.tables[i])
+	if err != nil {
+		return model.DiningTableEntity{}, err
+	}
+	return tables[0], nil
+}
+
+func (r *DiningTableRepositoryInMemoryImpl) InsertDiningTable(table model.DiningTableEntity) (model.DiningTableEntity, error) {
+	if r.nameTaken(table, uuid.Nil) {
+		return model.DiningTableEntity{}, errors.New(errDiningTableExists)
+	}
+	table.CreatedAt = time.Now()
+	table.UpdatedAt = table.CreatedAt
+	table.Version = 1
+	table.DraftOrderID = nil
+	r.tables = append(r.tables, table)
+	return table, nil
+}
+
+func (r *DiningTableRepositoryInMemoryImpl) UpdateDiningTableByID(id string, table model.DiningTableEntity) (model.DiningTableEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.DiningTableEntity{}, err
+	}
+	existing := &r.tables[i]
+	if existing.Version != table.Version {
+		return model.DiningTableEntity{}, errors.New(errDiningTableChanged)
+	}
+	table.OutletID = existing.OutletID
+	if r.nameTaken(table, existing.ID) {
+		return model.DiningTableEntity{}, errors.New(errDiningTableExists)
+	}
+
+	existing.Area = table.Area
+	existing.Name = table.Name
+	existing.Seats = table.Seats
+	existing.IsActive = table.IsActive
+	existing.UpdatedBy = table.UpdatedBy
+	existing.UpdatedAt = time.Now()
+	existing.Version++
+	return r.FindDiningTableByID(id)
+}
+
+// nameTaken mirrors idx_dining_table_name
+func (r *DiningTableRepositoryInMemoryImpl) nameTaken(table model.DiningTableEntity, exceptID uuid.UUID) bool {
+	for _, t := range r.tables {
+		if t.ID != exceptID && sameOutlet(t.OutletID, table.OutletID) && strings.EqualFold(t.Name, table.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// withDraftOrders fills in the draft order seated at each table
+func (r *DiningTableRepositoryInMemoryImpl) withDraftOrders(tables ...model.DiningTableEntity) ([]model.DiningTableEntity, error) {
+	drafts, err := r.draftRepo.FindDraftOrders("")
+	if err != nil {
+		return nil, err
+	}
+	for i := range tables {
+		tables[i].DraftOrderID = nil
+		for _, d := range drafts {
+			if d.TableID != nil && *d.TableID == tables[i].ID {
+				draftID := d.ID
+				tables[i].DraftOrderID = &draftID
+			}
+		}
+	}
+	return tables, nil
+}
+
+func (r *DiningTableRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errDiningTableNotFound)
+	}
+	for i, t := range r.tables {
+		if t.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errDiningTableNotFound)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryDiningTableRepository_FindDiningTables(t *testing.T) {
+	draftRepo := NewDraftOrderRepository(NewProductRepository())
+	repo := NewDiningTableRepository(draftRepo)
+	outletID := uuid.New()
+
+	terrace, err := repo.InsertDiningTable(model.DiningTableEntity{ID: uuid.New(), OutletID: &outletID, Area: "Terrace", Name: "T1"})
+	require.NoError(t, err)
+	indoor, err := repo.InsertDiningTable(model.DiningTableEntity{ID: uuid.New(), OutletID: &outletID, Area: "Indoor", Name: "7"})
+	require.NoError(t, err)
+	_, err = repo.InsertDiningTable(model.DiningTableEntity{ID: uuid.New(), Name: "7"})
+	require.NoError(t, err, "the name is only unique per outlet")
+	_, err = repo.InsertDiningTable(model.DiningTableEntity{ID: uuid.New(), OutletID: &outletID, Name: "t1"})
+	assert.Error(t, err)
+
+	draft, _ := draftRepo.InsertDraftOrder(model.DraftOrderEntity{ID: uuid.New(), Status: model.DraftOrderOpen, TableID: &terrace.ID})
+
+	tables, err := repo.FindDiningTables(&outletID)
+	require.NoError(t, err)
+	require.Len(t, tables, 2)
+	assert.Equal(t, indoor.ID, tables[0].ID, "by area")
+	assert.Nil(t, tables[0].DraftOrderID)
+	require.NotNil(t, tables[1].DraftOrderID)
+	assert.Equal(t, draft.ID, *tables[1].DraftOrderID)
+
+	all, err := repo.FindDiningTables(nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	draft.Status = model.DraftOrderConverted
+	_, _ = draftRepo.UpdateDraftOrder(draft)
+	freed, err := repo.FindDiningTableByID(terrace.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, freed.DraftOrderID)
+}
+
+func TestInMemoryDiningTableRepository_UpdateDiningTableByID(t *testing.T) {
+	repo := NewDiningTableRepository(NewDraftOrderRepository(NewProductRepository()))
+	table, _ := repo.InsertDiningTable(model.DiningTableEntity{ID: uuid.New(), Name: "7", Seats: 2, IsActive: true})
+
+	updated, err := repo.UpdateDiningTableByID(table.ID.String(), model.DiningTableEntity{Name: "7", Seats: 6, Version: table.Version})
+	require.NoError(t, err)
+	assert.Equal(t, 6, updated.Seats)
+	assert.False(t, updated.IsActive)
+	assert.Equal(t, 2, updated.Version)
+
+	_, err = repo.UpdateDiningTableByID(table.ID.String(), model.DiningTableEntity{Name: "8", Version: table.Version})
+	assert.Error(t, err, "stale version")
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errDraftOrderNotFound = "draft order not found"
+	errDraftOrderInactive = "draft order is no longer open or held"
+	errDraftOrderChanged  = "draft order changed while it was fired"
+)
+
+type DraftOrderRepositoryInMemoryImpl struct {
+	drafts      []model.DraftOrderEntity
+	productRepo repository.ProductRepository
+}
+
+func NewDraftOrderRepository(productRepo repository.ProductRepository) repository.DraftOrderRepository {
+	return &DraftOrderRepositoryInMemoryImpl{
+		drafts:      []model.DraftOrderEntity{},
+		productRepo: productRepo,
+	}
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) FindDraftOrders(status string) ([]model.DraftOrderEntity, error) {
+	// newest first, like the PostgreSQL implementation
+	var drafts []model.DraftOrderEntity
+	for i := len(r.drafts) - 1; i >= 0; i-- {
+		d := r.drafts[i]
+		if (status == "" && d.IsActive()) || d.Status == status {
+			drafts = append(drafts, r.withProductNames(d))
+		}
+	}
+	return drafts, nil
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) FindDraftOrderByID(id string) (model.DraftOrderEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.DraftOrderEntity{}, err
+	}
+	return r.withProductNames(r.drafts[i]), nil
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) InsertDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error) {
+	draft.CreatedAt = time.Now()
+	draft.UpdatedAt = draft.CreatedAt
+	draft.Version = 1
+	draft.Items = append([]model.DraftOrderItemEntity(nil), draft.Items...)
+	for i := range draft.Items {
+		draft.Items[i].CreatedAt = draft.CreatedAt
+	}
+	r.drafts = append(r.drafts, draft)
+	return r.withProductNames(draft), nil
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) UpdateDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error) {
+	i, err := r.indexOfActive(draft.ID.String())
+	if err != nil {
+		return model.DraftOrderEntity{}, err
+	}
+	existing := &r.drafts[i]
+	existing.Status = draft.Status
+	existing.Label = draft.Label
+	existing.TableNumber = draft.TableNumber
+	existing.TableID = draft.TableID
+	existing.Notes = draft.Notes
+	existing.UpdatedBy = draft.UpdatedBy
+	existing.UpdatedAt = time.Now()
+	existing.Version++
+	return r.withProductNames(*existing), nil
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) SetDraftOrderItem(item model.DraftOrderItemEntity) (model.DraftOrderEntity, error) {
+	i, err := r.indexOfActive(item.DraftOrderID.String())
+	if err != nil {
+		return model.DraftOrderEntity{}, err
+	}
+	draft := &r.drafts[i]
+
+	var items []model.DraftOrderItemEntity
+	found := false
+	for _, existing := range draft.Items {
+		if existing.ProductID != item.ProductID {
+			items = append(items, existing)
+			continue
+		}
+		found = true
+		if item.Quantity > 0 {
+			existing.Quantity = item.Quantity
+			items = append(items, existing)
+		}
+	}
+	if !found && item.Quantity > 0 {
+		item.CreatedAt = time.Now()
+		items = append(items, item)
+	}
+
+	draft.Items = items
+	draft.UpdatedBy = item.CreatedBy
+	draft.UpdatedAt = time.Now()
+	draft.Version++
+	return r.withProductNames(*draft), nil
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) FindReservedStocks(outletID *uuid.UUID, exceptID string) (map[uuid.UUID]int, error) {
+	reserved := map[uuid.UUID]int{}
+	for _, d := range r.drafts {
+		if !d.ReservesStock || !d.IsActive() || d.ID.String() == exceptID || !sameOutlet(d.OutletID, outletID) {
+			continue
+		}
+		for _, i := range d.Items {
+			product, err := r.productRepo.FindProductByID(i.ProductID.String())
+			if err != nil || !product.IsBundle() {
+				reserved[i.ProductID] += i.Quantity
+				continue
+			}
+			for _, c := range product.Components {
+				reserved[c.ComponentID] += i.Quantity * c.Quantity
+			}
+		}
+	}
+	return reserved, nil
+}
+
+// fireItems marks the draft's lines fired up to their quantity, failing like the PostgreSQL implementation
+// when the draft is no longer active or a line changed since the draft was read
+func (r *DraftOrderRepositoryInMemoryImpl) fireItems(draft model.DraftOrderEntity) error {
+	i, err := r.indexOfActive(draft.ID.String())
+	if err != nil {
+		return err
+	}
+	existing := &r.drafts[i]
+	if len(existing.Items) != len(draft.Items) {
+		return errors.New(errDraftOrderChanged)
+	}
+	for _, item := range draft.Items {
+		j := slices.IndexFunc(existing.Items, func(e model.DraftOrderItemEntity) bool { return e.ProductID == item.ProductID })
+		if j < 0 || existing.Items[j].Quantity != item.Quantity || existing.Items[j].FiredQuantity != item.FiredQuantity {
+			return errors.New(errDraftOrderChanged)
+		}
+	}
+	for j := range existing.Items {
+		existing.Items[j].FiredQuantity = existing.Items[j].Quantity
+	}
+	existing.UpdatedAt = time.Now()
+	existing.Version++
+	return nil
+}
+
+// sameOutlet compares like IS NOT DISTINCT FROM, drafts of a shop without outlets only match each other
+func sameOutlet(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errDraftOrderNotFound)
+	}
+	for i, d := range r.drafts {
+		if d.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errDraftOrderNotFound)
+}
+
+func (r *DraftOrderRepositoryInMemoryImpl) indexOfActive(id string) (int, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return -1, err
+	}
+	if !r.drafts[i].IsActive() {
+		return -1, errors.New(errDraftOrderInactive)
+	}
+	return i, nil
+}
+
+// withProductNames fills in the product names the PostgreSQL implementation would JOIN
+func (r *DraftOrderRepositoryInMemoryImpl) withProductNames(draft model.DraftOrderEntity) model.DraftOrderEntity {
+	items := make([]model.DraftOrderItemEntity, len(draft.Items))
+	for i, item := range draft.Items {
+		if product, err := r.productRepo.FindProductByID(item.ProductID.String()); err == nil {
+			item.ProductName = product.Name
+		}
+		items[i] = item
+	}
+	draft.Items = items
+	return draft
+}
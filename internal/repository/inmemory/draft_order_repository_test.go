@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryDraftOrderRepository_SetDraftOrderItem(t *testing.T) {
+	productRepo := NewProductRepository()
+	coffee, _ := productRepo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Kopi Susu", Stocks: 10})
+	cake, _ := productRepo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Brownies", Stocks: 5})
+	repo := NewDraftOrderRepository(productRepo)
+
+	draft, err := repo.InsertDraftOrder(model.DraftOrderEntity{
+		ID:     uuid.New(),
+		Status: model.DraftOrderOpen,
+		Items:  []model.DraftOrderItemEntity{{ProductID: coffee.ID, Quantity: 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Kopi Susu", draft.Items[0].ProductName)
+
+	draft, err = repo.SetDraftOrderItem(model.DraftOrderItemEntity{DraftOrderID: draft.ID, ProductID: cake.ID, Quantity: 2})
+	require.NoError(t, err)
+	draft, err = repo.SetDraftOrderItem(model.DraftOrderItemEntity{DraftOrderID: draft.ID, ProductID: coffee.ID, Quantity: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 3, draft.ItemQuantity(coffee.ID))
+	assert.Equal(t, 2, draft.ItemQuantity(cake.ID))
+
+	draft, err = repo.SetDraftOrderItem(model.DraftOrderItemEntity{DraftOrderID: draft.ID, ProductID: coffee.ID})
+	require.NoError(t, err)
+	require.Len(t, draft.Items, 1)
+	assert.Equal(t, cake.ID, draft.Items[0].ProductID)
+
+	draft.Status = model.DraftOrderCancelled
+	_, err = repo.UpdateDraftOrder(draft)
+	require.NoError(t, err)
+	_, err = repo.SetDraftOrderItem(model.DraftOrderItemEntity{DraftOrderID: draft.ID, ProductID: coffee.ID, Quantity: 1})
+	assert.Error(t, err, "a cancelled draft cannot change")
+}
+
+func TestInMemoryDraftOrderRepository_FindDraftOrders(t *testing.T) {
+	repo := NewDraftOrderRepository(NewProductRepository())
+	for _, status := range []string{model.DraftOrderOpen, model.DraftOrderHeld, model.DraftOrderCancelled} {
+		_, _ = repo.InsertDraftOrder(model.DraftOrderEntity{ID: uuid.New(), Status: status})
+	}
+
+	active, err := repo.FindDraftOrders("")
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, model.DraftOrderHeld, active[0].Status, "newest first")
+
+	cancelled, err := repo.FindDraftOrders(model.DraftOrderCancelled)
+	require.NoError(t, err)
+	assert.Len(t, cancelled, 1)
+}
+
+func TestInMemoryDraftOrderRepository_FindReservedStocks(t *testing.T) {
+	productRepo := NewProductRepository()
+	beans, _ := productRepo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Beans", Stocks: 20})
+	bundle, _ := productRepo.InsertProduct(model.ProductEntity{
+		ID:         uuid.New(),
+		Name:       "Beans Duo",
+		Type:       model.ProductTypeBundle,
+		Components: []model.BundleComponentEntity{{ComponentID: beans.ID, Quantity: 2}},
+	})
+	repo := NewDraftOrderRepository(productRepo)
+	outletID := uuid.New()
+
+	held, _ := repo.InsertDraftOrder(model.DraftOrderEntity{ID: uuid.New(), Status: model.DraftOrderHeld, ReservesStock: true,
+		Items: []model.DraftOrderItemEntity{{ProductID: beans.ID, Quantity: 1}, {ProductID: bundle.ID, Quantity: 3}}})
+	_, _ = repo.InsertDraftOrder(model.DraftOrderEntity{ID: uuid.New(), Status: model.DraftOrderOpen,
+		Items: []model.DraftOrderItemEntity{{ProductID: beans.ID, Quantity: 5}}})
+	_, _ = repo.InsertDraftOrder(model.DraftOrderEntity{ID: uuid.New(), Status: model.DraftOrderOpen, ReservesStock: true, OutletID: &outletID,
+		Items: []model.DraftOrderItemEntity{{ProductID: beans.ID, Quantity: 4}}})
+
+	reserved, err := repo.FindReservedStocks(nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{beans.ID: 7}, reserved, "the bundle holds its components, the draft not reserving holds nothing")
+
+	reserved, err = repo.FindReservedStocks(nil, held.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, reserved)
+
+	reserved, err = repo.FindReservedStocks(&outletID, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{beans.ID: 4}, reserved)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errGiftCardNotFound      = "gift card not found"
+	errGiftCardCodeTaken     = "gift card code already exists"
+	errGiftCardNegative      = "gift card balance cannot go below zero"
+	errGiftCardNotRedeemable = "gift card is inactive or expired"
+)
+
+type GiftCardRepositoryInMemoryImpl struct {
+	cards   []model.GiftCardEntity
+	entries []model.GiftCardEntryEntity
+}
+
+func NewGiftCardRepository() repository.GiftCardRepository {
+	return &GiftCardRepositoryInMemoryImpl{
+		cards:   []model.GiftCardEntity{},
+		entries: []model.GiftCardEntryEntity{},
+	}
+}
+
+func (r *GiftCardRepositoryInMemoryImpl) FindGiftCards() ([]model.GiftCardEntity, error) {
+	cards := make([]model.GiftCardEntity, 0, len(r.cards))
+	for i := len(r.cards) - 1; i >= 0; i-- {
+		cards = append(cards, r.cards[i])
+	}
+	return cards, nil
+}
+
+func (r *GiftCardRepositoryInMemoryImpl) FindGiftCardByCode(code string) (model.GiftCardEntity, error) {
+	for _, g := range r.cards {
+		if g.Code == code {
+			return g, nil
+		}
+	}
+	return model.GiftCardEntity{}, nil
+}
+
+func (r *GiftCardRepositoryInMemoryImpl) InsertGiftCard(card model.GiftCardEntity) (model.GiftCardEntity, error) {
+	for _, g := range r.cards {
+		if g.Code == card.Code {
+			return model.GiftCardEntity{}, errors.New(errGiftCardCodeTaken)
+		}
+	}
+	card.CreatedAt = time.Now()
+	card.UpdatedAt = card.CreatedAt
+	card.Version = 1
+	card.Balance = 0
+	r.cards = append(r.cards, card)
+
+	entryID, _ := uuid.NewV7()
+	if _, err := r.InsertGiftCardEntry(model.GiftCardEntryEntity{
+		ID:         entryID,
+		GiftCardID: card.ID,
+		Type:       model.GiftCardEntryIssue,
+		Amount:     card.InitialAmount,
+		CreatedBy:  card.CreatedBy,
+	}); err != nil {
+		r.cards = r.cards[:len(r.cards)-1]
+		return model.GiftCardEntity{}, err
+	}
+	return r.cards[len(r.cards)-1], nil
+}
+
+func (r *GiftCardRepositoryInMemoryImpl) FindGiftCardEntries(giftCardID string) ([]model.GiftCardEntryEntity, error) {
+	var entries []model.GiftCardEntryEntity
+	for _, e := range r.entries {
+		if e.GiftCardID.String() == giftCardID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// InsertGiftCardEntry moves the card's balance like trg_gift_card_ledger_apply, it never lets it go below zero
+func (r *GiftCardRepositoryInMemoryImpl) InsertGiftCardEntry(entry model.GiftCardEntryEntity) (model.GiftCardEntryEntity, error) {
+	i := -1
+	for j, g := range r.cards {
+		if g.ID == entry.GiftCardID {
+			i = j
+		}
+	}
+	if i < 0 {
+		return model.GiftCardEntryEntity{}, errors.New(errGiftCardNotFound)
+	}
+	if entry.Type == model.GiftCardEntryRedeem && !r.cards[i].IsRedeemable(time.Now()) {
+		return model.GiftCardEntryEntity{}, errors.New(errGiftCardNotRedeemable)
+	}
+	balance := r.cards[i].Balance + entry.Amount
+	if balance < 0 {
+		return model.GiftCardEntryEntity{}, errors.New(errGiftCardNegative)
+	}
+
+	r.cards[i].Balance = balance
+	r.cards[i].UpdatedBy = entry.CreatedBy
+	r.cards[i].UpdatedAt = time.Now()
+	entry.BalanceAfter = balance
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
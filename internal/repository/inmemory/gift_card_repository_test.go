@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryGiftCardRepository_Issue(t *testing.T) {
+	repo := NewGiftCardRepository()
+
+	card, err := repo.InsertGiftCard(model.GiftCardEntity{ID: uuid.New(), Code: "ABCD1234", InitialAmount: 100000, IsActive: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100000), card.Balance)
+
+	_, err = repo.InsertGiftCard(model.GiftCardEntity{ID: uuid.New(), Code: "ABCD1234", InitialAmount: 5000, IsActive: true})
+	assert.Error(t, err)
+
+	found, err := repo.FindGiftCardByCode("ABCD1234")
+	require.NoError(t, err)
+	assert.Equal(t, card.ID, found.ID)
+
+	missing, err := repo.FindGiftCardByCode("NOPE")
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, missing.ID)
+
+	entries, err := repo.FindGiftCardEntries(card.ID.String())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, model.GiftCardEntryIssue, entries[0].Type)
+}
+
+func TestInMemoryGiftCardRepository_Entries(t *testing.T) {
+	repo := NewGiftCardRepository()
+	card, _ := repo.InsertGiftCard(model.GiftCardEntity{ID: uuid.New(), Code: "GIFT", InitialAmount: 50000, IsActive: true})
+	lapsed := time.Now().Add(-time.Hour)
+	expired, _ := repo.InsertGiftCard(model.GiftCardEntity{ID: uuid.New(), Code: "OLD", InitialAmount: 50000, IsActive: true, ExpiresAt: &lapsed})
+
+	redeemed, err := repo.InsertGiftCardEntry(model.GiftCardEntryEntity{ID: uuid.New(), GiftCardID: card.ID, Type: model.GiftCardEntryRedeem, Amount: -30000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20000), redeemed.BalanceAfter)
+
+	_, err = repo.InsertGiftCardEntry(model.GiftCardEntryEntity{ID: uuid.New(), GiftCardID: card.ID, Type: model.GiftCardEntryRedeem, Amount: -30000})
+	assert.Error(t, err, "the card only holds 20000")
+
+	_, err = repo.InsertGiftCardEntry(model.GiftCardEntryEntity{ID: uuid.New(), GiftCardID: expired.ID, Type: model.GiftCardEntryRedeem, Amount: -1000})
+	assert.Error(t, err, "an expired card cannot pay")
+
+	topUp, err := repo.InsertGiftCardEntry(model.GiftCardEntryEntity{ID: uuid.New(), GiftCardID: card.ID, Type: model.GiftCardEntryTopUp, Amount: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(30000), topUp.BalanceAfter)
+
+	found, _ := repo.FindGiftCardByCode("GIFT")
+	assert.Equal(t, int64(30000), found.Balance)
+}
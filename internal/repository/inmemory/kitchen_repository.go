@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errKitchenStationNotFound = "kitchen station not found"
+	errKitchenStationChanged  = "kitchen station was changed"
+	errKitchenTicketNotFound  = "kitchen ticket not found"
+	errKitchenTicketChanged   = "kitchen ticket was changed by another screen"
+)
+
+type KitchenRepositoryInMemoryImpl struct {
+	stations  []model.KitchenStationEntity
+	tickets   []model.KitchenTicketEntity
+	draftRepo *DraftOrderRepositoryInMemoryImpl
+}
+
+// NewKitchenRepository marks the fired lines on draftRepo, the PostgreSQL implementation does it in the same transaction
+func NewKitchenRepository(draftRepo *DraftOrderRepositoryInMemoryImpl) repository.KitchenRepository {
+	return &KitchenRepositoryInMemoryImpl{
+		stations:  []model.KitchenStationEntity{},
+		tickets:   []model.KitchenTicketEntity{},
+		draftRepo: draftRepo,
+	}
+}
+
+func (r *KitchenRepositoryInMemoryImpl) FindKitchenStations() ([]model.KitchenStationEntity, error) {
+	stations := append([]model.KitchenStationEntity(nil), r.stations...)
+	sort.SliceStable(stations, func(i, j int) bool {
+		return stations[i].Name < stations[j].Name
+	})
+	return stations, nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) FindKitchenStationByID(id string) (model.KitchenStationEntity, error) {
+	i, err := r.indexOfStation(id)
+	if err != nil {
+		return model.KitchenStationEntity{}, err
+	}
+	return r.stations[i], nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) InsertKitchenStation(station model.KitchenStationEntity) (model.KitchenStationEntity, error) {
+	station.CreatedAt = time.Now()
+	station.UpdatedAt = station.CreatedAt
+	station.Version = 1
+	r.routeCategories(station.CategoryIDs)
+	r.stations = append(r.stations, station)
+	return station, nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) UpdateKitchenStationByID(id string, station model.KitchenStationEntity) (model.KitchenStationEntity, error) {
+	i, err := r.indexOfStation(id)
+	if err != nil {
+		return model.KitchenStationEntity{}, err
+	}
+	if r.stations[i].Version != station.Version {
+		return model.KitchenStationEntity{}, errors.New(errKitchenStationChanged)
+	}
+
+	r.routeCategories(station.CategoryIDs)
+	existing := &r.stations[i]
+	existing.Name = station.Name
+	existing.IsActive = station.IsActive
+	existing.CategoryIDs = station.CategoryIDs
+	existing.UpdatedBy = station.UpdatedBy
+	existing.UpdatedAt = time.Now()
+	existing.Version++
+	return *existing, nil
+}
+
+// routeCategories takes the categories off the stations preparing them, like the upsert on kitchen_station_category
+func (r *KitchenRepositoryInMemoryImpl) routeCategories(categoryIDs []uuid.UUID) {
+	for i := range r.stations {
+		r.stations[i].CategoryIDs = slices.DeleteFunc(slices.Clone(r.stations[i].CategoryIDs), func(id uuid.UUID) bool {
+			return slices.Contains(categoryIDs, id)
+		})
+	}
+}
+
+func (r *KitchenRepositoryInMemoryImpl) FindStationsByCategory() (map[uuid.UUID]uuid.UUID, error) {
+	stations := map[uuid.UUID]uuid.UUID{}
+	for _, s := range r.stations {
+		if !s.IsActive {
+			continue
+		}
+		for _, categoryID := range s.CategoryIDs {
+			stations[categoryID] = s.ID
+		}
+	}
+	return stations, nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) FindKitchenTickets(stationID, status string) ([]model.KitchenTicketEntity, error) {
+	// oldest first, like the PostgreSQL implementation
+	var tickets []model.KitchenTicketEntity
+	for _, t := range r.tickets {
+		if stationID != "" && t.StationID.String() != stationID {
+			continue
+		}
+		if (status == "" && t.Status != model.KitchenTicketServed) || t.Status == status {
+			tickets = append(tickets, r.withStationName(t))
+		}
+	}
+	return tickets, nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) FindKitchenTicketByID(id string) (model.KitchenTicketEntity, error) {
+	i, err := r.indexOfTicket(id)
+	if err != nil {
+		return model.KitchenTicketEntity{}, err
+	}
+	return r.withStationName(r.tickets[i]), nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) FireKitchenTickets(draft model.DraftOrderEntity, tickets []model.KitchenTicketEntity) ([]model.KitchenTicketEntity, error) {
+	if err := r.draftRepo.fireItems(draft); err != nil {
+		return nil, err
+	}
+
+	var fired []model.KitchenTicketEntity
+	now := time.Now()
+	for _, t := range tickets {
+		t.CreatedAt = now
+		t.UpdatedAt = now
+		t.Version = 1
+		r.tickets = append(r.tickets, t)
+		fired = append(fired, r.withStationName(t))
+	}
+	return fired, nil
+}
+
+func (r *KitchenRepositoryInMemoryImpl) UpdateKitchenTicketStatus(ticket model.KitchenTicketEntity) (model.KitchenTicketEntity, error) {
+	i, err := r.indexOfTicket(ticket.ID.String())
+	if err != nil {
+		return model.KitchenTicketEntity{}, err
+	}
+	existing := &r.tickets[i]
+	if existing.Version != ticket.Version {
+		return model.KitchenTicketEntity{}, errors.New(errKitchenTicketChanged)
+	}
+
+	now := time.Now()
+	switch ticket.Status {
+	case model.KitchenTicketPreparing:
+		existing.PreparingAt = &now
+	case model.KitchenTicketReady:
+		existing.ReadyAt = &now
+	case model.KitchenTicketServed:
+		existing.ServedAt = &now
+	}
+	existing.Status = ticket.Status
+	existing.UpdatedBy = ticket.UpdatedBy
+	existing.UpdatedAt = now
+	existing.Version++
+	return r.withStationName(*existing), nil
+}
+
+// withStationName fills in the station name the PostgreSQL implementation would JOIN
+func (r *KitchenRepositoryInMemoryImpl) withStationName(ticket model.KitchenTicketEntity) model.KitchenTicketEntity {
+	if i, err := r.indexOfStation(ticket.StationID.String()); err == nil {
+		ticket.StationName = r.stations[i].Name
+	}
+	return ticket
+}
+
+func (r *KitchenRepositoryInMemoryImpl) indexOfStation(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errKitchenStationNotFound)
+	}
+	for i, s := range r.stations {
+		if s.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errKitchenStationNotFound)
+}
+
+func (r *KitchenRepositoryInMemoryImpl) indexOfTicket(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errKitchenTicketNotFound)
+	}
+	for i, t := range r.tickets {
+		if t.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errKitchenTicketNotFound)
+}
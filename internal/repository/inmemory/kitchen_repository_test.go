@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryKitchenRepository_StationCategories(t *testing.T) {
+	repo := NewKitchenRepository(NewDraftOrderRepository(NewProductRepository()).(*DraftOrderRepositoryInMemoryImpl))
+	food, drinks := uuid.New(), uuid.New()
+
+	kitchen, err := repo.InsertKitchenStation(model.KitchenStationEntity{ID: uuid.New(), Name: "Kitchen", IsActive: true, CategoryIDs: []uuid.UUID{food, drinks}})
+	require.NoError(t, err)
+	bar, err := repo.InsertKitchenStation(model.KitchenStationEntity{ID: uuid.New(), Name: "Bar", IsActive: true, CategoryIDs: []uuid.UUID{drinks}})
+	require.NoError(t, err)
+
+	stations, err := repo.FindStationsByCategory()
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]uuid.UUID{food: kitchen.ID, drinks: bar.ID}, stations, "drinks moved to the bar")
+
+	_, err = repo.UpdateKitchenStationByID(bar.ID.String(), model.KitchenStationEntity{Name: "Bar", CategoryIDs: []uuid.UUID{drinks}, Version: bar.Version})
+	require.NoError(t, err)
+	stations, _ = repo.FindStationsByCategory()
+	assert.Equal(t, map[uuid.UUID]uuid.UUID{food: kitchen.ID}, stations, "an inactive station prepares nothing")
+
+	_, err = repo.UpdateKitchenStationByID(bar.ID.String(), model.KitchenStationEntity{Name: "Bar", Version: bar.Version})
+	assert.Error(t, err, "stale version")
+}
+
+func TestInMemoryKitchenRepository_FireKitchenTickets(t *testing.T) {
+	productRepo := NewProductRepository()
+	nasiGoreng, _ := productRepo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Nasi Goreng", Stocks: 10})
+	draftRepo := NewDraftOrderRepository(productRepo).(*DraftOrderRepositoryInMemoryImpl)
+	repo := NewKitchenRepository(draftRepo)
+	kitchen, _ := repo.InsertKitchenStation(model.KitchenStationEntity{ID: uuid.New(), Name: "Kitchen", IsActive: true})
+
+	draft, _ := draftRepo.InsertDraftOrder(model.DraftOrderEntity{
+		ID:     uuid.New(),
+		Status: model.DraftOrderOpen,
+		Items:  []model.DraftOrderItemEntity{{ProductID: nasiGoreng.ID, Quantity: 2}},
+	})
+	tickets := model.NewKitchenTickets(draft, map[uuid.UUID]uuid.UUID{nasiGoreng.ID: kitchen.ID})
+
+	fired, err := repo.FireKitchenTickets(draft, tickets)
+	require.NoError(t, err)
+	require.Len(t, fired, 1)
+	assert.Equal(t, "Kitchen", fired[0].StationName)
+
+	_, err = repo.FireKitchenTickets(draft, tickets)
+	assert.Error(t, err, "the draft read before the first firing is stale")
+
+	draft, _ = draftRepo.FindDraftOrderByID(draft.ID.String())
+	assert.Equal(t, 2, draft.Items[0].FiredQuantity)
+
+	ticket, err := repo.UpdateKitchenTicketStatus(model.KitchenTicketEntity{ID: fired[0].ID, Status: model.KitchenTicketReady, Version: fired[0].Version})
+	require.NoError(t, err)
+	assert.NotNil(t, ticket.ReadyAt)
+	assert.Nil(t, ticket.PreparingAt)
+	_, err = repo.UpdateKitchenTicketStatus(model.KitchenTicketEntity{ID: fired[0].ID, Status: model.KitchenTicketServed, Version: fired[0].Version})
+	assert.Error(t, err, "another screen moved the ticket")
+
+	open, err := repo.FindKitchenTickets(kitchen.ID.String(), "")
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+	_, _ = repo.UpdateKitchenTicketStatus(model.KitchenTicketEntity{ID: ticket.ID, Status: model.KitchenTicketServed, Version: ticket.Version})
+	open, _ = repo.FindKitchenTickets("", "")
+	assert.Empty(t, open)
+	served, _ := repo.FindKitchenTickets("", model.KitchenTicketServed)
+	assert.Len(t, served, 1)
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+type LotRepositoryInMemoryImpl struct {
+	lots              []model.ProductLotEntity
+	productRepo       repository.ProductRepository
+	stockMovementRepo repository.StockMovementRepository
+}
+
+// NewLotRepository keeps the remaining quantities on the ledger, like trg_stock_movement_lot does
+func NewLotRepository(productRepo repository.ProductRepository, stockMovementRepo repository.StockMovementRepository) repository.LotRepository {
+	return &LotRepositoryInMemoryImpl{
+		lots:              []model.ProductLotEntity{},
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+	}
+}
+
+func (r *LotRepositoryInMemoryImpl) FindLotsByProductID(productID string) ([]model.ProductLotEntity, error) {
+	parsedID, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, errors.New(errProductNotFound)
+	}
+	return r.find(func(l model.ProductLotEntity) bool { return l.ProductID == parsedID })
+}
+
+func (r *LotRepositoryInMemoryImpl) FindOpenLotsByProductID(productID string, outletID *uuid.UUID) ([]model.ProductLotEntity, error) {
+	parsedID, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, errors.New(errProductNotFound)
+	}
+	return r.find(func(l model.ProductLotEntity) bool {
+		sameOutlet := (l.OutletID == nil) == (outletID == nil) && (outletID == nil || *l.OutletID == *outletID)
+		return l.ProductID == parsedID && l.QuantityRemaining > 0 && sameOutlet
+	})
+}
+
+func (r *LotRepositoryInMemoryImpl) FindExpiringLots(until time.Time) ([]model.ProductLotEntity, error) {
+	last := model.NewDate(until)
+	return r.find(func(l model.ProductLotEntity) bool {
+		return l.QuantityRemaining > 0 && !model.NewDate(l.ExpiryDate).After(last.Time)
+	})
+}
+
+func (r *LotRepositoryInMemoryImpl) find(match func(model.ProductLotEntity) bool) ([]model.ProductLotEntity, error) {
+	var lots []model.ProductLotEntity
+	for _, l := range r.lots {
+		lot, err := r.withRemaining(l)
+		if err != nil {
+			return nil, err
+		}
+		if match(lot) {
+			lots = append(lots, lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].ExpiryDate.Before(lots[j].ExpiryDate)
+	})
+	return lots, nil
+}
+
+func (r *LotRepositoryInMemoryImpl) InsertLot(lot model.ProductLotEntity) (model.ProductLotEntity, error) {
+	for _, l := range r.lots {
+		if l.ProductID == lot.ProductID && l.LotNumber == lot.LotNumber {
+			return model.ProductLotEntity{}, errors.New("lot number already exists for this product")
+		}
+	}
+
+	movementID, _ := uuid.NewV7()
+	movement, err := r.stockMovementRepo.InsertStockMovement(model.StockMovementEntity{
+		ID:        movementID,
+		ProductID: lot.ProductID,
+		Type:      model.StockMovementPurchaseReceipt,
+		Quantity:  lot.QuantityReceived,
+		Reason:    "lot " + lot.LotNumber,
+		CreatedBy: lot.CreatedBy,
+		LotID:     &lot.ID,
+		OutletID:  lot.OutletID,
+	})
+	if err != nil {
+		return model.ProductLotEntity{}, err
+	}
+
+	lot.CreatedAt = movement.CreatedAt
+	r.lots = append(r.lots, lot)
+	return r.withRemaining(lot)
+}
+
+// withRemaining fills in the product name and sums the lot's movements into its remaining quantity
+func (r *LotRepositoryInMemoryImpl) withRemaining(lot model.ProductLotEntity) (model.ProductLotEntity, error) {
+	product, err := r.productRepo.FindProductByID(lot.ProductID.String())
+	if err != nil {
+		return model.ProductLotEntity{}, err
+	}
+	movements, err := r.stockMovementRepo.FindStockMovementsByProductID(lot.ProductID.String())
+	if err != nil {
+		return model.ProductLotEntity{}, err
+	}
+
+	lot.ProductName = product.Name
+	lot.QuantityRemaining = 0
+	for _, m := range movements {
+		if m.LotID != nil && *m.LotID == lot.ID {
+			lot.QuantityRemaining += m.Quantity
+		}
+	}
+	return lot, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLotRepositoryInMemory_InsertAndFind(t *testing.T) {
+	productRepo := NewProductRepository()
+	stockMovementRepo := NewStockMovementRepository(productRepo)
+	repo := NewLotRepository(productRepo, stockMovementRepo)
+
+	productID := uuid.New()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Susu", Stocks: 0})
+
+	now := time.Now()
+	newLot := func(number string, days, quantity int) model.ProductLotEntity {
+		return model.ProductLotEntity{ID: uuid.New(), ProductID: productID, LotNumber: number, ExpiryDate: now.AddDate(0, 0, days), QuantityReceived: quantity, CreatedBy: "USER"}
+	}
+
+	late, err := repo.InsertLot(newLot("B", 30, 4))
+	require.NoError(t, err)
+	assert.Equal(t, 4, late.QuantityRemaining)
+	assert.Equal(t, "Susu", late.ProductName)
+	early, err := repo.InsertLot(newLot("A", 3, 6))
+	require.NoError(t, err)
+
+	_, err = repo.InsertLot(newLot("A", 5, 1))
+	assert.Error(t, err, "lot numbers are unique per product")
+
+	product, _ := productRepo.FindProductByID(productID.String())
+	assert.Equal(t, 10, product.Stocks)
+
+	// a sale against the early lot is booked on the ledger
+	_, err = stockMovementRepo.InsertStockMovement(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementSale, Quantity: -6, LotID: &early.ID})
+	require.NoError(t, err)
+
+	lots, err := repo.FindLotsByProductID(productID.String())
+	require.NoError(t, err)
+	require.Len(t, lots, 2)
+	assert.Equal(t, "A", lots[0].LotNumber)
+	assert.Equal(t, 0, lots[0].QuantityRemaining)
+
+	open, _ := repo.FindOpenLotsByProductID(productID.String(), nil)
+	require.Len(t, open, 1)
+	assert.Equal(t, late.ID, open[0].ID)
+
+	// a lot received at an outlet is only open to that outlet's sales
+	outletID := uuid.New()
+	atOutlet := newLot("C", 10, 2)
+	atOutlet.OutletID = &outletID
+	_, err = repo.InsertLot(atOutlet)
+	require.NoError(t, err)
+	open, _ = repo.FindOpenLotsByProductID(productID.String(), &outletID)
+	require.Len(t, open, 1)
+	assert.Equal(t, atOutlet.ID, open[0].ID)
+	open, _ = repo.FindOpenLotsByProductID(productID.String(), nil)
+	assert.Len(t, open, 1, "the outlet's lot is not held at no outlet")
+	movements, _ := stockMovementRepo.FindStockMovementsByProductID(productID.String())
+	assert.Equal(t, &outletID, movements[0].OutletID)
+
+	expiring, _ := repo.FindExpiringLots(now.AddDate(0, 0, 7))
+	assert.Empty(t, expiring, "the early lot is sold out")
+	expiring, _ = repo.FindExpiringLots(now.AddDate(0, 0, 30))
+	assert.Len(t, expiring, 2)
+}
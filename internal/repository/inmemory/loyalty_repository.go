@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// pointsHolder is the in-memory customer repository, which keeps the cached balance the ledger moves
+type pointsHolder interface {
+	applyPoints(id uuid.UUID, points int) (int, error)
+}
+
+type LoyaltyRepositoryInMemoryImpl struct {
+	program      model.LoyaltyProgramEntity
+	entries      []model.LoyaltyEntryEntity
+	customerRepo pointsHolder
+}
+
+// NewLoyaltyRepository keeps the customers' points balance on the ledger, the repository has to be an in-memory one
+func NewLoyaltyRepository(customerRepo repository.CustomerRepository) repository.LoyaltyRepository {
+	return &LoyaltyRepositoryInMemoryImpl{
+		entries:      []model.LoyaltyEntryEntity{},
+		customerRepo: customerRepo.(pointsHolder),
+	}
+}
+
+func (r *LoyaltyRepositoryInMemoryImpl) FindLoyaltyProgram() (model.LoyaltyProgramEntity, error) {
+	return r.program, nil
+}
+
+func (r *LoyaltyRepositoryInMemoryImpl) SaveLoyaltyProgram(program model.LoyaltyProgramEntity) (model.LoyaltyProgramEntity, error) {
+	if r.program.Version > 0 {
+		if program.Version != r.program.Version {
+			return model.LoyaltyProgramEntity{}, errors.New("loyalty program version is outdated")
+		}
+		program.CreatedAt = r.program.CreatedAt
+		program.CreatedBy = r.program.CreatedBy
+	} else {
+		program.CreatedAt = time.Now()
+	}
+	program.UpdatedAt = time.Now()
+	program.Version = r.program.Version + 1
+	r.program = program
+	return program, nil
+}
+
+func (r *LoyaltyRepositoryInMemoryImpl) FindLoyaltyEntries(customerID string) ([]model.LoyaltyEntryEntity, error) {
+	var entries []model.LoyaltyEntryEntity
+	for _, e := range r.entries {
+		if e.CustomerID.String() == customerID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *LoyaltyRepositoryInMemoryImpl) InsertLoyaltyEntries(entries []model.LoyaltyEntryEntity) ([]model.LoyaltyEntryEntity, error) {
+	var applied []model.LoyaltyEntryEntity
+	for _, e := range entries {
+		balance, err := r.customerRepo.applyPoints(e.CustomerID, e.Points)
+		if err != nil {
+			// roll back what this call already applied
+			for i := len(applied) - 1; i >= 0; i-- {
+				_, _ = r.customerRepo.applyPoints(applied[i].CustomerID, -applied[i].Points)
+			}
+			return nil, err
+		}
+		e.BalanceAfter = balance
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = time.Now()
+		}
+		applied = append(applied, e)
+	}
+	r.entries = append(r.entries, applied...)
+	return applied, nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryLoyaltyRepository_Program(t *testing.T) {
+	repo := NewLoyaltyRepository(NewCustomerRepository())
+
+	program, err := repo.FindLoyaltyProgram()
+	require.NoError(t, err)
+	assert.False(t, program.IsActive)
+
+	saved, err := repo.SaveLoyaltyProgram(model.LoyaltyProgramEntity{IsActive: true, SpendPerPoint: 10000, PointValue: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.Version)
+
+	_, err = repo.SaveLoyaltyProgram(model.LoyaltyProgramEntity{SpendPerPoint: 5000, PointValue: 100, Version: 3})
+	assert.Error(t, err)
+
+	saved, err = repo.SaveLoyaltyProgram(model.LoyaltyProgramEntity{SpendPerPoint: 5000, PointValue: 100, Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved.Version)
+	assert.Equal(t, int64(5000), saved.SpendPerPoint)
+}
+
+func TestInMemoryLoyaltyRepository_Entries(t *testing.T) {
+	customerRepo := NewCustomerRepository()
+	repo := NewLoyaltyRepository(customerRepo)
+
+	customerID := uuid.New()
+	_, _ = customerRepo.InsertCustomer(model.CustomerEntity{ID: customerID, Name: "Budi"})
+
+	inserted, err := repo.InsertLoyaltyEntries([]model.LoyaltyEntryEntity{
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 50},
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryRedeem, Points: -20},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 50, inserted[0].BalanceAfter)
+	assert.Equal(t, 30, inserted[1].BalanceAfter)
+
+	_, err = repo.InsertLoyaltyEntries([]model.LoyaltyEntryEntity{
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 5},
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryRedeem, Points: -40},
+	})
+	assert.Error(t, err, "the balance cannot go below zero")
+
+	customer, _ := customerRepo.FindCustomerByID(customerID.String())
+	assert.Equal(t, 30, customer.PointsBalance, "a rejected batch leaves the balance as it was")
+	entries, err := repo.FindLoyaltyEntries(customerID.String())
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	_, _ = customerRepo.UpdateCustomerByID(customerID.String(), model.CustomerEntity{Name: "Budi Santoso"})
+	customer, _ = customerRepo.FindCustomerByID(customerID.String())
+	assert.Equal(t, 30, customer.PointsBalance, "updating the customer keeps their points")
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const errOutletNotFound = "outlet not found"
+
+type outletProductKey struct {
+	outletID  uuid.UUID
+	productID uuid.UUID
+}
+
+type OutletRepositoryInMemoryImpl struct {
+	outlets           []model.OutletEntity
+	prices            map[outletProductKey]*int64
+	productRepo       repository.ProductRepository
+	stockMovementRepo repository.StockMovementRepository
+}
+
+// NewOutletRepository keeps the outlet stock on the ledger, like trg_stock_movement_outlet does
+func NewOutletRepository(productRepo repository.ProductRepository, stockMovementRepo repository.StockMovementRepository) repository.OutletRepository {
+	return &OutletRepositoryInMemoryImpl{
+		outlets:           []model.OutletEntity{},
+		prices:            map[outletProductKey]*int64{},
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+	}
+}
+
+func (r *OutletRepositoryInMemoryImpl) FindOutlets() ([]model.OutletEntity, error) {
+	var outlets []model.OutletEntity
+	for _, o := range r.outlets {
+		if o.DeletedAt == nil {
+			outlets = append(outlets, o)
+		}
+	}
+	sort.SliceStable(outlets, func(i, j int) bool {
+		return outlets[i].Code < outlets[j].Code
+	})
+	return outlets, nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) FindOutletByID(id string) (model.OutletEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.OutletEntity{}, err
+	}
+	return r.outlets[i], nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) InsertOutlet(outlet model.OutletEntity) (model.OutletEntity, error) {
+	for _, o := range r.outlets {
+		if o.DeletedAt == nil && o.Code == outlet.Code {
+			return model.OutletEntity{}, errors.New("outlet code already exists")
+		}
+	}
+
+	outlet.CreatedAt = time.Now()
+	outlet.UpdatedAt = outlet.CreatedAt
+	outlet.Version = 1
+	r.outlets = append(r.outlets, outlet)
+	return outlet, nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) UpdateOutletByID(id string, outlet model.OutletEntity) (model.OutletEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.OutletEntity{}, err
+	}
+
+	existing := r.outlets[i]
+	outlet.ID = existing.ID
+	outlet.CreatedAt = existing.CreatedAt
+	outlet.CreatedBy = existing.CreatedBy
+	outlet.UpdatedAt = time.Now()
+	outlet.Version = existing.Version + 1
+	r.outlets[i] = outlet
+	return outlet, nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) DeleteOutletByID(id string) error {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r.outlets[i].DeletedAt = &now
+	return nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) FindOutletStocks(outletID string) ([]model.OutletStockEntity, error) {
+	parsedID, err := uuid.Parse(outletID)
+	if err != nil {
+		return nil, errors.New(errOutletNotFound)
+	}
+	products, err := r.productRepo.FindProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	var stocks []model.OutletStockEntity
+	for _, p := range products {
+		if p.DeletedAt != nil {
+			continue
+		}
+		stock, err := r.outletStock(parsedID, p)
+		if err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, stock)
+	}
+	sort.SliceStable(stocks, func(i, j int) bool {
+		return stocks[i].ProductName < stocks[j].ProductName
+	})
+	return stocks, nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) FindOutletStock(outletID, productID string) (model.OutletStockEntity, error) {
+	parsedID, err := uuid.Parse(outletID)
+	if err != nil {
+		return model.OutletStockEntity{}, errors.New(errOutletNotFound)
+	}
+	product, err := r.productRepo.FindProductByID(productID)
+	if err != nil {
+		return model.OutletStockEntity{}, err
+	}
+	return r.outletStock(parsedID, product)
+}
+
+func (r *OutletRepositoryInMemoryImpl) UpdateOutletPrice(outletID, productID string, priceOverride *int64) (model.OutletStockEntity, error) {
+	parsedID, err := uuid.Parse(outletID)
+	if err != nil {
+		return model.OutletStockEntity{}, errors.New(errOutletNotFound)
+	}
+	product, err := r.productRepo.FindProductByID(productID)
+	if err != nil {
+		return model.OutletStockEntity{}, err
+	}
+
+	r.prices[outletProductKey{outletID: parsedID, productID: product.ID}] = priceOverride
+	return r.outletStock(parsedID, product)
+}
+
+func (r *OutletRepositoryInMemoryImpl) FindStockHeldAtOutlets(productID string) (int, error) {
+	movements, err := r.stockMovementRepo.FindStockMovementsByProductID(productID)
+	if err != nil {
+		return 0, err
+	}
+	var held int
+	for _, m := range movements {
+		if m.OutletID != nil {
+			held += m.Quantity
+		}
+	}
+	return held, nil
+}
+
+// outletStock sums the product's movements at the outlet into its stock
+func (r *OutletRepositoryInMemoryImpl) outletStock(outletID uuid.UUID, product model.ProductEntity) (model.OutletStockEntity, error) {
+	movements, err := r.stockMovementRepo.FindStockMovementsByProductID(product.ID.String())
+	if err != nil {
+		return model.OutletStockEntity{}, err
+	}
+
+	stock := model.OutletStockEntity{
+		OutletID:      outletID,
+		ProductID:     product.ID,
+		ProductName:   product.Name,
+		ProductPrice:  product.Price,
+		PriceOverride: r.prices[outletProductKey{outletID: outletID, productID: product.ID}],
+		UpdatedAt:     product.UpdatedAt,
+	}
+	for _, m := range movements {
+		if m.OutletID != nil && *m.OutletID == outletID {
+			stock.Stock += m.Quantity
+		}
+	}
+	return stock, nil
+}
+
+func (r *OutletRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errOutletNotFound)
+	}
+	for i, o := range r.outlets {
+		if o.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errOutletNotFound)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutletRepositoryInMemory_CRUD(t *testing.T) {
+	productRepo := NewProductRepository()
+	repo := NewOutletRepository(productRepo, NewStockMovementRepository(productRepo))
+
+	outlet, err := repo.InsertOutlet(model.OutletEntity{ID: uuid.New(), Code: "JKT-01", Name: "Jakarta", IsActive: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, outlet.Version)
+
+	_, err = repo.InsertOutlet(model.OutletEntity{ID: uuid.New(), Code: "JKT-01", Name: "Jakarta 2"})
+	assert.Error(t, err, "outlet codes are unique")
+
+	updated, err := repo.UpdateOutletByID(outlet.ID.String(), model.OutletEntity{Code: "JKT-01", Name: "Jakarta Pusat"})
+	require.NoError(t, err)
+	assert.Equal(t, "Jakarta Pusat", updated.Name)
+	assert.Equal(t, 2, updated.Version)
+
+	require.NoError(t, repo.DeleteOutletByID(outlet.ID.String()))
+	outlets, _ := repo.FindOutlets()
+	assert.Empty(t, outlets)
+
+	_, err = repo.FindOutletByID(uuid.New().String())
+	assert.Error(t, err)
+}
+
+func TestOutletRepositoryInMemory_Stocks(t *testing.T) {
+	productRepo := NewProductRepository()
+	stockMovementRepo := NewStockMovementRepository(productRepo)
+	repo := NewOutletRepository(productRepo, stockMovementRepo)
+
+	outletID, productID := uuid.New(), uuid.New()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000})
+	_, err := stockMovementRepo.InsertStockMovement(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 8, OutletID: &outletID})
+	require.NoError(t, err)
+	_, err = stockMovementRepo.InsertStockMovement(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 5})
+	require.NoError(t, err)
+
+	stock, err := repo.FindOutletStock(outletID.String(), productID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 8, stock.Stock, "stock held outside the outlet is not counted")
+	assert.Equal(t, int64(10000), stock.EffectivePrice())
+
+	_, err = stockMovementRepo.InsertStockMovement(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementWaste, Quantity: -9, OutletID: &outletID})
+	assert.Error(t, err, "outlet stock cannot go below zero")
+
+	price := int64(12000)
+	stock, err = repo.UpdateOutletPrice(outletID.String(), productID.String(), &price)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12000), stock.EffectivePrice())
+
+	stocks, err := repo.FindOutletStocks(uuid.New().String())
+	require.NoError(t, err)
+	require.Len(t, stocks, 1)
+	assert.Equal(t, 0, stocks[0].Stock, "another outlet never held the product")
+	assert.Nil(t, stocks[0].PriceOverride)
+
+	held, err := repo.FindStockHeldAtOutlets(productID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 8, held, "the 5 units received at no outlet are not held by one")
+}
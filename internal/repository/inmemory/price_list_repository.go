@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const errPriceListNotFound = "price list not found"
+
+type PriceListRepositoryInMemoryImpl struct {
+	priceLists []model.PriceListEntity
+	prices     []model.ProductPriceEntity
+	tiers      []model.ProductPriceTierEntity
+}
+
+func NewPriceListRepository() repository.PriceListRepository {
+	return &PriceListRepositoryInMemoryImpl{
+		priceLists: []model.PriceListEntity{},
+		prices:     []model.ProductPriceEntity{},
+		tiers:      []model.ProductPriceTierEntity{},
+	}
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindPriceLists() ([]model.PriceListEntity, error) {
+	var priceLists []model.PriceListEntity
+	for _, l := range r.priceLists {
+		if l.DeletedAt == nil {
+			priceLists = append(priceLists, l)
+		}
+	}
+	sort.SliceStable(priceLists, func(i, j int) bool {
+		return priceLists[i].Code < priceLists[j].Code
+	})
+	return priceLists, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindPriceListByID(id string) (model.PriceListEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.PriceListEntity{}, err
+	}
+	return r.priceLists[i], nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) InsertPriceList(priceList model.PriceListEntity) (model.PriceListEntity, error) {
+	for _, l := range r.priceLists {
+		if l.DeletedAt == nil && strings.EqualFold(l.Code, priceList.Code) {
+			return model.PriceListEntity{}, errors.New("price list code already exists")
+		}
+	}
+
+	priceList.CreatedAt = time.Now()
+	priceList.UpdatedAt = priceList.CreatedAt
+	priceList.Version = 1
+	r.priceLists = append(r.priceLists, priceList)
+	return priceList, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) UpdatePriceListByID(id string, priceList model.PriceListEntity) (model.PriceListEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.PriceListEntity{}, err
+	}
+
+	existing := r.priceLists[i]
+	priceList.ID = existing.ID
+	priceList.CreatedAt = existing.CreatedAt
+	priceList.CreatedBy = existing.CreatedBy
+	priceList.UpdatedAt = time.Now()
+	priceList.Version = existing.Version + 1
+	r.priceLists[i] = priceList
+	return priceList, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) DeletePriceListByID(id string) error {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r.priceLists[i].DeletedAt = &now
+	return nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindPriceListsChangedSince(since uint64) ([]model.PriceListEntity, error) {
+	var priceLists []model.PriceListEntity
+	for _, l := range r.priceLists {
+		if l.ChangeSeq >= since {
+			priceLists = append(priceLists, l)
+		}
+	}
+	sort.SliceStable(priceLists, func(i, j int) bool {
+		return priceLists[i].ChangeSeq < priceLists[j].ChangeSeq
+	})
+	return priceLists, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindProductPrices(productID string) ([]model.ProductPriceEntity, error) {
+	parsedID, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, nil
+	}
+
+	var prices []model.ProductPriceEntity
+	for _, p := range r.prices {
+		if p.ProductID == parsedID && p.DeletedAt == nil {
+			prices = append(prices, r.withCode(p))
+		}
+	}
+	sort.SliceStable(prices, func(i, j int) bool {
+		if prices[i].PriceListCode != prices[j].PriceListCode {
+			return prices[i].PriceListCode < prices[j].PriceListCode
+		}
+		return prices[i].StartsAt.Before(prices[j].StartsAt)
+	})
+	return prices, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) InsertProductPrice(price model.ProductPriceEntity) (model.ProductPriceEntity, error) {
+	for _, p := range r.prices {
+		if p.ProductID == price.ProductID && p.DeletedAt == nil && p.StartsAt.Equal(price.StartsAt) && samePriceList(p.PriceListID, price.PriceListID) {
+			return model.ProductPriceEntity{}, errors.New("a price of the list already starts then")
+		}
+	}
+
+	price.CreatedAt = time.Now()
+	r.prices = append(r.prices, price)
+	return r.withCode(price), nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindProductPriceByID(productID, id string) (model.ProductPriceEntity, error) {
+	for _, p := range r.prices {
+		if p.ProductID.String() == productID && p.ID.String() == id {
+			return r.withCode(p), nil
+		}
+	}
+	return model.ProductPriceEntity{}, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) CancelProductPrice(productID, id string) error {
+	for i, p := range r.prices {
+		if p.ProductID.String() == productID && p.ID.String() == id && p.DeletedAt == nil {
+			now := time.Now()
+			r.prices[i].DeletedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindProductPricesByProductIDs(productIDs []string) ([]model.ProductPriceEntity, error) {
+	var prices []model.ProductPriceEntity
+	for _, id := range productIDs {
+		productPrices, _ := r.FindProductPrices(id)
+		prices = append(prices, productPrices...)
+	}
+	return prices, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindProductPriceTiers(productID string) ([]model.ProductPriceTierEntity, error) {
+	var tiers []model.ProductPriceTierEntity
+	for _, t := range r.tiers {
+		if t.ProductID.String() == productID {
+			tiers = append(tiers, t)
+		}
+	}
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].MinQuantity < tiers[j].MinQuantity
+	})
+	return tiers, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) FindProductPriceTiersByProductIDs(productIDs []string) ([]model.ProductPriceTierEntity, error) {
+	var tiers []model.ProductPriceTierEntity
+	for _, id := range productIDs {
+		productTiers, _ := r.FindProductPriceTiers(id)
+		tiers = append(tiers, productTiers...)
+	}
+	return tiers, nil
+}
+
+func (r *PriceListRepositoryInMemoryImpl) ReplaceProductPriceTiers(productID string, tiers []model.ProductPriceTierEntity) ([]model.ProductPriceTierEntity, error) {
+	seen := map[int]bool{}
+	for _, t := range tiers {
+		if seen[t.MinQuantity] {
+			return nil, errors.New("a tier of the product already starts at that quantity")
+		}
+		seen[t.MinQuantity] = true
+	}
+
+	kept := []model.ProductPriceTierEntity{}
+	for _, t := range r.tiers {
+		if t.ProductID.String() != productID {
+			kept = append(kept, t)
+		}
+	}
+	now := time.Now()
+	for _, t := range tiers {
+		t.CreatedAt = now
+		kept = append(kept, t)
+	}
+	r.tiers = kept
+	return r.FindProductPriceTiers(productID)
+}
+
+// withCode fills in the price list code the PostgreSQL implementation would JOIN
+func (r *PriceListRepositoryInMemoryImpl) withCode(price model.ProductPriceEntity) model.ProductPriceEntity {
+	if price.PriceListID == nil {
+		return price
+	}
+	for _, l := range r.priceLists {
+		if l.ID == *price.PriceListID {
+			price.PriceListCode = l.Code
+		}
+	}
+	return price
+}
+
+// samePriceList compares optional price list ids, nil being the base price schedule
+func samePriceList(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (r *PriceListRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errPriceListNotFound)
+	}
+	for i, l := range r.priceLists {
+		if l.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errPriceListNotFound)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceListRepositoryInMemory_CRUD(t *testing.T) {
+	repo := NewPriceListRepository()
+
+	priceList, err := repo.InsertPriceList(model.PriceListEntity{ID: uuid.New(), Code: "GROSIR", Name: "Wholesale", IsActive: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, priceList.Version)
+
+	_, err = repo.InsertPriceList(model.PriceListEntity{ID: uuid.New(), Code: "grosir", Name: "Wholesale 2"})
+	assert.Error(t, err, "price list codes are unique regardless of case")
+
+	updated, err := repo.UpdatePriceListByID(priceList.ID.String(), model.PriceListEntity{Code: "GROSIR", Name: "Grosir"})
+	require.NoError(t, err)
+	assert.Equal(t, "Grosir", updated.Name)
+	assert.Equal(t, 2, updated.Version)
+
+	require.NoError(t, repo.DeletePriceListByID(priceList.ID.String()))
+	priceLists, _ := repo.FindPriceLists()
+	assert.Empty(t, priceLists)
+
+	_, err = repo.FindPriceListByID(uuid.New().String())
+	assert.Error(t, err)
+}
+
+func TestPriceListRepositoryInMemory_FindPriceListsChangedSince(t *testing.T) {
+	repo := NewPriceListRepository()
+	old, _ := repo.InsertPriceList(model.PriceListEntity{ID: uuid.New(), Code: "OLD", Name: "Old", ChangeSeq: 90})
+	_, _ = repo.InsertPriceList(model.PriceListEntity{ID: uuid.New(), Code: "MEMBER", Name: "Member", IsActive: true, ChangeSeq: 120})
+	require.NoError(t, repo.DeletePriceListByID(old.ID.String()))
+	_, _ = repo.InsertPriceList(model.PriceListEntity{ID: uuid.New(), Code: "GROSIR", Name: "Wholesale", ChangeSeq: 110})
+
+	priceLists, err := repo.FindPriceListsChangedSince(100)
+	require.NoError(t, err)
+	require.Len(t, priceLists, 2)
+	assert.Equal(t, "GROSIR", priceLists[0].Code, "inactive lists are returned too")
+	assert.Equal(t, "MEMBER", priceLists[1].Code)
+
+	priceLists, err = repo.FindPriceListsChangedSince(0)
+	require.NoError(t, err)
+	assert.Len(t, priceLists, 3, "deleted lists are returned too")
+}
+
+func TestPriceListRepositoryInMemory_ProductPrices(t *testing.T) {
+	repo := NewPriceListRepository()
+	priceList, _ := repo.InsertPriceList(model.PriceListEntity{ID: uuid.New(), Code: "MEMBER", Name: "Member", IsActive: true})
+	productID := uuid.New()
+	startsAt := time.Now().Add(time.Hour)
+
+	base, err := repo.InsertProductPrice(model.ProductPriceEntity{ID: uuid.New(), ProductID: productID, Price: 10000, StartsAt: startsAt})
+	require.NoError(t, err)
+	listed, err := repo.InsertProductPrice(model.ProductPriceEntity{ID: uuid.New(), ProductID: productID, PriceListID: &priceList.ID, Price: 9000, StartsAt: startsAt})
+	require.NoError(t, err, "another list may start at the same time")
+	assert.Equal(t, "MEMBER", listed.PriceListCode)
+
+	_, err = repo.InsertProductPrice(model.ProductPriceEntity{ID: uuid.New(), ProductID: productID, Price: 11000, StartsAt: startsAt})
+	assert.Error(t, err, "one price per list and start")
+
+	prices, _ := repo.FindProductPrices(productID.String())
+	assert.Len(t, prices, 2)
+
+	found, _ := repo.FindProductPriceByID(productID.String(), base.ID.String())
+	assert.Equal(t, base.ID, found.ID)
+	missing, _ := repo.FindProductPriceByID(uuid.New().String(), base.ID.String())
+	assert.Equal(t, uuid.Nil, missing.ID)
+
+	require.NoError(t, repo.CancelProductPrice(productID.String(), base.ID.String()))
+	prices, _ = repo.FindProductPrices(productID.String())
+	require.Len(t, prices, 1)
+	assert.Equal(t, listed.ID, prices[0].ID)
+}
+
+func TestPriceListRepositoryInMemory_PriceTiers(t *testing.T) {
+	repo := NewPriceListRepository()
+	productID, otherID := uuid.New(), uuid.New()
+
+	_, err := repo.ReplaceProductPriceTiers(otherID.String(), []model.ProductPriceTierEntity{{ID: uuid.New(), ProductID: otherID, MinQuantity: 1, Price: 500}})
+	require.NoError(t, err)
+	tiers, err := repo.ReplaceProductPriceTiers(productID.String(), []model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 12, Price: 3000},
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 1, Price: 3500},
+	})
+	require.NoError(t, err)
+	require.Len(t, tiers, 2)
+	assert.Equal(t, 1, tiers[0].MinQuantity)
+
+	_, err = repo.ReplaceProductPriceTiers(productID.String(), []model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 6, Price: 3200},
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 6, Price: 3100},
+	})
+	assert.Error(t, err, "one tier per quantity")
+
+	tiers, err = repo.ReplaceProductPriceTiers(productID.String(), []model.ProductPriceTierEntity{})
+	require.NoError(t, err)
+	assert.Empty(t, tiers)
+	others, _ := repo.FindProductPriceTiers(otherID.String())
+	assert.Len(t, others, 1, "another product's tiers are left alone")
+}
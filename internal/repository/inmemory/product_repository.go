@@ -6,23 +6,33 @@ import (
 
 	"errors"
 	"github.com/google/uuid"
+	"sort"
 	"strings"
+	"time"
 )
 
 const errProductNotFound = "product not found"
 
 type ProductRepositoryInMemoryImpl struct {
 	products []model.ProductEntity
+	units    []model.ProductUnitEntity
+	images   []model.ProductImageEntity
 }
 
 func NewProductRepository() repository.ProductRepository {
 	return &ProductRepositoryInMemoryImpl{
 		products: []model.ProductEntity{},
+		units:    []model.ProductUnitEntity{},
+		images:   []model.ProductImageEntity{},
 	}
 }
 
 func (r *ProductRepositoryInMemoryImpl) FindProducts() ([]model.ProductEntity, error) {
-	return r.products, nil
+	var products []model.ProductEntity
+	for _, p := range r.products {
+		products = append(products, r.withComponents(p))
+	}
+	return products, nil
 }
 
 func (r *ProductRepositoryInMemoryImpl) FindProductByID(id string) (model.ProductEntity, error) {
@@ -32,12 +42,76 @@ func (r *ProductRepositoryInMemoryImpl) FindProductByID(id string) (model.Produc
 	}
 	for _, p := range r.products {
 		if p.ID == parsedID {
-			return p, nil
+			return r.withComponents(p), nil
 		}
 	}
 	return model.ProductEntity{}, errors.New(errProductNotFound)
 }
 
+func (r *ProductRepositoryInMemoryImpl) FindProductBySKU(sku string) (model.ProductEntity, error) {
+	for _, p := range r.products {
+		if p.DeletedAt == nil && p.SKU != "" && strings.EqualFold(p.SKU, sku) {
+			return r.withComponents(p), nil
+		}
+	}
+	return model.ProductEntity{}, errors.New(errProductNotFound)
+}
+
+func (r *ProductRepositoryInMemoryImpl) FindBundlesByComponentID(componentID string) ([]model.ProductEntity, error) {
+	var products []model.ProductEntity
+	for _, p := range r.products {
+		if p.DeletedAt != nil || !p.IsBundle() {
+			continue
+		}
+		for _, c := range p.Components {
+			if c.ComponentID.String() == componentID {
+				products = append(products, r.withComponents(p))
+				break
+			}
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) FindProductsByCategoryIDs(categoryIDs []string) ([]model.ProductEntity, error) {
+	var products []model.ProductEntity
+	for _, p := range r.products {
+		if p.DeletedAt != nil || p.CategoryID == nil {
+			continue
+		}
+		for _, id := range categoryIDs {
+			if p.CategoryID.String() == id {
+				products = append(products, r.withComponents(p))
+				break
+			}
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) FindCategoryProductStats() ([]model.CategoryProductStatsEntity, error) {
+	var stats []model.CategoryProductStatsEntity
+	index := map[uuid.UUID]int{}
+	for _, p := range r.products {
+		if p.CategoryID == nil {
+			continue
+		}
+		i, ok := index[*p.CategoryID]
+		if !ok {
+			i = len(stats)
+			index[*p.CategoryID] = i
+			stats = append(stats, model.CategoryProductStatsEntity{CategoryID: *p.CategoryID})
+		}
+		if p.DeletedAt != nil {
+			stats[i].InactiveProducts++
+			continue
+		}
+		stats[i].ActiveProducts++
+		stats[i].StockValue += p.StockValue()
+	}
+	return stats, nil
+}
+
 func (r *ProductRepositoryInMemoryImpl) FindProductsByNameAndActiveStatus(name string, activeStatus *bool) ([]model.ProductEntity, error) {
 	var products []model.ProductEntity
 	for _, p := range r.products {
@@ -50,6 +124,16 @@ func (r *ProductRepositoryInMemoryImpl) FindProductsByNameAndActiveStatus(name s
 		}
 
 		if matchName && statusMatch {
+			products = append(products, r.withComponents(p))
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) FindLowStockProducts() ([]model.ProductEntity, error) {
+	var products []model.ProductEntity
+	for _, p := range r.products {
+		if p.DeletedAt == nil && !p.IsBundle() && p.IsLowStock() {
 			products = append(products, p)
 		}
 	}
@@ -69,6 +153,7 @@ func (r *ProductRepositoryInMemoryImpl) UpdateProductByID(id string, product mod
 	for i, p := range r.products {
 		if p.ID == parsedID {
 			product.ID = parsedID
+			product.QuantityScale = p.QuantityScale // the scale stock is counted at never changes
 			r.products[i] = product
 			return product, nil
 		}
@@ -89,3 +174,152 @@ func (r *ProductRepositoryInMemoryImpl) DeleteProductByID(id string) error {
 	}
 	return errors.New(errProductNotFound)
 }
+
+// FindProductsChangedSince keeps the ChangeSeq the products were stored with, nothing is written concurrently
+// here so the next cursor is just past the latest one
+func (r *ProductRepositoryInMemoryImpl) FindProductsChangedSince(since uint64) ([]model.ProductEntity, uint64, error) {
+	var products []model.ProductEntity
+	next := since
+	for _, p := range r.products {
+		if p.ChangeSeq >= since {
+			products = append(products, r.withComponents(p))
+			next = max(next, p.ChangeSeq+1)
+		}
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		return products[i].ChangeSeq < products[j].ChangeSeq
+	})
+	return products, next, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) ApplyProductBatch(operations []model.ProductBatchOperationEntity, atomic bool) ([]model.ProductBatchResultEntity, error) {
+	// a failed atomic batch puts the products back as they were
+	snapshot := append([]model.ProductEntity(nil), r.products...)
+	results := make([]model.ProductBatchResultEntity, len(operations))
+	for i, op := range operations {
+		var err error
+		switch op.Op {
+		case model.ProductBatchCreate:
+			results[i].Product, err = r.InsertProduct(op.Product)
+		case model.ProductBatchUpdate:
+			results[i].Product, err = r.UpdateProductByID(op.Product.ID.String(), op.Product)
+		case model.ProductBatchDelete:
+			err = r.DeleteProductByID(op.Product.ID.String())
+			results[i].Product = op.Product
+		default:
+			err = errors.New("unknown operation " + op.Op)
+		}
+		if err != nil {
+			results[i] = model.ProductBatchResultEntity{Error: err.Error()}
+			if atomic {
+				r.products = snapshot
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// withComponents fills in the component details the PostgreSQL implementation would JOIN, and the product's images
+func (r *ProductRepositoryInMemoryImpl) withComponents(product model.ProductEntity) model.ProductEntity {
+	product.Images = r.productImages(product.ID)
+	if len(product.Components) == 0 {
+		return product
+	}
+
+	components := make([]model.BundleComponentEntity, len(product.Components))
+	for i, c := range product.Components {
+		c.BundleID = product.ID
+		for _, p := range r.products {
+			if p.ID == c.ComponentID {
+				c.ComponentName = p.Name
+				c.ComponentPrice = p.Price
+				c.ComponentStocks = p.Stocks
+				c.ComponentCost = p.CostPrice
+				break
+			}
+		}
+		components[i] = c
+	}
+	product.Components = components
+	return product
+}
+
+func (r *ProductRepositoryInMemoryImpl) FindProductUnits(productID string) ([]model.ProductUnitEntity, error) {
+	var units []model.ProductUnitEntity
+	for _, u := range r.units {
+		if u.ProductID.String() == productID {
+			units = append(units, u)
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].Name < units[j].Name
+	})
+	return units, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) FindProductUnitsByProductIDs(productIDs []string) ([]model.ProductUnitEntity, error) {
+	var units []model.ProductUnitEntity
+	for _, id := range productIDs {
+		productUnits, _ := r.FindProductUnits(id)
+		units = append(units, productUnits...)
+	}
+	return units, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) ReplaceProductUnits(productID string, units []model.ProductUnitEntity) ([]model.ProductUnitEntity, error) {
+	seen := map[string]bool{}
+	for _, u := range units {
+		if seen[strings.ToLower(u.Name)] {
+			return nil, errors.New("the product already has a unit of that name")
+		}
+		seen[strings.ToLower(u.Name)] = true
+	}
+
+	kept := []model.ProductUnitEntity{}
+	for _, u := range r.units {
+		if u.ProductID.String() != productID {
+			kept = append(kept, u)
+		}
+	}
+	now := time.Now()
+	for _, u := range units {
+		u.CreatedAt = now
+		kept = append(kept, u)
+	}
+	r.units = kept
+	return r.FindProductUnits(productID)
+}
+
+func (r *ProductRepositoryInMemoryImpl) productImages(productID uuid.UUID) []model.ProductImageEntity {
+	var images []model.ProductImageEntity
+	for _, i := range r.images {
+		if i.ProductID == productID {
+			images = append(images, i)
+		}
+	}
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].Position < images[j].Position
+	})
+	return images
+}
+
+func (r *ProductRepositoryInMemoryImpl) InsertProductImage(image model.ProductImageEntity) (model.ProductImageEntity, error) {
+	image.Position = 1
+	for _, i := range r.productImages(image.ProductID) {
+		image.Position = max(image.Position, i.Position+1)
+	}
+	image.CreatedAt = time.Now()
+	r.images = append(r.images, image)
+	return image, nil
+}
+
+func (r *ProductRepositoryInMemoryImpl) DeleteProductImage(productID, imageID string) (model.ProductImageEntity, error) {
+	for i, image := range r.images {
+		if image.ID.String() == imageID && image.ProductID.String() == productID {
+			r.images = append(r.images[:i], r.images[i+1:]...)
+			return image, nil
+		}
+	}
+	return model.ProductImageEntity{}, errors.New("product image not found")
+}
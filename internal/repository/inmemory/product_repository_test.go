@@ -251,3 +251,178 @@ func TestInMemoryProductRepository_FindProductsByNameAndActiveStatus(t *testing.
 	require.NoError(t, err)
 	assert.Empty(t, results)
 }
+
+func TestProductRepositoryInMemory_FindLowStockProducts(t *testing.T) {
+	repo := NewProductRepository()
+
+	lowID, _ := uuid.NewV7()
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: lowID, Name: "Kopi", Stocks: 5, ReorderPoint: 5})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Teh", Stocks: 6, ReorderPoint: 5})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Gula", Stocks: 0})
+
+	results, err := repo.FindLowStockProducts()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, lowID, results[0].ID)
+}
+
+func TestInMemoryProductRepository_FindProductsChangedSince(t *testing.T) {
+	repo := NewProductRepository()
+	deletedAt := time.Now()
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Old", ChangeSeq: 90})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Deleted", ChangeSeq: 130, DeletedAt: &deletedAt})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Changed", ChangeSeq: 100})
+
+	products, next, err := repo.FindProductsChangedSince(100)
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "Changed", products[0].Name, "oldest change first, the cursor itself included")
+	assert.Equal(t, "Deleted", products[1].Name, "soft-deleted products are sent too")
+	assert.Equal(t, uint64(131), next, "the next pull starts past the latest write")
+}
+
+func TestInMemoryProductRepository_ApplyProductBatch(t *testing.T) {
+	repo := NewProductRepository()
+	kopi := model.ProductEntity{ID: uuid.New(), Name: "Kopi", Price: 18000}
+	_, _ = repo.InsertProduct(kopi)
+
+	teh := model.ProductEntity{ID: uuid.New(), Name: "Teh", Price: 8000}
+	missing := model.ProductEntity{ID: uuid.New()}
+	kopi.Price = 20000
+	operations := []model.ProductBatchOperationEntity{
+		{Op: model.ProductBatchCreate, Product: teh},
+		{Op: model.ProductBatchUpdate, Product: kopi},
+		{Op: model.ProductBatchDelete, Product: missing},
+	}
+
+	results, err := repo.ApplyProductBatch(operations, true)
+	require.NoError(t, err)
+	assert.Equal(t, errProductNotFound, results[2].Error)
+	products, _ := repo.FindProducts()
+	require.Len(t, products, 1, "a failed atomic batch applies nothing")
+	assert.Equal(t, int64(18000), products[0].Price)
+
+	results, err = repo.ApplyProductBatch(operations, false)
+	require.NoError(t, err)
+	assert.Equal(t, "Teh", results[0].Product.Name)
+	assert.Equal(t, int64(20000), results[1].Product.Price)
+	assert.Equal(t, errProductNotFound, results[2].Error)
+	products, _ = repo.FindProducts()
+	assert.Len(t, products, 2, "best effort keeps what succeeded")
+}
+
+func TestInMemoryProductRepository_SKUAndUnits(t *testing.T) {
+	repo := NewProductRepository()
+	product, _ := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Beras", SKU: "00123", Unit: "kg", QuantityScale: 3})
+
+	found, err := repo.FindProductBySKU("00123")
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, found.ID)
+	_, err = repo.FindProductBySKU("99999")
+	assert.Error(t, err)
+
+	updated, err := repo.UpdateProductByID(product.ID.String(), model.ProductEntity{Name: "Beras Premium", SKU: "00123", Unit: "kg"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.QuantityScale, "the scale stock is counted at stays")
+
+	units, err := repo.ReplaceProductUnits(product.ID.String(), []model.ProductUnitEntity{
+		{ID: uuid.New(), ProductID: product.ID, Name: "sack", Factor: 5000},
+		{ID: uuid.New(), ProductID: product.ID, Name: "cup", Factor: 200},
+	})
+	require.NoError(t, err)
+	require.Len(t, units, 2)
+	assert.Equal(t, "cup", units[0].Name)
+
+	_, err = repo.ReplaceProductUnits(product.ID.String(), []model.ProductUnitEntity{
+		{ID: uuid.New(), ProductID: product.ID, Name: "sack", Factor: 5000},
+		{ID: uuid.New(), ProductID: product.ID, Name: "Sack", Factor: 10000},
+	})
+	assert.Error(t, err, "unit names are unique regardless of case")
+
+	units, _ = repo.ReplaceProductUnits(product.ID.String(), []model.ProductUnitEntity{})
+	assert.Empty(t, units)
+}
+
+func TestInMemoryProductRepository_FindProductsByCategoryIDs(t *testing.T) {
+	repo := NewProductRepository()
+	coffee, tea := uuid.New(), uuid.New()
+	espresso, _ := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Espresso", CategoryID: &coffee})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Green Tea", CategoryID: &tea})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Sugar"})
+	latte, _ := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Latte", CategoryID: &coffee})
+	require.NoError(t, repo.DeleteProductByID(latte.ID.String()))
+
+	products, err := repo.FindProductsByCategoryIDs([]string{coffee.String()})
+	require.NoError(t, err)
+	require.Len(t, products, 1, "deleted products are left out")
+	assert.Equal(t, espresso.ID, products[0].ID)
+
+	products, _ = repo.FindProductsByCategoryIDs([]string{coffee.String(), tea.String()})
+	assert.Len(t, products, 2)
+}
+
+func TestInMemoryProductRepository_FindBundlesByComponentID(t *testing.T) {
+	repo := NewProductRepository()
+	rice, _ := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Nasi", Type: model.ProductTypeStandard})
+	egg, _ := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Telur", Type: model.ProductTypeStandard})
+	combo, _ := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Paket Hemat", Type: model.ProductTypeBundle,
+		Components: []model.BundleComponentEntity{{ComponentID: rice.ID, Quantity: 1}, {ComponentID: egg.ID, Quantity: 1}}})
+
+	bundles, err := repo.FindBundlesByComponentID(rice.ID.String())
+	require.NoError(t, err)
+	require.Len(t, bundles, 1)
+	assert.Equal(t, combo.ID, bundles[0].ID)
+
+	bundles, _ = repo.FindBundlesByComponentID(combo.ID.String())
+	assert.Empty(t, bundles, "a bundle is no one's component")
+}
+
+func TestInMemoryProductRepository_FindCategoryProductStats(t *testing.T) {
+	repo := NewProductRepository()
+	coffee := uuid.New()
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Espresso", CategoryID: &coffee, Stocks: 10, CostPrice: 5000})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Beans", CategoryID: &coffee, Stocks: 1500, CostPrice: 120000, QuantityScale: 3})
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Sugar", Stocks: 4, CostPrice: 1000})
+	deletedAt := time.Now()
+	_, _ = repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Latte", CategoryID: &coffee, Stocks: 5, CostPrice: 8000, DeletedAt: &deletedAt})
+
+	stats, err := repo.FindCategoryProductStats()
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "products without a category are not counted")
+	assert.Equal(t, model.CategoryProductStatsEntity{CategoryID: coffee, ActiveProducts: 2, InactiveProducts: 1, StockValue: 230000}, stats[0])
+}
+
+func TestInMemoryProductRepository_ProductImages(t *testing.T) {
+	repo := NewProductRepository()
+	product, err := repo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Kopi", Type: model.ProductTypeStandard})
+	require.NoError(t, err)
+
+	first, err := model.NewProductImageEntity(product.ID, "image/jpeg")
+	require.NoError(t, err)
+	second, err := model.NewProductImageEntity(product.ID, "image/png")
+	require.NoError(t, err)
+	first, err = repo.InsertProductImage(first)
+	require.NoError(t, err)
+	second, err = repo.InsertProductImage(second)
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Position)
+	assert.Equal(t, 2, second.Position)
+
+	found, err := repo.FindProductByID(product.ID.String())
+	require.NoError(t, err)
+	require.Len(t, found.Images, 2)
+	assert.Equal(t, first.ID, found.Images[0].ID)
+
+	deleted, err := repo.DeleteProductImage(product.ID.String(), first.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, first.Key, deleted.Key)
+	_, err = repo.DeleteProductImage(product.ID.String(), first.ID.String())
+	assert.Error(t, err)
+	_, err = repo.DeleteProductImage(uuid.NewString(), second.ID.String())
+	assert.Error(t, err, "the image belongs to another product")
+
+	products, err := repo.FindProducts()
+	require.NoError(t, err)
+	require.Len(t, products[0].Images, 1)
+	assert.Equal(t, second.ID, products[0].Images[0].ID)
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const errPurchaseOrderNotFound = "purchase order not found"
+
+type PurchaseOrderRepositoryInMemoryImpl struct {
+	orders            []model.PurchaseOrderEntity
+	supplierRepo      repository.SupplierRepository
+	productRepo       repository.ProductRepository
+	stockMovementRepo repository.StockMovementRepository
+}
+
+func NewPurchaseOrderRepository(supplierRepo repository.SupplierRepository, productRepo repository.ProductRepository, stockMovementRepo repository.StockMovementRepository) repository.PurchaseOrderRepository {
+	return &PurchaseOrderRepositoryInMemoryImpl{
+		orders:            []model.PurchaseOrderEntity{},
+		supplierRepo:      supplierRepo,
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+	}
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) FindPurchaseOrders() ([]model.PurchaseOrderEntity, error) {
+	var orders []model.PurchaseOrderEntity
+	for _, o := range r.orders {
+		o = r.withNames(o)
+		o.Receipts = nil
+		orders = append(orders, o)
+	}
+	return orders, nil
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) FindPurchaseOrderByID(id string) (model.PurchaseOrderEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.PurchaseOrderEntity{}, err
+	}
+	return r.withNames(r.orders[i]), nil
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) InsertPurchaseOrder(order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error) {
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = order.CreatedAt
+	order.Version = 1
+	order.Lines = cloneLines(order.ID, order.Lines)
+	r.orders = append(r.orders, order)
+	return r.withNames(order), nil
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) UpdatePurchaseOrderByID(id string, order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.PurchaseOrderEntity{}, err
+	}
+	if r.orders[i].Status != model.PurchaseOrderDraft {
+		return model.PurchaseOrderEntity{}, fmt.Errorf("purchase order is %s, only drafts can be edited", r.orders[i].Status)
+	}
+
+	r.orders[i].SupplierID = order.SupplierID
+	r.orders[i].Notes = order.Notes
+	r.orders[i].Lines = cloneLines(r.orders[i].ID, order.Lines)
+	r.orders[i].UpdatedBy = order.UpdatedBy
+	r.orders[i].UpdatedAt = time.Now()
+	r.orders[i].Version++
+	return r.withNames(r.orders[i]), nil
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) UpdatePurchaseOrderStatus(id string, fromStatus, toStatus, actor string) (model.PurchaseOrderEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.PurchaseOrderEntity{}, err
+	}
+	if r.orders[i].Status != fromStatus {
+		return model.PurchaseOrderEntity{}, fmt.Errorf("purchase order is not %s", fromStatus)
+	}
+
+	now := time.Now()
+	r.orders[i].Status = toStatus
+	r.orders[i].UpdatedBy = actor
+	r.orders[i].UpdatedAt = now
+	r.orders[i].Version++
+	switch toStatus {
+	case model.PurchaseOrderSent:
+		r.orders[i].SentAt = &now
+	case model.PurchaseOrderClosed:
+		r.orders[i].ClosedAt = &now
+	}
+	return r.withNames(r.orders[i]), nil
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) ReceiveGoods(id string, receipt model.GoodsReceiptEntity) (model.PurchaseOrderEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.PurchaseOrderEntity{}, err
+	}
+	order := &r.orders[i]
+	if order.Status != model.PurchaseOrderSent && order.Status != model.PurchaseOrderPartiallyReceived {
+		return model.PurchaseOrderEntity{}, fmt.Errorf("purchase order is %s, goods can only be received once sent", order.Status)
+	}
+
+	// validate everything first so a bad line leaves the order untouched
+	lines := cloneLines(order.ID, order.Lines)
+	for _, received := range receipt.Lines {
+		found := false
+		for j := range lines {
+			if lines[j].ProductID == received.ProductID && received.Quantity <= lines[j].Outstanding() {
+				lines[j].QuantityReceived += received.Quantity
+				found = true
+				break
+			}
+		}
+		if !found {
+			return model.PurchaseOrderEntity{}, fmt.Errorf("product %s is not on this purchase order or exceeds the outstanding quantity", received.ProductID)
+		}
+	}
+
+	for _, received := range receipt.Lines {
+		if product, err := r.productRepo.FindProductByID(received.ProductID.String()); err == nil {
+			for _, l := range lines {
+				if l.ProductID == received.ProductID {
+					product.CostPrice = product.MovingAverageCost(received.Quantity, l.CostPrice)
+					_, _ = r.productRepo.UpdateProductByID(product.ID.String(), product)
+					break
+				}
+			}
+		}
+
+		movementID, _ := uuid.NewV7()
+		_, err := r.stockMovementRepo.InsertStockMovement(model.StockMovementEntity{
+			ID:          movementID,
+			ProductID:   received.ProductID,
+			Type:        model.StockMovementPurchaseReceipt,
+			Quantity:    received.Quantity,
+			Reason:      "goods receipt",
+			ReferenceID: &receipt.ID,
+			OutletID:    receipt.OutletID,
+			CreatedBy:   receipt.ReceivedBy,
+		})
+		if err != nil {
+			return model.PurchaseOrderEntity{}, err
+		}
+	}
+
+	now := time.Now()
+	receipt.ReceivedAt = now
+	order.Lines = lines
+	order.Receipts = append(order.Receipts, receipt)
+	order.Status = model.PurchaseOrderPartiallyReceived
+	if order.IsFullyReceived() {
+		order.Status = model.PurchaseOrderClosed
+		order.ClosedAt = &now
+	}
+	order.UpdatedBy = receipt.ReceivedBy
+	order.UpdatedAt = now
+	order.Version++
+	return r.withNames(*order), nil
+}
+
+func (r *PurchaseOrderRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errPurchaseOrderNotFound)
+	}
+	for i, o := range r.orders {
+		if o.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errPurchaseOrderNotFound)
+}
+
+func cloneLines(orderID uuid.UUID, lines []model.PurchaseOrderLineEntity) []model.PurchaseOrderLineEntity {
+	cloned := make([]model.PurchaseOrderLineEntity, len(lines))
+	for i, l := range lines {
+		l.PurchaseOrderID = orderID
+		cloned[i] = l
+	}
+	return cloned
+}
+
+// withNames mirrors the JOINs of the PostgreSQL implementation
+func (r *PurchaseOrderRepositoryInMemoryImpl) withNames(order model.PurchaseOrderEntity) model.PurchaseOrderEntity {
+	if supplier, err := r.supplierRepo.FindSupplierByID(order.SupplierID.String()); err == nil {
+		order.SupplierName = supplier.Name
+	}
+	productName := func(id uuid.UUID) string {
+		if product, err := r.productRepo.FindProductByID(id.String()); err == nil {
+			return product.Name
+		}
+		return ""
+	}
+
+	order.Lines = cloneLines(order.ID, order.Lines)
+	for i := range order.Lines {
+		order.Lines[i].ProductName = productName(order.Lines[i].ProductID)
+	}
+	receipts := make([]model.GoodsReceiptEntity, len(order.Receipts))
+	for i, receipt := range order.Receipts {
+		lines := make([]model.GoodsReceiptLineEntity, len(receipt.Lines))
+		for j, l := range receipt.Lines {
+			l.ProductName = productName(l.ProductID)
+			lines[j] = l
+		}
+		receipt.Lines = lines
+		receipts[i] = receipt
+	}
+	order.Receipts = receipts
+	return order
+}
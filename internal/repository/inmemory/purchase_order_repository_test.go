@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryPurchaseOrderRepository_ReceiveGoods(t *testing.T) {
+	productRepo := NewProductRepository()
+	supplierRepo := NewSupplierRepository()
+	stockMovementRepo := NewStockMovementRepository(productRepo)
+	repo := NewPurchaseOrderRepository(supplierRepo, productRepo, stockMovementRepo)
+
+	supplierID, productID := uuid.New(), uuid.New()
+	_, _ = supplierRepo.InsertSupplier(model.SupplierEntity{ID: supplierID, Name: "PT Sumber Makmur"})
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi", Stocks: 2})
+
+	orderID := uuid.New()
+	order, err := repo.InsertPurchaseOrder(model.PurchaseOrderEntity{
+		ID:         orderID,
+		SupplierID: supplierID,
+		Status:     model.PurchaseOrderDraft,
+		Lines:      []model.PurchaseOrderLineEntity{{ProductID: productID, QuantityOrdered: 10, CostPrice: 5000}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "PT Sumber Makmur", order.SupplierName)
+	assert.Equal(t, "Kopi", order.Lines[0].ProductName)
+
+	receipt := func(quantity int) model.GoodsReceiptEntity {
+		id := uuid.New()
+		return model.GoodsReceiptEntity{ID: id, PurchaseOrderID: orderID, ReceivedBy: "USER", Lines: []model.GoodsReceiptLineEntity{
+			{GoodsReceiptID: id, ProductID: productID, Quantity: quantity},
+		}}
+	}
+
+	_, err = repo.ReceiveGoods(orderID.String(), receipt(4))
+	assert.Error(t, err, "a draft cannot receive goods")
+
+	_, err = repo.UpdatePurchaseOrderStatus(orderID.String(), model.PurchaseOrderDraft, model.PurchaseOrderSent, "USER")
+	require.NoError(t, err)
+
+	order, err = repo.ReceiveGoods(orderID.String(), receipt(4))
+	require.NoError(t, err)
+	assert.Equal(t, model.PurchaseOrderPartiallyReceived, order.Status)
+	assert.Equal(t, 6, order.Lines[0].Outstanding())
+
+	_, err = repo.ReceiveGoods(orderID.String(), receipt(7))
+	assert.Error(t, err, "cannot receive more than outstanding")
+
+	// the rest is delivered straight to an outlet
+	outletID := uuid.New()
+	atOutlet := receipt(6)
+	atOutlet.OutletID = &outletID
+	order, err = repo.ReceiveGoods(orderID.String(), atOutlet)
+	require.NoError(t, err)
+	assert.Equal(t, model.PurchaseOrderClosed, order.Status)
+	assert.NotNil(t, order.ClosedAt)
+	assert.Len(t, order.Receipts, 2)
+
+	product, _ := productRepo.FindProductByID(productID.String())
+	assert.Equal(t, 12, product.Stocks)
+	movements, _ := stockMovementRepo.FindStockMovementsByProductID(productID.String())
+	require.Len(t, movements, 2)
+	assert.Equal(t, model.StockMovementPurchaseReceipt, movements[0].Type)
+	assert.Equal(t, &outletID, movements[0].OutletID, "the latest receipt went to the outlet")
+	assert.Nil(t, movements[1].OutletID)
+}
+
+func TestInMemoryPurchaseOrderRepository_UpdateOnlyDraft(t *testing.T) {
+	productRepo := NewProductRepository()
+	repo := NewPurchaseOrderRepository(NewSupplierRepository(), productRepo, NewStockMovementRepository(productRepo))
+
+	orderID := uuid.New()
+	_, _ = repo.InsertPurchaseOrder(model.PurchaseOrderEntity{ID: orderID, Status: model.PurchaseOrderDraft})
+
+	updated, err := repo.UpdatePurchaseOrderByID(orderID.String(), model.PurchaseOrderEntity{
+		Notes: "urgent",
+		Lines: []model.PurchaseOrderLineEntity{{ProductID: uuid.New(), QuantityOrdered: 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "urgent", updated.Notes)
+	assert.Equal(t, orderID, updated.Lines[0].PurchaseOrderID)
+
+	_, _ = repo.UpdatePurchaseOrderStatus(orderID.String(), model.PurchaseOrderDraft, model.PurchaseOrderSent, "USER")
+	_, err = repo.UpdatePurchaseOrderByID(orderID.String(), model.PurchaseOrderEntity{})
+	assert.Error(t, err)
+}
+
+func TestInMemoryPurchaseOrderRepository_ReceiveGoodsUpdatesCost(t *testing.T) {
+	productRepo := NewProductRepository()
+	supplierRepo := NewSupplierRepository()
+	repo := NewPurchaseOrderRepository(supplierRepo, productRepo, NewStockMovementRepository(productRepo))
+
+	supplierID, productID := uuid.New(), uuid.New()
+	_, _ = supplierRepo.InsertSupplier(model.SupplierEntity{ID: supplierID, Name: "PT Sumber Makmur"})
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi", Stocks: 10, CostPrice: 4000})
+
+	orderID := uuid.New()
+	_, err := repo.InsertPurchaseOrder(model.PurchaseOrderEntity{
+		ID:         orderID,
+		SupplierID: supplierID,
+		Status:     model.PurchaseOrderSent,
+		Lines:      []model.PurchaseOrderLineEntity{{ProductID: productID, QuantityOrdered: 10, CostPrice: 6000}},
+	})
+	require.NoError(t, err)
+
+	receiptID := uuid.New()
+	_, err = repo.ReceiveGoods(orderID.String(), model.GoodsReceiptEntity{ID: receiptID, PurchaseOrderID: orderID, Lines: []model.GoodsReceiptLineEntity{
+		{GoodsReceiptID: receiptID, ProductID: productID, Quantity: 10},
+	}})
+	require.NoError(t, err)
+
+	product, _ := productRepo.FindProductByID(productID.String())
+	assert.Equal(t, 20, product.Stocks)
+	assert.Equal(t, int64(5000), product.CostPrice)
+}
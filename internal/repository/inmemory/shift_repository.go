@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errShiftNotFound      = "shift not found"
+	errShiftNotOpen       = "shift is not open"
+	errShiftCashierIsOpen = "cashier already has an open shift"
+)
+
+type ShiftRepositoryInMemoryImpl struct {
+	shifts     []model.ShiftEntity
+	entries    []model.CashEntryEntity
+	outletRepo repository.OutletRepository
+	txRepo     repository.TransactionRepository
+}
+
+func NewShiftRepository(outletRepo repository.OutletRepository, txRepo repository.TransactionRepository) repository.ShiftRepository {
+	return &ShiftRepositoryInMemoryImpl{
+		shifts:     []model.ShiftEntity{},
+		entries:    []model.CashEntryEntity{},
+		outletRepo: outletRepo,
+		txRepo:     txRepo,
+	}
+}
+
+func (r *ShiftRepositoryInMemoryImpl) FindShifts(status string) ([]model.ShiftEntity, error) {
+	// newest first, like the PostgreSQL implementation
+	var shifts []model.ShiftEntity
+	for i := len(r.shifts) - 1; i >= 0; i-- {
+		if status == "" || r.shifts[i].Status == status {
+			shifts = append(shifts, r.withEntries(r.shifts[i]))
+		}
+	}
+	return shifts, nil
+}
+
+func (r *ShiftRepositoryInMemoryImpl) FindShiftByID(id string) (model.ShiftEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.ShiftEntity{}, err
+	}
+	return r.withEntries(r.shifts[i]), nil
+}
+
+func (r *ShiftRepositoryInMemoryImpl) FindOpenShiftByCashier(cashierName string) (model.ShiftEntity, error) {
+	for _, s := range r.shifts {
+		if s.CashierName == cashierName && s.Status == model.ShiftOpen {
+			return r.withEntries(s), nil
+		}
+	}
+	return model.ShiftEntity{}, nil
+}
+
+func (r *ShiftRepositoryInMemoryImpl) InsertShift(shift model.ShiftEntity) (model.ShiftEntity, error) {
+	if open, _ := r.FindOpenShiftByCashier(shift.CashierName); open.ID != uuid.Nil {
+		return model.ShiftEntity{}, errors.New(errShiftCashierIsOpen)
+	}
+	shift.CreatedAt = time.Now()
+	shift.UpdatedAt = shift.CreatedAt
+	shift.Version = 1
+	shift.CashEntries = nil
+	r.shifts = append(r.shifts, shift)
+	return r.withEntries(shift), nil
+}
+
+func (r *ShiftRepositoryInMemoryImpl) InsertCashEntry(entry model.CashEntryEntity) (model.CashEntryEntity, error) {
+	i, err := r.indexOf(entry.ShiftID.String())
+	if err != nil {
+		return model.CashEntryEntity{}, err
+	}
+	if r.shifts[i].Status != model.ShiftOpen {
+		return model.CashEntryEntity{}, errors.New(errShiftNotOpen)
+	}
+	entry.CreatedAt = time.Now()
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *ShiftRepositoryInMemoryImpl) CloseShift(id string, countedCash int64, notes string, actor string) (model.ShiftEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.ShiftEntity{}, err
+	}
+	if r.shifts[i].Status != model.ShiftOpen {
+		return model.ShiftEntity{}, errors.New(errShiftNotOpen)
+	}
+	sales, err := r.txRepo.GetShiftSales(id)
+	if err != nil {
+		return model.ShiftEntity{}, err
+	}
+	expected := model.NewShiftReport(r.withEntries(r.shifts[i]), sales).ExpectedCash.Amount
+
+	now := time.Now()
+	shift := &r.shifts[i]
+	shift.Status = model.ShiftClosed
+	shift.ClosedAt = &now
+	shift.ClosedBy = actor
+	shift.ExpectedCash = &expected
+	shift.CountedCash = &countedCash
+	if notes != "" {
+		shift.Notes = notes
+	}
+	shift.UpdatedBy = actor
+	shift.UpdatedAt = now
+	shift.Version++
+	return r.withEntries(*shift), nil
+}
+
+func (r *ShiftRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errShiftNotFound)
+	}
+	for i, s := range r.shifts {
+		if s.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errShiftNotFound)
+}
+
+func (r *ShiftRepositoryInMemoryImpl) withEntries(shift model.ShiftEntity) model.ShiftEntity {
+	if shift.OutletID != nil {
+		if outlet, err := r.outletRepo.FindOutletByID(shift.OutletID.String()); err == nil {
+			shift.OutletName = outlet.Name
+		}
+	}
+	shift.CashEntries = nil
+	for _, e := range r.entries {
+		if e.ShiftID == shift.ID {
+			shift.CashEntries = append(shift.CashEntries, e)
+		}
+	}
+	return shift
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShiftRepository() (*ShiftRepositoryInMemoryImpl, *TransactionRepositoryInMemoryImpl) {
+	productRepo := NewProductRepository()
+	txRepo := NewTransactionRepository(productRepo).(*TransactionRepositoryInMemoryImpl)
+	outletRepo := NewOutletRepository(productRepo, NewStockMovementRepository(productRepo))
+	return NewShiftRepository(outletRepo, txRepo).(*ShiftRepositoryInMemoryImpl), txRepo
+}
+
+func TestInMemoryShiftRepository_OneOpenShiftPerCashier(t *testing.T) {
+	repo, _ := newTestShiftRepository()
+
+	shift, err := repo.InsertShift(model.ShiftEntity{ID: uuid.New(), CashierName: "Sari", Status: model.ShiftOpen, OpeningFloat: 200000})
+	require.NoError(t, err)
+	assert.Equal(t, 1, shift.Version)
+
+	_, err = repo.InsertShift(model.ShiftEntity{ID: uuid.New(), CashierName: "Sari", Status: model.ShiftOpen})
+	assert.Error(t, err)
+
+	open, err := repo.FindOpenShiftByCashier("Sari")
+	require.NoError(t, err)
+	assert.Equal(t, shift.ID, open.ID)
+
+	none, err := repo.FindOpenShiftByCashier("Dewi")
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, none.ID)
+}
+
+func TestInMemoryShiftRepository_CloseShift(t *testing.T) {
+	repo, txRepo := newTestShiftRepository()
+	shift, _ := repo.InsertShift(model.ShiftEntity{ID: uuid.New(), CashierName: "Sari", Status: model.ShiftOpen, OpeningFloat: 200000})
+
+	_, err := repo.InsertCashEntry(model.CashEntryEntity{ID: uuid.New(), ShiftID: shift.ID, Type: model.CashEntryOut, Amount: 15000, Reason: "ice"})
+	require.NoError(t, err)
+	for _, tx := range []model.TransactionEntity{
+		{ID: uuid.New(), ShiftID: &shift.ID, TotalItems: 2, TotalPriceAmount: 50000},
+		{ID: uuid.New(), ShiftID: &shift.ID, TotalItems: 1, TotalPriceAmount: 30000, GiftCardAmount: 30000},
+		{ID: uuid.New(), TotalItems: 1, TotalPriceAmount: 99000},
+	} {
+		_, _ = txRepo.CreateTransaction(tx, nil)
+	}
+
+	sales, err := txRepo.GetShiftSales(shift.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, model.ShiftSalesEntity{TransactionCount: 2, ItemsSold: 3, NetSales: 80000, GiftCardAmount: 30000}, sales)
+
+	closed, err := repo.CloseShift(shift.ID.String(), 234000, "", "SUPERVISOR")
+	require.NoError(t, err)
+	assert.Equal(t, model.ShiftClosed, closed.Status)
+	assert.Equal(t, int64(235000), *closed.ExpectedCash, "float + cash sales - cash out")
+	assert.Equal(t, int64(234000), *closed.CountedCash)
+	require.Len(t, closed.CashEntries, 1)
+
+	_, err = repo.CloseShift(shift.ID.String(), 234000, "", "SUPERVISOR")
+	assert.Error(t, err)
+	_, err = repo.InsertCashEntry(model.CashEntryEntity{ID: uuid.New(), ShiftID: shift.ID, Type: model.CashEntryIn, Amount: 1000, Reason: "late"})
+	assert.Error(t, err)
+
+	openShifts, _ := repo.FindShifts(model.ShiftOpen)
+	assert.Empty(t, openShifts)
+	all, _ := repo.FindShifts("")
+	assert.Len(t, all, 1)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	errStockConflictNotFound = "stock conflict not found"
+	errStockConflictResolved = "stock conflict is already resolved"
+)
+
+type StockConflictRepositoryInMemoryImpl struct {
+	txRepo *TransactionRepositoryInMemoryImpl
+}
+
+// NewStockConflictRepository reads the conflicts txRepo booked with the offline sales, as the PostgreSQL implementation does
+func NewStockConflictRepository(txRepo *TransactionRepositoryInMemoryImpl) repository.StockConflictRepository {
+	return &StockConflictRepositoryInMemoryImpl{
+		txRepo: txRepo,
+	}
+}
+
+func (r *StockConflictRepositoryInMemoryImpl) FindStockConflicts(status string) ([]model.StockConflictEntity, error) {
+	var conflicts []model.StockConflictEntity
+	for i := len(r.txRepo.conflicts) - 1; i >= 0; i-- {
+		c := r.txRepo.conflicts[i]
+		if (status == model.StockConflictOpen && c.IsResolved()) || (status == model.StockConflictResolved && !c.IsResolved()) {
+			continue
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, nil
+}
+
+func (r *StockConflictRepositoryInMemoryImpl) FindStockConflictByID(id string) (model.StockConflictEntity, error) {
+	i := r.indexOf(id)
+	if i < 0 {
+		return model.StockConflictEntity{}, errors.New(errStockConflictNotFound)
+	}
+	return r.txRepo.conflicts[i], nil
+}
+
+func (r *StockConflictRepositoryInMemoryImpl) ResolveStockConflict(conflict model.StockConflictEntity) (model.StockConflictEntity, error) {
+	i := r.indexOf(conflict.ID.String())
+	if i < 0 {
+		return model.StockConflictEntity{}, errors.New(errStockConflictNotFound)
+	}
+	stored := &r.txRepo.conflicts[i]
+	if stored.IsResolved() {
+		return model.StockConflictEntity{}, errors.New(errStockConflictResolved)
+	}
+
+	now := time.Now()
+	stored.ResolvedAt = &now
+	stored.ResolvedBy = conflict.ResolvedBy
+	stored.ResolutionNotes = conflict.ResolutionNotes
+	return *stored, nil
+}
+
+func (r *StockConflictRepositoryInMemoryImpl) indexOf(id string) int {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1
+	}
+	for i, c := range r.txRepo.conflicts {
+		if c.ID == parsedID {
+			return i
+		}
+	}
+	return -1
+}
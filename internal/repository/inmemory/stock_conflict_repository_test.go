@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStockConflictRepository_OfflineSale(t *testing.T) {
+	productRepo := NewProductRepository()
+	kopi, _ := productRepo.InsertProduct(model.ProductEntity{ID: uuid.New(), Name: "Kopi Susu", Stocks: 1})
+	txRepo := NewTransactionRepository(productRepo).(*TransactionRepositoryInMemoryImpl)
+	repo := NewStockConflictRepository(txRepo)
+
+	txID, _ := uuid.NewV7()
+	_, err := txRepo.CreateTransaction(model.TransactionEntity{
+		ID:             txID,
+		TotalItems:     3,
+		StockConflicts: []model.StockConflictEntity{{ID: uuid.New(), TransactionID: txID, ProductID: kopi.ID, ProductName: kopi.Name, Quantity: 2}},
+	}, []model.TransactionDetailEntity{{ID: uuid.New(), TransactionID: txID, ProductID: &kopi.ID, Quantity: 3}})
+	require.NoError(t, err)
+
+	product, _ := productRepo.FindProductByID(kopi.ID.String())
+	assert.Equal(t, 0, product.Stocks, "the oversold units are booked back")
+
+	open, err := repo.FindStockConflicts(model.StockConflictOpen)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, 2, open[0].Quantity)
+
+	resolved, err := repo.ResolveStockConflict(model.StockConflictEntity{ID: open[0].ID, ResolvedBy: "USER", ResolutionNotes: "recounted"})
+	require.NoError(t, err)
+	assert.True(t, resolved.IsResolved())
+	assert.Equal(t, "recounted", resolved.ResolutionNotes)
+
+	open, _ = repo.FindStockConflicts(model.StockConflictOpen)
+	assert.Empty(t, open)
+	all, _ := repo.FindStockConflicts("")
+	assert.Len(t, all, 1)
+
+	_, err = repo.ResolveStockConflict(model.StockConflictEntity{ID: resolved.ID, ResolvedBy: "USER"})
+	assert.Error(t, err, "already resolved")
+	_, err = repo.FindStockConflictByID(uuid.NewString())
+	assert.Error(t, err)
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const errStockCountNotFound = "stock count not found"
+
+type StockCountRepositoryInMemoryImpl struct {
+	counts            []model.StockCountEntity
+	productRepo       repository.ProductRepository
+	stockMovementRepo repository.StockMovementRepository
+}
+
+func NewStockCountRepository(productRepo repository.ProductRepository, stockMovementRepo repository.StockMovementRepository) repository.StockCountRepository {
+	return &StockCountRepositoryInMemoryImpl{
+		counts:            []model.StockCountEntity{},
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+	}
+}
+
+func (r *StockCountRepositoryInMemoryImpl) FindStockCounts() ([]model.StockCountEntity, error) {
+	var counts []model.StockCountEntity
+	for _, c := range r.counts {
+		c.Lines = nil
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
+
+func (r *StockCountRepositoryInMemoryImpl) FindStockCountByID(id string) (model.StockCountEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.StockCountEntity{}, err
+	}
+	return r.withLiveStock(r.counts[i]), nil
+}
+
+func (r *StockCountRepositoryInMemoryImpl) InsertStockCount(count model.StockCountEntity) (model.StockCountEntity, error) {
+	products, err := r.productRepo.FindProducts()
+	if err != nil {
+		return model.StockCountEntity{}, err
+	}
+
+	count.Lines = nil
+	for _, p := range products {
+		if p.DeletedAt != nil || p.IsBundle() {
+			continue
+		}
+		if count.CategoryID != nil && (p.CategoryID == nil || *p.CategoryID != *count.CategoryID) {
+			continue
+		}
+		count.Lines = append(count.Lines, model.StockCountLineEntity{StockCountID: count.ID, ProductID: p.ID})
+	}
+	count.CreatedAt = time.Now()
+	count.UpdatedAt = count.CreatedAt
+	count.Version = 1
+
+	r.counts = append(r.counts, count)
+	return r.withLiveStock(count), nil
+}
+
+func (r *StockCountRepositoryInMemoryImpl) UpdateStockCountLines(id string, lines []model.StockCountLineEntity) (model.StockCountEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.StockCountEntity{}, err
+	}
+	if r.counts[i].Status != model.StockCountOpen {
+		return model.StockCountEntity{}, fmt.Errorf("stock count is %s, counts can only be entered while open", r.counts[i].Status)
+	}
+
+	// validate everything first so a bad line leaves the count untouched
+	existing := make([]model.StockCountLineEntity, len(r.counts[i].Lines))
+	copy(existing, r.counts[i].Lines)
+	now := time.Now()
+	for _, line := range lines {
+		found := false
+		for j := range existing {
+			if existing[j].ProductID == line.ProductID {
+				existing[j].CountedQuantity = line.CountedQuantity
+				existing[j].CountedAt = &now
+				existing[j].CountedBy = line.CountedBy
+				found = true
+				break
+			}
+		}
+		if !found {
+			return model.StockCountEntity{}, fmt.Errorf("product %s is not part of this stock count", line.ProductID)
+		}
+	}
+
+	r.counts[i].Lines = existing
+	r.counts[i].UpdatedAt = now
+	return r.withLiveStock(r.counts[i]), nil
+}
+
+func (r *StockCountRepositoryInMemoryImpl) UpdateStockCountStatus(id string, fromStatus, toStatus, actor string) (model.StockCountEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.StockCountEntity{}, err
+	}
+	if r.counts[i].Status != fromStatus {
+		return model.StockCountEntity{}, fmt.Errorf("stock count is not %s", fromStatus)
+	}
+
+	now := time.Now()
+	r.counts[i].Status = toStatus
+	r.counts[i].UpdatedBy = actor
+	r.counts[i].UpdatedAt = now
+	r.counts[i].Version++
+	if toStatus == model.StockCountSubmitted {
+		r.counts[i].SubmittedAt = &now
+	}
+	return r.withLiveStock(r.counts[i]), nil
+}
+
+func (r *StockCountRepositoryInMemoryImpl) PostStockCount(id string, actor string) (model.StockCountEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.StockCountEntity{}, err
+	}
+	if r.counts[i].Status != model.StockCountSubmitted {
+		return model.StockCountEntity{}, fmt.Errorf("stock count is not %s", model.StockCountSubmitted)
+	}
+
+	count := r.withLiveStock(r.counts[i])
+	for j, line := range count.Lines {
+		if line.CountedQuantity == nil {
+			continue
+		}
+		if variance := line.Variance(); variance != 0 {
+			movementID, _ := uuid.NewV7()
+			_, err := r.stockMovementRepo.InsertStockMovement(model.StockMovementEntity{
+				ID:          movementID,
+				ProductID:   line.ProductID,
+				Type:        model.StockMovementAdjustment,
+				Quantity:    variance,
+				Reason:      "stock count",
+				ReferenceID: &count.ID,
+				OutletID:    count.OutletID,
+				CreatedBy:   actor,
+			})
+			if err != nil {
+				return model.StockCountEntity{}, err
+			}
+		}
+		r.counts[i].Lines[j].SystemStock = line.SystemStock
+	}
+
+	now := time.Now()
+	r.counts[i].Status = model.StockCountPosted
+	r.counts[i].ApprovedAt = &now
+	r.counts[i].ApprovedBy = actor
+	r.counts[i].UpdatedBy = actor
+	r.counts[i].UpdatedAt = now
+	r.counts[i].Version++
+	return r.withLiveStock(r.counts[i]), nil
+}
+
+func (r *StockCountRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errStockCountNotFound)
+	}
+	for i, c := range r.counts {
+		if c.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errStockCountNotFound)
+}
+
+// withLiveStock mirrors COALESCE(system_stock, p.stock): lines read the product stock until the count is posted,
+// or the outlet's stock when the count is taken at an outlet
+func (r *StockCountRepositoryInMemoryImpl) withLiveStock(count model.StockCountEntity) model.StockCountEntity {
+	lines := make([]model.StockCountLineEntity, len(count.Lines))
+	for i, line := range count.Lines {
+		if product, err := r.productRepo.FindProductByID(line.ProductID.String()); err == nil {
+			line.ProductName = product.Name
+			line.ProductPrice = product.Price
+			if count.Status != model.StockCountPosted {
+				line.SystemStock = product.Stocks
+				if count.OutletID != nil {
+					line.SystemStock = r.outletStock(*count.OutletID, line.ProductID)
+				}
+			}
+		}
+		lines[i] = line
+	}
+	count.Lines = lines
+	return count
+}
+
+// outletStock sums the movements booked at the outlet, the in-memory stand-in for core.outlet_stock
+func (r *StockCountRepositoryInMemoryImpl) outletStock(outletID, productID uuid.UUID) int {
+	movements, err := r.stockMovementRepo.FindStockMovementsByProductID(productID.String())
+	if err != nil {
+		return 0
+	}
+	var stock int
+	for _, m := range movements {
+		if m.OutletID != nil && *m.OutletID == outletID {
+			stock += m.Quantity
+		}
+	}
+	return stock
+}
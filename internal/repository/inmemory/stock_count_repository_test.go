@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockCountRepositoryInMemory_Workflow(t *testing.T) {
+	productRepo := NewProductRepository()
+	movementRepo := NewStockMovementRepository(productRepo)
+	repo := NewStockCountRepository(productRepo, movementRepo)
+
+	dairyID := uuid.New()
+	milkID, breadID, bundleID := uuid.New(), uuid.New(), uuid.New()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: milkID, Name: "Susu", Price: 5000, Stocks: 12, CategoryID: &dairyID})
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: breadID, Name: "Roti", Price: 2000, Stocks: 4})
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: bundleID, Name: "Paket", Type: model.ProductTypeBundle})
+
+	countID, _ := uuid.NewV7()
+	count, err := repo.InsertStockCount(model.StockCountEntity{ID: countID, Status: model.StockCountOpen})
+	require.NoError(t, err)
+	assert.Len(t, count.Lines, 2, "bundles are not counted")
+
+	scopedID, _ := uuid.NewV7()
+	scoped, err := repo.InsertStockCount(model.StockCountEntity{ID: scopedID, Status: model.StockCountOpen, CategoryID: &dairyID})
+	require.NoError(t, err)
+	require.Len(t, scoped.Lines, 1)
+	assert.Equal(t, milkID, scoped.Lines[0].ProductID)
+
+	ten := 10
+	count, err = repo.UpdateStockCountLines(countID.String(), []model.StockCountLineEntity{{ProductID: milkID, CountedQuantity: &ten}})
+	require.NoError(t, err)
+	_, err = repo.UpdateStockCountLines(countID.String(), []model.StockCountLineEntity{{ProductID: uuid.New(), CountedQuantity: &ten}})
+	assert.Error(t, err)
+
+	_, err = repo.PostStockCount(countID.String(), "SUPERVISOR")
+	assert.Error(t, err, "only submitted counts can be posted")
+
+	_, err = repo.UpdateStockCountStatus(countID.String(), model.StockCountOpen, model.StockCountSubmitted, "USER")
+	require.NoError(t, err)
+	_, err = repo.UpdateStockCountLines(countID.String(), []model.StockCountLineEntity{{ProductID: milkID, CountedQuantity: &ten}})
+	assert.Error(t, err, "counts are locked once submitted")
+
+	posted, err := repo.PostStockCount(countID.String(), "SUPERVISOR")
+	require.NoError(t, err)
+	assert.Equal(t, model.StockCountPosted, posted.Status)
+	assert.Equal(t, "SUPERVISOR", posted.ApprovedBy)
+
+	milk, _ := productRepo.FindProductByID(milkID.String())
+	bread, _ := productRepo.FindProductByID(breadID.String())
+	assert.Equal(t, 10, milk.Stocks)
+	assert.Equal(t, 4, bread.Stocks, "uncounted products are left alone")
+
+	movements, _ := movementRepo.FindStockMovementsByProductID(milkID.String())
+	require.Len(t, movements, 1)
+	assert.Equal(t, -2, movements[0].Quantity)
+	assert.Equal(t, countID, *movements[0].ReferenceID)
+
+	// the posted count keeps the stock it was measured against
+	posted, _ = repo.FindStockCountByID(countID.String())
+	for _, line := range posted.Lines {
+		if line.ProductID == milkID {
+			assert.Equal(t, 12, line.SystemStock)
+		}
+	}
+
+	counts, err := repo.FindStockCounts()
+	require.NoError(t, err)
+	assert.Len(t, counts, 2)
+
+	_, err = repo.FindStockCountByID("invalid")
+	assert.Error(t, err)
+}
+
+func TestStockCountRepositoryInMemory_AtOutlet(t *testing.T) {
+	productRepo := NewProductRepository()
+	movementRepo := NewStockMovementRepository(productRepo)
+	repo := NewStockCountRepository(productRepo, movementRepo)
+
+	milkID, outletID := uuid.New(), uuid.New()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: milkID, Name: "Susu", Price: 5000, Stocks: 12})
+	_, err := movementRepo.InsertStockMovement(model.StockMovementEntity{
+		ID: uuid.New(), ProductID: milkID, Type: model.StockMovementTransfer, Quantity: 5, OutletID: &outletID,
+	})
+	require.NoError(t, err)
+
+	countID, _ := uuid.NewV7()
+	count, err := repo.InsertStockCount(model.StockCountEntity{ID: countID, Status: model.StockCountOpen, OutletID: &outletID})
+	require.NoError(t, err)
+	require.Len(t, count.Lines, 1)
+	assert.Equal(t, 5, count.Lines[0].SystemStock, "a count at an outlet is measured against the outlet's stock")
+
+	three := 3
+	_, err = repo.UpdateStockCountLines(countID.String(), []model.StockCountLineEntity{{ProductID: milkID, CountedQuantity: &three}})
+	require.NoError(t, err)
+	_, err = repo.UpdateStockCountStatus(countID.String(), model.StockCountOpen, model.StockCountSubmitted, "USER")
+	require.NoError(t, err)
+	posted, err := repo.PostStockCount(countID.String(), "SUPERVISOR")
+	require.NoError(t, err)
+	assert.Equal(t, 5, posted.Lines[0].SystemStock)
+
+	movements, _ := movementRepo.FindStockMovementsByProductID(milkID.String())
+	require.Len(t, movements, 2)
+	assert.Equal(t, -2, movements[0].Quantity)
+	assert.Equal(t, &outletID, movements[0].OutletID)
+
+	milk, _ := productRepo.FindProductByID(milkID.String())
+	assert.Equal(t, 15, milk.Stocks)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+type StockMovementRepositoryInMemoryImpl struct {
+	movements   []model.StockMovementEntity
+	productRepo repository.ProductRepository
+}
+
+func NewStockMovementRepository(productRepo repository.ProductRepository) repository.StockMovementRepository {
+	return &StockMovementRepositoryInMemoryImpl{
+		movements:   []model.StockMovementEntity{},
+		productRepo: productRepo,
+	}
+}
+
+func (r *StockMovementRepositoryInMemoryImpl) FindStockMovementsByProductID(productID string) ([]model.StockMovementEntity, error) {
+	parsedID, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, errors.New(errProductNotFound)
+	}
+
+	// newest first, like the PostgreSQL implementation
+	var movements []model.StockMovementEntity
+	for i := len(r.movements) - 1; i >= 0; i-- {
+		if r.movements[i].ProductID == parsedID {
+			movements = append(movements, r.movements[i])
+		}
+	}
+	return movements, nil
+}
+
+func (r *StockMovementRepositoryInMemoryImpl) InsertStockMovement(movement model.StockMovementEntity) (model.StockMovementEntity, error) {
+	product, err := r.productRepo.FindProductByID(movement.ProductID.String())
+	if err != nil || product.DeletedAt != nil {
+		return model.StockMovementEntity{}, errors.New(errProductNotFound)
+	}
+	if product.Stocks+movement.Quantity < 0 {
+		return model.StockMovementEntity{}, errors.New("stock cannot go below zero")
+	}
+	if movement.OutletID != nil && r.outletStock(*movement.OutletID, movement.ProductID)+movement.Quantity < 0 {
+		return model.StockMovementEntity{}, errors.New("outlet stock cannot go below zero")
+	}
+
+	product.Stocks += movement.Quantity
+	product.UpdatedAt = time.Now()
+	if _, err := r.productRepo.UpdateProductByID(product.ID.String(), product); err != nil {
+		return model.StockMovementEntity{}, err
+	}
+
+	movement.BalanceAfter = product.Stocks
+	movement.CreatedAt = time.Now()
+	r.movements = append(r.movements, movement)
+	return movement, nil
+}
+
+func (r *StockMovementRepositoryInMemoryImpl) GetStockReconciliation(productID string) (model.StockReconciliationEntity, error) {
+	product, err := r.productRepo.FindProductByID(productID)
+	if err != nil {
+		return model.StockReconciliationEntity{}, err
+	}
+
+	reconciliation := model.StockReconciliationEntity{ProductID: product.ID, Stock: product.Stocks}
+	for _, m := range r.movements {
+		if m.ProductID == product.ID {
+			reconciliation.LedgerBalance += m.Quantity
+		}
+	}
+	return reconciliation, nil
+}
+
+// outletStock sums the product's movements at the outlet, like core.outlet_stock does
+func (r *StockMovementRepositoryInMemoryImpl) outletStock(outletID, productID uuid.UUID) int {
+	var stock int
+	for _, m := range r.movements {
+		if m.ProductID == productID && m.OutletID != nil && *m.OutletID == outletID {
+			stock += m.Quantity
+		}
+	}
+	return stock
+}
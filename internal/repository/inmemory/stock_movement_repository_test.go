@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementRepositoryInMemory_InsertAndFind(t *testing.T) {
+	productRepo := NewProductRepository()
+	repo := NewStockMovementRepository(productRepo)
+
+	productID, _ := uuid.NewV7()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Susu", Stocks: 0})
+
+	receiptID, _ := uuid.NewV7()
+	receipt, err := repo.InsertStockMovement(model.StockMovementEntity{ID: receiptID, ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 10, receipt.BalanceAfter)
+
+	wasteID, _ := uuid.NewV7()
+	waste, err := repo.InsertStockMovement(model.StockMovementEntity{ID: wasteID, ProductID: productID, Type: model.StockMovementWaste, Quantity: -3})
+	require.NoError(t, err)
+	assert.Equal(t, 7, waste.BalanceAfter)
+
+	product, _ := productRepo.FindProductByID(productID.String())
+	assert.Equal(t, 7, product.Stocks)
+
+	movements, err := repo.FindStockMovementsByProductID(productID.String())
+	require.NoError(t, err)
+	require.Len(t, movements, 2)
+	assert.Equal(t, wasteID, movements[0].ID)
+
+	_, err = repo.FindStockMovementsByProductID("invalid")
+	assert.Error(t, err)
+}
+
+func TestStockMovementRepositoryInMemory_InsertRejectsNegativeStock(t *testing.T) {
+	productRepo := NewProductRepository()
+	repo := NewStockMovementRepository(productRepo)
+
+	productID, _ := uuid.NewV7()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Roti", Stocks: 1})
+
+	_, err := repo.InsertStockMovement(model.StockMovementEntity{ProductID: productID, Type: model.StockMovementWaste, Quantity: -2})
+	assert.Error(t, err)
+
+	_, err = repo.InsertStockMovement(model.StockMovementEntity{ProductID: uuid.New(), Type: model.StockMovementAdjustment, Quantity: 1})
+	assert.Error(t, err)
+}
+
+func TestStockMovementRepositoryInMemory_GetStockReconciliation(t *testing.T) {
+	productRepo := NewProductRepository()
+	repo := NewStockMovementRepository(productRepo)
+
+	productID, _ := uuid.NewV7()
+	// stock that predates the ledger shows up as a difference
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi", Stocks: 5})
+	_, _ = repo.InsertStockMovement(model.StockMovementEntity{ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 4})
+
+	reconciliation, err := repo.GetStockReconciliation(productID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 9, reconciliation.Stock)
+	assert.Equal(t, 4, reconciliation.LedgerBalance)
+
+	_, err = repo.GetStockReconciliation(uuid.New().String())
+	assert.Error(t, err)
+}
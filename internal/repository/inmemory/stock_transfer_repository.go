@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const errStockTransferNotFound = "stock transfer not found"
+
+type StockTransferRepositoryInMemoryImpl struct {
+	transfers         []model.StockTransferEntity
+	outletRepo        repository.OutletRepository
+	productRepo       repository.ProductRepository
+	stockMovementRepo repository.StockMovementRepository
+}
+
+func NewStockTransferRepository(outletRepo repository.OutletRepository, productRepo repository.ProductRepository, stockMovementRepo repository.StockMovementRepository) repository.StockTransferRepository {
+	return &StockTransferRepositoryInMemoryImpl{
+		transfers:         []model.StockTransferEntity{},
+		outletRepo:        outletRepo,
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+	}
+}
+
+func (r *StockTransferRepositoryInMemoryImpl) FindStockTransfers() ([]model.StockTransferEntity, error) {
+	// newest first, like the PostgreSQL implementation
+	var transfers []model.StockTransferEntity
+	for i := len(r.transfers) - 1; i >= 0; i-- {
+		transfers = append(transfers, r.withNames(r.transfers[i]))
+	}
+	return transfers, nil
+}
+
+func (r *StockTransferRepositoryInMemoryImpl) FindStockTransferByID(id string) (model.StockTransferEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.StockTransferEntity{}, err
+	}
+	return r.withNames(r.transfers[i]), nil
+}
+
+func (r *StockTransferRepositoryInMemoryImpl) InsertStockTransfer(transfer model.StockTransferEntity) (model.StockTransferEntity, error) {
+	transfer.Lines = cloneTransferLines(transfer.ID, transfer.Lines)
+	if err := r.moveStock(transfer, transfer.FromOutletID, -1, "dispatched", transfer.CreatedBy); err != nil {
+		return model.StockTransferEntity{}, err
+	}
+
+	transfer.CreatedAt = time.Now()
+	transfer.UpdatedAt = transfer.CreatedAt
+	transfer.Version = 1
+	r.transfers = append(r.transfers, transfer)
+	return r.withNames(transfer), nil
+}
+
+func (r *StockTransferRepositoryInMemoryImpl) ReceiveStockTransfer(id string, actor string) (model.StockTransferEntity, error) {
+	return r.complete(id, model.StockTransferReceived, actor)
+}
+
+func (r *StockTransferRepositoryInMemoryImpl) CancelStockTransfer(id string, actor string) (model.StockTransferEntity, error) {
+	return r.complete(id, model.StockTransferCancelled, actor)
+}
+
+// complete lands the stock in transit, at the destination when received or back at the source when cancelled
+func (r *StockTransferRepositoryInMemoryImpl) complete(id string, toStatus string, actor string) (model.StockTransferEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.StockTransferEntity{}, err
+	}
+	transfer := &r.transfers[i]
+	if transfer.Status != model.StockTransferInTransit {
+		return model.StockTransferEntity{}, fmt.Errorf("stock transfer is not %s", model.StockTransferInTransit)
+	}
+
+	outletID, reason := transfer.ToOutletID, "received"
+	if toStatus == model.StockTransferCancelled {
+		outletID, reason = transfer.FromOutletID, "cancelled"
+	}
+	if err := r.moveStock(*transfer, outletID, 1, reason, actor); err != nil {
+		return model.StockTransferEntity{}, err
+	}
+
+	now := time.Now()
+	transfer.Status = toStatus
+	transfer.UpdatedBy = actor
+	transfer.UpdatedAt = now
+	transfer.Version++
+	if toStatus == model.StockTransferReceived {
+		transfer.ReceivedAt = &now
+		transfer.ReceivedBy = actor
+	} else {
+		transfer.CancelledAt = &now
+	}
+	return r.withNames(*transfer), nil
+}
+
+// moveStock books one transfer movement per line at the outlet, sign -1 takes the stock out
+func (r *StockTransferRepositoryInMemoryImpl) moveStock(transfer model.StockTransferEntity, outletID uuid.UUID, sign int, reason string, actor string) error {
+	for _, line := range transfer.Lines {
+		movementID, _ := uuid.NewV7()
+		_, err := r.stockMovementRepo.InsertStockMovement(model.StockMovementEntity{
+			ID:          movementID,
+			ProductID:   line.ProductID,
+			Type:        model.StockMovementTransfer,
+			Quantity:    sign * line.Quantity,
+			Reason:      "stock transfer " + reason,
+			ReferenceID: &transfer.ID,
+			CreatedBy:   actor,
+			OutletID:    &outletID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *StockTransferRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errStockTransferNotFound)
+	}
+	for i, t := range r.transfers {
+		if t.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errStockTransferNotFound)
+}
+
+func cloneTransferLines(transferID uuid.UUID, lines []model.StockTransferLineEntity) []model.StockTransferLineEntity {
+	cloned := make([]model.StockTransferLineEntity, len(lines))
+	for i, l := range lines {
+		l.StockTransferID = transferID
+		cloned[i] = l
+	}
+	return cloned
+}
+
+// withNames mirrors the JOINs of the PostgreSQL implementation
+func (r *StockTransferRepositoryInMemoryImpl) withNames(transfer model.StockTransferEntity) model.StockTransferEntity {
+	if outlet, err := r.outletRepo.FindOutletByID(transfer.FromOutletID.String()); err == nil {
+		transfer.FromOutletName = outlet.Name
+	}
+	if outlet, err := r.outletRepo.FindOutletByID(transfer.ToOutletID.String()); err == nil {
+		transfer.ToOutletName = outlet.Name
+	}
+
+	transfer.Lines = cloneTransferLines(transfer.ID, transfer.Lines)
+	for i := range transfer.Lines {
+		if product, err := r.productRepo.FindProductByID(transfer.Lines[i].ProductID.String()); err == nil {
+			transfer.Lines[i].ProductName = product.Name
+		}
+	}
+	return transfer
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockTransferRepositoryInMemory_DispatchAndReceive(t *testing.T) {
+	productRepo := NewProductRepository()
+	stockMovementRepo := NewStockMovementRepository(productRepo)
+	outletRepo := NewOutletRepository(productRepo, stockMovementRepo)
+	repo := NewStockTransferRepository(outletRepo, productRepo, stockMovementRepo)
+
+	from, _ := outletRepo.InsertOutlet(model.OutletEntity{ID: uuid.New(), Code: "JKT", Name: "Jakarta", IsActive: true})
+	to, _ := outletRepo.InsertOutlet(model.OutletEntity{ID: uuid.New(), Code: "BDG", Name: "Bandung", IsActive: true})
+	productID := uuid.New()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi"})
+	_, err := stockMovementRepo.InsertStockMovement(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 10, OutletID: &from.ID})
+	require.NoError(t, err)
+
+	outletStock := func(outletID uuid.UUID) int {
+		stock, _ := outletRepo.FindOutletStock(outletID.String(), productID.String())
+		return stock.Stock
+	}
+	newTransfer := func(quantity int) model.StockTransferEntity {
+		return model.StockTransferEntity{ID: uuid.New(), FromOutletID: from.ID, ToOutletID: to.ID, Status: model.StockTransferInTransit,
+			Lines: []model.StockTransferLineEntity{{ProductID: productID, Quantity: quantity}}}
+	}
+
+	transfer, err := repo.InsertStockTransfer(newTransfer(4))
+	require.NoError(t, err)
+	assert.Equal(t, "Jakarta", transfer.FromOutletName)
+	assert.Equal(t, "Kopi", transfer.Lines[0].ProductName)
+	assert.Equal(t, 6, outletStock(from.ID))
+	assert.Equal(t, 0, outletStock(to.ID), "nothing arrives while in transit")
+
+	_, err = repo.InsertStockTransfer(newTransfer(7))
+	assert.Error(t, err, "the source only holds 6")
+
+	received, err := repo.ReceiveStockTransfer(transfer.ID.String(), "USER")
+	require.NoError(t, err)
+	assert.Equal(t, model.StockTransferReceived, received.Status)
+	assert.NotNil(t, received.ReceivedAt)
+	assert.Equal(t, 4, outletStock(to.ID))
+
+	_, err = repo.CancelStockTransfer(transfer.ID.String(), "USER")
+	assert.Error(t, err, "a received transfer cannot be cancelled")
+
+	product, _ := productRepo.FindProductByID(productID.String())
+	assert.Equal(t, 10, product.Stocks, "a completed transfer leaves the total untouched")
+}
+
+func TestStockTransferRepositoryInMemory_Cancel(t *testing.T) {
+	productRepo := NewProductRepository()
+	stockMovementRepo := NewStockMovementRepository(productRepo)
+	outletRepo := NewOutletRepository(productRepo, stockMovementRepo)
+	repo := NewStockTransferRepository(outletRepo, productRepo, stockMovementRepo)
+
+	fromID, toID, productID := uuid.New(), uuid.New(), uuid.New()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi"})
+	_, _ = stockMovementRepo.InsertStockMovement(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 3, OutletID: &fromID})
+
+	transfer, err := repo.InsertStockTransfer(model.StockTransferEntity{ID: uuid.New(), FromOutletID: fromID, ToOutletID: toID, Status: model.StockTransferInTransit,
+		Lines: []model.StockTransferLineEntity{{ProductID: productID, Quantity: 3}}})
+	require.NoError(t, err)
+
+	cancelled, err := repo.CancelStockTransfer(transfer.ID.String(), "USER")
+	require.NoError(t, err)
+	assert.Equal(t, model.StockTransferCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.CancelledAt)
+
+	stock, _ := outletRepo.FindOutletStock(fromID.String(), productID.String())
+	assert.Equal(t, 3, stock.Stock)
+
+	transfers, _ := repo.FindStockTransfers()
+	assert.Len(t, transfers, 1)
+}
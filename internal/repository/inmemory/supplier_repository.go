@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+const errSupplierNotFound = "supplier not found"
+
+type SupplierRepositoryInMemoryImpl struct {
+	suppliers []model.SupplierEntity
+}
+
+func NewSupplierRepository() repository.SupplierRepository {
+	return &SupplierRepositoryInMemoryImpl{
+		suppliers: []model.SupplierEntity{},
+	}
+}
+
+func (r *SupplierRepositoryInMemoryImpl) FindSuppliers() ([]model.SupplierEntity, error) {
+	var suppliers []model.SupplierEntity
+	for _, s := range r.suppliers {
+		if s.DeletedAt == nil {
+			suppliers = append(suppliers, s)
+		}
+	}
+	return suppliers, nil
+}
+
+func (r *SupplierRepositoryInMemoryImpl) FindSupplierByID(id string) (model.SupplierEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.SupplierEntity{}, err
+	}
+	return r.suppliers[i], nil
+}
+
+func (r *SupplierRepositoryInMemoryImpl) InsertSupplier(supplier model.SupplierEntity) (model.SupplierEntity, error) {
+	supplier.CreatedAt = time.Now()
+	supplier.UpdatedAt = supplier.CreatedAt
+	supplier.Version = 1
+	r.suppliers = append(r.suppliers, supplier)
+	return supplier, nil
+}
+
+func (r *SupplierRepositoryInMemoryImpl) UpdateSupplierByID(id string, supplier model.SupplierEntity) (model.SupplierEntity, error) {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return model.SupplierEntity{}, err
+	}
+
+	existing := r.suppliers[i]
+	supplier.ID = existing.ID
+	supplier.CreatedAt = existing.CreatedAt
+	supplier.CreatedBy = existing.CreatedBy
+	supplier.UpdatedAt = time.Now()
+	supplier.Version = existing.Version + 1
+	r.suppliers[i] = supplier
+	return supplier, nil
+}
+
+func (r *SupplierRepositoryInMemoryImpl) DeleteSupplierByID(id string) error {
+	i, err := r.indexOf(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r.suppliers[i].DeletedAt = &now
+	return nil
+}
+
+func (r *SupplierRepositoryInMemoryImpl) indexOf(id string) (int, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return -1, errors.New(errSupplierNotFound)
+	}
+	for i, s := range r.suppliers {
+		if s.ID == parsedID {
+			return i, nil
+		}
+	}
+	return -1, errors.New(errSupplierNotFound)
+}
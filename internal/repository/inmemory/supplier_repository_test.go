@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"testing"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemorySupplierRepository_CRUD(t *testing.T) {
+	repo := NewSupplierRepository()
+
+	id := uuid.New()
+	inserted, err := repo.InsertSupplier(model.SupplierEntity{ID: id, Name: "PT Sumber Makmur"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, inserted.Version)
+
+	updated, err := repo.UpdateSupplierByID(id.String(), model.SupplierEntity{Name: "PT Sumber Makmur Abadi"})
+	require.NoError(t, err)
+	assert.Equal(t, id, updated.ID)
+	assert.Equal(t, 2, updated.Version)
+
+	found, err := repo.FindSupplierByID(id.String())
+	require.NoError(t, err)
+	assert.Equal(t, "PT Sumber Makmur Abadi", found.Name)
+
+	require.NoError(t, repo.DeleteSupplierByID(id.String()))
+	suppliers, err := repo.FindSuppliers()
+	require.NoError(t, err)
+	assert.Empty(t, suppliers)
+
+	_, err = repo.FindSupplierByID("invalid")
+	assert.Error(t, err)
+}
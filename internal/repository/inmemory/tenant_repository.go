@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+)
+
+const errTenantNotFound = "tenant not found"
+
+type TenantRepositoryInMemoryImpl struct {
+	tenants []model.TenantEntity
+}
+
+// NewTenantRepository returns a repository holding the given tenants, tenants are provisioned outside the API
+func NewTenantRepository(tenants ...model.TenantEntity) repository.TenantRepository {
+	return &TenantRepositoryInMemoryImpl{
+		tenants: append([]model.TenantEntity{}, tenants...),
+	}
+}
+
+func (r *TenantRepositoryInMemoryImpl) FindTenantBySlug(slug string) (model.TenantEntity, error) {
+	for _, t := range r.tenants {
+		if t.Slug == slug && t.DeletedAt == nil {
+			return t, nil
+		}
+	}
+	return model.TenantEntity{}, errors.New(errTenantNotFound)
+}
+
+func (r *TenantRepositoryInMemoryImpl) FindTenantByAPITokenHash(hash string) (model.TenantEntity, error) {
+	for _, t := range r.tenants {
+		if hash != "" && t.APITokenHash == hash && t.DeletedAt == nil {
+			return t, nil
+		}
+	}
+	return model.TenantEntity{}, errors.New(errTenantNotFound)
+}
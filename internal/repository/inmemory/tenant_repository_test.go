@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryTenantRepository_Find(t *testing.T) {
+	deletedAt := time.Now()
+	shopID := uuid.New()
+	repo := NewTenantRepository(
+		model.TenantEntity{ID: shopID, Slug: "toko-a", APITokenHash: model.HashAPIToken("token-a"), IsActive: true},
+		model.TenantEntity{ID: uuid.New(), Slug: "toko-b", DeletedAt: &deletedAt},
+	)
+
+	bySlug, err := repo.FindTenantBySlug("toko-a")
+	require.NoError(t, err)
+	assert.Equal(t, shopID, bySlug.ID)
+
+	byToken, err := repo.FindTenantByAPITokenHash(model.HashAPIToken("token-a"))
+	require.NoError(t, err)
+	assert.Equal(t, shopID, byToken.ID)
+
+	_, err = repo.FindTenantBySlug("toko-b")
+	assert.Error(t, err)
+
+	_, err = repo.FindTenantByAPITokenHash("")
+	assert.Error(t, err)
+}
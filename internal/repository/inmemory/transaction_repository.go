@@ -1,15 +1,18 @@
 package repository
 
 import (
+	"sort"
 	"time"
 
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
 )
 
 type TransactionRepositoryInMemoryImpl struct {
 	transactions []model.TransactionEntity
 	details      []model.TransactionDetailEntity
+	conflicts    []model.StockConflictEntity
 	productRepo  repository.ProductRepository
 }
 
@@ -22,8 +25,24 @@ func NewTransactionRepository(productRepo repository.ProductRepository) reposito
 }
 
 func (r *TransactionRepositoryInMemoryImpl) CreateTransaction(tx model.TransactionEntity, details []model.TransactionDetailEntity) (model.TransactionEntity, error) {
+	for i, c := range tx.StockConflicts {
+		p, _ := r.productRepo.FindProductByID(c.ProductID.String())
+		p.Stocks += c.Quantity
+		p.UpdatedAt = time.Now()
+		_, _ = r.productRepo.UpdateProductByID(p.ID.String(), p)
+
+		tx.StockConflicts[i].CreatedAt = time.Now()
+		r.conflicts = append(r.conflicts, tx.StockConflicts[i])
+	}
 	for _, d := range details {
-		if d.ProductID != nil {
+		if len(d.Components) > 0 {
+			for _, c := range d.Components {
+				p, _ := r.productRepo.FindProductByID(c.ComponentID.String())
+				p.Stocks -= d.Quantity * c.Quantity
+				p.UpdatedAt = time.Now()
+				_, _ = r.productRepo.UpdateProductByID(p.ID.String(), p)
+			}
+		} else if d.ProductID != nil {
 			p, _ := r.productRepo.FindProductByID(d.ProductID.String())
 			p.Stocks -= d.Quantity
 			p.UpdatedAt = time.Now()
@@ -37,10 +56,13 @@ func (r *TransactionRepositoryInMemoryImpl) CreateTransaction(tx model.Transacti
 	return tx, nil
 }
 
-func (r *TransactionRepositoryInMemoryImpl) GetReportStats(startDate, endDate time.Time) (model.ReportResponse, error) {
+func (r *TransactionRepositoryInMemoryImpl) GetReportStats(startDate, endDate time.Time, outletID *uuid.UUID) (model.ReportResponse, error) {
 	var totalRevenue int64
 	var totalTransactions int
 	for _, tx := range r.transactions {
+		if !atOutlet(tx, outletID) {
+			continue
+		}
 		if (tx.CreatedAt.After(startDate) || tx.CreatedAt.Equal(startDate)) &&
 			(tx.CreatedAt.Before(endDate) || tx.CreatedAt.Equal(endDate)) {
 			totalRevenue += tx.TotalPriceAmount
@@ -57,10 +79,89 @@ func (r *TransactionRepositoryInMemoryImpl) GetReportStats(startDate, endDate ti
 	}, nil
 }
 
-func (r *TransactionRepositoryInMemoryImpl) GetMostPopularCategory(startDate, endDate time.Time) (model.PopularCategory, error) {
+func (r *TransactionRepositoryInMemoryImpl) GetSalesMargins(startDate, endDate time.Time, outletID *uuid.UUID) ([]model.SalesMarginEntity, error) {
+	inRange := map[uuid.UUID]bool{}
+	for _, tx := range r.transactions {
+		if atOutlet(tx, outletID) && !tx.CreatedAt.Before(startDate) && !tx.CreatedAt.After(endDate) {
+			inRange[tx.ID] = true
+		}
+	}
+
+	var margins []model.SalesMarginEntity
+	index := map[string]int{}
+	for _, d := range r.details {
+		if !inRange[d.TransactionID] {
+			continue
+		}
+		key := d.ProductName
+		if d.ProductID != nil {
+			key = d.ProductID.String()
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(margins)
+			index[key] = i
+			margins = append(margins, model.SalesMarginEntity{
+				ProductID:    d.ProductID,
+				ProductName:  d.ProductName,
+				CategoryID:   d.CategoryID,
+				CategoryName: d.CategoryName,
+			})
+		}
+		margins[i].Quantity += model.FromStockQuantity(d.Quantity, model.QuantityMultiplier(d.QuantityScale))
+		margins[i].Revenue += d.TotalPriceAmount
+		margins[i].COGS += d.TotalCostAmount
+	}
+	return margins, nil
+}
+
+// atOutlet reports whether the transaction belongs to the outlet, a nil outlet matches every transaction
+func atOutlet(tx model.TransactionEntity, outletID *uuid.UUID) bool {
+	return outletID == nil || (tx.OutletID != nil && *tx.OutletID == *outletID)
+}
+
+func (r *TransactionRepositoryInMemoryImpl) GetMostPopularCategory(startDate, endDate time.Time, outletID *uuid.UUID, level *int) (model.PopularCategory, error) {
 	return model.PopularCategory{}, nil
 }
 
-func (r *TransactionRepositoryInMemoryImpl) GetMostPopularProduct(startDate, endDate time.Time) (model.PopularItem, error) {
+func (r *TransactionRepositoryInMemoryImpl) GetMostPopularProduct(startDate, endDate time.Time, outletID *uuid.UUID) (model.PopularItem, error) {
 	return model.PopularItem{}, nil
 }
+
+func (r *TransactionRepositoryInMemoryImpl) FindTransactionsByCustomer(customerID string) ([]model.TransactionEntity, error) {
+	var transactions []model.TransactionEntity
+	for i := len(r.transactions) - 1; i >= 0; i-- {
+		tx := r.transactions[i]
+		if tx.CustomerID != nil && tx.CustomerID.String() == customerID && tx.DeletedAt == nil {
+			transactions = append(transactions, tx)
+		}
+	}
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+	})
+	return transactions, nil
+}
+
+func (r *TransactionRepositoryInMemoryImpl) FindTransactionByID(id string) (model.TransactionEntity, error) {
+	for _, tx := range r.transactions {
+		if tx.ID.String() == id {
+			return tx, nil
+		}
+	}
+	return model.TransactionEntity{}, nil
+}
+
+func (r *TransactionRepositoryInMemoryImpl) GetShiftSales(shiftID string) (model.ShiftSalesEntity, error) {
+	var sales model.ShiftSalesEntity
+	for _, tx := range r.transactions {
+		if tx.ShiftID == nil || tx.ShiftID.String() != shiftID || tx.DeletedAt != nil {
+			continue
+		}
+		sales.TransactionCount++
+		sales.ItemsSold += tx.TotalItems
+		sales.NetSales += tx.TotalPriceAmount
+		sales.PointsDiscount += tx.PointsDiscountAmount
+		sales.GiftCardAmount += tx.GiftCardAmount
+	}
+	return sales, nil
+}
@@ -4,6 +4,7 @@ import (
 	"codewithumam-kasir-api/internal/model"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 	"time"
 )
@@ -53,15 +54,165 @@ func TestTransactionRepositoryInMemory_GetReports(t *testing.T) {
 	txRepo := NewTransactionRepository(productRepo)
 
 	// Currently returns empty/nil as implemented
-	resp, err := txRepo.GetReportStats(time.Now(), time.Now())
+	resp, err := txRepo.GetReportStats(time.Now(), time.Now(), nil)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(0), resp.TotalRevenue.Amount)
 
-	cat, err := txRepo.GetMostPopularCategory(time.Now(), time.Now())
+	cat, err := txRepo.GetMostPopularCategory(time.Now(), time.Now(), nil, nil)
 	assert.NoError(t, err)
 	assert.Empty(t, cat.Name)
 
-	prod, err := txRepo.GetMostPopularProduct(time.Now(), time.Now())
+	prod, err := txRepo.GetMostPopularProduct(time.Now(), time.Now(), nil)
 	assert.NoError(t, err)
 	assert.Empty(t, prod.Name)
 }
+
+func TestTransactionRepositoryInMemory_CreateTransaction_Bundle(t *testing.T) {
+	productRepo := NewProductRepository()
+	txRepo := NewTransactionRepository(productRepo)
+
+	riceID, _ := uuid.NewV7()
+	teaID, _ := uuid.NewV7()
+	bundleID, _ := uuid.NewV7()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: riceID, Name: "Nasi", Price: 5000, Stocks: 10})
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: teaID, Name: "Es Teh", Price: 3000, Stocks: 10})
+	_, _ = productRepo.InsertProduct(model.ProductEntity{
+		ID:            bundleID,
+		Name:          "Paket Hemat",
+		Type:          model.ProductTypeBundle,
+		BundlePricing: model.BundlePricingDerived,
+		Components: []model.BundleComponentEntity{
+			{ComponentID: riceID, Quantity: 2},
+			{ComponentID: teaID, Quantity: 1},
+		},
+	})
+
+	bundle, err := productRepo.FindProductByID(bundleID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, bundle.AvailableStocks())
+	assert.Equal(t, int64(13000), bundle.EffectivePrice())
+
+	txID, _ := uuid.NewV7()
+	_, err = txRepo.CreateTransaction(model.TransactionEntity{ID: txID}, []model.TransactionDetailEntity{
+		{TransactionID: txID, ProductID: &bundleID, Quantity: 3, Components: bundle.Components},
+	})
+	assert.NoError(t, err)
+
+	rice, _ := productRepo.FindProductByID(riceID.String())
+	tea, _ := productRepo.FindProductByID(teaID.String())
+	assert.Equal(t, 4, rice.Stocks)
+	assert.Equal(t, 7, tea.Stocks)
+}
+
+func TestTransactionRepositoryInMemory_GetSalesMargins(t *testing.T) {
+	productRepo := NewProductRepository()
+	txRepo := NewTransactionRepository(productRepo)
+
+	productID, _ := uuid.NewV7()
+	_, _ = productRepo.InsertProduct(model.ProductEntity{ID: productID, Name: "Kopi", Stocks: 10})
+
+	sell := func(createdAt time.Time, quantity int) {
+		txID, _ := uuid.NewV7()
+		_, _ = txRepo.CreateTransaction(
+			model.TransactionEntity{ID: txID, CreatedAt: createdAt},
+			[]model.TransactionDetailEntity{{
+				TransactionID:    txID,
+				ProductID:        &productID,
+				ProductName:      "Kopi",
+				CategoryName:     "Minuman",
+				Quantity:         quantity,
+				TotalPriceAmount: int64(quantity) * 10000,
+				TotalCostAmount:  int64(quantity) * 4000,
+			}},
+		)
+	}
+	now := time.Now()
+	sell(now, 2)
+	sell(now, 1)
+	sell(now.AddDate(0, 0, -10), 5)
+
+	margins, err := txRepo.GetSalesMargins(now.Add(-time.Hour), now.Add(time.Hour), nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, margins, 1)
+	assert.Equal(t, 3.0, margins[0].Quantity)
+	assert.Equal(t, int64(30000), margins[0].Revenue)
+	assert.Equal(t, int64(12000), margins[0].COGS)
+}
+
+func TestTransactionRepositoryInMemory_GetSalesMargins_CountsBaseUnits(t *testing.T) {
+	txRepo := NewTransactionRepository(NewProductRepository())
+	riceID := uuid.New()
+	for _, grams := range []int{750, 1500} {
+		txID := uuid.New()
+		_, _ = txRepo.CreateTransaction(
+			model.TransactionEntity{ID: txID, CreatedAt: time.Now()},
+			[]model.TransactionDetailEntity{{
+				TransactionID: txID, ProductID: &riceID, ProductName: "Beras",
+				Quantity: grams, QuantityScale: 3, TotalPriceAmount: int64(grams) * 15,
+			}},
+		)
+	}
+
+	margins, err := txRepo.GetSalesMargins(time.Now().Add(-time.Hour), time.Now().Add(time.Hour), nil)
+	require.NoError(t, err)
+	require.Len(t, margins, 1)
+	assert.Equal(t, 2.25, margins[0].Quantity, "kilos sold, not grams")
+}
+
+func TestTransactionRepositoryInMemory_ReportsByOutlet(t *testing.T) {
+	productRepo := NewProductRepository()
+	txRepo := NewTransactionRepository(productRepo)
+
+	outletID, otherOutletID := uuid.New(), uuid.New()
+	now := time.Now()
+	for _, tx := range []model.TransactionEntity{
+		{ID: uuid.New(), CreatedAt: now, TotalPriceAmount: 10000, OutletID: &outletID},
+		{ID: uuid.New(), CreatedAt: now, TotalPriceAmount: 5000, OutletID: &otherOutletID},
+		{ID: uuid.New(), CreatedAt: now, TotalPriceAmount: 2000},
+	} {
+		_, _ = txRepo.CreateTransaction(tx, nil)
+	}
+
+	all, err := txRepo.GetReportStats(now.Add(-time.Hour), now.Add(time.Hour), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, all.TotalTransactions)
+
+	atOutlet, err := txRepo.GetReportStats(now.Add(-time.Hour), now.Add(time.Hour), &outletID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, atOutlet.TotalTransactions)
+	assert.Equal(t, int64(10000), atOutlet.TotalRevenue.Amount)
+}
+
+func TestTransactionRepositoryInMemory_FindTransactionsByCustomer(t *testing.T) {
+	txRepo := NewTransactionRepository(NewProductRepository())
+
+	customerID := uuid.New()
+	now := time.Now()
+	older := model.TransactionEntity{ID: uuid.New(), CreatedAt: now.Add(-time.Hour), CustomerID: &customerID}
+	newer := model.TransactionEntity{ID: uuid.New(), CreatedAt: now, CustomerID: &customerID}
+	for _, tx := range []model.TransactionEntity{older, {ID: uuid.New(), CreatedAt: now}, newer} {
+		_, _ = txRepo.CreateTransaction(tx, nil)
+	}
+
+	transactions, err := txRepo.FindTransactionsByCustomer(customerID.String())
+
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, newer.ID, transactions[0].ID)
+	assert.Equal(t, older.ID, transactions[1].ID)
+}
+
+func TestTransactionRepositoryInMemory_FindTransactionByID(t *testing.T) {
+	txRepo := NewTransactionRepository(NewProductRepository())
+	txID, _ := uuid.NewV7()
+	_, _ = txRepo.CreateTransaction(model.TransactionEntity{ID: txID, TotalItems: 1}, nil)
+
+	tx, err := txRepo.FindTransactionByID(txID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, txID, tx.ID)
+
+	missing, err := txRepo.FindTransactionByID(uuid.NewString())
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Nil, missing.ID)
+}
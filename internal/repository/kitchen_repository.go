@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
+)
+
+type KitchenRepository interface {
+	FindKitchenStations() ([]model.KitchenStationEntity, error)
+	FindKitchenStationByID(id string) (model.KitchenStationEntity, error)
+	// InsertKitchenStation routes the station's categories to it, taking them from the station preparing them before
+	InsertKitchenStation(station model.KitchenStationEntity) (model.KitchenStationEntity, error)
+	// UpdateKitchenStationByID replaces the station's categories like InsertKitchenStation routes them
+	UpdateKitchenStationByID(id string, station model.KitchenStationEntity) (model.KitchenStationEntity, error)
+	// FindStationsByCategory maps each routed category to the station preparing it, inactive stations are left out
+	FindStationsByCategory() (map[uuid.UUID]uuid.UUID, error)
+	// FindKitchenTickets lists the tickets oldest first, of every station when stationID is empty
+	// and the ones not served yet when status is empty
+	FindKitchenTickets(stationID, status string) ([]model.KitchenTicketEntity, error)
+	FindKitchenTicketByID(id string) (model.KitchenTicketEntity, error)
+	// FireKitchenTickets inserts the tickets and marks the draft's lines fired up to their quantity.
+	// It fails when the draft is no longer open or held or its lines changed since it was read.
+	FireKitchenTickets(draft model.DraftOrderEntity, tickets []model.KitchenTicketEntity) ([]model.KitchenTicketEntity, error)
+	// UpdateKitchenTicketStatus saves the ticket's status and stamps when it got there,
+	// failing when the ticket's version moved since it was read
+	UpdateKitchenTicketStatus(ticket model.KitchenTicketEntity) (model.KitchenTicketEntity, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
+)
+
+type LotRepository interface {
+	FindLotsByProductID(productID string) ([]model.ProductLotEntity, error)
+	// FindOpenLotsByProductID returns the lots with stock left at the outlet, or held at no outlet when outletID is nil,
+	// earliest expiry first
+	FindOpenLotsByProductID(productID string, outletID *uuid.UUID) ([]model.ProductLotEntity, error)
+	// FindExpiringLots returns the lots with stock left expiring on or before until, expired ones included
+	FindExpiringLots(until time.Time) ([]model.ProductLotEntity, error)
+	// InsertLot registers the lot and books its quantity into stock as a purchase receipt
+	InsertLot(lot model.ProductLotEntity) (model.ProductLotEntity, error)
+}
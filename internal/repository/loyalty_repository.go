@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type LoyaltyRepository interface {
+	// FindLoyaltyProgram returns the zero program, which is inactive, when the shop never set one up
+	FindLoyaltyProgram() (model.LoyaltyProgramEntity, error)
+	SaveLoyaltyProgram(program model.LoyaltyProgramEntity) (model.LoyaltyProgramEntity, error)
+	// FindLoyaltyEntries returns the customer's ledger oldest first
+	FindLoyaltyEntries(customerID string) ([]model.LoyaltyEntryEntity, error)
+	// InsertLoyaltyEntries books the entries together, failing them all when one would take the balance below zero
+	InsertLoyaltyEntries(entries []model.LoyaltyEntryEntity) ([]model.LoyaltyEntryEntity, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type OutletRepository interface {
+	FindOutlets() ([]model.OutletEntity, error)
+	FindOutletByID(id string) (model.OutletEntity, error)
+	InsertOutlet(outlet model.OutletEntity) (model.OutletEntity, error)
+	UpdateOutletByID(id string, outlet model.OutletEntity) (model.OutletEntity, error)
+	DeleteOutletByID(id string) error
+	// FindOutletStocks lists every active product with the outlet's stock and price
+	FindOutletStocks(outletID string) ([]model.OutletStockEntity, error)
+	// FindOutletStock returns zero stock and no override for a product the outlet never held
+	FindOutletStock(outletID, productID string) (model.OutletStockEntity, error)
+	UpdateOutletPrice(outletID, productID string, priceOverride *int64) (model.OutletStockEntity, error)
+	// FindStockHeldAtOutlets sums what all outlets hold of the product, the rest of its stock is held at no outlet
+	FindStockHeldAtOutlets(productID string) (int, error)
+}
@@ -5,15 +5,14 @@ import (
 	"codewithumam-kasir-api/internal/repository"
 	"context"
 	"fmt"
-
-	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
 )
 
 type CategoryRepositoryPostgreSQLImpl struct {
-	connPool *pgxpool.Pool
+	connPool DB
 }
 
-func NewCategoryRepository(connPool *pgxpool.Pool) repository.CategoryRepository {
+func NewCategoryRepository(connPool DB) repository.CategoryRepository {
 	return &CategoryRepositoryPostgreSQLImpl{
 		connPool: connPool,
 	}
@@ -21,7 +20,7 @@ func NewCategoryRepository(connPool *pgxpool.Pool) repository.CategoryRepository
 
 func (r *CategoryRepositoryPostgreSQLImpl) FindCategories() ([]model.CategoryEntity, error) {
 	var categories []model.CategoryEntity
-	rows, err := r.connPool.Query(context.Background(), "SELECT id, name, description, created_at, updated_at, deleted_at FROM core.category WHERE deleted_at IS NULL")
+	rows, err := r.connPool.Query(context.Background(), "SELECT id, parent_id, name, description, created_at, updated_at, deleted_at FROM core.category WHERE deleted_at IS NULL")
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
@@ -30,7 +29,7 @@ func (r *CategoryRepositoryPostgreSQLImpl) FindCategories() ([]model.CategoryEnt
 
 	for rows.Next() {
 		var category model.CategoryEntity
-		if err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt); err != nil {
+		if err := rows.Scan(&category.ID, &category.ParentID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt); err != nil {
 			fmt.Println(err)
 			return nil, err
 		}
@@ -42,7 +41,7 @@ func (r *CategoryRepositoryPostgreSQLImpl) FindCategories() ([]model.CategoryEnt
 
 func (r *CategoryRepositoryPostgreSQLImpl) FindCategoryByID(id string) (model.CategoryEntity, error) {
 	var category model.CategoryEntity
-	err := r.connPool.QueryRow(context.Background(), "SELECT id, name, description, created_at, updated_at, deleted_at, version FROM core.category WHERE id = $1", id).Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt, &category.Version)
+	err := r.connPool.QueryRow(context.Background(), "SELECT id, parent_id, name, description, created_at, updated_at, deleted_at, version FROM core.category WHERE id = $1", id).Scan(&category.ID, &category.ParentID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt, &category.Version)
 	if err != nil {
 		fmt.Println(err)
 		return model.CategoryEntity{}, err
@@ -52,7 +51,7 @@ func (r *CategoryRepositoryPostgreSQLImpl) FindCategoryByID(id string) (model.Ca
 
 func (r *CategoryRepositoryPostgreSQLImpl) FindCategoryByName(name string) (model.CategoryEntity, error) {
 	var category model.CategoryEntity
-	err := r.connPool.QueryRow(context.Background(), "SELECT id, name, description, created_at, updated_at, deleted_at, version FROM core.category WHERE name = $1 AND deleted_at IS NULL", name).Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt, &category.Version)
+	err := r.connPool.QueryRow(context.Background(), "SELECT id, parent_id, name, description, created_at, updated_at, deleted_at, version FROM core.category WHERE name = $1 AND deleted_at IS NULL", name).Scan(&category.ID, &category.ParentID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt, &category.Version)
 	if err != nil {
 		fmt.Println(err)
 		return model.CategoryEntity{}, err
@@ -61,7 +60,7 @@ func (r *CategoryRepositoryPostgreSQLImpl) FindCategoryByName(name string) (mode
 }
 
 func (r *CategoryRepositoryPostgreSQLImpl) InsertCategory(category model.CategoryEntity) (model.CategoryEntity, error) {
-	_, err := r.connPool.Exec(context.Background(), "INSERT INTO core.category (id, name, description, created_by, updated_by) VALUES ($1, $2, $3, $4, $5)", category.ID, category.Name, category.Description, category.CreatedBy, category.UpdatedBy)
+	_, err := r.connPool.Exec(context.Background(), "INSERT INTO core.category (id, parent_id, name, description, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6)", category.ID, category.ParentID, category.Name, category.Description, category.CreatedBy, category.UpdatedBy)
 	if err != nil {
 		fmt.Println(err)
 		return model.CategoryEntity{}, err
@@ -78,7 +77,7 @@ func (r *CategoryRepositoryPostgreSQLImpl) InsertCategory(category model.Categor
 }
 
 func (r *CategoryRepositoryPostgreSQLImpl) UpdateCategoryByID(id string, category model.CategoryEntity) (model.CategoryEntity, error) {
-	_, err := r.connPool.Exec(context.Background(), "UPDATE core.category SET name = $1, description = $2, updated_by = $3	 WHERE id = $4 AND version = $5", category.Name, category.Description, category.UpdatedBy, id, category.Version)
+	_, err := r.connPool.Exec(context.Background(), "UPDATE core.category SET parent_id = $1, name = $2, description = $3, updated_by = $4 WHERE id = $5 AND version = $6", category.ParentID, category.Name, category.Description, category.UpdatedBy, id, category.Version)
 	if err != nil {
 		fmt.Println(err)
 		return model.CategoryEntity{}, err
@@ -102,3 +101,30 @@ func (r *CategoryRepositoryPostgreSQLImpl) DeleteCategoryByID(id string) error {
 	}
 	return nil
 }
+
+func (r *CategoryRepositoryPostgreSQLImpl) FindCategoriesChangedSince(since uint64) ([]model.CategoryEntity, error) {
+	var categories []model.CategoryEntity
+	query := `
+		SELECT id, parent_id, name, description, created_at, updated_at, deleted_at, version, change_xid::text::numeric::bigint
+		FROM core.category
+		WHERE change_xid >= $1::text::xid8
+		ORDER BY change_xid, id
+	`
+	rows, err := r.connPool.Query(context.Background(), query, strconv.FormatUint(since, 10))
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category model.CategoryEntity
+		if err := rows.Scan(&category.ID, &category.ParentID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt, &category.Version, &category.ChangeSeq); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+)
+
+type CustomerRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewCustomerRepository(connPool DB) repository.CustomerRepository {
+	return &CustomerRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const customerColumns = `
+	id, version, created_at, created_by, updated_at, updated_by, deleted_at,
+	name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(notes, ''), points_balance, price_list_id
+`
+
+func (r *CustomerRepositoryPostgreSQLImpl) FindCustomers() ([]model.CustomerEntity, error) {
+	query := `SELECT ` + customerColumns + ` FROM core.customer WHERE deleted_at IS NULL ORDER BY name`
+	return r.findCustomers(query)
+}
+
+func (r *CustomerRepositoryPostgreSQLImpl) FindCustomersByPhone(phone string) ([]model.CustomerEntity, error) {
+	query := `SELECT ` + customerColumns + ` FROM core.customer WHERE phone = $1 AND deleted_at IS NULL`
+	return r.findCustomers(query, phone)
+}
+
+func (r *CustomerRepositoryPostgreSQLImpl) findCustomers(query string, args ...any) ([]model.CustomerEntity, error) {
+	var customers []model.CustomerEntity
+	rows, err := r.connPool.Query(context.Background(), query, args...)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c model.CustomerEntity
+		if err := rows.Scan(
+			&c.ID, &c.Version, &c.CreatedAt, &c.CreatedBy, &c.UpdatedAt, &c.UpdatedBy, &c.DeletedAt,
+			&c.Name, &c.Phone, &c.Email, &c.Notes, &c.PointsBalance, &c.PriceListID,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, nil
+}
+
+func (r *CustomerRepositoryPostgreSQLImpl) FindCustomerByID(id string) (model.CustomerEntity, error) {
+	var c model.CustomerEntity
+	query := `SELECT ` + customerColumns + ` FROM core.customer WHERE id = $1`
+	err := r.connPool.QueryRow(context.Background(), query, id).Scan(
+		&c.ID, &c.Version, &c.CreatedAt, &c.CreatedBy, &c.UpdatedAt, &c.UpdatedBy, &c.DeletedAt,
+		&c.Name, &c.Phone, &c.Email, &c.Notes, &c.PointsBalance, &c.PriceListID,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.CustomerEntity{}, err
+	}
+	return c, nil
+}
+
+func (r *CustomerRepositoryPostgreSQLImpl) InsertCustomer(customer model.CustomerEntity) (model.CustomerEntity, error) {
+	query := `
+		INSERT INTO core.customer (
+			id, name, phone, email, notes, created_by, updated_by, price_list_id
+		) VALUES (
+			$1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8
+		)
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		customer.ID, customer.Name, customer.Phone, customer.Email, customer.Notes,
+		customer.CreatedBy, customer.UpdatedBy, customer.PriceListID,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.CustomerEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindCustomerByID(customer.ID.String())
+}
+
+func (r *CustomerRepositoryPostgreSQLImpl) UpdateCustomerByID(id string, customer model.CustomerEntity) (model.CustomerEntity, error) {
+	query := `
+		UPDATE core.customer
+		SET name = $1, phone = NULLIF($2, ''), email = NULLIF($3, ''), notes = NULLIF($4, ''), updated_by = $5, price_list_id = $8
+		WHERE id = $6 AND version = $7 AND deleted_at IS NULL
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		customer.Name, customer.Phone, customer.Email, customer.Notes, customer.UpdatedBy,
+		id, customer.Version, customer.PriceListID,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.CustomerEntity{}, err
+	}
+	return r.FindCustomerByID(id)
+}
+
+func (r *CustomerRepositoryPostgreSQLImpl) DeleteCustomerByID(id string) error {
+	_, err := r.connPool.Exec(context.Background(), "UPDATE core.customer SET deleted_at = NOW(), updated_at = NOW(), updated_by = $1 WHERE id = $2", "USER", id)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	return nil
+}
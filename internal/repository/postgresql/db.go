@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"codewithumam-kasir-api/internal/tenant"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DB is the part of *pgxpool.Pool the repositories use, so they can be handed a tenant-scoped pool
+type DB interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ConfigureTenant makes every connection taken from the pool carry app.current_tenant_id,
+// which the row-level security policies of data/schema_tenant.sql read.
+// A context without a tenant clears the setting so a reused connection never leaks the previous one.
+func ConfigureTenant(cfg *pgxpool.Config) {
+	cfg.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		var tenantID string
+		if id, ok := tenant.IDFromContext(ctx); ok {
+			tenantID = id.String()
+		}
+		if _, err := conn.Exec(ctx, `SELECT set_config('app.current_tenant_id', $1, false)`, tenantID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+type tenantDB struct {
+	pool     *pgxpool.Pool
+	tenantID uuid.UUID
+}
+
+// ForTenant returns a DB whose connections are scoped to the tenant, the pool must be configured with ConfigureTenant
+func ForTenant(pool *pgxpool.Pool, tenantID uuid.UUID) DB {
+	return &tenantDB{
+		pool:     pool,
+		tenantID: tenantID,
+	}
+}
+
+func (db *tenantDB) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	return db.pool.Exec(tenant.WithID(ctx, db.tenantID), sql, arguments...)
+}
+
+func (db *tenantDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return db.pool.Query(tenant.WithID(ctx, db.tenantID), sql, args...)
+}
+
+func (db *tenantDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return db.pool.QueryRow(tenant.WithID(ctx, db.tenantID), sql, args...)
+}
+
+func (db *tenantDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return db.pool.Begin(tenant.WithID(ctx, db.tenantID))
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DiningTableRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewDiningTableRepository(connPool DB) repository.DiningTableRepository {
+	return &DiningTableRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+// diningTableSelect joins the draft order seated at the table, idx_draft_order_dining_table keeps it to one
+const diningTableSelect = `
+	SELECT
+		t.id, t.version, t.created_at, t.created_by, t.updated_at, t.updated_by,
+		t.outlet_id, t.area, t.name, t.seats, t.is_active, d.id
+	FROM core.dining_table t
+	LEFT JOIN core.draft_order d ON d.table_id = t.id AND d.status IN ('open', 'held')
+`
+
+func scanDiningTable(row pgx.Row) (model.DiningTableEntity, error) {
+	var t model.DiningTableEntity
+	err := row.Scan(
+		&t.ID, &t.Version, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy,
+		&t.OutletID, &t.Area, &t.Name, &t.Seats, &t.IsActive, &t.DraftOrderID,
+	)
+	return t, err
+}
+
+func (r *DiningTableRepositoryPostgreSQLImpl) FindDiningTables(outletID *uuid.UUID) ([]model.DiningTableEntity, error) {
+	var tables []model.DiningTableEntity
+	query := diningTableSelect + `
+		WHERE $1::uuid IS NULL OR t.outlet_id = $1
+		ORDER BY t.area, t.name
+	`
+	rows, err := r.connPool.Query(context.Background(), query, outletID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanDiningTable(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+func (r *DiningTableRepositoryPostgreSQLImpl) FindDiningTableByID(id string) (model.DiningTableEntity, error) {
+	t, err := scanDiningTable(r.connPool.QueryRow(context.Background(), diningTableSelect+` WHERE t.id = $1`, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.DiningTableEntity{}, err
+	}
+	return t, nil
+}
+
+func (r *DiningTableRepositoryPostgreSQLImpl) InsertDiningTable(table model.DiningTableEntity) (model.DiningTableEntity, error) {
+	query := `
+		INSERT INTO core.dining_table (id, outlet_id, area, name, seats, is_active, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		table.ID, table.OutletID, table.Area, table.Name, table.Seats, table.IsActive, table.CreatedBy, table.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.DiningTableEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindDiningTableByID(table.ID.String())
+}
+
+func (r *DiningTableRepositoryPostgreSQLImpl) UpdateDiningTableByID(id string, table model.DiningTableEntity) (model.DiningTableEntity, error) {
+	query := `
+		UPDATE core.dining_table
+		SET area = $1, name = $2, seats = $3, is_active = $4, updated_by = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6 AND version = $7
+	`
+	tag, err := r.connPool.Exec(context.Background(), query,
+		table.Area, table.Name, table.Seats, table.IsActive, table.UpdatedBy, id, table.Version,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.DiningTableEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.DiningTableEntity{}, fmt.Errorf("dining table %s was changed or removed", id)
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindDiningTableByID(id)
+}
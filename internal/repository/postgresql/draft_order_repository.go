@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DraftOrderRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewDraftOrderRepository(connPool DB) repository.DraftOrderRepository {
+	return &DraftOrderRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const draftOrderSelect = `
+	SELECT
+		id, version, created_at, created_by, updated_at, updated_by,
+		status, COALESCE(label, ''), COALESCE(table_number, ''), outlet_id, customer_id, COALESCE(notes, ''),
+		reserves_stock, transaction_id, table_id
+	FROM core.draft_order
+`
+
+func scanDraftOrder(row pgx.Row) (model.DraftOrderEntity, error) {
+	var d model.DraftOrderEntity
+	err := row.Scan(
+		&d.ID, &d.Version, &d.CreatedAt, &d.CreatedBy, &d.UpdatedAt, &d.UpdatedBy,
+		&d.Status, &d.Label, &d.TableNumber, &d.OutletID, &d.CustomerID, &d.Notes,
+		&d.ReservesStock, &d.TransactionID, &d.TableID,
+	)
+	return d, err
+}
+
+func (r *DraftOrderRepositoryPostgreSQLImpl) FindDraftOrders(status string) ([]model.DraftOrderEntity, error) {
+	ctx := context.Background()
+	query := draftOrderSelect + `
+		WHERE ($1 = '' AND status IN ('open', 'held')) OR status = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.connPool.Query(ctx, query, status)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drafts []model.DraftOrderEntity
+	var ids []uuid.UUID
+	for rows.Next() {
+		d, err := scanDraftOrder(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		drafts = append(drafts, d)
+		ids = append(ids, d.ID)
+	}
+	rows.Close()
+
+	items, err := findDraftOrderItems(ctx, r.connPool, ids)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	for i := range drafts {
+		drafts[i].Items = items[drafts[i].ID]
+	}
+
+	return drafts, nil
+}
+
+func (r *DraftOrderRepositoryPostgreSQLImpl) FindDraftOrderByID(id string) (model.DraftOrderEntity, error) {
+	ctx := context.Background()
+	d, err := scanDraftOrder(r.connPool.QueryRow(ctx, draftOrderSelect+` WHERE id = $1`, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+
+	items, err := findDraftOrderItems(ctx, r.connPool, []uuid.UUID{d.ID})
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+	d.Items = items[d.ID]
+
+	return d, nil
+}
+
+func findDraftOrderItems(ctx context.Context, conn DB, draftOrderIDs []uuid.UUID) (map[uuid.UUID][]model.DraftOrderItemEntity, error) {
+	items := map[uuid.UUID][]model.DraftOrderItemEntity{}
+	if len(draftOrderIDs) == 0 {
+		return items, nil
+	}
+	query := `
+		SELECT i.draft_order_id, i.product_id, p.name, i.quantity, i.fired_quantity, i.created_at, i.created_by
+		FROM core.draft_order_item i
+		JOIN core.product p ON i.product_id = p.id
+		WHERE i.draft_order_id = ANY($1)
+		ORDER BY i.created_at, i.product_id
+	`
+	rows, err := conn.Query(ctx, query, draftOrderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i model.DraftOrderItemEntity
+		if err := rows.Scan(&i.DraftOrderID, &i.ProductID, &i.ProductName, &i.Quantity, &i.FiredQuantity, &i.CreatedAt, &i.CreatedBy); err != nil {
+			return nil, err
+		}
+		items[i.DraftOrderID] = append(items[i.DraftOrderID], i)
+	}
+	return items, nil
+}
+
+func (r *DraftOrderRepositoryPostgreSQLImpl) InsertDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// idx_draft_order_table and idx_draft_order_dining_table refuse a second tab on a table that already runs one
+	query := `
+		INSERT INTO core.draft_order (
+			id, status, label, table_number, table_id, outlet_id, customer_id, notes, reserves_stock, created_by, updated_by
+		) VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, NULLIF($8, ''), $9, $10, $11)
+	`
+	_, err = conn.Exec(ctx, query,
+		draft.ID, draft.Status, draft.Label, draft.TableNumber, draft.TableID, draft.OutletID, draft.CustomerID, draft.Notes,
+		draft.ReservesStock, draft.CreatedBy, draft.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+
+	for _, i := range draft.Items {
+		_, err := conn.Exec(ctx, `
+			INSERT INTO core.draft_order_item (draft_order_id, product_id, quantity, created_by)
+			VALUES ($1, $2, $3, $4)
+		`, draft.ID, i.ProductID, i.Quantity, i.CreatedBy)
+		if err != nil {
+			fmt.Println(err)
+			return model.DraftOrderEntity{}, err
+		}
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindDraftOrderByID(draft.ID.String())
+}
+
+func (r *DraftOrderRepositoryPostgreSQLImpl) UpdateDraftOrder(draft model.DraftOrderEntity) (model.DraftOrderEntity, error) {
+	query := `
+		UPDATE core.draft_order
+		SET status = $1, label = NULLIF($2, ''), table_number = NULLIF($3, ''), table_id = $4, notes = NULLIF($5, ''),
+			updated_by = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7 AND status IN ('open', 'held')
+	`
+	tag, err := r.connPool.Exec(context.Background(), query,
+		draft.Status, draft.Label, draft.TableNumber, draft.TableID, draft.Notes, draft.UpdatedBy, draft.ID,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.DraftOrderEntity{}, fmt.Errorf("draft order %s is no longer open or held", draft.ID)
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindDraftOrderByID(draft.ID.String())
+}
+
+func (r *DraftOrderRepositoryPostgreSQLImpl) SetDraftOrderItem(item model.DraftOrderItemEntity) (model.DraftOrderEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// touching the draft locks it, so the change cannot slip in while the draft is converted or cancelled
+	tag, err := conn.Exec(ctx, `
+		UPDATE core.draft_order SET updated_by = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status IN ('open', 'held')
+	`, item.CreatedBy, item.DraftOrderID)
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.DraftOrderEntity{}, fmt.Errorf("draft order %s is no longer open or held", item.DraftOrderID)
+	}
+
+	if item.Quantity > 0 {
+		_, err = conn.Exec(ctx, `
+			INSERT INTO core.draft_order_item (draft_order_id, product_id, quantity, created_by)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (draft_order_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity
+		`, item.DraftOrderID, item.ProductID, item.Quantity, item.CreatedBy)
+	} else {
+		_, err = conn.Exec(ctx, `DELETE FROM core.draft_order_item WHERE draft_order_id = $1 AND product_id = $2`, item.DraftOrderID, item.ProductID)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.DraftOrderEntity{}, err
+	}
+
+	return r.FindDraftOrderByID(item.DraftOrderID.String())
+}
+
+func (r *DraftOrderRepositoryPostgreSQLImpl) FindReservedStocks(outletID *uuid.UUID, exceptID string) (map[uuid.UUID]int, error) {
+	query := `
+		SELECT COALESCE(c.component_id, i.product_id), SUM(i.quantity * COALESCE(c.quantity, 1))
+		FROM core.draft_order d
+		JOIN core.draft_order_item i ON i.draft_order_id = d.id
+		LEFT JOIN core.product_bundle_component c ON c.bundle_id = i.product_id
+		WHERE d.reserves_stock AND d.status IN ('open', 'held')
+			AND d.outlet_id IS NOT DISTINCT FROM $1
+			AND ($2 = '' OR d.id::TEXT <> $2)
+		GROUP BY 1
+	`
+	rows, err := r.connPool.Query(context.Background(), query, outletID, exceptID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	reserved := map[uuid.UUID]int{}
+	for rows.Next() {
+		var productID uuid.UUID
+		var quantity int
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		reserved[productID] = quantity
+	}
+	return reserved, nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type GiftCardRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewGiftCardRepository(connPool DB) repository.GiftCardRepository {
+	return &GiftCardRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const giftCardColumns = `
+	id, version, created_at, created_by, updated_at, updated_by,
+	code, customer_id, initial_amount, balance, expires_at, is_active
+`
+
+func scanGiftCard(row pgx.Row) (model.GiftCardEntity, error) {
+	var g model.GiftCardEntity
+	err := row.Scan(
+		&g.ID, &g.Version, &g.CreatedAt, &g.CreatedBy, &g.UpdatedAt, &g.UpdatedBy,
+		&g.Code, &g.CustomerID, &g.InitialAmount, &g.Balance, &g.ExpiresAt, &g.IsActive,
+	)
+	return g, err
+}
+
+func (r *GiftCardRepositoryPostgreSQLImpl) FindGiftCards() ([]model.GiftCardEntity, error) {
+	var cards []model.GiftCardEntity
+	query := `SELECT ` + giftCardColumns + ` FROM core.gift_card ORDER BY created_at DESC`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		g, err := scanGiftCard(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		cards = append(cards, g)
+	}
+
+	return cards, nil
+}
+
+func (r *GiftCardRepositoryPostgreSQLImpl) FindGiftCardByCode(code string) (model.GiftCardEntity, error) {
+	query := `SELECT ` + giftCardColumns + ` FROM core.gift_card WHERE code = $1`
+	g, err := scanGiftCard(r.connPool.QueryRow(context.Background(), query, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.GiftCardEntity{}, nil
+	}
+	if err != nil {
+		fmt.Println(err)
+		return model.GiftCardEntity{}, err
+	}
+	return g, nil
+}
+
+func (r *GiftCardRepositoryPostgreSQLImpl) InsertGiftCard(card model.GiftCardEntity) (model.GiftCardEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		return model.GiftCardEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO core.gift_card (
+			id, code, customer_id, initial_amount, expires_at, is_active, created_by, updated_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = conn.Exec(ctx, query,
+		card.ID, card.Code, card.CustomerID, card.InitialAmount, card.ExpiresAt, card.IsActive,
+		card.CreatedBy, card.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.GiftCardEntity{}, err
+	}
+
+	entryID, err := uuid.NewV7()
+	if err != nil {
+		return model.GiftCardEntity{}, err
+	}
+	_, err = insertGiftCardEntry(ctx, conn, model.GiftCardEntryEntity{
+		ID:         entryID,
+		GiftCardID: card.ID,
+		Type:       model.GiftCardEntryIssue,
+		Amount:     card.InitialAmount,
+		CreatedBy:  card.CreatedBy,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return model.GiftCardEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	inserted, err := scanGiftCard(conn.QueryRow(ctx, `SELECT `+giftCardColumns+` FROM core.gift_card WHERE id = $1`, card.ID))
+	if err != nil {
+		fmt.Println(err)
+		return model.GiftCardEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		return model.GiftCardEntity{}, err
+	}
+	return inserted, nil
+}
+
+func (r *GiftCardRepositoryPostgreSQLImpl) FindGiftCardEntries(giftCardID string) ([]model.GiftCardEntryEntity, error) {
+	var entries []model.GiftCardEntryEntity
+	query := `
+		SELECT
+			id, gift_card_id, entry_type, amount, balance_after, transaction_id,
+			COALESCE(notes, ''), created_at, created_by
+		FROM core.gift_card_ledger
+		WHERE gift_card_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.connPool.Query(context.Background(), query, giftCardID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.GiftCardEntryEntity
+		if err := rows.Scan(
+			&e.ID, &e.GiftCardID, &e.Type, &e.Amount, &e.BalanceAfter, &e.TransactionID,
+			&e.Notes, &e.CreatedAt, &e.CreatedBy,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (r *GiftCardRepositoryPostgreSQLImpl) InsertGiftCardEntry(entry model.GiftCardEntryEntity) (model.GiftCardEntryEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		return model.GiftCardEntryEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	inserted, err := insertGiftCardEntry(ctx, conn, entry)
+	if err != nil {
+		fmt.Println(err)
+		return model.GiftCardEntryEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		return model.GiftCardEntryEntity{}, err
+	}
+	return inserted, nil
+}
+
+// insertGiftCardEntry books one entry, trg_gift_card_ledger_apply moves the card's balance with it and
+// holds the card's row lock until the surrounding transaction ends
+func insertGiftCardEntry(ctx context.Context, conn pgx.Tx, entry model.GiftCardEntryEntity) (model.GiftCardEntryEntity, error) {
+	query := `
+		INSERT INTO core.gift_card_ledger (
+			id, gift_card_id, entry_type, amount, transaction_id, notes, created_by
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+	`
+	_, err := conn.Exec(ctx, query,
+		entry.ID, entry.GiftCardID, entry.Type, entry.Amount, entry.TransactionID, entry.Notes, entry.CreatedBy,
+	)
+	if err != nil {
+		return model.GiftCardEntryEntity{}, fmt.Errorf("failed to insert gift card entry: %w", err)
+	}
+
+	// Supabase buggy when using RETURNING
+	err = conn.QueryRow(ctx, "SELECT balance_after, created_at FROM core.gift_card_ledger WHERE id = $1", entry.ID).
+		Scan(&entry.BalanceAfter, &entry.CreatedAt)
+	if err != nil {
+		return model.GiftCardEntryEntity{}, fmt.Errorf("failed to read gift card entry: %w", err)
+	}
+	return entry, nil
+}
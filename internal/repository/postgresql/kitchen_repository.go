@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type KitchenRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewKitchenRepository(connPool DB) repository.KitchenRepository {
+	return &KitchenRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const kitchenStationSelect = `
+	SELECT
+		s.id, s.version, s.created_at, s.created_by, s.updated_at, s.updated_by, s.name, s.is_active,
+		ARRAY(SELECT c.category_id FROM core.kitchen_station_category c WHERE c.station_id = s.id ORDER BY c.category_id)
+	FROM core.kitchen_station s
+`
+
+func scanKitchenStation(row pgx.Row) (model.KitchenStationEntity, error) {
+	var s model.KitchenStationEntity
+	err := row.Scan(
+		&s.ID, &s.Version, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt, &s.UpdatedBy, &s.Name, &s.IsActive,
+		&s.CategoryIDs,
+	)
+	return s, err
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) FindKitchenStations() ([]model.KitchenStationEntity, error) {
+	var stations []model.KitchenStationEntity
+	rows, err := r.connPool.Query(context.Background(), kitchenStationSelect+` ORDER BY s.name`)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanKitchenStation(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		stations = append(stations, s)
+	}
+	return stations, nil
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) FindKitchenStationByID(id string) (model.KitchenStationEntity, error) {
+	s, err := scanKitchenStation(r.connPool.QueryRow(context.Background(), kitchenStationSelect+` WHERE s.id = $1`, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+	return s, nil
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) InsertKitchenStation(station model.KitchenStationEntity) (model.KitchenStationEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	_, err = conn.Exec(ctx, `
+		INSERT INTO core.kitchen_station (id, name, is_active, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`, station.ID, station.Name, station.IsActive, station.CreatedBy, station.UpdatedBy)
+	if err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+	if err := routeCategories(ctx, conn, station.ID, station.CategoryIDs); err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindKitchenStationByID(station.ID.String())
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) UpdateKitchenStationByID(id string, station model.KitchenStationEntity) (model.KitchenStationEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	tag, err := conn.Exec(ctx, `
+		UPDATE core.kitchen_station
+		SET name = $1, is_active = $2, updated_by = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND version = $5
+	`, station.Name, station.IsActive, station.UpdatedBy, id, station.Version)
+	if err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.KitchenStationEntity{}, fmt.Errorf("kitchen station %s was changed or removed", id)
+	}
+
+	if _, err := conn.Exec(ctx, `DELETE FROM core.kitchen_station_category WHERE station_id = $1`, id); err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+	stationID, err := uuid.Parse(id)
+	if err != nil {
+		return model.KitchenStationEntity{}, err
+	}
+	if err := routeCategories(ctx, conn, stationID, station.CategoryIDs); err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.KitchenStationEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindKitchenStationByID(id)
+}
+
+// routeCategories sends the categories to the station, a category is prepared at one station only
+func routeCategories(ctx context.Context, conn pgx.Tx, stationID uuid.UUID, categoryIDs []uuid.UUID) error {
+	for _, categoryID := range categoryIDs {
+		_, err := conn.Exec(ctx, `
+			INSERT INTO core.kitchen_station_category (category_id, station_id)
+			VALUES ($1, $2)
+			ON CONFLICT (tenant_id, category_id) DO UPDATE SET station_id = EXCLUDED.station_id
+		`, categoryID, stationID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) FindStationsByCategory() (map[uuid.UUID]uuid.UUID, error) {
+	query := `
+		SELECT c.category_id, c.station_id
+		FROM core.kitchen_station_category c
+		JOIN core.kitchen_station s ON s.id = c.station_id
+		WHERE s.is_active
+	`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stations := map[uuid.UUID]uuid.UUID{}
+	for rows.Next() {
+		var categoryID, stationID uuid.UUID
+		if err := rows.Scan(&categoryID, &stationID); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		stations[categoryID] = stationID
+	}
+	return stations, nil
+}
+
+const kitchenTicketSelect = `
+	SELECT
+		t.id, t.version, t.created_at, t.created_by, t.updated_at, t.updated_by,
+		t.draft_order_id, t.station_id, s.name, COALESCE(t.label, ''), COALESCE(t.table_number, ''),
+		t.status, t.preparing_at, t.ready_at, t.served_at
+	FROM core.kitchen_ticket t
+	JOIN core.kitchen_station s ON s.id = t.station_id
+`
+
+func scanKitchenTicket(row pgx.Row) (model.KitchenTicketEntity, error) {
+	var t model.KitchenTicketEntity
+	err := row.Scan(
+		&t.ID, &t.Version, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy,
+		&t.DraftOrderID, &t.StationID, &t.StationName, &t.Label, &t.TableNumber,
+		&t.Status, &t.PreparingAt, &t.ReadyAt, &t.ServedAt,
+	)
+	return t, err
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) FindKitchenTickets(stationID, status string) ([]model.KitchenTicketEntity, error) {
+	query := kitchenTicketSelect + `
+		WHERE ($1 = '' OR t.station_id::TEXT = $1)
+			AND (($2 = '' AND t.status <> 'served') OR t.status = $2)
+		ORDER BY t.created_at
+	`
+	return r.findKitchenTickets(query, stationID, status)
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) FindKitchenTicketByID(id string) (model.KitchenTicketEntity, error) {
+	tickets, err := r.findKitchenTickets(kitchenTicketSelect+` WHERE t.id = $1`, id)
+	if err != nil {
+		return model.KitchenTicketEntity{}, err
+	}
+	if len(tickets) == 0 {
+		return model.KitchenTicketEntity{}, pgx.ErrNoRows
+	}
+	return tickets[0], nil
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) findKitchenTickets(query string, args ...any) ([]model.KitchenTicketEntity, error) {
+	ctx := context.Background()
+	rows, err := r.connPool.Query(ctx, query, args...)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []model.KitchenTicketEntity
+	var ids []uuid.UUID
+	for rows.Next() {
+		t, err := scanKitchenTicket(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		tickets = append(tickets, t)
+		ids = append(ids, t.ID)
+	}
+	rows.Close()
+
+	items, err := findKitchenTicketItems(ctx, r.connPool, ids)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	for i := range tickets {
+		tickets[i].Items = items[tickets[i].ID]
+	}
+	return tickets, nil
+}
+
+func findKitchenTicketItems(ctx context.Context, conn DB, ticketIDs []uuid.UUID) (map[uuid.UUID][]model.KitchenTicketItemEntity, error) {
+	items := map[uuid.UUID][]model.KitchenTicketItemEntity{}
+	if len(ticketIDs) == 0 {
+		return items, nil
+	}
+	query := `
+		SELECT ticket_id, product_id, product_name, quantity
+		FROM core.kitchen_ticket_item
+		WHERE ticket_id = ANY($1)
+		ORDER BY product_name
+	`
+	rows, err := conn.Query(ctx, query, ticketIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i model.KitchenTicketItemEntity
+		if err := rows.Scan(&i.TicketID, &i.ProductID, &i.ProductName, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items[i.TicketID] = append(items[i.TicketID], i)
+	}
+	return items, nil
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) FireKitchenTickets(draft model.DraftOrderEntity, tickets []model.KitchenTicketEntity) ([]model.KitchenTicketEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// touching the draft locks it like SetDraftOrderItem does, so its lines hold still until the commit
+	tag, err := conn.Exec(ctx, `
+		UPDATE core.draft_order SET updated_by = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status IN ('open', 'held')
+	`, draft.UpdatedBy, draft.ID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, fmt.Errorf("draft order %s is no longer open or held", draft.ID)
+	}
+
+	// the lines read before the lock must still be the draft's lines, or the tickets would miss or repeat units
+	var lines int
+	if err := conn.QueryRow(ctx, `SELECT COUNT(*) FROM core.draft_order_item WHERE draft_order_id = $1`, draft.ID).Scan(&lines); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	if lines != len(draft.Items) {
+		return nil, fmt.Errorf("draft order %s changed while it was fired", draft.ID)
+	}
+	for _, i := range draft.Items {
+		tag, err := conn.Exec(ctx, `
+			UPDATE core.draft_order_item SET fired_quantity = quantity
+			WHERE draft_order_id = $1 AND product_id = $2 AND quantity = $3 AND fired_quantity = $4
+		`, draft.ID, i.ProductID, i.Quantity, i.FiredQuantity)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, fmt.Errorf("draft order %s changed while it was fired", draft.ID)
+		}
+	}
+
+	var ids []uuid.UUID
+	for _, t := range tickets {
+		_, err := conn.Exec(ctx, `
+			INSERT INTO core.kitchen_ticket (id, draft_order_id, station_id, label, table_number, status, created_by, updated_by)
+			VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8)
+		`, t.ID, t.DraftOrderID, t.StationID, t.Label, t.TableNumber, t.Status, t.CreatedBy, t.UpdatedBy)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		for _, i := range t.Items {
+			_, err := conn.Exec(ctx, `
+				INSERT INTO core.kitchen_ticket_item (ticket_id, product_id, product_name, quantity)
+				VALUES ($1, $2, $3, $4)
+			`, t.ID, i.ProductID, i.ProductName, i.Quantity)
+			if err != nil {
+				fmt.Println(err)
+				return nil, err
+			}
+		}
+		ids = append(ids, t.ID)
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.findKitchenTickets(kitchenTicketSelect+` WHERE t.id = ANY($1) ORDER BY s.name`, ids)
+}
+
+func (r *KitchenRepositoryPostgreSQLImpl) UpdateKitchenTicketStatus(ticket model.KitchenTicketEntity) (model.KitchenTicketEntity, error) {
+	query := `
+		UPDATE core.kitchen_ticket
+		SET status = $1,
+			preparing_at = CASE WHEN $1 = 'preparing' THEN CURRENT_TIMESTAMP ELSE preparing_at END,
+			ready_at = CASE WHEN $1 = 'ready' THEN CURRENT_TIMESTAMP ELSE ready_at END,
+			served_at = CASE WHEN $1 = 'served' THEN CURRENT_TIMESTAMP ELSE served_at END,
+			updated_by = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND version = $4
+	`
+	tag, err := r.connPool.Exec(context.Background(), query, ticket.Status, ticket.UpdatedBy, ticket.ID, ticket.Version)
+	if err != nil {
+		fmt.Println(err)
+		return model.KitchenTicketEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.KitchenTicketEntity{}, fmt.Errorf("kitchen ticket %s was changed by another screen", ticket.ID)
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindKitchenTicketByID(ticket.ID.String())
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type LotRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewLotRepository(connPool DB) repository.LotRepository {
+	return &LotRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const lotSelect = `
+	SELECT
+		l.id, l.product_id, p.name, l.outlet_id, l.lot_number, l.expiry_date,
+		l.quantity_received, l.quantity_remaining, l.created_at, l.created_by
+	FROM core.product_lot l
+	JOIN core.product p ON l.product_id = p.id
+`
+
+func (r *LotRepositoryPostgreSQLImpl) FindLotsByProductID(productID string) ([]model.ProductLotEntity, error) {
+	query := lotSelect + `
+		WHERE l.product_id = $1
+		ORDER BY l.expiry_date, l.id
+	`
+	return r.findLots(query, productID)
+}
+
+func (r *LotRepositoryPostgreSQLImpl) FindOpenLotsByProductID(productID string, outletID *uuid.UUID) ([]model.ProductLotEntity, error) {
+	query := lotSelect + `
+		WHERE l.product_id = $1 AND l.quantity_remaining > 0 AND l.outlet_id IS NOT DISTINCT FROM $2
+		ORDER BY l.expiry_date, l.id
+	`
+	return r.findLots(query, productID, outletID)
+}
+
+func (r *LotRepositoryPostgreSQLImpl) FindExpiringLots(until time.Time) ([]model.ProductLotEntity, error) {
+	query := lotSelect + `
+		WHERE l.quantity_remaining > 0 AND l.expiry_date <= $1 AND p.deleted_at IS NULL
+		ORDER BY l.expiry_date, p.name, l.id
+	`
+	return r.findLots(query, until.Format("2006-01-02"))
+}
+
+func (r *LotRepositoryPostgreSQLImpl) findLots(query string, args ...any) ([]model.ProductLotEntity, error) {
+	rows, err := r.connPool.Query(context.Background(), query, args...)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []model.ProductLotEntity
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, nil
+}
+
+func (r *LotRepositoryPostgreSQLImpl) InsertLot(lot model.ProductLotEntity) (model.ProductLotEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductLotEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// quantity_remaining starts at zero, the receipt movement below books the units into the lot
+	query := `
+		INSERT INTO core.product_lot (id, product_id, outlet_id, lot_number, expiry_date, quantity_received, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = conn.Exec(ctx, query,
+		lot.ID, lot.ProductID, lot.OutletID, lot.LotNumber, lot.ExpiryDate.Format("2006-01-02"), lot.QuantityReceived, lot.CreatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductLotEntity{}, err
+	}
+
+	movementID, err := uuid.NewV7()
+	if err != nil {
+		return model.ProductLotEntity{}, err
+	}
+	_, err = insertStockMovement(ctx, conn, model.StockMovementEntity{
+		ID:        movementID,
+		ProductID: lot.ProductID,
+		Type:      model.StockMovementPurchaseReceipt,
+		Quantity:  lot.QuantityReceived,
+		Reason:    "lot " + lot.LotNumber,
+		CreatedBy: lot.CreatedBy,
+		LotID:     &lot.ID,
+		OutletID:  lot.OutletID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductLotEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	inserted, err := scanLot(conn.QueryRow(ctx, lotSelect+"WHERE l.id = $1", lot.ID))
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductLotEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ProductLotEntity{}, err
+	}
+	return inserted, nil
+}
+
+func scanLot(row pgx.Row) (model.ProductLotEntity, error) {
+	var lot model.ProductLotEntity
+	err := row.Scan(
+		&lot.ID, &lot.ProductID, &lot.ProductName, &lot.OutletID, &lot.LotNumber, &lot.ExpiryDate,
+		&lot.QuantityReceived, &lot.QuantityRemaining, &lot.CreatedAt, &lot.CreatedBy,
+	)
+	return lot, err
+}
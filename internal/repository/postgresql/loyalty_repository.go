@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type LoyaltyRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewLoyaltyRepository(connPool DB) repository.LoyaltyRepository {
+	return &LoyaltyRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+func (r *LoyaltyRepositoryPostgreSQLImpl) FindLoyaltyProgram() (model.LoyaltyProgramEntity, error) {
+	ctx := context.Background()
+	var p model.LoyaltyProgramEntity
+	query := `
+		SELECT version, created_at, created_by, updated_at, updated_by,
+			is_active, spend_per_point, point_value, expiry_days
+		FROM core.loyalty_program
+	`
+	err := r.connPool.QueryRow(ctx, query).Scan(
+		&p.Version, &p.CreatedAt, &p.CreatedBy, &p.UpdatedAt, &p.UpdatedBy,
+		&p.IsActive, &p.SpendPerPoint, &p.PointValue, &p.ExpiryDays,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.LoyaltyProgramEntity{}, nil
+	}
+	if err != nil {
+		fmt.Println(err)
+		return model.LoyaltyProgramEntity{}, err
+	}
+
+	rows, err := r.connPool.Query(ctx, `SELECT category_id, multiplier::float8 FROM core.loyalty_category_multiplier`)
+	if err != nil {
+		fmt.Println(err)
+		return model.LoyaltyProgramEntity{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m model.LoyaltyCategoryMultiplierEntity
+		if err := rows.Scan(&m.CategoryID, &m.Multiplier); err != nil {
+			fmt.Println(err)
+			return model.LoyaltyProgramEntity{}, err
+		}
+		p.CategoryMultipliers = append(p.CategoryMultipliers, m)
+	}
+
+	return p, nil
+}
+
+func (r *LoyaltyRepositoryPostgreSQLImpl) SaveLoyaltyProgram(program model.LoyaltyProgramEntity) (model.LoyaltyProgramEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		return model.LoyaltyProgramEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO core.loyalty_program (
+			is_active, spend_per_point, point_value, expiry_days, created_by, updated_by
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			is_active = EXCLUDED.is_active,
+			spend_per_point = EXCLUDED.spend_per_point,
+			point_value = EXCLUDED.point_value,
+			expiry_days = EXCLUDED.expiry_days,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE core.loyalty_program.version = $7
+	`
+	tag, err := conn.Exec(ctx, query,
+		program.IsActive, program.SpendPerPoint, program.PointValue, program.ExpiryDays,
+		program.CreatedBy, program.UpdatedBy, program.Version,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.LoyaltyProgramEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.LoyaltyProgramEntity{}, fmt.Errorf("loyalty program version %d is outdated", program.Version)
+	}
+
+	if _, err := conn.Exec(ctx, `DELETE FROM core.loyalty_category_multiplier`); err != nil {
+		fmt.Println(err)
+		return model.LoyaltyProgramEntity{}, err
+	}
+	for _, m := range program.CategoryMultipliers {
+		_, err := conn.Exec(ctx,
+			`INSERT INTO core.loyalty_category_multiplier (category_id, multiplier) VALUES ($1, $2)`,
+			m.CategoryID, m.Multiplier,
+		)
+		if err != nil {
+			fmt.Println(err)
+			return model.LoyaltyProgramEntity{}, err
+		}
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		return model.LoyaltyProgramEntity{}, err
+	}
+	return r.FindLoyaltyProgram()
+}
+
+func (r *LoyaltyRepositoryPostgreSQLImpl) FindLoyaltyEntries(customerID string) ([]model.LoyaltyEntryEntity, error) {
+	var entries []model.LoyaltyEntryEntity
+	query := `
+		SELECT
+			id, customer_id, entry_type, points, balance_after, transaction_id, reference_entry_id,
+			expires_at, COALESCE(notes, ''), created_at, created_by
+		FROM core.loyalty_ledger
+		WHERE customer_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.connPool.Query(context.Background(), query, customerID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.LoyaltyEntryEntity
+		if err := rows.Scan(
+			&e.ID, &e.CustomerID, &e.Type, &e.Points, &e.BalanceAfter, &e.TransactionID, &e.ReferenceEntryID,
+			&e.ExpiresAt, &e.Notes, &e.CreatedAt, &e.CreatedBy,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (r *LoyaltyRepositoryPostgreSQLImpl) InsertLoyaltyEntries(entries []model.LoyaltyEntryEntity) ([]model.LoyaltyEntryEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	inserted := make([]model.LoyaltyEntryEntity, 0, len(entries))
+	for _, e := range entries {
+		entry, err := insertLoyaltyEntry(ctx, conn, e)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		inserted = append(inserted, entry)
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return inserted, nil
+}
+
+// insertLoyaltyEntry books one entry, trg_loyalty_ledger_apply moves the customer's balance with it
+func insertLoyaltyEntry(ctx context.Context, conn pgx.Tx, entry model.LoyaltyEntryEntity) (model.LoyaltyEntryEntity, error) {
+	query := `
+		INSERT INTO core.loyalty_ledger (
+			id, customer_id, entry_type, points, transaction_id, reference_entry_id, expires_at, notes, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+	`
+	_, err := conn.Exec(ctx, query,
+		entry.ID, entry.CustomerID, entry.Type, entry.Points, entry.TransactionID, entry.ReferenceEntryID,
+		entry.ExpiresAt, entry.Notes, entry.CreatedBy,
+	)
+	if err != nil {
+		return model.LoyaltyEntryEntity{}, fmt.Errorf("failed to insert loyalty entry: %w", err)
+	}
+
+	// Supabase buggy when using RETURNING
+	err = conn.QueryRow(ctx, "SELECT balance_after, created_at FROM core.loyalty_ledger WHERE id = $1", entry.ID).
+		Scan(&entry.BalanceAfter, &entry.CreatedAt)
+	if err != nil {
+		return model.LoyaltyEntryEntity{}, fmt.Errorf("failed to read loyalty entry: %w", err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type OutletRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewOutletRepository(connPool DB) repository.OutletRepository {
+	return &OutletRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const outletColumns = `
+	id, version, created_at, created_by, updated_at, updated_by, deleted_at,
+	code, name, COALESCE(address, ''), is_active
+`
+
+func scanOutlet(row pgx.Row) (model.OutletEntity, error) {
+	var o model.OutletEntity
+	err := row.Scan(
+		&o.ID, &o.Version, &o.CreatedAt, &o.CreatedBy, &o.UpdatedAt, &o.UpdatedBy, &o.DeletedAt,
+		&o.Code, &o.Name, &o.Address, &o.IsActive,
+	)
+	return o, err
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) FindOutlets() ([]model.OutletEntity, error) {
+	var outlets []model.OutletEntity
+	query := `SELECT ` + outletColumns + ` FROM core.outlet WHERE deleted_at IS NULL ORDER BY code`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		o, err := scanOutlet(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		outlets = append(outlets, o)
+	}
+
+	return outlets, nil
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) FindOutletByID(id string) (model.OutletEntity, error) {
+	query := `SELECT ` + outletColumns + ` FROM core.outlet WHERE id = $1`
+	o, err := scanOutlet(r.connPool.QueryRow(context.Background(), query, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.OutletEntity{}, err
+	}
+	return o, nil
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) InsertOutlet(outlet model.OutletEntity) (model.OutletEntity, error) {
+	query := `
+		INSERT INTO core.outlet (id, code, name, address, is_active, created_by, updated_by)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		outlet.ID, outlet.Code, outlet.Name, outlet.Address, outlet.IsActive, outlet.CreatedBy, outlet.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.OutletEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindOutletByID(outlet.ID.String())
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) UpdateOutletByID(id string, outlet model.OutletEntity) (model.OutletEntity, error) {
+	query := `
+		UPDATE core.outlet
+		SET code = $1, name = $2, address = NULLIF($3, ''), is_active = $4, updated_by = $5
+		WHERE id = $6 AND version = $7 AND deleted_at IS NULL
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		outlet.Code, outlet.Name, outlet.Address, outlet.IsActive, outlet.UpdatedBy, id, outlet.Version,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.OutletEntity{}, err
+	}
+	return r.FindOutletByID(id)
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) DeleteOutletByID(id string) error {
+	_, err := r.connPool.Exec(context.Background(), "UPDATE core.outlet SET deleted_at = NOW(), updated_at = NOW(), updated_by = $1 WHERE id = $2", "USER", id)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) FindStockHeldAtOutlets(productID string) (int, error) {
+	var held int
+	err := r.connPool.QueryRow(context.Background(),
+		"SELECT COALESCE(SUM(stock), 0) FROM core.outlet_stock WHERE product_id = $1", productID,
+	).Scan(&held)
+	if err != nil {
+		fmt.Println(err)
+		return 0, err
+	}
+	return held, nil
+}
+
+// outletStockSelect starts from the products so the ones an outlet never held show up with zero stock
+const outletStockSelect = `
+	SELECT
+		$1::uuid, p.id, p.name, p.price_amount,
+		COALESCE(s.stock, 0), s.price_amount, COALESCE(s.updated_at, p.updated_at)
+	FROM core.product p
+	LEFT JOIN core.outlet_stock s ON s.product_id = p.id AND s.outlet_id = $1
+`
+
+func scanOutletStock(row pgx.Row) (model.OutletStockEntity, error) {
+	var s model.OutletStockEntity
+	err := row.Scan(
+		&s.OutletID, &s.ProductID, &s.ProductName, &s.ProductPrice,
+		&s.Stock, &s.PriceOverride, &s.UpdatedAt,
+	)
+	return s, err
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) FindOutletStocks(outletID string) ([]model.OutletStockEntity, error) {
+	var stocks []model.OutletStockEntity
+	query := outletStockSelect + ` WHERE p.deleted_at IS NULL ORDER BY p.name`
+	rows, err := r.connPool.Query(context.Background(), query, outletID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s, err := scanOutletStock(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		stocks = append(stocks, s)
+	}
+	return stocks, nil
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) FindOutletStock(outletID, productID string) (model.OutletStockEntity, error) {
+	s, err := scanOutletStock(r.connPool.QueryRow(context.Background(), outletStockSelect+` WHERE p.id = $2`, outletID, productID))
+	if err != nil {
+		fmt.Println(err)
+		return model.OutletStockEntity{}, err
+	}
+	return s, nil
+}
+
+func (r *OutletRepositoryPostgreSQLImpl) UpdateOutletPrice(outletID, productID string, priceOverride *int64) (model.OutletStockEntity, error) {
+	query := `
+		INSERT INTO core.outlet_stock (outlet_id, product_id, price_amount)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (outlet_id, product_id) DO UPDATE SET
+			price_amount = EXCLUDED.price_amount,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.OutletStockEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if _, err := conn.Exec(ctx, query, outletID, productID, priceOverride); err != nil {
+		fmt.Println(err)
+		return model.OutletStockEntity{}, err
+	}
+	if err := touchProduct(ctx, conn, productID); err != nil {
+		fmt.Println(err)
+		return model.OutletStockEntity{}, err
+	}
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.OutletStockEntity{}, err
+	}
+	return r.FindOutletStock(outletID, productID)
+}
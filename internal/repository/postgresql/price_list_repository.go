@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PriceListRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewPriceListRepository(connPool DB) repository.PriceListRepository {
+	return &PriceListRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const priceListColumns = `
+	id, version, created_at, created_by, updated_at, updated_by, deleted_at,
+	code, name, is_active
+`
+
+func scanPriceList(row pgx.Row) (model.PriceListEntity, error) {
+	var l model.PriceListEntity
+	err := row.Scan(
+		&l.ID, &l.Version, &l.CreatedAt, &l.CreatedBy, &l.UpdatedAt, &l.UpdatedBy, &l.DeletedAt,
+		&l.Code, &l.Name, &l.IsActive,
+	)
+	return l, err
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindPriceLists() ([]model.PriceListEntity, error) {
+	var priceLists []model.PriceListEntity
+	query := `SELECT ` + priceListColumns + ` FROM core.price_list WHERE deleted_at IS NULL ORDER BY code`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		l, err := scanPriceList(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		priceLists = append(priceLists, l)
+	}
+
+	return priceLists, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindPriceListByID(id string) (model.PriceListEntity, error) {
+	query := `SELECT ` + priceListColumns + ` FROM core.price_list WHERE id = $1`
+	l, err := scanPriceList(r.connPool.QueryRow(context.Background(), query, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.PriceListEntity{}, err
+	}
+	return l, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) InsertPriceList(priceList model.PriceListEntity) (model.PriceListEntity, error) {
+	query := `
+		INSERT INTO core.price_list (id, code, name, is_active, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		priceList.ID, priceList.Code, priceList.Name, priceList.IsActive, priceList.CreatedBy, priceList.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.PriceListEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindPriceListByID(priceList.ID.String())
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) UpdatePriceListByID(id string, priceList model.PriceListEntity) (model.PriceListEntity, error) {
+	query := `
+		UPDATE core.price_list
+		SET code = $1, name = $2, is_active = $3, updated_by = $4
+		WHERE id = $5 AND version = $6 AND deleted_at IS NULL
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		priceList.Code, priceList.Name, priceList.IsActive, priceList.UpdatedBy, id, priceList.Version,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.PriceListEntity{}, err
+	}
+	return r.FindPriceListByID(id)
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) DeletePriceListByID(id string) error {
+	_, err := r.connPool.Exec(context.Background(), "UPDATE core.price_list SET deleted_at = NOW(), updated_at = NOW(), updated_by = $1 WHERE id = $2", "USER", id)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindPriceListsChangedSince(since uint64) ([]model.PriceListEntity, error) {
+	var priceLists []model.PriceListEntity
+	query := `
+		SELECT ` + priceListColumns + `, change_xid::text::numeric::bigint
+		FROM core.price_list
+		WHERE change_xid >= $1::text::xid8
+		ORDER BY change_xid, id
+	`
+	rows, err := r.connPool.Query(context.Background(), query, strconv.FormatUint(since, 10))
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l model.PriceListEntity
+		if err := rows.Scan(
+			&l.ID, &l.Version, &l.CreatedAt, &l.CreatedBy, &l.UpdatedAt, &l.UpdatedBy, &l.DeletedAt,
+			&l.Code, &l.Name, &l.IsActive, &l.ChangeSeq,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		priceLists = append(priceLists, l)
+	}
+
+	return priceLists, nil
+}
+
+const productPriceSelect = `
+	SELECT
+		pp.id, pp.created_at, pp.created_by, pp.deleted_at,
+		pp.product_id, pp.price_list_id, COALESCE(l.code, ''), pp.price_amount, pp.starts_at
+	FROM core.product_price pp
+	LEFT JOIN core.price_list l ON pp.price_list_id = l.id
+`
+
+func scanProductPrice(row pgx.Row) (model.ProductPriceEntity, error) {
+	var p model.ProductPriceEntity
+	err := row.Scan(
+		&p.ID, &p.CreatedAt, &p.CreatedBy, &p.DeletedAt,
+		&p.ProductID, &p.PriceListID, &p.PriceListCode, &p.Price, &p.StartsAt,
+	)
+	return p, err
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindProductPrices(productID string) ([]model.ProductPriceEntity, error) {
+	var prices []model.ProductPriceEntity
+	query := productPriceSelect + ` WHERE pp.product_id = $1 AND pp.deleted_at IS NULL ORDER BY l.code NULLS FIRST, pp.starts_at`
+	rows, err := r.connPool.Query(context.Background(), query, productID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanProductPrice(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+
+	return prices, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindProductPricesByProductIDs(productIDs []string) ([]model.ProductPriceEntity, error) {
+	var prices []model.ProductPriceEntity
+	query := productPriceSelect + ` WHERE pp.product_id = ANY($1::uuid[]) AND pp.deleted_at IS NULL ORDER BY pp.product_id, l.code NULLS FIRST, pp.starts_at`
+	rows, err := r.connPool.Query(context.Background(), query, productIDs)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanProductPrice(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+
+	return prices, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) InsertProductPrice(price model.ProductPriceEntity) (model.ProductPriceEntity, error) {
+	query := `
+		INSERT INTO core.product_price (id, product_id, price_list_id, price_amount, starts_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductPriceEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	_, err = conn.Exec(ctx, query,
+		price.ID, price.ProductID, price.PriceListID, price.Price, price.StartsAt, price.CreatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductPriceEntity{}, err
+	}
+	if err := touchProduct(ctx, conn, price.ProductID.String()); err != nil {
+		fmt.Println(err)
+		return model.ProductPriceEntity{}, err
+	}
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ProductPriceEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindProductPriceByID(price.ProductID.String(), price.ID.String())
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindProductPriceByID(productID, id string) (model.ProductPriceEntity, error) {
+	query := productPriceSelect + ` WHERE pp.product_id = $1 AND pp.id = $2`
+	p, err := scanProductPrice(r.connPool.QueryRow(context.Background(), query, productID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ProductPriceEntity{}, nil
+	}
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductPriceEntity{}, err
+	}
+	return p, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) CancelProductPrice(productID, id string) error {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if _, err := conn.Exec(ctx, "UPDATE core.product_price SET deleted_at = NOW() WHERE product_id = $1 AND id = $2 AND deleted_at IS NULL", productID, id); err != nil {
+		fmt.Println(err)
+		return err
+	}
+	if err := touchProduct(ctx, conn, productID); err != nil {
+		fmt.Println(err)
+		return err
+	}
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return err
+	}
+	return nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindProductPriceTiers(productID string) ([]model.ProductPriceTierEntity, error) {
+	var tiers []model.ProductPriceTierEntity
+	query := `
+		SELECT id, created_at, created_by, product_id, min_quantity, price_amount
+		FROM core.product_price_tier
+		WHERE product_id = $1
+		ORDER BY min_quantity
+	`
+	rows, err := r.connPool.Query(context.Background(), query, productID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.ProductPriceTierEntity
+		if err := rows.Scan(&t.ID, &t.CreatedAt, &t.CreatedBy, &t.ProductID, &t.MinQuantity, &t.Price); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		tiers = append(tiers, t)
+	}
+
+	return tiers, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) FindProductPriceTiersByProductIDs(productIDs []string) ([]model.ProductPriceTierEntity, error) {
+	var tiers []model.ProductPriceTierEntity
+	query := `
+		SELECT id, created_at, created_by, product_id, min_quantity, price_amount
+		FROM core.product_price_tier
+		WHERE product_id = ANY($1::uuid[])
+		ORDER BY product_id, min_quantity
+	`
+	rows, err := r.connPool.Query(context.Background(), query, productIDs)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.ProductPriceTierEntity
+		if err := rows.Scan(&t.ID, &t.CreatedAt, &t.CreatedBy, &t.ProductID, &t.MinQuantity, &t.Price); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		tiers = append(tiers, t)
+	}
+
+	return tiers, nil
+}
+
+func (r *PriceListRepositoryPostgreSQLImpl) ReplaceProductPriceTiers(productID string, tiers []model.ProductPriceTierEntity) ([]model.ProductPriceTierEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if _, err := conn.Exec(ctx, "DELETE FROM core.product_price_tier WHERE product_id = $1", productID); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	query := `
+		INSERT INTO core.product_price_tier (id, product_id, min_quantity, price_amount, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, t := range tiers {
+		if _, err := conn.Exec(ctx, query, t.ID, t.ProductID, t.MinQuantity, t.Price, t.CreatedBy); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+	}
+	if err := touchProduct(ctx, conn, productID); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindProductPriceTiers(productID)
+}
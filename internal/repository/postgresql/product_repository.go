@@ -4,16 +4,20 @@ import (
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/repository"
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type ProductRepositoryPostgreSQLImpl struct {
-	connPool *pgxpool.Pool
+	connPool DB
 }
 
-func NewProductRepository(connPool *pgxpool.Pool) repository.ProductRepository {
+func NewProductRepository(connPool DB) repository.ProductRepository {
 	return &ProductRepositoryPostgreSQLImpl{
 		connPool: connPool,
 	}
@@ -25,7 +29,10 @@ func (r *ProductRepositoryPostgreSQLImpl) FindProducts() ([]model.ProductEntity,
 		SELECT 
 			p.id, p.created_at, p.created_by, p.updated_at, p.updated_by,
 			p.name, p.stock, p.price_amount, p.category_id,
-			COALESCE(c.name, '') as category_name
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
 		FROM core.product p
 		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
 		WHERE p.deleted_at IS NULL
@@ -43,6 +50,9 @@ func (r *ProductRepositoryPostgreSQLImpl) FindProducts() ([]model.ProductEntity,
 			&product.ID, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy,
 			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
 			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
 		); err != nil {
 			fmt.Println(err)
 			return nil, err
@@ -50,6 +60,110 @@ func (r *ProductRepositoryPostgreSQLImpl) FindProducts() ([]model.ProductEntity,
 		products = append(products, product)
 	}
 
+	if err := r.attachBundleComponents(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	if err := r.attachProductImages(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindProductsByCategoryIDs(categoryIDs []string) ([]model.ProductEntity, error) {
+	var products []model.ProductEntity
+	query := `
+		SELECT 
+			p.id, p.created_at, p.created_by, p.updated_at, p.updated_by,
+			p.name, p.stock, p.price_amount, p.category_id,
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
+		FROM core.product p
+		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
+		WHERE p.deleted_at IS NULL AND p.category_id = ANY($1::uuid[])
+	`
+	rows, err := r.connPool.Query(context.Background(), query, categoryIDs)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product model.ProductEntity
+		if err := rows.Scan(
+			&product.ID, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy,
+			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
+			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := r.attachBundleComponents(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	if err := r.attachProductImages(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindBundlesByComponentID(componentID string) ([]model.ProductEntity, error) {
+	var products []model.ProductEntity
+	query := `
+		SELECT 
+			p.id, p.created_at, p.created_by, p.updated_at, p.updated_by,
+			p.name, p.stock, p.price_amount, p.category_id,
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
+		FROM core.product p
+		JOIN core.product_bundle_component b ON b.bundle_id = p.id
+		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
+		WHERE p.deleted_at IS NULL AND b.component_id = $1
+		ORDER BY p.name
+	`
+	rows, err := r.connPool.Query(context.Background(), query, componentID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product model.ProductEntity
+		if err := rows.Scan(
+			&product.ID, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy,
+			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
+			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := r.attachBundleComponents(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
 	return products, nil
 }
 
@@ -59,7 +173,10 @@ func (r *ProductRepositoryPostgreSQLImpl) FindProductByID(id string) (model.Prod
 		SELECT 
 			p.id, p.version, p.created_at, p.created_by, p.updated_at, p.updated_by, p.deleted_at,
 			p.name, p.stock, p.price_amount, p.category_id,
-			COALESCE(c.name, '') as category_name
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
 		FROM core.product p
 		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
 		WHERE p.id = $1
@@ -68,31 +185,52 @@ func (r *ProductRepositoryPostgreSQLImpl) FindProductByID(id string) (model.Prod
 		&product.ID, &product.Version, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy, &product.DeletedAt,
 		&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
 		&product.CategoryName,
+		&product.Type, &product.BundlePricing,
+		&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+		&product.Unit, &product.QuantityScale,
 	)
 	if err != nil {
 		fmt.Println(err)
 		return model.ProductEntity{}, err
 	}
+
+	if product.IsBundle() {
+		components, err := r.findBundleComponents([]uuid.UUID{product.ID})
+		if err != nil {
+			fmt.Println(err)
+			return model.ProductEntity{}, err
+		}
+		product.Components = components[product.ID]
+	}
+	images, err := r.findProductImages([]uuid.UUID{product.ID})
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductEntity{}, err
+	}
+	product.Images = images[product.ID]
 	return product, nil
 }
 
 func (r *ProductRepositoryPostgreSQLImpl) InsertProduct(product model.ProductEntity) (model.ProductEntity, error) {
-	query := `
-		WITH category_lookup AS (
-			SELECT id FROM core.category WHERE lower(name) = lower($5) AND deleted_at IS NULL
-		)
-		INSERT INTO core.product (
-			id, name, stock, price_amount, price_scale, currency, category_id,
-			created_by, updated_by
-		) VALUES (
-			$1, $2, $3, $4, 0, 'IDR', (SELECT id FROM category_lookup), $6, $7
-		)
-	`
-	_, err := r.connPool.Exec(context.Background(), query, product.ID, product.Name, product.Stocks, product.Price, product.CategoryName, product.CreatedBy, product.UpdatedBy)
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
 	if err != nil {
 		fmt.Println(err)
 		return model.ProductEntity{}, err
 	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if err := insertProduct(ctx, conn, product); err != nil {
+		fmt.Println(err)
+		return model.ProductEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ProductEntity{}, err
+	}
 
 	// Supabase buggy when using RETURNING
 	// query := `
@@ -131,24 +269,25 @@ func (r *ProductRepositoryPostgreSQLImpl) InsertProduct(product model.ProductEnt
 }
 
 func (r *ProductRepositoryPostgreSQLImpl) UpdateProductByID(id string, product model.ProductEntity) (model.ProductEntity, error) {
-	query := `
-		WITH category_lookup AS (
-			SELECT id FROM core.category WHERE lower(name) = lower($4) AND deleted_at IS NULL
-		)
-		UPDATE core.product 
-		SET 
-			name = $1, 
-			stock = $2,
-			price_amount = $3,
-			category_id = (SELECT id FROM category_lookup),
-			updated_by = $5
-		WHERE id = $6 AND version = $7 AND deleted_at IS NULL
-	`
-	_, err := r.connPool.Exec(context.Background(), query, product.Name, product.Stocks, product.Price, product.CategoryName, product.UpdatedBy, id, product.Version)
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
 	if err != nil {
 		fmt.Println(err)
 		return model.ProductEntity{}, err
 	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if _, err := updateProduct(ctx, conn, id, product); err != nil {
+		fmt.Println(err)
+		return model.ProductEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ProductEntity{}, err
+	}
 
 	// Supabase buggy when using RETURNING
 	// query := `
@@ -187,13 +326,48 @@ func (r *ProductRepositoryPostgreSQLImpl) UpdateProductByID(id string, product m
 	return updatedProduct, nil
 }
 
+func (r *ProductRepositoryPostgreSQLImpl) FindCategoryProductStats() ([]model.CategoryProductStatsEntity, error) {
+	var stats []model.CategoryProductStatsEntity
+	query := `
+		SELECT
+			category_id,
+			COUNT(*) FILTER (WHERE deleted_at IS NULL),
+			COUNT(*) FILTER (WHERE deleted_at IS NOT NULL),
+			COALESCE(SUM(ROUND(GREATEST(stock, 0) * cost_price_amount / power(10::NUMERIC, quantity_scale)))
+				FILTER (WHERE deleted_at IS NULL AND product_type = 'standard'), 0)::BIGINT
+		FROM core.product
+		WHERE category_id IS NOT NULL
+		GROUP BY category_id
+	`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.CategoryProductStatsEntity
+		if err := rows.Scan(&s.CategoryID, &s.ActiveProducts, &s.InactiveProducts, &s.StockValue); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
 func (r *ProductRepositoryPostgreSQLImpl) FindProductsByNameAndActiveStatus(name string, activeStatus *bool) ([]model.ProductEntity, error) {
 	var products []model.ProductEntity
 	query := `
 		SELECT 
 			p.id, p.created_at, p.created_by, p.updated_at, p.updated_by,
 			p.name, p.stock, p.price_amount, p.category_id,
-			COALESCE(c.name, '') as category_name
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
 		FROM core.product p
 		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
 		WHERE (p.name_tsvector @@ plainto_tsquery('english', $1) 
@@ -221,6 +395,59 @@ func (r *ProductRepositoryPostgreSQLImpl) FindProductsByNameAndActiveStatus(name
 			&product.ID, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy,
 			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
 			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	if err := r.attachBundleComponents(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	if err := r.attachProductImages(products); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindLowStockProducts() ([]model.ProductEntity, error) {
+	var products []model.ProductEntity
+	query := `
+		SELECT 
+			p.id, p.created_at, p.created_by, p.updated_at, p.updated_by,
+			p.name, p.stock, p.price_amount, p.category_id,
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
+		FROM core.product p
+		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
+		WHERE p.deleted_at IS NULL AND p.reorder_point > 0 AND p.stock <= p.reorder_point
+		ORDER BY p.stock - p.reorder_point, p.name
+	`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product model.ProductEntity
+		if err := rows.Scan(
+			&product.ID, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy,
+			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
+			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
 		); err != nil {
 			fmt.Println(err)
 			return nil, err
@@ -239,3 +466,642 @@ func (r *ProductRepositoryPostgreSQLImpl) DeleteProductByID(id string) error {
 	}
 	return nil
 }
+
+// touchProduct marks the product changed for a write to what is pulled along with it, its units, images or
+// prices, so the terminals pull the product again
+func touchProduct(ctx context.Context, conn pgx.Tx, productID string) error {
+	_, err := conn.Exec(ctx, "UPDATE core.product SET updated_at = CURRENT_TIMESTAMP WHERE id = $1", productID)
+	return err
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindProductsChangedSince(since uint64) ([]model.ProductEntity, uint64, error) {
+	var products []model.ProductEntity
+	// every transaction below the oldest one still running has finished, its writes are visible from here on
+	var next uint64
+	err := r.connPool.QueryRow(context.Background(), "SELECT pg_snapshot_xmin(pg_current_snapshot())::text::numeric::bigint").Scan(&next)
+	if err != nil {
+		fmt.Println(err)
+		return nil, 0, err
+	}
+
+	// the bound is inclusive, rows written at the cursor are sent again rather than missed
+	query := `
+		SELECT
+			p.id, p.version, p.change_xid::text::numeric::bigint, p.created_at, p.created_by, p.updated_at, p.updated_by, p.deleted_at,
+			p.name, p.stock, p.price_amount, p.category_id,
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
+		FROM core.product p
+		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
+		WHERE p.change_xid >= $1::text::xid8
+		ORDER BY p.change_xid, p.id
+	`
+	rows, err := r.connPool.Query(context.Background(), query, strconv.FormatUint(since, 10))
+	if err != nil {
+		fmt.Println(err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product model.ProductEntity
+		if err := rows.Scan(
+			&product.ID, &product.Version, &product.ChangeSeq, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy, &product.DeletedAt,
+			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
+			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
+		); err != nil {
+			fmt.Println(err)
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+
+	if err := r.attachBundleComponents(products); err != nil {
+		fmt.Println(err)
+		return nil, 0, err
+	}
+	if err := r.attachProductImages(products); err != nil {
+		fmt.Println(err)
+		return nil, 0, err
+	}
+
+	return products, next, nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) ApplyProductBatch(operations []model.ProductBatchOperationEntity, atomic bool) ([]model.ProductBatchResultEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	results := make([]model.ProductBatchResultEntity, len(operations))
+	failed := false
+	for i, op := range operations {
+		// each operation runs in a savepoint, a failed statement would otherwise abort the whole transaction
+		savepoint, err := conn.Begin(ctx)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		message, err := applyProductOperation(ctx, savepoint, op)
+		if err != nil {
+			fmt.Println(err)
+			message = productWriteError(err)
+		}
+		if message != "" {
+			_ = savepoint.Rollback(ctx)
+			results[i].Error = message
+			failed = true
+			if atomic {
+				return results, nil
+			}
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		results[i].Product = op.Product
+	}
+
+	if atomic && failed {
+		return results, nil
+	}
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	// read the written products back in one query rather than one per operation
+	var ids []uuid.UUID
+	for i, op := range operations {
+		if results[i].Error == "" {
+			ids = append(ids, op.Product.ID)
+		}
+	}
+	written, err := r.findProductsByIDs(ids)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	for i, op := range operations {
+		if product, ok := written[op.Product.ID]; ok && results[i].Error == "" {
+			results[i].Product = product
+		}
+	}
+	return results, nil
+}
+
+// applyProductOperation runs one operation of a batch, the message says why the product was left as it was
+func applyProductOperation(ctx context.Context, conn pgx.Tx, op model.ProductBatchOperationEntity) (string, error) {
+	switch op.Op {
+	case model.ProductBatchCreate:
+		return "", insertProduct(ctx, conn, op.Product)
+	case model.ProductBatchUpdate:
+		updated, err := updateProduct(ctx, conn, op.Product.ID.String(), op.Product)
+		if err != nil || updated {
+			return "", err
+		}
+		return "product not found or changed since version " + strconv.Itoa(op.Product.Version), nil
+	case model.ProductBatchDelete:
+		cmd, err := conn.Exec(ctx, "UPDATE core.product SET deleted_at = NOW(), updated_at = NOW(), updated_by = $1 WHERE id = $2 AND deleted_at IS NULL", op.Product.UpdatedBy, op.Product.ID)
+		if err != nil || cmd.RowsAffected() > 0 {
+			return "", err
+		}
+		return "product not found", nil
+	default:
+		return "unknown operation " + op.Op, nil
+	}
+}
+
+// productWriteError tells the client why the database refused the product without leaking its internals
+func productWriteError(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return "another product already has the sku"
+	}
+	return "the product could not be saved"
+}
+
+// findProductsByIDs loads the products with the given ids, soft-deleted ones included, keyed by id
+func (r *ProductRepositoryPostgreSQLImpl) findProductsByIDs(ids []uuid.UUID) (map[uuid.UUID]model.ProductEntity, error) {
+	found := map[uuid.UUID]model.ProductEntity{}
+	if len(ids) == 0 {
+		return found, nil
+	}
+	query := `
+		SELECT 
+			p.id, p.version, p.created_at, p.created_by, p.updated_at, p.updated_by, p.deleted_at,
+			p.name, p.stock, p.price_amount, p.category_id,
+			COALESCE(c.name, '') as category_name,
+			p.product_type, COALESCE(p.bundle_pricing, ''),
+			p.reorder_point, p.reorder_quantity, p.cost_price_amount, COALESCE(p.sku, ''),
+			p.unit, p.quantity_scale
+		FROM core.product p
+		LEFT JOIN core.category c ON p.category_id = c.id AND c.deleted_at IS NULL
+		WHERE p.id = ANY($1)
+	`
+	rows, err := r.connPool.Query(context.Background(), query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []model.ProductEntity
+	for rows.Next() {
+		var product model.ProductEntity
+		if err := rows.Scan(
+			&product.ID, &product.Version, &product.CreatedAt, &product.CreatedBy, &product.UpdatedAt, &product.UpdatedBy, &product.DeletedAt,
+			&product.Name, &product.Stocks, &product.Price, &product.CategoryID,
+			&product.CategoryName,
+			&product.Type, &product.BundlePricing,
+			&product.ReorderPoint, &product.ReorderQuantity, &product.CostPrice, &product.SKU,
+			&product.Unit, &product.QuantityScale,
+		); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachBundleComponents(products); err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		found[p.ID] = p
+	}
+	return found, nil
+}
+
+// insertProduct writes a new product with its bundle components and books its initial stock
+func insertProduct(ctx context.Context, conn pgx.Tx, product model.ProductEntity) error {
+	query := `
+		INSERT INTO core.product (
+			id, name, stock, price_amount, price_scale, currency, category_id,
+			created_by, updated_by, reorder_point, reorder_quantity, cost_price_amount, sku,
+			unit, quantity_scale
+		) VALUES (
+			$1, $2, $3, $4, 0, 'IDR', $5, $6, $7, $8, $9, $10, NULLIF($11, ''),
+			COALESCE(NULLIF($12, ''), 'pcs'), $13
+		)
+	`
+	// stock starts empty, the initial quantity is booked through the ledger below
+	_, err := conn.Exec(ctx, query, product.ID, product.Name, 0, product.Price, product.CategoryID, product.CreatedBy, product.UpdatedBy, product.ReorderPoint, product.ReorderQuantity, product.CostPrice, product.SKU, product.Unit, product.QuantityScale)
+	if err != nil {
+		return err
+	}
+	if err := adjustStock(ctx, conn, product.ID, product.Stocks, "initial stock", product.CreatedBy); err != nil {
+		return err
+	}
+	return replaceBundle(ctx, conn, product.ID, product)
+}
+
+// updateProduct writes the product when its version still matches and books a stock edit as a delta.
+// It reports false when no live product has the id and version.
+func updateProduct(ctx context.Context, conn pgx.Tx, id string, product model.ProductEntity) (bool, error) {
+	query := `
+		UPDATE core.product 
+		SET 
+			name = $1, 
+			price_amount = $2,
+			category_id = $3,
+			updated_by = $4,
+			reorder_point = $7,
+			reorder_quantity = $8,
+			cost_price_amount = $9,
+			sku = NULLIF($10, ''),
+			unit = COALESCE(NULLIF($11, ''), unit)
+		WHERE id = $5 AND version = $6 AND deleted_at IS NULL
+	`
+	// quantity_scale is left alone, the stock already booked is counted at it
+	// read the current stock before the version moves so the edit can be booked as a delta
+	var currentStock int
+	err := conn.QueryRow(ctx, "SELECT stock FROM core.product WHERE id = $1 AND version = $2 AND deleted_at IS NULL FOR UPDATE", id, product.Version).Scan(&currentStock)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+
+	cmd, err := conn.Exec(ctx, query, product.Name, product.Price, product.CategoryID, product.UpdatedBy, id, product.Version, product.ReorderPoint, product.ReorderQuantity, product.CostPrice, product.SKU, product.Unit)
+	if err != nil {
+		return false, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	productID, err := uuid.Parse(id)
+	if err != nil {
+		return false, err
+	}
+	if err := replaceBundle(ctx, conn, productID, product); err != nil {
+		return false, err
+	}
+	if err := adjustStock(ctx, conn, productID, product.Stocks-currentStock, "stock edited on product update", product.UpdatedBy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// adjustStock books a stock change as an adjustment movement, a zero delta is a no-op
+func adjustStock(ctx context.Context, conn pgx.Tx, productID uuid.UUID, delta int, reason, actor string) error {
+	if delta == 0 {
+		return nil
+	}
+
+	movementID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	_, err = insertStockMovement(ctx, conn, model.StockMovementEntity{
+		ID:        movementID,
+		ProductID: productID,
+		Type:      model.StockMovementAdjustment,
+		Quantity:  delta,
+		Reason:    reason,
+		CreatedBy: actor,
+	})
+	return err
+}
+
+// replaceBundle stores the product type and swaps the bundle components for the given ones
+func replaceBundle(ctx context.Context, conn pgx.Tx, productID uuid.UUID, product model.ProductEntity) error {
+	productType := product.Type
+	if productType == "" {
+		productType = model.ProductTypeStandard
+	}
+	var bundlePricing *string
+	if productType == model.ProductTypeBundle {
+		bundlePricing = &product.BundlePricing
+	}
+
+	_, err := conn.Exec(ctx, "UPDATE core.product SET product_type = $1, bundle_pricing = $2 WHERE id = $3", productType, bundlePricing, productID)
+	if err != nil {
+		return fmt.Errorf("failed to update product type: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, "DELETE FROM core.product_bundle_component WHERE bundle_id = $1", productID)
+	if err != nil {
+		return fmt.Errorf("failed to clear bundle components: %w", err)
+	}
+
+	for _, c := range product.Components {
+		_, err = conn.Exec(ctx,
+			"INSERT INTO core.product_bundle_component (bundle_id, component_id, quantity, created_by) VALUES ($1, $2, $3, $4)",
+			productID, c.ComponentID, c.Quantity, product.UpdatedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert bundle component: %w", err)
+		}
+	}
+	return nil
+}
+
+// findBundleComponents loads the components of the given bundles keyed by bundle id
+func (r *ProductRepositoryPostgreSQLImpl) findBundleComponents(bundleIDs []uuid.UUID) (map[uuid.UUID][]model.BundleComponentEntity, error) {
+	query := `
+		SELECT 
+			b.bundle_id, b.component_id, b.quantity, b.created_at, b.created_by,
+			p.name, p.price_amount, p.stock, p.cost_price_amount
+		FROM core.product_bundle_component b
+		JOIN core.product p ON b.component_id = p.id
+		WHERE b.bundle_id = ANY($1)
+		ORDER BY p.name
+	`
+	rows, err := r.connPool.Query(context.Background(), query, bundleIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := map[uuid.UUID][]model.BundleComponentEntity{}
+	for rows.Next() {
+		var c model.BundleComponentEntity
+		if err := rows.Scan(
+			&c.BundleID, &c.ComponentID, &c.Quantity, &c.CreatedAt, &c.CreatedBy,
+			&c.ComponentName, &c.ComponentPrice, &c.ComponentStocks, &c.ComponentCost,
+		); err != nil {
+			return nil, err
+		}
+		components[c.BundleID] = append(components[c.BundleID], c)
+	}
+	return components, rows.Err()
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) attachBundleComponents(products []model.ProductEntity) error {
+	var bundleIDs []uuid.UUID
+	for _, p := range products {
+		if p.IsBundle() {
+			bundleIDs = append(bundleIDs, p.ID)
+		}
+	}
+	if len(bundleIDs) == 0 {
+		return nil
+	}
+
+	components, err := r.findBundleComponents(bundleIDs)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Components = components[products[i].ID]
+	}
+	return nil
+}
+
+// findProductImages loads the images of the given products by position, keyed by product id
+func (r *ProductRepositoryPostgreSQLImpl) findProductImages(productIDs []uuid.UUID) (map[uuid.UUID][]model.ProductImageEntity, error) {
+	query := `
+		SELECT 
+			id, created_at, created_by, product_id, position, content_type, size_bytes,
+			width, height, key, url, thumbnail_key, thumbnail_url
+		FROM core.product_image
+		WHERE product_id = ANY($1)
+		ORDER BY position, id
+	`
+	rows, err := r.connPool.Query(context.Background(), query, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := map[uuid.UUID][]model.ProductImageEntity{}
+	for rows.Next() {
+		var i model.ProductImageEntity
+		if err := rows.Scan(
+			&i.ID, &i.CreatedAt, &i.CreatedBy, &i.ProductID, &i.Position, &i.ContentType, &i.Size,
+			&i.Width, &i.Height, &i.Key, &i.URL, &i.ThumbnailKey, &i.ThumbnailURL,
+		); err != nil {
+			return nil, err
+		}
+		images[i.ProductID] = append(images[i.ProductID], i)
+	}
+	return images, rows.Err()
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) attachProductImages(products []model.ProductEntity) error {
+	if len(products) == 0 {
+		return nil
+	}
+	productIDs := make([]uuid.UUID, len(products))
+	for i, p := range products {
+		productIDs[i] = p.ID
+	}
+
+	images, err := r.findProductImages(productIDs)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+	}
+	return nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindProductBySKU(sku string) (model.ProductEntity, error) {
+	var id uuid.UUID
+	err := r.connPool.QueryRow(context.Background(), "SELECT id FROM core.product WHERE lower(sku) = lower($1) AND deleted_at IS NULL", sku).Scan(&id)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductEntity{}, err
+	}
+	return r.FindProductByID(id.String())
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindProductUnits(productID string) ([]model.ProductUnitEntity, error) {
+	var units []model.ProductUnitEntity
+	query := `
+		SELECT id, created_at, created_by, product_id, name, factor, price_amount
+		FROM core.product_unit
+		WHERE product_id = $1
+		ORDER BY name
+	`
+	rows, err := r.connPool.Query(context.Background(), query, productID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u model.ProductUnitEntity
+		if err := rows.Scan(&u.ID, &u.CreatedAt, &u.CreatedBy, &u.ProductID, &u.Name, &u.Factor, &u.Price); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		units = append(units, u)
+	}
+
+	return units, nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) FindProductUnitsByProductIDs(productIDs []string) ([]model.ProductUnitEntity, error) {
+	var units []model.ProductUnitEntity
+	query := `
+		SELECT id, created_at, created_by, product_id, name, factor, price_amount
+		FROM core.product_unit
+		WHERE product_id = ANY($1::uuid[])
+		ORDER BY product_id, name
+	`
+	rows, err := r.connPool.Query(context.Background(), query, productIDs)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u model.ProductUnitEntity
+		if err := rows.Scan(&u.ID, &u.CreatedAt, &u.CreatedBy, &u.ProductID, &u.Name, &u.Factor, &u.Price); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		units = append(units, u)
+	}
+
+	return units, nil
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) ReplaceProductUnits(productID string, units []model.ProductUnitEntity) ([]model.ProductUnitEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if _, err := conn.Exec(ctx, "DELETE FROM core.product_unit WHERE product_id = $1", productID); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	query := `
+		INSERT INTO core.product_unit (id, product_id, name, factor, price_amount, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, u := range units {
+		if _, err := conn.Exec(ctx, query, u.ID, u.ProductID, u.Name, u.Factor, u.Price, u.CreatedBy); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+	}
+	if err := touchProduct(ctx, conn, productID); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindProductUnits(productID)
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) InsertProductImage(image model.ProductImageEntity) (model.ProductImageEntity, error) {
+	query := `
+		INSERT INTO core.product_image (
+			id, product_id, position, content_type, size_bytes, width, height,
+			key, url, thumbnail_key, thumbnail_url, created_by
+		) VALUES (
+			$1, $2, (SELECT COALESCE(max(position), 0) + 1 FROM core.product_image WHERE product_id = $2),
+			$3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	_, err = conn.Exec(ctx, query,
+		image.ID, image.ProductID, image.ContentType, image.Size, image.Width, image.Height,
+		image.Key, image.URL, image.ThumbnailKey, image.ThumbnailURL, image.CreatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	if err := touchProduct(ctx, conn, image.ProductID.String()); err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	images, err := r.findProductImages([]uuid.UUID{image.ProductID})
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	for _, i := range images[image.ProductID] {
+		if i.ID == image.ID {
+			return i, nil
+		}
+	}
+	return model.ProductImageEntity{}, pgx.ErrNoRows
+}
+
+func (r *ProductRepositoryPostgreSQLImpl) DeleteProductImage(productID, imageID string) (model.ProductImageEntity, error) {
+	var image model.ProductImageEntity
+	query := `
+		SELECT 
+			id, created_at, created_by, product_id, position, content_type, size_bytes,
+			width, height, key, url, thumbnail_key, thumbnail_url
+		FROM core.product_image
+		WHERE id = $1 AND product_id = $2
+	`
+	err := r.connPool.QueryRow(context.Background(), query, imageID, productID).Scan(
+		&image.ID, &image.CreatedAt, &image.CreatedBy, &image.ProductID, &image.Position, &image.ContentType, &image.Size,
+		&image.Width, &image.Height, &image.Key, &image.URL, &image.ThumbnailKey, &image.ThumbnailURL,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	if _, err := conn.Exec(ctx, "DELETE FROM core.product_image WHERE id = $1", image.ID); err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	if err := touchProduct(ctx, conn, image.ProductID.String()); err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ProductImageEntity{}, err
+	}
+	return image, nil
+}
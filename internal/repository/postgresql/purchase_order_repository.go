@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PurchaseOrderRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewPurchaseOrderRepository(connPool DB) repository.PurchaseOrderRepository {
+	return &PurchaseOrderRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const purchaseOrderColumns = `
+	o.id, o.version, o.created_at, o.created_by, o.updated_at, o.updated_by, o.deleted_at,
+	o.supplier_id, s.name, o.status, COALESCE(o.notes, ''), o.sent_at, o.closed_at
+`
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) FindPurchaseOrders() ([]model.PurchaseOrderEntity, error) {
+	ctx := context.Background()
+	var orders []model.PurchaseOrderEntity
+	query := `
+		SELECT ` + purchaseOrderColumns + `
+		FROM core.purchase_order o
+		JOIN core.supplier s ON o.supplier_id = s.id
+		WHERE o.deleted_at IS NULL
+		ORDER BY o.created_at DESC
+	`
+	rows, err := r.connPool.Query(ctx, query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var o model.PurchaseOrderEntity
+		if err := rows.Scan(
+			&o.ID, &o.Version, &o.CreatedAt, &o.CreatedBy, &o.UpdatedAt, &o.UpdatedBy, &o.DeletedAt,
+			&o.SupplierID, &o.SupplierName, &o.Status, &o.Notes, &o.SentAt, &o.ClosedAt,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		orders = append(orders, o)
+		ids = append(ids, o.ID)
+	}
+	rows.Close()
+
+	lines, err := r.findPurchaseOrderLines(ctx, ids)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Lines = lines[orders[i].ID]
+	}
+
+	return orders, nil
+}
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) FindPurchaseOrderByID(id string) (model.PurchaseOrderEntity, error) {
+	ctx := context.Background()
+	var o model.PurchaseOrderEntity
+	query := `
+		SELECT ` + purchaseOrderColumns + `
+		FROM core.purchase_order o
+		JOIN core.supplier s ON o.supplier_id = s.id
+		WHERE o.id = $1
+	`
+	err := r.connPool.QueryRow(ctx, query, id).Scan(
+		&o.ID, &o.Version, &o.CreatedAt, &o.CreatedBy, &o.UpdatedAt, &o.UpdatedBy, &o.DeletedAt,
+		&o.SupplierID, &o.SupplierName, &o.Status, &o.Notes, &o.SentAt, &o.ClosedAt,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	lines, err := r.findPurchaseOrderLines(ctx, []uuid.UUID{o.ID})
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+	o.Lines = lines[o.ID]
+
+	o.Receipts, err = r.findGoodsReceipts(ctx, o.ID)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	return o, nil
+}
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) InsertPurchaseOrder(order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	_, err = conn.Exec(ctx,
+		"INSERT INTO core.purchase_order (id, supplier_id, status, notes, created_by, updated_by) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)",
+		order.ID, order.SupplierID, order.Status, order.Notes, order.CreatedBy, order.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	if err := insertPurchaseOrderLines(ctx, conn, order.ID, order.Lines); err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindPurchaseOrderByID(order.ID.String())
+}
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) UpdatePurchaseOrderByID(id string, order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE core.purchase_order
+		SET supplier_id = $1, notes = NULLIF($2, ''), updated_by = $3
+		WHERE id = $4 AND version = $5 AND status = 'draft' AND deleted_at IS NULL
+	`
+	cmd, err := conn.Exec(ctx, query, order.SupplierID, order.Notes, order.UpdatedBy, id, order.Version)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	if cmd.RowsAffected() > 0 {
+		orderID, err := uuid.Parse(id)
+		if err != nil {
+			return model.PurchaseOrderEntity{}, err
+		}
+		if _, err := conn.Exec(ctx, "DELETE FROM core.purchase_order_line WHERE purchase_order_id = $1", orderID); err != nil {
+			fmt.Println(err)
+			return model.PurchaseOrderEntity{}, err
+		}
+		if err := insertPurchaseOrderLines(ctx, conn, orderID, order.Lines); err != nil {
+			fmt.Println(err)
+			return model.PurchaseOrderEntity{}, err
+		}
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	return r.FindPurchaseOrderByID(id)
+}
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) UpdatePurchaseOrderStatus(id string, fromStatus, toStatus, actor string) (model.PurchaseOrderEntity, error) {
+	query := `
+		UPDATE core.purchase_order
+		SET status = $1, updated_by = $2,
+			sent_at = CASE WHEN $1 = 'sent' THEN NOW() ELSE sent_at END,
+			closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE closed_at END
+		WHERE id = $3 AND status = $4 AND deleted_at IS NULL
+	`
+	cmd, err := r.connPool.Exec(context.Background(), query, toStatus, actor, id, fromStatus)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return model.PurchaseOrderEntity{}, fmt.Errorf("purchase order is not %s", fromStatus)
+	}
+
+	return r.FindPurchaseOrderByID(id)
+}
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) ReceiveGoods(id string, receipt model.GoodsReceiptEntity) (model.PurchaseOrderEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// lock the order so two deliveries booked at once cannot both see the same outstanding quantity
+	var status string
+	err = conn.QueryRow(ctx, "SELECT status FROM core.purchase_order WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&status)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+	if status != model.PurchaseOrderSent && status != model.PurchaseOrderPartiallyReceived {
+		return model.PurchaseOrderEntity{}, fmt.Errorf("purchase order is %s, goods can only be received once sent", status)
+	}
+
+	_, err = conn.Exec(ctx,
+		"INSERT INTO core.goods_receipt (id, purchase_order_id, outlet_id, notes, received_by) VALUES ($1, $2, $3, NULLIF($4, ''), $5)",
+		receipt.ID, receipt.PurchaseOrderID, receipt.OutletID, receipt.Notes, receipt.ReceivedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	receiveQuery := `
+		UPDATE core.purchase_order_line
+		SET quantity_received = quantity_received + $1
+		WHERE purchase_order_id = $2 AND product_id = $3 AND quantity_received + $1 <= quantity_ordered
+	`
+	for _, line := range receipt.Lines {
+		cmd, err := conn.Exec(ctx, receiveQuery, line.Quantity, receipt.PurchaseOrderID, line.ProductID)
+		if err != nil {
+			fmt.Println(err)
+			return model.PurchaseOrderEntity{}, err
+		}
+		if cmd.RowsAffected() == 0 {
+			return model.PurchaseOrderEntity{}, fmt.Errorf("product %s is not on this purchase order or exceeds the outstanding quantity", line.ProductID)
+		}
+
+		// moving average cost, weighted with the stock on hand before this receipt lands
+		costQuery := `
+			UPDATE core.product p
+			SET cost_price_amount = CASE
+				WHEN GREATEST(p.stock, 0) + $2 <= 0 THEN l.cost_price_amount
+				ELSE ROUND((GREATEST(p.stock, 0)::numeric * p.cost_price_amount + $2::numeric * l.cost_price_amount) / (GREATEST(p.stock, 0) + $2))::BIGINT
+			END
+			FROM core.purchase_order_line l
+			WHERE p.id = $1 AND l.purchase_order_id = $3 AND l.product_id = $1
+		`
+		if _, err := conn.Exec(ctx, costQuery, line.ProductID, line.Quantity, receipt.PurchaseOrderID); err != nil {
+			fmt.Println(err)
+			return model.PurchaseOrderEntity{}, err
+		}
+
+		_, err = conn.Exec(ctx,
+			"INSERT INTO core.goods_receipt_line (goods_receipt_id, product_id, quantity) VALUES ($1, $2, $3)",
+			receipt.ID, line.ProductID, line.Quantity,
+		)
+		if err != nil {
+			fmt.Println(err)
+			return model.PurchaseOrderEntity{}, err
+		}
+
+		movementID, _ := uuid.NewV7()
+		_, err = insertStockMovement(ctx, conn, model.StockMovementEntity{
+			ID:          movementID,
+			ProductID:   line.ProductID,
+			Type:        model.StockMovementPurchaseReceipt,
+			Quantity:    line.Quantity,
+			Reason:      "goods receipt",
+			ReferenceID: &receipt.ID,
+			OutletID:    receipt.OutletID,
+			CreatedBy:   receipt.ReceivedBy,
+		})
+		if err != nil {
+			fmt.Println(err)
+			return model.PurchaseOrderEntity{}, err
+		}
+	}
+
+	var outstanding int
+	err = conn.QueryRow(ctx,
+		"SELECT COALESCE(SUM(quantity_ordered - quantity_received), 0) FROM core.purchase_order_line WHERE purchase_order_id = $1",
+		receipt.PurchaseOrderID,
+	).Scan(&outstanding)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	nextStatus := model.PurchaseOrderPartiallyReceived
+	if outstanding == 0 {
+		nextStatus = model.PurchaseOrderClosed
+	}
+	_, err = conn.Exec(ctx, `
+		UPDATE core.purchase_order
+		SET status = $1, updated_by = $2,
+			closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE closed_at END
+		WHERE id = $3
+	`, nextStatus, receipt.ReceivedBy, receipt.PurchaseOrderID)
+	if err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.PurchaseOrderEntity{}, err
+	}
+
+	return r.FindPurchaseOrderByID(id)
+}
+
+func insertPurchaseOrderLines(ctx context.Context, conn pgx.Tx, orderID uuid.UUID, lines []model.PurchaseOrderLineEntity) error {
+	query := `
+		INSERT INTO core.purchase_order_line (purchase_order_id, product_id, quantity_ordered, cost_price_amount)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, line := range lines {
+		if _, err := conn.Exec(ctx, query, orderID, line.ProductID, line.QuantityOrdered, line.CostPrice); err != nil {
+			return fmt.Errorf("failed to insert purchase order line: %w", err)
+		}
+	}
+	return nil
+}
+
+// findPurchaseOrderLines loads the lines of several orders in one query, keyed by order id
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) findPurchaseOrderLines(ctx context.Context, orderIDs []uuid.UUID) (map[uuid.UUID][]model.PurchaseOrderLineEntity, error) {
+	lines := map[uuid.UUID][]model.PurchaseOrderLineEntity{}
+	if len(orderIDs) == 0 {
+		return lines, nil
+	}
+
+	query := `
+		SELECT l.purchase_order_id, l.product_id, p.name, l.quantity_ordered, l.quantity_received, l.cost_price_amount
+		FROM core.purchase_order_line l
+		JOIN core.product p ON l.product_id = p.id
+		WHERE l.purchase_order_id = ANY($1)
+		ORDER BY p.name
+	`
+	rows, err := r.connPool.Query(ctx, query, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l model.PurchaseOrderLineEntity
+		if err := rows.Scan(&l.PurchaseOrderID, &l.ProductID, &l.ProductName, &l.QuantityOrdered, &l.QuantityReceived, &l.CostPrice); err != nil {
+			return nil, err
+		}
+		lines[l.PurchaseOrderID] = append(lines[l.PurchaseOrderID], l)
+	}
+	return lines, nil
+}
+
+func (r *PurchaseOrderRepositoryPostgreSQLImpl) findGoodsReceipts(ctx context.Context, orderID uuid.UUID) ([]model.GoodsReceiptEntity, error) {
+	query := `
+		SELECT g.id, g.purchase_order_id, g.outlet_id, COALESCE(o.name, ''), COALESCE(g.notes, ''), g.received_at, g.received_by,
+			l.product_id, p.name, l.quantity
+		FROM core.goods_receipt g
+		JOIN core.goods_receipt_line l ON l.goods_receipt_id = g.id
+		JOIN core.product p ON l.product_id = p.id
+		LEFT JOIN core.outlet o ON g.outlet_id = o.id
+		WHERE g.purchase_order_id = $1
+		ORDER BY g.received_at, g.id, p.name
+	`
+	rows, err := r.connPool.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []model.GoodsReceiptEntity
+	for rows.Next() {
+		var g model.GoodsReceiptEntity
+		var l model.GoodsReceiptLineEntity
+		if err := rows.Scan(&g.ID, &g.PurchaseOrderID, &g.OutletID, &g.OutletName, &g.Notes, &g.ReceivedAt, &g.ReceivedBy, &l.ProductID, &l.ProductName, &l.Quantity); err != nil {
+			return nil, err
+		}
+		l.GoodsReceiptID = g.ID
+		if n := len(receipts); n > 0 && receipts[n-1].ID == g.ID {
+			receipts[n-1].Lines = append(receipts[n-1].Lines, l)
+			continue
+		}
+		g.Lines = []model.GoodsReceiptLineEntity{l}
+		receipts = append(receipts, g)
+	}
+	return receipts, nil
+}
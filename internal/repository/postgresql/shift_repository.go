@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ShiftRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewShiftRepository(connPool DB) repository.ShiftRepository {
+	return &ShiftRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const shiftSelect = `
+	SELECT
+		s.id, s.version, s.created_at, s.created_by, s.updated_at, s.updated_by,
+		s.cashier_name, s.outlet_id, COALESCE(o.name, ''), s.status, s.opening_float, COALESCE(s.notes, ''),
+		s.closed_at, COALESCE(s.closed_by, ''), s.expected_cash, s.counted_cash
+	FROM core.cashier_shift s
+	LEFT JOIN core.outlet o ON s.outlet_id = o.id
+`
+
+func scanShift(row pgx.Row) (model.ShiftEntity, error) {
+	var s model.ShiftEntity
+	err := row.Scan(
+		&s.ID, &s.Version, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt, &s.UpdatedBy,
+		&s.CashierName, &s.OutletID, &s.OutletName, &s.Status, &s.OpeningFloat, &s.Notes,
+		&s.ClosedAt, &s.ClosedBy, &s.ExpectedCash, &s.CountedCash,
+	)
+	return s, err
+}
+
+func (r *ShiftRepositoryPostgreSQLImpl) FindShifts(status string) ([]model.ShiftEntity, error) {
+	ctx := context.Background()
+	var shifts []model.ShiftEntity
+	rows, err := r.connPool.Query(ctx, shiftSelect+` WHERE ($1 = '' OR s.status = $1) ORDER BY s.created_at DESC`, status)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		s, err := scanShift(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		shifts = append(shifts, s)
+		ids = append(ids, s.ID)
+	}
+	rows.Close()
+
+	entries, err := findCashEntries(ctx, r.connPool, ids)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	for i := range shifts {
+		shifts[i].CashEntries = entries[shifts[i].ID]
+	}
+
+	return shifts, nil
+}
+
+func (r *ShiftRepositoryPostgreSQLImpl) FindShiftByID(id string) (model.ShiftEntity, error) {
+	return findShift(context.Background(), r.connPool, shiftSelect+` WHERE s.id = $1`, id)
+}
+
+func (r *ShiftRepositoryPostgreSQLImpl) FindOpenShiftByCashier(cashierName string) (model.ShiftEntity, error) {
+	s, err := findShift(context.Background(), r.connPool, shiftSelect+` WHERE s.cashier_name = $1 AND s.status = 'open'`, cashierName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ShiftEntity{}, nil
+	}
+	return s, err
+}
+
+// findShift loads one shift with its cash entries, conn is the pool or the transaction closing the shift
+func findShift(ctx context.Context, conn DB, query string, args ...any) (model.ShiftEntity, error) {
+	s, err := scanShift(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+
+	entries, err := findCashEntries(ctx, conn, []uuid.UUID{s.ID})
+	if err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+	s.CashEntries = entries[s.ID]
+
+	return s, nil
+}
+
+func findCashEntries(ctx context.Context, conn DB, shiftIDs []uuid.UUID) (map[uuid.UUID][]model.CashEntryEntity, error) {
+	entries := map[uuid.UUID][]model.CashEntryEntity{}
+	if len(shiftIDs) == 0 {
+		return entries, nil
+	}
+	query := `
+		SELECT id, shift_id, entry_type, amount, reason, created_at, created_by
+		FROM core.cash_drawer_entry
+		WHERE shift_id = ANY($1)
+		ORDER BY created_at, id
+	`
+	rows, err := conn.Query(ctx, query, shiftIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.CashEntryEntity
+		if err := rows.Scan(&e.ID, &e.ShiftID, &e.Type, &e.Amount, &e.Reason, &e.CreatedAt, &e.CreatedBy); err != nil {
+			return nil, err
+		}
+		entries[e.ShiftID] = append(entries[e.ShiftID], e)
+	}
+	return entries, nil
+}
+
+func (r *ShiftRepositoryPostgreSQLImpl) InsertShift(shift model.ShiftEntity) (model.ShiftEntity, error) {
+	query := `
+		INSERT INTO core.cashier_shift (
+			id, cashier_name, outlet_id, status, opening_float, notes, created_by, updated_by
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		shift.ID, shift.CashierName, shift.OutletID, shift.Status, shift.OpeningFloat, shift.Notes,
+		shift.CreatedBy, shift.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindShiftByID(shift.ID.String())
+}
+
+func (r *ShiftRepositoryPostgreSQLImpl) InsertCashEntry(entry model.CashEntryEntity) (model.CashEntryEntity, error) {
+	ctx := context.Background()
+	// trg_cash_drawer_entry_require_open_shift rejects the entry once the shift is closed
+	query := `
+		INSERT INTO core.cash_drawer_entry (id, shift_id, entry_type, amount, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.connPool.Exec(ctx, query, entry.ID, entry.ShiftID, entry.Type, entry.Amount, entry.Reason, entry.CreatedBy)
+	if err != nil {
+		fmt.Println(err)
+		return model.CashEntryEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	err = r.connPool.QueryRow(ctx, "SELECT created_at FROM core.cash_drawer_entry WHERE id = $1", entry.ID).Scan(&entry.CreatedAt)
+	if err != nil {
+		fmt.Println(err)
+		return model.CashEntryEntity{}, err
+	}
+	return entry, nil
+}
+
+func (r *ShiftRepositoryPostgreSQLImpl) CloseShift(id string, countedCash int64, notes string, actor string) (model.ShiftEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// the row lock waits for sales and cash entries still booking on the shift, they hold a share lock on it
+	shift, err := findShift(ctx, conn, shiftSelect+` WHERE s.id = $1 FOR UPDATE OF s`, id)
+	if err != nil {
+		return model.ShiftEntity{}, err
+	}
+	if shift.Status != model.ShiftOpen {
+		return model.ShiftEntity{}, fmt.Errorf("shift is not %s", model.ShiftOpen)
+	}
+	sales, err := shiftSales(ctx, conn, id)
+	if err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+	expected := model.NewShiftReport(shift, sales).ExpectedCash.Amount
+
+	query := `
+		UPDATE core.cashier_shift
+		SET status = $1, closed_at = NOW(), closed_by = $2, updated_by = $2,
+			expected_cash = $3, counted_cash = $4, notes = COALESCE(NULLIF($5, ''), notes)
+		WHERE id = $6
+	`
+	if _, err := conn.Exec(ctx, query, model.ShiftClosed, actor, expected, countedCash, notes, id); err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.ShiftEntity{}, err
+	}
+
+	return r.FindShiftByID(id)
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type StockConflictRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewStockConflictRepository(connPool DB) repository.StockConflictRepository {
+	return &StockConflictRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const stockConflictSelect = `
+	SELECT
+		id, created_at, created_by, transaction_id, product_id, product_name, outlet_id, quantity,
+		resolved_at, COALESCE(resolved_by, ''), COALESCE(resolution_notes, '')
+	FROM core.stock_conflict
+`
+
+func scanStockConflict(row pgx.Row) (model.StockConflictEntity, error) {
+	var c model.StockConflictEntity
+	err := row.Scan(
+		&c.ID, &c.CreatedAt, &c.CreatedBy, &c.TransactionID, &c.ProductID, &c.ProductName, &c.OutletID, &c.Quantity,
+		&c.ResolvedAt, &c.ResolvedBy, &c.ResolutionNotes,
+	)
+	return c, err
+}
+
+func (r *StockConflictRepositoryPostgreSQLImpl) FindStockConflicts(status string) ([]model.StockConflictEntity, error) {
+	var conflicts []model.StockConflictEntity
+	query := stockConflictSelect + `
+		WHERE $1 = ''
+			OR ($1 = 'open' AND resolved_at IS NULL)
+			OR ($1 = 'resolved' AND resolved_at IS NOT NULL)
+		ORDER BY created_at DESC
+	`
+	rows, err := r.connPool.Query(context.Background(), query, status)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanStockConflict(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, nil
+}
+
+func (r *StockConflictRepositoryPostgreSQLImpl) FindStockConflictByID(id string) (model.StockConflictEntity, error) {
+	c, err := scanStockConflict(r.connPool.QueryRow(context.Background(), stockConflictSelect+` WHERE id = $1`, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.StockConflictEntity{}, err
+	}
+	return c, nil
+}
+
+func (r *StockConflictRepositoryPostgreSQLImpl) ResolveStockConflict(conflict model.StockConflictEntity) (model.StockConflictEntity, error) {
+	query := `
+		UPDATE core.stock_conflict
+		SET resolved_at = CURRENT_TIMESTAMP, resolved_by = $1, resolution_notes = NULLIF($2, '')
+		WHERE id = $3 AND resolved_at IS NULL
+	`
+	tag, err := r.connPool.Exec(context.Background(), query, conflict.ResolvedBy, conflict.ResolutionNotes, conflict.ID)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockConflictEntity{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.StockConflictEntity{}, fmt.Errorf("stock conflict %s is already resolved", conflict.ID)
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindStockConflictByID(conflict.ID.String())
+}
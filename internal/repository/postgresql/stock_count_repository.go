@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type StockCountRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewStockCountRepository(connPool DB) repository.StockCountRepository {
+	return &StockCountRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const stockCountColumns = `
+	s.id, s.version, s.created_at, s.created_by, s.updated_at, s.updated_by, s.deleted_at,
+	s.status, s.category_id, COALESCE(c.name, ''), s.outlet_id, COALESCE(o.name, ''), COALESCE(s.notes, ''),
+	s.submitted_at, s.approved_at, COALESCE(s.approved_by, '')
+`
+
+func (r *StockCountRepositoryPostgreSQLImpl) FindStockCounts() ([]model.StockCountEntity, error) {
+	var counts []model.StockCountEntity
+	query := `
+		SELECT ` + stockCountColumns + `
+		FROM core.stock_count s
+		LEFT JOIN core.category c ON s.category_id = c.id
+		LEFT JOIN core.outlet o ON s.outlet_id = o.id
+		WHERE s.deleted_at IS NULL
+		ORDER BY s.created_at DESC
+	`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count model.StockCountEntity
+		if err := rows.Scan(
+			&count.ID, &count.Version, &count.CreatedAt, &count.CreatedBy, &count.UpdatedAt, &count.UpdatedBy, &count.DeletedAt,
+			&count.Status, &count.CategoryID, &count.CategoryName, &count.OutletID, &count.OutletName, &count.Notes,
+			&count.SubmittedAt, &count.ApprovedAt, &count.ApprovedBy,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+func (r *StockCountRepositoryPostgreSQLImpl) FindStockCountByID(id string) (model.StockCountEntity, error) {
+	ctx := context.Background()
+	var count model.StockCountEntity
+	query := `
+		SELECT ` + stockCountColumns + `
+		FROM core.stock_count s
+		LEFT JOIN core.category c ON s.category_id = c.id
+		LEFT JOIN core.outlet o ON s.outlet_id = o.id
+		WHERE s.id = $1
+	`
+	err := r.connPool.QueryRow(ctx, query, id).Scan(
+		&count.ID, &count.Version, &count.CreatedAt, &count.CreatedBy, &count.UpdatedAt, &count.UpdatedBy, &count.DeletedAt,
+		&count.Status, &count.CategoryID, &count.CategoryName, &count.OutletID, &count.OutletName, &count.Notes,
+		&count.SubmittedAt, &count.ApprovedAt, &count.ApprovedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	linesQuery := `
+		SELECT 
+			l.stock_count_id, l.product_id, p.name, p.price_amount,
+			COALESCE(l.system_stock, CASE WHEN s.outlet_id IS NULL THEN p.stock ELSE COALESCE(os.stock, 0) END),
+			l.counted_quantity, l.counted_at, COALESCE(l.counted_by, '')
+		FROM core.stock_count_line l
+		JOIN core.stock_count s ON l.stock_count_id = s.id
+		JOIN core.product p ON l.product_id = p.id
+		LEFT JOIN core.outlet_stock os ON os.outlet_id = s.outlet_id AND os.product_id = l.product_id
+		WHERE l.stock_count_id = $1
+		ORDER BY p.name
+	`
+	rows, err := r.connPool.Query(ctx, linesQuery, id)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line model.StockCountLineEntity
+		if err := rows.Scan(
+			&line.StockCountID, &line.ProductID, &line.ProductName, &line.ProductPrice,
+			&line.SystemStock, &line.CountedQuantity, &line.CountedAt, &line.CountedBy,
+		); err != nil {
+			fmt.Println(err)
+			return model.StockCountEntity{}, err
+		}
+		count.Lines = append(count.Lines, line)
+	}
+
+	return count, nil
+}
+
+func (r *StockCountRepositoryPostgreSQLImpl) InsertStockCount(count model.StockCountEntity) (model.StockCountEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	_, err = conn.Exec(ctx,
+		"INSERT INTO core.stock_count (id, status, category_id, outlet_id, notes, created_by, updated_by) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)",
+		count.ID, count.Status, count.CategoryID, count.OutletID, count.Notes, count.CreatedBy, count.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	// bundles hold no stock of their own so there is nothing to count
+	linesQuery := `
+		INSERT INTO core.stock_count_line (stock_count_id, product_id)
+		SELECT $1, p.id
+		FROM core.product p
+		WHERE p.deleted_at IS NULL
+		  AND p.product_type = 'standard'
+		  AND ($2::uuid IS NULL OR p.category_id = $2)
+	`
+	_, err = conn.Exec(ctx, linesQuery, count.ID, count.CategoryID)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	return r.FindStockCountByID(count.ID.String())
+}
+
+func (r *StockCountRepositoryPostgreSQLImpl) UpdateStockCountLines(id string, lines []model.StockCountLineEntity) (model.StockCountEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	// lock the header so a concurrent submit cannot slip in between the lines
+	var status string
+	err = conn.QueryRow(ctx, "SELECT status FROM core.stock_count WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	if status != model.StockCountOpen {
+		return model.StockCountEntity{}, fmt.Errorf("stock count is %s, counts can only be entered while open", status)
+	}
+
+	query := `
+		UPDATE core.stock_count_line
+		SET counted_quantity = $1, counted_at = NOW(), counted_by = $2
+		WHERE stock_count_id = $3 AND product_id = $4
+	`
+	for _, line := range lines {
+		cmd, err := conn.Exec(ctx, query, line.CountedQuantity, line.CountedBy, id, line.ProductID)
+		if err != nil {
+			fmt.Println(err)
+			return model.StockCountEntity{}, err
+		}
+		if cmd.RowsAffected() == 0 {
+			return model.StockCountEntity{}, fmt.Errorf("product %s is not part of this stock count", line.ProductID)
+		}
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	return r.FindStockCountByID(id)
+}
+
+func (r *StockCountRepositoryPostgreSQLImpl) UpdateStockCountStatus(id string, fromStatus, toStatus, actor string) (model.StockCountEntity, error) {
+	query := `
+		UPDATE core.stock_count
+		SET status = $1, updated_by = $2,
+			submitted_at = CASE WHEN $1 = 'submitted' THEN NOW() ELSE submitted_at END
+		WHERE id = $3 AND status = $4 AND deleted_at IS NULL
+	`
+	cmd, err := r.connPool.Exec(context.Background(), query, toStatus, actor, id, fromStatus)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return model.StockCountEntity{}, fmt.Errorf("stock count is not %s", fromStatus)
+	}
+
+	return r.FindStockCountByID(id)
+}
+
+func (r *StockCountRepositoryPostgreSQLImpl) PostStockCount(id string, actor string) (model.StockCountEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	var countID uuid.UUID
+	var outletID *uuid.UUID
+	err = conn.QueryRow(ctx, "SELECT id, outlet_id FROM core.stock_count WHERE id = $1 AND status = $2 FOR UPDATE", id, model.StockCountSubmitted).Scan(&countID, &outletID)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, fmt.Errorf("stock count is not %s: %w", model.StockCountSubmitted, err)
+	}
+
+	// freeze the system stock under a row lock, the variance is measured against it. Every movement updates the
+	// product row, so its lock also holds the outlet's stock still for a count taken at an outlet
+	freezeQuery := `
+		UPDATE core.stock_count_line l
+		SET system_stock = CASE WHEN $2::uuid IS NULL THEN p.stock ELSE COALESCE(
+			(SELECT os.stock FROM core.outlet_stock os WHERE os.outlet_id = $2 AND os.product_id = p.id), 0) END
+		FROM (
+			SELECT id, stock FROM core.product
+			WHERE id IN (SELECT product_id FROM core.stock_count_line WHERE stock_count_id = $1 AND counted_quantity IS NOT NULL)
+			FOR UPDATE
+		) p
+		WHERE l.stock_count_id = $1 AND l.product_id = p.id
+	`
+	if _, err := conn.Exec(ctx, freezeQuery, countID, outletID); err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	rows, err := conn.Query(ctx, `
+		SELECT product_id, counted_quantity - system_stock
+		FROM core.stock_count_line
+		WHERE stock_count_id = $1 AND counted_quantity IS NOT NULL AND counted_quantity <> system_stock
+	`, countID)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+	var variances []model.StockMovementEntity
+	for rows.Next() {
+		var m model.StockMovementEntity
+		if err := rows.Scan(&m.ProductID, &m.Quantity); err != nil {
+			rows.Close()
+			fmt.Println(err)
+			return model.StockCountEntity{}, err
+		}
+		variances = append(variances, m)
+	}
+	rows.Close()
+
+	for _, m := range variances {
+		m.ID, _ = uuid.NewV7()
+		m.Type = model.StockMovementAdjustment
+		m.Reason = "stock count"
+		m.ReferenceID = &countID
+		m.OutletID = outletID
+		m.CreatedBy = actor
+		if _, err := insertStockMovement(ctx, conn, m); err != nil {
+			fmt.Println(err)
+			return model.StockCountEntity{}, err
+		}
+	}
+
+	_, err = conn.Exec(ctx,
+		"UPDATE core.stock_count SET status = $1, approved_at = NOW(), approved_by = $2, updated_by = $2 WHERE id = $3",
+		model.StockCountPosted, actor, countID,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.StockCountEntity{}, err
+	}
+
+	return r.FindStockCountByID(id)
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type StockMovementRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewStockMovementRepository(connPool DB) repository.StockMovementRepository {
+	return &StockMovementRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+func (r *StockMovementRepositoryPostgreSQLImpl) FindStockMovementsByProductID(productID string) ([]model.StockMovementEntity, error) {
+	var movements []model.StockMovementEntity
+	query := `
+		SELECT 
+			id, product_id, movement_type, quantity, balance_after,
+			COALESCE(reason, ''), reference_id, created_at, created_by, lot_id, outlet_id
+		FROM core.stock_movement
+		WHERE product_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	rows, err := r.connPool.Query(context.Background(), query, productID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m model.StockMovementEntity
+		if err := rows.Scan(
+			&m.ID, &m.ProductID, &m.Type, &m.Quantity, &m.BalanceAfter,
+			&m.Reason, &m.ReferenceID, &m.CreatedAt, &m.CreatedBy, &m.LotID, &m.OutletID,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, nil
+}
+
+func (r *StockMovementRepositoryPostgreSQLImpl) InsertStockMovement(movement model.StockMovementEntity) (model.StockMovementEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockMovementEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	inserted, err := insertStockMovement(ctx, conn, movement)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockMovementEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.StockMovementEntity{}, err
+	}
+	return inserted, nil
+}
+
+func (r *StockMovementRepositoryPostgreSQLImpl) GetStockReconciliation(productID string) (model.StockReconciliationEntity, error) {
+	var reconciliation model.StockReconciliationEntity
+	query := `SELECT product_id, stock, ledger_balance FROM core.v_stock_reconciliation WHERE product_id = $1`
+	err := r.connPool.QueryRow(context.Background(), query, productID).Scan(
+		&reconciliation.ProductID, &reconciliation.Stock, &reconciliation.LedgerBalance,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockReconciliationEntity{}, err
+	}
+	return reconciliation, nil
+}
+
+// insertStockMovement appends a movement to the ledger inside the caller's transaction.
+// trg_stock_movement_apply moves core.product.stock and fills in balance_after,
+// trg_stock_movement_lot and trg_stock_movement_outlet move the lot and outlet stock when the movement names them.
+func insertStockMovement(ctx context.Context, conn pgx.Tx, movement model.StockMovementEntity) (model.StockMovementEntity, error) {
+	query := `
+		INSERT INTO core.stock_movement (
+			id, product_id, movement_type, quantity, reason, reference_id, created_by, lot_id, outlet_id
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)
+	`
+	_, err := conn.Exec(ctx, query,
+		movement.ID, movement.ProductID, movement.Type, movement.Quantity,
+		movement.Reason, movement.ReferenceID, movement.CreatedBy, movement.LotID, movement.OutletID,
+	)
+	if err != nil {
+		return model.StockMovementEntity{}, fmt.Errorf("failed to insert stock movement: %w", err)
+	}
+
+	// Supabase buggy when using RETURNING
+	err = conn.QueryRow(ctx, "SELECT balance_after, created_at FROM core.stock_movement WHERE id = $1", movement.ID).
+		Scan(&movement.BalanceAfter, &movement.CreatedAt)
+	if err != nil {
+		return model.StockMovementEntity{}, fmt.Errorf("failed to read stock movement: %w", err)
+	}
+	return movement, nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type StockTransferRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewStockTransferRepository(connPool DB) repository.StockTransferRepository {
+	return &StockTransferRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const stockTransferSelect = `
+	SELECT
+		t.id, t.version, t.created_at, t.created_by, t.updated_at, t.updated_by, t.deleted_at,
+		t.from_outlet_id, f.name, t.to_outlet_id, d.name, t.status, COALESCE(t.notes, ''),
+		t.received_at, COALESCE(t.received_by, ''), t.cancelled_at
+	FROM core.stock_transfer t
+	JOIN core.outlet f ON t.from_outlet_id = f.id
+	JOIN core.outlet d ON t.to_outlet_id = d.id
+`
+
+func scanStockTransfer(row pgx.Row) (model.StockTransferEntity, error) {
+	var t model.StockTransferEntity
+	err := row.Scan(
+		&t.ID, &t.Version, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeletedAt,
+		&t.FromOutletID, &t.FromOutletName, &t.ToOutletID, &t.ToOutletName, &t.Status, &t.Notes,
+		&t.ReceivedAt, &t.ReceivedBy, &t.CancelledAt,
+	)
+	return t, err
+}
+
+func (r *StockTransferRepositoryPostgreSQLImpl) FindStockTransfers() ([]model.StockTransferEntity, error) {
+	ctx := context.Background()
+	var transfers []model.StockTransferEntity
+	rows, err := r.connPool.Query(ctx, stockTransferSelect+` WHERE t.deleted_at IS NULL ORDER BY t.created_at DESC`)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		t, err := scanStockTransfer(rows)
+		if err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		transfers = append(transfers, t)
+		ids = append(ids, t.ID)
+	}
+	rows.Close()
+
+	lines, err := r.findStockTransferLines(ctx, ids)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	for i := range transfers {
+		transfers[i].Lines = lines[transfers[i].ID]
+	}
+
+	return transfers, nil
+}
+
+func (r *StockTransferRepositoryPostgreSQLImpl) FindStockTransferByID(id string) (model.StockTransferEntity, error) {
+	ctx := context.Background()
+	t, err := scanStockTransfer(r.connPool.QueryRow(ctx, stockTransferSelect+` WHERE t.id = $1`, id))
+	if err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+
+	lines, err := r.findStockTransferLines(ctx, []uuid.UUID{t.ID})
+	if err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+	t.Lines = lines[t.ID]
+
+	return t, nil
+}
+
+func (r *StockTransferRepositoryPostgreSQLImpl) InsertStockTransfer(transfer model.StockTransferEntity) (model.StockTransferEntity, error) {
+	ctx := context.Background()
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	_, err = conn.Exec(ctx,
+		"INSERT INTO core.stock_transfer (id, from_outlet_id, to_outlet_id, status, notes, created_by, updated_by) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)",
+		transfer.ID, transfer.FromOutletID, transfer.ToOutletID, transfer.Status, transfer.Notes, transfer.CreatedBy, transfer.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+
+	for _, line := range transfer.Lines {
+		_, err = conn.Exec(ctx,
+			"INSERT INTO core.stock_transfer_line (stock_transfer_id, product_id, quantity) VALUES ($1, $2, $3)",
+			transfer.ID, line.ProductID, line.Quantity,
+		)
+		if err != nil {
+			fmt.Println(err)
+			return model.StockTransferEntity{}, err
+		}
+	}
+
+	// outlet_stock_not_negative fails the dispatch when the source outlet holds too little
+	if err := moveTransferStock(ctx, conn, transfer, transfer.FromOutletID, -1, "dispatched", transfer.CreatedBy); err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindStockTransferByID(transfer.ID.String())
+}
+
+func (r *StockTransferRepositoryPostgreSQLImpl) ReceiveStockTransfer(id string, actor string) (model.StockTransferEntity, error) {
+	return r.completeStockTransfer(id, model.StockTransferReceived, actor)
+}
+
+func (r *StockTransferRepositoryPostgreSQLImpl) CancelStockTransfer(id string, actor string) (model.StockTransferEntity, error) {
+	return r.completeStockTransfer(id, model.StockTransferCancelled, actor)
+}
+
+// completeStockTransfer lands the stock in transit, at the destination when received or back at the source when cancelled
+func (r *StockTransferRepositoryPostgreSQLImpl) completeStockTransfer(id string, toStatus string, actor string) (model.StockTransferEntity, error) {
+	ctx := context.Background()
+	transfer, err := r.FindStockTransferByID(id)
+	if err != nil {
+		return model.StockTransferEntity{}, err
+	}
+
+	conn, err := r.connPool.Begin(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+	defer func() {
+		_ = conn.Rollback(ctx)
+	}()
+
+	query := `
+		UPDATE core.stock_transfer
+		SET status = $1, updated_by = $2,
+			received_at = CASE WHEN $1 = 'received' THEN NOW() ELSE received_at END,
+			received_by = CASE WHEN $1 = 'received' THEN $2 ELSE received_by END,
+			cancelled_at = CASE WHEN $1 = 'cancelled' THEN NOW() ELSE cancelled_at END
+		WHERE id = $3 AND status = $4 AND deleted_at IS NULL
+	`
+	cmd, err := conn.Exec(ctx, query, toStatus, actor, id, model.StockTransferInTransit)
+	if err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return model.StockTransferEntity{}, fmt.Errorf("stock transfer is not %s", model.StockTransferInTransit)
+	}
+
+	outletID, reason := transfer.ToOutletID, "received"
+	if toStatus == model.StockTransferCancelled {
+		outletID, reason = transfer.FromOutletID, "cancelled"
+	}
+	if err := moveTransferStock(ctx, conn, transfer, outletID, 1, reason, actor); err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+
+	if err := conn.Commit(ctx); err != nil {
+		fmt.Println(err)
+		return model.StockTransferEntity{}, err
+	}
+
+	return r.FindStockTransferByID(id)
+}
+
+// moveTransferStock books one transfer movement per line at the outlet, sign -1 takes the stock out
+func moveTransferStock(ctx context.Context, conn pgx.Tx, transfer model.StockTransferEntity, outletID uuid.UUID, sign int, reason string, actor string) error {
+	for _, line := range transfer.Lines {
+		movementID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		_, err = insertStockMovement(ctx, conn, model.StockMovementEntity{
+			ID:          movementID,
+			ProductID:   line.ProductID,
+			Type:        model.StockMovementTransfer,
+			Quantity:    sign * line.Quantity,
+			Reason:      "stock transfer " + reason,
+			ReferenceID: &transfer.ID,
+			CreatedBy:   actor,
+			OutletID:    &outletID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findStockTransferLines loads the lines of several transfers in one query, keyed by transfer id
+func (r *StockTransferRepositoryPostgreSQLImpl) findStockTransferLines(ctx context.Context, transferIDs []uuid.UUID) (map[uuid.UUID][]model.StockTransferLineEntity, error) {
+	lines := map[uuid.UUID][]model.StockTransferLineEntity{}
+	if len(transferIDs) == 0 {
+		return lines, nil
+	}
+
+	query := `
+		SELECT l.stock_transfer_id, l.product_id, p.name, l.quantity
+		FROM core.stock_transfer_line l
+		JOIN core.product p ON l.product_id = p.id
+		WHERE l.stock_transfer_id = ANY($1)
+		ORDER BY p.name
+	`
+	rows, err := r.connPool.Query(ctx, query, transferIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l model.StockTransferLineEntity
+		if err := rows.Scan(&l.StockTransferID, &l.ProductID, &l.ProductName, &l.Quantity); err != nil {
+			return nil, err
+		}
+		lines[l.StockTransferID] = append(lines[l.StockTransferID], l)
+	}
+	return lines, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+)
+
+type SupplierRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewSupplierRepository(connPool DB) repository.SupplierRepository {
+	return &SupplierRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const supplierColumns = `
+	id, version, created_at, created_by, updated_at, updated_by, deleted_at,
+	name, COALESCE(contact_name, ''), COALESCE(phone, ''), COALESCE(email, ''),
+	COALESCE(address, ''), COALESCE(notes, '')
+`
+
+func (r *SupplierRepositoryPostgreSQLImpl) FindSuppliers() ([]model.SupplierEntity, error) {
+	var suppliers []model.SupplierEntity
+	query := `SELECT ` + supplierColumns + ` FROM core.supplier WHERE deleted_at IS NULL ORDER BY name`
+	rows, err := r.connPool.Query(context.Background(), query)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.SupplierEntity
+		if err := rows.Scan(
+			&s.ID, &s.Version, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt, &s.UpdatedBy, &s.DeletedAt,
+			&s.Name, &s.ContactName, &s.Phone, &s.Email,
+			&s.Address, &s.Notes,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	return suppliers, nil
+}
+
+func (r *SupplierRepositoryPostgreSQLImpl) FindSupplierByID(id string) (model.SupplierEntity, error) {
+	var s model.SupplierEntity
+	query := `SELECT ` + supplierColumns + ` FROM core.supplier WHERE id = $1`
+	err := r.connPool.QueryRow(context.Background(), query, id).Scan(
+		&s.ID, &s.Version, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt, &s.UpdatedBy, &s.DeletedAt,
+		&s.Name, &s.ContactName, &s.Phone, &s.Email,
+		&s.Address, &s.Notes,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.SupplierEntity{}, err
+	}
+	return s, nil
+}
+
+func (r *SupplierRepositoryPostgreSQLImpl) InsertSupplier(supplier model.SupplierEntity) (model.SupplierEntity, error) {
+	query := `
+		INSERT INTO core.supplier (
+			id, name, contact_name, phone, email, address, notes, created_by, updated_by
+		) VALUES (
+			$1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9
+		)
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		supplier.ID, supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email,
+		supplier.Address, supplier.Notes, supplier.CreatedBy, supplier.UpdatedBy,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.SupplierEntity{}, err
+	}
+
+	// Supabase buggy when using RETURNING
+	return r.FindSupplierByID(supplier.ID.String())
+}
+
+func (r *SupplierRepositoryPostgreSQLImpl) UpdateSupplierByID(id string, supplier model.SupplierEntity) (model.SupplierEntity, error) {
+	query := `
+		UPDATE core.supplier
+		SET name = $1, contact_name = NULLIF($2, ''), phone = NULLIF($3, ''), email = NULLIF($4, ''),
+			address = NULLIF($5, ''), notes = NULLIF($6, ''), updated_by = $7
+		WHERE id = $8 AND version = $9 AND deleted_at IS NULL
+	`
+	_, err := r.connPool.Exec(context.Background(), query,
+		supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email,
+		supplier.Address, supplier.Notes, supplier.UpdatedBy, id, supplier.Version,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.SupplierEntity{}, err
+	}
+	return r.FindSupplierByID(id)
+}
+
+func (r *SupplierRepositoryPostgreSQLImpl) DeleteSupplierByID(id string) error {
+	_, err := r.connPool.Exec(context.Background(), "UPDATE core.supplier SET deleted_at = NOW(), updated_at = NOW(), updated_by = $1 WHERE id = $2", "USER", id)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"context"
+	"fmt"
+)
+
+// TenantRepositoryPostgreSQLImpl reads core.tenant, which has no row-level security, so it takes the unscoped pool
+type TenantRepositoryPostgreSQLImpl struct {
+	connPool DB
+}
+
+func NewTenantRepository(connPool DB) repository.TenantRepository {
+	return &TenantRepositoryPostgreSQLImpl{
+		connPool: connPool,
+	}
+}
+
+const tenantColumns = `
+	id, version, created_at, created_by, updated_at, updated_by, deleted_at,
+	slug, name, COALESCE(api_token_hash, ''), is_active
+`
+
+func (r *TenantRepositoryPostgreSQLImpl) FindTenantBySlug(slug string) (model.TenantEntity, error) {
+	query := `SELECT ` + tenantColumns + ` FROM core.tenant WHERE slug = $1 AND deleted_at IS NULL`
+	return r.findTenant(query, slug)
+}
+
+func (r *TenantRepositoryPostgreSQLImpl) FindTenantByAPITokenHash(hash string) (model.TenantEntity, error) {
+	query := `SELECT ` + tenantColumns + ` FROM core.tenant WHERE api_token_hash = $1 AND deleted_at IS NULL`
+	return r.findTenant(query, hash)
+}
+
+func (r *TenantRepositoryPostgreSQLImpl) findTenant(query string, arg string) (model.TenantEntity, error) {
+	var t model.TenantEntity
+	err := r.connPool.QueryRow(context.Background(), query, arg).Scan(
+		&t.ID, &t.Version, &t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeletedAt,
+		&t.Slug, &t.Name, &t.APITokenHash, &t.IsActive,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return model.TenantEntity{}, err
+	}
+	return t, nil
+}
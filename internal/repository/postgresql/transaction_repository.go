@@ -2,19 +2,21 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/repository"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type TransactionRepositoryPostgreSQLImpl struct {
-	connPool *pgxpool.Pool
+	connPool DB
 }
 
-func NewTransactionRepository(connPool *pgxpool.Pool) repository.TransactionRepository {
+func NewTransactionRepository(connPool DB) repository.TransactionRepository {
 	return &TransactionRepositoryPostgreSQLImpl{
 		connPool: connPool,
 	}
@@ -33,30 +35,37 @@ func (r *TransactionRepositoryPostgreSQLImpl) CreateTransaction(tx model.Transac
 	txQuery := `
 		INSERT INTO core.transaction (
 			id, total_items, total_price_amount, total_price_scale, currency, 
-			created_by, updated_by
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			created_by, updated_by, outlet_id, customer_id, shift_id,
+			points_earned, points_redeemed, points_discount_amount,
+			gift_card_id, gift_card_amount, created_at, price_list_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
+	// created_at is when the sale was rung up, an offline sale lands on the day it happened in the summaries
 	_, err = conn.Exec(ctx, txQuery,
 		tx.ID, tx.TotalItems, tx.TotalPriceAmount, tx.TotalPriceScale, tx.Currency,
-		tx.CreatedBy, tx.UpdatedBy,
+		tx.CreatedBy, tx.UpdatedBy, tx.OutletID, tx.CustomerID, tx.ShiftID,
+		tx.PointsEarned, tx.PointsRedeemed, tx.PointsDiscountAmount,
+		tx.GiftCardID, tx.GiftCardAmount, tx.CreatedAt, tx.PriceListID,
 	)
 	if err != nil {
 		return model.TransactionEntity{}, fmt.Errorf("failed to insert transaction: %w", err)
 	}
 
+	// the units an offline sale sold beyond the stock are booked back first, so the sale leaves the stock at zero
+	for i, c := range tx.StockConflicts {
+		if tx.StockConflicts[i], err = insertStockConflict(ctx, conn, c); err != nil {
+			return model.TransactionEntity{}, err
+		}
+	}
+
 	detailQuery := `
 		INSERT INTO core.transaction_detail (
 			id, transaction_id, product_id, product_name, category_id, category_name,
 			price_amount, price_scale, currency,
 			quantity, total_price_amount, total_price_scale, 
-			created_by, updated_by
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
-	`
-
-	stockQuery := `
-		UPDATE core.product 
-		SET stock = stock - $1, updated_at = NOW(), updated_by = $2
-		WHERE id = $3 AND deleted_at IS NULL
+			created_by, updated_by, cost_price_amount, total_cost_amount, created_at,
+			price_tier_min_quantity, quantity_scale, unit, unit_quantity, points_discount_amount
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, NULLIF($20, ''), $21, $22)
 	`
 
 	for _, d := range details {
@@ -64,23 +73,59 @@ func (r *TransactionRepositoryPostgreSQLImpl) CreateTransaction(tx model.Transac
 			d.ID, d.TransactionID, d.ProductID, d.ProductName, d.CategoryID, d.CategoryName,
 			d.PriceAmount, d.PriceScale, d.Currency,
 			d.Quantity, d.TotalPriceAmount, d.TotalPriceScale,
-			d.CreatedBy, d.UpdatedBy,
+			d.CreatedBy, d.UpdatedBy, d.CostPriceAmount, d.TotalCostAmount, tx.CreatedAt,
+			d.PriceTierMinQuantity, d.QuantityScale, d.Unit, d.UnitQuantity, d.PointsDiscountAmount,
 		)
 		if err != nil {
 			return model.TransactionEntity{}, fmt.Errorf("failed to insert transaction detail: %w", err)
 		}
 
-		if d.ProductID != nil {
-			cmd, err := conn.Exec(ctx, stockQuery, d.Quantity, d.CreatedBy, d.ProductID)
-			if err != nil {
-				return model.TransactionEntity{}, fmt.Errorf("failed to update stock: %w", err)
+		// a bundle holds no stock of its own, selling one consumes each of its components instead
+		if len(d.Components) > 0 {
+			for _, c := range d.Components {
+				if err := recordSale(ctx, conn, tx, c.ComponentID, d.Quantity*c.Quantity, d.Lots, d.CreatedBy); err != nil {
+					return model.TransactionEntity{}, fmt.Errorf("failed to update stock of %s in %s: %w", c.ComponentName, d.ProductName, err)
+				}
 			}
-			if cmd.RowsAffected() == 0 {
-				return model.TransactionEntity{}, fmt.Errorf("failed to update stock: product %s not found or deleted", d.ProductName)
+		} else if d.ProductID != nil {
+			if err := recordSale(ctx, conn, tx, *d.ProductID, d.Quantity, d.Lots, d.CreatedBy); err != nil {
+				return model.TransactionEntity{}, fmt.Errorf("failed to update stock of %s: %w", d.ProductName, err)
 			}
 		}
 	}
 
+	// the points go in with the sale, customer_points_not_negative fails it when they were already spent
+	for i, e := range tx.LoyaltyEntries {
+		if tx.LoyaltyEntries[i], err = insertLoyaltyEntry(ctx, conn, e); err != nil {
+			return model.TransactionEntity{}, err
+		}
+	}
+
+	// gift_card_balance_not_negative fails the sale when a concurrent one already spent the card's value
+	if tx.GiftCardEntry != nil {
+		entry, err := insertGiftCardEntry(ctx, conn, *tx.GiftCardEntry)
+		if err != nil {
+			return model.TransactionEntity{}, err
+		}
+		tx.GiftCardEntry = &entry
+	}
+
+	// the draft is converted with its sale, a draft converted or cancelled meanwhile fails the sale
+	if tx.DraftOrderID != nil {
+		query := `
+			UPDATE core.draft_order
+			SET status = $1, transaction_id = $2, updated_by = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $4 AND status IN ('open', 'held')
+		`
+		tag, err := conn.Exec(ctx, query, model.DraftOrderConverted, tx.ID, tx.UpdatedBy, tx.DraftOrderID)
+		if err != nil {
+			return model.TransactionEntity{}, fmt.Errorf("failed to convert draft order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return model.TransactionEntity{}, fmt.Errorf("draft order %s is no longer open or held", tx.DraftOrderID)
+		}
+	}
+
 	if err := conn.Commit(ctx); err != nil {
 		return model.TransactionEntity{}, err
 	}
@@ -88,34 +133,143 @@ func (r *TransactionRepositoryPostgreSQLImpl) CreateTransaction(tx model.Transac
 	return tx, nil
 }
 
-func (r *TransactionRepositoryPostgreSQLImpl) GetReportStats(startDate, endDate time.Time) (model.ReportResponse, error) {
+// insertStockConflict books the conflict's units back at the sale's outlet as an adjustment and records the conflict
+func insertStockConflict(ctx context.Context, conn pgx.Tx, conflict model.StockConflictEntity) (model.StockConflictEntity, error) {
+	movementID, err := uuid.NewV7()
+	if err != nil {
+		return model.StockConflictEntity{}, err
+	}
+	_, err = insertStockMovement(ctx, conn, model.StockMovementEntity{
+		ID:          movementID,
+		ProductID:   conflict.ProductID,
+		Type:        model.StockMovementAdjustment,
+		Quantity:    conflict.Quantity,
+		Reason:      "offline sale oversold",
+		ReferenceID: &conflict.TransactionID,
+		CreatedBy:   conflict.CreatedBy,
+		OutletID:    conflict.OutletID,
+	})
+	if err != nil {
+		return model.StockConflictEntity{}, fmt.Errorf("failed to book stock conflict of %s: %w", conflict.ProductName, err)
+	}
+
+	query := `
+		INSERT INTO core.stock_conflict (id, transaction_id, product_id, product_name, outlet_id, quantity, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = conn.Exec(ctx, query,
+		conflict.ID, conflict.TransactionID, conflict.ProductID, conflict.ProductName, conflict.OutletID, conflict.Quantity, conflict.CreatedBy,
+	)
+	if err != nil {
+		return model.StockConflictEntity{}, fmt.Errorf("failed to insert stock conflict: %w", err)
+	}
+
+	// Supabase buggy when using RETURNING
+	if err := conn.QueryRow(ctx, "SELECT created_at FROM core.stock_conflict WHERE id = $1", conflict.ID).Scan(&conflict.CreatedAt); err != nil {
+		return model.StockConflictEntity{}, fmt.Errorf("failed to read stock conflict: %w", err)
+	}
+	return conflict, nil
+}
+
+// recordSale takes the sold quantity out of stock through the ledger.
+// The part allocated to lots is booked lot by lot, the rest comes from stock held outside any lot.
+func recordSale(ctx context.Context, conn pgx.Tx, tx model.TransactionEntity, productID uuid.UUID, quantity int, lots []model.LotAllocationEntity, actor string) error {
+	for _, l := range lots {
+		if l.ProductID != productID {
+			continue
+		}
+		if err := recordSaleMovement(ctx, conn, tx, productID, &l.LotID, l.Quantity, actor); err != nil {
+			return err
+		}
+		quantity -= l.Quantity
+	}
+	if quantity <= 0 {
+		return nil
+	}
+	return recordSaleMovement(ctx, conn, tx, productID, nil, quantity, actor)
+}
+
+// recordSaleMovement books the sale at the transaction's outlet, outlet_stock_not_negative guards the outlet's stock
+func recordSaleMovement(ctx context.Context, conn pgx.Tx, tx model.TransactionEntity, productID uuid.UUID, lotID *uuid.UUID, quantity int, actor string) error {
+	movementID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	_, err = insertStockMovement(ctx, conn, model.StockMovementEntity{
+		ID:          movementID,
+		ProductID:   productID,
+		Type:        model.StockMovementSale,
+		Quantity:    -quantity,
+		ReferenceID: &tx.ID,
+		CreatedBy:   actor,
+		LotID:       lotID,
+		OutletID:    tx.OutletID,
+	})
+	return err
+}
+
+// transactionSummarySource is core.transaction_summary_daily, or the same columns derived from one outlet's
+// transactions since the daily summaries are kept across all outlets. The outlet is bound to $3.
+func transactionSummarySource(outletID *uuid.UUID) (string, []any) {
+	if outletID == nil {
+		return "core.transaction_summary_daily", nil
+	}
+	return `(
+			SELECT DATE(created_at) AS report_date, total_price_amount AS total_revenue, 1 AS total_transactions
+			FROM core.transaction
+			WHERE outlet_id = $3
+		) summary`, []any{*outletID}
+}
+
+// salesSummarySource is core.sales_summary_daily, or the same columns derived from one outlet's
+// transaction details. The outlet is bound to $3. total_sold counts base units, the details keep stock units.
+func salesSummarySource(outletID *uuid.UUID) (string, []any) {
+	if outletID == nil {
+		return "core.sales_summary_daily s", nil
+	}
+	return `(
+			SELECT
+				DATE(d.created_at) AS report_date, d.product_id,
+				COALESCE(d.category_id, '00000000-0000-0000-0000-000000000000'::uuid) AS category_id,
+				d.quantity::NUMERIC / power(10::NUMERIC, d.quantity_scale) AS total_sold,
+				d.total_price_amount AS total_revenue
+			FROM core.transaction_detail d
+			JOIN core.transaction t ON d.transaction_id = t.id
+			WHERE t.outlet_id = $3
+		) s`, []any{*outletID}
+}
+
+func (r *TransactionRepositoryPostgreSQLImpl) GetReportStats(startDate, endDate time.Time, outletID *uuid.UUID) (model.ReportResponse, error) {
 	ctx := context.Background()
 	var report model.ReportResponse
 
+	txSource, outletArgs := transactionSummarySource(outletID)
+	args := append([]any{startDate, endDate}, outletArgs...)
 	query := `
 		SELECT 
 			COALESCE(SUM(total_revenue), 0), 
 			COALESCE(SUM(total_transactions), 0)
-		FROM core.transaction_summary_daily
+		FROM ` + txSource + `
 		WHERE report_date >= $1 AND report_date <= $2
 	`
-	err := r.connPool.QueryRow(ctx, query, startDate, endDate).Scan(&report.TotalRevenue.Amount, &report.TotalTransactions)
+	err := r.connPool.QueryRow(ctx, query, args...).Scan(&report.TotalRevenue.Amount, &report.TotalTransactions)
 	if err != nil {
 		return report, err
 	}
 	report.TotalRevenue.Currency = "IDR" // Default
 	report.TotalRevenue.Display = float64(report.TotalRevenue.Amount)
 
+	salesSource, _ := salesSummarySource(outletID)
 	topItemsQuery := `
-		SELECT p.name, SUM(s.total_sold) as total_qty
-		FROM core.sales_summary_daily s
+		SELECT p.name, SUM(s.total_sold)::FLOAT8 as total_qty
+		FROM ` + salesSource + `
 		JOIN core.product p ON s.product_id = p.id
 		WHERE s.report_date >= $1 AND s.report_date <= $2
 		GROUP BY p.name
 		ORDER BY total_qty DESC
 		LIMIT 5
 	`
-	rows, err := r.connPool.Query(ctx, topItemsQuery, startDate, endDate)
+	rows, err := r.connPool.Query(ctx, topItemsQuery, args...)
 	if err != nil {
 		return report, err
 	}
@@ -130,15 +284,15 @@ func (r *TransactionRepositoryPostgreSQLImpl) GetReportStats(startDate, endDate
 	}
 
 	topCatsQuery := `
-		SELECT COALESCE(c.name, 'Uncategorized'), SUM(s.total_sold) as total_qty
-		FROM core.sales_summary_daily s
+		SELECT COALESCE(c.name, 'Uncategorized'), SUM(s.total_sold)::FLOAT8 as total_qty
+		FROM ` + salesSource + `
 		LEFT JOIN core.category c ON s.category_id = c.id
 		WHERE s.report_date >= $1 AND s.report_date <= $2
 		GROUP BY c.name, s.category_id
 		ORDER BY total_qty DESC
 		LIMIT 5
 	`
-	rows2, err := r.connPool.Query(ctx, topCatsQuery, startDate, endDate)
+	rows2, err := r.connPool.Query(ctx, topCatsQuery, args...)
 	if err != nil {
 		return report, err
 	}
@@ -155,40 +309,185 @@ func (r *TransactionRepositoryPostgreSQLImpl) GetReportStats(startDate, endDate
 	return report, nil
 }
 
-func (r *TransactionRepositoryPostgreSQLImpl) GetMostPopularCategory(startDate, endDate time.Time) (model.PopularCategory, error) {
+// GetSalesMargins reads the transaction details directly, the daily summaries carry no cost
+func (r *TransactionRepositoryPostgreSQLImpl) GetSalesMargins(startDate, endDate time.Time, outletID *uuid.UUID) ([]model.SalesMarginEntity, error) {
+	ctx := context.Background()
+	query := `
+		SELECT
+			d.product_id, d.product_name, d.category_id, d.category_name,
+			SUM(d.quantity::NUMERIC / power(10::NUMERIC, d.quantity_scale))::FLOAT8,
+			SUM(d.total_price_amount)::BIGINT, SUM(d.total_cost_amount)::BIGINT
+		FROM core.transaction_detail d
+		JOIN core.transaction t ON d.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at <= $2
+		  AND t.deleted_at IS NULL AND d.deleted_at IS NULL
+		  AND ($3::uuid IS NULL OR t.outlet_id = $3)
+		GROUP BY d.product_id, d.product_name, d.category_id, d.category_name
+		ORDER BY SUM(d.total_price_amount) - SUM(d.total_cost_amount) DESC, d.product_name
+	`
+	rows, err := r.connPool.Query(ctx, query, startDate, endDate, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var margins []model.SalesMarginEntity
+	for rows.Next() {
+		var m model.SalesMarginEntity
+		if err := rows.Scan(&m.ProductID, &m.ProductName, &m.CategoryID, &m.CategoryName, &m.Quantity, &m.Revenue, &m.COGS); err != nil {
+			return nil, err
+		}
+		margins = append(margins, m)
+	}
+	return margins, nil
+}
+
+func (r *TransactionRepositoryPostgreSQLImpl) GetMostPopularCategory(startDate, endDate time.Time, outletID *uuid.UUID, level *int) (model.PopularCategory, error) {
 	ctx := context.Background()
 	var category model.PopularCategory
+	source, outletArgs := salesSummarySource(outletID)
+	args := append([]any{startDate, endDate}, outletArgs...)
+	levelParam := fmt.Sprintf("$%d", len(args)+1)
+	// the path of names from the top level down, cut after level so the sales count towards that ancestor.
+	// LEAST skips a NULL level and keeps the whole path.
 	query := `
-		SELECT COALESCE(c.name, 'Uncategorized'), SUM(s.total_sold) as total_qty
-		FROM core.sales_summary_daily s
-		LEFT JOIN core.category c ON s.category_id = c.id
-		WHERE s.report_date >= $1 AND s.report_date <= $2
-		GROUP BY c.name, s.category_id
+		WITH RECURSIVE category_path AS (
+			SELECT id, ARRAY[id] AS ids, ARRAY[name] AS names
+			FROM core.category
+			WHERE parent_id IS NULL
+			UNION ALL
+			SELECT c.id, p.ids || c.id, p.names || c.name
+			FROM core.category c
+			JOIN category_path p ON c.parent_id = p.id
+		), rolled_up AS (
+			SELECT
+				cp.ids[1:LEAST(` + levelParam + `::INT + 1, cardinality(cp.ids))] AS ids,
+				cp.names[1:LEAST(` + levelParam + `::INT + 1, cardinality(cp.names))] AS names,
+				s.total_sold, s.report_date
+			FROM ` + source + `
+			LEFT JOIN category_path cp ON s.category_id = cp.id
+		)
+		SELECT
+			COALESCE(names[cardinality(names)], 'Uncategorized'), COALESCE(names, '{}'), SUM(total_sold)::FLOAT8 as total_qty
+		FROM rolled_up
+		WHERE report_date >= $1 AND report_date <= $2
+		GROUP BY ids, names
 		ORDER BY total_qty DESC
 		LIMIT 1
 	`
-	err := r.connPool.QueryRow(ctx, query, startDate, endDate).Scan(&category.Name, &category.TotalSoldQty)
+	err := r.connPool.QueryRow(ctx, query, append(args, level)...).Scan(&category.Name, &category.Path, &category.TotalSoldQty)
 	if err != nil {
 		return category, err
 	}
 	return category, nil
 }
 
-func (r *TransactionRepositoryPostgreSQLImpl) GetMostPopularProduct(startDate, endDate time.Time) (model.PopularItem, error) {
+func (r *TransactionRepositoryPostgreSQLImpl) GetMostPopularProduct(startDate, endDate time.Time, outletID *uuid.UUID) (model.PopularItem, error) {
 	ctx := context.Background()
 	var product model.PopularItem
+	source, outletArgs := salesSummarySource(outletID)
 	query := `
-		SELECT p.name, SUM(s.total_sold) as total_qty
-		FROM core.sales_summary_daily s
+		SELECT p.name, SUM(s.total_sold)::FLOAT8 as total_qty
+		FROM ` + source + `
 		JOIN core.product p ON s.product_id = p.id
 		WHERE s.report_date >= $1 AND s.report_date <= $2
 		GROUP BY p.name
 		ORDER BY total_qty DESC
 		LIMIT 1
 	`
-	err := r.connPool.QueryRow(ctx, query, startDate, endDate).Scan(&product.Name, &product.TotalSoldQty)
+	err := r.connPool.QueryRow(ctx, query, append([]any{startDate, endDate}, outletArgs...)...).Scan(&product.Name, &product.TotalSoldQty)
 	if err != nil {
 		return product, err
 	}
 	return product, nil
 }
+
+func (r *TransactionRepositoryPostgreSQLImpl) FindTransactionsByCustomer(customerID string) ([]model.TransactionEntity, error) {
+	var transactions []model.TransactionEntity
+	query := `
+		SELECT
+			id, total_items, total_price_amount, total_price_scale, currency,
+			created_at, created_by, updated_at, updated_by, deleted_at, version,
+			outlet_id, customer_id, shift_id, points_earned, points_redeemed, points_discount_amount,
+			gift_card_id, gift_card_amount
+		FROM core.transaction
+		WHERE customer_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+	rows, err := r.connPool.Query(context.Background(), query, customerID)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.TransactionEntity
+		if err := rows.Scan(
+			&t.ID, &t.TotalItems, &t.TotalPriceAmount, &t.TotalPriceScale, &t.Currency,
+			&t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeletedAt, &t.Version,
+			&t.OutletID, &t.CustomerID, &t.ShiftID, &t.PointsEarned, &t.PointsRedeemed, &t.PointsDiscountAmount,
+			&t.GiftCardID, &t.GiftCardAmount,
+		); err != nil {
+			fmt.Println(err)
+			return nil, err
+		}
+		t.TotalPriceDisplay = float64(t.TotalPriceAmount)
+		transactions = append(transactions, t)
+	}
+
+	return transactions, nil
+}
+
+func (r *TransactionRepositoryPostgreSQLImpl) FindTransactionByID(id string) (model.TransactionEntity, error) {
+	var t model.TransactionEntity
+	query := `
+		SELECT
+			id, total_items, total_price_amount, total_price_scale, currency,
+			created_at, created_by, updated_at, updated_by, deleted_at, version,
+			outlet_id, customer_id, shift_id, points_earned, points_redeemed, points_discount_amount,
+			gift_card_id, gift_card_amount, price_list_id
+		FROM core.transaction
+		WHERE id = $1
+	`
+	err := r.connPool.QueryRow(context.Background(), query, id).Scan(
+		&t.ID, &t.TotalItems, &t.TotalPriceAmount, &t.TotalPriceScale, &t.Currency,
+		&t.CreatedAt, &t.CreatedBy, &t.UpdatedAt, &t.UpdatedBy, &t.DeletedAt, &t.Version,
+		&t.OutletID, &t.CustomerID, &t.ShiftID, &t.PointsEarned, &t.PointsRedeemed, &t.PointsDiscountAmount,
+		&t.GiftCardID, &t.GiftCardAmount, &t.PriceListID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.TransactionEntity{}, nil
+	}
+	if err != nil {
+		fmt.Println(err)
+		return model.TransactionEntity{}, err
+	}
+	t.TotalPriceDisplay = float64(t.TotalPriceAmount)
+	return t, nil
+}
+
+func (r *TransactionRepositoryPostgreSQLImpl) GetShiftSales(shiftID string) (model.ShiftSalesEntity, error) {
+	sales, err := shiftSales(context.Background(), r.connPool, shiftID)
+	if err != nil {
+		fmt.Println(err)
+		return model.ShiftSalesEntity{}, err
+	}
+	return sales, nil
+}
+
+// shiftSales sums the shift's transactions, conn is the pool or the transaction closing the shift
+func shiftSales(ctx context.Context, conn DB, shiftID string) (model.ShiftSalesEntity, error) {
+	var sales model.ShiftSalesEntity
+	query := `
+		SELECT
+			COUNT(*), COALESCE(SUM(total_items), 0), COALESCE(SUM(total_price_amount), 0),
+			COALESCE(SUM(points_discount_amount), 0), COALESCE(SUM(gift_card_amount), 0)
+		FROM core.transaction
+		WHERE shift_id = $1 AND deleted_at IS NULL
+	`
+	err := conn.QueryRow(ctx, query, shiftID).Scan(
+		&sales.TransactionCount, &sales.ItemsSold, &sales.NetSales, &sales.PointsDiscount, &sales.GiftCardAmount,
+	)
+	return sales, err
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type PriceListRepository interface {
+	FindPriceLists() ([]model.PriceListEntity, error)
+	FindPriceListByID(id string) (model.PriceListEntity, error)
+	InsertPriceList(priceList model.PriceListEntity) (model.PriceListEntity, error)
+	UpdatePriceListByID(id string, priceList model.PriceListEntity) (model.PriceListEntity, error)
+	DeletePriceListByID(id string) error
+	// FindPriceListsChangedSince returns the price lists written at or after the since cursor, deleted and inactive ones
+	// included. The cursor comes from ProductRepository.FindProductsChangedSince.
+	FindPriceListsChangedSince(since uint64) ([]model.PriceListEntity, error)
+	// FindProductPrices lists the product's scheduled prices of every list, the cancelled ones left out, by start
+	FindProductPrices(productID string) ([]model.ProductPriceEntity, error)
+	// FindProductPricesByProductIDs lists the scheduled prices of every one of the products, the cancelled ones left out
+	FindProductPricesByProductIDs(productIDs []string) ([]model.ProductPriceEntity, error)
+	InsertProductPrice(price model.ProductPriceEntity) (model.ProductPriceEntity, error)
+	// FindProductPriceByID returns a zero entity when the product has no such price
+	FindProductPriceByID(productID, id string) (model.ProductPriceEntity, error)
+	CancelProductPrice(productID, id string) error
+	// FindProductPriceTiers lists the product's quantity tiers by MinQuantity
+	FindProductPriceTiers(productID string) ([]model.ProductPriceTierEntity, error)
+	// FindProductPriceTiersByProductIDs lists the quantity tiers of every one of the products
+	FindProductPriceTiersByProductIDs(productIDs []string) ([]model.ProductPriceTierEntity, error)
+	// ReplaceProductPriceTiers swaps the product's whole tier table for tiers
+	ReplaceProductPriceTiers(productID string, tiers []model.ProductPriceTierEntity) ([]model.ProductPriceTierEntity, error)
+}
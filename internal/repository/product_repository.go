@@ -7,8 +7,35 @@ import (
 type ProductRepository interface {
 	FindProducts() ([]model.ProductEntity, error)
 	FindProductByID(id string) (model.ProductEntity, error)
+	// FindProductBySKU finds the live product holding the SKU regardless of case
+	FindProductBySKU(sku string) (model.ProductEntity, error)
+	// FindProductsByCategoryIDs lists the live products in any of the categories
+	FindProductsByCategoryIDs(categoryIDs []string) ([]model.ProductEntity, error)
+	// FindBundlesByComponentID lists the live bundles the product is a component of
+	FindBundlesByComponentID(componentID string) ([]model.ProductEntity, error)
+	// FindCategoryProductStats counts the products of every category holding any, deleted ones included
+	FindCategoryProductStats() ([]model.CategoryProductStatsEntity, error)
 	FindProductsByNameAndActiveStatus(name string, activeStatus *bool) ([]model.ProductEntity, error)
+	FindLowStockProducts() ([]model.ProductEntity, error)
 	InsertProduct(product model.ProductEntity) (model.ProductEntity, error)
 	UpdateProductByID(id string, product model.ProductEntity) (model.ProductEntity, error)
 	DeleteProductByID(id string) error
+	// FindProductsChangedSince returns the products written at or after the since cursor, soft-deleted ones included,
+	// and the cursor to pull from next. The next cursor is read before the products, so a write still committing
+	// is sent again on the next pull rather than missed.
+	FindProductsChangedSince(since uint64) ([]model.ProductEntity, uint64, error)
+	// ApplyProductBatch runs the operations in one database transaction and returns a result per operation.
+	// An atomic batch stops at the first failure and applies nothing, the operations after it have empty results.
+	// Otherwise a failed operation is undone on its own and the rest are applied.
+	ApplyProductBatch(operations []model.ProductBatchOperationEntity, atomic bool) ([]model.ProductBatchResultEntity, error)
+	// FindProductUnits lists the units the product is also sold in by name
+	FindProductUnits(productID string) ([]model.ProductUnitEntity, error)
+	// FindProductUnitsByProductIDs lists the units of every one of the products
+	FindProductUnitsByProductIDs(productIDs []string) ([]model.ProductUnitEntity, error)
+	// ReplaceProductUnits swaps the product's whole set of units for units
+	ReplaceProductUnits(productID string, units []model.ProductUnitEntity) ([]model.ProductUnitEntity, error)
+	// InsertProductImage adds the image after the product's others
+	InsertProductImage(image model.ProductImageEntity) (model.ProductImageEntity, error)
+	// DeleteProductImage removes the image of the product and returns it, its files are left to the caller
+	DeleteProductImage(productID, imageID string) (model.ProductImageEntity, error)
 }
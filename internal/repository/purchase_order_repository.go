@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type PurchaseOrderRepository interface {
+	FindPurchaseOrders() ([]model.PurchaseOrderEntity, error)
+	FindPurchaseOrderByID(id string) (model.PurchaseOrderEntity, error)
+	InsertPurchaseOrder(order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error)
+	// UpdatePurchaseOrderByID replaces the supplier, notes and lines of a draft order
+	UpdatePurchaseOrderByID(id string, order model.PurchaseOrderEntity) (model.PurchaseOrderEntity, error)
+	UpdatePurchaseOrderStatus(id string, fromStatus, toStatus, actor string) (model.PurchaseOrderEntity, error)
+	// ReceiveGoods books the receipt, raises stock through purchase_receipt movements and moves the order
+	// to partially_received or closed, all or nothing
+	ReceiveGoods(id string, receipt model.GoodsReceiptEntity) (model.PurchaseOrderEntity, error)
+}
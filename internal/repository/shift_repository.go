@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type ShiftRepository interface {
+	// FindShifts returns the shifts in the given status newest first, every shift when status is empty
+	FindShifts(status string) ([]model.ShiftEntity, error)
+	FindShiftByID(id string) (model.ShiftEntity, error)
+	// FindOpenShiftByCashier returns the zero shift, with a nil ID, when the cashier has no open shift
+	FindOpenShiftByCashier(cashierName string) (model.ShiftEntity, error)
+	InsertShift(shift model.ShiftEntity) (model.ShiftEntity, error)
+	// InsertCashEntry books petty cash on the shift, failing unless the shift is open
+	InsertCashEntry(entry model.CashEntryEntity) (model.CashEntryEntity, error)
+	// CloseShift records the counted cash and snapshots the expected cash from every sale and entry booked on the shift
+	CloseShift(id string, countedCash int64, notes string, actor string) (model.ShiftEntity, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type StockConflictRepository interface {
+	// FindStockConflicts returns the conflicts newest first, the open or resolved ones only when status says so
+	FindStockConflicts(status string) ([]model.StockConflictEntity, error)
+	FindStockConflictByID(id string) (model.StockConflictEntity, error)
+	// ResolveStockConflict stamps the resolution on the conflict, failing when it is already resolved
+	ResolveStockConflict(conflict model.StockConflictEntity) (model.StockConflictEntity, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type StockCountRepository interface {
+	FindStockCounts() ([]model.StockCountEntity, error)
+	FindStockCountByID(id string) (model.StockCountEntity, error)
+	// InsertStockCount opens the count with one line per active product in scope
+	InsertStockCount(count model.StockCountEntity) (model.StockCountEntity, error)
+	UpdateStockCountLines(id string, lines []model.StockCountLineEntity) (model.StockCountEntity, error)
+	UpdateStockCountStatus(id string, fromStatus, toStatus, actor string) (model.StockCountEntity, error)
+	// PostStockCount books every counted variance as an adjustment and marks the count posted, all or nothing
+	PostStockCount(id string, actor string) (model.StockCountEntity, error)
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type StockMovementRepository interface {
+	FindStockMovementsByProductID(productID string) ([]model.StockMovementEntity, error)
+	InsertStockMovement(movement model.StockMovementEntity) (model.StockMovementEntity, error)
+	GetStockReconciliation(productID string) (model.StockReconciliationEntity, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type StockTransferRepository interface {
+	FindStockTransfers() ([]model.StockTransferEntity, error)
+	FindStockTransferByID(id string) (model.StockTransferEntity, error)
+	// InsertStockTransfer dispatches the transfer, the stock leaves the source outlet through transfer movements
+	InsertStockTransfer(transfer model.StockTransferEntity) (model.StockTransferEntity, error)
+	// ReceiveStockTransfer books the stock into the destination outlet and marks the transfer received
+	ReceiveStockTransfer(id string, actor string) (model.StockTransferEntity, error)
+	// CancelStockTransfer returns the stock in transit to the source outlet and marks the transfer cancelled
+	CancelStockTransfer(id string, actor string) (model.StockTransferEntity, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+type SupplierRepository interface {
+	FindSuppliers() ([]model.SupplierEntity, error)
+	FindSupplierByID(id string) (model.SupplierEntity, error)
+	InsertSupplier(supplier model.SupplierEntity) (model.SupplierEntity, error)
+	UpdateSupplierByID(id string, supplier model.SupplierEntity) (model.SupplierEntity, error)
+	DeleteSupplierByID(id string) error
+}
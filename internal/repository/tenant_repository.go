@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"codewithumam-kasir-api/internal/model"
+)
+
+// TenantRepository looks tenants up before a request is scoped to one, it is never tenant-scoped itself
+type TenantRepository interface {
+	FindTenantBySlug(slug string) (model.TenantEntity, error)
+	FindTenantByAPITokenHash(hash string) (model.TenantEntity, error)
+}
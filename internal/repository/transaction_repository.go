@@ -4,11 +4,21 @@ import (
 	"time"
 
 	"codewithumam-kasir-api/internal/model"
+	"github.com/google/uuid"
 )
 
 type TransactionRepository interface {
+	// CreateTransaction books the sale with its details and stock movements, and with the stock conflicts of an offline sale
 	CreateTransaction(tx model.TransactionEntity, details []model.TransactionDetailEntity) (model.TransactionEntity, error)
-	GetReportStats(startDate, endDate time.Time) (model.ReportResponse, error)
-	GetMostPopularCategory(startDate, endDate time.Time) (model.PopularCategory, error)
-	GetMostPopularProduct(startDate, endDate time.Time) (model.PopularItem, error)
+	// FindTransactionByID returns the transaction without its details, a zero ID when there is none
+	FindTransactionByID(id string) (model.TransactionEntity, error)
+	GetReportStats(startDate, endDate time.Time, outletID *uuid.UUID) (model.ReportResponse, error)
+	// GetMostPopularCategory counts a category's sales towards its ancestor at level, 0 being the top level.
+	// A nil level, or a category above the level, counts the sales towards the category itself.
+	GetMostPopularCategory(startDate, endDate time.Time, outletID *uuid.UUID, level *int) (model.PopularCategory, error)
+	GetMostPopularProduct(startDate, endDate time.Time, outletID *uuid.UUID) (model.PopularItem, error)
+	GetSalesMargins(startDate, endDate time.Time, outletID *uuid.UUID) ([]model.SalesMarginEntity, error)
+	// FindTransactionsByCustomer returns the customer's transactions newest first
+	FindTransactionsByCustomer(customerID string) ([]model.TransactionEntity, error)
+	GetShiftSales(shiftID string) (model.ShiftSalesEntity, error)
 }
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// maxImportRows caps one import file, a bigger catalog is imported in parts
+const maxImportRows = 10000
+
+// CatalogService moves the product catalog in and out in bulk as CSV, XLSX or JSON
+type CatalogService interface {
+	// ImportProducts upserts the file's products, matching an existing product by SKU and then by name.
+	// Rows that fail are reported and skipped, the rest are imported. A dry run only reports.
+	ImportProducts(format string, data []byte, dryRun bool) (model.ProductImportReport, error)
+	// ExportProducts writes every product to w as it goes, in a file ImportProducts takes back
+	ExportProducts(format string, w io.Writer) error
+}
+
+type catalogService struct {
+	productRepository  repository.ProductRepository
+	categoryRepository repository.CategoryRepository
+}
+
+func NewCatalogService(productRepository repository.ProductRepository, categoryRepository repository.CategoryRepository) CatalogService {
+	return &catalogService{
+		productRepository:  productRepository,
+		categoryRepository: categoryRepository,
+	}
+}
+
+// importRow is a row of the file with its row number as the user sees it and what was wrong with it
+type importRow struct {
+	number int
+	row    model.ProductImportRow
+	errors []model.ProductImportError
+}
+
+func (r *importRow) fail(column, message string) {
+	r.errors = append(r.errors, model.ProductImportError{Row: r.number, Column: column, Message: message})
+}
+
+func (s *catalogService) ImportProducts(format string, data []byte, dryRun bool) (model.ProductImportReport, error) {
+	rows, err := decodeProductImport(format, data)
+	if err != nil {
+		return model.ProductImportReport{}, err
+	}
+	if len(rows) == 0 {
+		return model.ProductImportReport{}, fmt.Errorf("%w: the file has no products", ErrInvalidProduct)
+	}
+	if len(rows) > maxImportRows {
+		return model.ProductImportReport{}, fmt.Errorf("%w: at most %d products can be imported at once", ErrInvalidProduct, maxImportRows)
+	}
+
+	products, err := s.productRepository.FindProducts()
+	if err != nil {
+		return model.ProductImportReport{}, err
+	}
+	bySKU, byName := map[string]int{}, map[string]int{}
+	for i, p := range products {
+		if p.SKU != "" {
+			bySKU[strings.ToLower(p.SKU)] = i
+		}
+		if _, ok := byName[strings.ToLower(p.Name)]; !ok {
+			byName[strings.ToLower(p.Name)] = i
+		}
+	}
+	categories, err := s.categoryRepository.FindCategories()
+	if err != nil {
+		return model.ProductImportReport{}, err
+	}
+	categoryNames := map[string]model.CategoryEntity{}
+	for _, c := range categories {
+		if c.DeletedAt == nil {
+			categoryNames[strings.ToLower(c.Name)] = c
+		}
+	}
+
+	report := model.ProductImportReport{DryRun: dryRun, TotalRows: len(rows), CategoriesCreated: []string{}, Errors: []model.ProductImportError{}}
+	// the row each product or SKU was first seen on, a second row for it is a mistake in the file
+	seen := map[string]int{}
+	for _, r := range rows {
+		existing := -1
+		if i, ok := bySKU[strings.ToLower(r.row.SKU)]; ok && r.row.SKU != "" {
+			existing = i
+		} else if i, ok := byName[strings.ToLower(r.row.Name)]; ok {
+			existing = i
+		}
+
+		keys := []string{"name:" + strings.ToLower(r.row.Name)}
+		if r.row.SKU != "" {
+			keys = append(keys, "sku:"+strings.ToLower(r.row.SKU))
+		}
+		for _, key := range keys {
+			if first, ok := seen[key]; ok {
+				r.fail("", fmt.Sprintf("the same product as row %d", first))
+				break
+			}
+		}
+		for _, key := range keys {
+			if _, ok := seen[key]; !ok {
+				seen[key] = r.number
+			}
+		}
+
+		var product model.ProductEntity
+		if existing >= 0 {
+			product = mergeImportRow(&r, products[existing])
+		} else {
+			product = newImportProduct(&r)
+		}
+		if len(r.errors) > 0 {
+			report.Failed++
+			report.Errors = append(report.Errors, r.errors...)
+			continue
+		}
+
+		if product.CategoryName != "" {
+			category, ok := categoryNames[strings.ToLower(product.CategoryName)]
+			if !ok {
+				request := model.CreateCategoryRequest{Name: product.CategoryName}
+				category = *request.ToEntity()
+				if !dryRun {
+					if category, err = s.categoryRepository.InsertCategory(category); err != nil {
+						report.Failed++
+						report.Errors = append(report.Errors, model.ProductImportError{Row: r.number, Column: "category", Message: "the category could not be created"})
+						continue
+					}
+				}
+				categoryNames[strings.ToLower(product.CategoryName)] = category
+				report.CategoriesCreated = append(report.CategoriesCreated, product.CategoryName)
+			}
+			// a dry run has no id for a category it would create, nothing is written with it
+			product.CategoryName = category.Name
+			product.CategoryID = nil
+			if category.ID != uuid.Nil {
+				product.CategoryID = &category.ID
+			}
+		}
+
+		if existing >= 0 {
+			if !dryRun {
+				if _, err := s.productRepository.UpdateProductByID(product.ID.String(), product); err != nil {
+					report.Failed++
+					report.Errors = append(report.Errors, model.ProductImportError{Row: r.number, Message: "the product could not be saved"})
+					continue
+				}
+			}
+			report.Updated++
+			continue
+		}
+		if !dryRun {
+			if _, err := s.productRepository.InsertProduct(product); err != nil {
+				report.Failed++
+				report.Errors = append(report.Errors, model.ProductImportError{Row: r.number, Message: "the product could not be saved"})
+				continue
+			}
+		}
+		report.Created++
+	}
+	return report, nil
+}
+
+// mergeImportRow lays the row's values over the existing product, the empty ones keep what it has
+func mergeImportRow(r *importRow, product model.ProductEntity) model.ProductEntity {
+	product.Name = r.row.Name
+	if r.row.SKU != "" {
+		product.SKU = r.row.SKU
+	}
+	if r.row.Category != "" {
+		product.CategoryName = r.row.Category
+	}
+	if r.row.Price != nil {
+		product.Price = *r.row.Price
+	}
+	if r.row.Stock != nil {
+		if product.IsBundle() {
+			r.fail("stock", "a bundle has no stock of its own")
+		}
+		product.Stocks = *r.row.Stock
+	}
+	if r.row.CostPrice != nil {
+		product.CostPrice = *r.row.CostPrice
+	}
+	if r.row.ReorderPoint != nil {
+		product.ReorderPoint = *r.row.ReorderPoint
+	}
+	if r.row.ReorderQuantity != nil {
+		product.ReorderQuantity = *r.row.ReorderQuantity
+	}
+	product.UpdatedBy = "USER"
+	validateImportRow(r, product)
+	return product
+}
+
+func newImportProduct(r *importRow) model.ProductEntity {
+	if r.row.Price == nil {
+		r.fail("price", "price is required for a new product")
+	}
+	request := model.CreateProductRequest{Name: r.row.Name, SKU: r.row.SKU, Category: r.row.Category}
+	if r.row.Price != nil {
+		request.Price = *r.row.Price
+	}
+	if r.row.Stock != nil {
+		request.Stocks = *r.row.Stock
+	}
+	if r.row.CostPrice != nil {
+		request.CostPrice = *r.row.CostPrice
+	}
+	if r.row.ReorderPoint != nil {
+		request.ReorderPoint = *r.row.ReorderPoint
+	}
+	if r.row.ReorderQuantity != nil {
+		request.ReorderQuantity = *r.row.ReorderQuantity
+	}
+	product := *request.ToEntity()
+	validateImportRow(r, product)
+	return product
+}
+
+func validateImportRow(r *importRow, product model.ProductEntity) {
+	if product.Name == "" {
+		r.fail("name", "name is required")
+	}
+	if product.Price < 0 {
+		r.fail("price", "price cannot be negative")
+	}
+	if product.Stocks < 0 {
+		r.fail("stock", "stock cannot be negative")
+	}
+	if product.CostPrice < 0 {
+		r.fail("cost_price", "cost price cannot be negative")
+	}
+	if err := validateReorder(product); err != nil {
+		r.fail("reorder_point", strings.TrimPrefix(err.Error(), ErrInvalidProduct.Error()+": "))
+	}
+}
+
+// decodeProductImport reads the file's rows, a file that cannot be read at all fails the whole import
+func decodeProductImport(format string, data []byte) ([]importRow, error) {
+	switch format {
+	case model.CatalogFormatCSV:
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid csv: %v", ErrInvalidProduct, err)
+		}
+		return parseProductTable(records)
+	case model.CatalogFormatXLSX:
+		records, err := utils.ReadXLSX(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProduct, err)
+		}
+		return parseProductTable(records)
+	case model.CatalogFormatJSON:
+		var products []model.ProductImportRow
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&products); err != nil {
+			return nil, fmt.Errorf("%w: invalid json: %v", ErrInvalidProduct, err)
+		}
+		var rows []importRow
+		for i, p := range products {
+			p.Name, p.SKU, p.Category = strings.TrimSpace(p.Name), strings.TrimSpace(p.SKU), strings.TrimSpace(p.Category)
+			rows = append(rows, importRow{number: i + 1, row: p})
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("%w: format must be csv, xlsx or json", ErrInvalidProduct)
+	}
+}
+
+// parseProductTable maps the sheet's rows onto products by its header row, blank rows are skipped
+func parseProductTable(records [][]string) ([]importRow, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make([]string, len(records[0]))
+	for i, header := range records[0] {
+		column := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), " ", "_")
+		if column != "" && !slices.Contains(model.ProductImportColumns, column) {
+			return nil, fmt.Errorf("%w: unknown column %q, the columns are %s", ErrInvalidProduct, header, strings.Join(model.ProductImportColumns, ", "))
+		}
+		if column != "" && slices.Contains(columns[:i], column) {
+			return nil, fmt.Errorf("%w: column %q appears twice", ErrInvalidProduct, header)
+		}
+		columns[i] = column
+	}
+	if !slices.Contains(columns, "name") {
+		return nil, fmt.Errorf("%w: the header row needs a name column", ErrInvalidProduct)
+	}
+
+	var rows []importRow
+	for i, record := range records[1:] {
+		r := importRow{number: i + 2}
+		blank := true
+		for j, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if j >= len(columns) || columns[j] == "" || cell == "" {
+				continue
+			}
+			blank = false
+			if err := setImportCell(&r.row, columns[j], cell); err != nil {
+				r.fail(columns[j], err.Error())
+			}
+		}
+		if !blank {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+func setImportCell(row *model.ProductImportRow, column, cell string) error {
+	switch column {
+	case "name":
+		row.Name = cell
+		return nil
+	case "sku":
+		row.SKU = cell
+		return nil
+	case "category":
+		row.Category = cell
+		return nil
+	}
+
+	n, err := parseWholeNumber(cell)
+	if err != nil {
+		return err
+	}
+	switch column {
+	case "price":
+		row.Price = &n
+	case "cost_price":
+		row.CostPrice = &n
+	default:
+		if n > math.MaxInt32 || n < math.MinInt32 {
+			return errors.New("number is too large")
+		}
+		i := int(n)
+		switch column {
+		case "stock":
+			row.Stock = &i
+		case "reorder_point":
+			row.ReorderPoint = &i
+		case "reorder_quantity":
+			row.ReorderQuantity = &i
+		}
+	}
+	return nil
+}
+
+// parseWholeNumber takes an integer, spreadsheets may write one as 18000.0 or 1.8E4
+func parseWholeNumber(cell string) (int64, error) {
+	if n, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(cell, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64/2 {
+		return 0, fmt.Errorf("%q is not a whole number", cell)
+	}
+	return int64(f), nil
+}
+
+func (s *catalogService) ExportProducts(format string, w io.Writer) error {
+	if _, ok := model.CatalogContentTypes[format]; !ok {
+		return fmt.Errorf("%w: format must be csv, xlsx or json", ErrInvalidProduct)
+	}
+	// read before writing anything, a failed read can still be answered with an error
+	products, err := s.productRepository.FindProducts()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case model.CatalogFormatJSON:
+		if _, err := io.WriteString(w, "[\n"); err != nil {
+			return err
+		}
+		for i, p := range products {
+			line, err := json.Marshal(p.ToImportRow())
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				line = append([]byte(",\n"), line...)
+			}
+			if _, err := w.Write(line); err != nil {
+				return err
+			}
+		}
+		_, err := io.WriteString(w, "\n]\n")
+		return err
+	case model.CatalogFormatXLSX:
+		sheet, err := utils.NewXLSXWriter(w)
+		if err != nil {
+			return err
+		}
+		if err := sheet.WriteRow(stringsToAny(model.ProductImportColumns)...); err != nil {
+			return err
+		}
+		for _, p := range products {
+			if err := sheet.WriteRow(exportCells(p.ToImportRow())...); err != nil {
+				return err
+			}
+		}
+		return sheet.Close()
+	default:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(model.ProductImportColumns); err != nil {
+			return err
+		}
+		for _, p := range products {
+			var record []string
+			for _, cell := range exportCells(p.ToImportRow()) {
+				record = append(record, fmt.Sprint(cell))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+}
+
+// exportCells lays the row out in the order of model.ProductImportColumns, a value left out is an empty cell
+func exportCells(row model.ProductImportRow) []any {
+	cell := func(n *int) any {
+		if n == nil {
+			return ""
+		}
+		return *n
+	}
+	cell64 := func(n *int64) any {
+		if n == nil {
+			return ""
+		}
+		return *n
+	}
+	return []any{row.Name, row.SKU, row.Category, cell64(row.Price), cell(row.Stock), cell64(row.CostPrice), cell(row.ReorderPoint), cell(row.ReorderQuantity)}
+}
+
+func stringsToAny(values []string) []any {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func catalogFixture() ([]model.ProductEntity, []model.CategoryEntity) {
+	products := []model.ProductEntity{
+		{ID: uuid.New(), Name: "Kopi", SKU: "KP-01", CategoryName: "Drinks", Price: 18000, Stocks: 40, Version: 3, Type: model.ProductTypeStandard},
+		{ID: uuid.New(), Name: "Teh", CategoryName: "Drinks", Price: 8000, Stocks: 12, Type: model.ProductTypeStandard},
+		{ID: uuid.New(), Name: "Paket Sarapan", Price: 25000, Type: model.ProductTypeBundle},
+	}
+	categories := []model.CategoryEntity{{ID: uuid.New(), Name: "Drinks"}}
+	return products, categories
+}
+
+func TestCatalogServiceImportProducts(t *testing.T) {
+	mockProductRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewCatalogService(mockProductRepo, mockCategoryRepo)
+
+	products, categories := catalogFixture()
+	mockProductRepo.On("FindProducts").Return(products, nil)
+	mockCategoryRepo.On("FindCategories").Return(categories, nil)
+
+	file := "\ufeffName,SKU,Category,Price,Stock\n" +
+		"Kopi Susu,kp-01,,20000,\n" + // renamed, matched by SKU
+		"teh,,drinks,,15\n" + // matched by name, price kept
+		"Roti,RT-01,Bakery,12000,8\n" +
+		"Paket Sarapan,,,,5\n" +
+		"Donat,,Bakery,,\n" +
+		",,,,\n" +
+		"Kue,,,abc,\n" +
+		"Roti,,,13000,\n"
+
+	mockProductRepo.On("UpdateProductByID", products[0].ID.String(), mock.MatchedBy(func(p model.ProductEntity) bool {
+		return p.Name == "Kopi Susu" && p.Price == 20000 && p.Stocks == 40 && p.Version == 3
+	})).Return(products[0], nil).Once()
+	mockProductRepo.On("UpdateProductByID", products[1].ID.String(), mock.MatchedBy(func(p model.ProductEntity) bool {
+		return p.Price == 8000 && p.Stocks == 15 && p.CategoryName == "Drinks" && p.CategoryID != nil && *p.CategoryID == categories[0].ID
+	})).Return(products[1], nil).Once()
+	bakery := model.CategoryEntity{ID: uuid.New(), Name: "Bakery"}
+	mockCategoryRepo.On("InsertCategory", mock.MatchedBy(func(c model.CategoryEntity) bool {
+		return c.Name == "Bakery"
+	})).Return(bakery, nil).Once()
+	mockProductRepo.On("InsertProduct", mock.MatchedBy(func(p model.ProductEntity) bool {
+		return p.Name == "Roti" && p.SKU == "RT-01" && p.Stocks == 8 && p.CategoryName == "Bakery" && p.CategoryID != nil && *p.CategoryID == bakery.ID && p.Type == model.ProductTypeStandard
+	})).Return(model.ProductEntity{}, nil).Once()
+
+	report, err := service.ImportProducts(model.CatalogFormatCSV, []byte(file), false)
+	require.NoError(t, err)
+	assert.Equal(t, 7, report.TotalRows, "the blank row is skipped")
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, 4, report.Failed)
+	assert.Equal(t, []string{"Bakery"}, report.CategoriesCreated)
+	assert.Equal(t, []model.ProductImportError{
+		{Row: 5, Column: "stock", Message: "a bundle has no stock of its own"},
+		{Row: 6, Column: "price", Message: "price is required for a new product"},
+		{Row: 8, Column: "price", Message: `"abc" is not a whole number`},
+		{Row: 8, Column: "price", Message: "price is required for a new product"},
+		{Row: 9, Message: "the same product as row 4"},
+	}, report.Errors)
+	mockProductRepo.AssertExpectations(t)
+	mockCategoryRepo.AssertExpectations(t)
+}
+
+func TestCatalogServiceImportProducts_DryRun(t *testing.T) {
+	mockProductRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewCatalogService(mockProductRepo, mockCategoryRepo)
+
+	products, categories := catalogFixture()
+	mockProductRepo.On("FindProducts").Return(products, nil)
+	mockCategoryRepo.On("FindCategories").Return(categories, nil)
+
+	var sheet bytes.Buffer
+	writer, err := utils.NewXLSXWriter(&sheet)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRow("name", "category", "price"))
+	require.NoError(t, writer.WriteRow("Roti", "Bakery", 12000))
+	require.NoError(t, writer.WriteRow("Kopi", "", 19000))
+	require.NoError(t, writer.Close())
+
+	report, err := service.ImportProducts(model.CatalogFormatXLSX, sheet.Bytes(), true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, []string{"Bakery"}, report.CategoriesCreated)
+	mockProductRepo.AssertNotCalled(t, "InsertProduct", mock.Anything)
+	mockProductRepo.AssertNotCalled(t, "UpdateProductByID", mock.Anything, mock.Anything)
+	mockCategoryRepo.AssertNotCalled(t, "InsertCategory", mock.Anything)
+}
+
+func TestCatalogServiceImportProducts_InvalidFile(t *testing.T) {
+	service := NewCatalogService(new(mocks.MockProductRepository), new(mocks.MockCategoryRepository))
+
+	for name, c := range map[string]struct {
+		format string
+		data   string
+	}{
+		"unknown format": {"ods", "name\nKopi\n"},
+		"no name column": {model.CatalogFormatCSV, "sku,price\nKP-01,1000\n"},
+		"unknown column": {model.CatalogFormatCSV, "name,harga\nKopi,1000\n"},
+		"header only":    {model.CatalogFormatCSV, "name,price\n"},
+		"not a workbook": {model.CatalogFormatXLSX, "name,price\n"},
+		"unknown field":  {model.CatalogFormatJSON, `[{"name":"Kopi","harga":1000}]`},
+	} {
+		_, err := service.ImportProducts(c.format, []byte(c.data), false)
+		assert.ErrorIs(t, err, ErrInvalidProduct, name)
+	}
+}
+
+func TestCatalogServiceExportProducts(t *testing.T) {
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewCatalogService(mockProductRepo, new(mocks.MockCategoryRepository))
+
+	products, _ := catalogFixture()
+	mockProductRepo.On("FindProducts").Return(products, nil)
+
+	var out bytes.Buffer
+	require.NoError(t, service.ExportProducts(model.CatalogFormatCSV, &out))
+	assert.Equal(t, "name,sku,category,price,stock,cost_price,reorder_point,reorder_quantity\n"+
+		"Kopi,KP-01,Drinks,18000,40,0,0,0\n"+
+		"Teh,,Drinks,8000,12,0,0,0\n"+
+		"Paket Sarapan,,,25000,,0,0,0\n", out.String())
+
+	out.Reset()
+	require.NoError(t, service.ExportProducts(model.CatalogFormatXLSX, &out))
+	rows, err := utils.ReadXLSX(out.Bytes())
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"Kopi", "KP-01", "Drinks", "18000", "40", "0", "0", "0"}, rows[1])
+
+	out.Reset()
+	require.NoError(t, service.ExportProducts(model.CatalogFormatJSON, &out))
+	var exported []model.ProductImportRow
+	require.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	require.Len(t, exported, 3)
+	assert.Nil(t, exported[2].Stock)
+
+	err = service.ExportProducts("ods", &out)
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+}
+
+func TestCatalogServiceExportProducts_ImportsBack(t *testing.T) {
+	mockProductRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewCatalogService(mockProductRepo, mockCategoryRepo)
+
+	products, categories := catalogFixture()
+	mockProductRepo.On("FindProducts").Return(products, nil)
+	mockCategoryRepo.On("FindCategories").Return(categories, nil)
+
+	var out bytes.Buffer
+	require.NoError(t, service.ExportProducts(model.CatalogFormatCSV, &out))
+	report, err := service.ImportProducts(model.CatalogFormatCSV, out.Bytes(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Updated)
+	assert.Empty(t, report.Errors, strings.TrimSpace(out.String()))
+}
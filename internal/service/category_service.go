@@ -1,28 +1,37 @@
 package service
 
 import (
+	"fmt"
+
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/repository"
 	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
 )
 
 // TODO: optional try to implement partial update (PATCH)
 // TODO: implement proper optimistic locking through etag
+// The categories fetched carry the counts and stock value of their products, subcategories included.
 type CategoryService interface {
 	FetchCategories() ([]model.Category, error)
 	FetchCategoryByID(id string) (model.Category, error)
+	// FetchCategoryTree returns the category with every category below it
+	FetchCategoryTree(id string) (model.CategoryNode, error)
 	CreateCategory(category model.CreateCategoryRequest) (model.Category, error)
 	UpdateCategoryByID(id string, category model.UpdateCategoryRequest) (model.Category, error)
+	// DeleteCategoryByID refuses a category that still has subcategories, they are moved or deleted first
 	DeleteCategoryByID(id string) error
 }
 
 type categoryService struct {
-	repository repository.CategoryRepository
+	repository        repository.CategoryRepository
+	productRepository repository.ProductRepository
 }
 
-func NewCategoryService(repository repository.CategoryRepository) CategoryService {
+func NewCategoryService(repository repository.CategoryRepository, productRepository repository.ProductRepository) CategoryService {
 	return &categoryService{
-		repository: repository,
+		repository:        repository,
+		productRepository: productRepository,
 	}
 }
 
@@ -31,9 +40,17 @@ func (s *categoryService) FetchCategories() ([]model.Category, error) {
 	if err != nil {
 		return nil, err
 	}
+	tree := model.NewCategoryTree(entities)
+	stats, err := s.productStats()
+	if err != nil {
+		return nil, err
+	}
 	categories := []model.Category{}
 	for _, entity := range entities {
-		categories = append(categories, *entity.ToModel())
+		category := entity.ToModel()
+		category.Breadcrumb = tree.Breadcrumb(entity.ID)
+		category.Products = rollUpProductStats(tree, stats, entity.ID)
+		categories = append(categories, *category)
 	}
 	return categories, nil
 }
@@ -43,11 +60,41 @@ func (s *categoryService) FetchCategoryByID(id string) (model.Category, error) {
 	if err != nil {
 		return model.Category{}, err
 	}
-	return *entity.ToModel(), nil
+	tree, err := s.tree()
+	if err != nil {
+		return model.Category{}, err
+	}
+	stats, err := s.productStats()
+	if err != nil {
+		return model.Category{}, err
+	}
+	category := entity.ToModel()
+	category.Breadcrumb = tree.Breadcrumb(entity.ID)
+	category.Products = rollUpProductStats(tree, stats, entity.ID)
+	return *category, nil
+}
+
+func (s *categoryService) FetchCategoryTree(id string) (model.CategoryNode, error) {
+	tree, err := s.tree()
+	if err != nil {
+		return model.CategoryNode{}, err
+	}
+	categoryID, _ := uuid.Parse(utils.DecodeBase62(id))
+	node := tree.Node(categoryID)
+	if node == nil {
+		return model.CategoryNode{}, fmt.Errorf("%w: %s", ErrCategoryNotFound, id)
+	}
+	return *node, nil
 }
 
 func (s *categoryService) CreateCategory(request model.CreateCategoryRequest) (model.Category, error) {
-	entity, err := s.repository.InsertCategory(*request.ToEntity())
+	category := *request.ToEntity()
+	if category.ParentID != nil {
+		if _, err := s.findParent(*category.ParentID); err != nil {
+			return model.Category{}, err
+		}
+	}
+	entity, err := s.repository.InsertCategory(category)
 	if err != nil {
 		return model.Category{}, err
 	}
@@ -55,7 +102,18 @@ func (s *categoryService) CreateCategory(request model.CreateCategoryRequest) (m
 }
 
 func (s *categoryService) UpdateCategoryByID(id string, request model.UpdateCategoryRequest) (model.Category, error) {
-	entity, err := s.repository.UpdateCategoryByID(utils.DecodeBase62(id), *request.ToEntity())
+	category := *request.ToEntity()
+	if category.ParentID != nil {
+		tree, err := s.findParent(*category.ParentID)
+		if err != nil {
+			return model.Category{}, err
+		}
+		categoryID, _ := uuid.Parse(utils.DecodeBase62(id))
+		if tree.IsWithin(*category.ParentID, categoryID) {
+			return model.Category{}, fmt.Errorf("%w: a category cannot be moved under itself or its own subcategory", ErrInvalidCategory)
+		}
+	}
+	entity, err := s.repository.UpdateCategoryByID(utils.DecodeBase62(id), category)
 	if err != nil {
 		return model.Category{}, err
 	}
@@ -63,5 +121,54 @@ func (s *categoryService) UpdateCategoryByID(id string, request model.UpdateCate
 }
 
 func (s *categoryService) DeleteCategoryByID(id string) error {
+	tree, err := s.tree()
+	if err != nil {
+		return err
+	}
+	categoryID, _ := uuid.Parse(utils.DecodeBase62(id))
+	if subtree := tree.Subtree(categoryID); len(subtree) > 1 {
+		return fmt.Errorf("%w: %d categories are below it", ErrCategoryHasSubcategories, len(subtree)-1)
+	}
 	return s.repository.DeleteCategoryByID(utils.DecodeBase62(id))
 }
+
+// productStats indexes the products' counts by the category they are directly in
+func (s *categoryService) productStats() (map[uuid.UUID]model.CategoryProductStatsEntity, error) {
+	entities, err := s.productRepository.FindCategoryProductStats()
+	if err != nil {
+		return nil, err
+	}
+	stats := map[uuid.UUID]model.CategoryProductStatsEntity{}
+	for _, entity := range entities {
+		stats[entity.CategoryID] = entity
+	}
+	return stats, nil
+}
+
+func rollUpProductStats(tree *model.CategoryTree, stats map[uuid.UUID]model.CategoryProductStatsEntity, id uuid.UUID) *model.CategoryProductStats {
+	total := &model.CategoryProductStats{}
+	for _, categoryID := range tree.Subtree(id) {
+		total.Add(stats[categoryID])
+	}
+	return total
+}
+
+func (s *categoryService) tree() (*model.CategoryTree, error) {
+	entities, err := s.repository.FindCategories()
+	if err != nil {
+		return nil, err
+	}
+	return model.NewCategoryTree(entities), nil
+}
+
+// findParent checks the parent is a live category and returns the tree it is in
+func (s *categoryService) findParent(parentID uuid.UUID) (*model.CategoryTree, error) {
+	tree, err := s.tree()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tree.Find(parentID); !ok {
+		return nil, fmt.Errorf("%w: parent category not found", ErrInvalidCategory)
+	}
+	return tree, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	mocks "codewithumam-kasir-api/internal/mock"
 	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +17,8 @@ import (
 
 func TestCategoryServiceFetchCategories(t *testing.T) {
 	mockRepo := new(mocks.MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewCategoryService(mockRepo, mockProductRepo)
 
 	now := time.Now()
 	entities := []model.CategoryEntity{
@@ -25,6 +27,7 @@ func TestCategoryServiceFetchCategories(t *testing.T) {
 	}
 
 	mockRepo.On("FindCategories").Return(entities, nil)
+	mockProductRepo.On("FindCategoryProductStats").Return([]model.CategoryProductStatsEntity{}, nil)
 
 	categories, err := service.FetchCategories()
 
@@ -37,7 +40,7 @@ func TestCategoryServiceFetchCategories(t *testing.T) {
 
 func TestCategoryServiceFetchCategoriesError(t *testing.T) {
 	mockRepo := new(mocks.MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(mocks.MockProductRepository))
 
 	mockRepo.On("FindCategories").Return(nil, errors.New("database error"))
 
@@ -50,7 +53,8 @@ func TestCategoryServiceFetchCategoriesError(t *testing.T) {
 
 func TestCategoryServiceFetchCategoryByID(t *testing.T) {
 	mockRepo := new(mocks.MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewCategoryService(mockRepo, mockProductRepo)
 
 	now := time.Now()
 	id := uuid.New()
@@ -60,17 +64,20 @@ func TestCategoryServiceFetchCategoryByID(t *testing.T) {
 	}
 
 	mockRepo.On("FindCategoryByID", mock.Anything).Return(entity, nil)
+	mockRepo.On("FindCategories").Return([]model.CategoryEntity{entity}, nil)
+	mockProductRepo.On("FindCategoryProductStats").Return([]model.CategoryProductStatsEntity{}, nil)
 
 	category, err := service.FetchCategoryByID("test-id")
 
 	require.NoError(t, err)
 	assert.Equal(t, "Electronics", category.Name)
+	assert.Equal(t, []model.CategoryCrumb{{ID: category.ID, Name: "Electronics"}}, category.Breadcrumb)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestCategoryServiceCreateCategory(t *testing.T) {
 	mockRepo := new(mocks.MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(mocks.MockProductRepository))
 
 	request := model.CreateCategoryRequest{
 		Name:        "New Category",
@@ -92,7 +99,7 @@ func TestCategoryServiceCreateCategory(t *testing.T) {
 
 func TestCategoryServiceUpdateCategoryByID(t *testing.T) {
 	mockRepo := new(mocks.MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(mocks.MockProductRepository))
 
 	request := model.UpdateCategoryRequest{
 		Name:        "Updated",
@@ -115,8 +122,9 @@ func TestCategoryServiceUpdateCategoryByID(t *testing.T) {
 
 func TestCategoryServiceDeleteCategoryByID(t *testing.T) {
 	mockRepo := new(mocks.MockCategoryRepository)
-	service := NewCategoryService(mockRepo)
+	service := NewCategoryService(mockRepo, new(mocks.MockProductRepository))
 
+	mockRepo.On("FindCategories").Return([]model.CategoryEntity{}, nil)
 	mockRepo.On("DeleteCategoryByID", mock.Anything).Return(nil)
 
 	err := service.DeleteCategoryByID("test-id")
@@ -124,3 +132,77 @@ func TestCategoryServiceDeleteCategoryByID(t *testing.T) {
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCategoryServiceHierarchy(t *testing.T) {
+	mockRepo := new(mocks.MockCategoryRepository)
+	service := NewCategoryService(mockRepo, new(mocks.MockProductRepository))
+
+	beverages, coffee, espresso := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("FindCategories").Return([]model.CategoryEntity{
+		{ID: beverages, Name: "Beverages"},
+		{ID: coffee, ParentID: &beverages, Name: "Coffee"},
+		{ID: espresso, ParentID: &coffee, Name: "Espresso"},
+	}, nil)
+	mockRepo.On("InsertCategory", mock.AnythingOfType("model.CategoryEntity")).Return(model.CategoryEntity{ID: uuid.New(), ParentID: &espresso, Name: "Ristretto"}, nil)
+	mockRepo.On("UpdateCategoryByID", beverages.String(), mock.AnythingOfType("model.CategoryEntity")).Return(model.CategoryEntity{ID: beverages, Name: "Beverages"}, nil)
+	id := func(id uuid.UUID) string { return utils.EncodeBase62(id.String()) }
+
+	tree, err := service.FetchCategoryTree(id(beverages))
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 1)
+	require.Len(t, tree.Children[0].Children, 1)
+	assert.Equal(t, "Espresso", tree.Children[0].Children[0].Name)
+	assert.Equal(t, []string{"Beverages", "Coffee", "Espresso"}, crumbNames(tree.Children[0].Children[0].Breadcrumb))
+
+	_, err = service.FetchCategoryTree(id(uuid.New()))
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+
+	created, err := service.CreateCategory(model.CreateCategoryRequest{Name: "Ristretto", ParentID: id(espresso)})
+	require.NoError(t, err)
+	assert.Equal(t, id(espresso), created.ParentID)
+	_, err = service.CreateCategory(model.CreateCategoryRequest{Name: "Tea", ParentID: id(uuid.New())})
+	assert.ErrorIs(t, err, ErrInvalidCategory)
+
+	for _, parent := range []uuid.UUID{beverages, espresso} {
+		_, err = service.UpdateCategoryByID(id(beverages), model.UpdateCategoryRequest{Name: "Beverages", ParentID: id(parent), Version: 1})
+		assert.ErrorIs(t, err, ErrInvalidCategory, "a category cannot sit below itself")
+	}
+	_, err = service.UpdateCategoryByID(id(beverages), model.UpdateCategoryRequest{Name: "Beverages", Version: 1})
+	assert.NoError(t, err, "moving to the top level")
+
+	err = service.DeleteCategoryByID(id(coffee))
+	assert.ErrorIs(t, err, ErrCategoryHasSubcategories)
+	mockRepo.AssertNotCalled(t, "DeleteCategoryByID", mock.Anything)
+}
+
+func crumbNames(crumbs []model.CategoryCrumb) []string {
+	names := []string{}
+	for _, c := range crumbs {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestCategoryServiceFetchCategories_ProductStats(t *testing.T) {
+	mockRepo := new(mocks.MockCategoryRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewCategoryService(mockRepo, mockProductRepo)
+
+	beverages, coffee, snacks := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("FindCategories").Return([]model.CategoryEntity{
+		{ID: beverages, Name: "Beverages"},
+		{ID: coffee, ParentID: &beverages, Name: "Coffee"},
+		{ID: snacks, Name: "Snacks"},
+	}, nil)
+	mockProductRepo.On("FindCategoryProductStats").Return([]model.CategoryProductStatsEntity{
+		{CategoryID: beverages, ActiveProducts: 1, StockValue: 20000},
+		{CategoryID: coffee, ActiveProducts: 3, InactiveProducts: 1, StockValue: 150000},
+	}, nil)
+
+	categories, err := service.FetchCategories()
+	require.NoError(t, err)
+	require.Len(t, categories, 3)
+	assert.Equal(t, &model.CategoryProductStats{ProductCount: 5, ActiveProductCount: 4, InactiveProductCount: 1, StockValue: 170000}, categories[0].Products, "a category counts its subcategories' products")
+	assert.Equal(t, &model.CategoryProductStats{ProductCount: 4, ActiveProductCount: 3, InactiveProductCount: 1, StockValue: 150000}, categories[1].Products)
+	assert.Equal(t, &model.CategoryProductStats{}, categories[2].Products)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type CustomerService interface {
+	// FetchCustomers lists every customer, or only the one holding phone when it is given
+	FetchCustomers(phone string) ([]model.Customer, error)
+	FetchCustomerByID(id string) (model.Customer, error)
+	CreateCustomer(customer model.CreateCustomerRequest) (model.Customer, error)
+	UpdateCustomerByID(id string, customer model.UpdateCustomerRequest) (model.Customer, error)
+	DeleteCustomerByID(id string) error
+	FetchCustomerTransactions(id string) (model.CustomerHistory, error)
+}
+
+type customerService struct {
+	repository    repository.CustomerRepository
+	txRepository  repository.TransactionRepository
+	priceListRepo repository.PriceListRepository
+}
+
+func NewCustomerService(repository repository.CustomerRepository, txRepository repository.TransactionRepository, priceListRepo repository.PriceListRepository) CustomerService {
+	return &customerService{
+		repository:    repository,
+		txRepository:  txRepository,
+		priceListRepo: priceListRepo,
+	}
+}
+
+func (s *customerService) FetchCustomers(phone string) ([]model.Customer, error) {
+	var entities []model.CustomerEntity
+	var err error
+	if strings.TrimSpace(phone) != "" {
+		entities, err = s.repository.FindCustomersByPhone(model.NormalizePhone(phone))
+	} else {
+		entities, err = s.repository.FindCustomers()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	customers := []model.Customer{}
+	for _, entity := range entities {
+		customers = append(customers, *entity.ToModel())
+	}
+	return customers, nil
+}
+
+func (s *customerService) FetchCustomerByID(id string) (model.Customer, error) {
+	entity, err := s.repository.FindCustomerByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.Customer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *customerService) CreateCustomer(request model.CreateCustomerRequest) (model.Customer, error) {
+	customer := *request.ToEntity()
+	if err := s.validateCustomer(customer, uuid.Nil); err != nil {
+		return model.Customer{}, err
+	}
+
+	entity, err := s.repository.InsertCustomer(customer)
+	if err != nil {
+		return model.Customer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *customerService) UpdateCustomerByID(id string, request model.UpdateCustomerRequest) (model.Customer, error) {
+	customer := *request.ToEntity()
+	customerID, _ := uuid.Parse(utils.DecodeBase62(id))
+	if err := s.validateCustomer(customer, customerID); err != nil {
+		return model.Customer{}, err
+	}
+
+	entity, err := s.repository.UpdateCustomerByID(utils.DecodeBase62(id), customer)
+	if err != nil {
+		return model.Customer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *customerService) DeleteCustomerByID(id string) error {
+	return s.repository.DeleteCustomerByID(utils.DecodeBase62(id))
+}
+
+func (s *customerService) FetchCustomerTransactions(id string) (model.CustomerHistory, error) {
+	customer, err := s.repository.FindCustomerByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.CustomerHistory{}, err
+	}
+	transactions, err := s.txRepository.FindTransactionsByCustomer(customer.ID.String())
+	if err != nil {
+		return model.CustomerHistory{}, err
+	}
+	return *model.NewCustomerHistory(customer, transactions), nil
+}
+
+// validateCustomer requires a name, an existing price list when one is given and a phone number
+// no other customer holds, self is the customer being updated
+func (s *customerService) validateCustomer(customer model.CustomerEntity, self uuid.UUID) error {
+	if strings.TrimSpace(customer.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCustomer)
+	}
+	if customer.PriceListID != nil {
+		if *customer.PriceListID == uuid.Nil {
+			return fmt.Errorf("%w: price list not found", ErrInvalidCustomer)
+		}
+		priceList, err := s.priceListRepo.FindPriceListByID(customer.PriceListID.String())
+		if err != nil || priceList.DeletedAt != nil {
+			return fmt.Errorf("%w: price list not found", ErrInvalidCustomer)
+		}
+	}
+	if customer.Phone == "" {
+		return nil
+	}
+	holders, err := s.repository.FindCustomersByPhone(customer.Phone)
+	if err != nil {
+		return err
+	}
+	for _, holder := range holders {
+		if holder.ID != self {
+			return fmt.Errorf("%w: phone %s already belongs to %s", ErrInvalidCustomer, customer.Phone, holder.Name)
+		}
+	}
+	return nil
+}
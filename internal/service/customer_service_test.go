@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerServiceCreateCustomer(t *testing.T) {
+	mockRepo := new(mocks.MockCustomerRepository)
+	service := NewCustomerService(mockRepo, new(mocks.MockTransactionRepository), new(mocks.MockPriceListRepository))
+
+	mockRepo.On("FindCustomersByPhone", "+628123456").Return(nil, nil)
+	mockRepo.On("InsertCustomer", mock.MatchedBy(func(c model.CustomerEntity) bool {
+		return c.Name == "Budi" && c.Phone == "+628123456"
+	})).Return(model.CustomerEntity{ID: uuid.New(), Name: "Budi", Phone: "+628123456"}, nil)
+
+	customer, err := service.CreateCustomer(model.CreateCustomerRequest{Name: "Budi", Phone: "+62 812-3456"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "+628123456", customer.Phone)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerServiceCreateCustomer_Invalid(t *testing.T) {
+	mockRepo := new(mocks.MockCustomerRepository)
+	service := NewCustomerService(mockRepo, new(mocks.MockTransactionRepository), new(mocks.MockPriceListRepository))
+
+	holderID := uuid.New()
+	mockRepo.On("FindCustomersByPhone", "08123456").Return([]model.CustomerEntity{{ID: holderID, Name: "Siti", Phone: "08123456"}}, nil)
+	mockRepo.On("UpdateCustomerByID", holderID.String(), mock.Anything).Return(model.CustomerEntity{ID: holderID, Name: "Siti"}, nil)
+
+	_, err := service.CreateCustomer(model.CreateCustomerRequest{Name: "  "})
+	assert.ErrorIs(t, err, ErrInvalidCustomer)
+
+	_, err = service.CreateCustomer(model.CreateCustomerRequest{Name: "Budi", Phone: "0812 3456"})
+	assert.ErrorIs(t, err, ErrInvalidCustomer)
+	mockRepo.AssertNotCalled(t, "InsertCustomer", mock.Anything)
+
+	_, err = service.UpdateCustomerByID(utils.EncodeBase62(holderID.String()), model.UpdateCustomerRequest{Name: "Siti", Phone: "08123456"})
+	assert.NoError(t, err, "a customer keeps their own phone number")
+}
+
+func TestCustomerServiceCreateCustomer_PriceList(t *testing.T) {
+	mockRepo := new(mocks.MockCustomerRepository)
+	mockPriceListRepo := new(mocks.MockPriceListRepository)
+	service := NewCustomerService(mockRepo, new(mocks.MockTransactionRepository), mockPriceListRepo)
+
+	listID, deletedID := uuid.New(), uuid.New()
+	deletedAt := time.Now()
+	mockPriceListRepo.On("FindPriceListByID", listID.String()).Return(model.PriceListEntity{ID: listID, Code: "MEMBER", IsActive: true}, nil)
+	mockPriceListRepo.On("FindPriceListByID", deletedID.String()).Return(model.PriceListEntity{ID: deletedID, DeletedAt: &deletedAt}, nil)
+	mockRepo.On("InsertCustomer", mock.MatchedBy(func(c model.CustomerEntity) bool {
+		return c.PriceListID != nil && *c.PriceListID == listID
+	})).Return(model.CustomerEntity{ID: uuid.New(), Name: "Budi", PriceListID: &listID}, nil)
+
+	customer, err := service.CreateCustomer(model.CreateCustomerRequest{Name: "Budi", PriceListID: utils.EncodeBase62(listID.String())})
+	require.NoError(t, err)
+	assert.Equal(t, utils.EncodeBase62(listID.String()), customer.PriceListID)
+
+	_, err = service.CreateCustomer(model.CreateCustomerRequest{Name: "Budi", PriceListID: utils.EncodeBase62(deletedID.String())})
+	assert.ErrorIs(t, err, ErrInvalidCustomer)
+	_, err = service.CreateCustomer(model.CreateCustomerRequest{Name: "Budi", PriceListID: "!!"})
+	assert.ErrorIs(t, err, ErrInvalidCustomer)
+	mockRepo.AssertNumberOfCalls(t, "InsertCustomer", 1)
+}
+
+func TestCustomerServiceFetchCustomers_ByPhone(t *testing.T) {
+	mockRepo := new(mocks.MockCustomerRepository)
+	service := NewCustomerService(mockRepo, new(mocks.MockTransactionRepository), new(mocks.MockPriceListRepository))
+
+	mockRepo.On("FindCustomersByPhone", "08123456").Return([]model.CustomerEntity{{ID: uuid.New(), Name: "Budi"}}, nil)
+	mockRepo.On("FindCustomers").Return(nil, nil)
+
+	customers, err := service.FetchCustomers(" 0812-3456 ")
+	require.NoError(t, err)
+	assert.Len(t, customers, 1)
+
+	customers, err = service.FetchCustomers("")
+	require.NoError(t, err)
+	assert.NotNil(t, customers)
+	assert.Empty(t, customers)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCustomerServiceFetchCustomerTransactions(t *testing.T) {
+	mockRepo := new(mocks.MockCustomerRepository)
+	mockTxRepo := new(mocks.MockTransactionRepository)
+	service := NewCustomerService(mockRepo, mockTxRepo, new(mocks.MockPriceListRepository))
+
+	customerID := uuid.New()
+	mockRepo.On("FindCustomerByID", customerID.String()).Return(model.CustomerEntity{ID: customerID, Name: "Budi"}, nil)
+	mockTxRepo.On("FindTransactionsByCustomer", customerID.String()).Return([]model.TransactionEntity{
+		{ID: uuid.New(), CreatedAt: time.Now(), TotalPriceAmount: 25000, CustomerID: &customerID},
+		{ID: uuid.New(), CreatedAt: time.Now().AddDate(0, 0, -7), TotalPriceAmount: 15000, CustomerID: &customerID},
+	}, nil)
+
+	history, err := service.FetchCustomerTransactions(utils.EncodeBase62(customerID.String()))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Budi", history.Customer.Name)
+	assert.Equal(t, int64(40000), history.LifetimeSpend.Amount)
+	assert.Equal(t, 2, history.VisitCount)
+	assert.Len(t, history.Transactions, 2)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type DiningTableService interface {
+	// FetchDiningTables takes an optional outlet, every table is listed when it is empty
+	FetchDiningTables(outletID string) ([]model.DiningTable, error)
+	FetchDiningTableByID(id string) (model.DiningTable, error)
+	CreateDiningTable(request model.CreateDiningTableRequest) (model.DiningTable, error)
+	UpdateDiningTableByID(id string, request model.UpdateDiningTableRequest) (model.DiningTable, error)
+}
+
+type diningTableService struct {
+	repository       repository.DiningTableRepository
+	outletRepository repository.OutletRepository
+}
+
+func NewDiningTableService(repository repository.DiningTableRepository, outletRepository repository.OutletRepository) DiningTableService {
+	return &diningTableService{
+		repository:       repository,
+		outletRepository: outletRepository,
+	}
+}
+
+func (s *diningTableService) FetchDiningTables(outletID string) ([]model.DiningTable, error) {
+	var parsed *uuid.UUID
+	if outletID != "" {
+		id, err := uuid.Parse(utils.DecodeBase62(outletID))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid outlet_id", ErrInvalidDiningTable)
+		}
+		parsed = &id
+	}
+	entities, err := s.repository.FindDiningTables(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []model.DiningTable{}
+	for _, entity := range entities {
+		tables = append(tables, *entity.ToModel())
+	}
+	return tables, nil
+}
+
+func (s *diningTableService) FetchDiningTableByID(id string) (model.DiningTable, error) {
+	entity, err := s.repository.FindDiningTableByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.DiningTable{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *diningTableService) CreateDiningTable(request model.CreateDiningTableRequest) (model.DiningTable, error) {
+	table := *request.ToEntity()
+	if err := validateDiningTable(table); err != nil {
+		return model.DiningTable{}, err
+	}
+	if table.OutletID != nil {
+		outlet, err := s.outletRepository.FindOutletByID(table.OutletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return model.DiningTable{}, fmt.Errorf("%w: outlet not found", ErrInvalidDiningTable)
+		}
+	}
+
+	entity, err := s.repository.InsertDiningTable(table)
+	if err != nil {
+		return model.DiningTable{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *diningTableService) UpdateDiningTableByID(id string, request model.UpdateDiningTableRequest) (model.DiningTable, error) {
+	table := *request.ToEntity()
+	if err := validateDiningTable(table); err != nil {
+		return model.DiningTable{}, err
+	}
+
+	entity, err := s.repository.UpdateDiningTableByID(utils.DecodeBase62(id), table)
+	if err != nil {
+		return model.DiningTable{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func validateDiningTable(table model.DiningTableEntity) error {
+	if table.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidDiningTable)
+	}
+	if table.Seats < 0 {
+		return fmt.Errorf("%w: seats cannot be negative", ErrInvalidDiningTable)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiningTableServiceCreateDiningTable(t *testing.T) {
+	mockRepo := new(mocks.MockDiningTableRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	service := NewDiningTableService(mockRepo, mockOutletRepo)
+
+	outletID, unknown := uuid.New(), uuid.New()
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", unknown.String()).Return(model.OutletEntity{}, errors.New("outlet not found"))
+	mockRepo.On("InsertDiningTable", mock.MatchedBy(func(table model.DiningTableEntity) bool {
+		return table.Name == "7" && *table.OutletID == outletID
+	})).Return(model.DiningTableEntity{ID: uuid.New(), OutletID: &outletID, Name: "7", IsActive: true}, nil)
+
+	for _, request := range []model.CreateDiningTableRequest{
+		{Name: " "},
+		{Name: "7", Seats: -1},
+		{Name: "7", OutletID: utils.EncodeBase62(unknown.String())},
+	} {
+		_, err := service.CreateDiningTable(request)
+		assert.ErrorIs(t, err, ErrInvalidDiningTable)
+	}
+
+	table, err := service.CreateDiningTable(model.CreateDiningTableRequest{Name: "7", OutletID: utils.EncodeBase62(outletID.String())})
+	require.NoError(t, err)
+	assert.False(t, table.Occupied)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDiningTableServiceFetchDiningTables(t *testing.T) {
+	mockRepo := new(mocks.MockDiningTableRepository)
+	service := NewDiningTableService(mockRepo, new(mocks.MockOutletRepository))
+
+	outletID, draftID := uuid.New(), uuid.New()
+	mockRepo.On("FindDiningTables", &outletID).Return([]model.DiningTableEntity{{ID: uuid.New(), Name: "7", DraftOrderID: &draftID}}, nil)
+
+	tables, err := service.FetchDiningTables(utils.EncodeBase62(outletID.String()))
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	assert.True(t, tables[0].Occupied)
+
+	_, err = service.FetchDiningTables("not-an-id")
+	assert.ErrorIs(t, err, ErrInvalidDiningTable)
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// DraftOrderService parks orders and runs table tabs. A draft collects items while it is open or held
+// and is paid through TransactionService when it is converted.
+type DraftOrderService interface {
+	// FetchDraftOrders takes an optional status, the open and held drafts are listed when it is empty
+	FetchDraftOrders(status string) ([]model.DraftOrder, error)
+	FetchDraftOrderByID(id string) (model.DraftOrder, error)
+	CreateDraftOrder(request model.CreateDraftOrderRequest) (model.DraftOrder, error)
+	// AddDraftOrderItem adds the quantity to the product's line, opening the line when the product is new to the draft
+	AddDraftOrderItem(id string, request model.AddDraftOrderItemRequest) (model.DraftOrder, error)
+	RemoveDraftOrderItem(id string, productID string) (model.DraftOrder, error)
+	// HoldDraftOrder parks the draft, it needs a label or a table number to be found again
+	HoldDraftOrder(id string, request model.HoldDraftOrderRequest) (model.DraftOrder, error)
+	ResumeDraftOrder(id string) (model.DraftOrder, error)
+	CancelDraftOrder(id string) (model.DraftOrder, error)
+	// AssignDraftOrderTable moves the party to a free dining table at the draft's outlet
+	AssignDraftOrderTable(id string, request model.AssignDraftOrderTableRequest) (model.DraftOrder, error)
+	// ConvertDraftOrder rings the draft up as a transaction, the draft is converted with the sale
+	ConvertDraftOrder(id string, request model.ConvertDraftOrderRequest) (model.Transaction, error)
+}
+
+type draftOrderService struct {
+	repository         repository.DraftOrderRepository
+	productRepository  repository.ProductRepository
+	outletRepository   repository.OutletRepository
+	customerRepository repository.CustomerRepository
+	tableRepository    repository.DiningTableRepository
+	txService          TransactionService
+	reserveStock       bool
+}
+
+// NewDraftOrderService takes reserveStock from config.DraftOrderConfig, the drafts created while it is on
+// hold their items back from other sales until they are converted or cancelled
+func NewDraftOrderService(repository repository.DraftOrderRepository, productRepository repository.ProductRepository, outletRepository repository.OutletRepository, customerRepository repository.CustomerRepository, tableRepository repository.DiningTableRepository, txService TransactionService, reserveStock bool) DraftOrderService {
+	return &draftOrderService{
+		repository:         repository,
+		productRepository:  productRepository,
+		outletRepository:   outletRepository,
+		customerRepository: customerRepository,
+		tableRepository:    tableRepository,
+		txService:          txService,
+		reserveStock:       reserveStock,
+	}
+}
+
+func (s *draftOrderService) FetchDraftOrders(status string) ([]model.DraftOrder, error) {
+	switch status {
+	case "", model.DraftOrderOpen, model.DraftOrderHeld, model.DraftOrderConverted, model.DraftOrderCancelled:
+	default:
+		return nil, fmt.Errorf("%w: status must be %s, %s, %s or %s", ErrInvalidDraftOrder,
+			model.DraftOrderOpen, model.DraftOrderHeld, model.DraftOrderConverted, model.DraftOrderCancelled)
+	}
+	entities, err := s.repository.FindDraftOrders(status)
+	if err != nil {
+		return nil, err
+	}
+
+	drafts := []model.DraftOrder{}
+	for _, entity := range entities {
+		drafts = append(drafts, *entity.ToModel())
+	}
+	return drafts, nil
+}
+
+func (s *draftOrderService) FetchDraftOrderByID(id string) (model.DraftOrder, error) {
+	entity, err := s.repository.FindDraftOrderByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *draftOrderService) CreateDraftOrder(request model.CreateDraftOrderRequest) (model.DraftOrder, error) {
+	draft := *request.ToEntity()
+	draft.ReservesStock = s.reserveStock
+	if draft.TableID != nil {
+		table, err := s.findFreeTable(draft, *draft.TableID)
+		if err != nil {
+			return model.DraftOrder{}, err
+		}
+		// a tab opened at a table runs at the table's outlet
+		if draft.OutletID == nil {
+			draft.OutletID = table.OutletID
+		}
+		if err := seatAt(&draft, table); err != nil {
+			return model.DraftOrder{}, err
+		}
+	}
+	if draft.OutletID != nil {
+		outlet, err := s.outletRepository.FindOutletByID(draft.OutletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return model.DraftOrder{}, fmt.Errorf("%w: outlet not found", ErrInvalidDraftOrder)
+		}
+		if !outlet.IsActive {
+			return model.DraftOrder{}, fmt.Errorf("%w: outlet %s is not active", ErrInvalidDraftOrder, outlet.Code)
+		}
+	}
+	if draft.CustomerID != nil {
+		customer, err := s.customerRepository.FindCustomerByID(draft.CustomerID.String())
+		if err != nil || customer.DeletedAt != nil {
+			return model.DraftOrder{}, fmt.Errorf("%w: customer not found", ErrInvalidDraftOrder)
+		}
+	}
+	for _, item := range draft.Items {
+		product, err := s.findProduct(item)
+		if err != nil {
+			return model.DraftOrder{}, err
+		}
+		if err := s.reserve(draft, product, item.Quantity); err != nil {
+			return model.DraftOrder{}, err
+		}
+	}
+
+	entity, err := s.repository.InsertDraftOrder(draft)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *draftOrderService) AddDraftOrderItem(id string, request model.AddDraftOrderItemRequest) (model.DraftOrder, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	item := *request.ToEntity(draft.ID)
+	product, err := s.findProduct(item)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+
+	item.Quantity += draft.ItemQuantity(item.ProductID)
+	if err := s.reserve(draft, product, item.Quantity); err != nil {
+		return model.DraftOrder{}, err
+	}
+	entity, err := s.repository.SetDraftOrderItem(item)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *draftOrderService) RemoveDraftOrderItem(id string, productID string) (model.DraftOrder, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	parsed, err := uuid.Parse(utils.DecodeBase62(productID))
+	if err != nil || draft.ItemQuantity(parsed) == 0 {
+		return model.DraftOrder{}, fmt.Errorf("%w: the product is not on the draft", ErrInvalidDraftOrder)
+	}
+
+	entity, err := s.repository.SetDraftOrderItem(model.DraftOrderItemEntity{DraftOrderID: draft.ID, ProductID: parsed, CreatedBy: "USER"})
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *draftOrderService) HoldDraftOrder(id string, request model.HoldDraftOrderRequest) (model.DraftOrder, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	if label := strings.TrimSpace(request.Label); label != "" {
+		draft.Label = label
+	}
+	if tableNumber := strings.TrimSpace(request.TableNumber); tableNumber != "" {
+		if draft.TableID != nil && tableNumber != draft.TableNumber {
+			return model.DraftOrder{}, fmt.Errorf("%w: the draft is seated at table %s, move it to another table instead", ErrInvalidDraftOrder, draft.TableNumber)
+		}
+		draft.TableNumber = tableNumber
+	}
+	if request.Notes != "" {
+		draft.Notes = request.Notes
+	}
+	if draft.Label == "" && draft.TableNumber == "" {
+		return model.DraftOrder{}, fmt.Errorf("%w: a held draft needs a label or a table number", ErrInvalidDraftOrder)
+	}
+	return s.updateStatus(draft, model.DraftOrderHeld)
+}
+
+func (s *draftOrderService) ResumeDraftOrder(id string) (model.DraftOrder, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return s.updateStatus(draft, model.DraftOrderOpen)
+}
+
+func (s *draftOrderService) CancelDraftOrder(id string) (model.DraftOrder, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return s.updateStatus(draft, model.DraftOrderCancelled)
+}
+
+func (s *draftOrderService) AssignDraftOrderTable(id string, request model.AssignDraftOrderTableRequest) (model.DraftOrder, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	tableID, err := uuid.Parse(utils.DecodeBase62(request.TableID))
+	if err != nil {
+		return model.DraftOrder{}, fmt.Errorf("%w: dining table not found", ErrInvalidDraftOrder)
+	}
+	table, err := s.findFreeTable(draft, tableID)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	if err := seatAt(&draft, table); err != nil {
+		return model.DraftOrder{}, err
+	}
+
+	draft.UpdatedBy = "USER"
+	entity, err := s.repository.UpdateDraftOrder(draft)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *draftOrderService) ConvertDraftOrder(id string, request model.ConvertDraftOrderRequest) (model.Transaction, error) {
+	draft, err := s.findActiveDraftOrder(id)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if len(draft.Items) == 0 {
+		return model.Transaction{}, fmt.Errorf("%w: the draft has no items", ErrInvalidDraftOrder)
+	}
+	return s.txService.CreateTransaction(request.ToTransactionRequest(draft))
+}
+
+func (s *draftOrderService) updateStatus(draft model.DraftOrderEntity, status string) (model.DraftOrder, error) {
+	draft.Status = status
+	draft.UpdatedBy = "USER"
+	entity, err := s.repository.UpdateDraftOrder(draft)
+	if err != nil {
+		return model.DraftOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+// findActiveDraftOrder loads the draft and fails with ErrDraftOrderStatus once it is converted or cancelled
+func (s *draftOrderService) findActiveDraftOrder(id string) (model.DraftOrderEntity, error) {
+	draft, err := s.repository.FindDraftOrderByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.DraftOrderEntity{}, err
+	}
+	if !draft.IsActive() {
+		return model.DraftOrderEntity{}, fmt.Errorf("%w: draft order is %s", ErrDraftOrderStatus, draft.Status)
+	}
+	return draft, nil
+}
+
+// findFreeTable loads a table in use that no other active draft is seated at
+func (s *draftOrderService) findFreeTable(draft model.DraftOrderEntity, tableID uuid.UUID) (model.DiningTableEntity, error) {
+	table, err := s.tableRepository.FindDiningTableByID(tableID.String())
+	if err != nil {
+		return model.DiningTableEntity{}, fmt.Errorf("%w: dining table not found", ErrInvalidDraftOrder)
+	}
+	if !table.IsActive {
+		return model.DiningTableEntity{}, fmt.Errorf("%w: table %s is not in use", ErrInvalidDraftOrder, table.Name)
+	}
+	if table.DraftOrderID != nil && *table.DraftOrderID != draft.ID {
+		return model.DiningTableEntity{}, fmt.Errorf("%w: table %s already has an open order", ErrInvalidDraftOrder, table.Name)
+	}
+	return table, nil
+}
+
+// seatAt puts the draft at the table, the table's name becomes the draft's table number
+func seatAt(draft *model.DraftOrderEntity, table model.DiningTableEntity) error {
+	sameOutlet := (draft.OutletID == nil && table.OutletID == nil) ||
+		(draft.OutletID != nil && table.OutletID != nil && *draft.OutletID == *table.OutletID)
+	if !sameOutlet {
+		return fmt.Errorf("%w: table %s is at another outlet", ErrInvalidDraftOrder, table.Name)
+	}
+	draft.TableID = &table.ID
+	draft.TableNumber = table.Name
+	return nil
+}
+
+// findProduct refuses lines of unknown products or without a quantity
+func (s *draftOrderService) findProduct(item model.DraftOrderItemEntity) (model.ProductEntity, error) {
+	if item.Quantity <= 0 {
+		return model.ProductEntity{}, fmt.Errorf("%w: quantity must be greater than zero", ErrInvalidDraftOrder)
+	}
+	product, err := s.productRepository.FindProductByID(item.ProductID.String())
+	if err != nil || product.DeletedAt != nil {
+		return model.ProductEntity{}, fmt.Errorf("%w: product not found", ErrInvalidDraftOrder)
+	}
+	// draft quantities count whole pieces, a weighed product is rung up at the till where it is weighed
+	if product.QuantityScale > 0 {
+		return model.ProductEntity{}, fmt.Errorf("%w: %s is sold by the %s and cannot go on a draft", ErrInvalidDraftOrder, product.Name, product.Unit)
+	}
+	return product, nil
+}
+
+// reserve checks the stock can spare the line's whole quantity once the other reserving drafts at the outlet
+// are set aside. Reservations are checked rather than locked, the stock ledger still refuses to oversell.
+func (s *draftOrderService) reserve(draft model.DraftOrderEntity, product model.ProductEntity, quantity int) error {
+	if !draft.ReservesStock {
+		return nil
+	}
+	if draft.OutletID != nil {
+		if _, err := atOutlet(s.outletRepository, &product, *draft.OutletID); err != nil {
+			return err
+		}
+	}
+	reserved, err := s.repository.FindReservedStocks(draft.OutletID, draft.ID.String())
+	if err != nil {
+		return err
+	}
+	if available := product.UnreservedStocks(reserved); available < quantity {
+		return fmt.Errorf("%w: only %d of %s can be reserved", ErrInvalidDraftOrder, max(available, 0), product.Name)
+	}
+	return nil
+}
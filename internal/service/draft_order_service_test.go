@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftOrderServiceCreateDraftOrder(t *testing.T) {
+	mockRepo := new(mocks.MockDraftOrderRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewDraftOrderService(mockRepo, mockProductRepo, new(mocks.MockOutletRepository), new(mocks.MockCustomerRepository), new(mocks.MockDiningTableRepository), new(mocks.MockTransactionService), true)
+
+	coffee, unknown, rice := uuid.New(), uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", coffee.String()).Return(model.ProductEntity{ID: coffee, Name: "Kopi Susu", Stocks: 10}, nil)
+	mockProductRepo.On("FindProductByID", unknown.String()).Return(model.ProductEntity{}, errors.New("product not found"))
+	mockProductRepo.On("FindProductByID", rice.String()).Return(model.ProductEntity{ID: rice, Name: "Beras", Stocks: 50000, Unit: "kg", QuantityScale: 3}, nil)
+	mockRepo.On("FindReservedStocks", (*uuid.UUID)(nil), mock.Anything).Return(map[uuid.UUID]int{coffee: 6}, nil)
+	mockRepo.On("InsertDraftOrder", mock.MatchedBy(func(d model.DraftOrderEntity) bool {
+		return d.ReservesStock && d.TableNumber == "7" && d.ItemQuantity(coffee) == 4
+	})).Return(model.DraftOrderEntity{ID: uuid.New(), Status: model.DraftOrderOpen, TableNumber: "7", ReservesStock: true}, nil)
+
+	item := func(id uuid.UUID, quantity int) []model.AddDraftOrderItemRequest {
+		return []model.AddDraftOrderItemRequest{{ProductID: utils.EncodeBase62(id.String()), Quantity: quantity}}
+	}
+	for _, request := range []model.CreateDraftOrderRequest{
+		{Items: item(coffee, 0)},
+		{Items: item(unknown, 1)},
+		{Items: item(coffee, 5)},
+		{Items: item(rice, 1)}, // a weighed product has no whole pieces to put on a draft
+	} {
+		_, err := service.CreateDraftOrder(request)
+		assert.ErrorIs(t, err, ErrInvalidDraftOrder)
+	}
+
+	draft, err := service.CreateDraftOrder(model.CreateDraftOrderRequest{TableNumber: "7", Items: item(coffee, 4)})
+	require.NoError(t, err)
+	assert.True(t, draft.ReservesStock)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDraftOrderServiceAddAndRemoveItems(t *testing.T) {
+	mockRepo := new(mocks.MockDraftOrderRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewDraftOrderService(mockRepo, mockProductRepo, new(mocks.MockOutletRepository), new(mocks.MockCustomerRepository), new(mocks.MockDiningTableRepository), new(mocks.MockTransactionService), false)
+
+	draftID, convertedID, coffee, cake := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("FindDraftOrderByID", draftID.String()).Return(model.DraftOrderEntity{
+		ID:     draftID,
+		Status: model.DraftOrderHeld,
+		Items:  []model.DraftOrderItemEntity{{DraftOrderID: draftID, ProductID: coffee, Quantity: 2}},
+	}, nil)
+	mockRepo.On("FindDraftOrderByID", convertedID.String()).Return(model.DraftOrderEntity{ID: convertedID, Status: model.DraftOrderConverted}, nil)
+	mockProductRepo.On("FindProductByID", coffee.String()).Return(model.ProductEntity{ID: coffee, Name: "Kopi Susu"}, nil)
+	mockRepo.On("SetDraftOrderItem", model.DraftOrderItemEntity{DraftOrderID: draftID, ProductID: coffee, Quantity: 3, CreatedBy: "USER"}).
+		Return(model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderHeld}, nil)
+	mockRepo.On("SetDraftOrderItem", model.DraftOrderItemEntity{DraftOrderID: draftID, ProductID: coffee, CreatedBy: "USER"}).
+		Return(model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderHeld}, nil)
+
+	id, converted := utils.EncodeBase62(draftID.String()), utils.EncodeBase62(convertedID.String())
+	_, err := service.AddDraftOrderItem(id, model.AddDraftOrderItemRequest{ProductID: utils.EncodeBase62(coffee.String()), Quantity: 1})
+	require.NoError(t, err, "a tab keeps taking items while it is held, stock is not reserved")
+
+	_, err = service.RemoveDraftOrderItem(id, utils.EncodeBase62(coffee.String()))
+	require.NoError(t, err)
+	_, err = service.RemoveDraftOrderItem(id, utils.EncodeBase62(cake.String()))
+	assert.ErrorIs(t, err, ErrInvalidDraftOrder)
+
+	_, err = service.AddDraftOrderItem(converted, model.AddDraftOrderItemRequest{ProductID: utils.EncodeBase62(coffee.String()), Quantity: 1})
+	assert.ErrorIs(t, err, ErrDraftOrderStatus)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDraftOrderServiceHoldDraftOrder(t *testing.T) {
+	mockRepo := new(mocks.MockDraftOrderRepository)
+	service := NewDraftOrderService(mockRepo, new(mocks.MockProductRepository), new(mocks.MockOutletRepository), new(mocks.MockCustomerRepository), new(mocks.MockDiningTableRepository), new(mocks.MockTransactionService), false)
+
+	draftID := uuid.New()
+	mockRepo.On("FindDraftOrderByID", draftID.String()).Return(model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderOpen}, nil)
+	mockRepo.On("UpdateDraftOrder", mock.MatchedBy(func(d model.DraftOrderEntity) bool {
+		return d.Status == model.DraftOrderHeld && d.Label == "Budi"
+	})).Return(model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderHeld, Label: "Budi"}, nil)
+
+	id := utils.EncodeBase62(draftID.String())
+	_, err := service.HoldDraftOrder(id, model.HoldDraftOrderRequest{Label: "  "})
+	assert.ErrorIs(t, err, ErrInvalidDraftOrder, "a parked order must be findable")
+
+	draft, err := service.HoldDraftOrder(id, model.HoldDraftOrderRequest{Label: " Budi "})
+	require.NoError(t, err)
+	assert.Equal(t, model.DraftOrderHeld, draft.Status)
+}
+
+func TestDraftOrderServiceConvertDraftOrder(t *testing.T) {
+	mockRepo := new(mocks.MockDraftOrderRepository)
+	mockTxService := new(mocks.MockTransactionService)
+	service := NewDraftOrderService(mockRepo, new(mocks.MockProductRepository), new(mocks.MockOutletRepository), new(mocks.MockCustomerRepository), new(mocks.MockDiningTableRepository), mockTxService, true)
+
+	draftID, emptyID, cancelledID, coffee := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	draft := model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderHeld, Items: []model.DraftOrderItemEntity{{ProductID: coffee, Quantity: 2}}}
+	mockRepo.On("FindDraftOrderByID", draftID.String()).Return(draft, nil)
+	mockRepo.On("FindDraftOrderByID", emptyID.String()).Return(model.DraftOrderEntity{ID: emptyID, Status: model.DraftOrderOpen}, nil)
+	mockRepo.On("FindDraftOrderByID", cancelledID.String()).Return(model.DraftOrderEntity{ID: cancelledID, Status: model.DraftOrderCancelled}, nil)
+
+	request := model.ConvertDraftOrderRequest{ShiftID: "s1"}
+	mockTxService.On("CreateTransaction", request.ToTransactionRequest(draft)).Return(model.Transaction{ID: "t1", TotalItems: 2}, nil)
+
+	_, err := service.ConvertDraftOrder(utils.EncodeBase62(emptyID.String()), request)
+	assert.ErrorIs(t, err, ErrInvalidDraftOrder)
+	_, err = service.ConvertDraftOrder(utils.EncodeBase62(cancelledID.String()), request)
+	assert.ErrorIs(t, err, ErrDraftOrderStatus)
+
+	tx, err := service.ConvertDraftOrder(utils.EncodeBase62(draftID.String()), request)
+	require.NoError(t, err)
+	assert.Equal(t, "t1", tx.ID)
+	mockTxService.AssertExpectations(t)
+}
+
+func TestDraftOrderServiceAssignDraftOrderTable(t *testing.T) {
+	mockRepo := new(mocks.MockDraftOrderRepository)
+	mockTableRepo := new(mocks.MockDiningTableRepository)
+	service := NewDraftOrderService(mockRepo, new(mocks.MockProductRepository), new(mocks.MockOutletRepository), new(mocks.MockCustomerRepository), mockTableRepo, new(mocks.MockTransactionService), false)
+
+	draftID, otherID, outletID := uuid.New(), uuid.New(), uuid.New()
+	free, taken, elsewhere := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("FindDraftOrderByID", draftID.String()).Return(model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderOpen, OutletID: &outletID, TableNumber: "7"}, nil)
+	mockTableRepo.On("FindDiningTableByID", free.String()).Return(model.DiningTableEntity{ID: free, OutletID: &outletID, Name: "T3", IsActive: true}, nil)
+	mockTableRepo.On("FindDiningTableByID", taken.String()).Return(model.DiningTableEntity{ID: taken, OutletID: &outletID, Name: "T4", IsActive: true, DraftOrderID: &otherID}, nil)
+	mockTableRepo.On("FindDiningTableByID", elsewhere.String()).Return(model.DiningTableEntity{ID: elsewhere, Name: "T5", IsActive: true}, nil)
+	mockRepo.On("UpdateDraftOrder", mock.MatchedBy(func(d model.DraftOrderEntity) bool {
+		return *d.TableID == free && d.TableNumber == "T3" && d.Status == model.DraftOrderOpen
+	})).Return(model.DraftOrderEntity{ID: draftID, Status: model.DraftOrderOpen, TableID: &free, TableNumber: "T3"}, nil)
+
+	id := utils.EncodeBase62(draftID.String())
+	for _, tableID := range []uuid.UUID{taken, elsewhere} {
+		_, err := service.AssignDraftOrderTable(id, model.AssignDraftOrderTableRequest{TableID: utils.EncodeBase62(tableID.String())})
+		assert.ErrorIs(t, err, ErrInvalidDraftOrder)
+	}
+
+	draft, err := service.AssignDraftOrderTable(id, model.AssignDraftOrderTableRequest{TableID: utils.EncodeBase62(free.String())})
+	require.NoError(t, err)
+	assert.Equal(t, "T3", draft.TableNumber)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package service
+
+import "errors"
+
+// Sentinel errors the handlers use to pick a client error status instead of 500.
+// Wrap them with fmt.Errorf("%w: ...") to keep the detail in the message.
+var (
+	ErrInvalidProduct   = errors.New("invalid product")
+	ErrInvalidCategory  = errors.New("invalid category")
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryHasSubcategories means the category cannot be deleted while categories are still below it
+	ErrCategoryHasSubcategories = errors.New("category has subcategories")
+	ErrInvalidStockMovement     = errors.New("invalid stock movement")
+	ErrInvalidStockCount        = errors.New("invalid stock count")
+	// ErrStockCountStatus means the action is not allowed in the count's current status
+	ErrStockCountStatus     = errors.New("stock count status conflict")
+	ErrInvalidSupplier      = errors.New("invalid supplier")
+	ErrInvalidPurchaseOrder = errors.New("invalid purchase order")
+	// ErrPurchaseOrderStatus means the action is not allowed in the order's current status
+	ErrPurchaseOrderStatus  = errors.New("purchase order status conflict")
+	ErrInvalidLot           = errors.New("invalid lot")
+	ErrInvalidOutlet        = errors.New("invalid outlet")
+	ErrInvalidStockTransfer = errors.New("invalid stock transfer")
+	// ErrStockTransferStatus means the action is not allowed in the transfer's current status
+	ErrStockTransferStatus = errors.New("stock transfer status conflict")
+	ErrInvalidCustomer     = errors.New("invalid customer")
+	ErrInvalidLoyalty      = errors.New("invalid loyalty request")
+	ErrInvalidGiftCard     = errors.New("invalid gift card")
+	ErrGiftCardNotFound    = errors.New("gift card not found")
+	ErrInvalidShift        = errors.New("invalid shift")
+	// ErrShiftStatus means the action is not allowed in the shift's current status
+	ErrShiftStatus       = errors.New("shift status conflict")
+	ErrInvalidDraftOrder = errors.New("invalid draft order")
+	// ErrDraftOrderStatus means the action is not allowed in the draft order's current status
+	ErrDraftOrderStatus   = errors.New("draft order status conflict")
+	ErrInvalidDiningTable = errors.New("invalid dining table")
+	ErrInvalidKitchen     = errors.New("invalid kitchen request")
+	// ErrKitchenTicketStatus means the ticket cannot move to the status from where it is
+	ErrKitchenTicketStatus = errors.New("kitchen ticket status conflict")
+	ErrInvalidSync         = errors.New("invalid sync request")
+	// ErrStockConflictStatus means the stock conflict is already resolved
+	ErrStockConflictStatus = errors.New("stock conflict status conflict")
+	ErrInvalidPriceList    = errors.New("invalid price list")
+	ErrInvalidQuantity     = errors.New("invalid quantity")
+	// ErrInvalidProductImage means the upload is not an image we can take, by type, size or content
+	ErrInvalidProductImage  = errors.New("invalid product image")
+	ErrProductImageNotFound = errors.New("product image not found")
+	// ErrProductPriceStarted means the scheduled price is already in effect and can no longer be cancelled
+	ErrProductPriceStarted = errors.New("product price already started")
+	// ErrTenantNotFound means the request names no known shop, by token or by subdomain
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantInactive = errors.New("tenant is not active")
+)
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// GiftCardService issues gift cards and store credit and tops them up. Cards pay for sales through TransactionService.
+type GiftCardService interface {
+	FetchGiftCards() ([]model.GiftCard, error)
+	// FetchGiftCardBalance is the balance check, code is matched however the customer typed it
+	FetchGiftCardBalance(code string) (model.GiftCardBalance, error)
+	IssueGiftCard(request model.CreateGiftCardRequest) (model.GiftCard, error)
+	TopUpGiftCard(code string, request model.TopUpGiftCardRequest) (model.GiftCardEntry, error)
+}
+
+type giftCardService struct {
+	repository         repository.GiftCardRepository
+	customerRepository repository.CustomerRepository
+}
+
+func NewGiftCardService(repository repository.GiftCardRepository, customerRepository repository.CustomerRepository) GiftCardService {
+	return &giftCardService{
+		repository:         repository,
+		customerRepository: customerRepository,
+	}
+}
+
+// giftCardCodeAlphabet leaves out 0, O, 1 and I, which are easily misread on a printed card
+const giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const giftCardCodeLength = 16
+
+func (s *giftCardService) FetchGiftCards() ([]model.GiftCard, error) {
+	cards, err := s.repository.FindGiftCards()
+	if err != nil {
+		return nil, err
+	}
+	var result []model.GiftCard
+	for _, c := range cards {
+		result = append(result, *c.ToModel())
+	}
+	return result, nil
+}
+
+func (s *giftCardService) FetchGiftCardBalance(code string) (model.GiftCardBalance, error) {
+	card, err := s.findGiftCard(code)
+	if err != nil {
+		return model.GiftCardBalance{}, err
+	}
+	entries, err := s.repository.FindGiftCardEntries(card.ID.String())
+	if err != nil {
+		return model.GiftCardBalance{}, err
+	}
+	return *model.NewGiftCardBalance(card, entries), nil
+}
+
+func (s *giftCardService) IssueGiftCard(request model.CreateGiftCardRequest) (model.GiftCard, error) {
+	card := *request.ToEntity()
+	if card.InitialAmount <= 0 {
+		return model.GiftCard{}, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidGiftCard)
+	}
+	if card.ExpiresAt != nil && !card.ExpiresAt.After(time.Now()) {
+		return model.GiftCard{}, fmt.Errorf("%w: expiry must be in the future", ErrInvalidGiftCard)
+	}
+	if card.CustomerID != nil {
+		customer, err := s.customerRepository.FindCustomerByID(card.CustomerID.String())
+		if err != nil || customer.DeletedAt != nil {
+			return model.GiftCard{}, fmt.Errorf("%w: customer not found", ErrInvalidGiftCard)
+		}
+	}
+
+	if card.Code == "" {
+		code, err := s.newGiftCardCode()
+		if err != nil {
+			return model.GiftCard{}, err
+		}
+		card.Code = code
+	} else {
+		holder, err := s.repository.FindGiftCardByCode(card.Code)
+		if err != nil {
+			return model.GiftCard{}, err
+		}
+		if holder.ID != uuid.Nil {
+			return model.GiftCard{}, fmt.Errorf("%w: code %s is already used", ErrInvalidGiftCard, card.Code)
+		}
+	}
+
+	entity, err := s.repository.InsertGiftCard(card)
+	if err != nil {
+		return model.GiftCard{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *giftCardService) TopUpGiftCard(code string, request model.TopUpGiftCardRequest) (model.GiftCardEntry, error) {
+	if request.Amount <= 0 {
+		return model.GiftCardEntry{}, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidGiftCard)
+	}
+	card, err := s.findGiftCard(code)
+	if err != nil {
+		return model.GiftCardEntry{}, err
+	}
+	if !card.IsRedeemable(time.Now()) {
+		return model.GiftCardEntry{}, fmt.Errorf("%w: gift card %s is inactive or expired", ErrInvalidGiftCard, card.Code)
+	}
+
+	id, _ := uuid.NewV7()
+	entry, err := s.repository.InsertGiftCardEntry(model.GiftCardEntryEntity{
+		ID:         id,
+		GiftCardID: card.ID,
+		Type:       model.GiftCardEntryTopUp,
+		Amount:     request.Amount,
+		Notes:      request.Notes,
+		CreatedBy:  "USER",
+	})
+	if err != nil {
+		return model.GiftCardEntry{}, err
+	}
+	return *entry.ToModel(), nil
+}
+
+func (s *giftCardService) findGiftCard(code string) (model.GiftCardEntity, error) {
+	card, err := s.repository.FindGiftCardByCode(model.NormalizeGiftCardCode(code))
+	if err != nil {
+		return model.GiftCardEntity{}, err
+	}
+	if card.ID == uuid.Nil {
+		return model.GiftCardEntity{}, ErrGiftCardNotFound
+	}
+	return card, nil
+}
+
+// newGiftCardCode draws random codes until one is not taken, a clash is unlikely at 32^16 codes
+func (s *giftCardService) newGiftCardCode() (string, error) {
+	for {
+		b := make([]byte, giftCardCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		for i := range b {
+			b[i] = giftCardCodeAlphabet[int(b[i])%len(giftCardCodeAlphabet)]
+		}
+		holder, err := s.repository.FindGiftCardByCode(string(b))
+		if err != nil {
+			return "", err
+		}
+		if holder.ID == uuid.Nil {
+			return string(b), nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiftCardServiceIssueGiftCard(t *testing.T) {
+	mockRepo := new(mocks.MockGiftCardRepository)
+	mockCustomerRepo := new(mocks.MockCustomerRepository)
+	service := NewGiftCardService(mockRepo, mockCustomerRepo)
+
+	unknownID := uuid.New()
+	mockCustomerRepo.On("FindCustomerByID", unknownID.String()).Return(model.CustomerEntity{}, errors.New("customer not found"))
+	mockRepo.On("FindGiftCardByCode", "TAKEN").Return(model.GiftCardEntity{ID: uuid.New(), Code: "TAKEN"}, nil)
+	mockRepo.On("FindGiftCardByCode", mock.Anything).Return(model.GiftCardEntity{}, nil)
+	mockRepo.On("InsertGiftCard", mock.MatchedBy(func(card model.GiftCardEntity) bool { return card.Code == "WELCOME2026" })).
+		Return(model.GiftCardEntity{ID: uuid.New(), Code: "WELCOME2026", InitialAmount: 50000, Balance: 50000, IsActive: true}, nil)
+	var generatedCode string
+	mockRepo.On("InsertGiftCard", mock.MatchedBy(func(card model.GiftCardEntity) bool { return card.Code != "WELCOME2026" })).
+		Run(func(args mock.Arguments) { generatedCode = args.Get(0).(model.GiftCardEntity).Code }).
+		Return(model.GiftCardEntity{ID: uuid.New(), InitialAmount: 50000, Balance: 50000, IsActive: true}, nil)
+
+	past := time.Now().Add(-time.Hour)
+	for _, request := range []model.CreateGiftCardRequest{
+		{Amount: 0},
+		{Amount: 50000, ExpiresAt: &past},
+		{Amount: 50000, CustomerID: utils.EncodeBase62(unknownID.String())},
+		{Amount: 50000, Code: "taken"},
+	} {
+		_, err := service.IssueGiftCard(request)
+		assert.ErrorIs(t, err, ErrInvalidGiftCard)
+	}
+	mockRepo.AssertNotCalled(t, "InsertGiftCard", mock.Anything)
+
+	card, err := service.IssueGiftCard(model.CreateGiftCardRequest{Amount: 50000, Code: "welcome-2026"})
+	require.NoError(t, err)
+	assert.Equal(t, "WELCOME2026", card.Code)
+	assert.Equal(t, int64(50000), card.Balance.Amount)
+
+	_, err = service.IssueGiftCard(model.CreateGiftCardRequest{Amount: 50000})
+	require.NoError(t, err)
+	assert.Len(t, generatedCode, giftCardCodeLength)
+	assert.Equal(t, model.NormalizeGiftCardCode(generatedCode), generatedCode)
+}
+
+func TestGiftCardServiceFetchGiftCardBalance(t *testing.T) {
+	mockRepo := new(mocks.MockGiftCardRepository)
+	service := NewGiftCardService(mockRepo, new(mocks.MockCustomerRepository))
+
+	card := model.GiftCardEntity{ID: uuid.New(), Code: "ABCD1234", InitialAmount: 50000, Balance: 20000, IsActive: true}
+	mockRepo.On("FindGiftCardByCode", "ABCD1234").Return(card, nil)
+	mockRepo.On("FindGiftCardByCode", "NOPE").Return(model.GiftCardEntity{}, nil)
+	mockRepo.On("FindGiftCardEntries", card.ID.String()).Return([]model.GiftCardEntryEntity{
+		{ID: uuid.New(), GiftCardID: card.ID, Type: model.GiftCardEntryIssue, Amount: 50000, BalanceAfter: 50000},
+		{ID: uuid.New(), GiftCardID: card.ID, Type: model.GiftCardEntryRedeem, Amount: -30000, BalanceAfter: 20000},
+	}, nil)
+
+	balance, err := service.FetchGiftCardBalance("abcd-1234")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20000), balance.Balance.Amount)
+	require.Len(t, balance.Entries, 2)
+	assert.Equal(t, model.GiftCardEntryRedeem, balance.Entries[0].Type)
+
+	_, err = service.FetchGiftCardBalance("nope")
+	assert.ErrorIs(t, err, ErrGiftCardNotFound)
+}
+
+func TestGiftCardServiceTopUpGiftCard(t *testing.T) {
+	mockRepo := new(mocks.MockGiftCardRepository)
+	service := NewGiftCardService(mockRepo, new(mocks.MockCustomerRepository))
+
+	lapsed := time.Now().Add(-time.Hour)
+	card := model.GiftCardEntity{ID: uuid.New(), Code: "GIFT", Balance: 20000, IsActive: true}
+	mockRepo.On("FindGiftCardByCode", "GIFT").Return(card, nil)
+	mockRepo.On("FindGiftCardByCode", "OLD").Return(model.GiftCardEntity{ID: uuid.New(), Code: "OLD", IsActive: true, ExpiresAt: &lapsed}, nil)
+	mockRepo.On("InsertGiftCardEntry", mock.MatchedBy(func(e model.GiftCardEntryEntity) bool {
+		return e.GiftCardID == card.ID && e.Type == model.GiftCardEntryTopUp && e.Amount == 10000
+	})).Return(model.GiftCardEntryEntity{ID: uuid.New(), GiftCardID: card.ID, Type: model.GiftCardEntryTopUp, Amount: 10000, BalanceAfter: 30000}, nil)
+
+	_, err := service.TopUpGiftCard("GIFT", model.TopUpGiftCardRequest{Amount: -5})
+	assert.ErrorIs(t, err, ErrInvalidGiftCard)
+	_, err = service.TopUpGiftCard("OLD", model.TopUpGiftCardRequest{Amount: 10000})
+	assert.ErrorIs(t, err, ErrInvalidGiftCard)
+
+	entry, err := service.TopUpGiftCard("gift", model.TopUpGiftCardRequest{Amount: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(30000), entry.BalanceAfter.Amount)
+}
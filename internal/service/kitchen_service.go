@@ -0,0 +1,203 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/event"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// KitchenService routes the items of draft orders to the kitchen stations preparing them.
+// Every ticket change is published for the kitchen display screens, with the ticket's station as the subject.
+type KitchenService interface {
+	FetchKitchenStations() ([]model.KitchenStation, error)
+	CreateKitchenStation(request model.CreateKitchenStationRequest) (model.KitchenStation, error)
+	UpdateKitchenStationByID(id string, request model.UpdateKitchenStationRequest) (model.KitchenStation, error)
+	// FetchKitchenTickets takes an optional station and status, the tickets not served yet are listed when status is empty
+	FetchKitchenTickets(stationID, status string) ([]model.KitchenTicket, error)
+	FetchKitchenTicketByID(id string) (model.KitchenTicket, error)
+	// FireDraftOrder sends the draft's items not sent yet to their stations, one ticket per station.
+	// Items of categories no station prepares are marked sent without a ticket.
+	FireDraftOrder(draftOrderID string) ([]model.KitchenTicket, error)
+	// UpdateKitchenTicketStatus moves the ticket forward, it may skip a step but never goes back
+	UpdateKitchenTicketStatus(id string, request model.UpdateKitchenTicketStatusRequest) (model.KitchenTicket, error)
+}
+
+type kitchenService struct {
+	repository           repository.KitchenRepository
+	draftOrderRepository repository.DraftOrderRepository
+	productRepository    repository.ProductRepository
+	categoryRepository   repository.CategoryRepository
+	publisher            event.Publisher
+}
+
+func NewKitchenService(repository repository.KitchenRepository, draftOrderRepository repository.DraftOrderRepository, productRepository repository.ProductRepository, categoryRepository repository.CategoryRepository, publisher event.Publisher) KitchenService {
+	return &kitchenService{
+		repository:           repository,
+		draftOrderRepository: draftOrderRepository,
+		productRepository:    productRepository,
+		categoryRepository:   categoryRepository,
+		publisher:            publisher,
+	}
+}
+
+func (s *kitchenService) FetchKitchenStations() ([]model.KitchenStation, error) {
+	entities, err := s.repository.FindKitchenStations()
+	if err != nil {
+		return nil, err
+	}
+
+	stations := []model.KitchenStation{}
+	for _, entity := range entities {
+		stations = append(stations, *entity.ToModel())
+	}
+	return stations, nil
+}
+
+func (s *kitchenService) CreateKitchenStation(request model.CreateKitchenStationRequest) (model.KitchenStation, error) {
+	station := *request.ToEntity()
+	if err := s.validateStation(station); err != nil {
+		return model.KitchenStation{}, err
+	}
+
+	entity, err := s.repository.InsertKitchenStation(station)
+	if err != nil {
+		return model.KitchenStation{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *kitchenService) UpdateKitchenStationByID(id string, request model.UpdateKitchenStationRequest) (model.KitchenStation, error) {
+	station := *request.ToEntity()
+	if err := s.validateStation(station); err != nil {
+		return model.KitchenStation{}, err
+	}
+
+	entity, err := s.repository.UpdateKitchenStationByID(utils.DecodeBase62(id), station)
+	if err != nil {
+		return model.KitchenStation{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *kitchenService) validateStation(station model.KitchenStationEntity) error {
+	if station.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidKitchen)
+	}
+	for _, categoryID := range station.CategoryIDs {
+		if _, err := s.categoryRepository.FindCategoryByID(categoryID.String()); err != nil {
+			return fmt.Errorf("%w: category not found", ErrInvalidKitchen)
+		}
+	}
+	return nil
+}
+
+func (s *kitchenService) FetchKitchenTickets(stationID, status string) ([]model.KitchenTicket, error) {
+	if status != "" && !model.IsKitchenTicketStatus(status) {
+		return nil, fmt.Errorf("%w: status must be %s, %s, %s or %s", ErrInvalidKitchen,
+			model.KitchenTicketQueued, model.KitchenTicketPreparing, model.KitchenTicketReady, model.KitchenTicketServed)
+	}
+	entities, err := s.repository.FindKitchenTickets(utils.DecodeBase62(stationID), status)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := []model.KitchenTicket{}
+	for _, entity := range entities {
+		tickets = append(tickets, *entity.ToModel())
+	}
+	return tickets, nil
+}
+
+func (s *kitchenService) FetchKitchenTicketByID(id string) (model.KitchenTicket, error) {
+	entity, err := s.repository.FindKitchenTicketByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.KitchenTicket{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *kitchenService) FireDraftOrder(draftOrderID string) ([]model.KitchenTicket, error) {
+	draft, err := s.draftOrderRepository.FindDraftOrderByID(utils.DecodeBase62(draftOrderID))
+	if err != nil {
+		return nil, err
+	}
+	if !draft.IsActive() {
+		return nil, fmt.Errorf("%w: draft order is %s", ErrDraftOrderStatus, draft.Status)
+	}
+	unfired := false
+	for _, item := range draft.Items {
+		unfired = unfired || item.Quantity > item.FiredQuantity
+	}
+	if !unfired {
+		return nil, fmt.Errorf("%w: every item of the draft order was already sent", ErrInvalidKitchen)
+	}
+
+	stationOf, err := s.stationsByProduct(draft)
+	if err != nil {
+		return nil, err
+	}
+	draft.UpdatedBy = "USER"
+	entities, err := s.repository.FireKitchenTickets(draft, model.NewKitchenTickets(draft, stationOf))
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := []model.KitchenTicket{}
+	for _, entity := range entities {
+		ticket := entity.ToModel()
+		s.publisher.Publish(event.NewWithSubject(event.KitchenTicketCreated, ticket.StationID, ticket))
+		tickets = append(tickets, *ticket)
+	}
+	return tickets, nil
+}
+
+// stationsByProduct maps the draft's products to the stations preparing their categories
+func (s *kitchenService) stationsByProduct(draft model.DraftOrderEntity) (map[uuid.UUID]uuid.UUID, error) {
+	stationOfCategory, err := s.repository.FindStationsByCategory()
+	if err != nil {
+		return nil, err
+	}
+
+	stationOf := map[uuid.UUID]uuid.UUID{}
+	for _, item := range draft.Items {
+		product, err := s.productRepository.FindProductByID(item.ProductID.String())
+		if err != nil {
+			return nil, err
+		}
+		if product.CategoryID == nil {
+			continue
+		}
+		if stationID, ok := stationOfCategory[*product.CategoryID]; ok {
+			stationOf[item.ProductID] = stationID
+		}
+	}
+	return stationOf, nil
+}
+
+func (s *kitchenService) UpdateKitchenTicketStatus(id string, request model.UpdateKitchenTicketStatusRequest) (model.KitchenTicket, error) {
+	if !model.IsKitchenTicketStatus(request.Status) {
+		return model.KitchenTicket{}, fmt.Errorf("%w: status must be %s, %s or %s", ErrInvalidKitchen,
+			model.KitchenTicketPreparing, model.KitchenTicketReady, model.KitchenTicketServed)
+	}
+	ticket, err := s.repository.FindKitchenTicketByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.KitchenTicket{}, err
+	}
+	if !ticket.CanMoveTo(request.Status) {
+		return model.KitchenTicket{}, fmt.Errorf("%w: ticket is already %s", ErrKitchenTicketStatus, ticket.Status)
+	}
+
+	ticket.Status = request.Status
+	ticket.UpdatedBy = "USER"
+	entity, err := s.repository.UpdateKitchenTicketStatus(ticket)
+	if err != nil {
+		return model.KitchenTicket{}, err
+	}
+	updated := entity.ToModel()
+	s.publisher.Publish(event.NewWithSubject(event.KitchenTicketUpdated, updated.StationID, updated))
+	return *updated, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"codewithumam-kasir-api/internal/event"
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKitchenServiceFireDraftOrder(t *testing.T) {
+	mockRepo := new(mocks.MockKitchenRepository)
+	mockDraftRepo := new(mocks.MockDraftOrderRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	mockPublisher := new(mocks.MockPublisher)
+	service := NewKitchenService(mockRepo, mockDraftRepo, mockProductRepo, new(mocks.MockCategoryRepository), mockPublisher)
+
+	draftID, firedID, cancelledID := uuid.New(), uuid.New(), uuid.New()
+	food, drinks, bar := uuid.New(), uuid.New(), uuid.New()
+	nasiGoreng, kopi := uuid.New(), uuid.New()
+	draft := model.DraftOrderEntity{
+		ID:          draftID,
+		Status:      model.DraftOrderOpen,
+		TableNumber: "7",
+		Items: []model.DraftOrderItemEntity{
+			{ProductID: nasiGoreng, ProductName: "Nasi Goreng", Quantity: 2},
+			{ProductID: kopi, ProductName: "Kopi Susu", Quantity: 1},
+		},
+	}
+	mockDraftRepo.On("FindDraftOrderByID", draftID.String()).Return(draft, nil)
+	mockDraftRepo.On("FindDraftOrderByID", firedID.String()).Return(model.DraftOrderEntity{
+		ID:     firedID,
+		Status: model.DraftOrderHeld,
+		Items:  []model.DraftOrderItemEntity{{ProductID: kopi, Quantity: 1, FiredQuantity: 1}},
+	}, nil)
+	mockDraftRepo.On("FindDraftOrderByID", cancelledID.String()).Return(model.DraftOrderEntity{ID: cancelledID, Status: model.DraftOrderCancelled}, nil)
+	mockProductRepo.On("FindProductByID", nasiGoreng.String()).Return(model.ProductEntity{ID: nasiGoreng, CategoryID: &food}, nil)
+	mockProductRepo.On("FindProductByID", kopi.String()).Return(model.ProductEntity{ID: kopi, CategoryID: &drinks}, nil)
+	// food has no station, only the coffee goes to the bar
+	mockRepo.On("FindStationsByCategory").Return(map[uuid.UUID]uuid.UUID{drinks: bar}, nil)
+	ticket := model.KitchenTicketEntity{ID: uuid.New(), DraftOrderID: draftID, StationID: bar, StationName: "Bar", Status: model.KitchenTicketQueued}
+	mockRepo.On("FireKitchenTickets", mock.MatchedBy(func(d model.DraftOrderEntity) bool { return d.ID == draftID }), mock.MatchedBy(func(tickets []model.KitchenTicketEntity) bool {
+		return len(tickets) == 1 && tickets[0].StationID == bar && tickets[0].Items[0].ProductID == kopi
+	})).Return([]model.KitchenTicketEntity{ticket}, nil)
+	mockPublisher.On("Publish", mock.MatchedBy(func(e event.Event) bool {
+		return e.Type == event.KitchenTicketCreated && e.Subject == utils.EncodeBase62(bar.String())
+	})).Return()
+
+	tickets, err := service.FireDraftOrder(utils.EncodeBase62(draftID.String()))
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	assert.Equal(t, "Bar", tickets[0].Station)
+
+	_, err = service.FireDraftOrder(utils.EncodeBase62(firedID.String()))
+	assert.ErrorIs(t, err, ErrInvalidKitchen)
+	_, err = service.FireDraftOrder(utils.EncodeBase62(cancelledID.String()))
+	assert.ErrorIs(t, err, ErrDraftOrderStatus)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestKitchenServiceUpdateKitchenTicketStatus(t *testing.T) {
+	mockRepo := new(mocks.MockKitchenRepository)
+	mockPublisher := new(mocks.MockPublisher)
+	service := NewKitchenService(mockRepo, new(mocks.MockDraftOrderRepository), new(mocks.MockProductRepository), new(mocks.MockCategoryRepository), mockPublisher)
+
+	ticketID, stationID := uuid.New(), uuid.New()
+	mockRepo.On("FindKitchenTicketByID", ticketID.String()).Return(model.KitchenTicketEntity{ID: ticketID, StationID: stationID, Status: model.KitchenTicketPreparing, Version: 2}, nil)
+	mockRepo.On("UpdateKitchenTicketStatus", mock.MatchedBy(func(ticket model.KitchenTicketEntity) bool {
+		return ticket.Status == model.KitchenTicketReady && ticket.Version == 2
+	})).Return(model.KitchenTicketEntity{ID: ticketID, StationID: stationID, Status: model.KitchenTicketReady, Version: 3}, nil)
+	mockPublisher.On("Publish", mock.MatchedBy(func(e event.Event) bool { return e.Type == event.KitchenTicketUpdated })).Return()
+
+	id := utils.EncodeBase62(ticketID.String())
+	_, err := service.UpdateKitchenTicketStatus(id, model.UpdateKitchenTicketStatusRequest{Status: "burnt"})
+	assert.ErrorIs(t, err, ErrInvalidKitchen)
+	_, err = service.UpdateKitchenTicketStatus(id, model.UpdateKitchenTicketStatusRequest{Status: model.KitchenTicketQueued})
+	assert.ErrorIs(t, err, ErrKitchenTicketStatus)
+
+	ticket, err := service.UpdateKitchenTicketStatus(id, model.UpdateKitchenTicketStatusRequest{Status: model.KitchenTicketReady})
+	require.NoError(t, err)
+	assert.Equal(t, model.KitchenTicketReady, ticket.Status)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestKitchenServiceCreateKitchenStation(t *testing.T) {
+	mockRepo := new(mocks.MockKitchenRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewKitchenService(mockRepo, new(mocks.MockDraftOrderRepository), new(mocks.MockProductRepository), mockCategoryRepo, new(mocks.MockPublisher))
+
+	drinks, unknown := uuid.New(), uuid.New()
+	mockCategoryRepo.On("FindCategoryByID", drinks.String()).Return(model.CategoryEntity{ID: drinks}, nil)
+	mockCategoryRepo.On("FindCategoryByID", unknown.String()).Return(model.CategoryEntity{}, errors.New("category not found"))
+	mockRepo.On("InsertKitchenStation", mock.Anything).Return(model.KitchenStationEntity{ID: uuid.New(), Name: "Bar", IsActive: true, CategoryIDs: []uuid.UUID{drinks}}, nil)
+
+	_, err := service.CreateKitchenStation(model.CreateKitchenStationRequest{Name: "Bar", CategoryIDs: []string{utils.EncodeBase62(unknown.String())}})
+	assert.ErrorIs(t, err, ErrInvalidKitchen)
+	_, err = service.CreateKitchenStation(model.CreateKitchenStationRequest{})
+	assert.ErrorIs(t, err, ErrInvalidKitchen)
+
+	station, err := service.CreateKitchenStation(model.CreateKitchenStationRequest{Name: "Bar", CategoryIDs: []string{utils.EncodeBase62(drinks.String())}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{utils.EncodeBase62(drinks.String())}, station.CategoryIDs)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// defaultExpiryWindow is used when GET /api/inventory/expiring has no within
+const defaultExpiryWindow = 7
+
+type LotService interface {
+	FetchLots(productID string) ([]model.ProductLot, error)
+	ReceiveLot(productID string, request model.CreateProductLotRequest) (model.ProductLot, error)
+	// FetchExpiringLots lists the lots with stock left expiring within a window like "7d", expired ones included
+	FetchExpiringLots(within string) ([]model.ProductLot, error)
+}
+
+type lotService struct {
+	repository  repository.LotRepository
+	productRepo repository.ProductRepository
+	outletRepo  repository.OutletRepository
+}
+
+func NewLotService(repository repository.LotRepository, productRepo repository.ProductRepository, outletRepo repository.OutletRepository) LotService {
+	return &lotService{
+		repository:  repository,
+		productRepo: productRepo,
+		outletRepo:  outletRepo,
+	}
+}
+
+func (s *lotService) FetchLots(productID string) ([]model.ProductLot, error) {
+	entities, err := s.repository.FindLotsByProductID(utils.DecodeBase62(productID))
+	if err != nil {
+		return nil, err
+	}
+	return toLotModels(entities), nil
+}
+
+func (s *lotService) ReceiveLot(productID string, request model.CreateProductLotRequest) (model.ProductLot, error) {
+	parsedID, err := uuid.Parse(utils.DecodeBase62(productID))
+	if err != nil {
+		return model.ProductLot{}, fmt.Errorf("%w: invalid product id", ErrInvalidLot)
+	}
+	request.LotNumber = strings.TrimSpace(request.LotNumber)
+	if request.LotNumber == "" {
+		return model.ProductLot{}, fmt.Errorf("%w: lot_number is required", ErrInvalidLot)
+	}
+	if request.ExpiryDate.IsZero() {
+		return model.ProductLot{}, fmt.Errorf("%w: expiry_date is required", ErrInvalidLot)
+	}
+	if request.Quantity <= 0 {
+		return model.ProductLot{}, fmt.Errorf("%w: quantity must be greater than zero", ErrInvalidLot)
+	}
+
+	lot := request.ToEntity(parsedID)
+	if lot.IsExpired(time.Now()) {
+		return model.ProductLot{}, fmt.Errorf("%w: the lot has already expired", ErrInvalidLot)
+	}
+
+	product, err := s.productRepo.FindProductByID(parsedID.String())
+	if err != nil {
+		return model.ProductLot{}, fmt.Errorf("%w: product not found", ErrInvalidLot)
+	}
+	if product.IsBundle() {
+		return model.ProductLot{}, fmt.Errorf("%w: a bundle holds no stock, receive lots of its components", ErrInvalidLot)
+	}
+	// a lot received at an outlet is sold from that outlet's stock only
+	if lot.OutletID != nil {
+		outlet, err := s.outletRepo.FindOutletByID(lot.OutletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return model.ProductLot{}, fmt.Errorf("%w: outlet not found", ErrInvalidLot)
+		}
+		if !outlet.IsActive {
+			return model.ProductLot{}, fmt.Errorf("%w: outlet %s is not active", ErrInvalidLot, outlet.Code)
+		}
+	}
+
+	entity, err := s.repository.InsertLot(*lot)
+	if err != nil {
+		return model.ProductLot{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *lotService) FetchExpiringLots(within string) ([]model.ProductLot, error) {
+	days, err := parseExpiryWindow(within)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := s.repository.FindExpiringLots(time.Now().AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+	return toLotModels(entities), nil
+}
+
+// parseExpiryWindow reads a number of days, written "7d" or "7"
+func parseExpiryWindow(within string) (int, error) {
+	if within == "" {
+		return defaultExpiryWindow, nil
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(within, "d"))
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("%w: within must be a number of days like 7d", ErrInvalidLot)
+	}
+	return days, nil
+}
+
+func toLotModels(entities []model.ProductLotEntity) []model.ProductLot {
+	lots := []model.ProductLot{}
+	for _, entity := range entities {
+		lots = append(lots, *entity.ToModel())
+	}
+	return lots
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLotServiceReceiveLot(t *testing.T) {
+	mockRepo := new(mocks.MockLotRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewLotService(mockRepo, mockProductRepo, new(mocks.MockOutletRepository))
+
+	productID := uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Susu"}, nil)
+	mockRepo.On("InsertLot", mock.MatchedBy(func(l model.ProductLotEntity) bool {
+		return l.ProductID == productID && l.LotNumber == "A1" && l.QuantityReceived == 12
+	})).Return(model.ProductLotEntity{ID: uuid.New(), ProductID: productID, LotNumber: "A1", QuantityReceived: 12, QuantityRemaining: 12}, nil)
+
+	lot, err := service.ReceiveLot(utils.EncodeBase62(productID.String()), model.CreateProductLotRequest{
+		LotNumber:  " A1 ",
+		ExpiryDate: model.NewDate(time.Now().AddDate(0, 0, 14)),
+		Quantity:   12,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, lot.QuantityRemaining)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLotServiceReceiveLot_Invalid(t *testing.T) {
+	mockRepo := new(mocks.MockLotRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	service := NewLotService(mockRepo, mockProductRepo, mockOutletRepo)
+
+	productID := uuid.New()
+	bundleID, milkID, closedID := uuid.New(), uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", bundleID.String()).Return(model.ProductEntity{ID: bundleID, Type: model.ProductTypeBundle}, nil)
+	mockProductRepo.On("FindProductByID", milkID.String()).Return(model.ProductEntity{ID: milkID, Type: model.ProductTypeStandard}, nil)
+	mockOutletRepo.On("FindOutletByID", closedID.String()).Return(model.OutletEntity{ID: closedID, Code: "BDG"}, nil)
+	mockOutletRepo.On("FindOutletByID", mock.Anything).Return(model.OutletEntity{}, errors.New("outlet not found"))
+	tomorrow := model.NewDate(time.Now().AddDate(0, 0, 1))
+
+	tests := []struct {
+		name      string
+		productID uuid.UUID
+		request   model.CreateProductLotRequest
+	}{
+		{"lot number required", productID, model.CreateProductLotRequest{ExpiryDate: tomorrow, Quantity: 1}},
+		{"expiry date required", productID, model.CreateProductLotRequest{LotNumber: "A1", Quantity: 1}},
+		{"quantity positive", productID, model.CreateProductLotRequest{LotNumber: "A1", ExpiryDate: tomorrow}},
+		{"already expired", productID, model.CreateProductLotRequest{LotNumber: "A1", ExpiryDate: model.NewDate(time.Now().AddDate(0, 0, -1)), Quantity: 1}},
+		{"bundle", bundleID, model.CreateProductLotRequest{LotNumber: "A1", ExpiryDate: tomorrow, Quantity: 1}},
+		{"unknown outlet", milkID, model.CreateProductLotRequest{OutletID: utils.EncodeBase62(uuid.New().String()), LotNumber: "A1", ExpiryDate: tomorrow, Quantity: 1}},
+		{"inactive outlet", milkID, model.CreateProductLotRequest{OutletID: utils.EncodeBase62(closedID.String()), LotNumber: "A1", ExpiryDate: tomorrow, Quantity: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ReceiveLot(utils.EncodeBase62(tt.productID.String()), tt.request)
+			assert.ErrorIs(t, err, ErrInvalidLot)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "InsertLot", mock.Anything)
+}
+
+func TestLotServiceFetchExpiringLots(t *testing.T) {
+	mockRepo := new(mocks.MockLotRepository)
+	service := NewLotService(mockRepo, new(mocks.MockProductRepository), new(mocks.MockOutletRepository))
+
+	mockRepo.On("FindExpiringLots", mock.MatchedBy(func(until time.Time) bool {
+		return model.NewDate(until).Equal(model.NewDate(time.Now().AddDate(0, 0, 3)).Time)
+	})).Return([]model.ProductLotEntity{{ID: uuid.New(), ProductID: uuid.New(), LotNumber: "A1", ExpiryDate: time.Now()}}, nil)
+
+	lots, err := service.FetchExpiringLots("3d")
+
+	require.NoError(t, err)
+	assert.Len(t, lots, 1)
+	assert.Equal(t, 0, lots[0].DaysUntilExpiry)
+
+	_, err = service.FetchExpiringLots("soon")
+	assert.ErrorIs(t, err, ErrInvalidLot)
+	_, err = service.FetchExpiringLots("-1d")
+	assert.ErrorIs(t, err, ErrInvalidLot)
+}
+
+func TestParseExpiryWindow(t *testing.T) {
+	days, err := parseExpiryWindow("")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultExpiryWindow, days)
+
+	days, err = parseExpiryWindow("14")
+	assert.NoError(t, err)
+	assert.Equal(t, 14, days)
+}
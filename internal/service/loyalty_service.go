@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// LoyaltyService runs the shop's points program. Sales earn and redeem points through TransactionService,
+// this service configures the program, shows a customer's points and books corrections.
+type LoyaltyService interface {
+	FetchLoyaltyProgram() (model.LoyaltyProgram, error)
+	UpdateLoyaltyProgram(request model.UpdateLoyaltyProgramRequest) (model.LoyaltyProgram, error)
+	FetchCustomerPoints(customerID string) (model.CustomerPoints, error)
+	// ReverseLoyaltyEntry books the opposite of one of the customer's entries, the ledger itself never changes
+	ReverseLoyaltyEntry(customerID string, request model.CreateLoyaltyReversalRequest) (model.LoyaltyEntry, error)
+}
+
+type loyaltyService struct {
+	repository         repository.LoyaltyRepository
+	customerRepository repository.CustomerRepository
+	categoryRepository repository.CategoryRepository
+}
+
+func NewLoyaltyService(repository repository.LoyaltyRepository, customerRepository repository.CustomerRepository, categoryRepository repository.CategoryRepository) LoyaltyService {
+	return &loyaltyService{
+		repository:         repository,
+		customerRepository: customerRepository,
+		categoryRepository: categoryRepository,
+	}
+}
+
+func (s *loyaltyService) FetchLoyaltyProgram() (model.LoyaltyProgram, error) {
+	program, err := s.repository.FindLoyaltyProgram()
+	if err != nil {
+		return model.LoyaltyProgram{}, err
+	}
+	return *program.ToModel(), nil
+}
+
+func (s *loyaltyService) UpdateLoyaltyProgram(request model.UpdateLoyaltyProgramRequest) (model.LoyaltyProgram, error) {
+	program := *request.ToEntity()
+	if err := s.validateLoyaltyProgram(program); err != nil {
+		return model.LoyaltyProgram{}, err
+	}
+
+	entity, err := s.repository.SaveLoyaltyProgram(program)
+	if err != nil {
+		return model.LoyaltyProgram{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *loyaltyService) FetchCustomerPoints(customerID string) (model.CustomerPoints, error) {
+	customer, err := s.customerRepository.FindCustomerByID(utils.DecodeBase62(customerID))
+	if err != nil {
+		return model.CustomerPoints{}, err
+	}
+	if _, err := expirePoints(s.repository, customer, time.Now()); err != nil {
+		return model.CustomerPoints{}, err
+	}
+
+	entries, err := s.repository.FindLoyaltyEntries(customer.ID.String())
+	if err != nil {
+		return model.CustomerPoints{}, err
+	}
+	return *model.NewCustomerPoints(customer.ID, entries), nil
+}
+
+func (s *loyaltyService) ReverseLoyaltyEntry(customerID string, request model.CreateLoyaltyReversalRequest) (model.LoyaltyEntry, error) {
+	customer, err := s.customerRepository.FindCustomerByID(utils.DecodeBase62(customerID))
+	if err != nil {
+		return model.LoyaltyEntry{}, err
+	}
+	now := time.Now()
+	balance, err := expirePoints(s.repository, customer, now)
+	if err != nil {
+		return model.LoyaltyEntry{}, err
+	}
+	entries, err := s.repository.FindLoyaltyEntries(customer.ID.String())
+	if err != nil {
+		return model.LoyaltyEntry{}, err
+	}
+
+	entry, err := reversibleEntry(entries, request.EntryID)
+	if err != nil {
+		return model.LoyaltyEntry{}, err
+	}
+	if entry.Points > balance {
+		return model.LoyaltyEntry{}, fmt.Errorf("%w: reversing takes %d points but the customer holds %d", ErrInvalidLoyalty, entry.Points, balance)
+	}
+
+	id, _ := uuid.NewV7()
+	reversal := model.LoyaltyEntryEntity{
+		ID:               id,
+		CustomerID:       customer.ID,
+		Type:             model.LoyaltyEntryReversal,
+		Points:           -entry.Points,
+		TransactionID:    entry.TransactionID,
+		ReferenceEntryID: &entry.ID,
+		Notes:            request.Notes,
+		CreatedBy:        "USER",
+	}
+	if reversal.Points > 0 {
+		program, err := s.repository.FindLoyaltyProgram()
+		if err != nil {
+			return model.LoyaltyEntry{}, err
+		}
+		reversal.ExpiresAt = program.ExpiresAt(now)
+	}
+
+	inserted, err := s.repository.InsertLoyaltyEntries([]model.LoyaltyEntryEntity{reversal})
+	if err != nil {
+		return model.LoyaltyEntry{}, err
+	}
+	return *inserted[0].ToModel(), nil
+}
+
+// reversibleEntry finds the entry to reverse, a reversal cannot itself be reversed and nothing is reversed twice.
+// Points that already lapsed cannot be reversed either, their expire entry took them out.
+func reversibleEntry(entries []model.LoyaltyEntryEntity, id string) (model.LoyaltyEntryEntity, error) {
+	entryID, err := uuid.Parse(utils.DecodeBase62(id))
+	if err != nil {
+		return model.LoyaltyEntryEntity{}, fmt.Errorf("%w: invalid entry id", ErrInvalidLoyalty)
+	}
+
+	var entry *model.LoyaltyEntryEntity
+	for i, e := range entries {
+		if e.ID == entryID {
+			entry = &entries[i]
+		}
+		if e.ReferenceEntryID != nil && *e.ReferenceEntryID == entryID {
+			switch e.Type {
+			case model.LoyaltyEntryReversal:
+				return model.LoyaltyEntryEntity{}, fmt.Errorf("%w: entry is already reversed", ErrInvalidLoyalty)
+			case model.LoyaltyEntryExpire:
+				return model.LoyaltyEntryEntity{}, fmt.Errorf("%w: the entry's points have expired", ErrInvalidLoyalty)
+			}
+		}
+	}
+	if entry == nil {
+		return model.LoyaltyEntryEntity{}, fmt.Errorf("%w: entry not found for the customer", ErrInvalidLoyalty)
+	}
+	if entry.Type == model.LoyaltyEntryReversal {
+		return model.LoyaltyEntryEntity{}, fmt.Errorf("%w: a reversal cannot be reversed", ErrInvalidLoyalty)
+	}
+	return *entry, nil
+}
+
+func (s *loyaltyService) validateLoyaltyProgram(program model.LoyaltyProgramEntity) error {
+	if program.SpendPerPoint <= 0 {
+		return fmt.Errorf("%w: spend per point must be greater than zero", ErrInvalidLoyalty)
+	}
+	if program.PointValue <= 0 {
+		return fmt.Errorf("%w: point value must be greater than zero", ErrInvalidLoyalty)
+	}
+	if program.ExpiryDays != nil && *program.ExpiryDays <= 0 {
+		return fmt.Errorf("%w: expiry days must be greater than zero, leave it out to keep points forever", ErrInvalidLoyalty)
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, m := range program.CategoryMultipliers {
+		if m.CategoryID == uuid.Nil {
+			return fmt.Errorf("%w: invalid category id", ErrInvalidLoyalty)
+		}
+		if m.Multiplier < 0 {
+			return fmt.Errorf("%w: multiplier cannot be negative", ErrInvalidLoyalty)
+		}
+		if seen[m.CategoryID] {
+			return fmt.Errorf("%w: the same category cannot have two multipliers", ErrInvalidLoyalty)
+		}
+		seen[m.CategoryID] = true
+
+		category, err := s.categoryRepository.FindCategoryByID(m.CategoryID.String())
+		if err != nil || category.DeletedAt != nil {
+			return fmt.Errorf("%w: category not found", ErrInvalidLoyalty)
+		}
+	}
+	return nil
+}
+
+// expirePoints books an expire entry for every lapsed lot of the customer's points and returns the balance left
+func expirePoints(repository repository.LoyaltyRepository, customer model.CustomerEntity, now time.Time) (int, error) {
+	entries, err := repository.FindLoyaltyEntries(customer.ID.String())
+	if err != nil {
+		return 0, err
+	}
+	balance := customer.PointsBalance
+	if len(entries) > 0 {
+		balance = entries[len(entries)-1].BalanceAfter
+	}
+
+	var expired []model.LoyaltyEntryEntity
+	for _, lot := range model.OpenPointLots(entries) {
+		if lot.ExpiresAt == nil || lot.ExpiresAt.After(now) {
+			continue
+		}
+		id, _ := uuid.NewV7()
+		expired = append(expired, model.LoyaltyEntryEntity{
+			ID:               id,
+			CustomerID:       customer.ID,
+			Type:             model.LoyaltyEntryExpire,
+			Points:           -lot.Remaining,
+			ReferenceEntryID: &lot.EntryID,
+			CreatedBy:        "SYSTEM",
+		})
+	}
+	if len(expired) == 0 {
+		return balance, nil
+	}
+
+	inserted, err := repository.InsertLoyaltyEntries(expired)
+	if err != nil {
+		return 0, err
+	}
+	return inserted[len(inserted)-1].BalanceAfter, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyServiceUpdateLoyaltyProgram(t *testing.T) {
+	mockRepo := new(mocks.MockLoyaltyRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewLoyaltyService(mockRepo, new(mocks.MockCustomerRepository), mockCategoryRepo)
+
+	categoryID, unknownID := uuid.New(), uuid.New()
+	mockCategoryRepo.On("FindCategoryByID", categoryID.String()).Return(model.CategoryEntity{ID: categoryID}, nil)
+	mockCategoryRepo.On("FindCategoryByID", unknownID.String()).Return(model.CategoryEntity{}, errors.New("category not found"))
+	mockRepo.On("SaveLoyaltyProgram", mock.Anything).Return(model.LoyaltyProgramEntity{IsActive: true, SpendPerPoint: 1000, PointValue: 10, Version: 1}, nil)
+
+	multiplier := func(id uuid.UUID, m float64) []model.LoyaltyCategoryMultiplier {
+		return []model.LoyaltyCategoryMultiplier{{CategoryID: utils.EncodeBase62(id.String()), Multiplier: m}}
+	}
+	zero := 0
+	for _, request := range []model.UpdateLoyaltyProgramRequest{
+		{PointValue: 10},
+		{SpendPerPoint: 1000},
+		{SpendPerPoint: 1000, PointValue: 10, ExpiryDays: &zero},
+		{SpendPerPoint: 1000, PointValue: 10, CategoryMultipliers: multiplier(categoryID, -1)},
+		{SpendPerPoint: 1000, PointValue: 10, CategoryMultipliers: multiplier(unknownID, 2)},
+		{SpendPerPoint: 1000, PointValue: 10, CategoryMultipliers: append(multiplier(categoryID, 2), multiplier(categoryID, 3)...)},
+	} {
+		_, err := service.UpdateLoyaltyProgram(request)
+		assert.ErrorIs(t, err, ErrInvalidLoyalty)
+	}
+	mockRepo.AssertNotCalled(t, "SaveLoyaltyProgram", mock.Anything)
+
+	program, err := service.UpdateLoyaltyProgram(model.UpdateLoyaltyProgramRequest{
+		IsActive: true, SpendPerPoint: 1000, PointValue: 10, CategoryMultipliers: multiplier(categoryID, 2),
+	})
+	require.NoError(t, err)
+	assert.True(t, program.IsActive)
+}
+
+func TestLoyaltyServiceFetchCustomerPoints_ExpiresLapsedPoints(t *testing.T) {
+	mockRepo := new(mocks.MockLoyaltyRepository)
+	mockCustomerRepo := new(mocks.MockCustomerRepository)
+	service := NewLoyaltyService(mockRepo, mockCustomerRepo, new(mocks.MockCategoryRepository))
+
+	customerID, lapsedID := uuid.New(), uuid.New()
+	lapsedAt, validUntil := time.Now().AddDate(0, 0, -1), time.Now().AddDate(0, 1, 0)
+	entries := []model.LoyaltyEntryEntity{
+		{ID: lapsedID, CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 30, BalanceAfter: 30, ExpiresAt: &lapsedAt, CreatedAt: lapsedAt.AddDate(0, -1, 0)},
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 20, BalanceAfter: 50, ExpiresAt: &validUntil, CreatedAt: time.Now()},
+	}
+	expire := model.LoyaltyEntryEntity{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryExpire, Points: -30, BalanceAfter: 20, ReferenceEntryID: &lapsedID}
+
+	mockCustomerRepo.On("FindCustomerByID", customerID.String()).Return(model.CustomerEntity{ID: customerID, PointsBalance: 50}, nil)
+	mockRepo.On("FindLoyaltyEntries", customerID.String()).Return(entries, nil).Once()
+	mockRepo.On("InsertLoyaltyEntries", mock.MatchedBy(func(e []model.LoyaltyEntryEntity) bool {
+		return len(e) == 1 && e[0].Type == model.LoyaltyEntryExpire && e[0].Points == -30 && *e[0].ReferenceEntryID == lapsedID
+	})).Return([]model.LoyaltyEntryEntity{expire}, nil).Once()
+	mockRepo.On("FindLoyaltyEntries", customerID.String()).Return(append(entries, expire), nil).Once()
+
+	points, err := service.FetchCustomerPoints(utils.EncodeBase62(customerID.String()))
+
+	require.NoError(t, err)
+	assert.Equal(t, 20, points.Balance)
+	require.Len(t, points.Expiring, 1)
+	assert.Equal(t, 20, points.Expiring[0].Points)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLoyaltyServiceReverseLoyaltyEntry(t *testing.T) {
+	mockRepo := new(mocks.MockLoyaltyRepository)
+	mockCustomerRepo := new(mocks.MockCustomerRepository)
+	service := NewLoyaltyService(mockRepo, mockCustomerRepo, new(mocks.MockCategoryRepository))
+
+	customerID, earnID, redeemID, reversedID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	entries := []model.LoyaltyEntryEntity{
+		{ID: earnID, CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 40, BalanceAfter: 40},
+		{ID: reversedID, CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 5, BalanceAfter: 45},
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryReversal, Points: -5, BalanceAfter: 40, ReferenceEntryID: &reversedID},
+		{ID: redeemID, CustomerID: customerID, Type: model.LoyaltyEntryRedeem, Points: -10, BalanceAfter: 30},
+	}
+	mockCustomerRepo.On("FindCustomerByID", customerID.String()).Return(model.CustomerEntity{ID: customerID, PointsBalance: 30}, nil)
+	mockRepo.On("FindLoyaltyEntries", customerID.String()).Return(entries, nil)
+	mockRepo.On("FindLoyaltyProgram").Return(model.LoyaltyProgramEntity{IsActive: true}, nil)
+	mockRepo.On("InsertLoyaltyEntries", mock.MatchedBy(func(e []model.LoyaltyEntryEntity) bool {
+		return len(e) == 1 && e[0].Type == model.LoyaltyEntryReversal && e[0].Points == 10 && *e[0].ReferenceEntryID == redeemID
+	})).Return([]model.LoyaltyEntryEntity{{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryReversal, Points: 10, BalanceAfter: 40, ReferenceEntryID: &redeemID}}, nil)
+
+	reverse := func(id uuid.UUID) (model.LoyaltyEntry, error) {
+		return service.ReverseLoyaltyEntry(utils.EncodeBase62(customerID.String()), model.CreateLoyaltyReversalRequest{EntryID: utils.EncodeBase62(id.String()), Notes: "wrong customer"})
+	}
+
+	_, err := reverse(earnID)
+	assert.ErrorIs(t, err, ErrInvalidLoyalty, "40 points cannot be taken from a balance of 30")
+
+	_, err = reverse(reversedID)
+	assert.ErrorIs(t, err, ErrInvalidLoyalty, "an entry is reversed once")
+
+	_, err = reverse(uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidLoyalty)
+
+	reversal, err := reverse(redeemID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LoyaltyEntryReversal, reversal.Type)
+	assert.Equal(t, 10, reversal.Points)
+	assert.Equal(t, utils.EncodeBase62(redeemID.String()), reversal.ReferenceEntryID)
+}
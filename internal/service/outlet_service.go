@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type OutletService interface {
+	FetchOutlets() ([]model.Outlet, error)
+	FetchOutletByID(id string) (model.Outlet, error)
+	CreateOutlet(request model.CreateOutletRequest) (model.Outlet, error)
+	UpdateOutletByID(id string, request model.UpdateOutletRequest) (model.Outlet, error)
+	DeleteOutletByID(id string) error
+	FetchOutletStocks(id string) ([]model.OutletStock, error)
+	UpdateOutletPrice(id, productID string, request model.UpdateOutletPriceRequest) (model.OutletStock, error)
+}
+
+type outletService struct {
+	repository        repository.OutletRepository
+	productRepository repository.ProductRepository
+}
+
+func NewOutletService(repository repository.OutletRepository, productRepository repository.ProductRepository) OutletService {
+	return &outletService{
+		repository:        repository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *outletService) FetchOutlets() ([]model.Outlet, error) {
+	entities, err := s.repository.FindOutlets()
+	if err != nil {
+		return nil, err
+	}
+
+	outlets := []model.Outlet{}
+	for _, entity := range entities {
+		outlets = append(outlets, *entity.ToModel())
+	}
+	return outlets, nil
+}
+
+func (s *outletService) FetchOutletByID(id string) (model.Outlet, error) {
+	entity, err := s.repository.FindOutletByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.Outlet{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *outletService) CreateOutlet(request model.CreateOutletRequest) (model.Outlet, error) {
+	if err := validateOutlet(request.Code, request.Name); err != nil {
+		return model.Outlet{}, err
+	}
+
+	entity, err := s.repository.InsertOutlet(*request.ToEntity())
+	if err != nil {
+		return model.Outlet{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *outletService) UpdateOutletByID(id string, request model.UpdateOutletRequest) (model.Outlet, error) {
+	if err := validateOutlet(request.Code, request.Name); err != nil {
+		return model.Outlet{}, err
+	}
+
+	entity, err := s.repository.UpdateOutletByID(utils.DecodeBase62(id), *request.ToEntity())
+	if err != nil {
+		return model.Outlet{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *outletService) DeleteOutletByID(id string) error {
+	return s.repository.DeleteOutletByID(utils.DecodeBase62(id))
+}
+
+func (s *outletService) FetchOutletStocks(id string) ([]model.OutletStock, error) {
+	outlet, err := s.repository.FindOutletByID(utils.DecodeBase62(id))
+	if err != nil {
+		return nil, err
+	}
+	entities, err := s.repository.FindOutletStocks(outlet.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	stocks := []model.OutletStock{}
+	for _, entity := range entities {
+		stocks = append(stocks, *entity.ToModel())
+	}
+	return stocks, nil
+}
+
+// UpdateOutletPrice sets or, with a null price, clears the outlet's own price of the product
+func (s *outletService) UpdateOutletPrice(id, productID string, request model.UpdateOutletPriceRequest) (model.OutletStock, error) {
+	if request.PriceOverride != nil && *request.PriceOverride < 0 {
+		return model.OutletStock{}, fmt.Errorf("%w: price cannot be negative", ErrInvalidOutlet)
+	}
+	outlet, err := s.repository.FindOutletByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.OutletStock{}, err
+	}
+	parsedProductID, err := uuid.Parse(utils.DecodeBase62(productID))
+	if err != nil {
+		return model.OutletStock{}, fmt.Errorf("%w: invalid product id", ErrInvalidOutlet)
+	}
+	product, err := s.productRepository.FindProductByID(parsedProductID.String())
+	if err != nil || product.DeletedAt != nil {
+		return model.OutletStock{}, fmt.Errorf("%w: product not found", ErrInvalidOutlet)
+	}
+
+	entity, err := s.repository.UpdateOutletPrice(outlet.ID.String(), product.ID.String(), request.PriceOverride)
+	if err != nil {
+		return model.OutletStock{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func validateOutlet(code, name string) error {
+	if strings.TrimSpace(code) == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidOutlet)
+	}
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidOutlet)
+	}
+	return nil
+}
+
+// parseOutletID decodes an optional Base62 outlet id, an empty id means every outlet
+func parseOutletID(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(utils.DecodeBase62(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid outlet id", ErrInvalidOutlet)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutletServiceCreateOutlet(t *testing.T) {
+	mockRepo := new(mocks.MockOutletRepository)
+	service := NewOutletService(mockRepo, new(mocks.MockProductRepository))
+
+	mockRepo.On("InsertOutlet", mock.MatchedBy(func(o model.OutletEntity) bool {
+		return o.Code == "JKT-01" && o.IsActive
+	})).Return(model.OutletEntity{ID: uuid.New(), Code: "JKT-01", Name: "Jakarta", IsActive: true}, nil)
+
+	outlet, err := service.CreateOutlet(model.CreateOutletRequest{Code: "JKT-01", Name: "Jakarta"})
+	require.NoError(t, err)
+	assert.Equal(t, "JKT-01", outlet.Code)
+
+	_, err = service.CreateOutlet(model.CreateOutletRequest{Name: "Jakarta"})
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+	_, err = service.UpdateOutletByID("o1", model.UpdateOutletRequest{Code: "JKT-01", Name: " "})
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+	mockRepo.AssertNumberOfCalls(t, "InsertOutlet", 1)
+}
+
+func TestOutletServiceUpdateOutletPrice(t *testing.T) {
+	mockRepo := new(mocks.MockOutletRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewOutletService(mockRepo, mockProductRepo)
+
+	outletID, productID, missingID := uuid.New(), uuid.New(), uuid.New()
+	price := int64(12000)
+	mockRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID}, nil)
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Price: 10000}, nil)
+	mockProductRepo.On("FindProductByID", missingID.String()).Return(model.ProductEntity{}, errors.New("product not found"))
+	mockRepo.On("UpdateOutletPrice", outletID.String(), productID.String(), &price).
+		Return(model.OutletStockEntity{OutletID: outletID, ProductID: productID, ProductPrice: 10000, PriceOverride: &price}, nil)
+
+	outlet := utils.EncodeBase62(outletID.String())
+	stock, err := service.UpdateOutletPrice(outlet, utils.EncodeBase62(productID.String()), model.UpdateOutletPriceRequest{PriceOverride: &price})
+	require.NoError(t, err)
+	assert.Equal(t, int64(12000), stock.Price)
+
+	negative := int64(-1)
+	_, err = service.UpdateOutletPrice(outlet, utils.EncodeBase62(productID.String()), model.UpdateOutletPriceRequest{PriceOverride: &negative})
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+	_, err = service.UpdateOutletPrice(outlet, utils.EncodeBase62(missingID.String()), model.UpdateOutletPriceRequest{})
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+	mockRepo.AssertNumberOfCalls(t, "UpdateOutletPrice", 1)
+}
+
+func TestOutletServiceFetchOutletStocks(t *testing.T) {
+	mockRepo := new(mocks.MockOutletRepository)
+	service := NewOutletService(mockRepo, new(mocks.MockProductRepository))
+
+	outletID := uuid.New()
+	mockRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID}, nil)
+	mockRepo.On("FindOutletStocks", outletID.String()).Return([]model.OutletStockEntity{{OutletID: outletID, ProductID: uuid.New(), Stock: 4, ProductPrice: 5000}}, nil)
+
+	stocks, err := service.FetchOutletStocks(utils.EncodeBase62(outletID.String()))
+
+	require.NoError(t, err)
+	require.Len(t, stocks, 1)
+	assert.Equal(t, 4, stocks[0].Stock)
+	assert.Equal(t, int64(5000), stocks[0].Price)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// PriceListService keeps the price lists and the products' scheduled prices.
+// Checkout picks the price in effect when the sale is rung up, see TransactionService.
+type PriceListService interface {
+	FetchPriceLists() ([]model.PriceList, error)
+	FetchPriceListByID(id string) (model.PriceList, error)
+	CreatePriceList(request model.CreatePriceListRequest) (model.PriceList, error)
+	UpdatePriceListByID(id string, request model.UpdatePriceListRequest) (model.PriceList, error)
+	DeletePriceListByID(id string) error
+	// FetchProductPrices lists the product's scheduled prices of every list, marking the ones in effect now
+	FetchProductPrices(productID string) ([]model.ProductPrice, error)
+	SchedulePrice(productID string, request model.SchedulePriceRequest) (model.ProductPrice, error)
+	// CancelPrice drops a scheduled price that has not started yet, one in effect is replaced by scheduling another
+	CancelPrice(productID, id string) error
+	FetchPriceTiers(productID string) ([]model.ProductPriceTier, error)
+	// ReplacePriceTiers sets the product's whole quantity tier table, an empty one removes the tiers
+	ReplacePriceTiers(productID string, request model.ReplacePriceTiersRequest) ([]model.ProductPriceTier, error)
+}
+
+type priceListService struct {
+	repository        repository.PriceListRepository
+	productRepository repository.ProductRepository
+}
+
+func NewPriceListService(repository repository.PriceListRepository, productRepository repository.ProductRepository) PriceListService {
+	return &priceListService{
+		repository:        repository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *priceListService) FetchPriceLists() ([]model.PriceList, error) {
+	entities, err := s.repository.FindPriceLists()
+	if err != nil {
+		return nil, err
+	}
+
+	priceLists := []model.PriceList{}
+	for _, entity := range entities {
+		priceLists = append(priceLists, *entity.ToModel())
+	}
+	return priceLists, nil
+}
+
+func (s *priceListService) FetchPriceListByID(id string) (model.PriceList, error) {
+	entity, err := s.repository.FindPriceListByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.PriceList{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *priceListService) CreatePriceList(request model.CreatePriceListRequest) (model.PriceList, error) {
+	if err := validatePriceList(request.Code, request.Name); err != nil {
+		return model.PriceList{}, err
+	}
+
+	entity, err := s.repository.InsertPriceList(*request.ToEntity())
+	if err != nil {
+		return model.PriceList{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *priceListService) UpdatePriceListByID(id string, request model.UpdatePriceListRequest) (model.PriceList, error) {
+	if err := validatePriceList(request.Code, request.Name); err != nil {
+		return model.PriceList{}, err
+	}
+
+	entity, err := s.repository.UpdatePriceListByID(utils.DecodeBase62(id), *request.ToEntity())
+	if err != nil {
+		return model.PriceList{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *priceListService) DeletePriceListByID(id string) error {
+	return s.repository.DeletePriceListByID(utils.DecodeBase62(id))
+}
+
+func (s *priceListService) FetchProductPrices(productID string) ([]model.ProductPrice, error) {
+	product, err := s.findProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := s.repository.FindProductPrices(product.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	prices := []model.ProductPrice{}
+	for _, entity := range entities {
+		price := entity.ToModel()
+		if current := model.EffectiveProductPrice(entities, entity.PriceListID, now); current != nil {
+			price.Current = current.ID == entity.ID
+		}
+		prices = append(prices, *price)
+	}
+	return prices, nil
+}
+
+func (s *priceListService) SchedulePrice(productID string, request model.SchedulePriceRequest) (model.ProductPrice, error) {
+	product, err := s.findProduct(productID)
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	if request.Price < 0 {
+		return model.ProductPrice{}, fmt.Errorf("%w: price cannot be negative", ErrInvalidPriceList)
+	}
+	// a price starting in the past would reprice what was already sold, allowing for a terminal's clock running behind
+	if request.StartsAt != nil && request.StartsAt.Before(time.Now().Add(-maxClockSkew)) {
+		return model.ProductPrice{}, fmt.Errorf("%w: starts_at cannot be in the past", ErrInvalidPriceList)
+	}
+
+	price := *request.ToEntity(product.ID)
+	if price.PriceListID != nil {
+		if *price.PriceListID == uuid.Nil {
+			return model.ProductPrice{}, fmt.Errorf("%w: price list not found", ErrInvalidPriceList)
+		}
+		priceList, err := s.repository.FindPriceListByID(price.PriceListID.String())
+		if err != nil || priceList.DeletedAt != nil {
+			return model.ProductPrice{}, fmt.Errorf("%w: price list not found", ErrInvalidPriceList)
+		}
+	}
+
+	entity, err := s.repository.InsertProductPrice(price)
+	if err != nil {
+		return model.ProductPrice{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *priceListService) CancelPrice(productID, id string) error {
+	product, err := s.findProduct(productID)
+	if err != nil {
+		return err
+	}
+	price, err := s.repository.FindProductPriceByID(product.ID.String(), utils.DecodeBase62(id))
+	if err != nil {
+		return err
+	}
+	if price.ID == uuid.Nil || price.DeletedAt != nil {
+		return fmt.Errorf("%w: scheduled price not found", ErrInvalidPriceList)
+	}
+	if !price.StartsAt.After(time.Now()) {
+		return fmt.Errorf("%w: it started at %s, schedule a new price instead", ErrProductPriceStarted, price.StartsAt.Format(time.RFC3339))
+	}
+	return s.repository.CancelProductPrice(product.ID.String(), price.ID.String())
+}
+
+func (s *priceListService) FetchPriceTiers(productID string) ([]model.ProductPriceTier, error) {
+	product, err := s.findProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := s.repository.FindProductPriceTiers(product.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return priceTiersToModel(entities), nil
+}
+
+func (s *priceListService) ReplacePriceTiers(productID string, request model.ReplacePriceTiersRequest) ([]model.ProductPriceTier, error) {
+	product, err := s.findProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[int]bool{}
+	for _, t := range request.Tiers {
+		if t.MinQuantity < 1 {
+			return nil, fmt.Errorf("%w: tier min_quantity must be at least 1", ErrInvalidPriceList)
+		}
+		if t.Price < 0 {
+			return nil, fmt.Errorf("%w: tier price cannot be negative", ErrInvalidPriceList)
+		}
+		if seen[t.MinQuantity] {
+			return nil, fmt.Errorf("%w: two tiers start at quantity %d", ErrInvalidPriceList, t.MinQuantity)
+		}
+		seen[t.MinQuantity] = true
+	}
+
+	entities, err := s.repository.ReplaceProductPriceTiers(product.ID.String(), request.ToEntities(product.ID))
+	if err != nil {
+		return nil, err
+	}
+	return priceTiersToModel(entities), nil
+}
+
+func priceTiersToModel(entities []model.ProductPriceTierEntity) []model.ProductPriceTier {
+	tiers := []model.ProductPriceTier{}
+	for _, entity := range entities {
+		tiers = append(tiers, *entity.ToModel())
+	}
+	return tiers
+}
+
+func (s *priceListService) findProduct(id string) (model.ProductEntity, error) {
+	product, err := s.productRepository.FindProductByID(utils.DecodeBase62(id))
+	if err != nil || product.DeletedAt != nil {
+		return model.ProductEntity{}, fmt.Errorf("%w: product not found", ErrInvalidPriceList)
+	}
+	return product, nil
+}
+
+func validatePriceList(code, name string) error {
+	if strings.TrimSpace(code) == "" {
+		return fmt.Errorf("%w: code is required", ErrInvalidPriceList)
+	}
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPriceList)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceListServiceCreatePriceList(t *testing.T) {
+	mockRepo := new(mocks.MockPriceListRepository)
+	service := NewPriceListService(mockRepo, new(mocks.MockProductRepository))
+
+	mockRepo.On("InsertPriceList", mock.MatchedBy(func(l model.PriceListEntity) bool {
+		return l.Code == "GROSIR" && l.IsActive
+	})).Return(model.PriceListEntity{ID: uuid.New(), Code: "GROSIR", Name: "Wholesale", IsActive: true}, nil)
+
+	priceList, err := service.CreatePriceList(model.CreatePriceListRequest{Code: "GROSIR", Name: "Wholesale"})
+	require.NoError(t, err)
+	assert.Equal(t, "GROSIR", priceList.Code)
+
+	_, err = service.CreatePriceList(model.CreatePriceListRequest{Name: "Wholesale"})
+	assert.ErrorIs(t, err, ErrInvalidPriceList)
+	_, err = service.UpdatePriceListByID("l1", model.UpdatePriceListRequest{Code: "GROSIR", Name: " "})
+	assert.ErrorIs(t, err, ErrInvalidPriceList)
+	mockRepo.AssertNumberOfCalls(t, "InsertPriceList", 1)
+}
+
+func TestPriceListServiceSchedulePrice(t *testing.T) {
+	mockRepo := new(mocks.MockPriceListRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewPriceListService(mockRepo, mockProductRepo)
+
+	productID, listID, missingID := uuid.New(), uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Price: 10000}, nil)
+	mockProductRepo.On("FindProductByID", missingID.String()).Return(model.ProductEntity{}, errors.New("product not found"))
+	mockRepo.On("FindPriceListByID", listID.String()).Return(model.PriceListEntity{ID: listID, Code: "GROSIR", IsActive: true}, nil)
+	mockRepo.On("FindPriceListByID", missingID.String()).Return(model.PriceListEntity{}, errors.New("price list not found"))
+	mockRepo.On("InsertProductPrice", mock.MatchedBy(func(p model.ProductPriceEntity) bool {
+		return p.ProductID == productID && p.PriceListID != nil && *p.PriceListID == listID
+	})).Return(model.ProductPriceEntity{ID: uuid.New(), ProductID: productID, PriceListID: &listID, Price: 9000}, nil)
+
+	product := utils.EncodeBase62(productID.String())
+	startsAt := time.Now().Add(24 * time.Hour)
+	price, err := service.SchedulePrice(product, model.SchedulePriceRequest{PriceListID: utils.EncodeBase62(listID.String()), Price: 9000, StartsAt: &startsAt})
+	require.NoError(t, err)
+	assert.Equal(t, int64(9000), price.Price)
+
+	past := time.Now().Add(-time.Hour)
+	for _, tt := range []struct {
+		name    string
+		product string
+		request model.SchedulePriceRequest
+	}{
+		{"missing product", utils.EncodeBase62(missingID.String()), model.SchedulePriceRequest{Price: 9000}},
+		{"negative price", product, model.SchedulePriceRequest{Price: -1}},
+		{"starts in the past", product, model.SchedulePriceRequest{Price: 9000, StartsAt: &past}},
+		{"missing price list", product, model.SchedulePriceRequest{PriceListID: utils.EncodeBase62(missingID.String()), Price: 9000}},
+		{"undecodable price list", product, model.SchedulePriceRequest{PriceListID: "!!", Price: 9000}},
+	} {
+		_, err = service.SchedulePrice(tt.product, tt.request)
+		assert.ErrorIs(t, err, ErrInvalidPriceList, tt.name)
+	}
+	mockRepo.AssertNumberOfCalls(t, "InsertProductPrice", 1)
+}
+
+func TestPriceListServiceFetchProductPricesMarksCurrent(t *testing.T) {
+	mockRepo := new(mocks.MockPriceListRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewPriceListService(mockRepo, mockProductRepo)
+
+	productID, listID := uuid.New(), uuid.New()
+	now := time.Now()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID}, nil)
+	mockRepo.On("FindProductPrices", productID.String()).Return([]model.ProductPriceEntity{
+		{ID: uuid.New(), ProductID: productID, Price: 10000, StartsAt: now.Add(-48 * time.Hour)},
+		{ID: uuid.New(), ProductID: productID, Price: 11000, StartsAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), ProductID: productID, Price: 12000, StartsAt: now.Add(time.Hour)},
+		{ID: uuid.New(), ProductID: productID, PriceListID: &listID, Price: 9000, StartsAt: now.Add(-time.Hour)},
+	}, nil)
+
+	prices, err := service.FetchProductPrices(utils.EncodeBase62(productID.String()))
+	require.NoError(t, err)
+	require.Len(t, prices, 4)
+	assert.Equal(t, []bool{false, true, false, true}, []bool{prices[0].Current, prices[1].Current, prices[2].Current, prices[3].Current})
+}
+
+func TestPriceListServiceCancelPrice(t *testing.T) {
+	mockRepo := new(mocks.MockPriceListRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewPriceListService(mockRepo, mockProductRepo)
+
+	productID, upcomingID, startedID, missingID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID}, nil)
+	mockRepo.On("FindProductPriceByID", productID.String(), upcomingID.String()).
+		Return(model.ProductPriceEntity{ID: upcomingID, ProductID: productID, StartsAt: time.Now().Add(time.Hour)}, nil)
+	mockRepo.On("FindProductPriceByID", productID.String(), startedID.String()).
+		Return(model.ProductPriceEntity{ID: startedID, ProductID: productID, StartsAt: time.Now().Add(-time.Hour)}, nil)
+	mockRepo.On("FindProductPriceByID", productID.String(), missingID.String()).Return(model.ProductPriceEntity{}, nil)
+	mockRepo.On("CancelProductPrice", productID.String(), upcomingID.String()).Return(nil)
+
+	product := utils.EncodeBase62(productID.String())
+	require.NoError(t, service.CancelPrice(product, utils.EncodeBase62(upcomingID.String())))
+	assert.ErrorIs(t, service.CancelPrice(product, utils.EncodeBase62(startedID.String())), ErrProductPriceStarted)
+	assert.ErrorIs(t, service.CancelPrice(product, utils.EncodeBase62(missingID.String())), ErrInvalidPriceList)
+	mockRepo.AssertNumberOfCalls(t, "CancelProductPrice", 1)
+}
+
+func TestPriceListServiceReplacePriceTiers(t *testing.T) {
+	mockRepo := new(mocks.MockPriceListRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewPriceListService(mockRepo, mockProductRepo)
+
+	productID := uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Price: 3500}, nil)
+	mockRepo.On("ReplaceProductPriceTiers", productID.String(), mock.MatchedBy(func(tiers []model.ProductPriceTierEntity) bool {
+		return len(tiers) == 2 && tiers[0].MinQuantity == 1 && tiers[1].MinQuantity == 12
+	})).Return([]model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 1, Price: 3500},
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 12, Price: 3000},
+	}, nil)
+
+	product := utils.EncodeBase62(productID.String())
+	tiers, err := service.ReplacePriceTiers(product, model.ReplacePriceTiersRequest{Tiers: []model.PriceTierRequest{
+		{MinQuantity: 12, Price: 3000},
+		{MinQuantity: 1, Price: 3500},
+	}})
+	require.NoError(t, err)
+	assert.Len(t, tiers, 2)
+
+	for _, invalid := range [][]model.PriceTierRequest{
+		{{MinQuantity: 0, Price: 3500}},
+		{{MinQuantity: 12, Price: -1}},
+		{{MinQuantity: 12, Price: 3000}, {MinQuantity: 12, Price: 2900}},
+	} {
+		_, err = service.ReplacePriceTiers(product, model.ReplacePriceTiersRequest{Tiers: invalid})
+		assert.ErrorIs(t, err, ErrInvalidPriceList)
+	}
+	mockRepo.AssertNumberOfCalls(t, "ReplaceProductPriceTiers", 1)
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/storage"
+	"codewithumam-kasir-api/internal/utils"
+)
+
+// thumbnailQuality is the JPEG quality thumbnails are saved at
+const thumbnailQuality = 85
+
+// The images are listed with the product, model.Product carries their URLs.
+type ProductImageService interface {
+	// UploadProductImage keeps the image as sent and a JPEG thumbnail of it, the image goes after the product's others
+	UploadProductImage(productID string, data []byte) (model.ProductImage, error)
+	DeleteProductImage(productID, imageID string) error
+}
+
+type productImageService struct {
+	repository repository.ProductRepository
+	store      storage.BlobStore
+}
+
+func NewProductImageService(repository repository.ProductRepository, store storage.BlobStore) ProductImageService {
+	return &productImageService{
+		repository: repository,
+		store:      store,
+	}
+}
+
+func (s *productImageService) UploadProductImage(productID string, data []byte) (model.ProductImage, error) {
+	product, err := s.repository.FindProductByID(utils.DecodeBase62(productID))
+	if err != nil || product.DeletedAt != nil {
+		return model.ProductImage{}, fmt.Errorf("%w: product not found", ErrInvalidProduct)
+	}
+	if len(data) == 0 {
+		return model.ProductImage{}, fmt.Errorf("%w: the file is empty", ErrInvalidProductImage)
+	}
+	if len(data) > model.MaxProductImageSize {
+		return model.ProductImage{}, fmt.Errorf("%w: the file is over %d MB", ErrInvalidProductImage, model.MaxProductImageSize>>20)
+	}
+	// the content decides the type, not the file name or the header the client sent
+	contentType := http.DetectContentType(data)
+	if _, ok := model.ProductImageTypes[contentType]; !ok {
+		return model.ProductImage{}, fmt.Errorf("%w: only JPEG, PNG and GIF images are accepted, not %s", ErrInvalidProductImage, contentType)
+	}
+	img, _, err := utils.DecodeImage(data, model.MaxProductImagePixels)
+	if err != nil {
+		return model.ProductImage{}, fmt.Errorf("%w: %v", ErrInvalidProductImage, err)
+	}
+
+	thumbnail := utils.Thumbnail(img, model.ProductThumbnailSize)
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if contentType == "image/jpeg" {
+		orientation := utils.JPEGOrientation(data)
+		thumbnail = utils.Orient(thumbnail, orientation)
+		if orientation >= 5 {
+			width, height = height, width
+		}
+	}
+	thumbnailData, err := utils.EncodeJPEG(thumbnail, thumbnailQuality)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+
+	image, err := model.NewProductImageEntity(product.ID, contentType)
+	if err != nil {
+		return model.ProductImage{}, err
+	}
+	image.Size, image.Width, image.Height = int64(len(data)), width, height
+	image.URL, image.ThumbnailURL = s.store.URL(image.Key), s.store.URL(image.ThumbnailKey)
+
+	if err := s.store.Put(image.Key, contentType, data); err != nil {
+		return model.ProductImage{}, err
+	}
+	if err := s.store.Put(image.ThumbnailKey, "image/jpeg", thumbnailData); err != nil {
+		s.removeFiles(image)
+		return model.ProductImage{}, err
+	}
+	inserted, err := s.repository.InsertProductImage(image)
+	if err != nil {
+		s.removeFiles(image)
+		return model.ProductImage{}, err
+	}
+	return *inserted.ToModel(), nil
+}
+
+func (s *productImageService) DeleteProductImage(productID, imageID string) error {
+	product, err := s.repository.FindProductByID(utils.DecodeBase62(productID))
+	if err != nil || product.DeletedAt != nil {
+		return fmt.Errorf("%w: product not found", ErrInvalidProduct)
+	}
+	imageUUID := utils.DecodeBase62(imageID)
+	found := false
+	for _, i := range product.Images {
+		found = found || i.ID.String() == imageUUID
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrProductImageNotFound, imageID)
+	}
+
+	image, err := s.repository.DeleteProductImage(product.ID.String(), imageUUID)
+	if err != nil {
+		return err
+	}
+	s.removeFiles(image)
+	return nil
+}
+
+// removeFiles deletes the image's files from the store. A file left behind only takes space,
+// so a failure here does not fail the request that is already done or failing.
+func (s *productImageService) removeFiles(image model.ProductImageEntity) {
+	_ = s.store.Delete(image.Key)
+	_ = s.store.Delete(image.ThumbnailKey)
+}
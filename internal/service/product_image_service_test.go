@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/storage"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// failingStore refuses to keep the files whose key ends with failSuffix and records what is deleted
+type failingStore struct {
+	*storage.LocalStore
+	failSuffix string
+	deleted    []string
+}
+
+func (s *failingStore) Put(key, contentType string, data []byte) error {
+	if s.failSuffix != "" && strings.HasSuffix(key, s.failSuffix) {
+		return errors.New("store unavailable")
+	}
+	return s.LocalStore.Put(key, contentType, data)
+}
+
+func (s *failingStore) Delete(key string) error {
+	s.deleted = append(s.deleted, key)
+	return s.LocalStore.Delete(key)
+}
+
+func TestProductImageServiceUploadProductImage(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	dir := t.TempDir()
+	service := NewProductImageService(mockRepo, storage.NewLocalStore(dir, "/media"))
+
+	product := model.ProductEntity{ID: uuid.New(), Name: "Kopi"}
+	mockRepo.On("FindProductByID", product.ID.String()).Return(product, nil)
+	var inserted model.ProductImageEntity
+	call := mockRepo.On("InsertProductImage", mock.MatchedBy(func(i model.ProductImageEntity) bool {
+		return i.ProductID == product.ID && i.ContentType == "image/png" && i.Width == 800 && i.Height == 400
+	}))
+	call.Run(func(args mock.Arguments) {
+		inserted = args.Get(0).(model.ProductImageEntity)
+		inserted.Position, inserted.CreatedAt = 1, time.Now()
+		call.ReturnArguments = mock.Arguments{inserted, nil}
+	})
+
+	data := testPNG(t, 800, 400)
+	uploaded, err := service.UploadProductImage(utils.EncodeBase62(product.ID.String()), data)
+	require.NoError(t, err)
+	assert.Equal(t, "/media/"+inserted.Key, uploaded.URL)
+	assert.Equal(t, "/media/"+inserted.ThumbnailKey, uploaded.ThumbnailURL)
+	assert.Equal(t, int64(len(data)), uploaded.Size)
+	assert.Equal(t, 1, uploaded.Position)
+
+	original, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(inserted.Key)))
+	require.NoError(t, err)
+	assert.Equal(t, data, original, "the image is kept as sent")
+	thumbnail, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(inserted.ThumbnailKey)))
+	require.NoError(t, err)
+	config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductThumbnailSize, config.Width)
+	assert.Equal(t, model.ProductThumbnailSize/2, config.Height)
+}
+
+func TestProductImageServiceUploadProductImage_Invalid(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductImageService(mockRepo, storage.NewLocalStore(t.TempDir(), "/media"))
+
+	product := model.ProductEntity{ID: uuid.New(), Name: "Kopi"}
+	deletedAt := time.Now()
+	retired := model.ProductEntity{ID: uuid.New(), Name: "Teh", DeletedAt: &deletedAt}
+	mockRepo.On("FindProductByID", product.ID.String()).Return(product, nil)
+	mockRepo.On("FindProductByID", retired.ID.String()).Return(retired, nil)
+	id := utils.EncodeBase62(product.ID.String())
+
+	_, err := service.UploadProductImage(utils.EncodeBase62(retired.ID.String()), testPNG(t, 10, 10))
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+	_, err = service.UploadProductImage(id, nil)
+	assert.ErrorIs(t, err, ErrInvalidProductImage)
+	_, err = service.UploadProductImage(id, []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"))
+	assert.ErrorIs(t, err, ErrInvalidProductImage)
+	_, err = service.UploadProductImage(id, append([]byte("\x89PNG\r\n\x1a\n"), "truncated"...))
+	assert.ErrorIs(t, err, ErrInvalidProductImage)
+	_, err = service.UploadProductImage(id, make([]byte, model.MaxProductImageSize+1))
+	assert.ErrorIs(t, err, ErrInvalidProductImage)
+	mockRepo.AssertNotCalled(t, "InsertProductImage", mock.Anything)
+}
+
+func TestProductImageServiceUploadProductImage_StoreFailure(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	store := &failingStore{LocalStore: storage.NewLocalStore(t.TempDir(), "/media")}
+	service := NewProductImageService(mockRepo, store)
+
+	product := model.ProductEntity{ID: uuid.New(), Name: "Kopi"}
+	mockRepo.On("FindProductByID", product.ID.String()).Return(product, nil)
+	mockRepo.On("InsertProductImage", mock.Anything).Return(model.ProductImageEntity{}, errors.New("db down"))
+
+	// the image went in but the row did not, both files are removed again
+	_, err := service.UploadProductImage(utils.EncodeBase62(product.ID.String()), testPNG(t, 10, 10))
+	assert.EqualError(t, err, "db down")
+	require.Len(t, store.deleted, 2)
+	assert.Contains(t, store.deleted[1], "_thumb.jpg")
+
+	// the thumbnail could not be kept, the image already is and is removed
+	store.failSuffix, store.deleted = "_thumb.jpg", nil
+	_, err = service.UploadProductImage(utils.EncodeBase62(product.ID.String()), testPNG(t, 10, 10))
+	assert.EqualError(t, err, "store unavailable")
+	require.Len(t, store.deleted, 2)
+	assert.True(t, strings.HasSuffix(store.deleted[0], ".png"))
+	mockRepo.AssertNumberOfCalls(t, "InsertProductImage", 1)
+}
+
+func TestProductImageServiceDeleteProductImage(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	store := &failingStore{LocalStore: storage.NewLocalStore(t.TempDir(), "/media")}
+	service := NewProductImageService(mockRepo, store)
+
+	product := model.ProductEntity{ID: uuid.New(), Name: "Kopi"}
+	image, err := model.NewProductImageEntity(product.ID, "image/png")
+	require.NoError(t, err)
+	product.Images = []model.ProductImageEntity{image}
+	mockRepo.On("FindProductByID", product.ID.String()).Return(product, nil)
+	mockRepo.On("DeleteProductImage", product.ID.String(), image.ID.String()).Return(image, nil)
+	id := utils.EncodeBase62(product.ID.String())
+
+	err = service.DeleteProductImage(id, utils.EncodeBase62(uuid.NewString()))
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+
+	require.NoError(t, service.DeleteProductImage(id, utils.EncodeBase62(image.ID.String())))
+	assert.Equal(t, []string{image.Key, image.ThumbnailKey}, store.deleted)
+	mockRepo.AssertExpectations(t)
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"fmt"
+	"strings"
+
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/repository"
 	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
 )
 
 // TODO: optional try to implement partial update (PATCH)
@@ -12,19 +16,33 @@ import (
 type ProductService interface {
 	FetchProducts() ([]model.Product, error)
 	FetchProductsByNameAndActiveStatus(name string, activeStatus *bool) ([]model.Product, error)
+	// FetchProductsByCategory lists the live products of the category and of every category below it
+	FetchProductsByCategory(categoryID string) ([]model.Product, error)
 	FetchProductByID(id string) (model.Product, error)
 	CreateProduct(product model.CreateProductRequest) (model.Product, error)
 	UpdateProductByID(id string, product model.UpdateProductRequest) (model.Product, error)
 	DeleteProductByID(id string) error
+	FetchLowStockProducts() ([]model.LowStockAlert, error)
+	// BatchProducts creates, updates and deletes products in one go, with a result per operation in request order.
+	// An atomic batch applies nothing when any operation fails, a best effort one applies every operation that succeeds.
+	BatchProducts(request model.ProductBatchRequest) (model.ProductBatchResponse, error)
+	FetchProductUnits(id string) ([]model.ProductUnit, error)
+	// ReplaceProductUnits sets the whole set of units the product is also sold in, an empty one removes them
+	ReplaceProductUnits(id string, request model.ReplaceProductUnitsRequest) ([]model.ProductUnit, error)
 }
 
+// maxProductBatch caps the operations of one batch
+const maxProductBatch = 500
+
 type productService struct {
-	repository repository.ProductRepository
+	repository         repository.ProductRepository
+	categoryRepository repository.CategoryRepository
 }
 
-func NewProductService(repository repository.ProductRepository) ProductService {
+func NewProductService(repository repository.ProductRepository, categoryRepository repository.CategoryRepository) ProductService {
 	return &productService{
-		repository: repository,
+		repository:         repository,
+		categoryRepository: categoryRepository,
 	}
 }
 
@@ -54,6 +72,32 @@ func (s *productService) FetchProductsByNameAndActiveStatus(name string, activeS
 	return products, nil
 }
 
+func (s *productService) FetchProductsByCategory(categoryID string) ([]model.Product, error) {
+	categories, err := s.categoryRepository.FindCategories()
+	if err != nil {
+		return nil, err
+	}
+	parsed, _ := uuid.Parse(utils.DecodeBase62(categoryID))
+	subtree := model.NewCategoryTree(categories).Subtree(parsed)
+	if len(subtree) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCategoryNotFound, categoryID)
+	}
+	ids := []string{}
+	for _, id := range subtree {
+		ids = append(ids, id.String())
+	}
+
+	entities, err := s.repository.FindProductsByCategoryIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	products := []model.Product{}
+	for _, entity := range entities {
+		products = append(products, *entity.ToModel())
+	}
+	return products, nil
+}
+
 func (s *productService) FetchProductByID(id string) (model.Product, error) {
 	entity, err := s.repository.FindProductByID(utils.DecodeBase62(id))
 	if err != nil {
@@ -63,7 +107,21 @@ func (s *productService) FetchProductByID(id string) (model.Product, error) {
 }
 
 func (s *productService) CreateProduct(request model.CreateProductRequest) (model.Product, error) {
-	entity, err := s.repository.InsertProduct(*request.ToEntity())
+	product := *request.ToEntity()
+	if err := s.validateBundle(product); err != nil {
+		return model.Product{}, err
+	}
+	if err := validateReorder(product); err != nil {
+		return model.Product{}, err
+	}
+	if err := validateUnit(product); err != nil {
+		return model.Product{}, err
+	}
+	if err := s.categoryLookup().resolve(&product); err != nil {
+		return model.Product{}, err
+	}
+
+	entity, err := s.repository.InsertProduct(product)
 	if err != nil {
 		return model.Product{}, err
 	}
@@ -71,7 +129,25 @@ func (s *productService) CreateProduct(request model.CreateProductRequest) (mode
 }
 
 func (s *productService) UpdateProductByID(id string, request model.UpdateProductRequest) (model.Product, error) {
-	entity, err := s.repository.UpdateProductByID(utils.DecodeBase62(id), *request.ToEntity())
+	product := *request.ToEntity()
+	product.ID, _ = uuid.Parse(utils.DecodeBase62(id))
+	if err := s.keepStoredType(&product); err != nil {
+		return model.Product{}, err
+	}
+	if err := s.validateBundle(product); err != nil {
+		return model.Product{}, err
+	}
+	if err := validateReorder(product); err != nil {
+		return model.Product{}, err
+	}
+	if err := validateUnit(product); err != nil {
+		return model.Product{}, err
+	}
+	if err := s.categoryLookup().resolve(&product); err != nil {
+		return model.Product{}, err
+	}
+
+	entity, err := s.repository.UpdateProductByID(utils.DecodeBase62(id), product)
 	if err != nil {
 		return model.Product{}, err
 	}
@@ -81,3 +157,330 @@ func (s *productService) UpdateProductByID(id string, request model.UpdateProduc
 func (s *productService) DeleteProductByID(id string) error {
 	return s.repository.DeleteProductByID(utils.DecodeBase62(id))
 }
+
+func (s *productService) FetchLowStockProducts() ([]model.LowStockAlert, error) {
+	entities, err := s.repository.FindLowStockProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []model.LowStockAlert{}
+	for _, entity := range entities {
+		alerts = append(alerts, *entity.ToLowStockAlert())
+	}
+	return alerts, nil
+}
+
+func (s *productService) BatchProducts(request model.ProductBatchRequest) (model.ProductBatchResponse, error) {
+	mode := request.Mode
+	if mode == "" {
+		mode = model.ProductBatchAtomic
+	}
+	if mode != model.ProductBatchAtomic && mode != model.ProductBatchBestEffort {
+		return model.ProductBatchResponse{}, fmt.Errorf("%w: mode must be %s or %s", ErrInvalidProduct, model.ProductBatchAtomic, model.ProductBatchBestEffort)
+	}
+	if len(request.Operations) == 0 {
+		return model.ProductBatchResponse{}, fmt.Errorf("%w: operations are required", ErrInvalidProduct)
+	}
+	if len(request.Operations) > maxProductBatch {
+		return model.ProductBatchResponse{}, fmt.Errorf("%w: at most %d operations can be sent at once", ErrInvalidProduct, maxProductBatch)
+	}
+	atomic := mode == model.ProductBatchAtomic
+
+	response := model.ProductBatchResponse{Mode: mode, Results: make([]model.ProductBatchResult, len(request.Operations))}
+	var operations []model.ProductBatchOperationEntity
+	// positions maps each operation sent to the repository back to its index in the request
+	var positions []int
+	categories := s.categoryLookup()
+	for i, op := range request.Operations {
+		response.Results[i] = model.ProductBatchResult{Index: i, Op: op.Op, ID: op.ID}
+		entity, err := s.batchOperation(op, categories)
+		if err != nil {
+			response.Results[i].Status = model.ProductBatchFailed
+			response.Results[i].Error = err.Error()
+			continue
+		}
+		operations = append(operations, entity)
+		positions = append(positions, i)
+	}
+
+	// an invalid operation fails an atomic batch before anything is written
+	if len(positions) == len(request.Operations) || !atomic {
+		if len(operations) > 0 {
+			results, err := s.repository.ApplyProductBatch(operations, atomic)
+			if err != nil {
+				return model.ProductBatchResponse{}, err
+			}
+			for j, result := range results {
+				r := &response.Results[positions[j]]
+				if result.Error != "" {
+					r.Status = model.ProductBatchFailed
+					r.Error = result.Error
+					continue
+				}
+				r.Status = model.ProductBatchSucceeded
+				r.ID = utils.EncodeBase62(result.Product.ID.String())
+				if operations[j].Op != model.ProductBatchDelete {
+					r.Product = result.Product.ToModel()
+				}
+			}
+		}
+	}
+
+	for _, r := range response.Results {
+		if r.Status == model.ProductBatchFailed {
+			response.Failed++
+		}
+	}
+	for i := range response.Results {
+		r := &response.Results[i]
+		if r.Status == model.ProductBatchFailed {
+			continue
+		}
+		if atomic && response.Failed > 0 {
+			// rolled back or never tried, either way nothing of it was kept
+			*r = model.ProductBatchResult{Index: i, Op: r.Op, ID: request.Operations[i].ID, Status: model.ProductBatchSkipped}
+			continue
+		}
+		response.Succeeded++
+	}
+	response.Committed = response.Succeeded > 0
+	return response, nil
+}
+
+// batchOperation turns an operation of a batch into what the repository applies, checking it as the single endpoints do
+func (s *productService) batchOperation(op model.ProductBatchOperation, categories *categoryLookup) (model.ProductBatchOperationEntity, error) {
+	if op.Op != model.ProductBatchCreate && op.Op != model.ProductBatchUpdate && op.Op != model.ProductBatchDelete {
+		return model.ProductBatchOperationEntity{}, fmt.Errorf("%w: op must be create, update or delete", ErrInvalidProduct)
+	}
+	if op.Op != model.ProductBatchDelete && len(op.Product) == 0 {
+		return model.ProductBatchOperationEntity{}, fmt.Errorf("%w: product is required", ErrInvalidProduct)
+	}
+	entity, ok := op.ToEntity()
+	if !ok {
+		return model.ProductBatchOperationEntity{}, fmt.Errorf("%w: invalid product body", ErrInvalidProduct)
+	}
+	if op.Op == model.ProductBatchDelete {
+		if entity.Product.ID == uuid.Nil {
+			return model.ProductBatchOperationEntity{}, fmt.Errorf("%w: invalid product id", ErrInvalidProduct)
+		}
+		return entity, nil
+	}
+	if op.Op == model.ProductBatchUpdate {
+		if entity.Product.ID == uuid.Nil {
+			return model.ProductBatchOperationEntity{}, fmt.Errorf("%w: invalid product id", ErrInvalidProduct)
+		}
+		if err := s.keepStoredType(&entity.Product); err != nil {
+			return model.ProductBatchOperationEntity{}, err
+		}
+	}
+
+	if err := s.validateBundle(entity.Product); err != nil {
+		return model.ProductBatchOperationEntity{}, err
+	}
+	if err := validateReorder(entity.Product); err != nil {
+		return model.ProductBatchOperationEntity{}, err
+	}
+	if err := validateUnit(entity.Product); err != nil {
+		return model.ProductBatchOperationEntity{}, err
+	}
+	if err := categories.resolve(&entity.Product); err != nil {
+		return model.ProductBatchOperationEntity{}, err
+	}
+	return entity, nil
+}
+
+// categoryLookup resolves the category products are assigned to, loading the categories on the first product naming one
+type categoryLookup struct {
+	repository repository.CategoryRepository
+	categories []model.CategoryEntity
+	loaded     bool
+}
+
+func (s *productService) categoryLookup() *categoryLookup {
+	return &categoryLookup{repository: s.categoryRepository}
+}
+
+// resolve points the product at its category by id, or else by name regardless of case.
+// A category that does not exist is an error instead of leaving the product uncategorized.
+func (l *categoryLookup) resolve(product *model.ProductEntity) error {
+	if product.CategoryID == nil && product.CategoryName == "" {
+		return nil
+	}
+	if !l.loaded {
+		categories, err := l.repository.FindCategories()
+		if err != nil {
+			return err
+		}
+		l.categories, l.loaded = categories, true
+	}
+
+	for _, c := range l.categories {
+		if c.DeletedAt != nil {
+			continue
+		}
+		if product.CategoryID != nil && c.ID == *product.CategoryID ||
+			product.CategoryID == nil && strings.EqualFold(c.Name, product.CategoryName) {
+			id := c.ID
+			product.CategoryID, product.CategoryName = &id, c.Name
+			return nil
+		}
+	}
+	if product.CategoryID != nil {
+		return fmt.Errorf("%w: category_id does not match any category", ErrCategoryNotFound)
+	}
+	return fmt.Errorf("%w: no category is named %s", ErrCategoryNotFound, product.CategoryName)
+}
+
+func validateReorder(product model.ProductEntity) error {
+	if product.ReorderPoint < 0 || product.ReorderQuantity < 0 {
+		return fmt.Errorf("%w: reorder point and reorder quantity cannot be negative", ErrInvalidProduct)
+	}
+	if product.IsBundle() && product.ReorderPoint > 0 {
+		return fmt.Errorf("%w: bundles have no stock of their own to reorder", ErrInvalidProduct)
+	}
+	return nil
+}
+
+func validateUnit(product model.ProductEntity) error {
+	if product.QuantityScale < 0 || product.QuantityScale > model.MaxQuantityScale {
+		return fmt.Errorf("%w: quantity scale must be between 0 and %d", ErrInvalidProduct, model.MaxQuantityScale)
+	}
+	if product.IsBundle() && product.QuantityScale > 0 {
+		return fmt.Errorf("%w: a bundle is sold whole, it cannot have a quantity scale", ErrInvalidProduct)
+	}
+	return nil
+}
+
+func (s *productService) FetchProductUnits(id string) ([]model.ProductUnit, error) {
+	product, err := s.repository.FindProductByID(utils.DecodeBase62(id))
+	if err != nil {
+		return nil, err
+	}
+	entities, err := s.repository.FindProductUnits(product.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return productUnitsToModel(entities, product.QuantityScale), nil
+}
+
+func (s *productService) ReplaceProductUnits(id string, request model.ReplaceProductUnitsRequest) ([]model.ProductUnit, error) {
+	product, err := s.repository.FindProductByID(utils.DecodeBase62(id))
+	if err != nil || product.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: product not found", ErrInvalidProduct)
+	}
+	if product.IsBundle() && len(request.Units) > 0 {
+		return nil, fmt.Errorf("%w: a bundle is sold whole, it has no other units", ErrInvalidProduct)
+	}
+
+	units := request.ToEntities(product.ID, product.QuantityScale)
+	seen := map[string]bool{strings.ToLower(product.Unit): true}
+	for _, u := range units {
+		if u.Name == "" {
+			return nil, fmt.Errorf("%w: unit name is required", ErrInvalidProduct)
+		}
+		if seen[strings.ToLower(u.Name)] {
+			return nil, fmt.Errorf("%w: unit %s is the base unit or listed twice", ErrInvalidProduct, u.Name)
+		}
+		seen[strings.ToLower(u.Name)] = true
+		if u.Factor <= 0 {
+			return nil, fmt.Errorf("%w: unit %s must hold a positive quantity of %s countable to %d decimals", ErrInvalidProduct, u.Name, product.Unit, product.QuantityScale)
+		}
+		if u.Price != nil && *u.Price < 0 {
+			return nil, fmt.Errorf("%w: unit %s price cannot be negative", ErrInvalidProduct, u.Name)
+		}
+	}
+
+	entities, err := s.repository.ReplaceProductUnits(product.ID.String(), units)
+	if err != nil {
+		return nil, err
+	}
+	return productUnitsToModel(entities, product.QuantityScale), nil
+}
+
+func productUnitsToModel(entities []model.ProductUnitEntity, scale int) []model.ProductUnit {
+	units := []model.ProductUnit{}
+	for _, entity := range entities {
+		units = append(units, *entity.ToModel(scale))
+	}
+	return units
+}
+
+// keepStoredType gives an update that leaves the type out the stored one, so editing a bundle's name does not
+// turn it into a standard product. A bundle keeps its pricing and components unless the update names new ones.
+func (s *productService) keepStoredType(product *model.ProductEntity) error {
+	if product.Type != "" {
+		return nil
+	}
+	stored, err := s.repository.FindProductByID(product.ID.String())
+	if err != nil || stored.DeletedAt != nil {
+		return fmt.Errorf("%w: product not found", ErrInvalidProduct)
+	}
+	product.Type = stored.Type
+	if !stored.IsBundle() {
+		return nil
+	}
+	if product.BundlePricing == "" {
+		product.BundlePricing = stored.BundlePricing
+	}
+	if len(product.Components) == 0 {
+		product.Components = stored.Components
+	}
+	return nil
+}
+
+// validateBundle checks the product type and, for bundles, that every component is an existing standard product,
+// that the bundle holds no stock of its own and is not itself a component of another, bundles do not nest either way
+func (s *productService) validateBundle(product model.ProductEntity) error {
+	switch product.Type {
+	case model.ProductTypeStandard:
+		if len(product.Components) > 0 || product.BundlePricing != "" {
+			return fmt.Errorf("%w: only bundles can have components", ErrInvalidProduct)
+		}
+		return nil
+	case model.ProductTypeBundle:
+	default:
+		return fmt.Errorf("%w: unknown product type %q", ErrInvalidProduct, product.Type)
+	}
+
+	if product.BundlePricing != model.BundlePricingFixed && product.BundlePricing != model.BundlePricingDerived {
+		return fmt.Errorf("%w: bundle pricing must be %q or %q", ErrInvalidProduct, model.BundlePricingFixed, model.BundlePricingDerived)
+	}
+	if len(product.Components) == 0 {
+		return fmt.Errorf("%w: bundle must have at least one component", ErrInvalidProduct)
+	}
+	// a bundle's stock is what its components make up, stock booked on the bundle itself would never be sold
+	if product.Stocks != 0 {
+		return fmt.Errorf("%w: a bundle holds no stock of its own, stock its components instead", ErrInvalidProduct)
+	}
+	containing, err := s.repository.FindBundlesByComponentID(product.ID.String())
+	if err != nil {
+		return err
+	}
+	if len(containing) > 0 {
+		return fmt.Errorf("%w: %s is a component of bundle %s and cannot be a bundle itself", ErrInvalidProduct, product.Name, containing[0].Name)
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, c := range product.Components {
+		if c.ComponentID == uuid.Nil {
+			return fmt.Errorf("%w: invalid component product id", ErrInvalidProduct)
+		}
+		if c.Quantity <= 0 {
+			return fmt.Errorf("%w: component quantity must be greater than zero", ErrInvalidProduct)
+		}
+		if c.ComponentID == product.ID || seen[c.ComponentID] {
+			return fmt.Errorf("%w: bundle cannot contain itself or the same component twice", ErrInvalidProduct)
+		}
+		seen[c.ComponentID] = true
+
+		component, err := s.repository.FindProductByID(c.ComponentID.String())
+		if err != nil || component.DeletedAt != nil {
+			return fmt.Errorf("%w: component product not found", ErrInvalidProduct)
+		}
+		if component.IsBundle() {
+			return fmt.Errorf("%w: bundle cannot contain another bundle", ErrInvalidProduct)
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	mocks "codewithumam-kasir-api/internal/mock"
 	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +17,7 @@ import (
 
 func TestProductServiceFetchProducts(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
 
 	now := time.Now()
 	entities := []model.ProductEntity{
@@ -37,7 +38,7 @@ func TestProductServiceFetchProducts(t *testing.T) {
 
 func TestProductServiceFetchProductsError(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
 
 	mockRepo.On("FindProducts").Return(nil, errors.New("database error"))
 
@@ -50,7 +51,7 @@ func TestProductServiceFetchProductsError(t *testing.T) {
 
 func TestProductServiceFetchProductByID(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
 
 	now := time.Now()
 	id := uuid.New()
@@ -71,7 +72,9 @@ func TestProductServiceFetchProductByID(t *testing.T) {
 
 func TestProductServiceCreateProduct(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewProductService(mockRepo, mockCategoryRepo)
+	mockCategoryRepo.On("FindCategories").Return([]model.CategoryEntity{{ID: uuid.New(), Name: "Electronics"}}, nil)
 
 	request := model.CreateProductRequest{
 		Name:     "New Product",
@@ -96,7 +99,11 @@ func TestProductServiceCreateProduct(t *testing.T) {
 
 func TestProductServiceUpdateProductByID(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewProductService(mockRepo, mockCategoryRepo)
+	mockCategoryRepo.On("FindCategories").Return([]model.CategoryEntity{{ID: uuid.New(), Name: "Updated Category"}}, nil)
+	productID := uuid.New()
+	mockRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Product", Type: model.ProductTypeStandard}, nil)
 
 	request := model.UpdateProductRequest{
 		Name:     "Updated Product",
@@ -112,7 +119,7 @@ func TestProductServiceUpdateProductByID(t *testing.T) {
 			CategoryName: "Updated Category", CreatedAt: time.Now(), UpdatedAt: time.Now(), Version: 2,
 		}, nil)
 
-	product, err := service.UpdateProductByID("test-id", request)
+	product, err := service.UpdateProductByID(utils.EncodeBase62(productID.String()), request)
 
 	require.NoError(t, err)
 	assert.Equal(t, "Updated Product", product.Name)
@@ -122,7 +129,7 @@ func TestProductServiceUpdateProductByID(t *testing.T) {
 
 func TestProductServiceDeleteProductByID(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
 
 	mockRepo.On("DeleteProductByID", mock.Anything).Return(nil)
 
@@ -134,7 +141,7 @@ func TestProductServiceDeleteProductByID(t *testing.T) {
 
 func TestProductServiceFetchProductsByNameAndActiveStatus(t *testing.T) {
 	mockRepo := new(mocks.MockProductRepository)
-	service := NewProductService(mockRepo)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
 
 	now := time.Now()
 	entities := []model.ProductEntity{
@@ -151,3 +158,336 @@ func TestProductServiceFetchProductsByNameAndActiveStatus(t *testing.T) {
 	assert.Equal(t, "Apple iPhone", products[0].Name)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestProductServiceCreateProduct_Bundle(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	componentID := uuid.New()
+	request := model.CreateProductRequest{
+		Name:          "Paket Hemat",
+		Type:          model.ProductTypeBundle,
+		BundlePricing: model.BundlePricingDerived,
+		Components: []model.BundleComponentRequest{
+			{ProductID: utils.EncodeBase62(componentID.String()), Quantity: 2},
+		},
+	}
+
+	mockRepo.On("FindProductByID", componentID.String()).
+		Return(model.ProductEntity{ID: componentID, Name: "Nasi", Price: 5000, Stocks: 10, Type: model.ProductTypeStandard}, nil)
+	mockRepo.On("FindBundlesByComponentID", mock.Anything).Return([]model.ProductEntity{}, nil)
+	mockRepo.On("InsertProduct", mock.AnythingOfType("model.ProductEntity")).
+		Return(model.ProductEntity{
+			ID: uuid.New(), Name: "Paket Hemat", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingDerived,
+			Components: []model.BundleComponentEntity{
+				{ComponentID: componentID, ComponentName: "Nasi", ComponentPrice: 5000, ComponentStocks: 10, Quantity: 2},
+			},
+		}, nil)
+
+	product, err := service.CreateProduct(request)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000), product.Price)
+	assert.Equal(t, 5, product.Stocks)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProductServiceCreateProduct_InvalidBundle(t *testing.T) {
+	componentID := uuid.New()
+	nestedID := uuid.New()
+	component := func(id uuid.UUID, qty int) []model.BundleComponentRequest {
+		return []model.BundleComponentRequest{{ProductID: utils.EncodeBase62(id.String()), Quantity: qty}}
+	}
+
+	tests := []struct {
+		name    string
+		request model.CreateProductRequest
+	}{
+		{"unknown type", model.CreateProductRequest{Name: "X", Type: "combo"}},
+		{"standard with components", model.CreateProductRequest{Name: "X", Components: component(componentID, 1)}},
+		{"missing pricing", model.CreateProductRequest{Name: "X", Type: model.ProductTypeBundle, Components: component(componentID, 1)}},
+		{"no components", model.CreateProductRequest{Name: "X", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingFixed}},
+		{"zero quantity", model.CreateProductRequest{Name: "X", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingFixed, Components: component(componentID, 0)}},
+		{"unknown component", model.CreateProductRequest{Name: "X", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingFixed, Components: component(uuid.New(), 1)}},
+		{"nested bundle", model.CreateProductRequest{Name: "X", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingFixed, Components: component(nestedID, 1)}},
+		{"bundle with own stock", model.CreateProductRequest{Name: "X", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingFixed, Components: component(componentID, 1), Stocks: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockProductRepository)
+			service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+			mockRepo.On("FindProductByID", nestedID.String()).Return(model.ProductEntity{ID: nestedID, Type: model.ProductTypeBundle}, nil)
+			mockRepo.On("FindProductByID", mock.Anything).Return(model.ProductEntity{}, errors.New("product not found"))
+			mockRepo.On("FindBundlesByComponentID", mock.Anything).Return([]model.ProductEntity{}, nil)
+
+			_, err := service.CreateProduct(tt.request)
+
+			assert.ErrorIs(t, err, ErrInvalidProduct)
+			mockRepo.AssertNotCalled(t, "InsertProduct", mock.Anything)
+		})
+	}
+}
+
+func TestProductServiceUpdateProductByID_KeepsStoredBundle(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	bundleID, componentID := uuid.New(), uuid.New()
+	mockRepo.On("FindProductByID", bundleID.String()).Return(model.ProductEntity{
+		ID: bundleID, Name: "Paket Hemat", Type: model.ProductTypeBundle, BundlePricing: model.BundlePricingFixed,
+		Components: []model.BundleComponentEntity{{BundleID: bundleID, ComponentID: componentID, Quantity: 2}},
+	}, nil)
+	mockRepo.On("FindProductByID", componentID.String()).Return(model.ProductEntity{ID: componentID, Name: "Nasi", Type: model.ProductTypeStandard}, nil)
+	mockRepo.On("FindBundlesByComponentID", bundleID.String()).Return([]model.ProductEntity{}, nil)
+	var updated model.ProductEntity
+	mockRepo.On("UpdateProductByID", bundleID.String(), mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(model.ProductEntity)
+	}).Return(model.ProductEntity{ID: bundleID}, nil)
+
+	_, err := service.UpdateProductByID(utils.EncodeBase62(bundleID.String()), model.UpdateProductRequest{Name: "Paket Super", Price: 25000, Version: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductTypeBundle, updated.Type, "a PUT without a type keeps the bundle a bundle")
+	assert.Equal(t, model.BundlePricingFixed, updated.BundlePricing)
+	if assert.Len(t, updated.Components, 1, "and keeps its components") {
+		assert.Equal(t, componentID, updated.Components[0].ComponentID)
+		assert.Equal(t, 2, updated.Components[0].Quantity)
+	}
+}
+
+func TestProductServiceUpdateProductByID_ComponentCannotBecomeBundle(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	nasiID, telurID, paketID := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("FindProductByID", telurID.String()).Return(model.ProductEntity{ID: telurID, Name: "Telur", Type: model.ProductTypeStandard}, nil)
+	mockRepo.On("FindBundlesByComponentID", nasiID.String()).Return([]model.ProductEntity{{ID: paketID, Name: "Paket Hemat", Type: model.ProductTypeBundle}}, nil)
+
+	_, err := service.UpdateProductByID(utils.EncodeBase62(nasiID.String()), model.UpdateProductRequest{
+		Name:          "Nasi Telur",
+		Type:          model.ProductTypeBundle,
+		BundlePricing: model.BundlePricingDerived,
+		Components:    []model.BundleComponentRequest{{ProductID: utils.EncodeBase62(telurID.String()), Quantity: 1}},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+	assert.ErrorContains(t, err, "component of bundle Paket Hemat")
+	mockRepo.AssertNotCalled(t, "UpdateProductByID", mock.Anything, mock.Anything)
+}
+
+func TestProductServiceFetchLowStockProducts(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	mockRepo.On("FindLowStockProducts").Return([]model.ProductEntity{
+		{ID: uuid.New(), Name: "Kopi", Stocks: 2, ReorderPoint: 5, ReorderQuantity: 24},
+	}, nil)
+
+	alerts, err := service.FetchLowStockProducts()
+
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "Kopi", alerts[0].Name)
+	assert.Equal(t, 24, alerts[0].ReorderQuantity)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProductServiceCreateProductNegativeReorderPoint(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	_, err := service.CreateProduct(model.CreateProductRequest{Name: "Kopi", Price: 1000, ReorderPoint: -1})
+
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+	mockRepo.AssertNotCalled(t, "InsertProduct", mock.Anything)
+}
+
+func TestProductServiceBatchProducts_BestEffort(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	kopiID, tehID := uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7())
+	mockRepo.On("FindProductByID", kopiID.String()).Return(model.ProductEntity{ID: kopiID, Name: "Kopi", Type: model.ProductTypeStandard}, nil)
+	mockRepo.On("ApplyProductBatch", mock.MatchedBy(func(ops []model.ProductBatchOperationEntity) bool {
+		return len(ops) == 2 && ops[0].Product.ID == kopiID && ops[0].Product.Price == 20000 && ops[1].Product.ID == tehID
+	}), false).Return([]model.ProductBatchResultEntity{
+		{Product: model.ProductEntity{ID: kopiID, Name: "Kopi", Price: 20000, Version: 4}},
+		{Error: "product not found"},
+	}, nil)
+
+	response, err := service.BatchProducts(model.ProductBatchRequest{
+		Mode: model.ProductBatchBestEffort,
+		Operations: []model.ProductBatchOperation{
+			{Op: model.ProductBatchUpdate, ID: utils.EncodeBase62(kopiID.String()), Product: []byte(`{"name":"Kopi","price":20000,"version":3}`)},
+			{Op: "rename"},
+			{Op: model.ProductBatchDelete, ID: utils.EncodeBase62(tehID.String())},
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, response.Committed)
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 2, response.Failed)
+	assert.Equal(t, model.ProductBatchSucceeded, response.Results[0].Status)
+	assert.Equal(t, int64(20000), response.Results[0].Product.Price)
+	assert.Equal(t, model.ProductBatchFailed, response.Results[1].Status)
+	assert.Contains(t, response.Results[1].Error, "op must be")
+	assert.Equal(t, "product not found", response.Results[2].Error)
+	assert.Equal(t, 2, response.Results[2].Index)
+}
+
+func TestProductServiceBatchProducts_Atomic(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	kopiID := uuid.Must(uuid.NewV7())
+	create := model.ProductBatchOperation{Op: model.ProductBatchCreate, Product: []byte(`{"name":"Teh","price":8000}`)}
+	remove := model.ProductBatchOperation{Op: model.ProductBatchDelete, ID: utils.EncodeBase62(kopiID.String())}
+
+	// an invalid operation fails the batch before the repository is called
+	response, err := service.BatchProducts(model.ProductBatchRequest{Operations: []model.ProductBatchOperation{
+		create, {Op: model.ProductBatchCreate, Product: []byte(`{"name":"Roti","price":1000,"reorder_point":-1}`)},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductBatchAtomic, response.Mode)
+	assert.False(t, response.Committed)
+	assert.Equal(t, model.ProductBatchSkipped, response.Results[0].Status)
+	assert.Equal(t, model.ProductBatchFailed, response.Results[1].Status)
+	mockRepo.AssertNotCalled(t, "ApplyProductBatch", mock.Anything, mock.Anything)
+
+	mockRepo.On("ApplyProductBatch", mock.Anything, true).Return([]model.ProductBatchResultEntity{
+		{Product: model.ProductEntity{Name: "Teh"}},
+		{Error: "product not found"},
+	}, nil)
+	response, err = service.BatchProducts(model.ProductBatchRequest{Operations: []model.ProductBatchOperation{create, remove}})
+	require.NoError(t, err)
+	assert.False(t, response.Committed)
+	assert.Equal(t, 0, response.Succeeded)
+	assert.Equal(t, model.ProductBatchSkipped, response.Results[0].Status)
+	assert.Nil(t, response.Results[0].Product, "a rolled back product is not reported")
+	assert.Equal(t, model.ProductBatchFailed, response.Results[1].Status)
+
+	_, err = service.BatchProducts(model.ProductBatchRequest{Mode: "sometimes", Operations: []model.ProductBatchOperation{create}})
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+	_, err = service.BatchProducts(model.ProductBatchRequest{})
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+}
+
+func TestProductServiceCreateProduct_InvalidQuantityScale(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	_, err := service.CreateProduct(model.CreateProductRequest{Name: "Beras", Price: 12500, Unit: "kg", QuantityScale: 4})
+	assert.ErrorIs(t, err, ErrInvalidProduct)
+	mockRepo.AssertNotCalled(t, "InsertProduct", mock.Anything)
+}
+
+func TestProductServiceReplaceProductUnits(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	service := NewProductService(mockRepo, new(mocks.MockCategoryRepository))
+
+	productID := uuid.New()
+	mockRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Mie", Unit: "pcs", Type: model.ProductTypeStandard}, nil)
+	mockRepo.On("ReplaceProductUnits", productID.String(), mock.MatchedBy(func(units []model.ProductUnitEntity) bool {
+		return len(units) == 1 && units[0].Name == "carton" && units[0].Factor == 24
+	})).Return([]model.ProductUnitEntity{{ID: uuid.New(), ProductID: productID, Name: "carton", Factor: 24}}, nil)
+
+	id := utils.EncodeBase62(productID.String())
+	units, err := service.ReplaceProductUnits(id, model.ReplaceProductUnitsRequest{Units: []model.ProductUnitRequest{{Name: "carton", Factor: 24}}})
+	require.NoError(t, err)
+	require.Len(t, units, 1)
+	assert.Equal(t, 24.0, units[0].Factor)
+
+	negative := int64(-1)
+	for _, invalid := range [][]model.ProductUnitRequest{
+		{{Name: "", Factor: 24}},
+		{{Name: "PCS", Factor: 1}},
+		{{Name: "carton", Factor: 24}, {Name: "Carton", Factor: 12}},
+		{{Name: "half", Factor: 0.5}},
+		{{Name: "carton", Factor: 24, Price: &negative}},
+	} {
+		_, err = service.ReplaceProductUnits(id, model.ReplaceProductUnitsRequest{Units: invalid})
+		assert.ErrorIs(t, err, ErrInvalidProduct)
+	}
+	mockRepo.AssertNumberOfCalls(t, "ReplaceProductUnits", 1)
+}
+
+func TestProductServiceFetchProductsByCategory(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewProductService(mockRepo, mockCategoryRepo)
+
+	beverages, coffee, snacks := uuid.New(), uuid.New(), uuid.New()
+	mockCategoryRepo.On("FindCategories").Return([]model.CategoryEntity{
+		{ID: beverages, Name: "Beverages"},
+		{ID: coffee, ParentID: &beverages, Name: "Coffee"},
+		{ID: snacks, Name: "Snacks"},
+	}, nil)
+	mockRepo.On("FindProductsByCategoryIDs", mock.MatchedBy(func(ids []string) bool {
+		return len(ids) == 2 && ids[0] == beverages.String() && ids[1] == coffee.String()
+	})).Return([]model.ProductEntity{{ID: uuid.New(), Name: "Espresso", CategoryID: &coffee}}, nil)
+
+	products, err := service.FetchProductsByCategory(utils.EncodeBase62(beverages.String()))
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "Espresso", products[0].Name)
+
+	_, err = service.FetchProductsByCategory(utils.EncodeBase62(uuid.New().String()))
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	mockRepo.AssertNumberOfCalls(t, "FindProductsByCategoryIDs", 1)
+}
+
+func TestProductServiceCreateProduct_Category(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewProductService(mockRepo, mockCategoryRepo)
+
+	drinks, deletedAt := model.CategoryEntity{ID: uuid.New(), Name: "Drinks"}, time.Now()
+	retired := model.CategoryEntity{ID: uuid.New(), Name: "Retired", DeletedAt: &deletedAt}
+	mockCategoryRepo.On("FindCategories").Return([]model.CategoryEntity{drinks, retired}, nil)
+	mockRepo.On("InsertProduct", mock.MatchedBy(func(p model.ProductEntity) bool {
+		return p.CategoryID != nil && *p.CategoryID == drinks.ID && p.CategoryName == "Drinks"
+	})).Return(model.ProductEntity{ID: uuid.New(), Name: "Kopi", CategoryID: &drinks.ID, CategoryName: "Drinks"}, nil)
+
+	// by id, the name sent alongside is ignored
+	product, err := service.CreateProduct(model.CreateProductRequest{Name: "Kopi", Price: 18000, CategoryID: utils.EncodeBase62(drinks.ID.String()), Category: "Food"})
+	require.NoError(t, err)
+	assert.Equal(t, utils.EncodeBase62(drinks.ID.String()), product.CategoryID)
+
+	// by name when no id is given, regardless of case
+	_, err = service.CreateProduct(model.CreateProductRequest{Name: "Kopi", Price: 18000, Category: "drinks"})
+	require.NoError(t, err)
+
+	_, err = service.CreateProduct(model.CreateProductRequest{Name: "Kopi", Price: 18000, Category: "Drinkz"})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	_, err = service.CreateProduct(model.CreateProductRequest{Name: "Kopi", Price: 18000, CategoryID: utils.EncodeBase62(retired.ID.String())})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	_, err = service.CreateProduct(model.CreateProductRequest{Name: "Kopi", Price: 18000, CategoryID: "not-an-id"})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	mockRepo.AssertNumberOfCalls(t, "InsertProduct", 2)
+}
+
+func TestProductServiceBatchProducts_UnknownCategory(t *testing.T) {
+	mockRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewProductService(mockRepo, mockCategoryRepo)
+
+	drinks := model.CategoryEntity{ID: uuid.New(), Name: "Drinks"}
+	mockCategoryRepo.On("FindCategories").Return([]model.CategoryEntity{drinks}, nil).Once()
+	mockRepo.On("ApplyProductBatch", mock.MatchedBy(func(ops []model.ProductBatchOperationEntity) bool {
+		return len(ops) == 1 && *ops[0].Product.CategoryID == drinks.ID
+	}), false).Return([]model.ProductBatchResultEntity{{Product: model.ProductEntity{Name: "Teh"}}}, nil)
+
+	response, err := service.BatchProducts(model.ProductBatchRequest{
+		Mode: model.ProductBatchBestEffort,
+		Operations: []model.ProductBatchOperation{
+			{Op: model.ProductBatchCreate, Product: []byte(`{"name":"Teh","price":8000,"category":"Drinks"}`)},
+			{Op: model.ProductBatchCreate, Product: []byte(`{"name":"Roti","price":1000,"category":"Bakery"}`)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.ProductBatchSucceeded, response.Results[0].Status)
+	assert.Equal(t, model.ProductBatchFailed, response.Results[1].Status)
+	assert.Contains(t, response.Results[1].Error, "Bakery")
+	mockCategoryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// PurchaseOrderService is the inbound side of inventory: draft an order, send it to the supplier,
+// then book one or more goods receipts until everything arrived or the order is closed short.
+type PurchaseOrderService interface {
+	FetchPurchaseOrders() ([]model.PurchaseOrder, error)
+	FetchPurchaseOrderByID(id string) (model.PurchaseOrder, error)
+	CreatePurchaseOrder(request model.CreatePurchaseOrderRequest) (model.PurchaseOrder, error)
+	UpdatePurchaseOrderByID(id string, request model.UpdatePurchaseOrderRequest) (model.PurchaseOrder, error)
+	SendPurchaseOrder(id string) (model.PurchaseOrder, error)
+	ReceiveGoods(id string, request model.ReceiveGoodsRequest) (model.PurchaseOrder, error)
+	ClosePurchaseOrder(id string) (model.PurchaseOrder, error)
+}
+
+type purchaseOrderService struct {
+	repository         repository.PurchaseOrderRepository
+	supplierRepository repository.SupplierRepository
+	productRepository  repository.ProductRepository
+	outletRepository   repository.OutletRepository
+}
+
+func NewPurchaseOrderService(repository repository.PurchaseOrderRepository, supplierRepository repository.SupplierRepository, productRepository repository.ProductRepository, outletRepository repository.OutletRepository) PurchaseOrderService {
+	return &purchaseOrderService{
+		repository:         repository,
+		supplierRepository: supplierRepository,
+		productRepository:  productRepository,
+		outletRepository:   outletRepository,
+	}
+}
+
+func (s *purchaseOrderService) FetchPurchaseOrders() ([]model.PurchaseOrder, error) {
+	entities, err := s.repository.FindPurchaseOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []model.PurchaseOrder{}
+	for _, entity := range entities {
+		orders = append(orders, *entity.ToModel())
+	}
+	return orders, nil
+}
+
+func (s *purchaseOrderService) FetchPurchaseOrderByID(id string) (model.PurchaseOrder, error) {
+	entity, err := s.repository.FindPurchaseOrderByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(request model.CreatePurchaseOrderRequest) (model.PurchaseOrder, error) {
+	order := *request.ToEntity()
+	if err := s.validatePurchaseOrder(order); err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	entity, err := s.repository.InsertPurchaseOrder(order)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *purchaseOrderService) UpdatePurchaseOrderByID(id string, request model.UpdatePurchaseOrderRequest) (model.PurchaseOrder, error) {
+	existing, err := s.findWithStatus(id, model.PurchaseOrderDraft)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	order := *request.ToEntity()
+	if err := s.validatePurchaseOrder(order); err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	entity, err := s.repository.UpdatePurchaseOrderByID(existing.ID.String(), order)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *purchaseOrderService) SendPurchaseOrder(id string) (model.PurchaseOrder, error) {
+	order, err := s.findWithStatus(id, model.PurchaseOrderDraft)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	entity, err := s.repository.UpdatePurchaseOrderStatus(order.ID.String(), model.PurchaseOrderDraft, model.PurchaseOrderSent, "USER")
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *purchaseOrderService) ReceiveGoods(id string, request model.ReceiveGoodsRequest) (model.PurchaseOrder, error) {
+	order, err := s.findWithStatus(id, model.PurchaseOrderSent, model.PurchaseOrderPartiallyReceived)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	if len(request.Items) == 0 {
+		return model.PurchaseOrder{}, fmt.Errorf("%w: at least one received item is required", ErrInvalidPurchaseOrder)
+	}
+	receipt := request.ToEntity(order.ID)
+	// goods received at an outlet land in that outlet's stock, which is what its checkout sells from
+	if receipt.OutletID != nil {
+		outlet, err := s.outletRepository.FindOutletByID(receipt.OutletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return model.PurchaseOrder{}, fmt.Errorf("%w: outlet not found", ErrInvalidPurchaseOrder)
+		}
+		if !outlet.IsActive {
+			return model.PurchaseOrder{}, fmt.Errorf("%w: outlet %s is not active", ErrInvalidPurchaseOrder, outlet.Code)
+		}
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, line := range receipt.Lines {
+		if line.Quantity <= 0 {
+			return model.PurchaseOrder{}, fmt.Errorf("%w: received quantity must be greater than zero", ErrInvalidPurchaseOrder)
+		}
+		if seen[line.ProductID] {
+			return model.PurchaseOrder{}, fmt.Errorf("%w: the same product cannot be received twice in one receipt", ErrInvalidPurchaseOrder)
+		}
+		seen[line.ProductID] = true
+
+		outstanding := -1
+		for _, ordered := range order.Lines {
+			if ordered.ProductID == line.ProductID {
+				outstanding = ordered.Outstanding()
+				break
+			}
+		}
+		if outstanding < 0 {
+			return model.PurchaseOrder{}, fmt.Errorf("%w: product is not on this purchase order", ErrInvalidPurchaseOrder)
+		}
+		if line.Quantity > outstanding {
+			return model.PurchaseOrder{}, fmt.Errorf("%w: received quantity exceeds the outstanding %d", ErrInvalidPurchaseOrder, outstanding)
+		}
+	}
+
+	entity, err := s.repository.ReceiveGoods(order.ID.String(), *receipt)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+// ClosePurchaseOrder closes an order short, whatever is still outstanding is no longer expected
+func (s *purchaseOrderService) ClosePurchaseOrder(id string) (model.PurchaseOrder, error) {
+	order, err := s.findWithStatus(id, model.PurchaseOrderSent, model.PurchaseOrderPartiallyReceived)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+
+	entity, err := s.repository.UpdatePurchaseOrderStatus(order.ID.String(), order.Status, model.PurchaseOrderClosed, "USER")
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+// validatePurchaseOrder checks the supplier and that every line orders a positive quantity of an existing standard product
+func (s *purchaseOrderService) validatePurchaseOrder(order model.PurchaseOrderEntity) error {
+	if order.SupplierID == uuid.Nil {
+		return fmt.Errorf("%w: invalid supplier id", ErrInvalidPurchaseOrder)
+	}
+	supplier, err := s.supplierRepository.FindSupplierByID(order.SupplierID.String())
+	if err != nil || supplier.DeletedAt != nil {
+		return fmt.Errorf("%w: supplier not found", ErrInvalidPurchaseOrder)
+	}
+
+	if len(order.Lines) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidPurchaseOrder)
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, line := range order.Lines {
+		if line.ProductID == uuid.Nil {
+			return fmt.Errorf("%w: invalid product id", ErrInvalidPurchaseOrder)
+		}
+		if line.QuantityOrdered <= 0 {
+			return fmt.Errorf("%w: quantity must be greater than zero", ErrInvalidPurchaseOrder)
+		}
+		if line.CostPrice < 0 {
+			return fmt.Errorf("%w: cost price cannot be negative", ErrInvalidPurchaseOrder)
+		}
+		if seen[line.ProductID] {
+			return fmt.Errorf("%w: the same product cannot be ordered twice", ErrInvalidPurchaseOrder)
+		}
+		seen[line.ProductID] = true
+
+		product, err := s.productRepository.FindProductByID(line.ProductID.String())
+		if err != nil || product.DeletedAt != nil {
+			return fmt.Errorf("%w: product not found", ErrInvalidPurchaseOrder)
+		}
+		if product.IsBundle() {
+			return fmt.Errorf("%w: bundles are not purchased, order their components instead", ErrInvalidPurchaseOrder)
+		}
+	}
+	return nil
+}
+
+// findWithStatus loads the order and fails with ErrPurchaseOrderStatus unless it is in one of the given statuses
+func (s *purchaseOrderService) findWithStatus(id string, statuses ...string) (model.PurchaseOrderEntity, error) {
+	order, err := s.repository.FindPurchaseOrderByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.PurchaseOrderEntity{}, err
+	}
+	for _, status := range statuses {
+		if order.Status == status {
+			return order, nil
+		}
+	}
+	return model.PurchaseOrderEntity{}, fmt.Errorf("%w: purchase order is %s", ErrPurchaseOrderStatus, order.Status)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPurchaseOrderServiceWithMocks() (PurchaseOrderService, *mocks.MockPurchaseOrderRepository, *mocks.MockSupplierRepository, *mocks.MockProductRepository) {
+	mockRepo := new(mocks.MockPurchaseOrderRepository)
+	mockSupplierRepo := new(mocks.MockSupplierRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	return NewPurchaseOrderService(mockRepo, mockSupplierRepo, mockProductRepo, new(mocks.MockOutletRepository)), mockRepo, mockSupplierRepo, mockProductRepo
+}
+
+func TestPurchaseOrderServiceCreatePurchaseOrder(t *testing.T) {
+	service, mockRepo, mockSupplierRepo, mockProductRepo := newPurchaseOrderServiceWithMocks()
+
+	supplierID, productID := uuid.New(), uuid.New()
+	mockSupplierRepo.On("FindSupplierByID", supplierID.String()).Return(model.SupplierEntity{ID: supplierID}, nil)
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Type: model.ProductTypeStandard}, nil)
+	mockRepo.On("InsertPurchaseOrder", mock.MatchedBy(func(o model.PurchaseOrderEntity) bool {
+		return o.Status == model.PurchaseOrderDraft && len(o.Lines) == 1 && o.Lines[0].CostPrice == 4000
+	})).Return(model.PurchaseOrderEntity{ID: uuid.New(), SupplierID: supplierID, Status: model.PurchaseOrderDraft}, nil)
+
+	order, err := service.CreatePurchaseOrder(model.CreatePurchaseOrderRequest{
+		SupplierID: utils.EncodeBase62(supplierID.String()),
+		Items:      []model.PurchaseOrderItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 12, CostPrice: 4000}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.PurchaseOrderDraft, order.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPurchaseOrderServiceCreatePurchaseOrder_Invalid(t *testing.T) {
+	service, mockRepo, mockSupplierRepo, mockProductRepo := newPurchaseOrderServiceWithMocks()
+
+	supplierID, productID, bundleID := uuid.New(), uuid.New(), uuid.New()
+	mockSupplierRepo.On("FindSupplierByID", supplierID.String()).Return(model.SupplierEntity{ID: supplierID}, nil)
+	mockSupplierRepo.On("FindSupplierByID", mock.Anything).Return(model.SupplierEntity{}, errors.New("supplier not found"))
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID}, nil)
+	mockProductRepo.On("FindProductByID", bundleID.String()).Return(model.ProductEntity{ID: bundleID, Type: model.ProductTypeBundle}, nil)
+
+	supplier := utils.EncodeBase62(supplierID.String())
+	product := utils.EncodeBase62(productID.String())
+	cases := map[string]model.CreatePurchaseOrderRequest{
+		"invalid supplier":  {SupplierID: "???", Items: []model.PurchaseOrderItemRequest{{ProductID: product, Quantity: 1}}},
+		"unknown supplier":  {SupplierID: utils.EncodeBase62(uuid.New().String()), Items: []model.PurchaseOrderItemRequest{{ProductID: product, Quantity: 1}}},
+		"no items":          {SupplierID: supplier},
+		"zero quantity":     {SupplierID: supplier, Items: []model.PurchaseOrderItemRequest{{ProductID: product}}},
+		"negative cost":     {SupplierID: supplier, Items: []model.PurchaseOrderItemRequest{{ProductID: product, Quantity: 1, CostPrice: -1}}},
+		"duplicate product": {SupplierID: supplier, Items: []model.PurchaseOrderItemRequest{{ProductID: product, Quantity: 1}, {ProductID: product, Quantity: 2}}},
+		"bundle":            {SupplierID: supplier, Items: []model.PurchaseOrderItemRequest{{ProductID: utils.EncodeBase62(bundleID.String()), Quantity: 1}}},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.CreatePurchaseOrder(req)
+			assert.ErrorIs(t, err, ErrInvalidPurchaseOrder)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "InsertPurchaseOrder", mock.Anything)
+}
+
+func TestPurchaseOrderServiceReceiveGoods(t *testing.T) {
+	service, mockRepo, _, _ := newPurchaseOrderServiceWithMocks()
+
+	orderID, productID := uuid.New(), uuid.New()
+	order := model.PurchaseOrderEntity{ID: orderID, Status: model.PurchaseOrderSent, Lines: []model.PurchaseOrderLineEntity{
+		{ProductID: productID, QuantityOrdered: 10, QuantityReceived: 4},
+	}}
+	mockRepo.On("FindPurchaseOrderByID", orderID.String()).Return(order, nil)
+	mockRepo.On("ReceiveGoods", orderID.String(), mock.MatchedBy(func(g model.GoodsReceiptEntity) bool {
+		return g.PurchaseOrderID == orderID && len(g.Lines) == 1 && g.Lines[0].Quantity == 6
+	})).Return(model.PurchaseOrderEntity{ID: orderID, Status: model.PurchaseOrderClosed}, nil)
+
+	id := utils.EncodeBase62(orderID.String())
+	item := func(quantity int) model.ReceiveGoodsRequest {
+		return model.ReceiveGoodsRequest{Items: []model.GoodsReceiptItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: quantity}}}
+	}
+
+	_, err := service.ReceiveGoods(id, item(7))
+	assert.ErrorIs(t, err, ErrInvalidPurchaseOrder)
+
+	_, err = service.ReceiveGoods(id, model.ReceiveGoodsRequest{Items: []model.GoodsReceiptItemRequest{{ProductID: utils.EncodeBase62(uuid.New().String()), Quantity: 1}}})
+	assert.ErrorIs(t, err, ErrInvalidPurchaseOrder)
+
+	result, err := service.ReceiveGoods(id, item(6))
+	require.NoError(t, err)
+	assert.Equal(t, model.PurchaseOrderClosed, result.Status)
+	mockRepo.AssertNumberOfCalls(t, "ReceiveGoods", 1)
+}
+
+func TestPurchaseOrderServiceReceiveGoods_AtOutlet(t *testing.T) {
+	mockRepo := new(mocks.MockPurchaseOrderRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	service := NewPurchaseOrderService(mockRepo, new(mocks.MockSupplierRepository), new(mocks.MockProductRepository), mockOutletRepo)
+
+	orderID, productID, outletID, closedID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	order := model.PurchaseOrderEntity{ID: orderID, Status: model.PurchaseOrderSent, Lines: []model.PurchaseOrderLineEntity{
+		{ProductID: productID, QuantityOrdered: 10},
+	}}
+	mockRepo.On("FindPurchaseOrderByID", orderID.String()).Return(order, nil)
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, Code: "JKT", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", closedID.String()).Return(model.OutletEntity{ID: closedID, Code: "BDG"}, nil)
+	mockOutletRepo.On("FindOutletByID", mock.Anything).Return(model.OutletEntity{}, errors.New("outlet not found"))
+	mockRepo.On("ReceiveGoods", orderID.String(), mock.MatchedBy(func(g model.GoodsReceiptEntity) bool {
+		return g.OutletID != nil && *g.OutletID == outletID
+	})).Return(model.PurchaseOrderEntity{ID: orderID, Status: model.PurchaseOrderClosed}, nil)
+
+	id := utils.EncodeBase62(orderID.String())
+	at := func(outlet string) model.ReceiveGoodsRequest {
+		return model.ReceiveGoodsRequest{OutletID: outlet, Items: []model.GoodsReceiptItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 10}}}
+	}
+
+	_, err := service.ReceiveGoods(id, at(utils.EncodeBase62(closedID.String())))
+	assert.ErrorIs(t, err, ErrInvalidPurchaseOrder)
+
+	_, err = service.ReceiveGoods(id, at(utils.EncodeBase62(uuid.New().String())))
+	assert.ErrorIs(t, err, ErrInvalidPurchaseOrder)
+
+	_, err = service.ReceiveGoods(id, at(utils.EncodeBase62(outletID.String())))
+	require.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "ReceiveGoods", 1)
+}
+
+func TestPurchaseOrderServiceStatusConflicts(t *testing.T) {
+	service, mockRepo, _, _ := newPurchaseOrderServiceWithMocks()
+
+	draftID, closedID := uuid.New(), uuid.New()
+	mockRepo.On("FindPurchaseOrderByID", draftID.String()).Return(model.PurchaseOrderEntity{ID: draftID, Status: model.PurchaseOrderDraft}, nil)
+	mockRepo.On("FindPurchaseOrderByID", closedID.String()).Return(model.PurchaseOrderEntity{ID: closedID, Status: model.PurchaseOrderClosed}, nil)
+	mockRepo.On("UpdatePurchaseOrderStatus", draftID.String(), model.PurchaseOrderDraft, model.PurchaseOrderSent, "USER").
+		Return(model.PurchaseOrderEntity{ID: draftID, Status: model.PurchaseOrderSent}, nil)
+
+	draft := utils.EncodeBase62(draftID.String())
+	closed := utils.EncodeBase62(closedID.String())
+
+	_, err := service.ReceiveGoods(draft, model.ReceiveGoodsRequest{})
+	assert.ErrorIs(t, err, ErrPurchaseOrderStatus)
+	_, err = service.ClosePurchaseOrder(draft)
+	assert.ErrorIs(t, err, ErrPurchaseOrderStatus)
+	_, err = service.SendPurchaseOrder(closed)
+	assert.ErrorIs(t, err, ErrPurchaseOrderStatus)
+	_, err = service.UpdatePurchaseOrderByID(closed, model.UpdatePurchaseOrderRequest{})
+	assert.ErrorIs(t, err, ErrPurchaseOrderStatus)
+
+	sent, err := service.SendPurchaseOrder(draft)
+	require.NoError(t, err)
+	assert.Equal(t, model.PurchaseOrderSent, sent.Status)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// ShiftService runs the cashiers' drawers: a shift opens with a float, takes petty cash in and out,
+// rings up sales through TransactionService and closes with the supervisor's cash count.
+type ShiftService interface {
+	// FetchShifts takes an optional status, open or closed
+	FetchShifts(status string) ([]model.Shift, error)
+	FetchShiftByID(id string) (model.Shift, error)
+	OpenShift(request model.OpenShiftRequest) (model.Shift, error)
+	CreateCashEntry(id string, request model.CreateCashEntryRequest) (model.CashEntry, error)
+	CloseShift(id string, request model.CloseShiftRequest) (model.Shift, error)
+	// FetchShiftReport is the X report of an open shift or the Z report of a closed one
+	FetchShiftReport(id string) (model.ShiftReport, error)
+}
+
+type shiftService struct {
+	repository       repository.ShiftRepository
+	outletRepository repository.OutletRepository
+	txRepository     repository.TransactionRepository
+}
+
+func NewShiftService(repository repository.ShiftRepository, outletRepository repository.OutletRepository, txRepository repository.TransactionRepository) ShiftService {
+	return &shiftService{
+		repository:       repository,
+		outletRepository: outletRepository,
+		txRepository:     txRepository,
+	}
+}
+
+func (s *shiftService) FetchShifts(status string) ([]model.Shift, error) {
+	if status != "" && status != model.ShiftOpen && status != model.ShiftClosed {
+		return nil, fmt.Errorf("%w: status must be %s or %s", ErrInvalidShift, model.ShiftOpen, model.ShiftClosed)
+	}
+	entities, err := s.repository.FindShifts(status)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := []model.Shift{}
+	for _, entity := range entities {
+		shifts = append(shifts, *entity.ToModel())
+	}
+	return shifts, nil
+}
+
+func (s *shiftService) FetchShiftByID(id string) (model.Shift, error) {
+	entity, err := s.repository.FindShiftByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.Shift{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *shiftService) OpenShift(request model.OpenShiftRequest) (model.Shift, error) {
+	shift := *request.ToEntity()
+	if shift.CashierName == "" {
+		return model.Shift{}, fmt.Errorf("%w: cashier name is required", ErrInvalidShift)
+	}
+	if shift.OpeningFloat < 0 {
+		return model.Shift{}, fmt.Errorf("%w: opening float cannot be negative", ErrInvalidShift)
+	}
+	if shift.OutletID != nil {
+		outlet, err := s.outletRepository.FindOutletByID(shift.OutletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return model.Shift{}, fmt.Errorf("%w: outlet not found", ErrInvalidShift)
+		}
+		if !outlet.IsActive {
+			return model.Shift{}, fmt.Errorf("%w: outlet %s is not active", ErrInvalidShift, outlet.Code)
+		}
+	}
+
+	open, err := s.repository.FindOpenShiftByCashier(shift.CashierName)
+	if err != nil {
+		return model.Shift{}, err
+	}
+	if open.ID != uuid.Nil {
+		return model.Shift{}, fmt.Errorf("%w: %s already has an open shift", ErrShiftStatus, shift.CashierName)
+	}
+
+	entity, err := s.repository.InsertShift(shift)
+	if err != nil {
+		return model.Shift{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *shiftService) CreateCashEntry(id string, request model.CreateCashEntryRequest) (model.CashEntry, error) {
+	shift, err := s.findOpenShift(id)
+	if err != nil {
+		return model.CashEntry{}, err
+	}
+	entry := *request.ToEntity(shift.ID)
+	if entry.Type != model.CashEntryIn && entry.Type != model.CashEntryOut {
+		return model.CashEntry{}, fmt.Errorf("%w: type must be %s or %s", ErrInvalidShift, model.CashEntryIn, model.CashEntryOut)
+	}
+	if entry.Amount <= 0 {
+		return model.CashEntry{}, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidShift)
+	}
+	if entry.Reason == "" {
+		return model.CashEntry{}, fmt.Errorf("%w: reason is required", ErrInvalidShift)
+	}
+
+	inserted, err := s.repository.InsertCashEntry(entry)
+	if err != nil {
+		return model.CashEntry{}, err
+	}
+	return *inserted.ToModel(), nil
+}
+
+func (s *shiftService) CloseShift(id string, request model.CloseShiftRequest) (model.Shift, error) {
+	if request.CountedCash == nil {
+		return model.Shift{}, fmt.Errorf("%w: counted cash is required", ErrInvalidShift)
+	}
+	if *request.CountedCash < 0 {
+		return model.Shift{}, fmt.Errorf("%w: counted cash cannot be negative", ErrInvalidShift)
+	}
+	shift, err := s.findOpenShift(id)
+	if err != nil {
+		return model.Shift{}, err
+	}
+
+	entity, err := s.repository.CloseShift(shift.ID.String(), *request.CountedCash, request.Notes, "USER")
+	if err != nil {
+		return model.Shift{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *shiftService) FetchShiftReport(id string) (model.ShiftReport, error) {
+	shift, err := s.repository.FindShiftByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.ShiftReport{}, err
+	}
+	sales, err := s.txRepository.GetShiftSales(shift.ID.String())
+	if err != nil {
+		return model.ShiftReport{}, err
+	}
+	return *model.NewShiftReport(shift, sales), nil
+}
+
+// findOpenShift loads the shift and fails with ErrShiftStatus unless it is open
+func (s *shiftService) findOpenShift(id string) (model.ShiftEntity, error) {
+	shift, err := s.repository.FindShiftByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.ShiftEntity{}, err
+	}
+	if shift.Status != model.ShiftOpen {
+		return model.ShiftEntity{}, fmt.Errorf("%w: shift is %s", ErrShiftStatus, shift.Status)
+	}
+	return shift, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftServiceOpenShift(t *testing.T) {
+	mockRepo := new(mocks.MockShiftRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	service := NewShiftService(mockRepo, mockOutletRepo, new(mocks.MockTransactionRepository))
+
+	outletID, closedOutletID, unknownID := uuid.New(), uuid.New(), uuid.New()
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, Code: "JKT", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", closedOutletID.String()).Return(model.OutletEntity{ID: closedOutletID, Code: "BDG"}, nil)
+	mockOutletRepo.On("FindOutletByID", unknownID.String()).Return(model.OutletEntity{}, errors.New("outlet not found"))
+	mockRepo.On("FindOpenShiftByCashier", "Sari").Return(model.ShiftEntity{ID: uuid.New(), CashierName: "Sari", Status: model.ShiftOpen}, nil)
+	mockRepo.On("FindOpenShiftByCashier", "Dewi").Return(model.ShiftEntity{}, nil)
+	mockRepo.On("InsertShift", mock.MatchedBy(func(s model.ShiftEntity) bool {
+		return s.CashierName == "Dewi" && s.Status == model.ShiftOpen && *s.OutletID == outletID
+	})).Return(model.ShiftEntity{ID: uuid.New(), CashierName: "Dewi", Status: model.ShiftOpen, OpeningFloat: 200000}, nil)
+
+	for _, request := range []model.OpenShiftRequest{
+		{CashierName: "  "},
+		{CashierName: "Dewi", OpeningFloat: -1},
+		{CashierName: "Dewi", OutletID: utils.EncodeBase62(unknownID.String())},
+		{CashierName: "Dewi", OutletID: utils.EncodeBase62(closedOutletID.String())},
+	} {
+		_, err := service.OpenShift(request)
+		assert.ErrorIs(t, err, ErrInvalidShift)
+	}
+	_, err := service.OpenShift(model.OpenShiftRequest{CashierName: "Sari"})
+	assert.ErrorIs(t, err, ErrShiftStatus, "one drawer per cashier")
+
+	shift, err := service.OpenShift(model.OpenShiftRequest{CashierName: " Dewi ", OutletID: utils.EncodeBase62(outletID.String()), OpeningFloat: 200000})
+	require.NoError(t, err)
+	assert.Equal(t, model.ShiftOpen, shift.Status)
+}
+
+func TestShiftServiceCreateCashEntry(t *testing.T) {
+	mockRepo := new(mocks.MockShiftRepository)
+	service := NewShiftService(mockRepo, new(mocks.MockOutletRepository), new(mocks.MockTransactionRepository))
+
+	openID, closedID := uuid.New(), uuid.New()
+	mockRepo.On("FindShiftByID", openID.String()).Return(model.ShiftEntity{ID: openID, Status: model.ShiftOpen}, nil)
+	mockRepo.On("FindShiftByID", closedID.String()).Return(model.ShiftEntity{ID: closedID, Status: model.ShiftClosed}, nil)
+	mockRepo.On("InsertCashEntry", mock.MatchedBy(func(e model.CashEntryEntity) bool {
+		return e.ShiftID == openID && e.Type == model.CashEntryOut && e.Amount == 15000
+	})).Return(model.CashEntryEntity{ID: uuid.New(), ShiftID: openID, Type: model.CashEntryOut, Amount: 15000, Reason: "ice"}, nil)
+
+	open, closed := utils.EncodeBase62(openID.String()), utils.EncodeBase62(closedID.String())
+	for _, request := range []model.CreateCashEntryRequest{
+		{Type: "refund", Amount: 15000, Reason: "ice"},
+		{Type: model.CashEntryOut, Amount: 0, Reason: "ice"},
+		{Type: model.CashEntryOut, Amount: 15000},
+	} {
+		_, err := service.CreateCashEntry(open, request)
+		assert.ErrorIs(t, err, ErrInvalidShift)
+	}
+	_, err := service.CreateCashEntry(closed, model.CreateCashEntryRequest{Type: model.CashEntryOut, Amount: 15000, Reason: "ice"})
+	assert.ErrorIs(t, err, ErrShiftStatus)
+
+	entry, err := service.CreateCashEntry(open, model.CreateCashEntryRequest{Type: model.CashEntryOut, Amount: 15000, Reason: "ice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(15000), entry.Amount.Amount)
+}
+
+func TestShiftServiceCloseShift(t *testing.T) {
+	mockRepo := new(mocks.MockShiftRepository)
+	service := NewShiftService(mockRepo, new(mocks.MockOutletRepository), new(mocks.MockTransactionRepository))
+
+	shiftID := uuid.New()
+	expected, counted := int64(235000), int64(234000)
+	mockRepo.On("FindShiftByID", shiftID.String()).Return(model.ShiftEntity{ID: shiftID, Status: model.ShiftOpen}, nil)
+	mockRepo.On("CloseShift", shiftID.String(), counted, "short by 1000", "USER").
+		Return(model.ShiftEntity{ID: shiftID, Status: model.ShiftClosed, ExpectedCash: &expected, CountedCash: &counted}, nil)
+
+	id := utils.EncodeBase62(shiftID.String())
+	_, err := service.CloseShift(id, model.CloseShiftRequest{})
+	assert.ErrorIs(t, err, ErrInvalidShift, "the count is required")
+	negative := int64(-1)
+	_, err = service.CloseShift(id, model.CloseShiftRequest{CountedCash: &negative})
+	assert.ErrorIs(t, err, ErrInvalidShift)
+
+	shift, err := service.CloseShift(id, model.CloseShiftRequest{CountedCash: &counted, Notes: "short by 1000"})
+	require.NoError(t, err)
+	assert.Equal(t, model.ShiftClosed, shift.Status)
+	assert.Equal(t, counted, shift.CountedCash.Amount)
+}
+
+func TestShiftServiceFetchShiftReport(t *testing.T) {
+	mockRepo := new(mocks.MockShiftRepository)
+	mockTxRepo := new(mocks.MockTransactionRepository)
+	service := NewShiftService(mockRepo, new(mocks.MockOutletRepository), mockTxRepo)
+
+	shiftID := uuid.New()
+	mockRepo.On("FindShiftByID", shiftID.String()).Return(model.ShiftEntity{
+		ID: shiftID, Status: model.ShiftOpen, OpeningFloat: 200000,
+		CashEntries: []model.CashEntryEntity{{Type: model.CashEntryIn, Amount: 50000}, {Type: model.CashEntryOut, Amount: 15000}},
+	}, nil)
+	mockTxRepo.On("GetShiftSales", shiftID.String()).Return(model.ShiftSalesEntity{
+		TransactionCount: 3, ItemsSold: 7, NetSales: 120000, PointsDiscount: 5000, GiftCardAmount: 20000,
+	}, nil)
+
+	report, err := service.FetchShiftReport(utils.EncodeBase62(shiftID.String()))
+
+	require.NoError(t, err)
+	assert.Equal(t, model.ShiftReportX, report.Kind)
+	assert.Equal(t, int64(125000), report.GrossSales.Amount)
+	assert.Equal(t, int64(100000), report.CashSales.Amount)
+	assert.Equal(t, int64(335000), report.ExpectedCash.Amount)
+	assert.Nil(t, report.Variance)
+}
+
+func TestShiftServiceFetchShifts_InvalidStatus(t *testing.T) {
+	service := NewShiftService(new(mocks.MockShiftRepository), new(mocks.MockOutletRepository), new(mocks.MockTransactionRepository))
+
+	_, err := service.FetchShifts("pending")
+
+	assert.ErrorIs(t, err, ErrInvalidShift)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// StockCountService runs a stock-take (stock opname): open, count, submit, then approve to post the variances.
+type StockCountService interface {
+	FetchStockCounts() ([]model.StockCount, error)
+	FetchStockCountByID(id string) (model.StockCount, error)
+	OpenStockCount(request model.CreateStockCountRequest) (model.StockCount, error)
+	RecordCounts(id string, request model.SubmitStockCountRequest) (model.StockCount, error)
+	FetchVarianceReport(id string) (model.StockCountVarianceReport, error)
+	SubmitStockCount(id string) (model.StockCount, error)
+	ApproveStockCount(id string) (model.StockCount, error)
+	CancelStockCount(id string) (model.StockCount, error)
+}
+
+type stockCountService struct {
+	repository         repository.StockCountRepository
+	categoryRepository repository.CategoryRepository
+	outletRepository   repository.OutletRepository
+}
+
+func NewStockCountService(repository repository.StockCountRepository, categoryRepository repository.CategoryRepository, outletRepository repository.OutletRepository) StockCountService {
+	return &stockCountService{
+		repository:         repository,
+		categoryRepository: categoryRepository,
+		outletRepository:   outletRepository,
+	}
+}
+
+func (s *stockCountService) FetchStockCounts() ([]model.StockCount, error) {
+	entities, err := s.repository.FindStockCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []model.StockCount{}
+	for _, entity := range entities {
+		counts = append(counts, *entity.ToModel())
+	}
+	return counts, nil
+}
+
+func (s *stockCountService) FetchStockCountByID(id string) (model.StockCount, error) {
+	entity, err := s.repository.FindStockCountByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.StockCount{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockCountService) OpenStockCount(request model.CreateStockCountRequest) (model.StockCount, error) {
+	count := request.ToEntity()
+	if count.CategoryID != nil {
+		if *count.CategoryID == uuid.Nil {
+			return model.StockCount{}, fmt.Errorf("%w: invalid category id", ErrInvalidStockCount)
+		}
+		if _, err := s.categoryRepository.FindCategoryByID(count.CategoryID.String()); err != nil {
+			return model.StockCount{}, fmt.Errorf("%w: category not found", ErrInvalidStockCount)
+		}
+	}
+	// a count at an outlet is checked against and posted to that outlet's stock
+	if count.OutletID != nil {
+		outlet, err := s.outletRepository.FindOutletByID(count.OutletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return model.StockCount{}, fmt.Errorf("%w: outlet not found", ErrInvalidStockCount)
+		}
+		if !outlet.IsActive {
+			return model.StockCount{}, fmt.Errorf("%w: outlet %s is not active", ErrInvalidStockCount, outlet.Code)
+		}
+	}
+
+	entity, err := s.repository.InsertStockCount(*count)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockCountService) RecordCounts(id string, request model.SubmitStockCountRequest) (model.StockCount, error) {
+	count, err := s.findWithStatus(id, model.StockCountOpen)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+
+	if len(request.Items) == 0 {
+		return model.StockCount{}, fmt.Errorf("%w: at least one counted item is required", ErrInvalidStockCount)
+	}
+	lines := request.ToEntities(count.ID)
+	for _, line := range lines {
+		if line.ProductID == uuid.Nil {
+			return model.StockCount{}, fmt.Errorf("%w: invalid product id", ErrInvalidStockCount)
+		}
+		if *line.CountedQuantity < 0 {
+			return model.StockCount{}, fmt.Errorf("%w: counted quantity cannot be negative", ErrInvalidStockCount)
+		}
+	}
+
+	entity, err := s.repository.UpdateStockCountLines(count.ID.String(), lines)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockCountService) FetchVarianceReport(id string) (model.StockCountVarianceReport, error) {
+	entity, err := s.repository.FindStockCountByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.StockCountVarianceReport{}, err
+	}
+	return *entity.ToVarianceReport(), nil
+}
+
+func (s *stockCountService) SubmitStockCount(id string) (model.StockCount, error) {
+	count, err := s.findWithStatus(id, model.StockCountOpen)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+
+	counted := false
+	for _, line := range count.Lines {
+		if line.CountedQuantity != nil {
+			counted = true
+			break
+		}
+	}
+	if !counted {
+		return model.StockCount{}, fmt.Errorf("%w: nothing has been counted yet", ErrInvalidStockCount)
+	}
+
+	entity, err := s.repository.UpdateStockCountStatus(count.ID.String(), model.StockCountOpen, model.StockCountSubmitted, "USER")
+	if err != nil {
+		return model.StockCount{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockCountService) ApproveStockCount(id string) (model.StockCount, error) {
+	count, err := s.findWithStatus(id, model.StockCountSubmitted)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+
+	entity, err := s.repository.PostStockCount(count.ID.String(), "USER")
+	if err != nil {
+		return model.StockCount{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockCountService) CancelStockCount(id string) (model.StockCount, error) {
+	count, err := s.findWithStatus(id, model.StockCountOpen, model.StockCountSubmitted)
+	if err != nil {
+		return model.StockCount{}, err
+	}
+
+	entity, err := s.repository.UpdateStockCountStatus(count.ID.String(), count.Status, model.StockCountCancelled, "USER")
+	if err != nil {
+		return model.StockCount{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+// findWithStatus loads the count and fails with ErrStockCountStatus unless it is in one of the given statuses
+func (s *stockCountService) findWithStatus(id string, statuses ...string) (model.StockCountEntity, error) {
+	count, err := s.repository.FindStockCountByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.StockCountEntity{}, err
+	}
+	for _, status := range statuses {
+		if count.Status == status {
+			return count, nil
+		}
+	}
+	return model.StockCountEntity{}, fmt.Errorf("%w: stock count is %s", ErrStockCountStatus, count.Status)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockCountServiceOpenStockCount(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewStockCountService(mockRepo, mockCategoryRepo, new(mocks.MockOutletRepository))
+
+	categoryID := uuid.New()
+	mockCategoryRepo.On("FindCategoryByID", categoryID.String()).Return(model.CategoryEntity{ID: categoryID}, nil)
+	mockRepo.On("InsertStockCount", mock.MatchedBy(func(c model.StockCountEntity) bool {
+		return c.Status == model.StockCountOpen && *c.CategoryID == categoryID
+	})).Return(model.StockCountEntity{ID: uuid.New(), Status: model.StockCountOpen, CategoryID: &categoryID}, nil)
+
+	count, err := service.OpenStockCount(model.CreateStockCountRequest{CategoryID: utils.EncodeBase62(categoryID.String())})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.StockCountOpen, count.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStockCountServiceOpenStockCount_UnknownCategory(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	service := NewStockCountService(mockRepo, mockCategoryRepo, new(mocks.MockOutletRepository))
+
+	mockCategoryRepo.On("FindCategoryByID", mock.Anything).Return(model.CategoryEntity{}, errors.New("category not found"))
+
+	_, err := service.OpenStockCount(model.CreateStockCountRequest{CategoryID: utils.EncodeBase62(uuid.New().String())})
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+
+	_, err = service.OpenStockCount(model.CreateStockCountRequest{CategoryID: "???"})
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+	mockRepo.AssertNotCalled(t, "InsertStockCount", mock.Anything)
+}
+
+func TestStockCountServiceOpenStockCount_AtOutlet(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	service := NewStockCountService(mockRepo, new(mocks.MockCategoryRepository), mockOutletRepo)
+
+	outletID, closedID := uuid.New(), uuid.New()
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, Code: "JKT", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", closedID.String()).Return(model.OutletEntity{ID: closedID, Code: "BDG"}, nil)
+	mockOutletRepo.On("FindOutletByID", mock.Anything).Return(model.OutletEntity{}, errors.New("outlet not found"))
+	mockRepo.On("InsertStockCount", mock.MatchedBy(func(c model.StockCountEntity) bool {
+		return c.OutletID != nil && *c.OutletID == outletID
+	})).Return(model.StockCountEntity{ID: uuid.New(), Status: model.StockCountOpen, OutletID: &outletID, OutletName: "Jakarta"}, nil)
+
+	_, err := service.OpenStockCount(model.CreateStockCountRequest{OutletID: utils.EncodeBase62(closedID.String())})
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+
+	_, err = service.OpenStockCount(model.CreateStockCountRequest{OutletID: "???"})
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+
+	count, err := service.OpenStockCount(model.CreateStockCountRequest{OutletID: utils.EncodeBase62(outletID.String())})
+	require.NoError(t, err)
+	assert.Equal(t, utils.EncodeBase62(outletID.String()), count.OutletID)
+	assert.Equal(t, "Jakarta", count.Outlet)
+	mockRepo.AssertNumberOfCalls(t, "InsertStockCount", 1)
+}
+
+func TestStockCountServiceRecordCounts(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	service := NewStockCountService(mockRepo, new(mocks.MockCategoryRepository), new(mocks.MockOutletRepository))
+
+	countID, productID := uuid.New(), uuid.New()
+	mockRepo.On("FindStockCountByID", countID.String()).Return(model.StockCountEntity{ID: countID, Status: model.StockCountOpen}, nil)
+	mockRepo.On("UpdateStockCountLines", countID.String(), mock.Anything).Return(model.StockCountEntity{ID: countID, Status: model.StockCountOpen}, nil)
+
+	id := utils.EncodeBase62(countID.String())
+	_, err := service.RecordCounts(id, model.SubmitStockCountRequest{Items: []model.StockCountItemRequest{
+		{ProductID: utils.EncodeBase62(productID.String()), CountedQuantity: 5},
+	}})
+	require.NoError(t, err)
+
+	_, err = service.RecordCounts(id, model.SubmitStockCountRequest{})
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+
+	_, err = service.RecordCounts(id, model.SubmitStockCountRequest{Items: []model.StockCountItemRequest{
+		{ProductID: utils.EncodeBase62(productID.String()), CountedQuantity: -1},
+	}})
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+	mockRepo.AssertNumberOfCalls(t, "UpdateStockCountLines", 1)
+}
+
+func TestStockCountServiceSubmitAndApprove(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	service := NewStockCountService(mockRepo, new(mocks.MockCategoryRepository), new(mocks.MockOutletRepository))
+
+	countID := uuid.New()
+	counted := 3
+	id := utils.EncodeBase62(countID.String())
+
+	mockRepo.On("FindStockCountByID", countID.String()).Return(model.StockCountEntity{
+		ID: countID, Status: model.StockCountOpen,
+		Lines: []model.StockCountLineEntity{{ProductID: uuid.New(), CountedQuantity: &counted}},
+	}, nil).Once()
+	mockRepo.On("UpdateStockCountStatus", countID.String(), model.StockCountOpen, model.StockCountSubmitted, "USER").
+		Return(model.StockCountEntity{ID: countID, Status: model.StockCountSubmitted}, nil)
+
+	count, err := service.SubmitStockCount(id)
+	require.NoError(t, err)
+	assert.Equal(t, model.StockCountSubmitted, count.Status)
+
+	mockRepo.On("FindStockCountByID", countID.String()).Return(model.StockCountEntity{ID: countID, Status: model.StockCountSubmitted}, nil)
+	mockRepo.On("PostStockCount", countID.String(), "USER").Return(model.StockCountEntity{ID: countID, Status: model.StockCountPosted}, nil)
+
+	count, err = service.ApproveStockCount(id)
+	require.NoError(t, err)
+	assert.Equal(t, model.StockCountPosted, count.Status)
+
+	_, err = service.SubmitStockCount(id)
+	assert.ErrorIs(t, err, ErrStockCountStatus)
+}
+
+func TestStockCountServiceSubmitStockCount_NothingCounted(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	service := NewStockCountService(mockRepo, new(mocks.MockCategoryRepository), new(mocks.MockOutletRepository))
+
+	countID := uuid.New()
+	mockRepo.On("FindStockCountByID", countID.String()).Return(model.StockCountEntity{
+		ID: countID, Status: model.StockCountOpen, Lines: []model.StockCountLineEntity{{ProductID: uuid.New()}},
+	}, nil)
+
+	_, err := service.SubmitStockCount(utils.EncodeBase62(countID.String()))
+	assert.ErrorIs(t, err, ErrInvalidStockCount)
+}
+
+func TestStockCountServiceCancelStockCount(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	service := NewStockCountService(mockRepo, new(mocks.MockCategoryRepository), new(mocks.MockOutletRepository))
+
+	countID := uuid.New()
+	mockRepo.On("FindStockCountByID", countID.String()).Return(model.StockCountEntity{ID: countID, Status: model.StockCountSubmitted}, nil)
+	mockRepo.On("UpdateStockCountStatus", countID.String(), model.StockCountSubmitted, model.StockCountCancelled, "USER").
+		Return(model.StockCountEntity{ID: countID, Status: model.StockCountCancelled}, nil)
+
+	count, err := service.CancelStockCount(utils.EncodeBase62(countID.String()))
+
+	require.NoError(t, err)
+	assert.Equal(t, model.StockCountCancelled, count.Status)
+}
+
+func TestStockCountServiceFetchVarianceReport(t *testing.T) {
+	mockRepo := new(mocks.MockStockCountRepository)
+	service := NewStockCountService(mockRepo, new(mocks.MockCategoryRepository), new(mocks.MockOutletRepository))
+
+	countID := uuid.New()
+	counted := 8
+	mockRepo.On("FindStockCountByID", countID.String()).Return(model.StockCountEntity{
+		ID: countID, Status: model.StockCountOpen,
+		Lines: []model.StockCountLineEntity{{ProductID: uuid.New(), ProductPrice: 1000, SystemStock: 10, CountedQuantity: &counted}},
+	}, nil)
+
+	report, err := service.FetchVarianceReport(utils.EncodeBase62(countID.String()))
+
+	require.NoError(t, err)
+	assert.Equal(t, -2, report.TotalVarianceQty)
+	assert.Equal(t, int64(-2000), report.TotalVarianceValue)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+type StockMovementService interface {
+	FetchStockMovements(productID string) ([]model.StockMovement, error)
+	CreateStockMovement(productID string, request model.CreateStockMovementRequest) (model.StockMovement, error)
+	FetchStockReconciliation(productID string) (model.StockReconciliation, error)
+}
+
+type stockMovementService struct {
+	repository        repository.StockMovementRepository
+	productRepository repository.ProductRepository
+}
+
+func NewStockMovementService(repository repository.StockMovementRepository, productRepository repository.ProductRepository) StockMovementService {
+	return &stockMovementService{
+		repository:        repository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *stockMovementService) FetchStockMovements(productID string) ([]model.StockMovement, error) {
+	entities, err := s.repository.FindStockMovementsByProductID(utils.DecodeBase62(productID))
+	if err != nil {
+		return nil, err
+	}
+
+	movements := []model.StockMovement{}
+	for _, entity := range entities {
+		movements = append(movements, *entity.ToModel())
+	}
+	return movements, nil
+}
+
+func (s *stockMovementService) CreateStockMovement(productID string, request model.CreateStockMovementRequest) (model.StockMovement, error) {
+	parsedID, err := uuid.Parse(utils.DecodeBase62(productID))
+	if err != nil {
+		return model.StockMovement{}, fmt.Errorf("%w: invalid product id", ErrInvalidStockMovement)
+	}
+	if err := validateStockMovement(request); err != nil {
+		return model.StockMovement{}, err
+	}
+	product, err := s.productRepository.FindProductByID(parsedID.String())
+	if err != nil || product.DeletedAt != nil {
+		return model.StockMovement{}, fmt.Errorf("%w: product not found", ErrInvalidStockMovement)
+	}
+	if product.IsBundle() {
+		return model.StockMovement{}, fmt.Errorf("%w: a bundle holds no stock, move the stock of its components", ErrInvalidStockMovement)
+	}
+	if _, ok := model.ToStockQuantity(request.Quantity, model.QuantityMultiplier(product.QuantityScale)); !ok {
+		return model.StockMovement{}, fmt.Errorf("%w: %v %s of %s is finer than %d decimals", ErrInvalidStockMovement, request.Quantity, product.Unit, product.Name, product.QuantityScale)
+	}
+
+	entity, err := s.repository.InsertStockMovement(*request.ToEntity(parsedID, product.QuantityScale))
+	if err != nil {
+		return model.StockMovement{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockMovementService) FetchStockReconciliation(productID string) (model.StockReconciliation, error) {
+	entity, err := s.repository.GetStockReconciliation(utils.DecodeBase62(productID))
+	if err != nil {
+		return model.StockReconciliation{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+// validateStockMovement only accepts the movements a person records by hand,
+// sales and refunds are booked by the transaction flow.
+func validateStockMovement(request model.CreateStockMovementRequest) error {
+	if request.Quantity == 0 {
+		return fmt.Errorf("%w: quantity must not be zero", ErrInvalidStockMovement)
+	}
+	if request.OutletID != "" {
+		if _, err := uuid.Parse(utils.DecodeBase62(request.OutletID)); err != nil {
+			return fmt.Errorf("%w: invalid outlet id", ErrInvalidStockMovement)
+		}
+	}
+
+	switch request.Type {
+	case model.StockMovementPurchaseReceipt:
+		if request.Quantity < 0 {
+			return fmt.Errorf("%w: a purchase receipt must add stock", ErrInvalidStockMovement)
+		}
+	case model.StockMovementWaste:
+		if request.Quantity > 0 {
+			return fmt.Errorf("%w: waste must take stock out", ErrInvalidStockMovement)
+		}
+	case model.StockMovementAdjustment, model.StockMovementTransfer:
+		if request.Reason == "" {
+			return fmt.Errorf("%w: a reason is required for %s", ErrInvalidStockMovement, request.Type)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported movement type %q", ErrInvalidStockMovement, request.Type)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementServiceFetchStockMovements(t *testing.T) {
+	mockRepo := new(mocks.MockStockMovementRepository)
+	service := NewStockMovementService(mockRepo, new(mocks.MockProductRepository))
+
+	productID := uuid.New()
+	mockRepo.On("FindStockMovementsByProductID", productID.String()).Return([]model.StockMovementEntity{
+		{ID: uuid.New(), ProductID: productID, Type: model.StockMovementSale, Quantity: -1, BalanceAfter: 4},
+	}, nil)
+
+	movements, err := service.FetchStockMovements(utils.EncodeBase62(productID.String()))
+
+	require.NoError(t, err)
+	assert.Len(t, movements, 1)
+	assert.Equal(t, 4, movements[0].BalanceAfter)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStockMovementServiceFetchStockMovementsError(t *testing.T) {
+	mockRepo := new(mocks.MockStockMovementRepository)
+	service := NewStockMovementService(mockRepo, new(mocks.MockProductRepository))
+
+	mockRepo.On("FindStockMovementsByProductID", mock.Anything).Return(nil, errors.New("database error"))
+
+	movements, err := service.FetchStockMovements("abc")
+
+	assert.Error(t, err)
+	assert.Nil(t, movements)
+}
+
+func TestStockMovementServiceCreateStockMovement(t *testing.T) {
+	mockRepo := new(mocks.MockStockMovementRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewStockMovementService(mockRepo, mockProductRepo)
+
+	productID := uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi"}, nil)
+	mockRepo.On("InsertStockMovement", mock.MatchedBy(func(m model.StockMovementEntity) bool {
+		return m.ProductID == productID && m.Type == model.StockMovementPurchaseReceipt && m.Quantity == 12
+	})).Return(model.StockMovementEntity{ID: uuid.New(), ProductID: productID, Type: model.StockMovementPurchaseReceipt, Quantity: 12, BalanceAfter: 12}, nil)
+
+	movement, err := service.CreateStockMovement(utils.EncodeBase62(productID.String()), model.CreateStockMovementRequest{
+		Type:     model.StockMovementPurchaseReceipt,
+		Quantity: 12,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, movement.BalanceAfter)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStockMovementServiceCreateStockMovement_Scaled(t *testing.T) {
+	mockRepo := new(mocks.MockStockMovementRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewStockMovementService(mockRepo, mockProductRepo)
+
+	// rice is kept in grams and counted by the kilo
+	riceID := uuid.New()
+	mockProductRepo.On("FindProductByID", riceID.String()).Return(model.ProductEntity{ID: riceID, Name: "Beras", Unit: "kg", QuantityScale: 3}, nil)
+	mockRepo.On("InsertStockMovement", mock.MatchedBy(func(m model.StockMovementEntity) bool {
+		return m.ProductID == riceID && m.Quantity == -250
+	})).Return(model.StockMovementEntity{ID: uuid.New(), ProductID: riceID, Type: model.StockMovementWaste, Quantity: -250}, nil)
+	id := utils.EncodeBase62(riceID.String())
+
+	_, err := service.CreateStockMovement(id, model.CreateStockMovementRequest{Type: model.StockMovementWaste, Quantity: -0.25})
+	require.NoError(t, err)
+
+	_, err = service.CreateStockMovement(id, model.CreateStockMovementRequest{Type: model.StockMovementWaste, Quantity: -0.2505})
+	assert.ErrorIs(t, err, ErrInvalidStockMovement, "finer than a gram")
+	mockRepo.AssertNumberOfCalls(t, "InsertStockMovement", 1)
+}
+
+func TestStockMovementServiceCreateStockMovement_Invalid(t *testing.T) {
+	productID := utils.EncodeBase62(uuid.New().String())
+
+	tests := []struct {
+		name      string
+		productID string
+		request   model.CreateStockMovementRequest
+	}{
+		{"invalid product", "???", model.CreateStockMovementRequest{Type: model.StockMovementWaste, Quantity: -1}},
+		{"zero quantity", productID, model.CreateStockMovementRequest{Type: model.StockMovementWaste}},
+		{"sale by hand", productID, model.CreateStockMovementRequest{Type: model.StockMovementSale, Quantity: -1}},
+		{"negative receipt", productID, model.CreateStockMovementRequest{Type: model.StockMovementPurchaseReceipt, Quantity: -1}},
+		{"positive waste", productID, model.CreateStockMovementRequest{Type: model.StockMovementWaste, Quantity: 1}},
+		{"adjustment without reason", productID, model.CreateStockMovementRequest{Type: model.StockMovementAdjustment, Quantity: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockStockMovementRepository)
+			service := NewStockMovementService(mockRepo, new(mocks.MockProductRepository))
+
+			_, err := service.CreateStockMovement(tt.productID, tt.request)
+
+			assert.ErrorIs(t, err, ErrInvalidStockMovement)
+			mockRepo.AssertNotCalled(t, "InsertStockMovement", mock.Anything)
+		})
+	}
+}
+
+func TestStockMovementServiceCreateStockMovement_Bundle(t *testing.T) {
+	mockRepo := new(mocks.MockStockMovementRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	service := NewStockMovementService(mockRepo, mockProductRepo)
+
+	bundleID := uuid.New()
+	mockProductRepo.On("FindProductByID", bundleID.String()).Return(model.ProductEntity{ID: bundleID, Name: "Paket Hemat", Type: model.ProductTypeBundle}, nil)
+
+	for _, request := range []model.CreateStockMovementRequest{
+		{Type: model.StockMovementPurchaseReceipt, Quantity: 5},
+		{Type: model.StockMovementWaste, Quantity: -1},
+		{Type: model.StockMovementAdjustment, Quantity: 2, Reason: "recount"},
+	} {
+		_, err := service.CreateStockMovement(utils.EncodeBase62(bundleID.String()), request)
+		assert.ErrorIs(t, err, ErrInvalidStockMovement, request.Type)
+	}
+	mockRepo.AssertNotCalled(t, "InsertStockMovement", mock.Anything)
+}
+
+func TestStockMovementServiceFetchStockReconciliation(t *testing.T) {
+	mockRepo := new(mocks.MockStockMovementRepository)
+	service := NewStockMovementService(mockRepo, new(mocks.MockProductRepository))
+
+	productID := uuid.New()
+	mockRepo.On("GetStockReconciliation", productID.String()).
+		Return(model.StockReconciliationEntity{ProductID: productID, Stock: 10, LedgerBalance: 8}, nil)
+
+	reconciliation, err := service.FetchStockReconciliation(utils.EncodeBase62(productID.String()))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, reconciliation.Difference)
+	assert.False(t, reconciliation.Balanced)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// StockTransferService moves stock between outlets: creating a transfer dispatches it,
+// the destination then receives it, or it is cancelled and the stock goes back to the source.
+type StockTransferService interface {
+	FetchStockTransfers() ([]model.StockTransfer, error)
+	FetchStockTransferByID(id string) (model.StockTransfer, error)
+	CreateStockTransfer(request model.CreateStockTransferRequest) (model.StockTransfer, error)
+	ReceiveStockTransfer(id string) (model.StockTransfer, error)
+	CancelStockTransfer(id string) (model.StockTransfer, error)
+}
+
+type stockTransferService struct {
+	repository        repository.StockTransferRepository
+	outletRepository  repository.OutletRepository
+	productRepository repository.ProductRepository
+}
+
+func NewStockTransferService(repository repository.StockTransferRepository, outletRepository repository.OutletRepository, productRepository repository.ProductRepository) StockTransferService {
+	return &stockTransferService{
+		repository:        repository,
+		outletRepository:  outletRepository,
+		productRepository: productRepository,
+	}
+}
+
+func (s *stockTransferService) FetchStockTransfers() ([]model.StockTransfer, error) {
+	entities, err := s.repository.FindStockTransfers()
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := []model.StockTransfer{}
+	for _, entity := range entities {
+		transfers = append(transfers, *entity.ToModel())
+	}
+	return transfers, nil
+}
+
+func (s *stockTransferService) FetchStockTransferByID(id string) (model.StockTransfer, error) {
+	entity, err := s.repository.FindStockTransferByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockTransferService) CreateStockTransfer(request model.CreateStockTransferRequest) (model.StockTransfer, error) {
+	transfer := *request.ToEntity()
+	if err := s.validateStockTransfer(transfer); err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	entity, err := s.repository.InsertStockTransfer(transfer)
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockTransferService) ReceiveStockTransfer(id string) (model.StockTransfer, error) {
+	transfer, err := s.findWithStatus(id, model.StockTransferInTransit)
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	entity, err := s.repository.ReceiveStockTransfer(transfer.ID.String(), "USER")
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *stockTransferService) CancelStockTransfer(id string) (model.StockTransfer, error) {
+	transfer, err := s.findWithStatus(id, model.StockTransferInTransit)
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+
+	entity, err := s.repository.CancelStockTransfer(transfer.ID.String(), "USER")
+	if err != nil {
+		return model.StockTransfer{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+// validateStockTransfer checks both outlets and that the source holds every line's quantity of a standard product
+func (s *stockTransferService) validateStockTransfer(transfer model.StockTransferEntity) error {
+	for _, outletID := range []uuid.UUID{transfer.FromOutletID, transfer.ToOutletID} {
+		if outletID == uuid.Nil {
+			return fmt.Errorf("%w: invalid outlet id", ErrInvalidStockTransfer)
+		}
+		outlet, err := s.outletRepository.FindOutletByID(outletID.String())
+		if err != nil || outlet.DeletedAt != nil {
+			return fmt.Errorf("%w: outlet not found", ErrInvalidStockTransfer)
+		}
+		if !outlet.IsActive {
+			return fmt.Errorf("%w: outlet %s is not active", ErrInvalidStockTransfer, outlet.Code)
+		}
+	}
+	if transfer.FromOutletID == transfer.ToOutletID {
+		return fmt.Errorf("%w: source and destination outlet must differ", ErrInvalidStockTransfer)
+	}
+
+	if len(transfer.Lines) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidStockTransfer)
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, line := range transfer.Lines {
+		if line.ProductID == uuid.Nil {
+			return fmt.Errorf("%w: invalid product id", ErrInvalidStockTransfer)
+		}
+		if line.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be greater than zero", ErrInvalidStockTransfer)
+		}
+		if seen[line.ProductID] {
+			return fmt.Errorf("%w: the same product cannot be transferred twice", ErrInvalidStockTransfer)
+		}
+		seen[line.ProductID] = true
+
+		product, err := s.productRepository.FindProductByID(line.ProductID.String())
+		if err != nil || product.DeletedAt != nil {
+			return fmt.Errorf("%w: product not found", ErrInvalidStockTransfer)
+		}
+		if product.IsBundle() {
+			return fmt.Errorf("%w: bundles hold no stock, transfer their components instead", ErrInvalidStockTransfer)
+		}
+		stock, err := s.outletRepository.FindOutletStock(transfer.FromOutletID.String(), product.ID.String())
+		if err != nil {
+			return err
+		}
+		if stock.Stock < line.Quantity {
+			return fmt.Errorf("%w: insufficient stock of %s at the source outlet, %d available", ErrInvalidStockTransfer, product.Name, stock.Stock)
+		}
+	}
+	return nil
+}
+
+// findWithStatus loads the transfer and fails with ErrStockTransferStatus unless it is in the given status
+func (s *stockTransferService) findWithStatus(id string, status string) (model.StockTransferEntity, error) {
+	transfer, err := s.repository.FindStockTransferByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.StockTransferEntity{}, err
+	}
+	if transfer.Status != status {
+		return model.StockTransferEntity{}, fmt.Errorf("%w: stock transfer is %s", ErrStockTransferStatus, transfer.Status)
+	}
+	return transfer, nil
+}
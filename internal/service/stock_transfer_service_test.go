@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newStockTransferServiceWithMocks() (StockTransferService, *mocks.MockStockTransferRepository, *mocks.MockOutletRepository, *mocks.MockProductRepository) {
+	mockRepo := new(mocks.MockStockTransferRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	mockProductRepo := new(mocks.MockProductRepository)
+	return NewStockTransferService(mockRepo, mockOutletRepo, mockProductRepo), mockRepo, mockOutletRepo, mockProductRepo
+}
+
+func TestStockTransferServiceCreateStockTransfer(t *testing.T) {
+	service, mockRepo, mockOutletRepo, mockProductRepo := newStockTransferServiceWithMocks()
+
+	fromID, toID, productID := uuid.New(), uuid.New(), uuid.New()
+	mockOutletRepo.On("FindOutletByID", fromID.String()).Return(model.OutletEntity{ID: fromID, IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", toID.String()).Return(model.OutletEntity{ID: toID, IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletStock", fromID.String(), productID.String()).Return(model.OutletStockEntity{Stock: 5}, nil)
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi"}, nil)
+	mockRepo.On("InsertStockTransfer", mock.MatchedBy(func(t model.StockTransferEntity) bool {
+		return t.Status == model.StockTransferInTransit && t.FromOutletID == fromID && len(t.Lines) == 1
+	})).Return(model.StockTransferEntity{ID: uuid.New(), FromOutletID: fromID, ToOutletID: toID, Status: model.StockTransferInTransit}, nil)
+
+	transfer, err := service.CreateStockTransfer(model.CreateStockTransferRequest{
+		FromOutletID: utils.EncodeBase62(fromID.String()),
+		ToOutletID:   utils.EncodeBase62(toID.String()),
+		Items:        []model.StockTransferItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 5}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.StockTransferInTransit, transfer.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStockTransferServiceCreateStockTransfer_Invalid(t *testing.T) {
+	service, mockRepo, mockOutletRepo, mockProductRepo := newStockTransferServiceWithMocks()
+
+	fromID, toID, inactiveID, productID, bundleID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockOutletRepo.On("FindOutletByID", fromID.String()).Return(model.OutletEntity{ID: fromID, IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", toID.String()).Return(model.OutletEntity{ID: toID, IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", inactiveID.String()).Return(model.OutletEntity{ID: inactiveID}, nil)
+	mockOutletRepo.On("FindOutletByID", mock.Anything).Return(model.OutletEntity{}, errors.New("outlet not found"))
+	mockOutletRepo.On("FindOutletStock", fromID.String(), productID.String()).Return(model.OutletStockEntity{Stock: 2}, nil)
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi"}, nil)
+	mockProductRepo.On("FindProductByID", bundleID.String()).Return(model.ProductEntity{ID: bundleID, Type: model.ProductTypeBundle}, nil)
+
+	from := utils.EncodeBase62(fromID.String())
+	to := utils.EncodeBase62(toID.String())
+	product := utils.EncodeBase62(productID.String())
+	items := func(quantity int) []model.StockTransferItemRequest {
+		return []model.StockTransferItemRequest{{ProductID: product, Quantity: quantity}}
+	}
+	cases := map[string]model.CreateStockTransferRequest{
+		"invalid outlet":     {FromOutletID: "???", ToOutletID: to, Items: items(1)},
+		"unknown outlet":     {FromOutletID: from, ToOutletID: utils.EncodeBase62(uuid.New().String()), Items: items(1)},
+		"inactive outlet":    {FromOutletID: from, ToOutletID: utils.EncodeBase62(inactiveID.String()), Items: items(1)},
+		"same outlet":        {FromOutletID: from, ToOutletID: from, Items: items(1)},
+		"no items":           {FromOutletID: from, ToOutletID: to},
+		"zero quantity":      {FromOutletID: from, ToOutletID: to, Items: items(0)},
+		"duplicate product":  {FromOutletID: from, ToOutletID: to, Items: append(items(1), items(1)...)},
+		"bundle":             {FromOutletID: from, ToOutletID: to, Items: []model.StockTransferItemRequest{{ProductID: utils.EncodeBase62(bundleID.String()), Quantity: 1}}},
+		"insufficient stock": {FromOutletID: from, ToOutletID: to, Items: items(3)},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.CreateStockTransfer(req)
+			assert.ErrorIs(t, err, ErrInvalidStockTransfer)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "InsertStockTransfer", mock.Anything)
+}
+
+func TestStockTransferServiceReceiveAndCancel(t *testing.T) {
+	service, mockRepo, _, _ := newStockTransferServiceWithMocks()
+
+	inTransitID, receivedID := uuid.New(), uuid.New()
+	mockRepo.On("FindStockTransferByID", inTransitID.String()).Return(model.StockTransferEntity{ID: inTransitID, Status: model.StockTransferInTransit}, nil)
+	mockRepo.On("FindStockTransferByID", receivedID.String()).Return(model.StockTransferEntity{ID: receivedID, Status: model.StockTransferReceived}, nil)
+	mockRepo.On("ReceiveStockTransfer", inTransitID.String(), "USER").Return(model.StockTransferEntity{ID: inTransitID, Status: model.StockTransferReceived}, nil)
+
+	transfer, err := service.ReceiveStockTransfer(utils.EncodeBase62(inTransitID.String()))
+	require.NoError(t, err)
+	assert.Equal(t, model.StockTransferReceived, transfer.Status)
+
+	_, err = service.ReceiveStockTransfer(utils.EncodeBase62(receivedID.String()))
+	assert.ErrorIs(t, err, ErrStockTransferStatus)
+	_, err = service.CancelStockTransfer(utils.EncodeBase62(receivedID.String()))
+	assert.ErrorIs(t, err, ErrStockTransferStatus)
+	mockRepo.AssertNotCalled(t, "CancelStockTransfer", mock.Anything, mock.Anything)
+}
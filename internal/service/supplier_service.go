@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+)
+
+type SupplierService interface {
+	FetchSuppliers() ([]model.Supplier, error)
+	FetchSupplierByID(id string) (model.Supplier, error)
+	CreateSupplier(supplier model.CreateSupplierRequest) (model.Supplier, error)
+	UpdateSupplierByID(id string, supplier model.UpdateSupplierRequest) (model.Supplier, error)
+	DeleteSupplierByID(id string) error
+}
+
+type supplierService struct {
+	repository repository.SupplierRepository
+}
+
+func NewSupplierService(repository repository.SupplierRepository) SupplierService {
+	return &supplierService{
+		repository: repository,
+	}
+}
+
+func (s *supplierService) FetchSuppliers() ([]model.Supplier, error) {
+	entities, err := s.repository.FindSuppliers()
+	if err != nil {
+		return nil, err
+	}
+
+	suppliers := []model.Supplier{}
+	for _, entity := range entities {
+		suppliers = append(suppliers, *entity.ToModel())
+	}
+	return suppliers, nil
+}
+
+func (s *supplierService) FetchSupplierByID(id string) (model.Supplier, error) {
+	entity, err := s.repository.FindSupplierByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.Supplier{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *supplierService) CreateSupplier(request model.CreateSupplierRequest) (model.Supplier, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return model.Supplier{}, fmt.Errorf("%w: name is required", ErrInvalidSupplier)
+	}
+
+	entity, err := s.repository.InsertSupplier(*request.ToEntity())
+	if err != nil {
+		return model.Supplier{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *supplierService) UpdateSupplierByID(id string, request model.UpdateSupplierRequest) (model.Supplier, error) {
+	if strings.TrimSpace(request.Name) == "" {
+		return model.Supplier{}, fmt.Errorf("%w: name is required", ErrInvalidSupplier)
+	}
+
+	entity, err := s.repository.UpdateSupplierByID(utils.DecodeBase62(id), *request.ToEntity())
+	if err != nil {
+		return model.Supplier{}, err
+	}
+	return *entity.ToModel(), nil
+}
+
+func (s *supplierService) DeleteSupplierByID(id string) error {
+	return s.repository.DeleteSupplierByID(utils.DecodeBase62(id))
+}
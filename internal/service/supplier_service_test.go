@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierServiceCreateSupplier(t *testing.T) {
+	mockRepo := new(mocks.MockSupplierRepository)
+	service := NewSupplierService(mockRepo)
+
+	mockRepo.On("InsertSupplier", mock.MatchedBy(func(s model.SupplierEntity) bool {
+		return s.Name == "PT Sumber Makmur"
+	})).Return(model.SupplierEntity{ID: uuid.New(), Name: "PT Sumber Makmur"}, nil)
+
+	supplier, err := service.CreateSupplier(model.CreateSupplierRequest{Name: "PT Sumber Makmur"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "PT Sumber Makmur", supplier.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSupplierServiceCreateSupplier_NameRequired(t *testing.T) {
+	mockRepo := new(mocks.MockSupplierRepository)
+	service := NewSupplierService(mockRepo)
+
+	_, err := service.CreateSupplier(model.CreateSupplierRequest{Name: "  "})
+	assert.ErrorIs(t, err, ErrInvalidSupplier)
+
+	_, err = service.UpdateSupplierByID("id", model.UpdateSupplierRequest{})
+	assert.ErrorIs(t, err, ErrInvalidSupplier)
+	mockRepo.AssertNotCalled(t, "InsertSupplier", mock.Anything)
+}
+
+func TestSupplierServiceFetchSuppliers(t *testing.T) {
+	mockRepo := new(mocks.MockSupplierRepository)
+	service := NewSupplierService(mockRepo)
+
+	id := uuid.New()
+	mockRepo.On("FindSuppliers").Return([]model.SupplierEntity{{ID: id, Name: "CV Jaya"}}, nil)
+	mockRepo.On("FindSupplierByID", id.String()).Return(model.SupplierEntity{ID: id, Name: "CV Jaya"}, nil)
+	mockRepo.On("DeleteSupplierByID", id.String()).Return(nil)
+
+	suppliers, err := service.FetchSuppliers()
+	require.NoError(t, err)
+	assert.Len(t, suppliers, 1)
+
+	supplier, err := service.FetchSupplierByID(utils.EncodeBase62(id.String()))
+	require.NoError(t, err)
+	assert.Equal(t, "CV Jaya", supplier.Name)
+
+	assert.NoError(t, service.DeleteSupplierByID(utils.EncodeBase62(id.String())))
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+	"codewithumam-kasir-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxSyncBatch caps the sales pushed at once, a terminal back online pushes its backlog in batches
+	maxSyncBatch = 200
+	// maxClockSkew is how far ahead of the server a terminal's clock may run
+	maxClockSkew = 5 * time.Minute
+)
+
+// SyncService keeps the POS terminals working through connectivity loss.
+// A terminal pulls the catalog changed since its cursor and pushes the sales it rang up offline once it is back.
+type SyncService interface {
+	// PullCatalog returns the products, categories and price lists changed since the cursor, soft-deleted ones included,
+	// with the prices, tiers and units of the products. The whole catalog is returned when the cursor is empty.
+	// An outlet id adds that outlet's price overrides.
+	PullCatalog(cursor, outletID string) (model.CatalogDelta, error)
+	// PushTransactions books the offline sales in order, one result per sale. A rejected sale does not stop the rest
+	// and a sale already booked is reported as a duplicate, so a terminal can push the same batch again after an error.
+	PushTransactions(request model.SyncTransactionsRequest) ([]model.SyncTransactionResult, error)
+	// FetchStockConflicts takes an optional status, open or resolved, every conflict is listed when it is empty
+	FetchStockConflicts(status string) ([]model.StockConflict, error)
+	ResolveStockConflict(id string, request model.ResolveStockConflictRequest) (model.StockConflict, error)
+}
+
+type syncService struct {
+	productRepository       repository.ProductRepository
+	categoryRepository      repository.CategoryRepository
+	priceListRepository     repository.PriceListRepository
+	outletRepository        repository.OutletRepository
+	transactionRepository   repository.TransactionRepository
+	stockConflictRepository repository.StockConflictRepository
+	transactionService      TransactionService
+}
+
+func NewSyncService(productRepository repository.ProductRepository, categoryRepository repository.CategoryRepository, priceListRepository repository.PriceListRepository, outletRepository repository.OutletRepository, transactionRepository repository.TransactionRepository, stockConflictRepository repository.StockConflictRepository, transactionService TransactionService) SyncService {
+	return &syncService{
+		productRepository:       productRepository,
+		categoryRepository:      categoryRepository,
+		priceListRepository:     priceListRepository,
+		outletRepository:        outletRepository,
+		transactionRepository:   transactionRepository,
+		stockConflictRepository: stockConflictRepository,
+		transactionService:      transactionService,
+	}
+}
+
+func (s *syncService) PullCatalog(cursor, outletID string) (model.CatalogDelta, error) {
+	since, err := parseSyncCursor(cursor)
+	if err != nil {
+		return model.CatalogDelta{}, err
+	}
+	outlet, err := parseOutletID(outletID)
+	if err != nil {
+		return model.CatalogDelta{}, fmt.Errorf("%w: invalid outlet id", ErrInvalidSync)
+	}
+
+	// the products come first, they hand out the cursor the categories and price lists are read behind too
+	products, next, err := s.productRepository.FindProductsChangedSince(since)
+	if err != nil {
+		return model.CatalogDelta{}, err
+	}
+	categories, err := s.categoryRepository.FindCategoriesChangedSince(since)
+	if err != nil {
+		return model.CatalogDelta{}, err
+	}
+	priceLists, err := s.priceListRepository.FindPriceListsChangedSince(since)
+	if err != nil {
+		return model.CatalogDelta{}, err
+	}
+
+	delta := model.CatalogDelta{
+		Products:   []model.CatalogProduct{},
+		Categories: []model.Category{},
+		PriceLists: []model.PriceList{},
+		Cursor:     strconv.FormatUint(next, 10),
+	}
+	for _, p := range products {
+		delta.Products = append(delta.Products, model.NewCatalogProduct(p))
+	}
+	for _, c := range categories {
+		delta.Categories = append(delta.Categories, *c.ToModel())
+	}
+	for _, l := range priceLists {
+		delta.PriceLists = append(delta.PriceLists, *l.ToModel())
+	}
+	if len(products) == 0 {
+		return delta, nil
+	}
+	if err := s.attachPricing(delta.Products, products, outlet); err != nil {
+		return model.CatalogDelta{}, err
+	}
+	return delta, nil
+}
+
+// attachPricing adds what prices each pulled product offline: its scheduled prices, tiers, units and the outlet's override
+// pulled holds the products in the same order.
+func (s *syncService) attachPricing(pulled []model.CatalogProduct, products []model.ProductEntity, outletID *uuid.UUID) error {
+	index := map[uuid.UUID]int{}
+	var productIDs []string
+	for i, p := range products {
+		index[p.ID] = i
+		productIDs = append(productIDs, p.ID.String())
+	}
+
+	prices, err := s.priceListRepository.FindProductPricesByProductIDs(productIDs)
+	if err != nil {
+		return err
+	}
+	for _, p := range prices {
+		if i, ok := index[p.ProductID]; ok {
+			pulled[i].Prices = append(pulled[i].Prices, *p.ToModel())
+		}
+	}
+	tiers, err := s.priceListRepository.FindProductPriceTiersByProductIDs(productIDs)
+	if err != nil {
+		return err
+	}
+	for _, t := range tiers {
+		if i, ok := index[t.ProductID]; ok {
+			pulled[i].PriceTiers = append(pulled[i].PriceTiers, *t.ToModel())
+		}
+	}
+	units, err := s.productRepository.FindProductUnitsByProductIDs(productIDs)
+	if err != nil {
+		return err
+	}
+	for _, u := range units {
+		if i, ok := index[u.ProductID]; ok {
+			pulled[i].Units = append(pulled[i].Units, *u.ToModel(products[i].QuantityScale))
+		}
+	}
+
+	if outletID == nil {
+		return nil
+	}
+	stocks, err := s.outletRepository.FindOutletStocks(outletID.String())
+	if err != nil {
+		return err
+	}
+	for _, stock := range stocks {
+		if i, ok := index[stock.ProductID]; ok && stock.PriceOverride != nil {
+			price := *stock.PriceOverride
+			pulled[i].OutletPrice = &price
+		}
+	}
+	return nil
+}
+
+// parseSyncCursor reads the cursor a previous pull returned, an empty one pulls the whole catalog.
+// A terminal still holding a timestamp cursor from before the cursors were transaction ids pulls everything once.
+func parseSyncCursor(cursor string) (uint64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	if since, err := strconv.ParseUint(cursor, 10, 64); err == nil {
+		return since, nil
+	}
+	if _, err := time.Parse(time.RFC3339Nano, cursor); err == nil {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("%w: cursor must be one a previous pull returned", ErrInvalidSync)
+}
+
+func (s *syncService) PushTransactions(request model.SyncTransactionsRequest) ([]model.SyncTransactionResult, error) {
+	if len(request.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: transactions are required", ErrInvalidSync)
+	}
+	if len(request.Transactions) > maxSyncBatch {
+		return nil, fmt.Errorf("%w: at most %d transactions can be pushed at once", ErrInvalidSync, maxSyncBatch)
+	}
+
+	results := []model.SyncTransactionResult{}
+	for _, sale := range request.Transactions {
+		result, err := s.pushTransaction(sale)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// pushTransaction books one offline sale. It only errs when the lookup fails, the terminal then pushes the batch again.
+func (s *syncService) pushTransaction(sale model.SyncTransactionRequest) (model.SyncTransactionResult, error) {
+	result := model.SyncTransactionResult{ID: sale.ID}
+	id := sale.ParseID()
+	if id == uuid.Nil {
+		return rejectSale(result, "id must be a UUIDv7"), nil
+	}
+	if sale.CreatedAt.IsZero() {
+		return rejectSale(result, "created_at is required"), nil
+	}
+	if sale.CreatedAt.After(time.Now().Add(maxClockSkew)) {
+		return rejectSale(result, "created_at is in the future"), nil
+	}
+
+	existing, err := s.transactionRepository.FindTransactionByID(id.String())
+	if err != nil {
+		return model.SyncTransactionResult{}, err
+	}
+	if existing.ID != uuid.Nil {
+		result.Status = model.SyncDuplicate
+		result.Transaction = existing.ToModel()
+		return result, nil
+	}
+
+	tx, err := s.transactionService.CreateTransaction(sale.ToCreateTransactionRequest())
+	if err != nil {
+		// a concurrent push of the same batch may have booked it in the meantime
+		if existing, findErr := s.transactionRepository.FindTransactionByID(id.String()); findErr == nil && existing.ID != uuid.Nil {
+			result.Status = model.SyncDuplicate
+			result.Transaction = existing.ToModel()
+			return result, nil
+		}
+		return rejectSale(result, err.Error()), nil
+	}
+	result.Status = model.SyncCreated
+	result.Transaction = &tx
+	return result, nil
+}
+
+func rejectSale(result model.SyncTransactionResult, reason string) model.SyncTransactionResult {
+	result.Status = model.SyncRejected
+	result.Error = reason
+	return result
+}
+
+func (s *syncService) FetchStockConflicts(status string) ([]model.StockConflict, error) {
+	if status != "" && status != model.StockConflictOpen && status != model.StockConflictResolved {
+		return nil, fmt.Errorf("%w: status must be open or resolved", ErrInvalidSync)
+	}
+	entities, err := s.stockConflictRepository.FindStockConflicts(status)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []model.StockConflict{}
+	for _, entity := range entities {
+		conflicts = append(conflicts, *entity.ToModel())
+	}
+	return conflicts, nil
+}
+
+func (s *syncService) ResolveStockConflict(id string, request model.ResolveStockConflictRequest) (model.StockConflict, error) {
+	conflict, err := s.stockConflictRepository.FindStockConflictByID(utils.DecodeBase62(id))
+	if err != nil {
+		return model.StockConflict{}, fmt.Errorf("%w: stock conflict not found", ErrInvalidSync)
+	}
+	if conflict.IsResolved() {
+		return model.StockConflict{}, fmt.Errorf("%w: resolved by %s", ErrStockConflictStatus, conflict.ResolvedBy)
+	}
+
+	conflict.ResolvedBy = "USER"
+	conflict.ResolutionNotes = strings.TrimSpace(request.Notes)
+	entity, err := s.stockConflictRepository.ResolveStockConflict(conflict)
+	if err != nil {
+		return model.StockConflict{}, err
+	}
+	return *entity.ToModel(), nil
+}
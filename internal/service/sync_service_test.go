@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncServicePullCatalog(t *testing.T) {
+	mockProductRepo := new(mocks.MockProductRepository)
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	mockPriceListRepo := new(mocks.MockPriceListRepository)
+	mockOutletRepo := new(mocks.MockOutletRepository)
+	service := NewSyncService(mockProductRepo, mockCategoryRepo, mockPriceListRepo, mockOutletRepo, new(mocks.MockTransactionRepository), new(mocks.MockStockConflictRepository), new(mocks.MockTransactionService))
+
+	deletedAt := time.Now()
+	kopi, teh, outletID := uuid.New(), uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductsChangedSince", uint64(7400)).Return([]model.ProductEntity{
+		{ID: kopi, Name: "Kopi", Price: 18000},
+		{ID: teh, Name: "Teh", DeletedAt: &deletedAt},
+	}, uint64(7410), nil)
+	mockCategoryRepo.On("FindCategoriesChangedSince", uint64(7400)).Return([]model.CategoryEntity{
+		{ID: uuid.New(), Name: "Drinks"},
+	}, nil)
+	mockPriceListRepo.On("FindPriceListsChangedSince", uint64(7400)).Return([]model.PriceListEntity{
+		{ID: uuid.New(), Code: "MEMBER", Name: "Member", IsActive: true},
+		{ID: uuid.New(), Code: "GROSIR", Name: "Grosir", DeletedAt: &deletedAt},
+	}, nil)
+	ids := []string{kopi.String(), teh.String()}
+	mockPriceListRepo.On("FindProductPricesByProductIDs", ids).Return([]model.ProductPriceEntity{
+		{ID: uuid.New(), ProductID: kopi, Price: 20000, StartsAt: time.Now().Add(24 * time.Hour)},
+	}, nil)
+	mockPriceListRepo.On("FindProductPriceTiersByProductIDs", ids).Return([]model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: kopi, MinQuantity: 10, Price: 16000},
+	}, nil)
+	mockProductRepo.On("FindProductUnitsByProductIDs", ids).Return([]model.ProductUnitEntity{}, nil)
+	override := int64(17000)
+	mockOutletRepo.On("FindOutletStocks", outletID.String()).Return([]model.OutletStockEntity{
+		{OutletID: outletID, ProductID: kopi, PriceOverride: &override},
+	}, nil)
+
+	delta, err := service.PullCatalog("7400", utils.EncodeBase62(outletID.String()))
+	require.NoError(t, err)
+	require.Len(t, delta.Products, 2)
+	assert.NotNil(t, delta.Products[1].DeletedAt, "soft-deletes are pulled too")
+	assert.Len(t, delta.Categories, 1)
+	require.Len(t, delta.PriceLists, 2)
+	assert.NotNil(t, delta.PriceLists[1].DeletedAt, "a deleted price list is pulled so the terminal drops it")
+	assert.Equal(t, "7410", delta.Cursor)
+	assert.Len(t, delta.Products[0].Prices, 1, "a scheduled price goes out before it starts")
+	assert.Equal(t, 10, delta.Products[0].PriceTiers[0].MinQuantity)
+	assert.Equal(t, &override, delta.Products[0].OutletPrice)
+	assert.Empty(t, delta.Products[1].PriceTiers)
+	assert.Nil(t, delta.Products[1].OutletPrice)
+
+	mockProductRepo.On("FindProductsChangedSince", uint64(7410)).Return([]model.ProductEntity(nil), uint64(7410), nil)
+	mockCategoryRepo.On("FindCategoriesChangedSince", uint64(7410)).Return([]model.CategoryEntity(nil), nil)
+	mockPriceListRepo.On("FindPriceListsChangedSince", uint64(7410)).Return([]model.PriceListEntity(nil), nil)
+	delta, err = service.PullCatalog("7410", "")
+	require.NoError(t, err)
+	assert.Empty(t, delta.Products)
+	assert.Empty(t, delta.PriceLists, "unchanged price lists are not sent again")
+	assert.Equal(t, "7410", delta.Cursor, "nothing changed, the cursor stays")
+
+	// a cursor from before the transaction id cursors pulls the whole catalog once
+	mockProductRepo.On("FindProductsChangedSince", uint64(0)).Return([]model.ProductEntity(nil), uint64(7412), nil)
+	mockCategoryRepo.On("FindCategoriesChangedSince", uint64(0)).Return([]model.CategoryEntity(nil), nil)
+	mockPriceListRepo.On("FindPriceListsChangedSince", uint64(0)).Return([]model.PriceListEntity(nil), nil)
+	delta, err = service.PullCatalog("2026-10-01T08:00:00Z", "")
+	require.NoError(t, err)
+	assert.Equal(t, "7412", delta.Cursor)
+
+	_, err = service.PullCatalog("yesterday", "")
+	assert.ErrorIs(t, err, ErrInvalidSync)
+	_, err = service.PullCatalog("", "???")
+	assert.ErrorIs(t, err, ErrInvalidSync)
+}
+
+func TestSyncServicePushTransactions(t *testing.T) {
+	mockTxRepo := new(mocks.MockTransactionRepository)
+	mockTxService := new(mocks.MockTransactionService)
+	service := NewSyncService(new(mocks.MockProductRepository), new(mocks.MockCategoryRepository), new(mocks.MockPriceListRepository), new(mocks.MockOutletRepository), mockTxRepo, new(mocks.MockStockConflictRepository), mockTxService)
+
+	newID, syncedID, failingID := uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7())
+	occurredAt := time.Now().Add(-time.Hour)
+	mockTxRepo.On("FindTransactionByID", newID.String()).Return(model.TransactionEntity{}, nil)
+	mockTxRepo.On("FindTransactionByID", syncedID.String()).Return(model.TransactionEntity{ID: syncedID}, nil)
+	mockTxRepo.On("FindTransactionByID", failingID.String()).Return(model.TransactionEntity{}, nil)
+	mockTxService.On("CreateTransaction", mock.MatchedBy(func(req model.CreateTransactionRequest) bool {
+		return req.Offline && req.ID == newID && req.OccurredAt.Equal(occurredAt)
+	})).Return(model.Transaction{ID: utils.EncodeBase62(newID.String())}, nil)
+	mockTxService.On("CreateTransaction", mock.MatchedBy(func(req model.CreateTransactionRequest) bool {
+		return req.ID == failingID
+	})).Return(model.Transaction{}, errors.New("product not found"))
+
+	results, err := service.PushTransactions(model.SyncTransactionsRequest{Transactions: []model.SyncTransactionRequest{
+		{ID: newID.String(), CreatedAt: occurredAt},
+		{ID: utils.EncodeBase62(syncedID.String()), CreatedAt: occurredAt},
+		{ID: failingID.String(), CreatedAt: occurredAt},
+		{ID: uuid.NewString(), CreatedAt: occurredAt},
+		{ID: uuid.Must(uuid.NewV7()).String(), CreatedAt: time.Now().Add(time.Hour)},
+	}})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	assert.Equal(t, model.SyncCreated, results[0].Status)
+	assert.Equal(t, model.SyncDuplicate, results[1].Status, "Base62 ids are accepted too")
+	assert.Equal(t, model.SyncRejected, results[2].Status)
+	assert.Equal(t, "product not found", results[2].Error)
+	assert.Equal(t, "id must be a UUIDv7", results[3].Error, "a UUIDv4 is not accepted")
+	assert.Equal(t, "created_at is in the future", results[4].Error)
+	mockTxService.AssertNumberOfCalls(t, "CreateTransaction", 2)
+
+	_, err = service.PushTransactions(model.SyncTransactionsRequest{})
+	assert.ErrorIs(t, err, ErrInvalidSync)
+}
+
+func TestSyncServiceResolveStockConflict(t *testing.T) {
+	mockConflictRepo := new(mocks.MockStockConflictRepository)
+	service := NewSyncService(new(mocks.MockProductRepository), new(mocks.MockCategoryRepository), new(mocks.MockPriceListRepository), new(mocks.MockOutletRepository), new(mocks.MockTransactionRepository), mockConflictRepo, new(mocks.MockTransactionService))
+
+	openID, resolvedID := uuid.New(), uuid.New()
+	resolvedAt := time.Now()
+	mockConflictRepo.On("FindStockConflictByID", openID.String()).Return(model.StockConflictEntity{ID: openID, Quantity: 2}, nil)
+	mockConflictRepo.On("FindStockConflictByID", resolvedID.String()).Return(model.StockConflictEntity{ID: resolvedID, ResolvedAt: &resolvedAt, ResolvedBy: "USER"}, nil)
+	mockConflictRepo.On("ResolveStockConflict", mock.MatchedBy(func(c model.StockConflictEntity) bool {
+		return c.ID == openID && c.ResolutionNotes == "recounted"
+	})).Return(model.StockConflictEntity{ID: openID, Quantity: 2, ResolvedAt: &resolvedAt, ResolvedBy: "USER", ResolutionNotes: "recounted"}, nil)
+
+	conflict, err := service.ResolveStockConflict(utils.EncodeBase62(openID.String()), model.ResolveStockConflictRequest{Notes: " recounted "})
+	require.NoError(t, err)
+	assert.Equal(t, model.StockConflictResolved, conflict.Status)
+
+	_, err = service.ResolveStockConflict(utils.EncodeBase62(resolvedID.String()), model.ResolveStockConflictRequest{})
+	assert.ErrorIs(t, err, ErrStockConflictStatus)
+
+	_, err = service.FetchStockConflicts("pending")
+	assert.ErrorIs(t, err, ErrInvalidSync)
+}
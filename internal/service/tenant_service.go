@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
+)
+
+// TenantService works out which shop a request belongs to
+type TenantService interface {
+	ResolveTenant(token string, host string) (model.TenantEntity, error)
+}
+
+type tenantService struct {
+	repository         repository.TenantRepository
+	baseDomain         string
+	allowSubdomainOnly bool
+}
+
+// NewTenantService resolves subdomains of baseDomain, an empty baseDomain only resolves API tokens.
+// A subdomain alone names a shop but proves nothing, it only resolves without a token when allowSubdomainOnly is set.
+func NewTenantService(repository repository.TenantRepository, baseDomain string, allowSubdomainOnly bool) TenantService {
+	return &tenantService{
+		repository:         repository,
+		baseDomain:         strings.ToLower(strings.Trim(baseDomain, ".")),
+		allowSubdomainOnly: allowSubdomainOnly,
+	}
+}
+
+// ResolveTenant looks the tenant up by its API token first and by the subdomain of host otherwise.
+// When both are given they have to name the same tenant.
+func (s *tenantService) ResolveTenant(token string, host string) (model.TenantEntity, error) {
+	slug := s.subdomain(host)
+
+	var tenant model.TenantEntity
+	var err error
+	switch {
+	case token != "":
+		tenant, err = s.repository.FindTenantByAPITokenHash(model.HashAPIToken(token))
+		if err != nil {
+			return model.TenantEntity{}, fmt.Errorf("%w: unknown api token", ErrTenantNotFound)
+		}
+		if slug != "" && slug != tenant.Slug {
+			return model.TenantEntity{}, fmt.Errorf("%w: api token does not belong to %s", ErrTenantNotFound, slug)
+		}
+	case slug != "":
+		if !s.allowSubdomainOnly {
+			return model.TenantEntity{}, fmt.Errorf("%w: an api token is required for %s", ErrTenantNotFound, slug)
+		}
+		tenant, err = s.repository.FindTenantBySlug(slug)
+		if err != nil {
+			return model.TenantEntity{}, fmt.Errorf("%w: unknown subdomain %s", ErrTenantNotFound, slug)
+		}
+	default:
+		return model.TenantEntity{}, fmt.Errorf("%w: no api token or subdomain", ErrTenantNotFound)
+	}
+
+	if !tenant.IsActive {
+		return model.TenantEntity{}, fmt.Errorf("%w: %s", ErrTenantInactive, tenant.Slug)
+	}
+	return tenant, nil
+}
+
+// subdomain returns the single label host has in front of the base domain, or "" when it has none
+func (s *tenantService) subdomain(host string) string {
+	if s.baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	slug, ok := strings.CutSuffix(host, "."+s.baseDomain)
+	if !ok || slug == "" || strings.Contains(slug, ".") {
+		return ""
+	}
+	return slug
+}
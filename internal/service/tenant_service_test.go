@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	mocks "codewithumam-kasir-api/internal/mock"
+	"codewithumam-kasir-api/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantServiceResolveTenant_ByToken(t *testing.T) {
+	mockRepo := new(mocks.MockTenantRepository)
+	service := NewTenantService(mockRepo, "kasir.example.com", false)
+
+	shop := model.TenantEntity{ID: uuid.New(), Slug: "toko-a", IsActive: true}
+	mockRepo.On("FindTenantByAPITokenHash", model.HashAPIToken("token-a")).Return(shop, nil)
+
+	tenant, err := service.ResolveTenant("token-a", "api.internal:8080")
+	require.NoError(t, err)
+	assert.Equal(t, shop.ID, tenant.ID)
+
+	tenant, err = service.ResolveTenant("token-a", "toko-a.kasir.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, shop.ID, tenant.ID)
+
+	_, err = service.ResolveTenant("token-a", "toko-b.kasir.example.com")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+	mockRepo.AssertNotCalled(t, "FindTenantBySlug", "toko-b")
+}
+
+func TestTenantServiceResolveTenant_BySubdomain(t *testing.T) {
+	mockRepo := new(mocks.MockTenantRepository)
+	service := NewTenantService(mockRepo, "kasir.example.com", true)
+
+	shop := model.TenantEntity{ID: uuid.New(), Slug: "toko-a", IsActive: true}
+	mockRepo.On("FindTenantBySlug", "toko-a").Return(shop, nil)
+	mockRepo.On("FindTenantBySlug", "toko-x").Return(model.TenantEntity{}, errors.New("tenant not found"))
+
+	tenant, err := service.ResolveTenant("", "Toko-A.kasir.example.com:443")
+	require.NoError(t, err)
+	assert.Equal(t, shop.ID, tenant.ID)
+
+	_, err = service.ResolveTenant("", "toko-x.kasir.example.com")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+
+	for _, host := range []string{"kasir.example.com", "a.b.kasir.example.com", "toko-a.other.com", ""} {
+		_, err = service.ResolveTenant("", host)
+		assert.ErrorIs(t, err, ErrTenantNotFound, host)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTenantServiceResolveTenant_SubdomainNeedsTokenByDefault(t *testing.T) {
+	mockRepo := new(mocks.MockTenantRepository)
+	service := NewTenantService(mockRepo, "kasir.example.com", false)
+
+	_, err := service.ResolveTenant("", "toko-a.kasir.example.com")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+	mockRepo.AssertNotCalled(t, "FindTenantBySlug", "toko-a")
+}
+
+func TestTenantServiceResolveTenant_Inactive(t *testing.T) {
+	mockRepo := new(mocks.MockTenantRepository)
+	service := NewTenantService(mockRepo, "", true)
+
+	mockRepo.On("FindTenantByAPITokenHash", model.HashAPIToken("token-a")).Return(model.TenantEntity{ID: uuid.New(), Slug: "toko-a"}, nil)
+
+	_, err := service.ResolveTenant("token-a", "")
+	assert.ErrorIs(t, err, ErrTenantInactive)
+
+	_, err = service.ResolveTenant("", "toko-a.kasir.example.com")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+}
@@ -2,8 +2,12 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"codewithumam-kasir-api/internal/event"
 	"codewithumam-kasir-api/internal/model"
 	"codewithumam-kasir-api/internal/repository"
 	"codewithumam-kasir-api/internal/utils"
@@ -12,20 +16,41 @@ import (
 
 type TransactionService interface {
 	CreateTransaction(req model.CreateTransactionRequest) (model.Transaction, error)
-	FetchReport(startDateStr, endDateStr, period string) (model.ReportResponse, error)
-	FetchMostPopularCategory(startDateStr, endDateStr string) (model.PopularCategory, error)
-	FetchMostPopularProduct(startDateStr, endDateStr string) (model.PopularItem, error)
+	// The reports take an optional Base62 outlet id, empty reports across every outlet
+	FetchReport(startDateStr, endDateStr, period, outletID string) (model.ReportResponse, error)
+	// FetchMostPopularCategory rolls the sales up to the categories at level when one is given, 0 being the top level
+	FetchMostPopularCategory(startDateStr, endDateStr, outletID, level string) (model.PopularCategory, error)
+	FetchMostPopularProduct(startDateStr, endDateStr, outletID string) (model.PopularItem, error)
+	FetchMarginReport(startDateStr, endDateStr, period, outletID string) (model.MarginReport, error)
 }
 
 type TransactionServiceImpl struct {
-	txRepo      repository.TransactionRepository
-	productRepo repository.ProductRepository
+	txRepo        repository.TransactionRepository
+	productRepo   repository.ProductRepository
+	lotRepo       repository.LotRepository
+	outletRepo    repository.OutletRepository
+	customerRepo  repository.CustomerRepository
+	loyaltyRepo   repository.LoyaltyRepository
+	giftCardRepo  repository.GiftCardRepository
+	shiftRepo     repository.ShiftRepository
+	draftRepo     repository.DraftOrderRepository
+	priceListRepo repository.PriceListRepository
+	publisher     event.Publisher
 }
 
-func NewTransactionService(txRepo repository.TransactionRepository, productRepo repository.ProductRepository) TransactionService {
+func NewTransactionService(txRepo repository.TransactionRepository, productRepo repository.ProductRepository, lotRepo repository.LotRepository, outletRepo repository.OutletRepository, customerRepo repository.CustomerRepository, loyaltyRepo repository.LoyaltyRepository, giftCardRepo repository.GiftCardRepository, shiftRepo repository.ShiftRepository, draftRepo repository.DraftOrderRepository, priceListRepo repository.PriceListRepository, publisher event.Publisher) TransactionService {
 	return &TransactionServiceImpl{
-		txRepo:      txRepo,
-		productRepo: productRepo,
+		txRepo:        txRepo,
+		productRepo:   productRepo,
+		lotRepo:       lotRepo,
+		outletRepo:    outletRepo,
+		customerRepo:  customerRepo,
+		loyaltyRepo:   loyaltyRepo,
+		giftCardRepo:  giftCardRepo,
+		shiftRepo:     shiftRepo,
+		draftRepo:     draftRepo,
+		priceListRepo: priceListRepo,
+		publisher:     publisher,
 	}
 }
 
@@ -33,27 +58,138 @@ func (s *TransactionServiceImpl) CreateTransaction(req model.CreateTransactionRe
 	if len(req.Items) == 0 {
 		return model.Transaction{}, errors.New("transaction must have at least one item")
 	}
+	shift, err := s.findOpenShift(req.ShiftID)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if shift != nil && shift.OutletID != nil && req.OutletID == "" {
+		req.OutletID = utils.EncodeBase62(shift.OutletID.String())
+	}
+	outletID, err := s.findSellingOutlet(req.OutletID)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if shift != nil && shift.OutletID != nil && (outletID == nil || *outletID != *shift.OutletID) {
+		return model.Transaction{}, fmt.Errorf("%w: the shift's drawer is at outlet %s", ErrInvalidShift, shift.OutletName)
+	}
+	customer, err := s.findCustomer(req.CustomerID)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	priceListID, err := s.findPriceList(req.PriceListID, customer, req.Offline)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if req.RedeemPoints < 0 {
+		return model.Transaction{}, fmt.Errorf("%w: points to redeem cannot be negative", ErrInvalidLoyalty)
+	}
+	if req.RedeemPoints > 0 && customer == nil {
+		return model.Transaction{}, fmt.Errorf("%w: redeeming points needs a customer", ErrInvalidLoyalty)
+	}
+	if req.GiftCardAmount < 0 {
+		return model.Transaction{}, fmt.Errorf("%w: gift card amount cannot be negative", ErrInvalidGiftCard)
+	}
+	if req.GiftCardAmount > 0 && req.GiftCardCode == "" {
+		return model.Transaction{}, fmt.Errorf("%w: gift card amount needs a gift card code", ErrInvalidGiftCard)
+	}
+
+	draftOrderID, err := parseDraftOrderID(req.DraftOrderID)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	// units held on reserving drafts are not for sale, except by the draft this sale converts.
+	// An offline sale already happened, the terminal could not see the reservations anyway.
+	reserved := map[uuid.UUID]int{}
+	if !req.Offline {
+		if reserved, err = s.draftRepo.FindReservedStocks(outletID, utils.DecodeBase62(req.DraftOrderID)); err != nil {
+			return model.Transaction{}, err
+		}
+	}
 
 	txID, _ := uuid.NewV7()
+	createdAt := time.Now()
+	if req.Offline {
+		txID, createdAt = req.ID, req.OccurredAt
+	}
 	var totalItems int
 	var totalPriceAmount int64
 	var details []model.TransactionDetailEntity
 
 	currency := "IDR"
 	scale := 0
+	lots := &lotAllocator{repo: s.lotRepo, outletID: outletID, now: createdAt, offline: req.Offline, lots: map[uuid.UUID][]model.ProductLotEntity{}}
+	demand := newStockTally(reserved)
 
 	for _, item := range req.Items {
-		product, err := s.productRepo.FindProductByID(utils.DecodeBase62(item.ProductID))
+		product, label, err := s.findItemProduct(item)
 		if err != nil {
 			return model.Transaction{}, err
 		}
+		prices, err := s.priceListRepo.FindProductPrices(product.ID.String())
+		if err != nil {
+			return model.Transaction{}, err
+		}
+		// a scheduled base price replaces the catalog price once it starts, an outlet override and
+		// then the sale's price list still take precedence over it
+		if scheduled := model.EffectiveProductPrice(prices, nil, createdAt); scheduled != nil {
+			product.Price = scheduled.Price
+		}
+		price := product.EffectivePrice()
+		if outletID != nil {
+			if price, err = atOutlet(s.outletRepo, &product, *outletID); err != nil {
+				return model.Transaction{}, err
+			}
+		} else if err := atNoOutlet(s.outletRepo, &product); err != nil {
+			return model.Transaction{}, err
+		}
+		if priceListID != nil {
+			if listed := model.EffectiveProductPrice(prices, priceListID, createdAt); listed != nil {
+				price = listed.Price
+			}
+		}
+		line, err := s.saleLine(product, item, label, price)
+		if err != nil {
+			return model.Transaction{}, err
+		}
+		// a quantity tier only ever lowers the unit price the line would pay otherwise,
+		// it prices the base unit so a line rung up in another unit or from a label keeps its price.
+		// Tiers count whole base units, 2.5 kg of rice reaches the 2 kg tier and not the 2500 one.
+		tiers, err := s.priceListRepo.FindProductPriceTiers(product.ID.String())
+		if err != nil {
+			return model.Transaction{}, err
+		}
+		var tierMinQuantity *int
+		baseQuantity := line.quantity / model.QuantityMultiplier(product.QuantityScale)
+		listedPrice := line.unitPrice
+		if tier := model.ApplicablePriceTier(tiers, baseQuantity); tier != nil && tier.Price <= line.unitPrice && line.inBaseUnit(product) {
+			line.unitPrice, tierMinQuantity = tier.Price, &tier.MinQuantity
+		}
+		// an offline sale was paid at the terminal's prices, the catalog it priced by may be older than ours
+		if req.Offline && item.UnitPrice != nil && line.label == nil {
+			if err := checkTerminalPrice(product, line, *item.UnitPrice, listedPrice); err != nil {
+				return model.Transaction{}, err
+			}
+			if tierMinQuantity != nil && *item.UnitPrice != line.unitPrice {
+				tierMinQuantity = nil
+			}
+			line.unitPrice = *item.UnitPrice
+		}
 
-		if product.Stocks < item.Quantity {
-			return model.Transaction{}, errors.New("insufficient stock for product: " + product.Name)
+		// the lines are checked together, two lines of the same product or of bundles sharing a component
+		// must not pass one by one and then drive the stock below zero
+		demand.consume(product, line.quantity)
+		if name, short := demand.short(); short && !req.Offline {
+			return model.Transaction{}, errors.New("insufficient stock for product: " + name)
+		}
+		allocations, err := lots.allocateProduct(product, line.quantity)
+		if err != nil {
+			return model.Transaction{}, err
 		}
 
 		detailID, _ := uuid.NewV7()
-		itemTotalPrice := product.Price * int64(item.Quantity)
+		itemTotalPrice := line.totalPrice()
+		cost := product.EffectiveCost()
+		multiplier := int64(model.QuantityMultiplier(product.QuantityScale))
 
 		detail := model.TransactionDetailEntity{
 			ID:                detailID,
@@ -62,20 +198,30 @@ func (s *TransactionServiceImpl) CreateTransaction(req model.CreateTransactionRe
 			ProductName:       product.Name,
 			CategoryID:        product.CategoryID,
 			CategoryName:      product.CategoryName,
-			PriceAmount:       product.Price,
+			PriceAmount:       line.unitPrice,
 			PriceScale:        scale,
-			PriceDisplay:      float64(product.Price), // Simple conversion for now
+			PriceDisplay:      float64(line.unitPrice), // Simple conversion for now
 			Currency:          currency,
-			Quantity:          item.Quantity,
+			Quantity:          line.quantity,
 			TotalPriceAmount:  itemTotalPrice,
 			TotalPriceScale:   scale,
 			TotalPriceDisplay: float64(itemTotalPrice),
 			CreatedBy:         "USER",
 			UpdatedBy:         "USER",
+			Components:        product.Components,
+			CostPriceAmount:   cost,
+			TotalCostAmount:   model.RoundDiv(cost*int64(line.quantity), multiplier),
+			Lots:              allocations,
+
+			PriceTierMinQuantity: tierMinQuantity,
+			QuantityScale:        product.QuantityScale,
+			Unit:                 line.unit,
+			UnitQuantity:         model.FromStockQuantity(line.quantity, line.factor),
 		}
 
 		details = append(details, detail)
-		totalItems += item.Quantity
+		// a weighed line counts as one item, 0.75 kg of rice is one thing in the bag
+		totalItems += int((int64(line.quantity) + multiplier - 1) / multiplier)
 		totalPriceAmount += itemTotalPrice
 	}
 
@@ -88,13 +234,32 @@ func (s *TransactionServiceImpl) CreateTransaction(req model.CreateTransactionRe
 		Currency:          currency,
 		CreatedBy:         "USER",
 		UpdatedBy:         "USER",
-		CreatedAt:         time.Now(),
+		CreatedAt:         createdAt,
+		OutletID:          outletID,
+		DraftOrderID:      draftOrderID,
+		PriceListID:       priceListID,
+	}
+	txEntity.StockConflicts = demand.conflicts(txEntity)
+	if shift != nil {
+		txEntity.ShiftID = &shift.ID
+	}
+	if customer != nil {
+		txEntity.CustomerID = &customer.ID
+		if err := s.applyLoyalty(&txEntity, *customer, details, req.RedeemPoints); err != nil {
+			return model.Transaction{}, err
+		}
+	}
+	if req.GiftCardCode != "" {
+		if err := s.applyGiftCard(&txEntity, customer, req.GiftCardCode, req.GiftCardAmount); err != nil {
+			return model.Transaction{}, err
+		}
 	}
 
 	createdTx, err := s.txRepo.CreateTransaction(txEntity, details)
 	if err != nil {
 		return model.Transaction{}, err
 	}
+	s.publishLowStock(details)
 
 	result := createdTx.ToModel()
 	// Populate details for the response
@@ -105,28 +270,534 @@ func (s *TransactionServiceImpl) CreateTransaction(req model.CreateTransactionRe
 	return *result, nil
 }
 
-func (s *TransactionServiceImpl) FetchReport(startDateStr, endDateStr, period string) (model.ReportResponse, error) {
+// findSellingOutlet resolves the outlet the sale is rung up at, only an active outlet can sell
+func (s *TransactionServiceImpl) findSellingOutlet(id string) (*uuid.UUID, error) {
+	outletID, err := parseOutletID(id)
+	if err != nil || outletID == nil {
+		return nil, err
+	}
+	outlet, err := s.outletRepo.FindOutletByID(outletID.String())
+	if err != nil || outlet.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: outlet not found", ErrInvalidOutlet)
+	}
+	if !outlet.IsActive {
+		return nil, fmt.Errorf("%w: outlet %s is not active", ErrInvalidOutlet, outlet.Code)
+	}
+	return outletID, nil
+}
+
+// findOpenShift resolves the shift whose drawer takes the sale, only an open shift can sell
+func (s *TransactionServiceImpl) findOpenShift(id string) (*model.ShiftEntity, error) {
+	if id == "" {
+		return nil, nil
+	}
+	shift, err := s.shiftRepo.FindShiftByID(utils.DecodeBase62(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: shift not found", ErrInvalidShift)
+	}
+	if shift.Status != model.ShiftOpen {
+		return nil, fmt.Errorf("%w: shift is %s", ErrInvalidShift, shift.Status)
+	}
+	return &shift, nil
+}
+
+// parseDraftOrderID parses the Base62 id of the draft order the sale converts, nil when the sale is not one
+func parseDraftOrderID(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(utils.DecodeBase62(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: draft order not found", ErrInvalidDraftOrder)
+	}
+	return &parsed, nil
+}
+
+// findItemProduct resolves the product an item sells, by its id or by the item code of a weighing scale label
+func (s *TransactionServiceImpl) findItemProduct(item model.CreateTransactionItemRequest) (model.ProductEntity, *model.ScaleBarcode, error) {
+	if item.Barcode == "" {
+		product, err := s.productRepo.FindProductByID(utils.DecodeBase62(item.ProductID))
+		return product, nil, err
+	}
+	label, ok := model.ParseScaleBarcode(item.Barcode)
+	if !ok {
+		return model.ProductEntity{}, nil, fmt.Errorf("%w: %s is not a weighing scale barcode", ErrInvalidQuantity, item.Barcode)
+	}
+	product, err := s.productRepo.FindProductBySKU(label.ItemCode)
+	if err != nil {
+		return model.ProductEntity{}, nil, fmt.Errorf("%w: no product has SKU %s", ErrInvalidQuantity, label.ItemCode)
+	}
+	return product, &label, nil
+}
+
+// saleLine is what an item takes from stock and what it costs in the unit it was rung up in
+type saleLine struct {
+	quantity  int    // stock units
+	unit      string // the unit rung up
+	factor    int    // stock units one of unit holds
+	unitPrice int64  // per unit
+	label     *model.ScaleBarcode
+}
+
+func (l saleLine) inBaseUnit(product model.ProductEntity) bool {
+	return l.label == nil && l.unit == product.Unit
+}
+
+func (l saleLine) totalPrice() int64 {
+	if l.label != nil {
+		return l.label.Price
+	}
+	return model.RoundDiv(l.unitPrice*int64(l.quantity), int64(l.factor))
+}
+
+// saleLine converts the item's quantity to stock units, price being the product's price per whole base unit
+func (s *TransactionServiceImpl) saleLine(product model.ProductEntity, item model.CreateTransactionItemRequest, label *model.ScaleBarcode, price int64) (saleLine, error) {
+	multiplier := model.QuantityMultiplier(product.QuantityScale)
+	line := saleLine{unit: product.Unit, factor: multiplier, unitPrice: price, label: label}
+	switch {
+	case item.StockQuantity > 0:
+		line.quantity = item.StockQuantity
+	case label != nil:
+		// the scale weighed the goods at the price per base unit, the weight is what the label's price buys
+		if price <= 0 {
+			return saleLine{}, fmt.Errorf("%w: %s has no price to weigh the label's amount by", ErrInvalidQuantity, product.Name)
+		}
+		line.quantity = int(model.RoundDiv(label.Price*int64(multiplier), price))
+	default:
+		if item.Unit != "" && !strings.EqualFold(item.Unit, product.Unit) {
+			units, err := s.productRepo.FindProductUnits(product.ID.String())
+			if err != nil {
+				return saleLine{}, err
+			}
+			unit := model.FindUnit(units, item.Unit)
+			if unit == nil {
+				return saleLine{}, fmt.Errorf("%w: %s is not sold by the %s", ErrInvalidQuantity, product.Name, item.Unit)
+			}
+			line.unit, line.factor = unit.Name, unit.Factor
+			line.unitPrice = model.RoundDiv(price*int64(unit.Factor), int64(multiplier))
+			if unit.Price != nil {
+				line.unitPrice = *unit.Price
+			}
+		}
+		quantity, ok := model.ToStockQuantity(item.Quantity, line.factor)
+		if !ok {
+			return saleLine{}, fmt.Errorf("%w: %v %s of %s is finer than %d decimals of %s", ErrInvalidQuantity, item.Quantity, line.unit, product.Name, product.QuantityScale, product.Unit)
+		}
+		line.quantity = quantity
+	}
+	if line.quantity <= 0 {
+		return saleLine{}, fmt.Errorf("%w: quantity of %s must be greater than zero", ErrInvalidQuantity, product.Name)
+	}
+	return line, nil
+}
+
+// offlinePriceSpread is how far off the server's price an offline terminal's price may be, either way.
+// A terminal that missed a price change charges the old price, one that is further off is not trusted.
+const offlinePriceSpread = 2
+
+// checkTerminalPrice refuses a price an offline terminal charged that is negative or more than
+// offlinePriceSpread times off the price the server lists for the line's unit
+func checkTerminalPrice(product model.ProductEntity, line saleLine, charged, listed int64) error {
+	if charged < 0 || charged > listed*offlinePriceSpread || charged*offlinePriceSpread < listed {
+		return fmt.Errorf("%w: %s was charged %d per %s, too far off its price of %d", ErrInvalidSync, product.Name, charged, line.unit, listed)
+	}
+	return nil
+}
+
+// findPriceList resolves the price list the sale is priced by, the one asked for or else the customer's.
+// Items the list has no price for, or a sale without a list, pay the base price.
+func (s *TransactionServiceImpl) findPriceList(id string, customer *model.CustomerEntity, offline bool) (*uuid.UUID, error) {
+	if id == "" {
+		if customer == nil || customer.PriceListID == nil {
+			return nil, nil
+		}
+		priceList, err := s.priceListRepo.FindPriceListByID(customer.PriceListID.String())
+		if err != nil || !priceList.CanPrice() {
+			return nil, nil
+		}
+		return &priceList.ID, nil
+	}
+
+	priceList, err := s.priceListRepo.FindPriceListByID(utils.DecodeBase62(id))
+	if err != nil || priceList.ID == uuid.Nil || priceList.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: price list not found", ErrInvalidPriceList)
+	}
+	// an offline sale already happened at the list's prices, even if the list was deactivated since
+	if !priceList.IsActive && !offline {
+		return nil, fmt.Errorf("%w: price list %s is not active", ErrInvalidPriceList, priceList.Code)
+	}
+	return &priceList.ID, nil
+}
+
+// findCustomer resolves the customer the sale is attached to, a sale without one stays anonymous
+func (s *TransactionServiceImpl) findCustomer(id string) (*model.CustomerEntity, error) {
+	if id == "" {
+		return nil, nil
+	}
+	customer, err := s.customerRepo.FindCustomerByID(utils.DecodeBase62(id))
+	if err != nil || customer.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: customer not found", ErrInvalidCustomer)
+	}
+	return &customer, nil
+}
+
+// applyLoyalty takes the redeemed points off the sale and adds the ledger entries for them and for the points
+// the rest of the sale earns. The repository books the entries with the sale so both happen or neither does.
+func (s *TransactionServiceImpl) applyLoyalty(tx *model.TransactionEntity, customer model.CustomerEntity, details []model.TransactionDetailEntity, redeemPoints int) error {
+	program, err := s.loyaltyRepo.FindLoyaltyProgram()
+	if err != nil {
+		return err
+	}
+
+	if redeemPoints > 0 {
+		if !program.IsActive {
+			return fmt.Errorf("%w: the loyalty program is not active", ErrInvalidLoyalty)
+		}
+		balance, err := expirePoints(s.loyaltyRepo, customer, tx.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if redeemPoints > balance {
+			return fmt.Errorf("%w: customer holds %d points", ErrInvalidLoyalty, balance)
+		}
+		discount := int64(redeemPoints) * program.PointValue
+		if discount > tx.TotalPriceAmount {
+			return fmt.Errorf("%w: at most %d points can be redeemed on this sale", ErrInvalidLoyalty, tx.TotalPriceAmount/program.PointValue)
+		}
+
+		tx.PointsRedeemed = redeemPoints
+		tx.PointsDiscountAmount = discount
+		tx.TotalPriceAmount -= discount
+		tx.TotalPriceDisplay = float64(tx.TotalPriceAmount)
+		// the lines carry the discount too, or the reports built on them would count revenue never taken
+		model.SpreadPointsDiscount(details, discount)
+		id, _ := uuid.NewV7()
+		tx.LoyaltyEntries = append(tx.LoyaltyEntries, model.LoyaltyEntryEntity{
+			ID:            id,
+			CustomerID:    customer.ID,
+			Type:          model.LoyaltyEntryRedeem,
+			Points:        -redeemPoints,
+			TransactionID: &tx.ID,
+			CreatedBy:     tx.CreatedBy,
+		})
+	}
+
+	if earned := program.EarnedPoints(details, tx.TotalPriceAmount); earned > 0 {
+		tx.PointsEarned = earned
+		id, _ := uuid.NewV7()
+		tx.LoyaltyEntries = append(tx.LoyaltyEntries, model.LoyaltyEntryEntity{
+			ID:            id,
+			CustomerID:    customer.ID,
+			Type:          model.LoyaltyEntryEarn,
+			Points:        earned,
+			TransactionID: &tx.ID,
+			ExpiresAt:     program.ExpiresAt(tx.CreatedAt),
+			CreatedBy:     tx.CreatedBy,
+		})
+	}
+	return nil
+}
+
+// applyGiftCard pays part of the sale from the card, all of it the card covers when amount is zero.
+// The repository books the redemption with the sale, so a card spent meanwhile fails the whole sale.
+func (s *TransactionServiceImpl) applyGiftCard(tx *model.TransactionEntity, customer *model.CustomerEntity, code string, amount int64) error {
+	card, err := s.giftCardRepo.FindGiftCardByCode(model.NormalizeGiftCardCode(code))
+	if err != nil {
+		return err
+	}
+	if card.ID == uuid.Nil {
+		return fmt.Errorf("%w: gift card not found", ErrInvalidGiftCard)
+	}
+	if !card.IsRedeemable(tx.CreatedAt) {
+		return fmt.Errorf("%w: gift card %s is inactive or expired", ErrInvalidGiftCard, card.Code)
+	}
+	if card.CustomerID != nil && (customer == nil || customer.ID != *card.CustomerID) {
+		return fmt.Errorf("%w: store credit can only pay for its holder's sales", ErrInvalidGiftCard)
+	}
+
+	if amount == 0 {
+		amount = min(card.Balance, tx.TotalPriceAmount)
+	}
+	if amount > card.Balance {
+		return fmt.Errorf("%w: gift card holds %d", ErrInvalidGiftCard, card.Balance)
+	}
+	if amount > tx.TotalPriceAmount {
+		return fmt.Errorf("%w: at most %d of this sale can be paid by gift card", ErrInvalidGiftCard, tx.TotalPriceAmount)
+	}
+	if amount == 0 {
+		return fmt.Errorf("%w: gift card %s has no balance left", ErrInvalidGiftCard, card.Code)
+	}
+
+	id, _ := uuid.NewV7()
+	tx.GiftCardID = &card.ID
+	tx.GiftCardAmount = amount
+	tx.GiftCardEntry = &model.GiftCardEntryEntity{
+		ID:            id,
+		GiftCardID:    card.ID,
+		Type:          model.GiftCardEntryRedeem,
+		Amount:        -amount,
+		TransactionID: &tx.ID,
+		CreatedBy:     tx.CreatedBy,
+	}
+	return nil
+}
+
+// atOutlet swaps the product's stock, or each component's for a bundle, for what the outlet holds
+// and returns the outlet's price, which is the product price unless the outlet overrides it
+func atOutlet(outletRepo repository.OutletRepository, product *model.ProductEntity, outletID uuid.UUID) (int64, error) {
+	stock, err := outletRepo.FindOutletStock(outletID.String(), product.ID.String())
+	if err != nil {
+		return 0, err
+	}
+	if product.IsBundle() {
+		product.Components = append([]model.BundleComponentEntity(nil), product.Components...)
+		for i, c := range product.Components {
+			componentStock, err := outletRepo.FindOutletStock(outletID.String(), c.ComponentID.String())
+			if err != nil {
+				return 0, err
+			}
+			product.Components[i].ComponentStocks = componentStock.Stock
+		}
+	} else {
+		product.Stocks = stock.Stock
+	}
+
+	if stock.PriceOverride != nil {
+		return *stock.PriceOverride, nil
+	}
+	return product.EffectivePrice(), nil
+}
+
+// atNoOutlet swaps the product's stock, or each component's for a bundle, for what is held at no outlet.
+// The units the outlets hold are theirs to sell, a sale rung up at no outlet cannot take them.
+func atNoOutlet(outletRepo repository.OutletRepository, product *model.ProductEntity) error {
+	if !product.IsBundle() {
+		held, err := outletRepo.FindStockHeldAtOutlets(product.ID.String())
+		if err != nil {
+			return err
+		}
+		product.Stocks -= held
+		return nil
+	}
+
+	product.Components = append([]model.BundleComponentEntity(nil), product.Components...)
+	for i, c := range product.Components {
+		held, err := outletRepo.FindStockHeldAtOutlets(c.ComponentID.String())
+		if err != nil {
+			return err
+		}
+		product.Components[i].ComponentStocks -= held
+	}
+	return nil
+}
+
+// lotAllocator hands out FEFO allocations for one transaction, it caches the open lots
+// per product so two lines selling the same product never take the same units.
+// Only the lots held where the stock is sold from are drawn, like the stock they are checked against.
+type lotAllocator struct {
+	repo     repository.LotRepository
+	outletID *uuid.UUID
+	now      time.Time
+	offline  bool // an offline sale already happened, it is allocated what the lots hold instead of refused
+	lots     map[uuid.UUID][]model.ProductLotEntity
+}
+
+// allocateProduct allocates the product itself, or each component when it is a bundle
+func (a *lotAllocator) allocateProduct(product model.ProductEntity, quantity int) ([]model.LotAllocationEntity, error) {
+	if !product.IsBundle() {
+		return a.allocate(product.ID, product.Name, product.Stocks, quantity)
+	}
+
+	var allocations []model.LotAllocationEntity
+	for _, c := range product.Components {
+		componentAllocations, err := a.allocate(c.ComponentID, c.ComponentName, c.ComponentStocks, quantity*c.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, componentAllocations...)
+	}
+	return allocations, nil
+}
+
+// allocate refuses the sale when only expired lots could cover it, products without lots pass untouched
+func (a *lotAllocator) allocate(productID uuid.UUID, name string, stock, quantity int) ([]model.LotAllocationEntity, error) {
+	lots, ok := a.lots[productID]
+	if !ok {
+		found, err := a.repo.FindOpenLotsByProductID(productID.String(), a.outletID)
+		if err != nil {
+			return nil, err
+		}
+		lots = found
+		a.lots[productID] = lots
+	}
+	if len(lots) == 0 {
+		return nil, nil
+	}
+
+	if !a.offline && stock-model.ExpiredQuantity(lots, a.now) < quantity {
+		return nil, errors.New("insufficient unexpired stock for product: " + name)
+	}
+	return model.AllocateFEFO(lots, quantity, a.now), nil
+}
+
+// stockTally adds up what the lines of a sale take from each product holding stock, a bundle's lines
+// taking from its components, to find the units sold beyond the stock on the books. A sale rung up here
+// is refused once a product runs short, an offline sale books the shortfall as conflicts.
+type stockTally struct {
+	productIDs []uuid.UUID
+	names      map[uuid.UUID]string
+	stocks     map[uuid.UUID]int
+	consumed   map[uuid.UUID]int
+	reserved   map[uuid.UUID]int // units held for draft orders, not for sale
+}
+
+func newStockTally(reserved map[uuid.UUID]int) *stockTally {
+	return &stockTally{names: map[uuid.UUID]string{}, stocks: map[uuid.UUID]int{}, consumed: map[uuid.UUID]int{}, reserved: reserved}
+}
+
+// consume takes quantity of the product, its stock being the outlet's when the sale is at one
+func (o *stockTally) consume(product model.ProductEntity, quantity int) {
+	if !product.IsBundle() {
+		o.take(product.ID, product.Name, product.Stocks, quantity)
+		return
+	}
+	for _, c := range product.Components {
+		o.take(c.ComponentID, c.ComponentName, c.ComponentStocks, quantity*c.Quantity)
+	}
+}
+
+func (o *stockTally) take(productID uuid.UUID, name string, stock, quantity int) {
+	if _, ok := o.stocks[productID]; !ok {
+		o.productIDs = append(o.productIDs, productID)
+		o.names[productID] = name
+		o.stocks[productID] = stock - o.reserved[productID]
+	}
+	o.consumed[productID] += quantity
+}
+
+// short returns the first product the lines so far take more of than it holds
+func (o *stockTally) short() (string, bool) {
+	for _, productID := range o.productIDs {
+		if o.consumed[productID] > o.stocks[productID] {
+			return o.names[productID], true
+		}
+	}
+	return "", false
+}
+
+// conflicts returns a conflict for every product the sale takes more of than it holds
+func (o *stockTally) conflicts(tx model.TransactionEntity) []model.StockConflictEntity {
+	var conflicts []model.StockConflictEntity
+	for _, productID := range o.productIDs {
+		short := o.consumed[productID] - o.stocks[productID]
+		if short <= 0 {
+			continue
+		}
+		id, _ := uuid.NewV7()
+		conflicts = append(conflicts, model.StockConflictEntity{
+			ID:            id,
+			TransactionID: tx.ID,
+			ProductID:     productID,
+			ProductName:   o.names[productID],
+			OutletID:      tx.OutletID,
+			Quantity:      short,
+			CreatedBy:     tx.CreatedBy,
+		})
+	}
+	return conflicts
+}
+
+// publishLowStock emits product.low_stock for every product this sale pushed to or below its reorder point.
+// Products that were already low before the sale are skipped so the owner is alerted once per crossing.
+func (s *TransactionServiceImpl) publishLowStock(details []model.TransactionDetailEntity) {
+	consumed := map[uuid.UUID]int{}
+	var productIDs []uuid.UUID
+	consume := func(id uuid.UUID, quantity int) {
+		if _, ok := consumed[id]; !ok {
+			productIDs = append(productIDs, id)
+		}
+		consumed[id] += quantity
+	}
+	for _, d := range details {
+		if len(d.Components) == 0 {
+			consume(*d.ProductID, d.Quantity)
+			continue
+		}
+		for _, c := range d.Components {
+			consume(c.ComponentID, c.Quantity*d.Quantity)
+		}
+	}
+
+	for _, id := range productIDs {
+		product, err := s.productRepo.FindProductByID(id.String())
+		if err != nil || !product.IsLowStock() {
+			continue
+		}
+		if product.Stocks+consumed[id] <= product.ReorderPoint {
+			continue
+		}
+		s.publisher.Publish(event.New(event.ProductLowStock, product.ToLowStockAlert()))
+	}
+}
+
+func (s *TransactionServiceImpl) FetchReport(startDateStr, endDateStr, period, outletIDStr string) (model.ReportResponse, error) {
 	startDate, endDate := s.parseDateRange(startDateStr, endDateStr, period)
 	if startDate.After(endDate) {
 		return model.ReportResponse{}, errors.New("startDate cannot be after endDate")
 	}
-	return s.txRepo.GetReportStats(startDate, endDate)
+	outletID, err := parseOutletID(outletIDStr)
+	if err != nil {
+		return model.ReportResponse{}, err
+	}
+	return s.txRepo.GetReportStats(startDate, endDate, outletID)
 }
 
-func (s *TransactionServiceImpl) FetchMostPopularCategory(startDateStr, endDateStr string) (model.PopularCategory, error) {
+func (s *TransactionServiceImpl) FetchMostPopularCategory(startDateStr, endDateStr, outletIDStr, levelStr string) (model.PopularCategory, error) {
 	startDate, endDate := s.parseDateRange(startDateStr, endDateStr, "")
 	if startDate.After(endDate) {
 		return model.PopularCategory{}, errors.New("startDate cannot be after endDate")
 	}
-	return s.txRepo.GetMostPopularCategory(startDate, endDate)
+	outletID, err := parseOutletID(outletIDStr)
+	if err != nil {
+		return model.PopularCategory{}, err
+	}
+	var level *int
+	if levelStr != "" {
+		parsed, err := strconv.Atoi(levelStr)
+		if err != nil || parsed < 0 {
+			return model.PopularCategory{}, fmt.Errorf("%w: level must be 0 or more", ErrInvalidCategory)
+		}
+		level = &parsed
+	}
+	return s.txRepo.GetMostPopularCategory(startDate, endDate, outletID, level)
 }
 
-func (s *TransactionServiceImpl) FetchMostPopularProduct(startDateStr, endDateStr string) (model.PopularItem, error) {
+func (s *TransactionServiceImpl) FetchMostPopularProduct(startDateStr, endDateStr, outletIDStr string) (model.PopularItem, error) {
 	startDate, endDate := s.parseDateRange(startDateStr, endDateStr, "")
 	if startDate.After(endDate) {
 		return model.PopularItem{}, errors.New("startDate cannot be after endDate")
 	}
-	return s.txRepo.GetMostPopularProduct(startDate, endDate)
+	outletID, err := parseOutletID(outletIDStr)
+	if err != nil {
+		return model.PopularItem{}, err
+	}
+	return s.txRepo.GetMostPopularProduct(startDate, endDate, outletID)
+}
+
+func (s *TransactionServiceImpl) FetchMarginReport(startDateStr, endDateStr, period, outletIDStr string) (model.MarginReport, error) {
+	startDate, endDate := s.parseDateRange(startDateStr, endDateStr, period)
+	if startDate.After(endDate) {
+		return model.MarginReport{}, errors.New("startDate cannot be after endDate")
+	}
+	outletID, err := parseOutletID(outletIDStr)
+	if err != nil {
+		return model.MarginReport{}, err
+	}
+	margins, err := s.txRepo.GetSalesMargins(startDate, endDate, outletID)
+	if err != nil {
+		return model.MarginReport{}, err
+	}
+	return *model.NewMarginReport(startDate, endDate, margins), nil
 }
 
 func (s *TransactionServiceImpl) parseDateRange(startDateStr, endDateStr, period string) (time.Time, time.Time) {
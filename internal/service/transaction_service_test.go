@@ -1,9 +1,12 @@
 package service
 
 import (
+	"codewithumam-kasir-api/internal/event"
 	"codewithumam-kasir-api/internal/mock"
 	"codewithumam-kasir-api/internal/model"
+	"codewithumam-kasir-api/internal/repository"
 	"codewithumam-kasir-api/internal/utils"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	testifyMock "github.com/stretchr/testify/mock"
@@ -14,7 +17,7 @@ import (
 func TestTransactionService_CreateTransaction(t *testing.T) {
 	mockTxRepo := new(mock.MockTransactionRepository)
 	mockProductRepo := new(mock.MockProductRepository)
-	service := NewTransactionService(mockTxRepo, mockProductRepo)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
 
 	productID, _ := uuid.NewV7()
 	product := model.ProductEntity{
@@ -48,7 +51,7 @@ func TestTransactionService_CreateTransaction(t *testing.T) {
 func TestTransactionService_CreateTransaction_InsufficientStock(t *testing.T) {
 	mockTxRepo := new(mock.MockTransactionRepository)
 	mockProductRepo := new(mock.MockProductRepository)
-	service := NewTransactionService(mockTxRepo, mockProductRepo)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
 
 	productID, _ := uuid.NewV7()
 	product := model.ProductEntity{
@@ -74,14 +77,48 @@ func TestTransactionService_CreateTransaction_InsufficientStock(t *testing.T) {
 	assert.Contains(t, err.Error(), "insufficient stock")
 }
 
+func TestTransactionService_CreateTransaction_InsufficientStockAcrossLines(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
+
+	kopi, gula, pagi, sore := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", kopi.String()).Return(model.ProductEntity{ID: kopi, Name: "Kopi", Price: 10000, Stocks: 3}, nil)
+	mockProductRepo.On("FindProductByID", gula.String()).Return(model.ProductEntity{ID: gula, Name: "Gula", Price: 2000, Stocks: 3}, nil)
+	// two bundles sharing the sugar, each fits on its own but not both
+	for _, id := range []uuid.UUID{pagi, sore} {
+		mockProductRepo.On("FindProductByID", id.String()).Return(model.ProductEntity{
+			ID: id, Name: "Paket", Price: 15000, Type: model.ProductTypeBundle,
+			Components: []model.BundleComponentEntity{{ComponentID: gula, ComponentName: "Gula", ComponentStocks: 3, Quantity: 2}},
+		}, nil)
+	}
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+	line := func(id uuid.UUID, quantity float64) model.CreateTransactionItemRequest {
+		return model.CreateTransactionItemRequest{ProductID: utils.EncodeBase62(id.String()), Quantity: quantity}
+	}
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{line(kopi, 2), line(kopi, 2)}})
+	assert.EqualError(t, err, "insufficient stock for product: Kopi", "the same product on two lines")
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{line(pagi, 1), line(sore, 1)}})
+	assert.EqualError(t, err, "insufficient stock for product: Gula", "bundles sharing a component")
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{line(pagi, 1), line(gula, 2)}})
+	assert.EqualError(t, err, "insufficient stock for product: Gula", "a bundle and its component sold loose")
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{line(kopi, 2), line(kopi, 1), line(pagi, 1), line(gula, 1)}})
+	assert.NoError(t, err, "everything fits when added up")
+	mockTxRepo.AssertNumberOfCalls(t, "CreateTransaction", 1)
+}
+
 func TestTransactionService_FetchReport(t *testing.T) {
 	mockTxRepo := new(mock.MockTransactionRepository)
 	mockProductRepo := new(mock.MockProductRepository)
-	service := NewTransactionService(mockTxRepo, mockProductRepo)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
 
-	mockTxRepo.On("GetReportStats", testifyMock.Anything, testifyMock.Anything).Return(model.ReportResponse{TotalTransactions: 5}, nil)
+	mockTxRepo.On("GetReportStats", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(model.ReportResponse{TotalTransactions: 5}, nil)
 
-	resp, err := service.FetchReport("", "", "today")
+	resp, err := service.FetchReport("", "", "today", "")
 
 	assert.NoError(t, err)
 	assert.Equal(t, 5, resp.TotalTransactions)
@@ -90,18 +127,37 @@ func TestTransactionService_FetchReport(t *testing.T) {
 func TestTransactionService_Reports(t *testing.T) {
 	mockTxRepo := new(mock.MockTransactionRepository)
 	mockProductRepo := new(mock.MockProductRepository)
-	service := NewTransactionService(mockTxRepo, mockProductRepo)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
 
-	mockTxRepo.On("GetMostPopularCategory", testifyMock.Anything, testifyMock.Anything).Return(model.PopularCategory{Name: "Cat"}, nil)
-	mockTxRepo.On("GetMostPopularProduct", testifyMock.Anything, testifyMock.Anything).Return(model.PopularItem{Name: "Prod"}, nil)
+	mockTxRepo.On("GetMostPopularCategory", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(model.PopularCategory{Name: "Cat"}, nil)
+	mockTxRepo.On("GetMostPopularProduct", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return(model.PopularItem{Name: "Prod"}, nil)
 
-	cat, _ := service.FetchMostPopularCategory("", "")
-	prod, _ := service.FetchMostPopularProduct("", "")
+	cat, _ := service.FetchMostPopularCategory("", "", "", "")
+	prod, _ := service.FetchMostPopularProduct("", "", "")
 
 	assert.Equal(t, "Cat", cat.Name)
 	assert.Equal(t, "Prod", prod.Name)
 }
 
+func TestTransactionService_FetchMostPopularCategory_RollsUpToLevel(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo})
+
+	level := 0
+	mockTxRepo.On("GetMostPopularCategory", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything, &level).
+		Return(model.PopularCategory{Name: "Beverages", Path: []string{"Beverages"}, TotalSoldQty: 40}, nil)
+
+	category, err := service.FetchMostPopularCategory("", "", "", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Beverages", category.Name)
+
+	for _, invalid := range []string{"-1", "top"} {
+		_, err = service.FetchMostPopularCategory("", "", "", invalid)
+		assert.ErrorIs(t, err, ErrInvalidCategory)
+	}
+	mockTxRepo.AssertNumberOfCalls(t, "GetMostPopularCategory", 1)
+}
+
 func TestTransactionService_ParseDateRange(t *testing.T) {
 	mockTxRepo := new(mock.MockTransactionRepository)
 	mockProductRepo := new(mock.MockProductRepository)
@@ -185,9 +241,855 @@ func TestTransactionService_ParseDateRange(t *testing.T) {
 func TestTransactionService_FetchReport_InvalidDateRange(t *testing.T) {
 	mockTxRepo := new(mock.MockTransactionRepository)
 	mockProductRepo := new(mock.MockProductRepository)
-	service := NewTransactionService(mockTxRepo, mockProductRepo)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
 
-	_, err := service.FetchReport("2024-01-02", "2024-01-01", "")
+	_, err := service.FetchReport("2024-01-02", "2024-01-01", "", "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "startDate cannot be after endDate")
 }
+
+func TestTransactionService_CreateTransaction_Bundle(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
+
+	bundleID, _ := uuid.NewV7()
+	componentID, _ := uuid.NewV7()
+	bundle := model.ProductEntity{
+		ID:            bundleID,
+		Name:          "Paket Hemat",
+		Type:          model.ProductTypeBundle,
+		BundlePricing: model.BundlePricingDerived,
+		Components: []model.BundleComponentEntity{
+			{BundleID: bundleID, ComponentID: componentID, ComponentName: "Nasi", ComponentPrice: 5000, ComponentStocks: 5, Quantity: 2},
+		},
+	}
+
+	mockProductRepo.On("FindProductByID", bundleID.String()).Return(bundle, nil)
+	mockProductRepo.On("FindProductByID", componentID.String()).Return(model.ProductEntity{ID: componentID, Name: "Nasi", Stocks: 1}, nil)
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.MatchedBy(func(details []model.TransactionDetailEntity) bool {
+		return len(details) == 1 && len(details[0].Components) == 1 && details[0].TotalPriceAmount == 20000
+	})).Return(model.TransactionEntity{ID: bundleID}, nil)
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{
+		Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(bundleID.String()), Quantity: 2}},
+	})
+	assert.NoError(t, err)
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{
+		Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(bundleID.String()), Quantity: 3}},
+	})
+	assert.ErrorContains(t, err, "insufficient stock")
+	mockTxRepo.AssertNumberOfCalls(t, "CreateTransaction", 1)
+}
+
+func TestTransactionService_CreateTransaction_PublishesLowStock(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockPublisher := new(mock.MockPublisher)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, publisher: mockPublisher})
+
+	crossingID, _ := uuid.NewV7()
+	alreadyLowID, _ := uuid.NewV7()
+	crossing := model.ProductEntity{ID: crossingID, Name: "Kopi", Price: 1000, Stocks: 6, ReorderPoint: 5, ReorderQuantity: 20}
+	alreadyLow := model.ProductEntity{ID: alreadyLowID, Name: "Gula", Price: 1000, Stocks: 3, ReorderPoint: 5}
+
+	mockProductRepo.On("FindProductByID", crossingID.String()).Return(crossing, nil).Once()
+	mockProductRepo.On("FindProductByID", alreadyLowID.String()).Return(alreadyLow, nil).Once()
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Return(model.TransactionEntity{ID: crossingID}, nil)
+
+	crossing.Stocks = 4
+	alreadyLow.Stocks = 2
+	mockProductRepo.On("FindProductByID", crossingID.String()).Return(crossing, nil).Once()
+	mockProductRepo.On("FindProductByID", alreadyLowID.String()).Return(alreadyLow, nil).Once()
+	mockPublisher.On("Publish", testifyMock.MatchedBy(func(e event.Event) bool {
+		alert, ok := e.Payload.(*model.LowStockAlert)
+		return e.Type == event.ProductLowStock && ok && alert.Name == "Kopi" && alert.Stocks == 4 && alert.ReorderQuantity == 20
+	})).Once()
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{
+		Items: []model.CreateTransactionItemRequest{
+			{ProductID: utils.EncodeBase62(crossingID.String()), Quantity: 2},
+			{ProductID: utils.EncodeBase62(alreadyLowID.String()), Quantity: 1},
+		},
+	})
+
+	assert.NoError(t, err)
+	mockPublisher.AssertExpectations(t)
+	mockProductRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransaction_SnapshotsCost(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
+
+	productID, _ := uuid.NewV7()
+	product := model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, CostPrice: 4000, Stocks: 10}
+
+	mockProductRepo.On("FindProductByID", productID.String()).Return(product, nil)
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.MatchedBy(func(details []model.TransactionDetailEntity) bool {
+		return len(details) == 1 && details[0].CostPriceAmount == 4000 && details[0].TotalCostAmount == 12000
+	})).Return(model.TransactionEntity{ID: productID}, nil)
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{
+		Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 3}},
+	})
+
+	assert.NoError(t, err)
+	mockTxRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_FetchMarginReport(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo})
+
+	mockTxRepo.On("GetSalesMargins", testifyMock.Anything, testifyMock.Anything, testifyMock.Anything).Return([]model.SalesMarginEntity{
+		{ProductName: "Kopi", CategoryName: "Minuman", Quantity: 2, Revenue: 20000, COGS: 8000},
+	}, nil)
+
+	report, err := service.FetchMarginReport("", "", "month-to-date", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12000), report.GrossProfit)
+	assert.Equal(t, 60.0, report.MarginPercent)
+	assert.Len(t, report.Categories, 1)
+}
+
+func TestTransactionService_FetchMarginReport_InvalidDateRange(t *testing.T) {
+	service := newTransactionServiceWithMocks(transactionServiceMocks{})
+
+	_, err := service.FetchMarginReport("2026-02-01", "2026-01-01", "", "")
+
+	assert.EqualError(t, err, "startDate cannot be after endDate")
+}
+
+// transactionServiceMocks are the collaborators of the transaction service under test,
+// the ones a test leaves out have no expectations or the defaults below
+type transactionServiceMocks struct {
+	txRepo        repository.TransactionRepository
+	productRepo   repository.ProductRepository
+	lotRepo       repository.LotRepository
+	outletRepo    repository.OutletRepository
+	customerRepo  repository.CustomerRepository
+	loyaltyRepo   repository.LoyaltyRepository
+	giftCardRepo  repository.GiftCardRepository
+	shiftRepo     repository.ShiftRepository
+	draftRepo     repository.DraftOrderRepository
+	priceListRepo repository.PriceListRepository
+	publisher     event.Publisher
+}
+
+func newTransactionServiceWithMocks(m transactionServiceMocks) TransactionService {
+	if m.txRepo == nil {
+		m.txRepo = new(mock.MockTransactionRepository)
+	}
+	if m.productRepo == nil {
+		m.productRepo = new(mock.MockProductRepository)
+	}
+	if m.lotRepo == nil {
+		m.lotRepo = emptyLotRepository()
+	}
+	if m.outletRepo == nil {
+		m.outletRepo = noOutletStock()
+	}
+	if m.customerRepo == nil {
+		m.customerRepo = new(mock.MockCustomerRepository)
+	}
+	if m.loyaltyRepo == nil {
+		m.loyaltyRepo = new(mock.MockLoyaltyRepository)
+	}
+	if m.giftCardRepo == nil {
+		m.giftCardRepo = new(mock.MockGiftCardRepository)
+	}
+	if m.shiftRepo == nil {
+		m.shiftRepo = new(mock.MockShiftRepository)
+	}
+	if m.draftRepo == nil {
+		m.draftRepo = noReservations()
+	}
+	if m.priceListRepo == nil {
+		m.priceListRepo = noScheduledPrices()
+	}
+	if m.publisher == nil {
+		m.publisher = new(mock.MockPublisher)
+	}
+	return NewTransactionService(m.txRepo, m.productRepo, m.lotRepo, m.outletRepo, m.customerRepo, m.loyaltyRepo,
+		m.giftCardRepo, m.shiftRepo, m.draftRepo, m.priceListRepo, m.publisher)
+}
+
+// emptyLotRepository has no lots for any product, sales skip FEFO
+func emptyLotRepository() *mock.MockLotRepository {
+	repo := new(mock.MockLotRepository)
+	repo.On("FindOpenLotsByProductID", testifyMock.Anything, testifyMock.Anything).Return([]model.ProductLotEntity{}, nil)
+	return repo
+}
+
+// noOutletStock is a store without outlet stock, everything is held at no outlet
+func noOutletStock() *mock.MockOutletRepository {
+	repo := new(mock.MockOutletRepository)
+	repo.On("FindStockHeldAtOutlets", testifyMock.Anything).Return(0, nil)
+	return repo
+}
+
+func noReservations() *mock.MockDraftOrderRepository {
+	repo := new(mock.MockDraftOrderRepository)
+	repo.On("FindReservedStocks", testifyMock.Anything, testifyMock.Anything).Return(map[uuid.UUID]int{}, nil)
+	return repo
+}
+
+func noScheduledPrices() *mock.MockPriceListRepository {
+	repo := new(mock.MockPriceListRepository)
+	repo.On("FindProductPrices", testifyMock.Anything).Return([]model.ProductPriceEntity{}, nil)
+	repo.On("FindProductPriceTiers", testifyMock.Anything).Return([]model.ProductPriceTierEntity{}, nil)
+	return repo
+}
+
+func TestTransactionService_CreateTransaction_AllocatesLotsFEFO(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockLotRepo := new(mock.MockLotRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, lotRepo: mockLotRepo})
+
+	productID, _ := uuid.NewV7()
+	product := model.ProductEntity{ID: productID, Name: "Susu", Price: 8000, Stocks: 12}
+	today := time.Now()
+	expired := model.ProductLotEntity{ID: uuid.New(), ProductID: productID, ExpiryDate: today.AddDate(0, 0, -1), QuantityRemaining: 2}
+	later := model.ProductLotEntity{ID: uuid.New(), ProductID: productID, ExpiryDate: today.AddDate(0, 0, 10), QuantityRemaining: 5}
+	sooner := model.ProductLotEntity{ID: uuid.New(), ProductID: productID, ExpiryDate: today, QuantityRemaining: 3}
+
+	mockProductRepo.On("FindProductByID", productID.String()).Return(product, nil)
+	mockLotRepo.On("FindOpenLotsByProductID", productID.String(), (*uuid.UUID)(nil)).Return([]model.ProductLotEntity{expired, later, sooner}, nil).Once()
+
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: productID}, nil)
+
+	item := model.CreateTransactionItemRequest{ProductID: utils.EncodeBase62(productID.String()), Quantity: 2}
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{item, item}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.LotAllocationEntity{{LotID: sooner.ID, ProductID: productID, Quantity: 2}}, details[0].Lots)
+	assert.Equal(t, []model.LotAllocationEntity{
+		{LotID: sooner.ID, ProductID: productID, Quantity: 1},
+		{LotID: later.ID, ProductID: productID, Quantity: 1},
+	}, details[1].Lots, "the second line continues where the first left off")
+	mockLotRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransaction_BlocksExpiredLots(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockLotRepo := new(mock.MockLotRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, lotRepo: mockLotRepo})
+
+	productID, _ := uuid.NewV7()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Roti", Stocks: 4}, nil)
+	mockLotRepo.On("FindOpenLotsByProductID", productID.String(), (*uuid.UUID)(nil)).Return([]model.ProductLotEntity{
+		{ID: uuid.New(), ProductID: productID, ExpiryDate: time.Now().AddDate(0, 0, -2), QuantityRemaining: 3},
+	}, nil)
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{
+		Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 2}},
+	})
+
+	assert.EqualError(t, err, "insufficient unexpired stock for product: Roti")
+	mockTxRepo.AssertNotCalled(t, "CreateTransaction", testifyMock.Anything, testifyMock.Anything)
+}
+
+func TestTransactionService_CreateTransaction_IgnoresLotsAtOtherOutlets(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockLotRepo := new(mock.MockLotRepository)
+	mockOutletRepo := new(mock.MockOutletRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, lotRepo: mockLotRepo, outletRepo: mockOutletRepo})
+
+	outletID, otherID, productID := uuid.New(), uuid.New(), uuid.New()
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, Code: "JKT", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletStock", outletID.String(), productID.String()).Return(model.OutletStockEntity{Stock: 3}, nil)
+	// the product holds 8 units, 5 of them in an expired lot at the other outlet
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Roti", Price: 5000, Stocks: 8}, nil)
+	fresh := model.ProductLotEntity{ID: uuid.New(), ProductID: productID, OutletID: &outletID, ExpiryDate: time.Now().AddDate(0, 0, 3), QuantityRemaining: 3}
+	mockLotRepo.On("FindOpenLotsByProductID", productID.String(), &outletID).Return([]model.ProductLotEntity{fresh}, nil)
+	mockLotRepo.On("FindOpenLotsByProductID", productID.String(), testifyMock.Anything).Return([]model.ProductLotEntity{
+		{ID: uuid.New(), ProductID: productID, OutletID: &otherID, ExpiryDate: time.Now().AddDate(0, 0, -2), QuantityRemaining: 5},
+	}, nil)
+
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: productID}, nil)
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{
+		OutletID: utils.EncodeBase62(outletID.String()),
+		Items:    []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 3}},
+	})
+
+	assert.NoError(t, err, "the other outlet's expired units do not count against this outlet's stock")
+	assert.Equal(t, []model.LotAllocationEntity{{LotID: fresh.ID, ProductID: productID, Quantity: 3}}, details[0].Lots)
+}
+
+func TestTransactionService_CreateTransaction_AtOutlet(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockOutletRepo := new(mock.MockOutletRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, outletRepo: mockOutletRepo})
+
+	outletID, productID := uuid.New(), uuid.New()
+	override := int64(12000)
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, Code: "JKT", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletStock", outletID.String(), productID.String()).Return(model.OutletStockEntity{Stock: 3, PriceOverride: &override}, nil)
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 50}, nil)
+
+	var sold model.TransactionEntity
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: productID}, nil)
+
+	request := func(quantity float64) model.CreateTransactionRequest {
+		return model.CreateTransactionRequest{
+			OutletID: utils.EncodeBase62(outletID.String()),
+			Items:    []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: quantity}},
+		}
+	}
+
+	_, err := service.CreateTransaction(request(4))
+	assert.ErrorContains(t, err, "insufficient stock", "only the outlet's 3 units can be sold")
+
+	_, err = service.CreateTransaction(request(2))
+	assert.NoError(t, err)
+	assert.Equal(t, &outletID, sold.OutletID)
+	assert.Equal(t, int64(12000), details[0].PriceAmount)
+	assert.Equal(t, int64(24000), sold.TotalPriceAmount)
+}
+
+func TestTransactionService_CreateTransaction_AtNoOutletLeavesOutletStock(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockOutletRepo := new(mock.MockOutletRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, outletRepo: mockOutletRepo})
+
+	productID := uuid.New()
+	// 8 of the 10 units sit at outlets, only 2 are held at no outlet
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 10}, nil)
+	mockOutletRepo.On("FindStockHeldAtOutlets", productID.String()).Return(8, nil)
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Return(model.TransactionEntity{ID: productID}, nil)
+
+	request := func(quantity float64) model.CreateTransactionRequest {
+		return model.CreateTransactionRequest{
+			Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: quantity}},
+		}
+	}
+
+	_, err := service.CreateTransaction(request(3))
+	assert.ErrorContains(t, err, "insufficient stock", "the outlets' units are not for a sale at no outlet")
+
+	_, err = service.CreateTransaction(request(2))
+	assert.NoError(t, err)
+	mockTxRepo.AssertNumberOfCalls(t, "CreateTransaction", 1)
+}
+
+func TestTransactionService_CreateTransaction_InvalidOutlet(t *testing.T) {
+	mockOutletRepo := new(mock.MockOutletRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{outletRepo: mockOutletRepo})
+
+	inactiveID := uuid.New()
+	mockOutletRepo.On("FindOutletByID", inactiveID.String()).Return(model.OutletEntity{ID: inactiveID, Code: "BDG"}, nil)
+	item := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(uuid.New().String()), Quantity: 1}}
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{OutletID: "???", Items: item})
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{OutletID: utils.EncodeBase62(inactiveID.String()), Items: item})
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+}
+
+func TestTransactionService_CreateTransaction_ScheduledAndListPrices(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockCustomerRepo := new(mock.MockCustomerRepository)
+	mockLoyaltyRepo := new(mock.MockLoyaltyRepository)
+	mockPriceListRepo := new(mock.MockPriceListRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, customerRepo: mockCustomerRepo, loyaltyRepo: mockLoyaltyRepo, priceListRepo: mockPriceListRepo})
+
+	productID, customerID, memberID, closedID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 5}, nil)
+	mockCustomerRepo.On("FindCustomerByID", customerID.String()).Return(model.CustomerEntity{ID: customerID, Name: "Budi", PriceListID: &memberID}, nil)
+	mockLoyaltyRepo.On("FindLoyaltyProgram").Return(model.LoyaltyProgramEntity{}, nil)
+	mockPriceListRepo.On("FindPriceListByID", memberID.String()).Return(model.PriceListEntity{ID: memberID, Code: "MEMBER", IsActive: true}, nil)
+	mockPriceListRepo.On("FindPriceListByID", closedID.String()).Return(model.PriceListEntity{ID: closedID, Code: "PROMO"}, nil)
+	mockPriceListRepo.On("FindProductPrices", productID.String()).Return([]model.ProductPriceEntity{
+		{ID: uuid.New(), ProductID: productID, Price: 11000, StartsAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), ProductID: productID, Price: 12000, StartsAt: now.Add(24 * time.Hour)},
+		{ID: uuid.New(), ProductID: productID, PriceListID: &memberID, Price: 9000, StartsAt: now.Add(-time.Hour)},
+	}, nil)
+	mockPriceListRepo.On("FindProductPriceTiers", productID.String()).Return([]model.ProductPriceTierEntity{}, nil)
+
+	var sold model.TransactionEntity
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+	item := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 2}}
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: item})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11000), details[0].PriceAmount, "the started base price replaces the catalog price")
+	assert.Nil(t, sold.PriceListID)
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{CustomerID: utils.EncodeBase62(customerID.String()), Items: item})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9000), details[0].PriceAmount, "the customer's price list prices the sale")
+	assert.Equal(t, int64(18000), sold.TotalPriceAmount)
+	assert.Equal(t, &memberID, sold.PriceListID)
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{PriceListID: utils.EncodeBase62(closedID.String()), Items: item})
+	assert.ErrorIs(t, err, ErrInvalidPriceList, "an inactive price list cannot price a sale")
+}
+
+func TestTransactionService_CreateTransaction_PriceTiers(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockPriceListRepo := new(mock.MockPriceListRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, priceListRepo: mockPriceListRepo})
+
+	productID := uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Telur", Price: 3500, Stocks: 100}, nil)
+	mockPriceListRepo.On("FindProductPrices", productID.String()).Return([]model.ProductPriceEntity{}, nil)
+	mockPriceListRepo.On("FindProductPriceTiers", productID.String()).Return([]model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 1, Price: 3500},
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 12, Price: 3000},
+		{ID: uuid.New(), ProductID: productID, MinQuantity: 60, Price: 4000},
+	}, nil)
+
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+	sell := func(quantity float64) model.TransactionDetailEntity {
+		_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: quantity}}})
+		assert.NoError(t, err)
+		return details[0]
+	}
+
+	detail := sell(11)
+	assert.Equal(t, int64(3500), detail.PriceAmount)
+	assert.Equal(t, 1, *detail.PriceTierMinQuantity)
+
+	detail = sell(12)
+	assert.Equal(t, int64(3000), detail.PriceAmount, "crossing a tier lowers the unit price")
+	assert.Equal(t, int64(36000), detail.TotalPriceAmount)
+	assert.Equal(t, 12, *detail.PriceTierMinQuantity)
+
+	detail = sell(60)
+	assert.Equal(t, int64(3500), detail.PriceAmount, "a tier never raises the price")
+	assert.Nil(t, detail.PriceTierMinQuantity)
+}
+
+func TestTransactionService_CreateTransaction_PriceTiersScaled(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockPriceListRepo := new(mock.MockPriceListRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, priceListRepo: mockPriceListRepo})
+
+	// rice kept in grams, priced per kilo, 5 kg and more is cheaper
+	riceID := uuid.New()
+	mockProductRepo.On("FindProductByID", riceID.String()).Return(model.ProductEntity{ID: riceID, Name: "Beras", Price: 12500, Stocks: 50000, Unit: "kg", QuantityScale: 3}, nil)
+	mockPriceListRepo.On("FindProductPrices", riceID.String()).Return([]model.ProductPriceEntity{}, nil)
+	mockPriceListRepo.On("FindProductPriceTiers", riceID.String()).Return([]model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: riceID, MinQuantity: 5, Price: 12000},
+	}, nil)
+
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+	sell := func(kilos float64) model.TransactionDetailEntity {
+		_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(riceID.String()), Quantity: kilos}}})
+		assert.NoError(t, err)
+		return details[0]
+	}
+
+	detail := sell(4.999)
+	assert.Equal(t, int64(12500), detail.PriceAmount, "4999 grams are below the 5 kg tier")
+	assert.Nil(t, detail.PriceTierMinQuantity)
+
+	detail = sell(5.25)
+	assert.Equal(t, int64(12000), detail.PriceAmount, "the tier counts kilos, not grams")
+	assert.Equal(t, int64(63000), detail.TotalPriceAmount)
+	assert.Equal(t, 5, *detail.PriceTierMinQuantity)
+}
+
+func TestTransactionService_CreateTransaction_WithCustomer(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockCustomerRepo := new(mock.MockCustomerRepository)
+	mockLoyaltyRepo := new(mock.MockLoyaltyRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, customerRepo: mockCustomerRepo, loyaltyRepo: mockLoyaltyRepo})
+
+	customerID, unknownID, productID := uuid.New(), uuid.New(), uuid.New()
+	mockCustomerRepo.On("FindCustomerByID", customerID.String()).Return(model.CustomerEntity{ID: customerID, Name: "Budi"}, nil)
+	mockCustomerRepo.On("FindCustomerByID", unknownID.String()).Return(model.CustomerEntity{}, errors.New("customer not found"))
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 5}, nil)
+	mockLoyaltyRepo.On("FindLoyaltyProgram").Return(model.LoyaltyProgramEntity{}, nil)
+
+	var sold model.TransactionEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+	}).Return(model.TransactionEntity{ID: productID, CustomerID: &customerID}, nil)
+	item := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 1}}
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{CustomerID: utils.EncodeBase62(unknownID.String()), Items: item})
+	assert.ErrorIs(t, err, ErrInvalidCustomer)
+	mockTxRepo.AssertNotCalled(t, "CreateTransaction", testifyMock.Anything, testifyMock.Anything)
+
+	tx, err := service.CreateTransaction(model.CreateTransactionRequest{CustomerID: utils.EncodeBase62(customerID.String()), Items: item})
+	assert.NoError(t, err)
+	assert.Equal(t, &customerID, sold.CustomerID)
+	assert.Equal(t, utils.EncodeBase62(customerID.String()), tx.CustomerID)
+}
+
+func TestTransactionService_CreateTransaction_RedeemsAndEarnsPoints(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockCustomerRepo := new(mock.MockCustomerRepository)
+	mockLoyaltyRepo := new(mock.MockLoyaltyRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, customerRepo: mockCustomerRepo, loyaltyRepo: mockLoyaltyRepo})
+
+	customerID, productID := uuid.New(), uuid.New()
+	mockCustomerRepo.On("FindCustomerByID", customerID.String()).Return(model.CustomerEntity{ID: customerID, Name: "Budi", PointsBalance: 50}, nil)
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 5}, nil)
+	mockLoyaltyRepo.On("FindLoyaltyProgram").Return(model.LoyaltyProgramEntity{IsActive: true, SpendPerPoint: 1000, PointValue: 100}, nil)
+	mockLoyaltyRepo.On("FindLoyaltyEntries", customerID.String()).Return([]model.LoyaltyEntryEntity{
+		{ID: uuid.New(), CustomerID: customerID, Type: model.LoyaltyEntryEarn, Points: 50, BalanceAfter: 50},
+	}, nil)
+
+	var sold model.TransactionEntity
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{}, nil)
+	item := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 2}}
+	customer := utils.EncodeBase62(customerID.String())
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: item, RedeemPoints: 10})
+	assert.ErrorIs(t, err, ErrInvalidLoyalty, "points need a customer")
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{CustomerID: customer, Items: item, RedeemPoints: 51})
+	assert.ErrorIs(t, err, ErrInvalidLoyalty, "more than the balance")
+	mockTxRepo.AssertNotCalled(t, "CreateTransaction", testifyMock.Anything, testifyMock.Anything)
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{CustomerID: customer, Items: item, RedeemPoints: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2000), sold.PointsDiscountAmount)
+	assert.Equal(t, int64(18000), sold.TotalPriceAmount)
+	assert.Equal(t, 20, sold.PointsRedeemed)
+	assert.Equal(t, 18, sold.PointsEarned, "only the paid share of the sale earns points")
+	if assert.Len(t, details, 1) {
+		assert.Equal(t, int64(18000), details[0].TotalPriceAmount, "the line's revenue is net of the discount")
+		assert.Equal(t, int64(2000), details[0].PointsDiscountAmount)
+		assert.Equal(t, int64(10000), details[0].PriceAmount, "the unit price stays as charged")
+	}
+	if assert.Len(t, sold.LoyaltyEntries, 2) {
+		assert.Equal(t, model.LoyaltyEntryRedeem, sold.LoyaltyEntries[0].Type)
+		assert.Equal(t, -20, sold.LoyaltyEntries[0].Points)
+		assert.Equal(t, model.LoyaltyEntryEarn, sold.LoyaltyEntries[1].Type)
+		assert.Equal(t, 18, sold.LoyaltyEntries[1].Points)
+	}
+}
+
+func TestTransactionService_CreateTransaction_PaysWithGiftCard(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockCustomerRepo := new(mock.MockCustomerRepository)
+	mockGiftCardRepo := new(mock.MockGiftCardRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, customerRepo: mockCustomerRepo, giftCardRepo: mockGiftCardRepo})
+
+	productID, holderID := uuid.New(), uuid.New()
+	lapsed := time.Now().Add(-time.Hour)
+	card := model.GiftCardEntity{ID: uuid.New(), Code: "GIFT", Balance: 15000, IsActive: true}
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 5}, nil)
+	mockGiftCardRepo.On("FindGiftCardByCode", "GIFT").Return(card, nil)
+	mockGiftCardRepo.On("FindGiftCardByCode", "OLD").Return(model.GiftCardEntity{ID: uuid.New(), Code: "OLD", Balance: 15000, IsActive: true, ExpiresAt: &lapsed}, nil)
+	mockGiftCardRepo.On("FindGiftCardByCode", "CREDIT").Return(model.GiftCardEntity{ID: uuid.New(), Code: "CREDIT", Balance: 15000, IsActive: true, CustomerID: &holderID}, nil)
+	mockGiftCardRepo.On("FindGiftCardByCode", "NOPE").Return(model.GiftCardEntity{}, nil)
+
+	var sold model.TransactionEntity
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{}, nil)
+	item := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 2}}
+
+	for _, req := range []model.CreateTransactionRequest{
+		{Items: item, GiftCardAmount: 5000},
+		{Items: item, GiftCardCode: "gift", GiftCardAmount: -1},
+		{Items: item, GiftCardCode: "gift", GiftCardAmount: 16000},
+		{Items: item, GiftCardCode: "nope"},
+		{Items: item, GiftCardCode: "old"},
+		{Items: item, GiftCardCode: "credit"},
+	} {
+		_, err := service.CreateTransaction(req)
+		assert.ErrorIs(t, err, ErrInvalidGiftCard)
+	}
+	mockTxRepo.AssertNotCalled(t, "CreateTransaction", testifyMock.Anything, testifyMock.Anything)
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: item, GiftCardCode: "gift"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20000), sold.TotalPriceAmount, "a gift card is a tender, the sale keeps its value")
+	assert.Equal(t, int64(20000), details[0].TotalPriceAmount, "and so do its lines")
+	assert.Zero(t, details[0].PointsDiscountAmount)
+	assert.Equal(t, int64(15000), sold.GiftCardAmount, "all the card holds when no amount is given")
+	assert.Equal(t, &card.ID, sold.GiftCardID)
+	if assert.NotNil(t, sold.GiftCardEntry) {
+		assert.Equal(t, model.GiftCardEntryRedeem, sold.GiftCardEntry.Type)
+		assert.Equal(t, int64(-15000), sold.GiftCardEntry.Amount)
+		assert.Equal(t, &sold.ID, sold.GiftCardEntry.TransactionID)
+	}
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: item, GiftCardCode: "GIFT", GiftCardAmount: 5000})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5000), sold.GiftCardAmount)
+}
+
+func TestTransactionService_CreateTransaction_OnShift(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockOutletRepo := new(mock.MockOutletRepository)
+	mockShiftRepo := new(mock.MockShiftRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, outletRepo: mockOutletRepo, shiftRepo: mockShiftRepo})
+
+	productID, outletID, otherOutletID := uuid.New(), uuid.New(), uuid.New()
+	openID, closedID := uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Kopi", Price: 10000, Stocks: 5}, nil)
+	mockOutletRepo.On("FindOutletByID", outletID.String()).Return(model.OutletEntity{ID: outletID, Code: "JKT", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletByID", otherOutletID.String()).Return(model.OutletEntity{ID: otherOutletID, Code: "BDG", IsActive: true}, nil)
+	mockOutletRepo.On("FindOutletStock", outletID.String(), productID.String()).Return(model.OutletStockEntity{Stock: 5}, nil)
+	mockShiftRepo.On("FindShiftByID", openID.String()).Return(model.ShiftEntity{ID: openID, Status: model.ShiftOpen, OutletID: &outletID, OutletName: "Jakarta"}, nil)
+	mockShiftRepo.On("FindShiftByID", closedID.String()).Return(model.ShiftEntity{ID: closedID, Status: model.ShiftClosed}, nil)
+
+	var sold model.TransactionEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+	}).Return(model.TransactionEntity{}, nil)
+	item := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 1}}
+	open := utils.EncodeBase62(openID.String())
+
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: item, ShiftID: utils.EncodeBase62(closedID.String())})
+	assert.ErrorIs(t, err, ErrInvalidShift)
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: item, ShiftID: open, OutletID: utils.EncodeBase62(otherOutletID.String())})
+	assert.ErrorIs(t, err, ErrInvalidShift, "the drawer is at another outlet")
+	mockTxRepo.AssertNotCalled(t, "CreateTransaction", testifyMock.Anything, testifyMock.Anything)
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: item, ShiftID: open})
+	assert.NoError(t, err)
+	assert.Equal(t, &openID, sold.ShiftID)
+	assert.Equal(t, &outletID, sold.OutletID, "the sale is at the shift's outlet")
+}
+
+func TestTransactionService_FetchReport_ByOutlet(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo})
+
+	outletID := uuid.New()
+	mockTxRepo.On("GetReportStats", testifyMock.Anything, testifyMock.Anything, &outletID).Return(model.ReportResponse{TotalTransactions: 2}, nil)
+
+	resp, err := service.FetchReport("", "", "today", utils.EncodeBase62(outletID.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resp.TotalTransactions)
+
+	_, err = service.FetchReport("", "", "today", "???")
+	assert.ErrorIs(t, err, ErrInvalidOutlet)
+}
+
+func TestTransactionService_CreateTransaction_HonoursReservations(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockDraftRepo := new(mock.MockDraftOrderRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, draftRepo: mockDraftRepo})
+
+	productID, draftID := uuid.New(), uuid.New()
+	mockProductRepo.On("FindProductByID", productID.String()).Return(model.ProductEntity{ID: productID, Name: "Brownies", Price: 25000, Stocks: 5}, nil)
+	mockDraftRepo.On("FindReservedStocks", (*uuid.UUID)(nil), "").Return(map[uuid.UUID]int{productID: 4}, nil)
+	mockDraftRepo.On("FindReservedStocks", (*uuid.UUID)(nil), draftID.String()).Return(map[uuid.UUID]int{}, nil)
+	mockTxRepo.On("CreateTransaction", testifyMock.MatchedBy(func(tx model.TransactionEntity) bool {
+		return tx.DraftOrderID != nil && *tx.DraftOrderID == draftID
+	}), testifyMock.Anything).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+
+	items := []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(productID.String()), Quantity: 2}}
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: items})
+	assert.EqualError(t, err, "insufficient stock for product: Brownies", "4 of the 5 are held on a draft")
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: items, DraftOrderID: utils.EncodeBase62(draftID.String())})
+	assert.NoError(t, err, "the draft holding them converts")
+
+	_, err = service.CreateTransaction(model.CreateTransactionRequest{Items: items, DraftOrderID: "not-a-draft"})
+	assert.ErrorIs(t, err, ErrInvalidDraftOrder)
+	mockTxRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_CreateTransaction_OfflineBooksStockConflicts(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, draftRepo: new(mock.MockDraftOrderRepository)})
+
+	kopi, gula := uuid.New(), uuid.New()
+	paket, _ := uuid.NewV7()
+	mockProductRepo.On("FindProductByID", kopi.String()).Return(model.ProductEntity{ID: kopi, Name: "Kopi", Price: 10000, Stocks: 1}, nil)
+	mockProductRepo.On("FindProductByID", gula.String()).Return(model.ProductEntity{ID: gula, Name: "Gula", Stocks: 4}, nil)
+	mockProductRepo.On("FindProductByID", paket.String()).Return(model.ProductEntity{
+		ID: paket, Name: "Paket Kopi", Price: 15000, Type: model.ProductTypeBundle,
+		Components: []model.BundleComponentEntity{
+			{ComponentID: kopi, ComponentName: "Kopi", ComponentStocks: 1, Quantity: 1},
+			{ComponentID: gula, ComponentName: "Gula", ComponentStocks: 5, Quantity: 1},
+		},
+	}, nil)
+
+	var sold model.TransactionEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+	}).Return(model.TransactionEntity{ID: paket}, nil)
+
+	clientID, _ := uuid.NewV7()
+	occurredAt := time.Now().Add(-3 * time.Hour)
+	_, err := service.CreateTransaction(model.CreateTransactionRequest{
+		Offline:    true,
+		ID:         clientID,
+		OccurredAt: occurredAt,
+		Items: []model.CreateTransactionItemRequest{
+			{ProductID: utils.EncodeBase62(kopi.String()), Quantity: 2},
+			{ProductID: utils.EncodeBase62(paket.String()), Quantity: 1},
+		},
+	})
+
+	assert.NoError(t, err, "an offline sale is never refused for stock")
+	assert.Equal(t, clientID, sold.ID)
+	assert.Equal(t, occurredAt, sold.CreatedAt)
+	if assert.Len(t, sold.StockConflicts, 1, "only the coffee was oversold") {
+		assert.Equal(t, kopi, sold.StockConflicts[0].ProductID)
+		assert.Equal(t, 2, sold.StockConflicts[0].Quantity, "3 sold across the lines, 1 on the books")
+		assert.Equal(t, clientID, sold.StockConflicts[0].TransactionID)
+	}
+}
+
+func TestTransactionService_CreateTransaction_OfflineKeepsTerminalPrices(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	mockPriceListRepo := new(mock.MockPriceListRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo, priceListRepo: mockPriceListRepo})
+
+	// the price went up to 11000 after the terminal last pulled the catalog at 10000
+	kopiID := uuid.New()
+	mockProductRepo.On("FindProductByID", kopiID.String()).Return(model.ProductEntity{ID: kopiID, Name: "Kopi", Price: 11000, Stocks: 10}, nil)
+	mockPriceListRepo.On("FindProductPrices", kopiID.String()).Return([]model.ProductPriceEntity{}, nil)
+	mockPriceListRepo.On("FindProductPriceTiers", kopiID.String()).Return([]model.ProductPriceTierEntity{
+		{ID: uuid.New(), ProductID: kopiID, MinQuantity: 3, Price: 10500},
+	}, nil)
+
+	var sold model.TransactionEntity
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+	sell := func(offline bool, quantity float64, charged int64) error {
+		clientID, _ := uuid.NewV7()
+		_, err := service.CreateTransaction(model.CreateTransactionRequest{
+			Offline:    offline,
+			ID:         clientID,
+			OccurredAt: time.Now().Add(-time.Hour),
+			Items:      []model.CreateTransactionItemRequest{{ProductID: utils.EncodeBase62(kopiID.String()), Quantity: quantity, UnitPrice: &charged}},
+		})
+		return err
+	}
+
+	assert.NoError(t, sell(true, 2, 10000))
+	assert.Equal(t, int64(10000), details[0].PriceAmount, "the sale is booked at what the customer paid")
+	assert.Equal(t, int64(20000), sold.TotalPriceAmount)
+
+	assert.NoError(t, sell(true, 3, 10000))
+	assert.Nil(t, details[0].PriceTierMinQuantity, "the terminal did not charge the tier's price")
+	assert.NoError(t, sell(true, 3, 10500))
+	assert.Equal(t, 3, *details[0].PriceTierMinQuantity)
+
+	assert.ErrorIs(t, sell(true, 1, 100), ErrInvalidSync, "a price far below ours is not trusted")
+	assert.ErrorIs(t, sell(true, 1, 50000), ErrInvalidSync, "nor one far above")
+	assert.ErrorIs(t, sell(true, 1, -1), ErrInvalidSync)
+
+	assert.NoError(t, sell(false, 1, 100))
+	assert.Equal(t, int64(11000), details[0].PriceAmount, "a sale rung up online pays the server's price")
+}
+
+func TestTransactionService_CreateTransaction_DecimalQuantitiesAndUnits(t *testing.T) {
+	mockTxRepo := new(mock.MockTransactionRepository)
+	mockProductRepo := new(mock.MockProductRepository)
+	service := newTransactionServiceWithMocks(transactionServiceMocks{txRepo: mockTxRepo, productRepo: mockProductRepo})
+
+	riceID, noodleID := uuid.New(), uuid.New()
+	cartonPrice := int64(80000)
+	mockProductRepo.On("FindProductByID", riceID.String()).Return(model.ProductEntity{ID: riceID, Name: "Beras", SKU: "00123", Price: 12500, CostPrice: 10000, Stocks: 50000, Unit: "kg", QuantityScale: 3}, nil)
+	mockProductRepo.On("FindProductByID", noodleID.String()).Return(model.ProductEntity{ID: noodleID, Name: "Mie", Price: 3500, Stocks: 100, Unit: model.DefaultUnit}, nil)
+	mockProductRepo.On("FindProductBySKU", "00123").Return(model.ProductEntity{ID: riceID, Name: "Beras", SKU: "00123", Price: 12500, Stocks: 50000, Unit: "kg", QuantityScale: 3}, nil)
+	mockProductRepo.On("FindProductUnits", noodleID.String()).Return([]model.ProductUnitEntity{
+		{ID: uuid.New(), ProductID: noodleID, Name: "carton", Factor: 24, Price: &cartonPrice},
+		{ID: uuid.New(), ProductID: noodleID, Name: "pack", Factor: 5},
+	}, nil)
+
+	var sold model.TransactionEntity
+	var details []model.TransactionDetailEntity
+	mockTxRepo.On("CreateTransaction", testifyMock.Anything, testifyMock.Anything).Run(func(args testifyMock.Arguments) {
+		sold = args.Get(0).(model.TransactionEntity)
+		details = args.Get(1).([]model.TransactionDetailEntity)
+	}).Return(model.TransactionEntity{ID: uuid.New()}, nil)
+	sell := func(item model.CreateTransactionItemRequest) (model.TransactionDetailEntity, error) {
+		_, err := service.CreateTransaction(model.CreateTransactionRequest{Items: []model.CreateTransactionItemRequest{item}})
+		if err != nil {
+			return model.TransactionDetailEntity{}, err
+		}
+		return details[0], nil
+	}
+	rice, noodle := utils.EncodeBase62(riceID.String()), utils.EncodeBase62(noodleID.String())
+
+	detail, err := sell(model.CreateTransactionItemRequest{ProductID: rice, Quantity: 0.75})
+	assert.NoError(t, err)
+	assert.Equal(t, 750, detail.Quantity, "three quarters of a kilo in grams")
+	assert.Equal(t, 3, detail.QuantityScale)
+	assert.Equal(t, int64(12500), detail.PriceAmount)
+	assert.Equal(t, int64(9375), detail.TotalPriceAmount)
+	assert.Equal(t, int64(7500), detail.TotalCostAmount)
+	assert.Equal(t, 1, sold.TotalItems, "a part of a kilo counts as one item")
+
+	_, err = sell(model.CreateTransactionItemRequest{ProductID: rice, Quantity: 0.7505})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+	_, err = sell(model.CreateTransactionItemRequest{ProductID: noodle, Quantity: 1.5})
+	assert.ErrorIs(t, err, ErrInvalidQuantity, "a product sold whole cannot sell half")
+
+	detail, err = sell(model.CreateTransactionItemRequest{ProductID: noodle, Quantity: 2, Unit: "Carton"})
+	assert.NoError(t, err)
+	assert.Equal(t, 48, detail.Quantity, "two cartons take 48 pieces from stock")
+	assert.Equal(t, "carton", detail.Unit)
+	assert.Equal(t, int64(80000), detail.PriceAmount, "the carton's own price")
+	assert.Equal(t, int64(160000), detail.TotalPriceAmount)
+
+	detail, err = sell(model.CreateTransactionItemRequest{ProductID: noodle, Quantity: 1, Unit: "pack"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(17500), detail.PriceAmount, "a unit without a price costs its pieces")
+
+	_, err = sell(model.CreateTransactionItemRequest{ProductID: noodle, Quantity: 1, Unit: "box"})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	detail, err = sell(model.CreateTransactionItemRequest{Barcode: "2000123093758"})
+	assert.NoError(t, err)
+	assert.Equal(t, riceID, *detail.ProductID)
+	assert.Equal(t, 750, detail.Quantity, "the label's price buys 750 grams")
+	assert.Equal(t, int64(9375), detail.TotalPriceAmount, "the label's price is charged as printed")
+
+	_, err = sell(model.CreateTransactionItemRequest{Barcode: "2000123093759"})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir, "/media/")
+
+	require.NoError(t, store.Put("products/abc/1.jpg", "image/jpeg", []byte("jpeg")))
+	data, err := os.ReadFile(filepath.Join(dir, "products", "abc", "1.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", string(data))
+	assert.Equal(t, "/media/products/abc/1.jpg", store.URL("products/abc/1.jpg"))
+
+	require.NoError(t, store.Put("products/abc/1.jpg", "image/jpeg", []byte("replaced")))
+	entries, err := os.ReadDir(filepath.Join(dir, "products", "abc"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+
+	require.NoError(t, store.Delete("products/abc/1.jpg"))
+	require.NoError(t, store.Delete("products/abc/1.jpg"), "deleting twice is not an error")
+	_, err = os.Stat(filepath.Join(dir, "products", "abc", "1.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	for _, key := range []string{"", "/etc/passwd", "../outside.jpg", "products/../../outside.jpg", "products//1.jpg"} {
+		assert.ErrorIs(t, store.Put(key, "image/jpeg", []byte("x")), ErrInvalidKey, key)
+		assert.ErrorIs(t, store.Delete(key), ErrInvalidKey, key)
+	}
+}
+
+func TestLocalStore_Handler(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "/media")
+	require.NoError(t, store.Put("products/abc/1.jpg", "image/jpeg", []byte("jpeg")))
+	handler := http.StripPrefix("/media", store.Handler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/media/products/abc/1.jpg", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "jpeg", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/media/products/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "directories are not listed")
+}
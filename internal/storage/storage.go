@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrInvalidKey means the key is empty or would escape the store, such as one with ".." in it
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore keeps uploaded files such as product images under a slash separated key
+// and tells the public URL each one is served from
+type BlobStore interface {
+	Put(key, contentType string, data []byte) error
+	// Delete removes the file, a key that is already gone is not an error
+	Delete(key string) error
+	URL(key string) string
+}
+
+func validKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, "/") && path.Clean(key) == key && key != ".." && !strings.HasPrefix(key, "../")
+}
+
+// joinURL puts the key under the base URL the store's files are served from
+func joinURL(baseURL, key string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + key
+}
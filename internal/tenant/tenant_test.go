@@ -0,0 +1,22 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithID(t *testing.T) {
+	_, ok := IDFromContext(context.Background())
+	assert.False(t, ok)
+
+	id := uuid.New()
+	got, ok := IDFromContext(WithID(context.Background(), id))
+	assert.True(t, ok)
+	assert.Equal(t, id, got)
+
+	_, ok = IDFromContext(WithID(context.Background(), uuid.Nil))
+	assert.False(t, ok, "the nil uuid names no tenant")
+}
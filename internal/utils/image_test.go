@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDecodeImage(t *testing.T) {
+	data := encodePNG(t, image.NewRGBA(image.Rect(0, 0, 40, 30)))
+
+	img, format, err := DecodeImage(data, 1200)
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, 40, img.Bounds().Dx())
+
+	_, _, err = DecodeImage(data, 1199)
+	assert.Error(t, err, "over the pixel limit")
+	_, _, err = DecodeImage([]byte("not an image"), 1200)
+	assert.Error(t, err)
+}
+
+func TestThumbnail(t *testing.T) {
+	// left half red, right half transparent
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for y := range 200 {
+		for x := range 200 {
+			src.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	thumb := Thumbnail(src, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, thumb.RGBAAt(10, 10))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, thumb.RGBAAt(90, 10), "transparency is flattened on white")
+
+	small := Thumbnail(image.NewRGBA(image.Rect(0, 0, 20, 80)), 100)
+	assert.Equal(t, image.Rect(0, 0, 20, 80), small.Bounds(), "a small image is not scaled up")
+	assert.Equal(t, image.Rect(0, 0, 25, 100), Thumbnail(image.NewRGBA(image.Rect(0, 0, 50, 200)), 100).Bounds())
+
+	_, err := EncodeJPEG(thumb, 85)
+	assert.NoError(t, err)
+}
+
+// exifJPEG is the start of a JPEG carrying only an EXIF segment with the orientation tag
+func exifJPEG(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xff, 0xd8, 0xff, 0xe1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+	return append(append(data, segment...), 0xff, 0xda, 0, 2)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	assert.Equal(t, 6, JPEGOrientation(exifJPEG(binary.BigEndian, 6)))
+	assert.Equal(t, 8, JPEGOrientation(exifJPEG(binary.LittleEndian, 8)))
+	assert.Equal(t, 1, JPEGOrientation(exifJPEG(binary.BigEndian, 42)), "an invalid orientation is ignored")
+	assert.Equal(t, 1, JPEGOrientation([]byte{0xff, 0xd8, 0xff, 0xda, 0, 2}))
+	assert.Equal(t, 1, JPEGOrientation([]byte("\x89PNG")))
+}
+
+func TestOrient(t *testing.T) {
+	// a 3x2 image with a red top-left pixel
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.SetRGBA(0, 0, color.RGBA{R: 255, A: 255})
+
+	assert.Same(t, img, Orient(img, 1))
+	cases := map[int]image.Point{2: {2, 0}, 3: {2, 1}, 4: {0, 1}, 5: {0, 0}, 6: {1, 0}, 7: {1, 2}, 8: {0, 2}}
+	for orientation, red := range cases {
+		upright := Orient(img, orientation)
+		if orientation >= 5 {
+			assert.Equal(t, image.Rect(0, 0, 2, 3), upright.Bounds(), orientation)
+		} else {
+			assert.Equal(t, image.Rect(0, 0, 3, 2), upright.Bounds(), orientation)
+		}
+		assert.Equal(t, uint8(255), upright.RGBAAt(red.X, red.Y).R, orientation)
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXLSXWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow("name", "sku", "price"))
+	require.NoError(t, w.WriteRow("Kopi <Susu> & Gula", "00123", int64(18000)))
+	require.NoError(t, w.Close())
+
+	rows, err := ReadXLSX(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"name", "sku", "price"},
+		{"Kopi <Susu> & Gula", "00123", "18000"},
+	}, rows, "text keeps its leading zeros and escaping")
+}
+
+// a sheet the way spreadsheet apps save it: shared strings, rich text, sparse cells and skipped rows
+func TestReadXLSX_SharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Produk" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId3" Target="worksheets/produk.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><si><t>name</t></si><si><r><t>Teh </t></r><r><t>Manis</t></r></si></sst>`,
+		"xl/worksheets/produk.xml":   `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="s"><v>0</v></c></row><row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>5000</v></c><c r="D3" t="b"><v>1</v></c></row></sheetData></worksheet>`,
+	} {
+		f, err := archive.Create(name)
+		require.NoError(t, err)
+		_, _ = f.Write([]byte(content))
+	}
+	require.NoError(t, archive.Close())
+
+	rows, err := ReadXLSX(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name"}, nil, {"Teh Manis", "", "5000", "true"}}, rows)
+
+	_, err = ReadXLSX([]byte("name,price"))
+	assert.Error(t, err, "a CSV is not a workbook")
+}